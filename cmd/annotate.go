@@ -26,6 +26,7 @@ import (
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/utils"
 )
 
 var (
@@ -158,7 +159,7 @@ func readInput(arg string) ([]io.Reader, error) {
 }
 
 func readInputsFromURL(url string) ([]io.Reader, error) {
-	resp, err := http.Get(url) // #nosec
+	resp, err := utils.HTTPClient().Get(url) // #nosec
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var (
+	bindingOperation     string
+	bindingMetadata      map[string]string
+	bindingData          string
+	bindingDataFile      string
+	bindingResourcesPath string
+	bindingRaw           bool
+)
+
+var BindingsCmd = &cobra.Command{
+	Use:   "bindings",
+	Short: "Send requests to the binding components of an app. Supported platforms: Self-hosted",
+}
+
+var BindingsTriggerCmd = &cobra.Command{
+	Use:     "trigger <app-id> <binding>",
+	Aliases: []string{"invoke"},
+	Short:   "Send a binding event to a binding component, through the sidecar of an app",
+	Example: `
+# Send an event with a JSON payload to the checkout binding of myapp
+dapr bindings trigger myapp checkout --data '{"orderId":1}'
+
+# Send an event with metadata and the payload of a file
+dapr bindings trigger myapp checkout --operation create --metadata key=order-1 --data-file ./order.json
+
+# Send an event with the payload read from stdin
+cat order.json | dapr bindings trigger myapp checkout --data -
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appID, name := args[0], args[1]
+		payload, err := readPayload("bindings trigger", bindingData, bindingDataFile)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		// The binding is looked up in the resources of the app, so that a typo is reported before sending the event.
+		dirs := []string{bindingResourcesPath}
+		if bindingResourcesPath == "" {
+			dirs, err = appResourcesPaths(appID)
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		}
+		if _, err = standalone.FindBindingComponent(dirs, name); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		result, err := standalone.NewClient().InvokeBinding(appID, name, standalone.BindingRequest{
+			Operation: bindingOperation,
+			Metadata:  bindingMetadata,
+			Data:      payload,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error sending the event to binding %s: %s", name, err))
+			os.Exit(1)
+		}
+		if response := formatResponse(result.Body, bindingRaw); response != "" {
+			fmt.Println(response)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Binding event sent successfully, status %s in %s", result.Status, result.Latency.Round(time.Millisecond))
+	},
+}
+
+// appResourcesPaths returns the resources directories of the running instance of appID, or the resolved components
+// directory if the instance doesn't report them.
+func appResourcesPaths(appID string) ([]string, error) {
+	apps, err := standalone.ListInstances(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	if instance, ok := findInstance(apps, appID); ok && len(instance.ResourcesPaths) > 0 {
+		return instance.ResourcesPaths, nil
+	}
+	dir, err := standalone.ResolveComponentsPath("", daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	return []string{dir.Path}, nil
+}
+
+func init() {
+	BindingsTriggerCmd.Flags().StringVar(&bindingOperation, "operation", standalone.DefaultBindingOperation, "The operation of the binding event")
+	BindingsTriggerCmd.Flags().StringToStringVarP(&bindingMetadata, "metadata", "m", nil, "Metadata items of the binding event, as key=value pairs")
+	BindingsTriggerCmd.Flags().StringVarP(&bindingData, "data", "d", "", "The data of the binding event, or - to read it from stdin (optional)")
+	BindingsTriggerCmd.Flags().StringVarP(&bindingDataFile, "data-file", "f", "", "A file containing the data of the binding event, or - to read it from stdin (optional)")
+	BindingsTriggerCmd.Flags().StringVar(&bindingResourcesPath, "resources-path", "", "The resources directory to look the binding up in. Defaults to the resources of the app")
+	BindingsTriggerCmd.Flags().BoolVar(&bindingRaw, "raw", false, "Print the response as is, without indenting JSON")
+	BindingsTriggerCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	BindingsCmd.AddCommand(BindingsTriggerCmd)
+
+	BindingsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(BindingsCmd)
+}
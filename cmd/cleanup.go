@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	cleanupKillOrphans  bool
+	cleanupOutputFormat string
+)
+
+var CleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove the run records of the instances which exited, and stop the orphaned Dapr sidecars. Supported platforms: Self-hosted",
+	Long: `Remove the run records of the instances whose processes are gone, left behind by the run commands of closed terminals.
+The daprd processes running the runtime binary of the install dir, which no run record tracks and whose run command
+is gone, are orphans holding their ports: cleanup asks whether to stop each of them, or stops them with --kill-orphans.
+The other processes are never touched. The list command also removes the stale run records.`,
+	Example: `
+# Remove the stale run records, and choose which orphaned sidecars to stop
+dapr cleanup
+
+# Remove the stale run records and stop all the orphaned sidecars, without asking
+dapr cleanup --kill-orphans
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(cleanupOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		report, err := standalone.Cleanup(standalone.CleanupOptions{
+			DaprRuntimePath: daprRuntimePath,
+			KillOrphans:     cleanupKillOrphans,
+			Confirm: func(orphan standalone.OrphanProcess) (bool, error) {
+				return print.Confirm(fmt.Sprintf("Stop the orphaned daprd process %d of app %q, holding the ports %s?", orphan.PID, orphan.AppID, joinPorts(orphan.Ports)), false)
+			},
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Error cleaning up: %s", err)
+			os.Exit(1)
+		}
+		failed := false
+		for _, orphan := range report.Orphans {
+			if orphan.Err != nil {
+				print.FailureStatusEvent(os.Stderr, "Failed to stop the orphaned daprd process %d of app %q: %s", orphan.PID, orphan.AppID, orphan.Err)
+				failed = true
+			}
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		} else {
+			for _, r := range report.RemovedRecords {
+				print.InfoStatusEvent(os.Stdout, "Removed the run record of app %q, whose processes are gone", r.AppID)
+			}
+			for _, orphan := range report.Orphans {
+				switch {
+				case orphan.Err != nil:
+				case orphan.Result == standalone.OrphanLeftRunning:
+					print.WarningStatusEvent(os.Stdout, "Left the orphaned daprd process %d of app %q running, stop it with dapr cleanup --kill-orphans", orphan.PID, orphan.AppID)
+				default:
+					print.InfoStatusEvent(os.Stdout, "Stopped the orphaned daprd process %d of app %q: %s", orphan.PID, orphan.AppID, orphan.Result)
+				}
+			}
+			switch {
+			case len(report.FreedPorts) > 0:
+				print.SuccessStatusEvent(os.Stdout, "Freed the ports %s", joinPorts(report.FreedPorts))
+			case len(report.RemovedRecords) == 0 && len(report.Orphans) == 0:
+				print.SuccessStatusEvent(os.Stdout, "Nothing to clean up")
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func joinPorts(ports []int) string {
+	s := make([]string, 0, len(ports))
+	for _, p := range ports {
+		s = append(s, strconv.Itoa(p))
+	}
+	return strings.Join(s, ", ")
+}
+
+func init() {
+	CleanupCmd.Flags().BoolVar(&cleanupKillOrphans, "kill-orphans", false, "Stop the orphaned Dapr sidecars without asking")
+	CleanupCmd.Flags().StringVarP(&cleanupOutputFormat, "output", "o", "", "The output format. Valid values are: json")
+	CleanupCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(CleanupCmd)
+}
@@ -14,12 +14,15 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
 
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -27,6 +30,11 @@ import (
 var (
 	componentsName         string
 	componentsOutputFormat string
+
+	newComponentOpts standalone.NewComponentOptions
+
+	listComponentsPath         string
+	listComponentsOutputFormat string
 )
 
 var ComponentsCmd = &cobra.Command{
@@ -65,7 +73,142 @@ dapr components -k --all-namespaces
 `,
 }
 
+var ComponentsNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Generate a component file from a template. Supported platforms: Self-hosted",
+	Example: `
+# Generate a Redis state store named statestore in the default components directory
+dapr components new --type state.redis --name statestore
+
+# Generate a Redis pub/sub reading its password from a secret, in the ./components directory
+dapr components new --type pubsub.redis --name pubsub --host redis:6379 --password-secret redis:password --secret-store localsecretstore --target-dir ./components
+
+# Generate a local file secret store
+dapr components new --type secretstores.local.file --name localsecretstore --secrets-file ./secrets.json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targetDir, err := standalone.ResolveComponentsPath(newComponentOpts.TargetDir, daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		newComponentOpts.TargetDir = targetDir.Path
+		filePath, err := standalone.NewComponent(newComponentOpts)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, fmt.Sprintf("Component %s written to %s", newComponentOpts.Name, filePath))
+	},
+}
+
+var ComponentsValidateCmd = &cobra.Command{
+	Use:   "validate [dir...]",
+	Short: "Validate the component files of resources directories. Supported platforms: Self-hosted",
+	Example: `
+# Validate the components of the default components directory
+dapr components validate
+
+# Validate the components of the ./components and ./resources directories
+dapr components validate ./components ./resources
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dirs := args
+		if len(dirs) == 0 {
+			componentsPath, err := standalone.ResolveComponentsPath("", daprRuntimePath)
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			print.InfoStatusEvent(os.Stdout, "Using components directory %s", componentsPath)
+			dirs = []string{componentsPath.Path}
+		}
+		problems, err := standalone.ValidateComponents(dirs...)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if len(problems) > 0 {
+			for _, p := range problems {
+				print.FailureStatusEvent(os.Stderr, p.String())
+			}
+			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Found %d problem(s) in %s", len(problems), strings.Join(dirs, ", ")))
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, fmt.Sprintf("No problems found in %s", strings.Join(dirs, ", ")))
+	},
+}
+
+var ComponentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the components of a components directory. Supported platforms: Self-hosted",
+	Example: `
+# List the components of the default components directory
+dapr components list
+
+# List the components of the ./components directory in JSON format
+dapr components list --path ./components -o json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(listComponentsOutputFormat, print.OutputJSON, print.OutputWide); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		dir, err := standalone.ResolveComponentsPath(listComponentsPath, daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		print.InfoStatusEvent(os.Stdout, "Using components directory %s", dir)
+		components, err := standalone.ListComponents(dir.Path)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		table := print.NewTable(
+			print.TableColumn{Name: "Name", Key: "name"},
+			print.TableColumn{Name: "Type", Key: "type"},
+			print.TableColumn{Name: "File", Key: "file", Truncate: true},
+			print.TableColumn{Name: "Status", Key: "status", Truncate: true},
+		)
+		for _, c := range components {
+			status := "ok"
+			if c.Error != "" {
+				status = "error: " + c.Error
+			}
+			table.AddRow(c.Name, c.Type, c.File, status)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
 func init() {
+	ComponentsListCmd.Flags().StringVarP(&listComponentsPath, "path", "", "", "The components directory to list. Defaults to the resolved components directory")
+	ComponentsListCmd.Flags().StringVarP(&listComponentsOutputFormat, "output", "o", "", "The output format of the list. Valid values are: json, or wide to not truncate the columns")
+	ComponentsListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ComponentsCmd.AddCommand(ComponentsListCmd)
+
+	ComponentsValidateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ComponentsCmd.AddCommand(ComponentsValidateCmd)
+
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.Type, "type", "", "", "The component type. Supported values are: "+strings.Join(standalone.ComponentTypes(), ", "))
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.Name, "name", "", "", "The name of the component, the file is named after it")
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.TargetDir, "target-dir", "", "", "The directory to write the component file to. Defaults to the resolved components directory")
+	ComponentsNewCmd.Flags().BoolVarP(&newComponentOpts.Force, "force", "", false, "Overwrite the component file if it exists")
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.Host, "host", "", "", "The address of the service backing the component, e.g. the Redis host")
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.PasswordSecret, "password-secret", "", "", "The secret holding the password, as name or name:key")
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.SecretStore, "secret-store", "", "", "The secret store to read the referenced secrets from")
+	ComponentsNewCmd.Flags().StringVarP(&newComponentOpts.SecretsFile, "secrets-file", "", "", "The secrets file of a local file secret store")
+	ComponentsNewCmd.Flags().StringToStringVarP(&newComponentOpts.Metadata, "set", "", nil, "Metadata items to set, as name=value pairs")
+	ComponentsNewCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ComponentsNewCmd.MarkFlagRequired("type")
+	ComponentsNewCmd.MarkFlagRequired("name")
+	ComponentsCmd.AddCommand(ComponentsNewCmd)
+
 	ComponentsCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "If true, list all Dapr components in all namespaces")
 	ComponentsCmd.Flags().StringVarP(&componentsName, "name", "n", "", "The components name to be printed (optional)")
 	ComponentsCmd.Flags().StringVarP(&resourceNamespace, "namespace", "", "", "List all namespace components in a Kubernetes cluster")
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var (
+	configViewOutputFormat string
+	configScope            string
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the settings of the CLI persisted in the CLI config file. Supported platforms: Self-hosted",
+	Long: `Manage the settings of the CLI persisted in the CLI config file.
+
+The settings are read from config.json in the dapr install dir, then from dapr/config.json in the user config dir,
+$XDG_CONFIG_HOME or $HOME/.config on Linux, which is the only one runtimePath is read from. A command line flag
+takes precedence over the environment variable of a setting, which takes precedence over the config files, which
+take precedence over the default value.
+
+The settings set with config set are written to the CLI config file of the user config dir, or to the one of the
+dapr install dir with --scope install.
+`,
+}
+
+var ConfigViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the effective settings of the CLI, along with where each value comes from",
+	Example: `
+# Print the effective settings
+dapr config view
+
+# Print the effective settings in JSON format
+dapr config view -o json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(configViewOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		settings, err := standalone.CLISettings(flagLookup(cmd), daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		table := print.NewTable(
+			print.TableColumn{Name: "Setting", Key: "setting"},
+			print.TableColumn{Name: "Value", Key: "value"},
+			print.TableColumn{Name: "Variable", Key: "variable"},
+			print.TableColumn{Name: "Source", Key: "source"},
+		)
+		for _, s := range settings {
+			table.AddRow(s.Name, s.DisplayValue(), s.EnvVar, s.Source)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var ConfigGetCmd = &cobra.Command{
+	Use:   "get <setting>",
+	Short: "Print the effective value of a setting of the CLI",
+	Example: `
+# Print the container runtime the commands use
+dapr config get containerRuntime
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setting, err := standalone.ResolveSetting(args[0], nil, daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(setting.Value)
+	},
+}
+
+var ConfigSetCmd = &cobra.Command{
+	Use:   "set <setting> <value>",
+	Short: "Persist a setting of the CLI in a CLI config file",
+	Example: `
+# Use podman for all the install dirs
+dapr config set containerRuntime podman
+
+# Use another Redis port for the install dir of --runtime-path only
+dapr config set redisPort 6380 --scope install --runtime-path /opt/dapr
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := standalone.SetCLISetting(args[0], args[1], configScope, daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Set %s in %s", args[0], path)
+	},
+}
+
+var ConfigUnsetCmd = &cobra.Command{
+	Use:   "unset <setting>",
+	Short: "Remove a setting of the CLI from a CLI config file",
+	Example: `
+# Go back to the default container runtime
+dapr config unset containerRuntime
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, removed, err := standalone.UnsetCLISetting(args[0], configScope, daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if !removed {
+			print.InfoStatusEvent(os.Stdout, "%s is not set in %s", args[0], path)
+			return
+		}
+		print.SuccessStatusEvent(os.Stdout, "Removed %s from %s", args[0], path)
+	},
+}
+
+// flagLookup returns the values of the command line flags of cmd which are set.
+func flagLookup(cmd *cobra.Command) standalone.FlagLookup {
+	return func(name string) (string, bool) {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || !f.Changed {
+			return "", false
+		}
+		return f.Value.String(), true
+	}
+}
+
+// cliSetting returns the setting name, resolved from the command line flag of cmd, the environment, the CLI config
+// files or the default value, in that order.
+func cliSetting(cmd *cobra.Command, name string) standalone.Setting {
+	setting, err := standalone.ResolveSetting(name, flagLookup(cmd), daprRuntimePath)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	return setting
+}
+
+// resolveSetting returns the value of the setting name, as resolved by cliSetting.
+func resolveSetting(cmd *cobra.Command, name string) string {
+	return cliSetting(cmd, name).Value
+}
+
+func init() {
+	ConfigViewCmd.Flags().StringVarP(&configViewOutputFormat, "output", "o", "", "The output format. Valid values are: json")
+	ConfigViewCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigGetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	for _, c := range []*cobra.Command{ConfigSetCmd, ConfigUnsetCmd} {
+		c.Flags().StringVar(&configScope, "scope", standalone.ConfigScopeUser, "The CLI config file to write to. Valid values are: user, for the one of the user config dir, or install, for the one of the dapr install dir")
+		c.Flags().BoolP("help", "h", false, "Print this help message")
+	}
+	ConfigCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigCmd.AddCommand(ConfigViewCmd, ConfigGetCmd, ConfigSetCmd, ConfigUnsetCmd)
+	RootCmd.AddCommand(ConfigCmd)
+}
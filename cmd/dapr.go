@@ -16,14 +16,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
 	"github.com/dapr/cli/pkg/api"
+	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/pkg/standalone"
+	cli_ver "github.com/dapr/cli/pkg/version"
 )
 
 var RootCmd = &cobra.Command{
@@ -48,9 +52,12 @@ Distributed Application Runtime`,
 	},
 }
 
+// daprVersion is the output of the version command. The first two fields are kept for the scripts which parse them.
 type daprVersion struct {
-	CliVersion     string `json:"Cli version"`
-	RuntimeVersion string `json:"Runtime version"`
+	CliVersion     string                 `json:"Cli version"`
+	RuntimeVersion string                 `json:"Runtime version"`
+	CLI            cli_ver.Build          `json:"cli"`
+	Runtime        standalone.RuntimeInfo `json:"runtime"`
 }
 
 type osType string
@@ -62,15 +69,24 @@ const (
 var (
 	cliVersion      string
 	versionFlag     bool
-	daprVer         daprVersion
 	logAsJSON       bool
+	quiet           bool
+	noColor         bool
+	assumeYes       bool
+	statusToStdout  bool
+	cliLogLevel     string
 	daprRuntimePath string
+	profile         string
+
+	// updateCheck is started by initConfig, its notice is printed once the command completed.
+	updateCheck *standalone.UpdateCheck
 )
 
 // Execute adds all child commands to the root command.
 func Execute(version, apiVersion string) {
 	// Need to be set here as it is accessed in initConfig.
 	cliVersion = version
+	cli_ver.SetCLIVersion(version)
 	api.RuntimeAPIVersion = apiVersion
 
 	cobra.OnInitialize(initConfig)
@@ -79,10 +95,44 @@ func Execute(version, apiVersion string) {
 		fmt.Println(err)
 		os.Exit(-1)
 	}
+	// A command never waits for the check, the notice is the one of its result if it completed, or of the previous one.
+	if notice := updateCheck.Notice(0); notice != "" {
+		print.InfoStatusEvent(os.Stderr, notice)
+	}
+}
+
+// getDaprVersion returns the versions of the CLI and of the installed runtime.
+func getDaprVersion() daprVersion {
+	runtimeInfo := standalone.GetRuntimeInfo(daprRuntimePath)
+	runtimeVersion := runtimeInfo.Version
+	switch runtimeInfo.Status {
+	case standalone.RuntimeNotInstalled:
+		runtimeVersion = "n/a (not installed)"
+	case standalone.RuntimeNotExecutable:
+		runtimeVersion = fmt.Sprintf("n/a (%s %s: %s)", runtimeInfo.Binary, runtimeInfo.Status, runtimeInfo.Error)
+	}
+	return daprVersion{
+		// Set in Execute() method in this file before the commands run.
+		CliVersion:     cliVersion,
+		RuntimeVersion: runtimeVersion,
+		CLI:            cli_ver.CLI,
+		Runtime:        runtimeInfo,
+	}
 }
 
 func printVersion() {
-	fmt.Printf(cliVersionTemplateString, daprVer.CliVersion, daprVer.RuntimeVersion)
+	v := getDaprVersion()
+	fmt.Printf(cliVersionTemplateString, v.CliVersion, v.RuntimeVersion)
+	for _, line := range [][2]string{
+		{"CLI git commit", v.CLI.GitCommit},
+		{"CLI build date", v.CLI.BuildDate},
+		{"Runtime binary", v.Runtime.Binary},
+		{"Placement image", v.Runtime.PlacementImage},
+	} {
+		if line[1] != "" {
+			fmt.Printf("%s: %s\n", line[0], line[1])
+		}
+	}
 }
 
 // Function is called as a preRun initializer for each command executed.
@@ -90,22 +140,62 @@ func initConfig() {
 	if logAsJSON {
 		print.EnableJSONFormat()
 	}
-	// err intentionally ignored since daprd may not yet be installed.
-	runtimeVer, _ := standalone.GetRuntimeVersion(daprRuntimePath)
-
-	daprVer = daprVersion{
-		// Set in Execute() method in this file before initConfig() is called by cmd.Execute().
-		CliVersion:     cliVersion,
-		RuntimeVersion: strings.ReplaceAll(runtimeVer, "\n", ""),
+	level, err := print.ParseLogLevel(cliLogLevel)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
 	}
-
+	print.SetLogLevel(level)
+	if quiet {
+		print.EnableQuietMode()
+	}
+	if noColor {
+		print.DisableColor()
+	}
+	print.SetAssumeYes(assumeYes)
+	print.SetStatusToStdout(statusToStdout)
 	viper.SetEnvPrefix("dapr")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv()
+
+	if RootCmd.PersistentFlags().Changed("profile") {
+		if err := standalone.SetProfile(profile); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	// A mistake in the CLI config files fails every command, with where it is, rather than being ignored.
+	if err := standalone.LoadCLIConfig(daprRuntimePath); err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	kubernetes.SetHelmRepo(kubernetes.HelmRepo{
+		URL:      resolveSetting(RootCmd, standalone.SettingHelmRepoURL),
+		Username: resolveSetting(RootCmd, standalone.SettingHelmRepoUsername),
+		Password: resolveSetting(RootCmd, standalone.SettingHelmRepoPassword),
+	})
+
+	if runtime.GOOS == string(windowsOsType) {
+		// An upgrade of the CLI leaves the previous executable behind on Windows, as it can't be removed while it runs.
+		standalone.RemoveStaleCLI()
+	}
+
+	// The update check is only for people at a terminal, a notice would get in the way of scripts.
+	if term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd())) && standalone.UpdateCheckEnabled(daprRuntimePath) {
+		updateCheck = standalone.StartUpdateCheck(daprRuntimePath, cliVersion)
+	}
 }
 
 func init() {
 	RootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "version for dapr")
-	RootCmd.PersistentFlags().StringVarP(&daprRuntimePath, "runtime-path", "", "", "The path to the dapr runtime installation directory")
+	RootCmd.PersistentFlags().StringVarP(&daprRuntimePath, "runtime-path", "", "", "The path to the dapr runtime installation directory, the .dapr directory of which is used. Without it, the DAPR_HOME directory is used if the variable is set")
+	RootCmd.PersistentFlags().StringVar(&profile, "profile", "", "The profile of the self-hosted environment to use, each with its own install dir and containers. Defaults to DAPR_PROFILE, or to the default profile")
 	RootCmd.PersistentFlags().BoolVarP(&logAsJSON, "log-as-json", "", false, "Log output in JSON format")
+	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors and the results of the command")
+	RootCmd.PersistentFlags().BoolVarP(&noColor, "no-color", "", false, "Disable colors and glyphs in the output. Setting the NO_COLOR environment variable has the same effect")
+	RootCmd.PersistentFlags().BoolVar(&statusToStdout, "status-to-stdout", false, "Write status output to stdout instead of stderr. Status output always goes to stderr with --output json or jsonl")
+	RootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to all prompts, including confirmations of destructive operations")
+	RootCmd.PersistentFlags().BoolVar(&assumeYes, "assume-yes", false, "Alias of --yes")
+	// dapr run and dapr annotate define their own --log-level flag for the runtime, which takes precedence there.
+	RootCmd.PersistentFlags().StringVarP(&cliLogLevel, "log-level", "", "info", "The CLI log verbosity. Valid values are: debug, info, warn, or error")
 }
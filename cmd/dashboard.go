@@ -14,10 +14,13 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
@@ -53,6 +56,7 @@ var (
 	dashboardHost       string
 	dashboardLocalPort  int
 	dashboardVersionCmd bool
+	dashboardNoBrowser  bool
 )
 
 var DashboardCmd = &cobra.Command{
@@ -68,6 +72,9 @@ dapr dashboard -p 9999
 # Start dashboard locally on a random port which is free.
 dapr dashboard -p 0
 
+# Start dashboard locally without opening it in the browser
+dapr dashboard --no-browser
+
 # Port forward to dashboard in Kubernetes
 dapr dashboard -k
 
@@ -185,23 +192,29 @@ dapr dashboard -k -p 0
 			print.InfoStatusEvent(os.Stdout, fmt.Sprintf("Dapr dashboard found in namespace:\t%s", foundNamespace))
 			print.InfoStatusEvent(os.Stdout, fmt.Sprintf("Dapr dashboard available at:\t%s\n", webURL))
 
-			err = browser.OpenURL(webURL)
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, "Failed to start Dapr dashboard in browser automatically")
-				print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Visit %s in your browser to view the dashboard", webURL))
-			}
+			openDashboardURL(webURL)
 
 			<-portForward.GetStop()
 		} else {
-			// Standalone mode.
-			dashboardCmd, err := standalone.NewDashboardCmd(daprRuntimePath, dashboardLocalPort)
+			// Standalone mode, the logs of the dashboard are streamed until it is interrupted.
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			err := standalone.RunDashboard(ctx, standalone.DashboardOptions{
+				DaprRuntimePath: daprRuntimePath,
+				Port:            dashboardLocalPort,
+				Logs:            os.Stdout,
+				OnReady: func(webURL string) {
+					print.InfoStatusEvent(os.Stdout, "Dapr dashboard available at:\t%s\n", webURL)
+					openDashboardURL(webURL)
+				},
+			})
+			if errors.Is(err, standalone.ErrDashboardNotInstalled) {
+				print.FailureStatusEvent(os.Stderr, "%s, add it with `dapr init --dashboard-version latest`", err)
+				os.Exit(1)
+			}
 			if err != nil {
-				print.FailureStatusEvent(os.Stderr, "Failed to get Dapr install	directory: %v", err)
-			} else {
-				err = dashboardCmd.Run()
-				if err != nil {
-					print.FailureStatusEvent(os.Stderr, "Dapr dashboard failed to run: %v", err)
-				}
+				print.FailureStatusEvent(os.Stderr, "Dapr dashboard failed to run: %v", err)
+				os.Exit(1)
 			}
 		}
 	},
@@ -212,12 +225,24 @@ dapr dashboard -k -p 0
 	},
 }
 
+// openDashboardURL opens the dashboard at webURL in the default browser, unless --no-browser is set.
+func openDashboardURL(webURL string) {
+	if dashboardNoBrowser {
+		return
+	}
+	if err := browser.OpenURL(webURL); err != nil {
+		print.FailureStatusEvent(os.Stderr, "Failed to start Dapr dashboard in browser automatically")
+		print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Visit %s in your browser to view the dashboard", webURL))
+	}
+}
+
 func init() {
 	DashboardCmd.Flags().BoolVarP(&kubernetesMode, "kubernetes", "k", false, "Opens Dapr dashboard in local browser via local proxy to Kubernetes cluster")
 	DashboardCmd.Flags().BoolVarP(&dashboardVersionCmd, "version", "v", false, "Print the version for Dapr dashboard")
 	DashboardCmd.Flags().StringVarP(&dashboardHost, "address", "a", defaultHost, "Address to listen on. Only accepts IP address or localhost as a value")
 	DashboardCmd.Flags().IntVarP(&dashboardLocalPort, "port", "p", defaultLocalPort, "The local port on which to serve Dapr dashboard")
 	DashboardCmd.Flags().StringVarP(&dashboardNamespace, "namespace", "n", daprSystemNamespace, "The namespace where Dapr dashboard is running")
+	DashboardCmd.Flags().BoolVar(&dashboardNoBrowser, "no-browser", false, "Don't open the dashboard in the default browser")
 	DashboardCmd.Flags().BoolP("help", "h", false, "Print this help message")
 
 	RootCmd.AddCommand(DashboardCmd)
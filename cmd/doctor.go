@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	doctorOutputFormat     string
+	doctorContainerRuntime string
+	doctorComponentsPath   string
+	doctorFix              bool
+)
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the common problems of the local environment, with hints to fix them. Supported platforms: Self-hosted",
+	Example: `
+# Check the local environment
+dapr doctor
+
+# Check the environment set up with podman
+dapr doctor --container-runtime podman
+
+# Get the results of the checks in JSON format
+dapr doctor -o json
+
+# Restrict the permissions of the files of the install dir which are too open
+dapr doctor --fix
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(doctorOutputFormat, print.OutputJSON, print.OutputWide); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		doctorContainerRuntime = installContainerRuntime(cmd)
+		if !utils.IsValidContainerRuntime(doctorContainerRuntime) {
+			print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+			os.Exit(1)
+		}
+		report, err := standalone.RunDoctor(standalone.DoctorOptions{
+			DaprRuntimePath:  daprRuntimePath,
+			DockerNetwork:    resolveSetting(cmd, standalone.SettingNetwork),
+			ContainerRuntime: doctorContainerRuntime,
+			ComponentsPath:   doctorComponentsPath,
+			Fix:              doctorFix,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		} else {
+			table := print.NewTable(
+				print.TableColumn{Name: "Check", Key: "check"},
+				print.TableColumn{Name: "Status", Key: "status"},
+				print.TableColumn{Name: "Message", Key: "message", Truncate: true},
+				print.TableColumn{Name: "Hint", Key: "hint", Truncate: true},
+			)
+			for _, c := range report.Checks {
+				table.AddRow(c.Name, c.Status, c.Message, c.Hint)
+			}
+			if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			for _, v := range report.Permissions {
+				if v.Fixed {
+					print.InfoStatusEvent(os.Stdout, "Restricted the permissions of %s from %s to at most %s", v.Path, v.Mode, v.Allowed)
+				}
+			}
+		}
+		// Warnings don't fail the command, so that it can gate scripts on what actually breaks init and run.
+		switch {
+		case report.Failures > 0:
+			print.FailureStatusEvent(os.Stderr, "%d check(s) failed and %d warned, see the hints to fix them", report.Failures, report.Warnings)
+			os.Exit(1)
+		case report.Warnings > 0:
+			print.WarningStatusEvent(os.Stdout, "All the checks passed, with %d warning(s)", report.Warnings)
+		default:
+			print.SuccessStatusEvent(os.Stdout, "All the checks passed")
+		}
+	},
+}
+
+func init() {
+	DoctorCmd.Flags().String("network", "", "The Docker network the containers of the local environment run in")
+	DoctorCmd.Flags().StringVarP(&doctorContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	DoctorCmd.Flags().StringVarP(&doctorComponentsPath, "components-path", "d", "", "The components directory to check. Defaults to the one of the CLI config file, or $HOME/.dapr/components or %USERPROFILE%\\.dapr\\components")
+	DoctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Restrict the permissions of the files and directories of the install dir which are more open than the policy")
+	DoctorCmd.Flags().StringVarP(&doctorOutputFormat, "output", "o", "", "The output format of the checks. Valid values are: json, or wide to not truncate the columns")
+	DoctorCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(DoctorCmd)
+}
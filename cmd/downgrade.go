@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var DowngradeCmd = &cobra.Command{
+	Use:   "downgrade",
+	Short: "Roll the self-hosted Dapr runtime back to the version active before the last upgrade, or to an older version. Supported platforms: Self-hosted",
+	Example: `
+# Switch back to the runtime version active before the last upgrade, same as dapr upgrade --rollback
+dapr downgrade
+
+# Downgrade the runtime to an older version
+dapr downgrade --runtime-version 1.10.0
+
+# Roll back even if the previous version can't read the placement data of the active one
+dapr downgrade --force
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		upgradeRollback = false
+		upgradeStandalone(cmd, true)
+	},
+}
+
+func init() {
+	DowngradeCmd.Flags().StringVarP(&upgradeRuntimeVersion, "runtime-version", "", "", "The older version of the Dapr runtime to downgrade to, for example: 1.10.0. Defaults to the version active before the last upgrade")
+	DowngradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Reinstall the runtime even if the version is already active, and switch to versions which can't read the placement data of the active one")
+	DowngradeCmd.Flags().BoolVar(&upgradeWait, "wait-for-lock", false, "Wait for a concurrently running init, uninstall or upgrade to finish instead of failing")
+	DowngradeCmd.Flags().StringVar(&upgradePubKeyFile, "pubkey-file", "", "The minisign public key file to verify the signed checksums of the releases with, such as the key of a mirror. The signatures aren't verified without it, as the CLI embeds no release signing key")
+	DowngradeCmd.Flags().BoolVar(&upgradeSkipSignature, "skip-signature-verification", false, "INSECURE: install the downloaded archives without verifying the signatures of their checksums, for releases which don't publish them")
+	DowngradeCmd.Flags().String("network", "", "The Docker network the placement container runs in")
+	DowngradeCmd.Flags().StringVarP(&upgradeContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	DowngradeCmd.Flags().StringVarP(&upgradeOutputFormat, "output", "o", "", "The output format. Valid values are: json for a report of the versions before and after the downgrade")
+	DowngradeCmd.Flags().String("image-registry", "", "Custom/Private docker image repository URL")
+	DowngradeCmd.Flags().StringVarP(&upgradeImageVariant, "image-variant", "", "", "The image variant to use for the Dapr runtime, for example: mariner")
+	DowngradeCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(DowngradeCmd)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	exportDiagnosticsFile              string
+	exportDiagnosticsContainerRuntime  string
+	exportDiagnosticsComponentsPath    string
+	exportDiagnosticsIncludeComponents bool
+)
+
+var ExportDiagnosticsCmd = &cobra.Command{
+	Use:   "export-diagnostics",
+	Short: "Collect the diagnostics of the local environment into a zip file to attach to a support request. Supported platforms: Self-hosted",
+	Example: `
+# Write the diagnostics bundle to the diagnostics directory of the install dir
+dapr export-diagnostics
+
+# Write the diagnostics bundle to a given file
+dapr export-diagnostics --output-file ./dapr-diagnostics.zip
+
+# Include the component files, with the values of their secrets redacted
+dapr export-diagnostics --include-components
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		exportDiagnosticsContainerRuntime = installContainerRuntime(cmd)
+		if !utils.IsValidContainerRuntime(exportDiagnosticsContainerRuntime) {
+			print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+			os.Exit(1)
+		}
+		bundle, err := standalone.ExportDiagnostics(standalone.ExportDiagnosticsOptions{
+			DaprRuntimePath:   daprRuntimePath,
+			DockerNetwork:     resolveSetting(cmd, standalone.SettingNetwork),
+			ContainerRuntime:  exportDiagnosticsContainerRuntime,
+			ComponentsPath:    exportDiagnosticsComponentsPath,
+			IncludeComponents: exportDiagnosticsIncludeComponents,
+			Output:            exportDiagnosticsFile,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Error exporting the diagnostics: %s", err)
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Diagnostics bundle written to %s (%s)", bundle.Path, formatBundleSize(bundle.Size))
+		print.InfoStatusEvent(os.Stdout, "It holds no credentials, the values of the secrets are redacted. Review it before attaching it to a support request")
+	},
+}
+
+// formatBundleSize formats a size in bytes for the output.
+func formatBundleSize(size int64) string {
+	const kb = 1024
+	if size < kb*kb {
+		return fmt.Sprintf("%.1f KB", float64(size)/kb)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(kb*kb))
+}
+
+func init() {
+	ExportDiagnosticsCmd.Flags().StringVar(&exportDiagnosticsFile, "output-file", "", "The path of the zip file to write. Defaults to a new file in the diagnostics directory of the install dir")
+	ExportDiagnosticsCmd.Flags().String("network", "", "The Docker network the containers of the local environment run in")
+	ExportDiagnosticsCmd.Flags().StringVarP(&exportDiagnosticsContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	ExportDiagnosticsCmd.Flags().StringVarP(&exportDiagnosticsComponentsPath, "components-path", "d", "", "The components directory to list. Defaults to the one of the CLI config file, or $HOME/.dapr/components or %USERPROFILE%\\.dapr\\components")
+	ExportDiagnosticsCmd.Flags().BoolVar(&exportDiagnosticsIncludeComponents, "include-components", false, "Include the contents of the component files, with the values of their secrets redacted")
+	ExportDiagnosticsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(ExportDiagnosticsCmd)
+}
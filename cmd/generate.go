@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var (
+	composeSidecarAppID          string
+	composeSidecarAppPort        int
+	composeSidecarAppService     string
+	composeSidecarNetwork        string
+	composeSidecarRuntimeVersion string
+	composeSidecarComponentsPath string
+	composeSidecarHTTPPort       int
+	composeSidecarGRPCPort       int
+)
+
+var GenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the configuration to run Dapr with other tools. Supported platforms: Self-hosted",
+}
+
+var GenerateComposeSidecarCmd = &cobra.Command{
+	Use:   "compose-sidecar",
+	Short: "Print a compose service block running the sidecar of a compose service, to merge into its compose file. Supported platforms: Self-hosted",
+	Long: `Print a compose file with a service running the sidecar of an app service of a compose project.
+
+The sidecar runs the runtime image of the install, shares the network namespace of the app service so that they
+reach each other on localhost, mounts the components directory, and reaches the placement container of dapr init
+by its alias in the network of init, which the app service is attached to. Merge the block into the compose file
+of the app with docker-compose -f docker-compose.yml -f dapr.yml up.
+`,
+	Example: `
+# Run the sidecar of the myapp service, listening on port 3000
+dapr generate compose-sidecar --app-id myapp --app-port 3000 > dapr.yml
+docker-compose -f docker-compose.yml -f dapr.yml up
+
+# Run the sidecar of the app with the ID x, in the web service
+dapr generate compose-sidecar --app-id x --app-port 3000 --app-service web
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sidecar, err := standalone.GenerateComposeSidecar(standalone.ComposeSidecarOptions{
+			DaprRuntimePath: daprRuntimePath,
+			AppID:           composeSidecarAppID,
+			AppPort:         composeSidecarAppPort,
+			AppService:      composeSidecarAppService,
+			DockerNetwork:   composeSidecarNetwork,
+			RuntimeVersion:  composeSidecarRuntimeVersion,
+			ComponentsPath:  composeSidecarComponentsPath,
+			DaprHTTPPort:    composeSidecarHTTPPort,
+			DaprGRPCPort:    composeSidecarGRPCPort,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if !sidecar.InNetwork {
+			print.WarningStatusEvent(os.Stderr, "The containers of dapr init aren't in a docker network, the sidecar reaches the placement service at %s. The components must reach their services through the host too, or init with --network to run them in a network", sidecar.PlacementAddress)
+		}
+		// The file goes alone to stdout, to redirect it to the compose file.
+		fmt.Print(sidecar.File)
+	},
+}
+
+func init() {
+	GenerateComposeSidecarCmd.Flags().StringVarP(&composeSidecarAppID, "app-id", "a", "", "The ID of the app")
+	GenerateComposeSidecarCmd.Flags().IntVarP(&composeSidecarAppPort, "app-port", "p", 0, "The port the app listens on")
+	GenerateComposeSidecarCmd.Flags().StringVar(&composeSidecarAppService, "app-service", "", "The compose service of the app. Defaults to the app ID")
+	GenerateComposeSidecarCmd.Flags().StringVar(&composeSidecarNetwork, "network", "", "The Docker network the containers of init run in. Defaults to the one of the install")
+	GenerateComposeSidecarCmd.Flags().StringVarP(&composeSidecarRuntimeVersion, "runtime-version", "", "", "The version of the runtime image. Defaults to the installed one")
+	GenerateComposeSidecarCmd.Flags().StringVarP(&composeSidecarComponentsPath, "components-path", "d", "", "The components directory to mount in the sidecar. Defaults to the one of the install")
+	GenerateComposeSidecarCmd.Flags().IntVarP(&composeSidecarHTTPPort, "dapr-http-port", "H", 3500, "The HTTP port of the sidecar")
+	GenerateComposeSidecarCmd.Flags().IntVarP(&composeSidecarGRPCPort, "dapr-grpc-port", "G", 50001, "The gRPC port of the sidecar")
+	GenerateComposeSidecarCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	GenerateComposeSidecarCmd.MarkFlagRequired("app-id")
+	GenerateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	GenerateCmd.AddCommand(GenerateComposeSidecarCmd)
+	RootCmd.AddCommand(GenerateCmd)
+}
@@ -14,13 +14,19 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
@@ -29,10 +35,12 @@ import (
 )
 
 var (
-	kubernetesMode    bool
-	wait              bool
-	timeout           uint
-	slimMode          bool
+	kubernetesMode bool
+	wait           bool
+	timeout        uint
+	slimMode       bool
+	// slimModeSet is set if slim mode is set with --slim, its environment variable or the CLI config file.
+	slimModeSet       bool
 	runtimeVersion    string
 	dashboardVersion  string
 	allNamespaces     bool
@@ -41,26 +49,51 @@ var (
 	enableMTLS        bool
 	enableHA          bool
 	values            []string
+	initRender        bool
+	initValuesFile    string
 	fromDir           string
 	containerRuntime  string
 	imageVariant      string
+	initRetries       int
+	diagnosticsBundle bool
+	initStrict        bool
+	initForce         bool
+	initSystem        string
+	initOutputFormat  string
+	initFromLockfile  string
+	initPubKeyFile    string
+	initSkipSignature bool
+	initChecksumFile  string
+	initSignatureFile string
+	initUnhardened    bool
+	initWithMTLS      bool
+	// initWaitForLock waits for a concurrently running init, uninstall or upgrade to finish instead of failing.
+	initWaitForLock bool
+	// initComponentsProvider provides the state store and pubsub components of self-hosted mode.
+	initComponentsProvider string
+	// initElevatedReport is where the install relaunched through a UAC prompt writes its report.
+	initElevatedReport string
 )
 
 var InitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Install Dapr on supported hosting platforms. Supported platforms: Kubernetes and self-hosted",
 	PreRun: func(cmd *cobra.Command, args []string) {
-		viper.BindPFlag("network", cmd.Flags().Lookup("network"))
-		viper.BindPFlag("image-registry", cmd.Flags().Lookup("image-registry"))
-
-		runtimeVersion = getConfigurationValue("runtime-version", cmd)
-		dashboardVersion = getConfigurationValue("dashboard-version", cmd)
-		containerRuntime = getConfigurationValue("container-runtime", cmd)
+		runtimeVersion = resolveSetting(cmd, standalone.SettingRuntimeVersion)
+		dashboardVersion = resolveSetting(cmd, standalone.SettingDashboardVersion)
+		containerRuntime = resolveSetting(cmd, standalone.SettingContainerRuntime)
+		slim := cliSetting(cmd, standalone.SettingSlimMode)
+		// The setting is validated as a boolean.
+		slimMode, _ = strconv.ParseBool(slim.Value)
+		slimModeSet = slim.Source != standalone.SettingSourceDefault
 	},
 	Example: `
 # Initialize Dapr in self-hosted mode
 dapr init
 
+# Reproduce the self-hosted environment pinned by the lockfile of a project
+dapr init --from-lockfile dapr.lock
+
 # Initialize Dapr in self-hosted mode with a provided docker image registry. Image looked up as <registry-url>/<image>.
 # Check docs or README for more information on the format of the image path that is required.
 dapr init --image-registry <registry-url>
@@ -74,15 +107,42 @@ dapr init -k --wait --timeout 600
 # Initialize particular Dapr runtime in self-hosted mode
 dapr init --runtime-version 0.10.0
 
+# Print the manifests of a Kubernetes install to a file, to apply them with another tool
+dapr init -k --render > runtime.yaml
+
+# Initialize Dapr in Kubernetes with the replica counts and the resource requests of a values file
+dapr init -k --values values.yaml
+
 # Initialize particular Dapr runtime in Kubernetes
 dapr init -k --runtime-version 0.10.0
 
 # Initialize Dapr in slim self-hosted mode
 dapr init -s
 
+# Initialize Dapr in slim self-hosted mode, running the placement service as a systemd user unit
+dapr init -s --init-system systemd
+
+# Initialize Dapr in slim self-hosted mode on Windows, running the placement service as a Windows service
+dapr init -s --init-system windows-service
+
+# Initialize Dapr in self-hosted mode, retrying failed downloads up to 5 times within 10 minutes
+dapr init --retries 5 --timeout 600
+
+# Initialize Dapr in self-hosted mode and return once placement and Redis accept connections, within 2 minutes
+dapr init --wait --timeout 120
+
+# Initialize Dapr in self-hosted mode with the in-memory state store and pubsub components instead of Redis
+dapr init --components-provider in-memory
+
+# Initialize Dapr in self-hosted mode and print an install report in JSON format
+dapr init -o json
+
 # Initialize Dapr from a directory (installer-bundle installation) (Preview feature)
 dapr init --from-dir <path-to-directory>
 
+# Initialize Dapr from a bundle, verifying its archives against checksums signed with the key of the bundle
+dapr init --from-dir <path-to-directory> --checksum-file <path-to-checksums> --signature-file <path-to-signature> --pubkey-file <path-to-public-key>
+
 # Initialize dapr with a particular image variant. Allowed values: "mariner"
 dapr init --image-variant <variant>
 
@@ -93,49 +153,46 @@ dapr init --runtime-path <path-to-install-directory>
 # See more at: https://docs.dapr.io/getting-started/
 `,
 	Run: func(cmd *cobra.Command, args []string) {
+		if kubernetesMode && initOutputFormat != "" {
+			print.FailureStatusEvent(os.Stderr, "--output is only valid for self-hosted mode")
+			os.Exit(1)
+		}
+		if err := setOutputFormat(initOutputFormat, print.OutputJSON, print.OutputJSONLines, print.OutputWide); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		imageRegistryFlag := resolveSetting(cmd, standalone.SettingImageRegistry)
+		if (initRender || initValuesFile != "") && !kubernetesMode {
+			print.FailureStatusEvent(os.Stderr, "--render and --values are only valid for Kubernetes mode")
+			os.Exit(1)
+		}
+		// The manifests are the only output on stdout, so that it can be redirected to a file.
+		if initRender {
+			config, err := kubernetesInitConfiguration(cmd, imageRegistryFlag)
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			manifests, err := kubernetes.Render(config)
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			fmt.Print(manifests)
+			return
+		}
 		print.PendingStatusEvent(os.Stdout, "Making the jump to hyperspace...")
-		imageRegistryFlag := strings.TrimSpace(viper.GetString("image-registry"))
 
 		if kubernetesMode {
 			print.InfoStatusEvent(os.Stdout, "Note: To install Dapr using Helm, see here: https://docs.dapr.io/getting-started/install-dapr-kubernetes/#install-with-helm-advanced\n")
-			imageRegistryURI := ""
-			var err error
-
-			if len(strings.TrimSpace(daprRuntimePath)) != 0 {
-				print.FailureStatusEvent(os.Stderr, "--runtime-path is only valid for self-hosted mode")
-				os.Exit(1)
-			}
-
 			if len(imageRegistryFlag) != 0 {
 				warnForPrivateRegFeat()
-				imageRegistryURI = imageRegistryFlag
-			} else {
-				imageRegistryURI, err = kubernetes.GetImageRegistry()
 			}
+			config, err := kubernetesInitConfiguration(cmd, imageRegistryFlag)
 			if err != nil {
 				print.FailureStatusEvent(os.Stderr, err.Error())
 				os.Exit(1)
 			}
-			if err = verifyCustomCertFlags(cmd); err != nil {
-				print.FailureStatusEvent(os.Stderr, err.Error())
-				os.Exit(1)
-			}
-
-			config := kubernetes.InitConfiguration{
-				Namespace:                 initNamespace,
-				Version:                   runtimeVersion,
-				DashboardVersion:          dashboardVersion,
-				EnableMTLS:                enableMTLS,
-				EnableHA:                  enableHA,
-				Args:                      values,
-				Wait:                      wait,
-				Timeout:                   timeout,
-				ImageRegistryURI:          imageRegistryURI,
-				ImageVariant:              imageVariant,
-				RootCertificateFilePath:   strings.TrimSpace(caRootCertificateFile),
-				IssuerCertificateFilePath: strings.TrimSpace(issuerPublicCertificateFile),
-				IssuerPrivateKeyFilePath:  strings.TrimSpace(issuerPrivateKeyFile),
-			}
 			err = kubernetes.Init(config)
 			if err != nil {
 				print.FailureStatusEvent(os.Stderr, err.Error())
@@ -143,10 +200,24 @@ dapr init --runtime-path <path-to-install-directory>
 			}
 			print.SuccessStatusEvent(os.Stdout, fmt.Sprintf("Success! Dapr has been installed to namespace %s. To verify, run `dapr status -k' in your terminal. To get started, go here: https://aka.ms/dapr-getting-started", config.Namespace))
 		} else {
+			var lock *standalone.Lockfile
+			if initFromLockfile != "" {
+				var err error
+				lock, err = readInitLockfile(cmd, initFromLockfile)
+				if err != nil {
+					print.FailureStatusEvent(os.Stderr, err.Error())
+					os.Exit(1)
+				}
+				runtimeVersion, dashboardVersion, slimMode = lock.RuntimeVersion, lock.DashboardVersion, lock.SlimMode
+			}
+			if !slimMode && lock == nil && !slimModeSet && standalone.DefaultSlimMode(containerRuntime) {
+				print.InfoStatusEvent(os.Stdout, "%s is not installed, installing in slim mode on %s without the placement, Redis and Zipkin containers", containerRuntime, runtime.GOOS)
+				slimMode = true
+			}
 			dockerNetwork := ""
 			imageRegistryURI := ""
 			if !slimMode {
-				dockerNetwork = viper.GetString("network")
+				dockerNetwork = resolveSetting(cmd, standalone.SettingNetwork)
 				imageRegistryURI = imageRegistryFlag
 			}
 			// If both --image-registry and --from-dir flags are given, error out saying only one can be given.
@@ -161,20 +232,278 @@ dapr init --runtime-path <path-to-install-directory>
 				warnForPrivateRegFeat()
 			}
 
+			if !standalone.IsValidComponentsProvider(initComponentsProvider) {
+				print.FailureStatusEvent(os.Stderr, "Invalid components provider. Supported values are redis, in-memory and none.")
+				os.Exit(1)
+			}
+			if !standalone.IsValidInitSystem(initSystem) {
+				print.FailureStatusEvent(os.Stderr, "Invalid init system. Supported values are none, systemd and windows-service.")
+				os.Exit(1)
+			}
+			if initSystem == standalone.InitSystemWindowsService && runtime.GOOS != string(windowsOsType) {
+				print.FailureStatusEvent(os.Stderr, "--init-system windows-service is only available on Windows")
+				os.Exit(1)
+			}
+			if initSystem != standalone.InitSystemNone && !slimMode {
+				print.FailureStatusEvent(os.Stderr, "--init-system is only valid with --slim, the services run in containers otherwise")
+				os.Exit(1)
+			}
+			if initSkipSignature && initPubKeyFile != "" {
+				print.FailureStatusEvent(os.Stderr, "--pubkey-file can't be used with --skip-signature-verification")
+				os.Exit(1)
+			}
+			if (initChecksumFile != "" || initSignatureFile != "") && strings.TrimSpace(fromDir) == "" {
+				print.FailureStatusEvent(os.Stderr, "--checksum-file and --signature-file verify the archives of a bundle, they can only be used with --from-dir")
+				os.Exit(1)
+			}
+			if (initChecksumFile != "" || initSignatureFile != "") && initPubKeyFile == "" && !initSkipSignature {
+				print.FailureStatusEvent(os.Stderr, "--checksum-file and --signature-file need --pubkey-file, the key the checksums of the bundle are signed with")
+				os.Exit(1)
+			}
+			if (initChecksumFile != "" || initSignatureFile != "") && initSkipSignature {
+				print.FailureStatusEvent(os.Stderr, "--checksum-file and --signature-file can't be used with --skip-signature-verification")
+				os.Exit(1)
+			}
 			if !utils.IsValidContainerRuntime(containerRuntime) {
 				print.FailureStatusEvent(os.Stdout, "Invalid container runtime. Supported values are docker and podman.")
 				os.Exit(1)
 			}
-			err := standalone.Init(runtimeVersion, dashboardVersion, dockerNetwork, slimMode, imageRegistryURI, fromDir, containerRuntime, imageVariant, daprRuntimePath)
+			var elevation *standalone.ElevationReport
+			if runtime.GOOS == string(windowsOsType) && initElevatedReport == "" {
+				var err error
+				elevation, err = standalone.PlanElevation(daprRuntimePath, initSystem)
+				if err != nil {
+					print.FailureStatusEvent(os.Stderr, err.Error())
+					os.Exit(1)
+				}
+				switch elevation.Decision {
+				case standalone.ElevationPerUser:
+					print.WarningStatusEvent(os.Stdout, "%s, installing in %s instead. To install in %s, run the command from a terminal started with Run as administrator", elevation.Reason, elevation.InstallDir, elevation.RequestedDir)
+					daprRuntimePath = filepath.Dir(elevation.InstallDir)
+				case standalone.ElevationUAC:
+					print.InfoStatusEvent(os.Stdout, "The install needs administrator rights as %s, it runs through a UAC prompt in a new window", elevation.Reason)
+					report, err := standalone.RunElevated(os.Args[1:], elevation)
+					if err != nil {
+						print.FailureStatusEvent(os.Stderr, err.Error())
+						os.Exit(1)
+					}
+					printInitReport(report, "")
+					return
+				}
+			}
+			logPath := ""
+			if cliLog, err := standalone.OpenCLILog(daprRuntimePath); err != nil {
+				print.WarningStatusEvent(os.Stderr, "could not open the CLI log file: %s", err)
+			} else {
+				defer cliLog.Close()
+				print.SetLogFile(cliLog)
+				print.LogToFile(print.LogInfo, "dapr %s", strings.Join(os.Args[1:], " "))
+				logPath = cliLog.Path()
+			}
+			checkLegacyInstall(os.Stdout, containerRuntime)
+
+			ctx := context.Background()
+			if cmd.Flags().Changed("timeout") {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+				defer cancel()
+			}
+			ctx, stopInterrupts := interruptibleInit(ctx)
+			defer stopInterrupts()
+			if initSkipSignature {
+				print.WarningStatusEvent(os.Stdout, "--skip-signature-verification is set, the downloaded archives are installed without verifying that they are the ones published with the release")
+			}
+			signature := standalone.SignatureOptions{
+				PublicKeyFile: initPubKeyFile,
+				Skip:          initSkipSignature,
+				ChecksumFile:  initChecksumFile,
+				SignatureFile: initSignatureFile,
+			}
+			report, err := standalone.NewInstaller(
+				standalone.WithRuntimeVersion(runtimeVersion),
+				standalone.WithDashboardVersion(dashboardVersion),
+				standalone.WithDockerNetwork(dockerNetwork),
+				standalone.WithSlimMode(slimMode),
+				standalone.WithImageRegistry(imageRegistryURI),
+				standalone.WithFromDir(fromDir),
+				standalone.WithContainerRuntime(containerRuntime),
+				standalone.WithImageVariant(imageVariant),
+				standalone.WithRuntimePath(daprRuntimePath),
+				standalone.WithRetries(initRetries),
+				standalone.WithDiagnosticsBundle(diagnosticsBundle),
+				standalone.WithWaitForLock(initWaitForLock),
+				standalone.WithStrict(initStrict),
+				standalone.WithForce(initForce),
+				standalone.WithInitSystem(initSystem),
+				standalone.WithLockfile(lock),
+				standalone.WithSignatureOptions(signature),
+				standalone.WithUnhardenedRedis(initUnhardened),
+				standalone.WithComponentsProvider(initComponentsProvider),
+			).Install(ctx)
 			if err != nil {
-				print.FailureStatusEvent(os.Stderr, err.Error())
-				os.Exit(1)
+				report.Error = err.Error()
+				if errors.Is(context.Cause(ctx), standalone.ErrInitInterrupted) && report.Interrupted == nil {
+					// Init was interrupted before it created anything.
+					report.Interrupted = &standalone.InitRollback{Removed: []standalone.InitArtifact{}, LeftBehind: []standalone.InitArtifact{}}
+				}
+			} else {
+				if initWithMTLS {
+					generateInitMTLSCredentials(report)
+				}
+				if wait && !report.UpToDate {
+					waitForInitEnvironment(ctx, report)
+				}
 			}
-			print.SuccessStatusEvent(os.Stdout, "Success! Dapr is up and running. To get started, go here: https://aka.ms/dapr-getting-started")
+			report.Elevation = elevation
+			if initElevatedReport != "" {
+				if writeErr := standalone.WriteElevatedReport(initElevatedReport, report); writeErr != nil {
+					print.FailureStatusEvent(os.Stderr, writeErr.Error())
+				}
+			}
+			printInitReport(report, logPath)
 		}
 	},
 }
 
+// printInitReport prints the report of a self-hosted init, and exits with an error if the init failed. logPath is the
+// CLI log file with the details of the failure, if any.
+func printInitReport(report *standalone.InitReport, logPath string) {
+	if print.GetOutputFormat() == print.OutputJSON {
+		if printErr := utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); printErr != nil {
+			print.FailureStatusEvent(os.Stderr, printErr.Error())
+			os.Exit(1)
+		}
+	}
+	if report.Interrupted != nil {
+		printInitRollback(report.Interrupted)
+		os.Exit(standalone.InterruptedExitCode)
+	}
+	if report.Error != "" {
+		if logPath != "" {
+			print.FailureStatusEvent(os.Stderr, "%s\nSee the log file for details: %s", report.Error, logPath)
+		} else {
+			print.FailureStatusEvent(os.Stderr, report.Error)
+		}
+		os.Exit(1)
+	}
+	if report.UpToDate {
+		print.SuccessStatusEvent(os.Stdout, "Dapr is already up to date (v%s) in %s, use --force to reinstall it", report.RuntimeVersion, report.InstallDir)
+		return
+	}
+	if report.Elevation != nil && report.Elevation.Decision == standalone.ElevationUAC {
+		print.SuccessStatusEvent(os.Stdout, "Success! Dapr was installed in %s as administrator. To get started, go here: https://aka.ms/dapr-getting-started", report.InstallDir)
+		return
+	}
+	printSlowInitTimings(report)
+	print.SuccessStatusEvent(os.Stdout, "Success! Dapr is up and running. To get started, go here: https://aka.ms/dapr-getting-started")
+	if report.Readiness == nil && !wait {
+		print.InfoStatusEvent(os.Stdout, "The readiness of the environment wasn't verified, use --wait for init to return once it is usable")
+	}
+}
+
+// printSlowInitTimings prints the slowest timings of the init of report, if it took longer than
+// standalone.SlowInitThreshold. All the timings are in the JSON report and the CLI log file.
+func printSlowInitTimings(report *standalone.InitReport) {
+	took := time.Duration(report.DurationMs) * time.Millisecond
+	if took <= standalone.SlowInitThreshold || len(report.Timings) == 0 {
+		return
+	}
+	print.InfoStatusEvent(os.Stdout, "Init took %s, the slowest parts were:", took.Round(time.Second))
+	for _, t := range report.SlowestTimings(3) {
+		print.InfoStatusEvent(os.Stdout, "  %s", t)
+	}
+}
+
+// waitForInitEnvironment waits up to --timeout for the environment installed by init to be usable, reporting the
+// parts it is still waiting for, and records the failure in report if it isn't.
+func waitForInitEnvironment(ctx context.Context, report *standalone.InitReport) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	print.InfoStatusEvent(os.Stdout, "Waiting for the environment to be usable")
+	err := standalone.WaitForEnvironment(ctx, report, containerRuntime, func(name string, waited time.Duration) {
+		print.InfoStatusEvent(os.Stdout, "waiting for %s (%s)", name, waited.Round(time.Second))
+	})
+	if err != nil {
+		report.Error = err.Error()
+	}
+}
+
+// printInitRollback prints what the rollback of an interrupted init cleaned up and what it left behind.
+func printInitRollback(rollback *standalone.InitRollback) {
+	for _, a := range rollback.Removed {
+		print.InfoStatusEvent(os.Stderr, "Removed the %s %s", a.Kind, a.Name)
+	}
+	for _, a := range rollback.LeftBehind {
+		print.WarningStatusEvent(os.Stderr, "Left behind the %s %s: %s", a.Kind, a.Name, a.Reason)
+	}
+	print.FailureStatusEvent(os.Stderr, "Init was interrupted, cleaned up %d and left behind %d item(s)", len(rollback.Removed), len(rollback.LeftBehind))
+}
+
+// interruptibleInit returns a context which is cancelled with standalone.ErrInitInterrupted on SIGINT or SIGTERM, for
+// init to roll back what it created. A second signal exits at once, without waiting for the rollback. The returned
+// function stops handling the signals.
+func interruptibleInit(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		print.WarningStatusEvent(os.Stderr, "Interrupted, rolling back what init created. Press Ctrl-C again to exit at once")
+		cancel(standalone.ErrInitInterrupted)
+		select {
+		case <-signals:
+			print.FailureStatusEvent(os.Stderr, "Exited without rolling back, run `dapr uninstall` to remove what init left behind")
+			os.Exit(standalone.InterruptedExitCode)
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel(nil)
+	}
+}
+
+// kubernetesInitConfiguration returns the configuration of an init in Kubernetes mode from the flags.
+func kubernetesInitConfiguration(cmd *cobra.Command, imageRegistryFlag string) (kubernetes.InitConfiguration, error) {
+	if len(strings.TrimSpace(daprRuntimePath)) != 0 {
+		return kubernetes.InitConfiguration{}, errors.New("--runtime-path is only valid for self-hosted mode")
+	}
+
+	imageRegistryURI := imageRegistryFlag
+	if len(imageRegistryURI) == 0 {
+		var err error
+		if imageRegistryURI, err = kubernetes.GetImageRegistry(); err != nil {
+			return kubernetes.InitConfiguration{}, err
+		}
+	}
+	if err := verifyCustomCertFlags(cmd); err != nil {
+		return kubernetes.InitConfiguration{}, err
+	}
+
+	return kubernetes.InitConfiguration{
+		Namespace:                 initNamespace,
+		Version:                   runtimeVersion,
+		DashboardVersion:          dashboardVersion,
+		EnableMTLS:                enableMTLS,
+		EnableHA:                  enableHA,
+		Args:                      values,
+		Wait:                      wait,
+		Timeout:                   timeout,
+		ImageRegistryURI:          imageRegistryURI,
+		ImageVariant:              imageVariant,
+		RootCertificateFilePath:   strings.TrimSpace(caRootCertificateFile),
+		IssuerCertificateFilePath: strings.TrimSpace(issuerPublicCertificateFile),
+		IssuerPrivateKeyFilePath:  strings.TrimSpace(issuerPrivateKeyFile),
+		ValuesFile:                strings.TrimSpace(initValuesFile),
+	}, nil
+}
+
 func verifyCustomCertFlags(cmd *cobra.Command) error {
 	ca := cmd.Flags().Lookup("ca-root-certificate")
 	issuerKey := cmd.Flags().Lookup("issuer-private-key")
@@ -192,6 +521,32 @@ func verifyCustomCertFlags(cmd *cobra.Command) error {
 	return nil
 }
 
+// readInitLockfile reads the lockfile init reproduces, which pins the versions and the slim mode: the flags setting
+// them must agree with it.
+func readInitLockfile(cmd *cobra.Command, path string) (*standalone.Lockfile, error) {
+	if strings.TrimSpace(fromDir) != "" {
+		return nil, fmt.Errorf("both --from-lockfile and --from-dir flags cannot be given at the same time")
+	}
+	lock, err := standalone.ReadLockfile(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range []struct {
+		flag   string
+		value  string
+		locked string
+	}{
+		{"runtime-version", runtimeVersion, lock.RuntimeVersion},
+		{"dashboard-version", dashboardVersion, lock.DashboardVersion},
+		{"slim", fmt.Sprint(slimMode), fmt.Sprint(lock.SlimMode)},
+	} {
+		if cmd.Flags().Changed(f.flag) && f.value != f.locked {
+			return nil, fmt.Errorf("--%s %s conflicts with %s in the lockfile %s", f.flag, f.value, f.locked, path)
+		}
+	}
+	return lock, nil
+}
+
 func warnForPrivateRegFeat() {
 	print.WarningStatusEvent(os.Stdout, "Flag --image-registry is a preview feature and is subject to change.")
 }
@@ -202,18 +557,37 @@ func init() {
 	defaultContainerRuntime := string(utils.DOCKER)
 
 	InitCmd.Flags().BoolVarP(&kubernetesMode, "kubernetes", "k", false, "Deploy Dapr to a Kubernetes cluster")
-	InitCmd.Flags().BoolVarP(&wait, "wait", "", false, "Wait for Kubernetes initialization to complete")
-	InitCmd.Flags().UintVarP(&timeout, "timeout", "", 300, "The wait timeout for the Kubernetes installation")
-	InitCmd.Flags().BoolVarP(&slimMode, "slim", "s", false, "Exclude placement service, Redis and Zipkin containers from self-hosted installation")
+	InitCmd.Flags().BoolVarP(&wait, "wait", "", false, "Wait for Kubernetes initialization to complete. In self-hosted mode, return once the binaries run and placement, Redis and Zipkin accept connections")
+	InitCmd.Flags().BoolVar(&initWaitForLock, "wait-for-lock", false, "Wait for a concurrently running self-hosted init, uninstall or upgrade to finish instead of failing")
+	InitCmd.Flags().UintVarP(&timeout, "timeout", "", 300, "The wait timeout for the Kubernetes installation. In self-hosted mode, the overall timeout for the installation when set, and the one of --wait for the environment to be usable")
+	InitCmd.Flags().IntVarP(&initRetries, "retries", "", 3, "The number of times to retry failed downloads and image pulls in self-hosted mode")
+	InitCmd.Flags().StringVarP(&initOutputFormat, "output", "o", "", "The output format for self-hosted mode. Valid values are: json for an install report, jsonl for progress events, or wide to not truncate the summary")
+	InitCmd.Flags().BoolVar(&initForce, "force", false, "Reinstall the self-hosted installation even if it is up to date: replace the binaries, recreate the containers with their images pulled again, re-pinning their digests, and regenerate the configuration file even if it has been edited")
+	InitCmd.Flags().BoolVar(&initStrict, "strict", false, "Fail the self-hosted installation if any step reports a warning")
+	InitCmd.Flags().BoolVarP(&diagnosticsBundle, "diagnostics-bundle", "", false, "Write a diagnostics bundle to attach to bug reports if the self-hosted installation fails")
+	InitCmd.Flags().BoolVarP(&slimMode, "slim", "s", false, "Exclude placement service, Redis and Zipkin containers from self-hosted installation. The default on FreeBSD when the container runtime is not installed")
+	InitCmd.Flags().StringVar(&initSystem, "init-system", standalone.InitSystemNone, "The init system to run the placement binary, and a locally installed redis-server, as services in slim mode. Supported values are none (default), systemd and windows-service, which needs an elevated prompt")
 	InitCmd.Flags().StringVarP(&runtimeVersion, "runtime-version", "", defaultRuntimeVersion, "The version of the Dapr runtime to install, for example: 1.0.0")
 	InitCmd.Flags().StringVarP(&dashboardVersion, "dashboard-version", "", defaultDashboardVersion, "The version of the Dapr dashboard to install, for example: 0.13.0")
 	InitCmd.Flags().StringVarP(&initNamespace, "namespace", "n", "dapr-system", "The Kubernetes namespace to install Dapr in")
 	InitCmd.Flags().BoolVarP(&enableMTLS, "enable-mtls", "", true, "Enable mTLS in your cluster")
 	InitCmd.Flags().BoolVarP(&enableHA, "enable-ha", "", false, "Enable high availability (HA) mode")
 	InitCmd.Flags().String("network", "", "The Docker network on which to deploy the Dapr runtime")
+	InitCmd.Flags().StringVar(&initFromLockfile, "from-lockfile", "", "Reproduce the self-hosted environment pinned by the lockfile written by init, for example the "+standalone.DefaultLockfileName+" of a project")
+	InitCmd.Flags().StringVar(&initPubKeyFile, "pubkey-file", "", "The minisign public key file to verify the signed checksums of the releases with, such as the key of a mirror. The signatures aren't verified without it, as the CLI embeds no release signing key")
+	InitCmd.Flags().BoolVar(&initSkipSignature, "skip-signature-verification", false, "INSECURE: install the downloaded archives without verifying the signatures of their checksums, for releases which don't publish them")
+	InitCmd.Flags().BoolVar(&initWithMTLS, "with-mtls", false, "Generate a root certificate and issuer credentials in the certs directory of the self-hosted installation, with the configuration enabling mTLS between the sidecars, as dapr mtls generate")
+	InitCmd.Flags().StringVar(&initComponentsProvider, "components-provider", standalone.ComponentsProviderRedis, "The provider of the state store and pubsub components of self-hosted mode. Supported values are redis (default), which runs a Redis container, in-memory, which runs nothing, and none, which sets up no components")
+	InitCmd.Flags().BoolVar(&initUnhardened, "unhardened", false, "Run the Redis container as root with a writable root filesystem, for Redis images which can't run as the redis user with a read-only root filesystem")
 	InitCmd.Flags().StringVarP(&fromDir, "from-dir", "", "", "Use Dapr artifacts from local directory for self-hosted installation")
+	InitCmd.Flags().StringVar(&initChecksumFile, "checksum-file", "", "The checksums manifest to verify the archives of the --from-dir bundle against. Defaults to the checksums.txt file next to the archives, or at the root of the bundle")
+	InitCmd.Flags().StringVar(&initSignatureFile, "signature-file", "", "The minisign signature of the --checksum-file manifest. Defaults to the manifest file with the .minisig extension")
 	InitCmd.Flags().StringVarP(&imageVariant, "image-variant", "", "", "The image variant to use for the Dapr runtime, for example: mariner")
+	InitCmd.Flags().StringVar(&initElevatedReport, standalone.ElevatedReportFlag, "", "The file the install relaunched through a UAC prompt writes its report to")
+	InitCmd.Flags().MarkHidden(standalone.ElevatedReportFlag)
 	InitCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	InitCmd.Flags().BoolVar(&initRender, "render", false, "Print the manifests of a Kubernetes install instead of deploying them, for another tool to apply")
+	InitCmd.Flags().StringVar(&initValuesFile, "values", "", "A YAML file of values of the Kubernetes charts, e.g. for the replica counts and the resource requests, which --set overrides")
 	InitCmd.Flags().StringArrayVar(&values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	InitCmd.Flags().String("image-registry", "", "Custom/private docker image repository URL")
 	InitCmd.Flags().StringVarP(&containerRuntime, "container-runtime", "", defaultContainerRuntime, "The container runtime to use. Supported values are docker (default) and podman")
@@ -224,15 +598,3 @@ func init() {
 
 	RootCmd.AddCommand(InitCmd)
 }
-
-// getConfigurationValue returns the value for a given configuration key.
-// The value is retrieved from the following sources, in order:
-// Default value
-// Environment variable (respecting registered prefixes)
-// Command line flag
-// Value is returned as a string.
-func getConfigurationValue(n string, cmd *cobra.Command) string {
-	viper.BindEnv(n)
-	viper.BindPFlag(n, cmd.Flags().Lookup(n))
-	return viper.GetString(n)
-}
@@ -14,12 +14,17 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/pkg/standalone"
@@ -34,6 +39,8 @@ var (
 	invokeVerb      string
 	invokeDataFile  string
 	invokeSocket    string
+	invokeHeaders   []string
+	invokeRaw       bool
 )
 
 var InvokeCmd = &cobra.Command{
@@ -46,25 +53,25 @@ dapr invoke --app-id target --method sample --data '{"key":"value"}
 # Invoke a sample method on target app with GET Verb
 dapr invoke --app-id target --method sample --verb GET
 
+# Invoke a sample method on target app with a header, printing the JSON response as is
+dapr invoke --app-id target --method sample --verb GET --header "X-Tenant: acme" --raw
+
+# Invoke a sample method on target app with the payload read from stdin
+cat order.json | dapr invoke --app-id target --method sample --data -
+
 # Invoke a sample method on target app with GET Verb using Unix domain socket
 dapr invoke --unix-domain-socket --app-id target --method sample --verb GET
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		bytePayload := []byte{}
-		var err error
-		if invokeDataFile != "" && invokeData != "" {
-			print.FailureStatusEvent(os.Stderr, "Only one of --data and --data-file allowed in the same invoke command")
+		bytePayload, err := readPayload("invoke", invokeData, invokeDataFile)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
 			os.Exit(1)
 		}
-
-		if invokeDataFile != "" {
-			bytePayload, err = os.ReadFile(invokeDataFile)
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, "Error reading payload from '%s'. Error: %s", invokeDataFile, err)
-				os.Exit(1)
-			}
-		} else if invokeData != "" {
-			bytePayload = []byte(invokeData)
+		headers, err := parseHeaders(invokeHeaders)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
 		}
 		client := standalone.NewClient()
 
@@ -78,28 +85,70 @@ dapr invoke --unix-domain-socket --app-id target --method sample --verb GET
 			}
 		}
 
-		response, err := client.Invoke(invokeAppID, invokeAppMethod, bytePayload, invokeVerb, invokeSocket)
+		result, err := client.InvokeMethod(invokeAppID, invokeAppMethod, bytePayload, invokeVerb, standalone.InvokeOptions{
+			Socket:  invokeSocket,
+			Headers: headers,
+		})
 		if err != nil {
 			err = fmt.Errorf("error invoking app %s: %w", invokeAppID, err)
 			print.FailureStatusEvent(os.Stderr, err.Error())
 			os.Exit(1)
 		}
 
-		if response != "" {
+		if response := formatResponse(result.Body, invokeRaw); response != "" {
 			fmt.Println(response)
 		}
-		print.SuccessStatusEvent(os.Stdout, "App invoked successfully")
+		print.SuccessStatusEvent(os.Stdout, "App invoked successfully, status %s in %s", result.Status, result.Latency.Round(time.Millisecond))
 	},
 }
 
+// parseHeaders parses the headers given as "Name: value" or "Name=value".
+func parseHeaders(values []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, ":")
+		if !ok {
+			name, value, ok = strings.Cut(v, "=")
+		}
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected Name: value or Name=value", v)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// formatResponse returns body indented if it is JSON, unless raw is set.
+func formatResponse(body []byte, raw bool) string {
+	if !raw && json.Valid(body) {
+		var b bytes.Buffer
+		if err := json.Indent(&b, body, "", "  "); err == nil {
+			return b.String()
+		}
+	}
+	return string(body)
+}
+
 func init() {
 	InvokeCmd.Flags().StringVarP(&invokeAppID, "app-id", "a", "", "The application id to invoke")
 	InvokeCmd.Flags().StringVarP(&invokeAppMethod, "method", "m", "", "The method to invoke")
-	InvokeCmd.Flags().StringVarP(&invokeData, "data", "d", "", "The JSON serialized data string (optional)")
+	InvokeCmd.Flags().StringVarP(&invokeData, "data", "d", "", "The JSON serialized data string, or - to read it from stdin (optional)")
 	InvokeCmd.Flags().StringVarP(&invokeVerb, "verb", "v", defaultHTTPVerb, "The HTTP verb to use")
-	InvokeCmd.Flags().StringVarP(&invokeDataFile, "data-file", "f", "", "A file containing the JSON serialized data (optional)")
+	InvokeCmd.Flags().StringVarP(&invokeDataFile, "data-file", "f", "", "A file containing the JSON serialized data, or - to read it from stdin (optional)")
+	InvokeCmd.Flags().StringArrayVarP(&invokeHeaders, "header", "H", []string{}, "A header to send, as Name: value or Name=value. Can be given multiple times")
+	InvokeCmd.Flags().BoolVar(&invokeRaw, "raw", false, "Print the response as is, without indenting JSON")
 	InvokeCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	InvokeCmd.Flags().StringVarP(&invokeSocket, "unix-domain-socket", "u", "", "Path to a unix domain socket dir. If specified, Dapr API servers will use Unix Domain Sockets")
+	InvokeCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		switch name {
+		case "payload":
+			name = "data"
+		case "payload-file":
+			name = "data-file"
+		}
+		return pflag.NormalizedName(name)
+	})
 	InvokeCmd.MarkFlagRequired("app-id")
 	InvokeCmd.MarkFlagRequired("method")
 	RootCmd.AddCommand(InvokeCmd)
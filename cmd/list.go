@@ -16,6 +16,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
@@ -54,6 +56,84 @@ func outputList(list interface{}, length int) {
 	}
 }
 
+// outputWideList outputs the self-hosted instances with their endpoints, settings and health, without truncating
+// them. The sidecars are probed in parallel.
+func outputWideList(list []standalone.ListOutput) {
+	if len(list) == 0 {
+		fmt.Println("No Dapr instances found.")
+		return
+	}
+	table := print.NewTable(
+		print.TableColumn{Name: "App ID", Key: "appId"},
+		print.TableColumn{Name: "Dapr HTTP", Key: "httpEndpoint"},
+		print.TableColumn{Name: "Dapr gRPC", Key: "grpcEndpoint"},
+		print.TableColumn{Name: "App port", Key: "appPort"},
+		print.TableColumn{Name: "Resources", Key: "resourcesPaths"},
+		print.TableColumn{Name: "Config", Key: "configFile"},
+		print.TableColumn{Name: "Placement", Key: "placementHostAddress"},
+		print.TableColumn{Name: "Dapr logs", Key: "daprdLogPath"},
+		print.TableColumn{Name: "App logs", Key: "appLogPath"},
+		print.TableColumn{Name: "Restarts", Key: "restarts"},
+		print.TableColumn{Name: "Health", Key: "health"},
+		print.TableColumn{Name: "Components", Key: "components"},
+		print.TableColumn{Name: "Age", Key: "age"},
+	)
+	health := standalone.ProbeInstances(list, standalone.DefaultHealthProbeTimeout)
+	for i, instance := range list {
+		e := instance.Endpoints()
+		appPort := ""
+		if e.AppPort > 0 {
+			appPort = strconv.Itoa(e.AppPort)
+		}
+		restarts := strconv.Itoa(instance.Restarts)
+		if instance.Restarts > 0 {
+			restarts = fmt.Sprintf("%d (last exit code %d)", instance.Restarts, instance.LastExitCode)
+		}
+		table.AddRow(e.AppID, e.HTTPEndpoint, e.GRPCEndpoint, appPort, strings.Join(e.ResourcesPaths, ","), e.ConfigFile,
+			e.PlacementHostAddress, e.DaprdLogPath, e.AppLogPath, restarts, health[i].Status, componentsCount(health[i]), instance.Age)
+	}
+	if err := table.Render(os.Stdout, print.OutputWide); err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+}
+
+// componentsCount returns the number of components loaded by a probed sidecar, empty if it couldn't be queried.
+func componentsCount(health standalone.InstanceHealth) string {
+	if health.Components < 0 {
+		return ""
+	}
+	return strconv.Itoa(health.Components)
+}
+
+// outputStandaloneList outputs the self-hosted instances. In the table output, the instances started from the same
+// run template are listed together under its name.
+func outputStandaloneList(list []standalone.ListOutput) {
+	output := func(instances []standalone.ListOutput) { outputList(instances, len(instances)) }
+	if outputFormat == "wide" {
+		output = outputWideList
+	}
+	groups := standalone.GroupByRunTemplate(list)
+	if outputFormat == "json" || outputFormat == "yaml" || len(groups) == 0 || (len(groups) == 1 && groups[0].RunTemplatePath == "") {
+		output(list)
+		return
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		switch {
+		case group.RunTemplatePath == "":
+			fmt.Println("Started individually:")
+		case group.RunTemplateName != "":
+			fmt.Printf("Run template %s (%s):\n", group.RunTemplateName, group.RunTemplatePath)
+		default:
+			fmt.Printf("Run template %s:\n", group.RunTemplatePath)
+		}
+		output(group.Instances)
+	}
+}
+
 var ListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all Dapr instances. Supported platforms: Kubernetes and self-hosted",
@@ -61,6 +141,9 @@ var ListCmd = &cobra.Command{
 # List Dapr instances in self-hosted mode
 dapr list
 
+# List Dapr instances in self-hosted mode with their endpoints and settings
+dapr list -o wide
+
 # List Dapr instances in all namespaces in Kubernetes mode
 dapr list -k
 
@@ -71,7 +154,7 @@ dapr list -k --namespace default
 dapr list -k --all-namespaces
 `,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		if outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "table" {
+		if outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "table" && outputFormat != "wide" {
 			print.FailureStatusEvent(os.Stdout, "An invalid output format was specified.")
 			os.Exit(1)
 		}
@@ -93,13 +176,36 @@ dapr list -k --all-namespaces
 
 			outputList(list, len(list))
 		} else {
-			list, err := standalone.List()
+			// The stale run records are removed, and the orphans are only reported, as a best effort.
+			orphans := map[int]bool{}
+			cleanup, cleanupErr := standalone.Cleanup(standalone.CleanupOptions{DaprRuntimePath: daprRuntimePath})
+			if cleanupErr == nil {
+				for _, orphan := range cleanup.Orphans {
+					orphans[orphan.PID] = true
+				}
+			}
+			list, err := standalone.ListInstances(daprRuntimePath)
 			if err != nil {
 				print.FailureStatusEvent(os.Stderr, err.Error())
 				os.Exit(1)
 			}
 
-			outputList(list, len(list))
+			outputStandaloneList(list)
+			for _, instance := range list {
+				if orphans[instance.DaprdPID] {
+					print.WarningStatusEvent(os.Stdout, "The daprd process %d of app %q was left behind by a dapr run command which exited: stop it with dapr cleanup", instance.DaprdPID, instance.AppID)
+				} else if instance.Orphan {
+					print.WarningStatusEvent(os.Stdout, "The daprd process %d of app %q is not tracked by a running dapr run command, it may be an orphan: stop it with dapr stop --app-id %s", instance.DaprdPID, instance.AppID, instance.AppID)
+				}
+				if instance.AttachedCliPID > 0 {
+					print.InfoStatusEvent(os.Stdout, "The app %d of app %q is attached to its Dapr sidecar by the dapr run --attach command %d", instance.AppPID, instance.AppID, instance.AttachedCliPID)
+				}
+			}
+			if outputFormat != "json" && outputFormat != "yaml" {
+				if dashboard, _ := standalone.GetDashboardRecord(daprRuntimePath); dashboard != nil {
+					print.InfoStatusEvent(os.Stdout, "The dashboard is running at %s, with pid %d", dashboard.URL, dashboard.PID)
+				}
+			}
 		}
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
@@ -113,7 +219,7 @@ func init() {
 	ListCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "If true, list all Dapr pods in all namespaces")
 	ListCmd.Flags().BoolVarP(&kubernetesMode, "kubernetes", "k", false, "List all Dapr pods in a Kubernetes cluster")
 	ListCmd.Flags().StringVarP(&resourceNamespace, "namespace", "", "", "List define namespace pod in a Kubernetes cluster")
-	ListCmd.Flags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, or table (default)")
+	ListCmd.Flags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, table (default), or wide for the endpoints, settings and health of the self-hosted instances")
 	ListCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	RootCmd.AddCommand(ListCmd)
 }
@@ -14,12 +14,16 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
 )
 
 var (
@@ -27,35 +31,86 @@ var (
 	podName   string
 	namespace string
 	k8s       bool
+	logsOpts  standalone.LogsOptions
 )
 
 var LogsCmd = &cobra.Command{
-	Use:   "logs",
-	Short: "Get Dapr sidecar logs for an application. Supported platforms: Kubernetes",
+	Use:   "logs [app-id]",
+	Short: "Get Dapr sidecar logs for an application. Supported platforms: Kubernetes and self-hosted",
 	Example: `
 # Get logs of sample app from target pod in custom namespace
 dapr logs -k --app-id sample --pod-name target --namespace custom
+
+# Follow the Dapr and app logs of an app started with dapr run --detach, from the last 10 minutes
+dapr logs sample --follow --since 10m
+
+# Get the last 100 lines of the Dapr logs of an app started with dapr run --detach
+dapr logs sample --tail 100 --runtime-only
 `,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		err := kubernetes.Logs(logsAppID, podName, namespace)
+		if len(args) > 0 {
+			logsAppID = args[0]
+		}
+		if logsAppID == "" {
+			print.FailureStatusEvent(os.Stderr, "The app id is required")
+			os.Exit(1)
+		}
+		if k8s {
+			err := kubernetes.Logs(logsAppID, podName, namespace)
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			print.SuccessStatusEvent(os.Stdout, "Fetched logs")
+			return
+		}
+
+		if logsOpts.RuntimeOnly && logsOpts.AppOnly {
+			print.FailureStatusEvent(os.Stderr, "The --runtime-only and --app-only flags can't be used together")
+			os.Exit(1)
+		}
+		daprDir, err := standalone.GetDaprRuntimePath(daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Failed to get Dapr install directory: %v", err)
+			os.Exit(1)
+		}
+		sources, err := standalone.InstanceLogSources(daprDir, logsAppID)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		err = standalone.Logs(ctx, sources, logsOpts, func(line standalone.LogLine) {
+			if line.Source == standalone.LogSourceApp {
+				fmt.Println(print.Blue("== APP == ") + line.Text)
+			} else {
+				fmt.Println(print.Yellow("== DAPR == ") + line.Text)
+			}
+		})
 		if err != nil {
 			print.FailureStatusEvent(os.Stderr, err.Error())
 			os.Exit(1)
 		}
-		print.SuccessStatusEvent(os.Stdout, "Fetched logs")
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
-		kubernetes.CheckForCertExpiry()
+		if k8s {
+			kubernetes.CheckForCertExpiry()
+		}
 	},
 }
 
 func init() {
-	LogsCmd.Flags().BoolVarP(&k8s, "kubernetes", "k", true, "Get logs from a Kubernetes cluster")
+	LogsCmd.Flags().BoolVarP(&k8s, "kubernetes", "k", false, "Get logs from a Kubernetes cluster")
 	LogsCmd.Flags().StringVarP(&logsAppID, "app-id", "a", "", "The application id for which logs are needed")
 	LogsCmd.Flags().StringVarP(&podName, "pod-name", "p", "", "The name of the pod in Kubernetes, in case your application has multiple pods (optional)")
 	LogsCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "The Kubernetes namespace in which your application is deployed")
+	LogsCmd.Flags().BoolVarP(&logsOpts.Follow, "follow", "f", false, "Keep printing the logs as they are written, in self-hosted mode")
+	LogsCmd.Flags().IntVar(&logsOpts.Tail, "tail", -1, "The number of lines to print, from the end of the logs. All of them by default, in self-hosted mode")
+	LogsCmd.Flags().DurationVar(&logsOpts.Since, "since", 0, "Only print the logs written in this duration, like 10m, in self-hosted mode")
+	LogsCmd.Flags().BoolVar(&logsOpts.RuntimeOnly, "runtime-only", false, "Only print the Dapr logs, in self-hosted mode")
+	LogsCmd.Flags().BoolVar(&logsOpts.AppOnly, "app-only", false, "Only print the app logs, in self-hosted mode")
 	LogsCmd.Flags().BoolP("help", "h", false, "Print this help message")
-	LogsCmd.MarkFlagRequired("app-id")
-	LogsCmd.MarkFlagRequired("kubernetes")
 	RootCmd.AddCommand(LogsCmd)
 }
@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+// installContainerRuntime returns the container runtime a self-hosted command uses: the value of its
+// --container-runtime flag or of DAPR_CONTAINER_RUNTIME if either is set, or else the one init recorded in the
+// install manifest, or else the one of the CLI config file or the default.
+func installContainerRuntime(cmd *cobra.Command) string {
+	setting, err := standalone.ResolveSetting(standalone.SettingContainerRuntime, flagLookup(cmd), daprRuntimePath)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if setting.Explicit() {
+		return setting.Value
+	}
+	if manifest, err := standalone.LoadInstallManifest(daprRuntimePath); err == nil && manifest != nil && manifest.ContainerRuntime != "" {
+		return manifest.ContainerRuntime
+	}
+	return setting.Value
+}
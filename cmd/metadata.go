@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var metadataOutputFormat string
+
+var MetadataCmd = &cobra.Command{
+	Use:   "metadata <app-id>",
+	Short: "Show the endpoints, loaded components, actors and extended metadata of a running Dapr instance. Supported platforms: Self-hosted",
+	Example: `
+# Show the endpoints and the metadata of the sidecar of the instance of app id myapp
+dapr metadata myapp
+
+# Get the HTTP endpoint of the sidecar of myapp in a script
+dapr metadata myapp -o json | jq -r .httpEndpoint
+
+# Check that the statestore component is loaded by the sidecar of myapp
+dapr metadata myapp -o json | jq '.components[] | select(.name == "statestore")'
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(metadataOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		apps, err := standalone.ListInstances(daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		instance, ok := findInstance(apps, args[0])
+		if !ok {
+			print.FailureStatusEvent(os.Stderr, "No running Dapr instance found with app id %q", args[0])
+			os.Exit(1)
+		}
+		m, err := standalone.GetInstanceMetadata(instance, standalone.DefaultHealthProbeTimeout)
+		switch {
+		case errors.Is(err, standalone.ErrSidecarNotRunning):
+			print.FailureStatusEvent(os.Stderr, "The sidecar of app id %s is not running: %s", args[0], err)
+			os.Exit(1)
+		case errors.Is(err, standalone.ErrMetadataNotSupported):
+			print.FailureStatusEvent(os.Stderr, "The sidecar of app id %s can't report its metadata: %s", args[0], err)
+			print.InfoStatusEvent(os.Stderr, "Run the app with runtime %s or later, installed for instance with: dapr init --runtime-version %s", standalone.MetadataAPIMinRuntimeVersion, standalone.MetadataAPIMinRuntimeVersion)
+			os.Exit(1)
+		case err != nil:
+			print.FailureStatusEvent(os.Stderr, "Error getting the metadata of app id %s: %s", args[0], err)
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), m); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		if err = outputInstanceMetadata(m); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// outputInstanceMetadata outputs the endpoints of an instance, followed by the tables of the components and the
+// actors loaded by its sidecar and its extended metadata.
+func outputInstanceMetadata(m standalone.InstanceMetadata) error {
+	for _, line := range m.Banner() {
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	if len(m.Components) == 0 {
+		fmt.Println("No components loaded.")
+	} else {
+		table := print.NewTable(
+			print.TableColumn{Name: "Component", Key: "name"},
+			print.TableColumn{Name: "Type", Key: "type"},
+			print.TableColumn{Name: "Version", Key: "version"},
+			print.TableColumn{Name: "Capabilities", Key: "capabilities", Truncate: true},
+		)
+		for _, c := range m.Components {
+			table.AddRow(c.Name, c.Type, c.Version, strings.Join(c.Capabilities, ","))
+		}
+		if err := table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			return err
+		}
+	}
+
+	if len(m.Actors) > 0 {
+		fmt.Println()
+		table := print.NewTable(
+			print.TableColumn{Name: "Actor type", Key: "type"},
+			print.TableColumn{Name: "Active", Key: "count"},
+		)
+		for _, a := range m.Actors {
+			table.AddRow(a.Type, strconv.Itoa(a.Count))
+		}
+		if err := table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			return err
+		}
+	}
+
+	if len(m.Extended) > 0 {
+		fmt.Println()
+		table := print.NewTable(
+			print.TableColumn{Name: "Extended", Key: "key"},
+			print.TableColumn{Name: "Value", Key: "value", Truncate: true},
+		)
+		keys := make([]string, 0, len(m.Extended))
+		for k := range m.Extended {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			table.AddRow(k, m.Extended[k])
+		}
+		if err := table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	MetadataCmd.Flags().StringVarP(&metadataOutputFormat, "output", "o", "", "The output format of the metadata. Valid values are: json")
+	MetadataCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(MetadataCmd)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	migrateOutputFormat     string
+	migrateContainerRuntime string
+	migrateDryRun           bool
+	migrateWaitForLock      bool
+)
+
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Adopt the local environment installed by an older CLI, without an install manifest. Supported platforms: Self-hosted",
+	Long: `Adopt the local environment installed by an older CLI, which recorded no install manifest.
+
+The runtime binaries installed in /usr/local/bin, or C:\dapr on Windows, are copied into the bin directory of the
+install dir, and the unlabeled containers of placement, Redis and Zipkin published on their known ports are renamed
+to the names of their services. The manifest recording them is then written, so that the other commands find the
+install. Nothing is changed if adopting the install isn't safe, and a clean reinstall is recommended instead.
+
+init and status only check what the migration would change, with a notice to run it. Running it again changes nothing.
+`,
+	Example: `
+# Adopt the environment installed by an older CLI
+dapr migrate
+
+# List what the migration would change, without changing it
+dapr migrate --dry-run
+
+# Get what the migration changed in JSON format
+dapr migrate -o json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(migrateOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		migrateContainerRuntime = installContainerRuntime(cmd)
+		if !utils.IsValidContainerRuntime(migrateContainerRuntime) {
+			print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+			os.Exit(1)
+		}
+		report, err := standalone.MigrateLegacyInstall(standalone.MigrateOptions{
+			DaprRuntimePath:  daprRuntimePath,
+			ContainerRuntime: migrateContainerRuntime,
+			DryRun:           migrateDryRun,
+			WaitForLock:      migrateWaitForLock,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		} else {
+			printMigrationReport(os.Stdout, report)
+		}
+		if report.Recommendation != "" {
+			os.Exit(1)
+		}
+		switch {
+		case !report.Legacy:
+			print.SuccessStatusEvent(os.Stdout, "Nothing to migrate in %s", report.InstallDir)
+		case migrateDryRun:
+			print.SuccessStatusEvent(os.Stdout, "Nothing was changed, as --dry-run is set")
+		default:
+			print.SuccessStatusEvent(os.Stdout, "The install in %s is adopted", report.InstallDir)
+		}
+	},
+}
+
+// printMigrationReport prints the changes, notes and recommendation of a migration to w.
+func printMigrationReport(w io.Writer, report *standalone.MigrationReport) {
+	for _, c := range report.Changes {
+		print.InfoStatusEvent(w, "%s %s %s", c.Action, c.Name, c.Detail)
+	}
+	for _, n := range report.Notes {
+		print.InfoStatusEvent(w, "%s", n)
+	}
+	if report.Recommendation != "" {
+		print.WarningStatusEvent(w, "The install made by an older CLI in %s can't be adopted: %s", report.InstallDir, report.Recommendation)
+	}
+}
+
+// checkLegacyInstall checks for an install made by an older CLI for init and status, which leave it as it is, with a
+// notice to w of what `dapr migrate` would change, or if it recommends a reinstall. Failing to check doesn't fail the
+// command.
+func checkLegacyInstall(w io.Writer, containerRuntime string) {
+	report, err := standalone.MigrateLegacyInstall(standalone.MigrateOptions{
+		DaprRuntimePath:  daprRuntimePath,
+		ContainerRuntime: containerRuntime,
+		DryRun:           true,
+	})
+	if err != nil {
+		print.WarningStatusEvent(w, "Could not check for an install made by an older CLI: %s", err)
+		return
+	}
+	if len(report.Changes) == 0 && report.Recommendation == "" {
+		return
+	}
+	if len(report.Changes) > 0 {
+		print.InfoStatusEvent(w, "Found an install made by an older CLI in %s, run `dapr migrate` to adopt it with these changes:", report.InstallDir)
+	}
+	printMigrationReport(w, report)
+}
+
+func init() {
+	MigrateCmd.Flags().StringVarP(&migrateContainerRuntime, "container-runtime", "", "docker", "The container runtime of the containers to adopt. Supported values are docker (default) and podman")
+	MigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "List what the migration would change, without changing it")
+	MigrateCmd.Flags().BoolVar(&migrateWaitForLock, "wait-for-lock", false, "Wait for a concurrently running init, uninstall or upgrade to finish instead of failing")
+	MigrateCmd.Flags().StringVarP(&migrateOutputFormat, "output", "o", "", "The output format of the migration. Valid values are: json")
+	MigrateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(MigrateCmd)
+}
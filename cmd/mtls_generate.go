@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	mtlsGenerateRenew        bool
+	mtlsGenerateValidUntil   uint
+	mtlsGenerateOutputFormat string
+)
+
+var MTLSGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a root certificate and issuer credentials to run the self-hosted sidecars with mTLS. Supported platforms: Self-hosted",
+	Example: `
+# Generate the mTLS credentials in $HOME/.dapr/certs
+dapr mtls generate
+
+# Regenerate expiring credentials, valid for 90 days
+dapr mtls generate --renew --valid-until 90
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(mtlsGenerateOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if mtlsGenerateValidUntil == 0 {
+			print.FailureStatusEvent(os.Stderr, "--valid-until must be at least 1 day")
+			os.Exit(1)
+		}
+		creds, err := standalone.GenerateMTLSCredentials(daprRuntimePath, time.Duration(mtlsGenerateValidUntil)*24*time.Hour, mtlsGenerateRenew)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), creds); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		printMTLSCredentials(creds)
+	},
+}
+
+// printMTLSCredentials prints where the generated mTLS credentials are, and how to run the sidecars with them.
+func printMTLSCredentials(creds *standalone.MTLSCredentials) {
+	print.SuccessStatusEvent(os.Stdout, "mTLS credentials written to %s, they expire on %s", creds.Dir, creds.Expiry.Format(time.RFC1123))
+	print.InfoStatusEvent(os.Stdout, "Run sentry with `--issuer-credentials %s`, then the apps with `dapr run --config %s` to enable mTLS between their sidecars", creds.Dir, creds.ConfigFile)
+}
+
+// generateInitMTLSCredentials generates the mTLS credentials of init --with-mtls, keeping the existing ones.
+func generateInitMTLSCredentials(report *standalone.InitReport) {
+	creds, err := standalone.GenerateMTLSCredentials(daprRuntimePath, standalone.DefaultMTLSValidity, false)
+	if errors.Is(err, standalone.ErrMTLSCredentialsExist) {
+		print.InfoStatusEvent(os.Stdout, "Keeping the existing mTLS credentials in %s, they expire on %s", creds.Dir, creds.Expiry.Format(time.RFC1123))
+		report.MTLS = creds
+		return
+	}
+	if err != nil {
+		report.Error = err.Error()
+		return
+	}
+	report.MTLS = creds
+	if print.GetOutputFormat() != print.OutputJSON {
+		printMTLSCredentials(creds)
+	}
+}
+
+func init() {
+	MTLSGenerateCmd.Flags().BoolVar(&mtlsGenerateRenew, "renew", false, "Regenerate the credentials if they exist, for instance when they expire soon")
+	MTLSGenerateCmd.Flags().UintVarP(&mtlsGenerateValidUntil, "valid-until", "", uint(standalone.DefaultMTLSValidity/(24*time.Hour)), "The number of days the certificates are valid for")
+	MTLSGenerateCmd.Flags().StringVarP(&mtlsGenerateOutputFormat, "output", "o", "", "The output format. Valid values are: json for the paths and the expiry of the credentials")
+	MTLSGenerateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	MTLSCmd.AddCommand(MTLSGenerateCmd)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var (
+	newTemplate     string
+	newAppID        string
+	newAppPort      int
+	newDaprHTTPPort int
+	newForce        bool
+)
+
+var NewCmd = &cobra.Command{
+	Use:   "new <dir>",
+	Short: "Scaffold a new project with an app, its components and a run manifest. Supported platforms: Self-hosted",
+	Long: `Scaffold a new project in a directory: a minimal HTTP app saving orders to the state store, in the language
+of the template, a components directory with the state store and pub/sub components of the Redis container of
+dapr init, and a dapr.yaml run manifest to run it with dapr run -f. The templates are embedded in the CLI.
+The templates are: ` + strings.Join(standalone.ProjectTemplates(), ", "),
+	Example: `
+# Scaffold a Go app in the orders directory, and run it
+dapr new --template go-http orders
+cd orders && dapr run -f .
+
+# Scaffold a Python app with the ID checkout, listening on port 5000
+dapr new --template python-http --app-id checkout --app-port 5000 ./checkout
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := standalone.NewProject(standalone.NewProjectOptions{
+			Template:        newTemplate,
+			Dir:             args[0],
+			AppID:           newAppID,
+			AppPort:         newAppPort,
+			DaprHTTPPort:    newDaprHTTPPort,
+			Force:           newForce,
+			DaprRuntimePath: daprRuntimePath,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		for _, f := range files {
+			print.InfoStatusEvent(os.Stdout, "Created %s", filepath.Join(args[0], f))
+		}
+		print.SuccessStatusEvent(os.Stdout, "Project scaffolded in %s, run it with `cd %s && dapr run -f .`", args[0], args[0])
+	},
+}
+
+func init() {
+	NewCmd.Flags().StringVarP(&newTemplate, "template", "t", "", "The template of the app. Supported values are: "+strings.Join(standalone.ProjectTemplates(), ", "))
+	NewCmd.Flags().StringVarP(&newAppID, "app-id", "a", "", "The ID of the app. Defaults to the name of the directory")
+	NewCmd.Flags().IntVarP(&newAppPort, "app-port", "p", 3000, "The port the app listens on")
+	NewCmd.Flags().IntVarP(&newDaprHTTPPort, "dapr-http-port", "H", 3500, "The HTTP port of the sidecar")
+	NewCmd.Flags().BoolVar(&newForce, "force", false, "Scaffold the project in a directory which isn't empty, overwriting the files of the project")
+	NewCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	NewCmd.MarkFlagRequired("template")
+	RootCmd.AddCommand(NewCmd)
+}
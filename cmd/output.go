@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+// setOutputFormat validates the value of the --output flag of a command supporting the given machine readable
+// formats, and applies it to the status output so that stdout only holds the results of the command.
+func setOutputFormat(format string, supported ...print.OutputFormat) error {
+	f := print.OutputFormat(strings.TrimSpace(format))
+	if f == print.OutputText {
+		return nil
+	}
+	for _, s := range supported {
+		if f == s {
+			print.SetOutputFormat(f)
+			return nil
+		}
+	}
+	valid := make([]string, 0, len(supported))
+	for _, s := range supported {
+		valid = append(valid, string(s))
+	}
+	return fmt.Errorf("invalid value for --output: %s. Valid values are: %s", format, strings.Join(valid, ", "))
+}
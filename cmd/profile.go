@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var profileListOutputFormat string
+
+var ProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage the profiles of the self-hosted environment. Supported platforms: Self-hosted",
+	Long: `Manage the profiles of the self-hosted environment.
+
+Each profile has its own install dir, with its install manifest, binaries, components, CLI config file and run
+records, and its own containers, suffixed with its name. The default profile is the install dir itself, the other
+ones are in its profiles directory. The commands use the profile of the --profile flag, or of the DAPR_PROFILE
+environment variable, or of profile in the CLI config file of the user config dir, or else the default profile.
+`,
+}
+
+var ProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profiles",
+	Example: `
+# List the profiles
+dapr profile list
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(profileListOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		profiles, err := standalone.ListProfiles(daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), profiles); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		table := print.NewTable(
+			print.TableColumn{Name: "Name", Key: "name"},
+			print.TableColumn{Name: "Active", Key: "active"},
+			print.TableColumn{Name: "Runtime", Key: "runtimeVersion"},
+			print.TableColumn{Name: "Directory", Key: "dir"},
+		)
+		for _, p := range profiles {
+			active := ""
+			if p.Active {
+				active = "*"
+			}
+			runtimeVersion := p.RuntimeVersion
+			if runtimeVersion == "" {
+				runtimeVersion = "not installed"
+			}
+			table.AddRow(p.Name, active, runtimeVersion, p.Dir)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var ProfileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a profile, to install an environment in with dapr init --profile",
+	Example: `
+# Create the clientA profile and install the runtime 1.11.0 in it
+dapr profile create clientA
+dapr init --profile clientA --runtime-version 1.11.0
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := standalone.CreateProfile(daprRuntimePath, args[0])
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Created profile %s in %s, run `dapr init --profile %s` to install it", p.Name, p.Dir, p.Name)
+	},
+}
+
+var ProfileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile which has no install",
+	Example: `
+# Remove the environment of the clientA profile, then the profile
+dapr uninstall --all --profile clientA
+dapr profile delete clientA
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := standalone.DeleteProfile(daprRuntimePath, args[0]); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Deleted profile %s", args[0])
+	},
+}
+
+func init() {
+	ProfileListCmd.Flags().StringVarP(&profileListOutputFormat, "output", "o", "", "The output format. Valid values are: json")
+	ProfileListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ProfileCreateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ProfileDeleteCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ProfileCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ProfileCmd.AddCommand(ProfileListCmd, ProfileCreateCmd, ProfileDeleteCmd)
+	RootCmd.AddCommand(ProfileCmd)
+}
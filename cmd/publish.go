@@ -16,10 +16,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/pkg/standalone"
@@ -33,6 +35,14 @@ var (
 	publishPayloadFile string
 	publishSocket      string
 	publishMetadata    string
+	publishContentType string
+	publishPort        int
+
+	publishCloudEvent       bool
+	publishCloudEventType   string
+	publishCloudEventSource string
+	publishTraceID          string
+	publishDryRun           bool
 )
 
 var PublishCmd = &cobra.Command{
@@ -47,23 +57,53 @@ dapr publish --enable-domain-socket --publish-app-id myapp --pubsub target --top
 
 # Publish to sample topic in target pubsub via a publishing app without cloud event
 dapr publish --publish-app-id myapp --pubsub target --topic sample --data '{"key":"value"}' --metadata '{"rawPayload":"true","ttlInSeconds":"10"}'
+
+# Publish to sample topic in target pubsub wrapped in a CloudEvents envelope, with a traceparent
+dapr publish --publish-app-id myapp --pubsub target --topic sample --data '{"key":"value"}' --cloudevent --cloudevent-type com.example.order --trace-id 4bf92f3577b34da6a3ce929d0e0e4736
+
+# Print the CloudEvents envelope of the data without publishing it
+dapr publish --pubsub target --topic sample --data 'hello' --cloudevent --dry-run
+
+# Publish the events of a file as plain text through the sidecar listening on port 3500
+cat events.txt | dapr publish --port 3500 --pubsub target --topic sample --data - --content-type text/plain
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		bytePayload := []byte{}
-		var err error
-		if publishPayloadFile != "" && publishPayload != "" {
-			print.FailureStatusEvent(os.Stderr, "Only one of --data and --data-file allowed in the same publish command")
+		if publishAppID == "" && publishPort == 0 && !publishDryRun {
+			print.FailureStatusEvent(os.Stderr, "One of --publish-app-id and --port is required")
+			os.Exit(1)
+		}
+		if !publishCloudEvent && (publishCloudEventType != "" || publishCloudEventSource != "" || publishTraceID != "") {
+			print.FailureStatusEvent(os.Stderr, "--cloudevent-type, --cloudevent-source and --trace-id can only be used with --cloudevent")
+			os.Exit(1)
+		}
+		bytePayload, err := readPayload("publish", publishPayload, publishPayloadFile)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
 			os.Exit(1)
 		}
 
-		if publishPayloadFile != "" {
-			bytePayload, err = os.ReadFile(publishPayloadFile)
+		contentType := publishContentType
+		if publishCloudEvent {
+			event, err := standalone.NewCloudEvent(bytePayload, standalone.CloudEventOptions{
+				Source:      publishCloudEventSource,
+				Type:        publishCloudEventType,
+				ContentType: publishContentType,
+				TraceID:     publishTraceID,
+			})
 			if err != nil {
-				print.FailureStatusEvent(os.Stderr, "Error reading payload from '%s'. Error: %s", publishPayloadFile, err)
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			if bytePayload, err = json.MarshalIndent(event, "", "  "); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
 				os.Exit(1)
 			}
-		} else if publishPayload != "" {
-			bytePayload = []byte(publishPayload)
+			contentType = "application/cloudevents+json"
+		}
+
+		if publishDryRun {
+			fmt.Println(string(bytePayload))
+			return
 		}
 
 		client := standalone.NewClient()
@@ -86,26 +126,72 @@ dapr publish --publish-app-id myapp --pubsub target --topic sample --data '{"key
 			}
 		}
 
-		err = client.Publish(publishAppID, pubsubName, publishTopic, bytePayload, publishSocket, metadata)
+		result, err := client.PublishEvent(publishAppID, pubsubName, publishTopic, bytePayload, standalone.PublishOptions{
+			Socket:      publishSocket,
+			Metadata:    metadata,
+			HTTPPort:    publishPort,
+			ContentType: contentType,
+		})
 		if err != nil {
 			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error publishing topic %s: %s", publishTopic, err))
 			os.Exit(1)
 		}
 
-		print.SuccessStatusEvent(os.Stdout, "Event published successfully")
+		print.SuccessStatusEvent(os.Stdout, "Event published successfully, status code %d", result.StatusCode)
 	},
 }
 
+// readPayload returns the payload of command given either inline with data or as the file dataFile. The payload is
+// read from stdin if either is -.
+func readPayload(command, data, dataFile string) ([]byte, error) {
+	if dataFile != "" && data != "" {
+		return nil, fmt.Errorf("Only one of --data and --data-file allowed in the same %s command", command)
+	}
+
+	switch {
+	case data == "-" || dataFile == "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("error reading payload from stdin: %w", err)
+		}
+		return b, nil
+	case dataFile != "":
+		b, err := os.ReadFile(dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading payload from '%s': %w", dataFile, err)
+		}
+		return b, nil
+	}
+	return []byte(data), nil
+}
+
 func init() {
 	PublishCmd.Flags().StringVarP(&publishAppID, "publish-app-id", "i", "", "The ID of the publishing app")
 	PublishCmd.Flags().StringVarP(&pubsubName, "pubsub", "p", "", "The name of the pub/sub component")
 	PublishCmd.Flags().StringVarP(&publishTopic, "topic", "t", "", "The topic to be published to")
-	PublishCmd.Flags().StringVarP(&publishPayload, "data", "d", "", "The JSON serialized data string (optional)")
-	PublishCmd.Flags().StringVarP(&publishPayloadFile, "data-file", "f", "", "A file containing the JSON serialized data (optional)")
+	PublishCmd.Flags().StringVarP(&publishPayload, "data", "d", "", "The data string, or - to read it from stdin (optional)")
+	PublishCmd.Flags().StringVarP(&publishPayloadFile, "data-file", "f", "", "A file containing the data, or - to read it from stdin (optional)")
+	PublishCmd.Flags().StringVar(&publishContentType, "content-type", "", "The content type of the data. Detected from the data if not set: application/cloudevents+json, application/json or text/plain")
+	PublishCmd.Flags().BoolVar(&publishCloudEvent, "cloudevent", false, "Wrap the data in a CloudEvents envelope, with the content type of the data as datacontenttype")
+	PublishCmd.Flags().StringVar(&publishCloudEventType, "cloudevent-type", "", fmt.Sprintf("The type of the CloudEvents envelope (default %q)", standalone.DefaultCloudEventType))
+	PublishCmd.Flags().StringVar(&publishCloudEventSource, "cloudevent-source", "", fmt.Sprintf("The source of the CloudEvents envelope (default %q)", standalone.DefaultCloudEventSource))
+	PublishCmd.Flags().StringVar(&publishTraceID, "trace-id", "", "The W3C trace id, 32 hexadecimal characters, of the traceparent of the CloudEvents envelope")
+	PublishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "Print the data which would be published, with its CloudEvents envelope with --cloudevent, without publishing it")
+	PublishCmd.Flags().IntVar(&publishPort, "port", 0, "The HTTP port of the sidecar to publish through, instead of the one of the instance of --publish-app-id")
 	PublishCmd.Flags().StringVarP(&publishSocket, "unix-domain-socket", "u", "", "Path to a unix domain socket dir. If specified, Dapr API servers will use Unix Domain Sockets")
 	PublishCmd.Flags().StringVarP(&publishMetadata, "metadata", "m", "", "The JSON serialized publish metadata (optional)")
 	PublishCmd.Flags().BoolP("help", "h", false, "Print this help message")
-	PublishCmd.MarkFlagRequired("publish-app-id")
+	PublishCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		switch name {
+		case "app-id":
+			name = "publish-app-id"
+		case "payload":
+			name = "data"
+		case "payload-file":
+			name = "data-file"
+		}
+		return pflag.NormalizedName(name)
+	})
 	PublishCmd.MarkFlagRequired("topic")
 	PublishCmd.MarkFlagRequired("pubsub")
 	RootCmd.AddCommand(PublishCmd)
@@ -15,16 +15,20 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/dapr/cli/pkg/metadata"
@@ -48,8 +52,10 @@ var (
 	enableProfiling    bool
 	logLevel           string
 	protocol           string
-	componentsPath     string
+	componentsPaths    []string
+	showComponents     bool
 	resourcesPaths     []string
+	skipValidation     bool
 	appSSL             bool
 	metricsPort        int
 	maxRequestBodySize int
@@ -64,6 +70,34 @@ var (
 	apiListenAddresses string
 	runFilePath        string
 	appChannelAddress  string
+	detach             bool
+	replace            bool
+	exitOnFirstFailure bool
+	appEnv             []string
+	appEnvFile         string
+	appDir             string
+	watch              bool
+	watchInclude       []string
+	watchIgnore        []string
+	restartSidecar     bool
+	waitForApp         bool
+	waitForAppTimeout  time.Duration
+	appHealthEndpoint  string
+	appCAFile          string
+	runtimeArgs        []string
+	showCommand        bool
+	restartPolicyFlag  string
+	allowDuplicateID   bool
+	attachAppID        string
+	// startSidecarAfterApp starts the sidecar once the app is ready with --wait-for-app.
+	startSidecarAfterApp bool
+	// The processes of an instance stop each other when they exit, unless disabled.
+	killAppOnSidecarExit bool
+	killSidecarOnAppExit bool
+	// runFileStopping is set once the apps of a run file are being stopped, when their exit is expected.
+	runFileStopping atomic.Bool
+	// detachedChild is set by run --detach for the background process it starts.
+	detachedChild bool
 )
 
 const (
@@ -96,10 +130,20 @@ dapr run --app-id myapp --app-port 3000 --app-protocol grpc -- go run main.go
 # Run a gRPC application written in Go (listening on port 3000) with a different app channel address
 dapr run --app-id myapp --app-port 3000 --app-channel-address localhost --app-protocol grpc -- go run main.go
 
+# Run an application serving https with a self-signed certificate, waiting for it with its certificate verified
+dapr run --app-id myapp --app-port 3000 --app-protocol https --app-ca-file ./ca.pem --wait-for-app -- node app.js
+
 
 # Run sidecar only specifying dapr runtime installation directory
 dapr run --app-id myapp --runtime-path /usr/local/dapr
 
+# Run with the components of the project overlaid on the global components
+dapr run --app-id myapp --components-path ./components -- python myapp.py
+
+# Run an application in the background, and stop it later
+dapr run --app-id myapp --detach -- python myapp.py
+dapr stop --app-id myapp
+
 # Run multiple apps by providing path of a run config file
 dapr run --run-file dapr.yaml
 
@@ -112,6 +156,10 @@ dapr run --run-file /path/to/directory
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(runFilePath) > 0 {
+			if detach || watch || attachAppID != "" || cmd.Flags().Changed("restart") {
+				print.FailureStatusEvent(os.Stderr, "The --detach, --watch, --attach and --restart flags are not supported with a run file")
+				os.Exit(1)
+			}
 			if runtime.GOOS == string(windowsOsType) {
 				print.FailureStatusEvent(os.Stderr, "The run command with run file is not supported on Windows")
 				os.Exit(1)
@@ -125,6 +173,10 @@ dapr run --run-file /path/to/directory
 			return
 		}
 		if len(args) == 0 {
+			if watch || waitForApp || attachAppID != "" {
+				print.FailureStatusEvent(os.Stderr, "The --watch, --wait-for-app and --attach flags require an application command")
+				os.Exit(1)
+			}
 			fmt.Println(print.WhiteBold("WARNING: no application command found."))
 		}
 
@@ -134,14 +186,146 @@ dapr run --run-file /path/to/directory
 			os.Exit(1)
 		}
 
-		// Fallback to default config file if not specified.
+		if attachAppID != "" {
+			if detach || watch || replace || cmd.Flags().Changed("restart") {
+				print.FailureStatusEvent(os.Stderr, "The --detach, --watch, --replace and --restart flags are not supported with --attach")
+				os.Exit(1)
+			}
+			os.Exit(runAttached(daprDirPath, attachAppID, args))
+		}
+
+		if waitForApp && appPort <= 0 && appHealthEndpoint == "" {
+			print.FailureStatusEvent(os.Stderr, "The --wait-for-app flag requires --app-port or --app-health-endpoint")
+			os.Exit(1)
+		}
+		appTLS := standalone.IsTLSAppProtocol(standalone.EffectiveAppProtocol(protocol, appSSL))
+		if appCAFile != "" {
+			if !appTLS {
+				print.FailureStatusEvent(os.Stderr, "The --app-ca-file flag requires --app-protocol https or grpcs")
+				os.Exit(1)
+			}
+			if err := standalone.ValidateAppCAFile(appCAFile); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		}
+		restartPolicy, err := standalone.ParseRestartPolicy(restartPolicyFlag)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if appID == "" {
+			projectDir := appDir
+			if projectDir == "" {
+				projectDir = "."
+			}
+			// The running instance of the project is stopped with --replace, so its id isn't suffixed.
+			var resolvedAppID standalone.ResolvedAppID
+			if replace {
+				resolvedAppID, err = standalone.ProjectAppID(projectDir)
+			} else {
+				resolvedAppID, err = standalone.DeriveAppID(projectDir, daprRuntimePath, allowDuplicateID)
+			}
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			if resolvedAppID.ID != "" {
+				appID = resolvedAppID.ID
+				print.WarningStatusEvent(os.Stdout, "No app id given with --app-id, using %s", resolvedAppID)
+			}
+		}
+		if replace && appID != "" {
+			replaceInstance(daprDirPath, appID)
+		}
+		if detach && !detachedChild {
+			if err = startDetached(daprDirPath); err != nil {
+				print.FailureStatusEvent(os.Stderr, "Failed to start in the background: %s", err)
+				os.Exit(1)
+			}
+			return
+		}
+		// The detached instances write the output of daprd and of the app to log files.
+		var daprdOutput, appOutput io.Writer = os.Stdout, nil
+		var daprdLogPath, appLogPath string
+		if detachedChild {
+			logsDir := standalone.GetRunLogsPath(daprDirPath, appID)
+			daprdLogPath, appLogPath = filepath.Join(logsDir, "daprd.log"), filepath.Join(logsDir, "app.log")
+			daprdLog, logErr := print.OpenRotatingFile(daprdLogPath, standalone.DefaultLogFileMaxSize, standalone.DefaultLogFileBackups)
+			if logErr != nil {
+				print.FailureStatusEvent(os.Stderr, logErr.Error())
+				os.Exit(1)
+			}
+			defer daprdLog.Close()
+			appLog, logErr := print.OpenRotatingFile(appLogPath, standalone.DefaultLogFileMaxSize, standalone.DefaultLogFileBackups)
+			if logErr != nil {
+				print.FailureStatusEvent(os.Stderr, logErr.Error())
+				os.Exit(1)
+			}
+			defer appLog.Close()
+			// The lines are timestamped for dapr logs to interleave them.
+			daprdOutput, appOutput = standalone.NewTimestampWriter(daprdLog), standalone.NewTimestampWriter(appLog)
+		}
+		// The recent output of the app is reported if it isn't ready in time.
+		recentAppOutput := standalone.NewRecentLines(20)
+		printAppLine := func(line string) {
+			recentAppOutput.Add(line)
+			if appOutput != nil {
+				fmt.Fprintln(appOutput, line)
+			} else {
+				fmt.Println(print.Blue(fmt.Sprintf("== APP == %s", line)))
+			}
+		}
+
+		env, envConflicts, err := standalone.ResolveAppEnv(appEnvFile, appEnv)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if appDir != "" {
+			if appDir, err = filepath.Abs(appDir); err == nil {
+				err = validateAppDir(appDir)
+			}
+			if err != nil {
+				print.FailureStatusEvent(os.Stderr, "Invalid app directory: %s", err)
+				os.Exit(1)
+			}
+		}
+
+		// Fallback to default config file if not specified and generated by init.
 		if configFile == "" {
-			configFile = standalone.GetDaprConfigPath(daprDirPath)
+			if defaultConfigFile := standalone.GetDaprConfigPath(daprDirPath); utils.ValidateFilePath(defaultConfigFile) == nil {
+				configFile = defaultConfigFile
+			}
 		}
 
-		// Fallback to default components directory if not specified.
-		if componentsPath == "" {
-			componentsPath = standalone.GetDaprComponentsPath(daprDirPath)
+		// The global components directory is overlaid with the directories of the --components-path flags.
+		resolvedComponentsPath, err := standalone.ResolveComponentsPath("", daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Failed to get components directory: %v", err)
+			os.Exit(1)
+		}
+		componentsPath := resolvedComponentsPath.Path
+		cleanupComponents := func() {}
+		if len(resourcesPaths) == 0 {
+			print.DebugStatusEvent(os.Stdout, "using components directory %s", resolvedComponentsPath)
+			layers := componentLayers(resolvedComponentsPath.Path, componentsPaths)
+			if len(layers) > 1 || showComponents {
+				merged, mergeErr := mergeComponentLayers(layers)
+				if mergeErr != nil {
+					print.FailureStatusEvent(os.Stderr, mergeErr.Error())
+					os.Exit(1)
+				}
+				componentsPath = merged.Dir
+				cleanupComponents = func() {
+					if cleanupErr := merged.Cleanup(); cleanupErr != nil {
+						print.WarningStatusEvent(os.Stdout, "Failed to remove the merged components directory %s: %s", merged.Dir, cleanupErr)
+					}
+				}
+			} else if len(layers) == 1 {
+				componentsPath = layers[0]
+			}
 		}
 
 		if unixDomainSocket != "" {
@@ -159,13 +343,14 @@ dapr run --run-file /path/to/directory
 
 		sharedRunConfig := &standalone.SharedRunConfig{
 			ConfigFile:         configFile,
-			EnableProfiling:    enableProfiling,
+			EnableProfiling:    enableProfiling || cmd.Flags().Changed("profile-port"),
 			LogLevel:           logLevel,
 			MaxConcurrency:     maxConcurrency,
 			AppProtocol:        protocol,
 			PlacementHostAddr:  viper.GetString("placement-host-address"),
 			ComponentsPath:     componentsPath,
 			ResourcesPaths:     resourcesPaths,
+			SkipValidation:     skipValidation,
 			AppSSL:             appSSL,
 			MaxRequestBodySize: maxRequestBodySize,
 			HTTPReadBufferSize: readBufferSize,
@@ -177,8 +362,10 @@ dapr run --run-file /path/to/directory
 			EnableAPILogging:   enableAPILogging,
 			APIListenAddresses: apiListenAddresses,
 			DaprdInstallPath:   daprRuntimePath,
+			Env:                env,
+			RuntimeArgs:        runtimeArgs,
 		}
-		output, err := runExec.NewOutput(&standalone.RunConfig{
+		runConfig := &standalone.RunConfig{
 			AppID:             appID,
 			AppChannelAddress: appChannelAddress,
 			AppPort:           appPort,
@@ -190,11 +377,23 @@ dapr run --run-file /path/to/directory
 			UnixDomainSocket:  unixDomainSocket,
 			InternalGRPCPort:  internalGRPCPort,
 			SharedRunConfig:   *sharedRunConfig,
-		})
+		}
+		output, err := runExec.NewOutput(runConfig)
 		if err != nil {
 			print.FailureStatusEvent(os.Stderr, err.Error())
+			cleanupComponents()
 			os.Exit(1)
 		}
+		for _, c := range append(envConflicts, runConfig.EnvConflicts()...) {
+			print.WarningStatusEvent(os.Stdout, c.String())
+		}
+		if showCommand {
+			print.InfoStatusEvent(os.Stdout, "Runtime command: %s", standalone.RedactedCommandLine(output.DaprCMD))
+		}
+		// Only the app runs in the app directory: the paths of the flags are relative to the current directory.
+		if output.AppCMD != nil && appDir != "" {
+			output.AppCMD.Dir = appDir
+		}
 		// TODO: In future release replace following logic with the refactored functions seen below.
 
 		sigCh := make(chan os.Signal, 1)
@@ -202,161 +401,266 @@ dapr run --run-file /path/to/directory
 
 		daprRunning := make(chan bool, 1)
 		appRunning := make(chan bool, 1)
+		// The processes which exited, so that the run stops once both did when they don't stop each other.
+		var daprExited, appExited atomic.Bool
+		// restarting is set while --watch restarts the processes, whose exit is expected then.
+		var restarting atomic.Bool
+		var daprDone, appDone chan struct{}
+		// failed receives the processes which failed, "daprd" or "app", to restart them with --restart.
+		failed := make(chan string, 2)
+		var restarts atomic.Int64
+		// stopping is set once the run stops, so that the processes exiting then aren't restarted.
+		var stopping atomic.Bool
+		// appFailure is the error of the app which exited on its own before the run stopped, rather than killed by it,
+		// whose exit code is the one of the command. It is written by the goroutine waiting for the app.
+		var appFailure atomic.Pointer[error]
+		// daprFailure is the error daprd exited with, written by the goroutine waiting for it.
+		var daprFailure atomic.Pointer[error]
+		daprdStderr := io.Writer(os.Stderr)
+		if detachedChild {
+			daprdStderr = daprdOutput
+		}
 
-		go func() {
-			var startInfo string
-			if unixDomainSocket != "" {
-				startInfo = fmt.Sprintf(
-					"Starting Dapr with id %s. HTTP Socket: %v. gRPC Socket: %v.",
-					output.AppID,
-					utils.GetSocket(unixDomainSocket, output.AppID, "http"),
-					utils.GetSocket(unixDomainSocket, output.AppID, "grpc"))
-			} else {
-				startInfo = fmt.Sprintf(
-					"Starting Dapr with id %s. HTTP Port: %v. gRPC Port: %v",
-					output.AppID,
-					output.DaprHTTPPort,
-					output.DaprGRPCPort)
-			}
-			print.InfoStatusEvent(os.Stdout, startInfo)
-
-			output.DaprCMD.Stdout = os.Stdout
-			output.DaprCMD.Stderr = os.Stderr
-
-			err = output.DaprCMD.Start()
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, err.Error())
-				os.Exit(1)
-			}
-
+		// waitDaprd waits for the daprd process to exit in the background, returning a channel closed once it did.
+		waitDaprd := func(cmd *exec.Cmd) chan struct{} {
+			done := make(chan struct{})
 			go func() {
-				daprdErr := output.DaprCMD.Wait()
+				defer close(done)
+				daprdErr := cmd.Wait()
+				if restarting.Load() {
+					return
+				}
 
 				if daprdErr != nil {
-					output.DaprErr = daprdErr
+					daprFailure.Store(&daprdErr)
 					print.FailureStatusEvent(os.Stderr, "The daprd process exited with error code: %s", daprdErr.Error())
 				} else {
 					print.SuccessStatusEvent(os.Stdout, "Exited Dapr successfully")
 				}
-				sigCh <- os.Interrupt
-			}()
-
-			if appPort <= 0 {
-				// If app does not listen to port, we can check for Dapr's sidecar health before starting the app.
-				// Otherwise, it creates a deadlock.
-				sidecarUp := true
-
-				if unixDomainSocket != "" {
-					httpSocket := utils.GetSocket(unixDomainSocket, output.AppID, "http")
-					print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on HTTP socket %v", httpSocket)
-					err = utils.IsDaprListeningOnSocket(httpSocket, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
-					if err != nil {
-						sidecarUp = false
-						print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on HTTP socket: %s", err.Error())
-					}
-
-					grpcSocket := utils.GetSocket(unixDomainSocket, output.AppID, "grpc")
-					print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on GRPC socket %v", grpcSocket)
-					err = utils.IsDaprListeningOnSocket(grpcSocket, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
-					if err != nil {
-						sidecarUp = false
-						print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on GRPC socket: %s", err.Error())
-					}
-
-				} else {
-					print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on HTTP port %v", output.DaprHTTPPort)
-					err = utils.IsDaprListeningOnPort(output.DaprHTTPPort, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
-					if err != nil {
-						sidecarUp = false
-						print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on HTTP port: %s", err.Error())
-					}
-
-					print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on GRPC port %v", output.DaprGRPCPort)
-					err = utils.IsDaprListeningOnPort(output.DaprGRPCPort, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
-					if err != nil {
-						sidecarUp = false
-						print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on GRPC port: %s", err.Error())
-					}
+				daprExited.Store(true)
+				setRunRecordExitReason(daprRuntimePath, output.AppID, exitReason("daprd", daprdErr))
+				if daprdErr != nil && !stopping.Load() && restartOnFailure("daprd", restartPolicy, int(restarts.Load())) {
+					failed <- "daprd"
+					return
 				}
-
-				if sidecarUp {
-					print.InfoStatusEvent(os.Stdout, "Dapr sidecar is up and running.")
-				} else {
-					print.WarningStatusEvent(os.Stdout, "Dapr sidecar might not be responding.")
+				if output.AppCMD != nil && !appExited.Load() && !killAppOnSidecarExit {
+					print.WarningStatusEvent(os.Stdout, "The app keeps running without its Dapr sidecar")
+					return
 				}
-			}
-
-			daprRunning <- true
-		}()
-
-		<-daprRunning
-
-		go func() {
-			if output.AppCMD == nil {
-				appRunning <- true
-				return
-			}
+				sigCh <- os.Interrupt
+			}()
+			return done
+		}
 
+		// startApp starts the app command of output, writing its output, and waits for it to exit in the background.
+		startApp := func() error {
 			stdErrPipe, pipeErr := output.AppCMD.StderrPipe()
 			if pipeErr != nil {
-				print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error creating stderr for App: %s", err.Error()))
-				appRunning <- false
-				return
+				return fmt.Errorf("error creating stderr for App: %w", pipeErr)
 			}
-
 			stdOutPipe, pipeErr := output.AppCMD.StdoutPipe()
 			if pipeErr != nil {
-				print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error creating stdout for App: %s", err.Error()))
-				appRunning <- false
-				return
+				return fmt.Errorf("error creating stdout for App: %w", pipeErr)
 			}
 
 			errScanner := bufio.NewScanner(stdErrPipe)
 			outScanner := bufio.NewScanner(stdOutPipe)
 			go func() {
 				for errScanner.Scan() {
-					fmt.Println(print.Blue(fmt.Sprintf("== APP == %s", errScanner.Text())))
+					printAppLine(errScanner.Text())
 				}
 			}()
 
 			go func() {
 				for outScanner.Scan() {
-					fmt.Println(print.Blue(fmt.Sprintf("== APP == %s", outScanner.Text())))
+					printAppLine(outScanner.Text())
 				}
 			}()
 
-			err = output.AppCMD.Start()
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, err.Error())
-				appRunning <- false
-				return
+			if startErr := standalone.StartAppProcess(output.AppCMD); startErr != nil {
+				return startErr
 			}
+			appExited.Store(false)
 
+			cmd, done := output.AppCMD, make(chan struct{})
+			appDone = done
 			go func() {
-				appErr := output.AppCMD.Wait()
+				defer close(done)
+				appErr := cmd.Wait()
+				if restarting.Load() {
+					return
+				}
 
 				if appErr != nil {
-					output.AppErr = appErr
+					if !stopping.Load() {
+						appFailure.Store(&appErr)
+					}
 					print.FailureStatusEvent(os.Stderr, "The App process exited with error code: %s", appErr.Error())
 				} else {
 					print.SuccessStatusEvent(os.Stdout, "Exited App successfully")
 				}
+				appExited.Store(true)
+				setRunRecordExitReason(daprRuntimePath, output.AppID, exitReason("app", appErr))
+				if appErr != nil && !daprExited.Load() && !stopping.Load() && restartOnFailure("app", restartPolicy, int(restarts.Load())) {
+					failed <- "app"
+					return
+				}
+				if !daprExited.Load() && watch {
+					print.InfoStatusEvent(os.Stdout, "Waiting for a change to restart the app")
+					return
+				}
+				if !daprExited.Load() && !killSidecarOnAppExit {
+					print.WarningStatusEvent(os.Stdout, "The Dapr sidecar keeps running without its app")
+					return
+				}
 				sigCh <- os.Interrupt
 			}()
+			return nil
+		}
+
+		startSidecar := func() {
+			go func() {
+				var startInfo string
+				if unixDomainSocket != "" {
+					startInfo = fmt.Sprintf(
+						"Starting Dapr with id %s. HTTP Socket: %v. gRPC Socket: %v.",
+						output.AppID,
+						utils.GetSocket(unixDomainSocket, output.AppID, "http"),
+						utils.GetSocket(unixDomainSocket, output.AppID, "grpc"))
+				} else {
+					startInfo = fmt.Sprintf(
+						"Starting Dapr with id %s. HTTP Port: %v. gRPC Port: %v",
+						output.AppID,
+						output.DaprHTTPPort,
+						output.DaprGRPCPort)
+				}
+				print.InfoStatusEvent(os.Stdout, startInfo)
+
+				output.DaprCMD.Stdout = daprdOutput
+				output.DaprCMD.Stderr = daprdStderr
+
+				err = output.DaprCMD.Start()
+				if err != nil {
+					print.FailureStatusEvent(os.Stderr, err.Error())
+					cleanupComponents()
+					os.Exit(1)
+				}
+				daprDone = waitDaprd(output.DaprCMD)
+
+				if appPort <= 0 {
+					// If app does not listen to port, we can check for Dapr's sidecar health before starting the app.
+					// Otherwise, it creates a deadlock.
+					sidecarUp := true
+
+					if unixDomainSocket != "" {
+						httpSocket := utils.GetSocket(unixDomainSocket, output.AppID, "http")
+						print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on HTTP socket %v", httpSocket)
+						err = utils.IsDaprListeningOnSocket(httpSocket, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+						if err != nil {
+							sidecarUp = false
+							print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on HTTP socket: %s", err.Error())
+						}
+
+						grpcSocket := utils.GetSocket(unixDomainSocket, output.AppID, "grpc")
+						print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on GRPC socket %v", grpcSocket)
+						err = utils.IsDaprListeningOnSocket(grpcSocket, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+						if err != nil {
+							sidecarUp = false
+							print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on GRPC socket: %s", err.Error())
+						}
+
+					} else {
+						print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on HTTP port %v", output.DaprHTTPPort)
+						err = utils.IsDaprListeningOnPort(output.DaprHTTPPort, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+						if err != nil {
+							sidecarUp = false
+							print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on HTTP port: %s", err.Error())
+						}
+
+						print.InfoStatusEvent(os.Stdout, "Checking if Dapr sidecar is listening on GRPC port %v", output.DaprGRPCPort)
+						err = utils.IsDaprListeningOnPort(output.DaprGRPCPort, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+						if err != nil {
+							sidecarUp = false
+							print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening on GRPC port: %s", err.Error())
+						}
+					}
+
+					if sidecarUp {
+						print.InfoStatusEvent(os.Stdout, "Dapr sidecar is up and running.")
+					} else {
+						print.WarningStatusEvent(os.Stdout, "Dapr sidecar might not be responding.")
+					}
+				}
+
+				daprRunning <- true
+			}()
 
-			appRunning <- true
-		}()
+			<-daprRunning
+		}
 
-		appRunStatus := <-appRunning
-		if !appRunStatus {
-			// Start App failed, try to stop Dapr and exit.
-			err = output.DaprCMD.Process.Kill()
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Start App failed, try to stop Dapr Error: %s", err))
-			} else {
-				print.SuccessStatusEvent(os.Stdout, "Start App failed, try to stop Dapr successfully")
+		launchApp := func() {
+			go func() {
+				if output.AppCMD == nil {
+					appRunning <- true
+					return
+				}
+
+				if startErr := startApp(); startErr != nil {
+					print.FailureStatusEvent(os.Stderr, startErr.Error())
+					appRunning <- false
+					return
+				}
+
+				appRunning <- true
+			}()
+
+			appRunStatus := <-appRunning
+			if !appRunStatus {
+				// Start App failed, try to stop Dapr and exit. Dapr isn't started yet with --start-sidecar-after-app.
+				if output.DaprCMD.Process != nil {
+					err = output.DaprCMD.Process.Kill()
+					if err != nil {
+						print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Start App failed, try to stop Dapr Error: %s", err))
+					} else {
+						print.SuccessStatusEvent(os.Stdout, "Start App failed, try to stop Dapr successfully")
+					}
+				}
+				cleanupComponents()
+				os.Exit(1)
+			}
+		}
+
+		readiness := standalone.AppReadiness{Port: appPort, HealthEndpoint: appHealthEndpoint, SSL: appTLS, CAFile: appCAFile}
+		waitForAppReady := func() {
+			print.InfoStatusEvent(os.Stdout, "Waiting for the app to be ready on %s", readiness)
+			if readyErr := standalone.WaitForApp(readiness, waitForAppTimeout, appDone); readyErr != nil {
+				print.FailureStatusEvent(os.Stderr, readyErr.Error())
+				if lines := recentAppOutput.Lines(); len(lines) > 0 {
+					print.FailureStatusEvent(os.Stderr, "Recent output of the app:\n%s", strings.Join(lines, "\n"))
+				}
+				if output.AppCMD.Process != nil {
+					standalone.KillAppProcess(output.AppCMD.Process)
+				}
+				if output.DaprCMD.Process != nil {
+					output.DaprCMD.Process.Kill()
+				}
+				cleanupComponents()
+				os.Exit(1)
+			}
+			print.SuccessStatusEvent(os.Stdout, "The app is ready on %s", readiness)
+		}
+
+		// With --start-sidecar-after-app, the sidecar doesn't probe the app before it is ready.
+		if waitForApp && startSidecarAfterApp {
+			launchApp()
+			waitForAppReady()
+			startSidecar()
+		} else {
+			startSidecar()
+			launchApp()
+			if waitForApp {
+				waitForAppReady()
 			}
-			os.Exit(1)
 		}
 
 		// Metadata API is only available if app has started listening to port, so wait for app to start before calling metadata API.
@@ -385,16 +689,174 @@ dapr run --run-file /path/to/directory
 		} else {
 			print.SuccessStatusEvent(os.Stdout, "You're up and running! Dapr logs will appear here.\n")
 		}
+		endpoints := runConfig.Endpoints()
+		if detachedChild {
+			endpoints.DaprdLogPath, endpoints.AppLogPath = daprdLogPath, appLogPath
+		}
+		printEndpoints(os.Stdout, endpoints)
+
+		record := standalone.RunRecord{
+			AppID:           output.AppID,
+			DaprdPID:        output.DaprCMD.Process.Pid,
+			CliPID:          os.Getpid(),
+			HTTPPort:        output.DaprHTTPPort,
+			GRPCPort:        output.DaprGRPCPort,
+			Started:         time.Now(),
+			Command:         strings.Join(args, " "),
+			DaprdCreateTime: standalone.ProcessCreateTime(output.DaprCMD.Process.Pid),
+			CliCreateTime:   standalone.ProcessCreateTime(os.Getpid()),
+		}
+		if appPort > 0 {
+			record.AppPort = appPort
+		}
+		if output.AppCMD != nil && output.AppCMD.Process != nil {
+			record.AppPID = output.AppCMD.Process.Pid
+		}
+		if detachedChild {
+			record.Detached = true
+			record.DaprdLogPath, record.AppLogPath = daprdLogPath, appLogPath
+		}
+		setRunRecordSettings(&record, runConfig)
+		writeRunRecord(daprRuntimePath, record)
+
+		// restartProcesses restarts the app if app is set, and daprd if sidecar is set, with the same config and ports.
+		// It returns false if a process couldn't be started again.
+		restartProcesses := func(app, sidecar bool) bool {
+			restarting.Store(true)
+			defer restarting.Store(false)
+
+			if app && output.AppCMD.Process != nil {
+				app := output.AppCMD.Process
+				stopForRestart(appDone, func() error { return standalone.TerminateAppProcess(app) }, func() error { return standalone.KillAppProcess(app) })
+			}
+			if sidecar {
+				daprd := output.DaprCMD.Process
+				stopForRestart(daprDone, func() error { return standalone.TerminateSidecarProcess(daprd.Pid, output.DaprHTTPPort) }, daprd.Kill)
+				if unixDomainSocket != "" {
+					for _, s := range []string{"http", "grpc"} {
+						os.Remove(utils.GetSocket(unixDomainSocket, output.AppID, s))
+					}
+				}
+				daprCMD, cmdErr := standalone.GetDaprCommand(runConfig)
+				if cmdErr == nil {
+					daprCMD.Stdout, daprCMD.Stderr = daprdOutput, daprdStderr
+					cmdErr = daprCMD.Start()
+				}
+				if cmdErr != nil {
+					print.FailureStatusEvent(os.Stderr, "Failed to restart Dapr: %s", cmdErr)
+					sigCh <- os.Interrupt
+					return false
+				}
+				output.DaprCMD = daprCMD
+				daprFailure.Store(nil)
+				daprExited.Store(false)
+				daprDone = waitDaprd(daprCMD)
+				record.DaprdPID, record.DaprdCreateTime = daprCMD.Process.Pid, standalone.ProcessCreateTime(daprCMD.Process.Pid)
+			}
 
-		<-sigCh
+			if app {
+				output.AppCMD, output.AppErr = standalone.GetAppCommand(runConfig), nil
+				appFailure.Store(nil)
+				if appDir != "" {
+					output.AppCMD.Dir = appDir
+				}
+				if startErr := startApp(); startErr != nil {
+					print.FailureStatusEvent(os.Stderr, "Failed to restart the app: %s", startErr)
+					return false
+				}
+				record.AppPID = output.AppCMD.Process.Pid
+			}
+
+			// The extended metadata is kept by the sidecar, so it is set again for a new one.
+			if sidecar {
+				if unixDomainSocket != "" {
+					err = utils.IsDaprListeningOnSocket(utils.GetSocket(unixDomainSocket, output.AppID, "http"), time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+				} else {
+					err = utils.IsDaprListeningOnPort(output.DaprHTTPPort, time.Duration(runtimeWaitTimeoutInSeconds)*time.Second)
+				}
+				if err != nil {
+					print.WarningStatusEvent(os.Stdout, "Dapr sidecar is not listening: %s", err.Error())
+				}
+				putMetadata(output.DaprHTTPPort, "cliPID", strconv.Itoa(os.Getpid()), output.AppID)
+				if output.AppCMD != nil {
+					putMetadata(output.DaprHTTPPort, "appCommand", strings.Join(args, " "), output.AppID)
+				}
+			}
+			if record.AppPID > 0 {
+				putMetadata(output.DaprHTTPPort, "appPID", strconv.Itoa(record.AppPID), output.AppID)
+			}
+			writeRunRecord(daprRuntimePath, record)
+			return true
+		}
+
+		// restart restarts the app, and daprd with --restart-sidecar, for a change with --watch.
+		restart := func(changed string) {
+			if restartProcesses(true, restartSidecar) {
+				print.SuccessStatusEvent(os.Stdout, "Restarted due to change in %s", changed)
+			}
+		}
+
+		// restartFailed restarts a process which failed with --restart, after the backoff. A stop signal wins over
+		// the restart: it returns false if the run must stop.
+		restartFailed := func(name string) bool {
+			n := int(restarts.Add(1))
+			backoff := standalone.RestartBackoff(n - 1)
+			exitCode := appExitCode(loadFailure(&appFailure))
+			if name == "daprd" {
+				exitCode = appExitCode(loadFailure(&daprFailure))
+			}
+			print.InfoStatusEvent(os.Stdout, "Restarting %s in %s (restart %d, policy %s)", name, backoff, n, restartPolicy)
+			select {
+			case <-sigCh:
+				return false
+			case <-time.After(backoff):
+			}
+			record.Restarts, record.LastExitCode = n, exitCode
+			if !restartProcesses(name == "app", name == "daprd") {
+				return false
+			}
+			print.SuccessStatusEvent(os.Stdout, "Restarted %s", name)
+			return true
+		}
+
+		var changes <-chan string
+		if watch {
+			watchDir := appDir
+			if watchDir == "" {
+				watchDir, err = os.Getwd()
+			}
+			var watcher *standalone.Watcher
+			if err == nil {
+				ignore := append(append([]string{}, standalone.DefaultWatchIgnores...), watchIgnore...)
+				watcher, err = standalone.NewWatcher(watchDir, standalone.WatchOptions{Include: watchInclude, Ignore: ignore})
+			}
+			if err != nil {
+				print.WarningStatusEvent(os.Stdout, "Not watching for changes: %s", err)
+			} else {
+				defer watcher.Close()
+				changes = watcher.Changes()
+				print.InfoStatusEvent(os.Stdout, "Watching %s for changes", watchDir)
+			}
+		}
+		for waiting := true; waiting; {
+			select {
+			case <-sigCh:
+				waiting = false
+			case changed := <-changes:
+				restart(changed)
+			case name := <-failed:
+				waiting = restartFailed(name)
+			}
+		}
+		stopping.Store(true)
 		print.InfoStatusEvent(os.Stdout, "\nterminated signal received: shutting down")
 
 		exitWithError := false
 
-		if output.DaprErr != nil {
+		if daprErr := loadFailure(&daprFailure); daprErr != nil {
 			exitWithError = true
-			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error exiting Dapr: %s", output.DaprErr))
-		} else if output.DaprCMD.ProcessState == nil || !output.DaprCMD.ProcessState.Exited() {
+			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error exiting Dapr: %s", daprErr))
+		} else if !daprExited.Load() {
 			err = output.DaprCMD.Process.Kill()
 			if err != nil {
 				exitWithError = true
@@ -404,11 +866,11 @@ dapr run --run-file /path/to/directory
 			}
 		}
 
-		if output.AppErr != nil {
+		if appErr := loadFailure(&appFailure); appErr != nil {
 			exitWithError = true
-			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error exiting App: %s", output.AppErr))
-		} else if output.AppCMD != nil && (output.AppCMD.ProcessState == nil || !output.AppCMD.ProcessState.Exited()) {
-			err = output.AppCMD.Process.Kill()
+			print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error exiting App: %s", appErr))
+		} else if output.AppCMD != nil && output.AppCMD.Process != nil && (output.AppCMD.ProcessState == nil || !output.AppCMD.ProcessState.Exited()) {
+			err = standalone.KillAppProcess(output.AppCMD.Process)
 			if err != nil {
 				exitWithError = true
 				print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error exiting App: %s", err))
@@ -422,7 +884,13 @@ dapr run --run-file /path/to/directory
 				os.Remove(utils.GetSocket(unixDomainSocket, output.AppID, s))
 			}
 		}
+		cleanupComponents()
+		removeRunRecord(daprRuntimePath, output.AppID)
 
+		// The exit code of the app is the exit code of the command, starting and stopping the processes otherwise.
+		if code := appExitCode(loadFailure(&appFailure)); code > 0 {
+			os.Exit(code)
+		}
 		if exitWithError {
 			os.Exit(1)
 		}
@@ -431,21 +899,43 @@ dapr run --run-file /path/to/directory
 
 func init() {
 	RunCmd.Flags().IntVarP(&appPort, "app-port", "p", -1, "The port your application is listening on")
-	RunCmd.Flags().StringVarP(&appID, "app-id", "a", "", "The id for your application, used for service discovery")
+	RunCmd.Flags().StringVarP(&appID, "app-id", "a", "", "The id for your application, used for service discovery. Defaults to the appId of the .dapr/project.json file of the app directory, or to the name of the directory")
+	RunCmd.Flags().BoolVar(&allowDuplicateID, "allow-duplicate-id", false, "Suffix the app id derived from the app directory, as in myapp-2, when an instance with the id is already running")
 	RunCmd.Flags().StringVarP(&configFile, "config", "c", "", "Dapr configuration file")
-	RunCmd.Flags().IntVarP(&port, "dapr-http-port", "H", -1, "The HTTP port for Dapr to listen on")
-	RunCmd.Flags().IntVarP(&grpcPort, "dapr-grpc-port", "G", -1, "The gRPC port for Dapr to listen on")
+	RunCmd.Flags().IntVarP(&port, "dapr-http-port", "H", -1, "The HTTP port for Dapr to listen on. Defaults to the first free port from 3500")
+	RunCmd.Flags().IntVarP(&grpcPort, "dapr-grpc-port", "G", -1, "The gRPC port for Dapr to listen on. Defaults to the first free port from 50001")
 	RunCmd.Flags().IntVarP(&internalGRPCPort, "dapr-internal-grpc-port", "I", -1, "The gRPC port for the Dapr internal API to listen on")
 	RunCmd.Flags().BoolVar(&enableProfiling, "enable-profiling", false, "Enable pprof profiling via an HTTP endpoint")
-	RunCmd.Flags().IntVarP(&profilePort, "profile-port", "", -1, "The port for the profile server to listen on")
+	RunCmd.Flags().IntVarP(&profilePort, "profile-port", "", -1, "The port for the profile server to listen on. Setting it enables profiling")
 	RunCmd.Flags().StringVarP(&logLevel, "log-level", "", "info", "The log verbosity. Valid values are: debug, info, warn, error, fatal, or panic")
-	RunCmd.Flags().IntVarP(&maxConcurrency, "app-max-concurrency", "", -1, "The concurrency level of the application, otherwise is unlimited")
+	RunCmd.Flags().IntVarP(&maxConcurrency, "app-max-concurrency", "", -1, "The concurrency level of the application, otherwise is unlimited. Can also be given as --max-concurrency")
 	RunCmd.Flags().StringVarP(&protocol, "app-protocol", "P", "http", "The protocol (grpc, grpcs, http, https, h2c) Dapr uses to talk to the application")
-	RunCmd.Flags().StringVarP(&componentsPath, "components-path", "d", "", "The path for components directory. Default is $HOME/.dapr/components or %USERPROFILE%\\.dapr\\components")
-	RunCmd.Flags().StringSliceVarP(&resourcesPaths, "resources-path", "", []string{}, "The path for resources directory")
-	// TODO: Remove below line once the flag is removed in the future releases.
-	// By marking this as deprecated, the flag will be hidden from the help menu, but will continue to work. It will show a warning message when used.
-	RunCmd.Flags().MarkDeprecated("components-path", "This flag is deprecated and will be removed in the future releases. Use \"resources-path\" flag instead")
+	RunCmd.Flags().StringSliceVarP(&componentsPaths, "components-path", "d", []string{}, "A components directory to overlay on the global one, $HOME/.dapr/components or %USERPROFILE%\\.dapr\\components. Can be repeated, later directories win for same-named components")
+	RunCmd.Flags().BoolVar(&showComponents, "show-components", false, "Print the components resolved from the components directories before starting")
+	RunCmd.Flags().StringSliceVarP(&resourcesPaths, "resources-path", "", []string{}, "The path for resources directory. The directories are passed to the runtime as they are, without merging them with the components directories")
+	RunCmd.Flags().BoolVar(&detach, "detach", false, "Run in the background, writing the output of Dapr and of the app to log files. Stop it with dapr stop")
+	RunCmd.Flags().StringArrayVar(&appEnv, "env", []string{}, "An environment variable of the app, as KEY=VALUE. Can be repeated")
+	RunCmd.Flags().StringVar(&appEnvFile, "env-file", "", "A file with environment variables of the app, one KEY=VALUE per line. The --env flags win over it")
+	RunCmd.Flags().StringVar(&appDir, "app-dir", "", "The working directory of the app. Defaults to the current directory")
+	RunCmd.Flags().StringArrayVar(&runtimeArgs, "runtime-arg", []string{}, "An argument appended as it is to the command line of the Dapr runtime, for the options without a flag. Can be repeated")
+	RunCmd.Flags().BoolVar(&showCommand, "show-command", false, "Print the command line of the Dapr runtime, with the values looking like secrets elided")
+	RunCmd.Flags().StringVar(&attachAppID, "attach", "", "The app id of a running Dapr sidecar to run the app against, without starting one. Stopping the app leaves the sidecar running")
+	RunCmd.Flags().StringVar(&restartPolicyFlag, "restart", "no", "The restart policy of the app and of the Dapr sidecar: no, or on-failure[:max] to restart them with the same ports when they exit with an error, at most max times")
+	RunCmd.Flags().BoolVar(&waitForApp, "wait-for-app", false, "Wait for the app to accept connections on the app port, or for its health endpoint to answer, before announcing it")
+	RunCmd.Flags().DurationVar(&waitForAppTimeout, "wait-for-app-timeout", standalone.DefaultAppReadyTimeout, "How long to wait for the app with --wait-for-app, before stopping it with its sidecar")
+	RunCmd.Flags().StringVar(&appHealthEndpoint, "app-health-endpoint", "", "The URL, or path on the app port, of the app endpoint answering once the app is ready, for --wait-for-app")
+	RunCmd.Flags().StringVar(&appCAFile, "app-ca-file", "", "The PEM file of the CA certificates the certificate of an app serving TLS is verified against by --wait-for-app. Without it the certificate isn't verified, as by the Dapr sidecar")
+	RunCmd.Flags().BoolVar(&startSidecarAfterApp, "start-sidecar-after-app", false, "Start the Dapr sidecar once the app is ready with --wait-for-app")
+	RunCmd.Flags().BoolVar(&watch, "watch", false, "Restart the app when the files of the app directory change")
+	RunCmd.Flags().StringSliceVar(&watchInclude, "watch-include", []string{}, "The patterns of the files to watch, such as *.go. All the files are watched by default")
+	RunCmd.Flags().StringSliceVar(&watchIgnore, "watch-ignore", []string{}, "The patterns of the files and directories not to watch, in addition to node_modules, .git, bin and the other defaults")
+	RunCmd.Flags().BoolVar(&restartSidecar, "restart-sidecar", false, "Restart the Dapr sidecar along with the app with --watch")
+	RunCmd.Flags().BoolVar(&killAppOnSidecarExit, "kill-app-on-sidecar-exit", true, "Stop the app when the Dapr sidecar exits")
+	RunCmd.Flags().BoolVar(&killSidecarOnAppExit, "kill-sidecar-on-app-exit", true, "Stop the Dapr sidecar when the app exits")
+	RunCmd.Flags().BoolVar(&replace, "replace", false, "Stop the running instance with the same app id first")
+	RunCmd.Flags().BoolVar(&detachedChild, "detached-child", false, "")
+	RunCmd.Flags().MarkHidden("detached-child")
+	RunCmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Start even if the resource files have problems, leaving it to the runtime to report them")
 	RunCmd.Flags().String("placement-host-address", "localhost", "The address of the placement service. Format is either <hostname> for default port or <hostname>:<port> for custom port")
 	// TODO: Remove below flag once the flag is removed in runtime in future release.
 	RunCmd.Flags().BoolVar(&appSSL, "app-ssl", false, "Enable https when Dapr invokes the application")
@@ -463,7 +953,14 @@ func init() {
 	RunCmd.Flags().BoolVar(&enableAPILogging, "enable-api-logging", false, "Log API calls at INFO verbosity. Valid values are: true or false")
 	RunCmd.Flags().StringVar(&apiListenAddresses, "dapr-listen-addresses", "", "Comma separated list of IP addresses that sidecar will listen to")
 	RunCmd.Flags().StringVarP(&runFilePath, "run-file", "f", "", "Path to the run template file for the list of apps to run")
+	RunCmd.Flags().BoolVar(&exitOnFirstFailure, "exit-on-first-failure", false, "Stop all the apps of the run file as soon as the Dapr sidecar or the app of one of them fails")
 	RunCmd.Flags().StringVarP(&appChannelAddress, "app-channel-address", "", utils.DefaultAppChannelAddress, "The network address the application listens on")
+	RunCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "max-concurrency" {
+			name = "app-max-concurrency"
+		}
+		return pflag.NormalizedName(name)
+	})
 	RootCmd.AddCommand(RunCmd)
 }
 
@@ -483,7 +980,7 @@ func executeRun(runTemplateName, runFilePath string, apps []runfileconfig.App) (
 
 	print.WarningStatusEvent(os.Stdout, "This is a preview feature and subject to change in future releases.")
 
-	for _, app := range apps {
+	for i, app := range apps {
 		print.StatusEvent(os.Stdout, print.LogInfo, "Validating config and starting app %q", app.RunConfig.AppID)
 		// Set defaults if zero value provided in config yaml.
 		app.RunConfig.SetDefaultFromSchema()
@@ -529,7 +1026,7 @@ func executeRun(runTemplateName, runFilePath string, apps []runfileconfig.App) (
 		}
 		customAppLogWriter = print.CustomLogWriter{W: appLogWriter}
 		runState, err := startDaprdAndAppProcesses(&runConfig, app.AppDirPath, sigCh,
-			daprdLogWriterCloser, daprdLogWriterCloser, customAppLogWriter, customAppLogWriter)
+			daprdLogWriterCloser, daprdLogWriterCloser, customAppLogWriter, customAppLogWriter, print.AppColor(i))
 		if err != nil {
 			print.StatusEvent(appDaprdWriter, print.LogFailure, "Error starting Dapr and app (%q): %s", app.AppID, err.Error())
 			exitWithError = true
@@ -564,14 +1061,24 @@ func executeRun(runTemplateName, runFilePath string, apps []runfileconfig.App) (
 				putAppProcessIDInMeta(runState)
 			}
 		}
+		writeRunRecord(runConfig.DaprdInstallPath, appRunRecord(app, runState, runTemplateName, runFilePath))
 
 		print.StatusEvent(runState.DaprCMD.OutputWriter, print.LogSuccess, "You're up and running! Dapr logs will appear here.\n")
+		endpoints := app.RunConfig.Endpoints()
+		if app.AppLogDestination != standalone.Console {
+			endpoints.AppLogPath = app.AppLogFileName
+		}
+		if app.DaprdLogDestination != standalone.Console {
+			endpoints.DaprdLogPath = app.DaprdLogFileName
+		}
+		printEndpoints(runState.DaprCMD.OutputWriter, endpoints)
 		logInformationalStatusToStdout(app)
 	}
 	// If all apps have been started and there are no errors in starting the apps wait for signal from sigCh.
 	if !exitWithError {
 		// After all apps started wait for sigCh.
 		<-sigCh
+		runFileStopping.Store(true)
 		// To add a new line in Stdout.
 		fmt.Println()
 		print.InfoStatusEvent(os.Stdout, "Received signal to stop Dapr and app processes. Shutting down Dapr and app processes.")
@@ -639,6 +1146,9 @@ func gracefullyShutdownAppsAndCloseResources(runState []*runExec.RunExec, apps [
 	for _, s := range runState {
 		stopDaprdAndAppProcesses(s)
 	}
+	for _, app := range apps {
+		removeRunRecord(app.RunConfig.DaprdInstallPath, app.AppID)
+	}
 	var err error
 	// close log file resources.
 	for _, app := range apps {
@@ -665,6 +1175,11 @@ func executeRunWithAppsConfigFile(runFilePath string) {
 		print.StatusEvent(os.Stdout, print.LogFailure, "No apps to run")
 		os.Exit(1)
 	}
+	if skipValidation {
+		for i := range apps {
+			apps[i].SkipValidation = true
+		}
+	}
 	exitWithError, closeErr := executeRun(config.Name, runFilePath, apps)
 	if exitWithError {
 		if closeErr != nil {
@@ -678,7 +1193,7 @@ func executeRunWithAppsConfigFile(runFilePath string) {
 // This should be called as a blocking function call.
 func startDaprdAndAppProcesses(runConfig *standalone.RunConfig, commandDir string, sigCh chan os.Signal,
 	daprdOutputWriter io.Writer, daprdErrorWriter io.Writer,
-	appOutputWriter io.Writer, appErrorWriter io.Writer,
+	appOutputWriter io.Writer, appErrorWriter io.Writer, appColor func(a ...interface{}) string,
 ) (*runExec.RunExec, error) {
 	daprRunning := make(chan bool, 1)
 	appRunning := make(chan bool, 1)
@@ -693,6 +1208,9 @@ func startDaprdAndAppProcesses(runConfig *standalone.RunConfig, commandDir strin
 	}
 	daprCMD.WithOutputWriter(daprdOutputWriter)
 	daprCMD.WithErrorWriter(daprdErrorWriter)
+	if showCommand {
+		print.StatusEvent(daprdOutputWriter, print.LogInfo, "Runtime command of app %q: %s", runConfig.AppID, standalone.RedactedCommandLine(daprCMD.Command))
+	}
 	daprCMD.SetStdout()
 	daprCMD.SetStderr()
 
@@ -713,6 +1231,7 @@ func startDaprdAndAppProcesses(runConfig *standalone.RunConfig, commandDir strin
 	}
 
 	runState := runExec.New(runConfig, daprCMD, appCmd)
+	runState.AppColor = appColor
 
 	startErrChan := make(chan error, 1)
 
@@ -819,18 +1338,18 @@ func startAppProcess(runConfig *standalone.RunConfig, runE *runExec.RunExec,
 	outScanner := bufio.NewScanner(stdOutPipe)
 	go func() {
 		for errScanner.Scan() {
-			fmt.Fprintln(runE.AppCMD.ErrorWriter, print.Blue(fmt.Sprintf("== APP - %s == %s", runE.AppID,
+			fmt.Fprintln(runE.AppCMD.ErrorWriter, runE.AppColor(fmt.Sprintf("== APP - %s == %s", runE.AppID,
 				errScanner.Text())))
 		}
 	}()
 
 	go func() {
 		for outScanner.Scan() {
-			fmt.Fprintln(runE.AppCMD.OutputWriter, print.Blue(fmt.Sprintf("== APP - %s == %s", runE.AppID, outScanner.Text())))
+			fmt.Fprintln(runE.AppCMD.OutputWriter, runE.AppColor(fmt.Sprintf("== APP - %s == %s", runE.AppID, outScanner.Text())))
 		}
 	}()
 
-	err := runE.AppCMD.Command.Start()
+	err := standalone.StartAppProcess(runE.AppCMD.Command)
 	if err != nil {
 		print.StatusEvent(runE.AppCMD.ErrorWriter, print.LogFailure, err.Error())
 		errorChan <- err
@@ -847,6 +1366,13 @@ func startAppProcess(runConfig *standalone.RunConfig, runE *runExec.RunExec,
 		} else {
 			print.StatusEvent(runE.AppCMD.OutputWriter, print.LogSuccess, "Exited App successfully")
 		}
+		setRunRecordExitReason(runConfig.DaprdInstallPath, runE.AppID, exitReason("app", appErr))
+		if killSidecarOnAppExit && runE.DaprCMD.Command.ProcessState == nil {
+			killDaprdProcess(runE)
+		}
+		if appErr != nil && exitOnFirstFailure {
+			stopOnFailure(sigCh, runE.AppID)
+		}
 	}()
 
 	appRunning <- true
@@ -887,6 +1413,13 @@ func startDaprdProcess(runConfig *standalone.RunConfig, runE *runExec.RunExec,
 		} else {
 			print.StatusEvent(runE.DaprCMD.OutputWriter, print.LogSuccess, "Exited Dapr successfully")
 		}
+		setRunRecordExitReason(runConfig.DaprdInstallPath, runE.AppID, exitReason("daprd", daprdErr))
+		if killAppOnSidecarExit && runE.AppCMD.Command != nil && runE.AppCMD.Command.Process != nil && runE.AppCMD.Command.ProcessState == nil {
+			killAppProcess(runE)
+		}
+		if daprdErr != nil && exitOnFirstFailure {
+			stopOnFailure(sigCh, runE.AppID)
+		}
 	}()
 
 	if runConfig.AppPort <= 0 {
@@ -935,6 +1468,62 @@ func startDaprdProcess(runConfig *standalone.RunConfig, runE *runExec.RunExec,
 	daprRunning <- true
 }
 
+// stopForRestart asks a process to exit with terminate, and kills it with kill if it is still running after the
+// grace period. done is closed once the process exited.
+func stopForRestart(done <-chan struct{}, terminate, kill func() error) {
+	select {
+	case <-done:
+		return
+	default:
+	}
+	if err := terminate(); err == nil {
+		select {
+		case <-done:
+			return
+		case <-time.After(standalone.DefaultStopGracePeriod):
+		}
+	}
+	if err := kill(); err != nil {
+		print.WarningStatusEvent(os.Stdout, "Failed to stop the process for the restart: %s", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(standalone.DefaultStopGracePeriod):
+	}
+}
+
+// restartOnFailure reports whether a process which failed is restarted by policy, once the instance was restarted
+// restarts times.
+func restartOnFailure(name string, policy standalone.RestartPolicy, restarts int) bool {
+	if policy.Allows(restarts) {
+		return true
+	}
+	if policy.OnFailure {
+		print.WarningStatusEvent(os.Stdout, "Not restarting %s after %d restarts", name, restarts)
+	}
+	return false
+}
+
+// putMetadata puts a value in the extended metadata of the sidecar, warning if it fails.
+func putMetadata(httpPort int, key, value, appID string) {
+	if err := metadata.Put(httpPort, key, value, appID, unixDomainSocket); err != nil {
+		print.WarningStatusEvent(os.Stdout, "Could not update sidecar metadata for %s: %s", key, err.Error())
+	}
+}
+
+// stopOnFailure stops all the apps of a run file once a process of appID failed, for --exit-on-first-failure.
+func stopOnFailure(sigCh chan os.Signal, appID string) {
+	if runFileStopping.Load() {
+		return
+	}
+	print.StatusEvent(os.Stdout, print.LogWarning, "App %q failed, stopping all the apps of the run file", appID)
+	// The signal is already pending if another process failed first.
+	select {
+	case sigCh <- os.Interrupt:
+	default:
+	}
+}
+
 // killDaprdProcess is used to kill the Daprd process and return error on failure.
 func killDaprdProcess(runE *runExec.RunExec) error {
 	err := runE.DaprCMD.Command.Process.Kill()
@@ -951,7 +1540,7 @@ func killAppProcess(runE *runExec.RunExec) error {
 	if runE.AppCMD.Command == nil {
 		return nil
 	}
-	err := runE.AppCMD.Command.Process.Kill()
+	err := standalone.KillAppProcess(runE.AppCMD.Command.Process)
 	if err != nil {
 		print.StatusEvent(runE.DaprCMD.ErrorWriter, print.LogFailure, "Error exiting App: %s", err)
 		return err
@@ -1046,3 +1635,389 @@ func getRunFilePath(path string) (string, error) {
 	}
 	return path, nil
 }
+
+func validateAppDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// componentLayers returns the components directories to merge, in order: the global directory if it exists, then
+// the directories of the --components-path flags. A directory given more than once is only kept the first time.
+func componentLayers(globalPath string, paths []string) []string {
+	var layers []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			layers = append(layers, path)
+		}
+	}
+	if utils.ValidateFilePath(globalPath) == nil {
+		add(globalPath)
+	}
+	for _, p := range paths {
+		if strings.TrimSpace(p) != "" {
+			add(strings.TrimSpace(p))
+		}
+	}
+	return layers
+}
+
+// mergeComponentLayers validates each of the layers, as the same-named components across layers are expected, and
+// merges them into a temporary directory for the runtime.
+func mergeComponentLayers(layers []string) (*standalone.MergedComponents, error) {
+	if !skipValidation {
+		var problems []standalone.ComponentProblem
+		for _, layer := range layers {
+			layerProblems, err := standalone.ValidateComponents(layer)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, layerProblems...)
+		}
+		if len(problems) > 0 {
+			return nil, &standalone.ComponentsValidationError{Problems: problems}
+		}
+	}
+
+	merged, err := standalone.MergeComponentDirs(layers...)
+	if err != nil {
+		return nil, err
+	}
+	print.DebugStatusEvent(os.Stdout, "merged components directories %s into %s", strings.Join(layers, ", "), merged.Dir)
+	if showComponents {
+		table := print.NewTable(
+			print.TableColumn{Name: "Kind", Key: "kind"},
+			print.TableColumn{Name: "Name", Key: "name"},
+			print.TableColumn{Name: "Type", Key: "type"},
+			print.TableColumn{Name: "File", Key: "file", Truncate: true},
+			print.TableColumn{Name: "Overrides", Key: "overrides", Truncate: true},
+		)
+		for _, c := range merged.Components {
+			table.AddRow(c.Kind, c.Name, c.Type, c.File, strings.Join(c.Overrides, ", "))
+		}
+		print.InfoStatusEvent(os.Stdout, "Resolved components from %s:", strings.Join(layers, ", "))
+		table.RenderStatus(os.Stdout)
+	}
+	return merged, nil
+}
+
+// appExitCode returns the exit code of the app from the error of waiting for its process, or 0 if it didn't exit
+// with an error code. An app terminated by a signal has no exit code and returns 1.
+func appExitCode(appErr error) int {
+	var exitErr *exec.ExitError
+	if !errors.As(appErr, &exitErr) {
+		return 0
+	}
+	if code := exitErr.ExitCode(); code > 0 {
+		return code
+	}
+	return 1
+}
+
+// loadFailure returns the error stored in failure, or nil if there is none.
+func loadFailure(failure *atomic.Pointer[error]) error {
+	if err := failure.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// appRunRecord returns the run record of an app of a run file.
+func appRunRecord(app runfileconfig.App, runState *runExec.RunExec, runTemplateName, runFilePath string) standalone.RunRecord {
+	record := standalone.RunRecord{
+		AppID:           runState.AppID,
+		DaprdPID:        runState.DaprCMD.Command.Process.Pid,
+		CliPID:          os.Getpid(),
+		HTTPPort:        runState.DaprHTTPPort,
+		GRPCPort:        runState.DaprGRPCPort,
+		AppPort:         app.RunConfig.AppPort,
+		Started:         time.Now(),
+		Command:         strings.Join(app.RunConfig.Command, " "),
+		RunTemplatePath: runFilePath,
+		RunTemplateName: runTemplateName,
+		DaprdCreateTime: standalone.ProcessCreateTime(runState.DaprCMD.Command.Process.Pid),
+		CliCreateTime:   standalone.ProcessCreateTime(os.Getpid()),
+	}
+	// The path is absolute as in the metadata of the sidecar, so that the instances of the run file are grouped.
+	if absPath, err := filepath.Abs(runFilePath); err == nil {
+		record.RunTemplatePath = absPath
+	}
+	if runState.AppCMD.Command != nil && runState.AppCMD.Command.Process != nil {
+		record.AppPID = runState.AppCMD.Command.Process.Pid
+	}
+	if app.AppLogDestination != standalone.Console {
+		record.AppLogPath = app.AppLogFileName
+	}
+	if app.DaprdLogDestination != standalone.Console {
+		record.DaprdLogPath = app.DaprdLogFileName
+	}
+	setRunRecordSettings(&record, &app.RunConfig)
+	return record
+}
+
+// setRunRecordSettings records the settings of daprd in effect from a validated config.
+func setRunRecordSettings(record *standalone.RunRecord, config *standalone.RunConfig) {
+	endpoints := config.Endpoints()
+	record.ResourcesPaths = endpoints.ResourcesPaths
+	record.ConfigFile = endpoints.ConfigFile
+	record.PlacementHostAddress = endpoints.PlacementHostAddress
+	record.UnixDomainSocket = config.UnixDomainSocket
+	record.AppProtocol = endpoints.AppProtocol
+}
+
+// printEndpoints prints the endpoints of an instance once it started, for users to know how to reach it.
+func printEndpoints(w io.Writer, endpoints standalone.Endpoints) {
+	print.StatusEvent(w, print.LogInfo, "Dapr instance %s:", endpoints.AppID)
+	for _, line := range endpoints.Banner() {
+		if print.IsJSONLogEnabled() {
+			print.StatusEvent(w, print.LogInfo, line)
+			continue
+		}
+		fmt.Fprintln(print.StatusWriter(w), "  "+line)
+	}
+}
+
+// writeRunRecord records a started instance for the list command. Failing to write it doesn't stop the instance.
+func writeRunRecord(daprRuntimePath string, record standalone.RunRecord) {
+	daprDir, err := standalone.GetDaprRuntimePath(daprRuntimePath)
+	if err == nil {
+		err = standalone.WriteRunRecord(daprDir, record)
+	}
+	if err != nil {
+		print.WarningStatusEvent(os.Stdout, "Could not write the run record of app %q: %s", record.AppID, err)
+	}
+}
+
+// removeRunRecord removes the record of a stopped instance.
+func removeRunRecord(daprRuntimePath, appID string) {
+	daprDir, err := standalone.GetDaprRuntimePath(daprRuntimePath)
+	if err == nil {
+		err = standalone.RemoveRunRecord(daprDir, appID)
+	}
+	if err != nil {
+		print.WarningStatusEvent(os.Stdout, "Could not remove the run record of app %q: %s", appID, err)
+	}
+}
+
+// setRunRecordExitReason records in the record of an instance why its daprd or app process exited.
+func setRunRecordExitReason(daprRuntimePath, appID, reason string) {
+	daprDir, err := standalone.GetDaprRuntimePath(daprRuntimePath)
+	if err == nil {
+		err = standalone.SetRunRecordExitReason(daprDir, appID, reason)
+	}
+	if err != nil {
+		print.WarningStatusEvent(os.Stdout, "Could not update the run record of app %q: %s", appID, err)
+	}
+}
+
+// exitReason describes how the process exited, for the run records.
+func exitReason(process string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s exited: %s", process, err)
+	}
+	return process + " exited"
+}
+
+// runAttached runs the app command args against the running sidecar of attachID, for run --attach, and returns the
+// exit code of the command. Only the app is stopped on a signal.
+func runAttached(daprDir, attachID string, args []string) int {
+	record, err := standalone.FindAttachTarget(daprDir, attachID)
+	if err == nil {
+		if record.UnixDomainSocket != "" {
+			err = utils.IsDaprListeningOnSocket(utils.GetSocket(record.UnixDomainSocket, attachID, "http"), 2*time.Second)
+		} else {
+			err = utils.IsDaprListeningOnPort(record.HTTPPort, 2*time.Second)
+		}
+		if err != nil {
+			err = fmt.Errorf("the Dapr sidecar of app id %q is not responding: %w", attachID, err)
+		}
+	}
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		return 1
+	}
+	if appPort > 0 && appPort != record.AppPort {
+		print.WarningStatusEvent(os.Stdout, "The Dapr sidecar of app id %s calls the app on port %d, not on the port %d of --app-port", attachID, record.AppPort, appPort)
+	}
+
+	env, envConflicts, err := standalone.ResolveAppEnv(appEnvFile, appEnv)
+	if err == nil && appDir != "" {
+		err = validateAppDir(appDir)
+	}
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		return 1
+	}
+	// The app gets the environment of an app started with the sidecar, from the ports of the instance.
+	config := &standalone.RunConfig{
+		AppID:             attachID,
+		AppChannelAddress: appChannelAddress,
+		AppPort:           record.AppPort,
+		HTTPPort:          record.HTTPPort,
+		GRPCPort:          record.GRPCPort,
+		Command:           args,
+		SharedRunConfig:   standalone.SharedRunConfig{Env: env},
+	}
+	for _, c := range append(envConflicts, config.EnvConflicts()...) {
+		print.WarningStatusEvent(os.Stdout, c.String())
+	}
+	appCMD := standalone.GetAppCommand(config)
+	appCMD.Dir = appDir
+	var pipes []io.Reader
+	for _, pipe := range []func() (io.ReadCloser, error){appCMD.StdoutPipe, appCMD.StderrPipe} {
+		r, pipeErr := pipe()
+		if pipeErr != nil {
+			print.FailureStatusEvent(os.Stderr, "Error creating the output of the app: %s", pipeErr)
+			return 1
+		}
+		pipes = append(pipes, r)
+	}
+	for _, r := range pipes {
+		scanner := bufio.NewScanner(r)
+		go func() {
+			for scanner.Scan() {
+				fmt.Println(print.Blue(fmt.Sprintf("== APP == %s", scanner.Text())))
+			}
+		}()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	daprsyscall.SetupShutdownNotify(sigCh)
+	if err = standalone.StartAppProcess(appCMD); err != nil {
+		print.FailureStatusEvent(os.Stderr, "Failed to start the app: %s", err)
+		return 1
+	}
+	app := appCMD.Process
+	attached := &standalone.AttachedApp{CliPID: os.Getpid(), AppPID: app.Pid, Command: strings.Join(args, " "), Started: time.Now()}
+	if err = standalone.SetRunRecordAttachedApp(daprDir, attachID, attached); err != nil {
+		print.WarningStatusEvent(os.Stdout, "Could not update the run record of app %q: %s", attachID, err)
+	}
+	defer func() {
+		if err := standalone.SetRunRecordAttachedApp(daprDir, attachID, nil); err != nil {
+			print.WarningStatusEvent(os.Stdout, "Could not update the run record of app %q: %s", attachID, err)
+		}
+	}()
+	print.SuccessStatusEvent(os.Stdout, "You're up and running! The app is attached to the Dapr sidecar of app id %s (HTTP port %d, gRPC port %d), its logs will appear here.\n", attachID, record.HTTPPort, record.GRPCPort)
+
+	done := make(chan struct{})
+	var appErr error
+	go func() {
+		defer close(done)
+		appErr = appCMD.Wait()
+	}()
+	select {
+	case <-done:
+	case <-sigCh:
+		print.InfoStatusEvent(os.Stdout, "\nterminated signal received: stopping the app")
+		stopForRestart(done, func() error { return standalone.TerminateAppProcess(app) }, func() error { return standalone.KillAppProcess(app) })
+		<-done
+		// The app exiting from the signal isn't a failure.
+		appErr = nil
+	}
+	if appErr != nil {
+		print.FailureStatusEvent(os.Stderr, "The App process exited with error code: %s", appErr.Error())
+	} else {
+		print.SuccessStatusEvent(os.Stdout, "Exited App successfully")
+	}
+	print.InfoStatusEvent(os.Stdout, "The Dapr sidecar of app id %s keeps running", attachID)
+	return appExitCode(appErr)
+}
+
+// replaceInstance stops the running instance of appID, if there is one, for run --replace.
+func replaceInstance(daprDir, appID string) {
+	apps, err := standalone.ListInstances(daprRuntimePath)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, "failed to get list of apps started by dapr : %s", err)
+		os.Exit(1)
+	}
+	instance, ok := findInstance(apps, appID)
+	if !ok {
+		return
+	}
+	print.InfoStatusEvent(os.Stdout, "Stopping the running instance of app id %s", appID)
+	stopGracePeriod = standalone.DefaultStopGracePeriod
+	if !stopInstance(daprDir, instance, standalone.GetCLIPIDCountMap(apps)) {
+		os.Exit(1)
+	}
+}
+
+// startDetached starts the run command again in the background, as a detached child writing the output of daprd
+// and of the app to log files, and returns once it has started.
+func startDetached(daprDir string) error {
+	if appID == "" {
+		generated, err := standalone.GenerateAppID()
+		if err != nil {
+			return err
+		}
+		appID = generated
+	}
+	apps, err := standalone.ListInstances(daprRuntimePath)
+	if err != nil {
+		return err
+	}
+	if _, ok := findInstance(apps, appID); ok {
+		return fmt.Errorf("app id %q is already running, stop it first or use --replace", appID)
+	}
+
+	logsDir := standalone.GetRunLogsPath(daprDir, appID)
+	if err = os.MkdirAll(logsDir, print.PrivateDirMode); err != nil {
+		return fmt.Errorf("error creating logs directory: %w", err)
+	}
+	cliLogPath := filepath.Join(logsDir, "cli.log")
+	cliLog, err := os.OpenFile(cliLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, print.PrivateFileMode)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	defer cliLog.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	child := exec.Command(executable, detachedArgs(os.Args[1:], appID)...)
+	child.Stdout, child.Stderr = cliLog, cliLog
+	daprsyscall.DetachProcess(child)
+	if err = child.Start(); err != nil {
+		return err
+	}
+	pid := child.Process.Pid
+	// The child isn't waited for, it outlives this process.
+	child.Process.Release()
+
+	print.SuccessStatusEvent(os.Stdout, "Started app id %s in the background (pid %d)", appID, pid)
+	print.InfoStatusEvent(os.Stdout, "Dapr logs: %s", filepath.Join(logsDir, "daprd.log"))
+	print.InfoStatusEvent(os.Stdout, "App logs: %s", filepath.Join(logsDir, "app.log"))
+	print.InfoStatusEvent(os.Stdout, "CLI logs: %s", cliLogPath)
+	print.InfoStatusEvent(os.Stdout, "Use dapr list to check it is running, and dapr stop --app-id %s to stop it", appID)
+	return nil
+}
+
+// detachedArgs returns the arguments of the detached child from the arguments of the run command: the flags of
+// detached mode are replaced with the flag of the child, and the app id is set. The app command after "--" is kept
+// as it is.
+func detachedArgs(args []string, appID string) []string {
+	end := len(args)
+	for i, a := range args {
+		if a == "--" {
+			end = i
+			break
+		}
+	}
+	childArgs := make([]string, 0, len(args)+3)
+	for _, a := range args[:end] {
+		if a == "--detach" || strings.HasPrefix(a, "--detach=") || a == "--replace" || strings.HasPrefix(a, "--replace=") {
+			continue
+		}
+		childArgs = append(childArgs, a)
+	}
+	childArgs = append(childArgs, "--detached-child", "--app-id", appID)
+	return append(childArgs, args[end:]...)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	selftestOutputFormat     string
+	selftestContainerRuntime string
+	selftestStateStore       string
+	selftestPubSub           string
+	selftestTimeout          time.Duration
+)
+
+var SelftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that the local environment works end to end, with a sidecar for a built-in echo app. Supported platforms: Self-hosted",
+	Long: `Check that the local environment works end to end, as an app would use it.
+
+The selftest starts an echo app built into the CLI and a sidecar for it, with the components of the environment.
+Through the sidecar, it saves a state and reads it back, publishes a message which the app receives, and invokes the
+echo method of the app. The app and the sidecar are stopped once the checks are done, and the log of the sidecar is
+kept for the failing checks.
+`,
+	Example: `
+# Check the environment set up by dapr init
+dapr selftest
+
+# Check the components named mystore and mypubsub
+dapr selftest --state-store mystore --pubsub mypubsub
+
+# Get the results of the checks in JSON format
+dapr selftest -o json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(selftestOutputFormat, print.OutputJSON, print.OutputWide); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		selftestContainerRuntime = installContainerRuntime(cmd)
+		if !utils.IsValidContainerRuntime(selftestContainerRuntime) {
+			print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+			os.Exit(1)
+		}
+		// Ctrl+C stops the checks, the app and the sidecar are still torn down.
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		report, err := standalone.RunSelftest(ctx, standalone.SelftestOptions{
+			DaprRuntimePath:  daprRuntimePath,
+			DockerNetwork:    resolveSetting(cmd, standalone.SettingNetwork),
+			ContainerRuntime: selftestContainerRuntime,
+			StateStore:       selftestStateStore,
+			PubSub:           selftestPubSub,
+			Timeout:          selftestTimeout,
+		})
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		} else {
+			table := print.NewTable(
+				print.TableColumn{Name: "Check", Key: "check"},
+				print.TableColumn{Name: "Layer", Key: "layer"},
+				print.TableColumn{Name: "Status", Key: "status"},
+				print.TableColumn{Name: "Message", Key: "message", Truncate: true},
+				print.TableColumn{Name: "Hint", Key: "hint", Truncate: true},
+			)
+			for _, c := range report.Checks {
+				table.AddRow(c.Name, c.Layer, c.Status, c.Message, c.Hint)
+			}
+			if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+		}
+		if report.Failures > 0 {
+			print.FailureStatusEvent(os.Stderr, "%d check(s) failed, see the hints to fix them", report.Failures)
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "The local environment works end to end")
+	},
+}
+
+func init() {
+	SelftestCmd.Flags().String("network", "", "The Docker network the containers of the local environment run in")
+	SelftestCmd.Flags().StringVarP(&selftestContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	SelftestCmd.Flags().StringVar(&selftestStateStore, "state-store", "statestore", "The name of the state store component to check")
+	SelftestCmd.Flags().StringVar(&selftestPubSub, "pubsub", "pubsub", "The name of the pubsub component to check")
+	SelftestCmd.Flags().DurationVar(&selftestTimeout, "timeout", standalone.DefaultSelftestTimeout, "How long the checks through the sidecar may take, from its start to the receipt of the test message")
+	SelftestCmd.Flags().StringVarP(&selftestOutputFormat, "output", "o", "", "The output format of the checks. Valid values are: json, or wide to not truncate the columns")
+	SelftestCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(SelftestCmd)
+}
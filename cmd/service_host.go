@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var serviceHostName string
+
+// ServiceHostCmd hosts the Windows services registered by init --init-system windows-service, for the service
+// control manager, which can't run the placement binary as a service itself.
+var ServiceHostCmd = &cobra.Command{
+	Use:    standalone.WindowsServiceHostCommand + " --name <service> -- <command>...",
+	Short:  "Run a command as a Windows service of slim mode. Supported platforms: Windows",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := standalone.RunWindowsService(serviceHostName, args); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ServiceHostCmd.Flags().StringVar(&serviceHostName, "name", "", "The name of the Windows service")
+	ServiceHostCmd.MarkFlagRequired("name")
+	ServiceHostCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(ServiceHostCmd)
+}
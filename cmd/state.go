@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+)
+
+var stateStore string
+
+var StateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and change the state of an app through its sidecar. Supported platforms: Self-hosted",
+	Example: `
+# Get the value of key order-1 in the state store of myapp
+dapr state get myapp order-1
+
+# Set the value of key order-1 in the cache state store of myapp
+dapr state set myapp order-1 '{"id":1}' --store cache
+
+# Delete key order-1 from the state store of myapp
+dapr state delete myapp order-1
+`,
+}
+
+var StateGetCmd = &cobra.Command{
+	Use:   "get <app-id> <key>",
+	Short: "Print the value of a key in a state store",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		item, err := standalone.NewClient().GetState(args[0], stateStore, args[1])
+		if errors.Is(err, standalone.ErrStateKeyNotFound) {
+			print.FailureStatusEvent(os.Stderr, "Key %s not found in state store %s of app %s", args[1], stateStore, args[0])
+			os.Exit(1)
+		}
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Error getting key %s: %s", args[1], err)
+			os.Exit(1)
+		}
+		value, encoded := standalone.FormatStateValue(item.Value)
+		if encoded {
+			print.InfoStatusEvent(os.Stderr, "The value is binary, printed base64 encoded")
+		}
+		fmt.Println(value)
+	},
+}
+
+var StateSetCmd = &cobra.Command{
+	Use:   "set <app-id> <key> <value>",
+	Short: "Set the value of a key in a state store. The value is read from stdin if it is -",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := readPayload("state set", args[2], "")
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if err = standalone.NewClient().SaveState(args[0], stateStore, args[1], value); err != nil {
+			print.FailureStatusEvent(os.Stderr, "Error setting key %s: %s", args[1], err)
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Key %s set in state store %s", args[1], stateStore)
+	},
+}
+
+var StateDeleteCmd = &cobra.Command{
+	Use:   "delete <app-id> <key>",
+	Short: "Delete a key from a state store",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := standalone.NewClient().DeleteState(args[0], stateStore, args[1]); err != nil {
+			print.FailureStatusEvent(os.Stderr, "Error deleting key %s: %s", args[1], err)
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Key %s deleted from state store %s", args[1], stateStore)
+	},
+}
+
+func init() {
+	StateCmd.PersistentFlags().StringVarP(&stateStore, "store", "s", standalone.DefaultStateStore, "The name of the state store component")
+	StateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	for _, c := range []*cobra.Command{StateGetCmd, StateSetCmd, StateDeleteCmd} {
+		c.Flags().BoolP("help", "h", false, "Print this help message")
+		StateCmd.AddCommand(c)
+	}
+	RootCmd.AddCommand(StateCmd)
+}
@@ -15,23 +15,73 @@ package cmd
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
 	"github.com/dapr/cli/utils"
 )
 
+var (
+	statusOutputFormat     string
+	statusContainerRuntime string
+	statusCheckLockfile    string
+)
+
 var StatusCmd = &cobra.Command{
-	Use:   "status",
-	Short: "Show the health status of Dapr services. Supported platforms: Kubernetes",
+	Use:   "status [app-id]",
+	Short: "Show the health status of Dapr services, of the local environment, or of a self-hosted Dapr instance. Supported platforms: Kubernetes and self-hosted",
 	Example: `
+# Check that the runtime binary and the containers set up by dapr init are alive
+dapr status
+
+# Report the differences between the environment and the dapr.lock lockfile of the current directory
+dapr status --check-lockfile
+
+# Report the differences between the environment and another lockfile
+dapr status --check-lockfile=../team/dapr.lock
+
+# Get the health, uptime and number of loaded components of the instance of app id myapp
+dapr status myapp
+
+# Get the health of the instance of app id myapp in JSON format
+dapr status myapp -o json
+
 # Get status of Dapr services from Kubernetes
 dapr status -k 
+
+# Get status of Dapr services from Kubernetes in JSON format
+dapr status -k -o json
+
+# Get status of Dapr services from Kubernetes without truncating the columns
+dapr status -k -o wide
 `,
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(statusOutputFormat, print.OutputJSON, print.OutputWide); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if !k8s {
+			if len(args) == 0 && statusCheckLockfile != "" {
+				outputLockfileDrift(cmd, statusCheckLockfile)
+				return
+			}
+			if len(args) == 0 {
+				outputEnvironmentStatus(cmd)
+				return
+			}
+			outputInstanceStatus(args[0])
+			return
+		}
+		if len(args) > 0 {
+			print.FailureStatusEvent(os.Stderr, "An app id can not be given with -k")
+			os.Exit(1)
+		}
 		sc, err := kubernetes.NewStatusClient()
 		if err != nil {
 			print.FailureStatusEvent(os.Stderr, err.Error())
@@ -46,22 +96,190 @@ dapr status -k
 			print.FailureStatusEvent(os.Stderr, "No status returned. Is Dapr initialized in your cluster?")
 			os.Exit(1)
 		}
-		table, err := gocsv.MarshalString(status)
-		if err != nil {
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), status); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		table := print.NewTable(
+			print.TableColumn{Name: "Name", Key: "name", Truncate: true},
+			print.TableColumn{Name: "Namespace", Key: "namespace", Truncate: true},
+			print.TableColumn{Name: "Healthy", Key: "healthy"},
+			print.TableColumn{Name: "Status", Key: "status"},
+			print.TableColumn{Name: "Replicas", Key: "replicas"},
+			print.TableColumn{Name: "Version", Key: "version"},
+			print.TableColumn{Name: "Ready", Key: "ready"},
+			print.TableColumn{Name: "Restarts", Key: "restarts"},
+			print.TableColumn{Name: "Age", Key: "age"},
+			print.TableColumn{Name: "Created", Key: "created", Truncate: true},
+		)
+		for _, s := range status {
+			table.AddRow(s.Name, s.Namespace, s.Healthy, s.Status, strconv.Itoa(s.Replicas), s.Version, s.Ready, strconv.Itoa(s.Restarts), s.Age, s.Created)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
 			print.FailureStatusEvent(os.Stderr, err.Error())
 			os.Exit(1)
 		}
-
-		utils.PrintTable(table)
 	},
+	Args: cobra.MaximumNArgs(1),
 	PostRun: func(cmd *cobra.Command, args []string) {
-		kubernetes.CheckForCertExpiry()
+		if k8s {
+			kubernetes.CheckForCertExpiry()
+		}
 	},
 }
 
+// outputInstanceStatus probes the sidecar of the self-hosted instance of appID and outputs its health.
+func outputInstanceStatus(appID string) {
+	apps, err := standalone.ListInstances(daprRuntimePath)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	instance, ok := findInstance(apps, appID)
+	if !ok {
+		print.FailureStatusEvent(os.Stderr, "No running Dapr instance found with app id %q", appID)
+		os.Exit(1)
+	}
+	health := standalone.ProbeInstance(instance, standalone.DefaultHealthProbeTimeout)
+	if print.GetOutputFormat() == print.OutputJSON {
+		if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), health); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	table := print.NewTable(
+		print.TableColumn{Name: "App ID", Key: "appId", Truncate: true},
+		print.TableColumn{Name: "Status", Key: "status"},
+		print.TableColumn{Name: "Uptime", Key: "uptime"},
+		print.TableColumn{Name: "Components", Key: "components"},
+		print.TableColumn{Name: "Error", Key: "error", Truncate: true},
+	)
+	table.AddRow(health.AppID, health.Status, health.Uptime, componentsCount(health), health.Error)
+	if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// outputEnvironmentStatus outputs the status of the runtime binary and the containers set up by init, with the
+// overall verdict.
+func outputEnvironmentStatus(cmd *cobra.Command) {
+	statusContainerRuntime = installContainerRuntime(cmd)
+	if !utils.IsValidContainerRuntime(statusContainerRuntime) {
+		print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+		os.Exit(1)
+	}
+	checkLegacyInstall(os.Stdout, statusContainerRuntime)
+	status, err := standalone.GetEnvironmentStatus(daprRuntimePath, resolveSetting(cmd, standalone.SettingNetwork), statusContainerRuntime)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if print.GetOutputFormat() == print.OutputJSON {
+		if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), status); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	table := print.NewTable(
+		print.TableColumn{Name: "Name", Key: "name"},
+		print.TableColumn{Name: "Kind", Key: "kind"},
+		print.TableColumn{Name: "Status", Key: "status"},
+		print.TableColumn{Name: "Version", Key: "version", Truncate: true},
+		print.TableColumn{Name: "Uptime", Key: "uptime"},
+		print.TableColumn{Name: "Ports", Key: "ports", Truncate: true},
+		print.TableColumn{Name: "Error", Key: "error", Truncate: true},
+	)
+	for _, c := range status.Components {
+		table.AddRow(c.Name, c.Kind, c.Status, c.Version, c.Uptime, strings.Join(c.Ports, ","), c.Error)
+	}
+	if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if m := status.MTLS; m != nil {
+		if m.Expiring() {
+			print.WarningStatusEvent(os.Stdout, "The mTLS certificates in %s expire on %s, regenerate them with `dapr mtls generate --renew`", m.Dir, m.Expiry.Format(time.RFC1123))
+		} else {
+			print.InfoStatusEvent(os.Stdout, "The mTLS certificates in %s expire on %s", m.Dir, m.Expiry.Format(time.RFC1123))
+		}
+	}
+	switch status.ComponentsProvider {
+	case standalone.ComponentsProviderNone:
+		print.InfoStatusEvent(os.Stdout, "Init set up no state store and pubsub components, with --components-provider none")
+	case standalone.ComponentsProviderInMemory:
+		print.InfoStatusEvent(os.Stdout, "The state store and pubsub components are the in-memory ones of the runtime")
+	}
+	for _, c := range status.Components {
+		if c.Drift != "" {
+			print.WarningStatusEvent(os.Stdout, "%s %s, run `dapr upgrade --force` to re-pin it", c.Name, c.Drift)
+		}
+	}
+	if status.Verdict == standalone.EnvironmentHealthy {
+		print.SuccessStatusEvent(os.Stdout, "The Dapr environment in %s (%s) is %s", status.InstallDir, environmentLabel(status), status.Verdict)
+	} else {
+		print.WarningStatusEvent(os.Stdout, "The Dapr environment in %s (%s) is %s, run `dapr init` to set up the missing pieces", status.InstallDir, environmentLabel(status), status.Verdict)
+	}
+}
+
+// environmentLabel returns the platform of the environment, labelled as WSL in a WSL distro.
+func environmentLabel(status *standalone.EnvironmentStatus) string {
+	if status.WSL {
+		return status.Platform + ", WSL"
+	}
+	return status.Platform
+}
+
+// outputLockfileDrift outputs the differences between the lockfile at path and the local environment, and exits
+// with an error if there are any.
+func outputLockfileDrift(cmd *cobra.Command, path string) {
+	lock, err := standalone.ReadLockfile(path)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	drift := standalone.CheckLockfile(lock, daprRuntimePath, resolveSetting(cmd, standalone.SettingNetwork), statusContainerRuntime)
+	if print.GetOutputFormat() == print.OutputJSON {
+		if drift == nil {
+			drift = []standalone.LockfileDrift{}
+		}
+		if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), drift); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	} else if len(drift) > 0 {
+		table := print.NewTable(
+			print.TableColumn{Name: "Item", Key: "item"},
+			print.TableColumn{Name: "Locked", Key: "locked", Truncate: true},
+			print.TableColumn{Name: "Actual", Key: "actual", Truncate: true},
+		)
+		for _, d := range drift {
+			table.AddRow(d.Item, d.Locked, d.Actual)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	if len(drift) > 0 {
+		print.FailureStatusEvent(os.Stderr, "The environment has drifted from the lockfile %s, run `dapr uninstall --all` and `dapr init --from-lockfile %s` to reproduce it", path, path)
+		os.Exit(1)
+	}
+	print.SuccessStatusEvent(os.Stdout, "The environment matches the lockfile %s", path)
+}
+
 func init() {
 	StatusCmd.Flags().BoolVarP(&k8s, "kubernetes", "k", false, "Show the health status of Dapr services on Kubernetes cluster")
+	StatusCmd.Flags().StringVar(&statusCheckLockfile, "check-lockfile", "", "Report the differences between the self-hosted environment and a lockfile written by init, given as --check-lockfile=<path>, "+standalone.DefaultLockfileName+" if no path is given")
+	StatusCmd.Flags().Lookup("check-lockfile").NoOptDefVal = standalone.DefaultLockfileName
+	StatusCmd.Flags().String("network", "", "The Docker network the containers of the local environment run in")
+	StatusCmd.Flags().StringVarP(&statusContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	StatusCmd.Flags().StringVarP(&statusOutputFormat, "output", "o", "", "The output format of the status. Valid values are: json, or wide to not truncate the columns")
 	StatusCmd.Flags().BoolP("help", "h", false, "Print this help message")
-	StatusCmd.MarkFlagRequired("kubernetes")
 	RootCmd.AddCommand(StatusCmd)
 }
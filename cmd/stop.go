@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -25,15 +26,30 @@ import (
 	"github.com/dapr/cli/pkg/standalone"
 )
 
-var stopAppID string
+var (
+	stopAppID       string
+	stopAll         bool
+	stopGracePeriod time.Duration
+	stopServices    bool
+	stopForce       bool
+)
 
 var StopCmd = &cobra.Command{
-	Use:   "stop",
+	Use:   "stop [app-id...]",
 	Short: "Stop Dapr instances and their associated apps. Supported platforms: Self-hosted",
 	Example: `
 # Stop Dapr application
 dapr stop --app-id <ID>
 
+# Stop all the Dapr applications, giving them 30 seconds to exit before they are killed
+dapr stop --all --grace-period 30s
+
+# Stop an application with signals, without asking its sidecar to shut down through the API first
+dapr stop --app-id <ID> --force
+
+# Stop the placement and Redis services installed by init --slim --init-system
+dapr stop --services
+
 # Stop multiple apps by providing a run config file
 dapr stop --run-file dapr.yaml
 
@@ -60,29 +76,67 @@ dapr stop --run-file /path/to/directory
 			}
 			return
 		}
+		if stopServices {
+			if !stopSlimServices() {
+				os.Exit(1)
+			}
+			if stopAppID == "" && len(args) == 0 && !stopAll {
+				return
+			}
+		}
 		if stopAppID != "" {
 			args = append(args, stopAppID)
 		}
-		apps, err := standalone.List()
+		if len(args) == 0 && !stopAll {
+			print.FailureStatusEvent(os.Stderr, "Specify the app ids to stop, or --all")
+			os.Exit(1)
+		}
+		apps, err := standalone.ListInstances(daprRuntimePath)
 		if err != nil {
 			print.FailureStatusEvent(os.Stderr, "failed to get list of apps started by dapr : %s", err)
 			os.Exit(1)
 		}
+		if stopAll {
+			args = args[:0]
+			for _, a := range apps {
+				args = append(args, a.AppID)
+			}
+			if len(args) == 0 {
+				print.InfoStatusEvent(os.Stdout, "No Dapr instances found.")
+				return
+			}
+		}
+		daprDir, err := standalone.GetDaprRuntimePath(daprRuntimePath)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Failed to get Dapr install directory: %v", err)
+			os.Exit(1)
+		}
+
 		cliPIDToNoOfApps := standalone.GetCLIPIDCountMap(apps)
+		failed := false
 		for _, appID := range args {
-			err = standalone.Stop(appID, cliPIDToNoOfApps, apps)
-			if err != nil {
-				print.FailureStatusEvent(os.Stderr, "failed to stop app id %s: %s", appID, err)
-			} else {
-				print.SuccessStatusEvent(os.Stdout, "app stopped successfully: %s", appID)
+			instance, ok := findInstance(apps, appID)
+			if !ok {
+				print.WarningStatusEvent(os.Stdout, "app id %s is not running", appID)
+				continue
+			}
+			if !stopInstance(daprDir, instance, cliPIDToNoOfApps) {
+				failed = true
 			}
 		}
+		if failed {
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	StopCmd.Flags().StringVarP(&stopAppID, "app-id", "a", "", "The application id to be stopped")
 	StopCmd.Flags().StringVarP(&runFilePath, "run-file", "f", "", "Path to the run template file for the list of apps to stop")
+	StopCmd.Flags().BoolVar(&stopAll, "all", false, "Stop all the Dapr applications")
+	StopCmd.Flags().DurationVar(&stopGracePeriod, "grace-period", standalone.DefaultStopGracePeriod, "How long the sidecar has to exit after the shutdown request, and the processes after the termination signal, before they are killed")
+	StopCmd.Flags().BoolVar(&stopForce, "force", false, "Signal the processes right away, without asking the sidecar to shut down through its API first")
+	StopCmd.Flags().BoolVar(&stopServices, "services", false, "Stop the services of slim mode installed by init --init-system")
 	StopCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	RootCmd.AddCommand(StopCmd)
 }
@@ -94,3 +148,59 @@ func executeStopWithRunFile(runFilePath string) error {
 	}
 	return standalone.StopAppsWithRunFile(absFilePath)
 }
+
+// stopSlimServices stops the services installed by init --init-system, reporting the result of each. It returns
+// false if a service couldn't be stopped.
+func stopSlimServices() bool {
+	results, err := standalone.StopServices(daprRuntimePath, stopGracePeriod)
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		return false
+	}
+	ok := true
+	for _, r := range results {
+		if r.Err != nil {
+			ok = false
+			print.FailureStatusEvent(os.Stderr, "service %s: %s", r.Name, r.Err)
+			continue
+		}
+		print.InfoStatusEvent(os.Stdout, "service %s: %s", r.Name, r.Result)
+	}
+	return ok
+}
+
+func findInstance(apps []standalone.ListOutput, appID string) (standalone.ListOutput, bool) {
+	for _, a := range apps {
+		if a.AppID == appID {
+			return a, true
+		}
+	}
+	return standalone.ListOutput{}, false
+}
+
+// stopInstance stops the processes of instance, reporting the result of each, and removes its run record. It
+// returns false if a process couldn't be stopped.
+func stopInstance(daprDir string, instance standalone.ListOutput, cliPIDToNoOfApps map[int]int) bool {
+	ok := true
+	for _, r := range standalone.StopInstance(instance, cliPIDToNoOfApps, stopGracePeriod, stopForce) {
+		if r.Fallback != "" {
+			print.InfoStatusEvent(os.Stdout, "app id %s: %s process %d: %s, falling back to the termination signal", instance.AppID, r.Name, r.PID, r.Fallback)
+		}
+		switch {
+		case r.Err != nil:
+			ok = false
+			print.FailureStatusEvent(os.Stderr, "app id %s: %s", instance.AppID, r.Err)
+		case r.Result == standalone.ProcessKilled:
+			print.WarningStatusEvent(os.Stdout, "app id %s: %s process %d %s", instance.AppID, r.Name, r.PID, r.Result)
+		default:
+			print.InfoStatusEvent(os.Stdout, "app id %s: %s process %d %s", instance.AppID, r.Name, r.PID, r.Result)
+		}
+	}
+	if err := standalone.RemoveRunRecord(daprDir, instance.AppID); err != nil {
+		print.WarningStatusEvent(os.Stdout, "app id %s: %s", instance.AppID, err)
+	}
+	if ok {
+		print.SuccessStatusEvent(os.Stdout, "app stopped successfully: %s", instance.AppID)
+	}
+	return ok
+}
@@ -31,6 +31,9 @@ var (
 	uninstallNamespace        string
 	uninstallKubernetes       bool
 	uninstallAll              bool
+	uninstallWait             bool
+	uninstallDryRun           bool
+	uninstallOutputFormat     string
 	uninstallContainerRuntime string
 )
 
@@ -48,33 +51,81 @@ dapr uninstall --all
 # Uninstall from Kubernetes
 dapr uninstall -k
 
+# Print what an uninstall from the Kubernetes namespace my-dapr removes, without removing it
+dapr uninstall -k -n my-dapr --dry-run
+
 # Uninstall Dapr from non-default install directory
 # This will remove the .dapr directory present in the path <path-to-install-directory>
 dapr uninstall --runtime-path <path-to-install-directory>
 `,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		viper.BindPFlag("network", cmd.Flags().Lookup("network"))
 		viper.BindPFlag("install-path", cmd.Flags().Lookup("install-path"))
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 
+		if uninstallKubernetes && uninstallOutputFormat != "" {
+			print.FailureStatusEvent(os.Stderr, "--output is only valid for self-hosted mode")
+			os.Exit(1)
+		}
+		if !uninstallKubernetes && uninstallDryRun {
+			print.FailureStatusEvent(os.Stderr, "--dry-run is only valid for Kubernetes mode")
+			os.Exit(1)
+		}
+		if err = setOutputFormat(uninstallOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if uninstallAll && !uninstallDryRun {
+			question := "This removes the .dapr directory with your components and configuration, and the Redis, Placement and Zipkin containers. Continue?"
+			if uninstallKubernetes {
+				question = "This removes the Dapr CRDs and all of their resources from the cluster. Continue?"
+			}
+			// Defaults to yes so that scripts relying on --all keep working without a terminal.
+			ok, promptErr := print.Confirm(question, true)
+			if promptErr != nil {
+				print.FailureStatusEvent(os.Stderr, promptErr.Error())
+				os.Exit(1)
+			}
+			if !ok {
+				print.WarningStatusEvent(os.Stdout, "Uninstall cancelled")
+				return
+			}
+		}
+
 		if uninstallKubernetes {
 			if len(strings.TrimSpace(daprRuntimePath)) != 0 {
 				print.FailureStatusEvent(os.Stderr, "--runtime-path is only valid for self-hosted mode")
 				os.Exit(1)
 			}
 
+			if uninstallDryRun {
+				if err = kubernetes.Uninstall(uninstallNamespace, uninstallAll, timeout, true); err != nil {
+					print.FailureStatusEvent(os.Stderr, fmt.Sprintf("Error removing Dapr: %s", err))
+					os.Exit(1)
+				}
+				print.SuccessStatusEvent(os.Stdout, "Nothing was removed, as --dry-run is set")
+				return
+			}
 			print.InfoStatusEvent(os.Stdout, "Removing Dapr from your cluster...")
-			err = kubernetes.Uninstall(uninstallNamespace, uninstallAll, timeout)
+			err = kubernetes.Uninstall(uninstallNamespace, uninstallAll, timeout, false)
 		} else {
+			uninstallContainerRuntime = installContainerRuntime(cmd)
 			if !utils.IsValidContainerRuntime(uninstallContainerRuntime) {
 				print.FailureStatusEvent(os.Stdout, "Invalid container runtime. Supported values are docker and podman.")
 				os.Exit(1)
 			}
 			print.InfoStatusEvent(os.Stdout, "Removing Dapr from your machine...")
-			dockerNetwork := viper.GetString("network")
-			err = standalone.Uninstall(uninstallAll, dockerNetwork, uninstallContainerRuntime, daprRuntimePath)
+			dockerNetwork := resolveSetting(cmd, standalone.SettingNetwork)
+			var report *standalone.UninstallReport
+			report, err = standalone.Uninstall(uninstallAll, dockerNetwork, uninstallContainerRuntime, daprRuntimePath, uninstallWait)
+			if print.GetOutputFormat() == print.OutputJSON {
+				if printErr := utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); printErr != nil {
+					print.FailureStatusEvent(os.Stderr, printErr.Error())
+					os.Exit(1)
+				}
+			}
 		}
 
 		if err != nil {
@@ -88,7 +139,10 @@ dapr uninstall --runtime-path <path-to-install-directory>
 func init() {
 	UninstallCmd.Flags().BoolVarP(&uninstallKubernetes, "kubernetes", "k", false, "Uninstall Dapr from a Kubernetes cluster")
 	UninstallCmd.Flags().UintVarP(&timeout, "timeout", "", 300, "The timeout for the Kubernetes uninstall")
+	UninstallCmd.Flags().StringVarP(&uninstallOutputFormat, "output", "o", "", "The output format for self-hosted mode. Valid values are: json for a report of what was removed")
+	UninstallCmd.Flags().BoolVar(&uninstallWait, "wait-for-lock", false, "Wait for a concurrently running init, uninstall or upgrade to finish instead of failing")
 	UninstallCmd.Flags().BoolVar(&uninstallAll, "all", false, "Remove .dapr directory, Redis, Placement and Zipkin containers on local machine, and CRDs on a Kubernetes cluster")
+	UninstallCmd.Flags().BoolVar(&uninstallDryRun, "dry-run", false, "Print what an uninstall from Kubernetes removes, without removing it")
 	UninstallCmd.Flags().String("network", "", "The Docker network from which to remove the Dapr runtime")
 	UninstallCmd.Flags().StringVarP(&uninstallNamespace, "namespace", "n", "dapr-system", "The Kubernetes namespace to uninstall Dapr from")
 	UninstallCmd.Flags().BoolP("help", "h", false, "Print this help message")
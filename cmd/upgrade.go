@@ -14,36 +14,69 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
 )
 
 var (
 	upgradeRuntimeVersion   string
 	upgradeImageVariant     string
 	upgradeDashboardVersion string
+	upgradeForce            bool
+	upgradeCLI              bool
+	upgradeCLIVersion       string
+	upgradeRollback         bool
+	upgradeWait             bool
+	upgradePubKeyFile       string
+	upgradeSkipSignature    bool
+	upgradeContainerRuntime string
+	upgradeOutputFormat     string
 )
 
 var UpgradeCmd = &cobra.Command{
 	Use:   "upgrade",
-	Short: "Upgrades or downgrades a Dapr control plane installation in a cluster. Supported platforms: Kubernetes",
-	PreRun: func(cmd *cobra.Command, args []string) {
-		viper.BindPFlag("image-registry", cmd.Flags().Lookup("image-registry"))
-	},
+	Short: "Upgrades or downgrades a Dapr installation. Supported platforms: Kubernetes and self-hosted",
 	Example: `
 # Upgrade Dapr in Kubernetes
-dapr upgrade -k
+dapr upgrade -k --runtime-version 1.11.0
+
+# Upgrade the self-hosted runtime and placement container to the latest release
+dapr upgrade
+
+# Upgrade or downgrade the self-hosted runtime to a given version
+dapr upgrade --runtime-version 1.10.0
+
+# Switch back to the self-hosted runtime version active before the last upgrade
+dapr upgrade --rollback
+
+# Upgrade the CLI itself to the latest release
+dapr upgrade --cli
 
 # See more at: https://docs.dapr.io/getting-started/
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		imageRegistryFlag := strings.TrimSpace(viper.GetString("image-registry"))
+		if upgradeCLI {
+			upgradeCLIBinary(cmd)
+			return
+		}
+		if !kubernetesMode {
+			upgradeStandalone(cmd, false)
+			return
+		}
+		if upgradeRuntimeVersion == "" {
+			print.FailureStatusEvent(os.Stderr, "--runtime-version is required to upgrade Dapr in Kubernetes")
+			os.Exit(1)
+		}
+		imageRegistryFlag := resolveSetting(cmd, standalone.SettingImageRegistry)
 		imageRegistryURI := ""
 		var err error
 
@@ -72,22 +105,167 @@ dapr upgrade -k
 		print.SuccessStatusEvent(os.Stdout, "Dapr control plane successfully upgraded to version %s. Make sure your deployments are restarted to pick up the latest sidecar version.", upgradeRuntimeVersion)
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
-		kubernetes.CheckForCertExpiry()
+		if kubernetesMode {
+			kubernetes.CheckForCertExpiry()
+		}
 	},
 }
 
+// upgradeStandalone upgrades, or with downgrade only downgrades, the self-hosted installation and prints the versions
+// before and after.
+func upgradeStandalone(cmd *cobra.Command, downgrade bool) {
+	if err := setOutputFormat(upgradeOutputFormat, print.OutputJSON); err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if upgradeRollback && cmd.Flags().Changed("runtime-version") {
+		print.FailureStatusEvent(os.Stderr, "Only one of --rollback and --runtime-version allowed")
+		os.Exit(1)
+	}
+	upgradeContainerRuntime = installContainerRuntime(cmd)
+	if !utils.IsValidContainerRuntime(upgradeContainerRuntime) {
+		print.FailureStatusEvent(os.Stderr, "Invalid container runtime. Supported values are docker and podman.")
+		os.Exit(1)
+	}
+	imageRegistryURI := resolveSetting(cmd, standalone.SettingImageRegistry)
+	if imageRegistryURI != "" {
+		warnForPrivateRegFeat()
+	}
+	version := upgradeRuntimeVersion
+	if version == "" {
+		version = "latest"
+	}
+
+	opts := standalone.UpgradeOptions{
+		RuntimeVersion:   version,
+		Rollback:         upgradeRollback || (downgrade && upgradeRuntimeVersion == ""),
+		Downgrade:        downgrade,
+		Force:            upgradeForce,
+		DockerNetwork:    resolveSetting(cmd, standalone.SettingNetwork),
+		ImageRegistryURL: imageRegistryURI,
+		ContainerRuntime: upgradeContainerRuntime,
+		ImageVariant:     upgradeImageVariant,
+		DaprInstallPath:  daprRuntimePath,
+		Wait:             upgradeWait,
+		Signature:        upgradeSignatureOptions(),
+	}
+	report, err := standalone.Upgrade(context.Background(), opts)
+	if errors.Is(err, standalone.ErrVersionPruned) {
+		ok, promptErr := print.Confirm(fmt.Sprintf("The %s. Download it again?", err), false)
+		if promptErr != nil {
+			print.FailureStatusEvent(os.Stderr, promptErr.Error())
+			os.Exit(1)
+		}
+		if !ok {
+			print.FailureStatusEvent(os.Stderr, "Failed to upgrade Dapr: %s, use --yes to download it again", err)
+			os.Exit(1)
+		}
+		opts.Redownload = true
+		report, err = standalone.Upgrade(context.Background(), opts)
+	}
+	if errors.Is(err, standalone.ErrVersionAlreadyActive) {
+		print.WarningStatusEvent(os.Stdout, "Nothing to upgrade: %s", err)
+		return
+	}
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, "Failed to upgrade Dapr: %s", err)
+		os.Exit(1)
+	}
+	if print.GetOutputFormat() == print.OutputJSON {
+		if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	summary := print.NewTable(
+		print.TableColumn{Name: "Name", Key: "name"},
+		print.TableColumn{Name: "Before", Key: "before"},
+		print.TableColumn{Name: "After", Key: "after"},
+	)
+	summary.AddRow("runtime", report.PreviousVersion, report.RuntimeVersion)
+	if !report.SlimMode {
+		summary.AddRow("placement image", report.PreviousPlacementImage, report.PlacementImage)
+	}
+	for _, d := range report.Digests {
+		summary.AddRow(d.Container+" digest", d.Previous, d.Digest)
+	}
+	summary.RenderStatus(os.Stdout)
+	if opts.Rollback {
+		print.SuccessStatusEvent(os.Stdout, "Dapr runtime successfully rolled back to version %s. Restart your apps to pick up the sidecar version, or use `dapr upgrade --rollback` to switch back to %s.", report.RuntimeVersion, report.PreviousVersion)
+		return
+	}
+	print.SuccessStatusEvent(os.Stdout, "Dapr runtime successfully switched to version %s. Restart your apps to pick up the new sidecar version, or use `dapr upgrade --rollback` to switch back to %s.", report.RuntimeVersion, report.PreviousVersion)
+}
+
+// upgradeSignatureOptions returns how the archives downloaded by the upgrade are verified, as for init.
+func upgradeSignatureOptions() standalone.SignatureOptions {
+	if upgradeSkipSignature && upgradePubKeyFile != "" {
+		print.FailureStatusEvent(os.Stderr, "--pubkey-file can't be used with --skip-signature-verification")
+		os.Exit(1)
+	}
+	if upgradeSkipSignature {
+		print.WarningStatusEvent(os.Stdout, "--skip-signature-verification is set, the downloaded archives are installed without verifying that they are the ones published with the release")
+	}
+	return standalone.SignatureOptions{PublicKeyFile: upgradePubKeyFile, Skip: upgradeSkipSignature}
+}
+
+// upgradeCLIBinary replaces the running CLI with another release.
+func upgradeCLIBinary(cmd *cobra.Command) {
+	for _, flag := range []string{"kubernetes", "runtime-version", "rollback", "dashboard-version"} {
+		if cmd.Flags().Changed(flag) {
+			print.FailureStatusEvent(os.Stderr, "--%s can't be used with --cli", flag)
+			os.Exit(1)
+		}
+	}
+	if err := setOutputFormat(upgradeOutputFormat, print.OutputJSON); err != nil {
+		print.FailureStatusEvent(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	report, err := standalone.UpgradeCLI(context.Background(), standalone.CLIUpgradeOptions{
+		Version:        upgradeCLIVersion,
+		CurrentVersion: cliVersion,
+		Force:          upgradeForce,
+		Signature:      upgradeSignatureOptions(),
+	})
+	if errors.Is(err, standalone.ErrCLIUpToDate) {
+		print.WarningStatusEvent(os.Stdout, "Nothing to upgrade: %s", err)
+		return
+	}
+	if err != nil {
+		print.FailureStatusEvent(os.Stderr, "Failed to upgrade the CLI: %s", err)
+		os.Exit(1)
+	}
+	if print.GetOutputFormat() == print.OutputJSON {
+		if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), report); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	print.SuccessStatusEvent(os.Stdout, "Dapr CLI %s successfully upgraded to version %s in %s", report.PreviousVersion, report.Version, report.Path)
+}
+
 func init() {
 	UpgradeCmd.Flags().BoolVarP(&kubernetesMode, "kubernetes", "k", false, "Upgrade or downgrade Dapr in a Kubernetes cluster")
 	UpgradeCmd.Flags().UintVarP(&timeout, "timeout", "", 300, "The timeout for the Kubernetes upgrade")
-	UpgradeCmd.Flags().StringVarP(&upgradeRuntimeVersion, "runtime-version", "", "", "The version of the Dapr runtime to upgrade or downgrade to, for example: 1.0.0")
+	UpgradeCmd.Flags().StringVarP(&upgradeRuntimeVersion, "runtime-version", "", "", "The version of the Dapr runtime to upgrade or downgrade to, for example: 1.0.0. Required in Kubernetes, defaults to latest in self-hosted mode")
+	UpgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Reinstall the self-hosted runtime even if the version is already active, and switch back to versions which can't read the placement data of the active one. Also re-pins the recorded image digests of the Redis and Zipkin containers to the images they run")
+	UpgradeCmd.Flags().BoolVar(&upgradeRollback, "rollback", false, "Switch the self-hosted runtime back to the version active before the last upgrade")
+	UpgradeCmd.Flags().BoolVar(&upgradeCLI, "cli", false, "Upgrade the CLI itself instead of the runtime")
+	UpgradeCmd.Flags().StringVar(&upgradeCLIVersion, "cli-version", "latest", "The version of the CLI to upgrade to with --cli, for example: 1.11.0")
+	UpgradeCmd.Flags().BoolVar(&upgradeWait, "wait-for-lock", false, "Wait for a concurrently running init, uninstall or upgrade to finish instead of failing")
+	UpgradeCmd.Flags().StringVar(&upgradePubKeyFile, "pubkey-file", "", "The minisign public key file to verify the signed checksums of the releases with, such as the key of a mirror. The signatures aren't verified without it, as the CLI embeds no release signing key")
+	UpgradeCmd.Flags().BoolVar(&upgradeSkipSignature, "skip-signature-verification", false, "INSECURE: install the downloaded archives without verifying the signatures of their checksums, for releases which don't publish them")
+	UpgradeCmd.Flags().String("network", "", "The Docker network the self-hosted placement container runs in")
+	UpgradeCmd.Flags().StringVarP(&upgradeContainerRuntime, "container-runtime", "", "docker", "The container runtime to use. Supported values are docker (default) and podman")
+	UpgradeCmd.Flags().StringVarP(&upgradeOutputFormat, "output", "o", "", "The output format for self-hosted mode. Valid values are: json for a report of the versions before and after the upgrade")
 	UpgradeCmd.Flags().StringVarP(&upgradeDashboardVersion, "dashboard-version", "", "", "The version of the Dapr dashboard to upgrade or downgrade to, for example: 0.13.0")
 	UpgradeCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	UpgradeCmd.Flags().StringArrayVar(&values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	UpgradeCmd.Flags().String("image-registry", "", "Custom/Private docker image repository URL")
 	UpgradeCmd.Flags().StringVarP(&upgradeImageVariant, "image-variant", "", "", "The image variant to use for the Dapr runtime, for example: mariner")
 
-	UpgradeCmd.MarkFlagRequired("runtime-version")
-	UpgradeCmd.MarkFlagRequired("kubernetes")
-
 	RootCmd.AddCommand(UpgradeCmd)
 }
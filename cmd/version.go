@@ -29,9 +29,12 @@ var output string
 
 var VersionCmd = &cobra.Command{
 	Use:   "version",
-	Short: "Print the Dapr runtime and CLI version",
+	Short: "Print the Dapr runtime and CLI version, and the placement image recorded at init",
 	Example: `
-# Version for Dapr
+# Versions of the CLI and of the installed runtime
+dapr version
+
+# Versions of the CLI and of the installed runtime in JSON format, along with the CLI build and the runtime binary
 dapr version --output json
 `,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -42,10 +45,10 @@ dapr version --output json
 		switch output {
 		case "":
 			// normal output.
-			fmt.Printf(cliVersionTemplateString, daprVer.CliVersion, daprVer.RuntimeVersion)
+			printVersion()
 		case "json":
 			// json output.
-			b, err := json.Marshal(daprVer)
+			b, err := json.Marshal(getDaprVersion())
 			if err != nil {
 				print.FailureStatusEvent(os.Stderr, err.Error())
 				os.Exit(1)
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/standalone"
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+var (
+	versionsOutputFormat string
+	versionsPreRelease   bool
+)
+
+// runtimeRelease is a release version of the runtime, as listed by the versions command.
+type runtimeRelease struct {
+	Version   string `json:"version"`
+	Active    bool   `json:"active"`
+	Installed bool   `json:"installed"`
+}
+
+var VersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "List the published versions of the Dapr runtime, newest first, and which are installed locally. Supported platforms: Self-hosted",
+	Example: `
+# List the runtime versions which can be installed with dapr init or dapr upgrade
+dapr versions
+
+# Include the release candidates
+dapr versions --pre-release
+
+# Get the latest runtime version in a script
+dapr versions -o json | jq -r '.[0].version'
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setOutputFormat(versionsOutputFormat, print.OutputJSON); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		versions, err := cli_ver.ListDaprVersions(versionsPreRelease)
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Failed to list the runtime versions: %s", err)
+			os.Exit(1)
+		}
+		active, installed, err := standalone.InstalledRuntimeVersions(daprRuntimePath)
+		if err != nil {
+			print.WarningStatusEvent(os.Stderr, "Could not tell the installed runtime versions: %s", err)
+		}
+		isInstalled := make(map[string]bool, len(installed)+1)
+		for _, v := range installed {
+			isInstalled[v] = true
+		}
+		isInstalled[active] = active != ""
+
+		releases := make([]runtimeRelease, 0, len(versions))
+		for _, v := range versions {
+			releases = append(releases, runtimeRelease{Version: v, Active: v == active, Installed: isInstalled[v]})
+		}
+		if print.GetOutputFormat() == print.OutputJSON {
+			if err = utils.PrintDetail(os.Stdout, string(print.OutputJSON), releases); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		table := print.NewTable(
+			print.TableColumn{Name: "Version", Key: "version"},
+			print.TableColumn{Name: "Status", Key: "status"},
+		)
+		for _, r := range releases {
+			status := ""
+			switch {
+			case r.Active:
+				status = "active"
+			case r.Installed:
+				status = "installed"
+			}
+			table.AddRow(r.Version, status)
+		}
+		if err = table.Render(os.Stdout, print.GetOutputFormat()); err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	VersionsCmd.Flags().StringVarP(&versionsOutputFormat, "output", "o", "", "The output format of the list. Valid values are: json")
+	VersionsCmd.Flags().BoolVar(&versionsPreRelease, "pre-release", false, "Include the pre-release versions, such as release candidates")
+	VersionsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(VersionsCmd)
+}
@@ -13,7 +13,8 @@
 # limitations under the License.
 # ------------------------------------------------------------
 
-# Dapr CLI location
+# Dapr CLI location, which may be moved to the Homebrew bin directory on macOS if it isn't set
+DAPR_INSTALL_DIR_SET=${DAPR_INSTALL_DIR:+true}
 : ${DAPR_INSTALL_DIR:="/usr/local/bin"}
 
 # sudo is required to copy binary to DAPR_INSTALL_DIR for linux
@@ -41,15 +42,37 @@ getSystemInfo() {
 
     OS=$(echo `uname`|tr '[:upper:]' '[:lower:]')
 
+    # On macOS, the bin directory of Homebrew is writable without sudo, prefer it when /usr/local/bin isn't.
+    if [ "$OS" == "darwin" ] && [ -z "$DAPR_INSTALL_DIR_SET" ] && [ ! -w "$DAPR_INSTALL_DIR" ]; then
+        getHomebrewBinDir
+        if [ -n "$HOMEBREW_BIN_DIR" ]; then
+            echo "$DAPR_INSTALL_DIR is not writable, installing into the Homebrew bin directory $HOMEBREW_BIN_DIR"
+            DAPR_INSTALL_DIR=$HOMEBREW_BIN_DIR
+            DAPR_CLI_FILE="${DAPR_INSTALL_DIR}/${DAPR_CLI_FILENAME}"
+        fi
+    fi
+
     # Most linux distro needs root permission to copy the file to /usr/local/bin
-    if [[ "$OS" == "linux" || "$OS" == "darwin" ]] && [ "$DAPR_INSTALL_DIR" == "/usr/local/bin" ]; then
+    if [[ "$OS" == "linux" || "$OS" == "darwin" || "$OS" == "freebsd" ]] && [ "$DAPR_INSTALL_DIR" == "/usr/local/bin" ] && [ ! -w "$DAPR_INSTALL_DIR" ]; then
         USE_SUDO="true"
     fi
 }
 
+# Sets HOMEBREW_BIN_DIR to the bin directory of Homebrew if it is installed and the directory is writable.
+getHomebrewBinDir() {
+    HOMEBREW_BIN_DIR=""
+    local prefix
+    for prefix in "${HOMEBREW_PREFIX:-}" /opt/homebrew /usr/local; do
+        if [ -n "$prefix" ] && [ -x "$prefix/bin/brew" ] && [ -w "$prefix/bin" ]; then
+            HOMEBREW_BIN_DIR="$prefix/bin"
+            return
+        fi
+    done
+}
+
 verifySupported() {
     releaseTag=$1
-    local supported=(darwin-amd64 linux-amd64 linux-arm linux-arm64)
+    local supported=(darwin-amd64 linux-amd64 linux-arm linux-arm64 freebsd-amd64 freebsd-arm64)
     local current_osarch="${OS}-${ARCH}"
 
     for osarch in "${supported[@]}"; do
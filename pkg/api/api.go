@@ -20,9 +20,18 @@ var (
 
 // Metadata representa information about sidecar.
 type Metadata struct {
-	ID                string                      `json:"id"`
-	ActiveActorsCount []MetadataActiveActorsCount `json:"actors"`
-	Extended          map[string]string           `json:"extended"`
+	ID                   string                      `json:"id"`
+	ActiveActorsCount    []MetadataActiveActorsCount `json:"actors"`
+	Extended             map[string]string           `json:"extended"`
+	RegisteredComponents []MetadataComponent         `json:"components"`
+}
+
+// MetadataComponent is a component loaded by the sidecar.
+type MetadataComponent struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // MetadataActiveActorsCount contain actorType and count of actors each type has.
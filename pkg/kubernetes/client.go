@@ -69,6 +69,24 @@ func getConfig() (*rest.Config, error) {
 	return config, err
 }
 
+// currentContext returns the name of the kubeconfig context the clients connect with, or an empty string if it
+// can't be told.
+func currentContext() string {
+	doOnce.Do(func() {
+		flag.Parse()
+	})
+
+	configLoadRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if *kubeconfig != "" {
+		configLoadRules.ExplicitPath = *kubeconfig
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(configLoadRules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
+
 // GetKubeConfigClient returns the kubeconfig and the client created from the kubeconfig.
 func GetKubeConfigClient() (*rest.Config, *k8s.Clientset, error) {
 	config, err := getConfig()
@@ -0,0 +1,256 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	authorization_v1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+const (
+	// managedByLabel marks the resources the CLI created itself, rather than through the charts.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	cliManagedBy   = "dapr-cli"
+	// installRecordName is the ConfigMap of the install namespace recording what the CLI deployed in it.
+	installRecordName     = "dapr-cli-install"
+	helmReleaseAnnotation = "meta.helm.sh/release-name"
+)
+
+// workloadsReadyInterval is how often the readiness of the deployed workloads is polled.
+var workloadsReadyInterval = 2 * time.Second
+
+// clusterPermission is a permission the user of the kubeconfig context needs to install the charts.
+type clusterPermission struct {
+	verb       string
+	group      string
+	resource   string
+	namespaced bool
+}
+
+// installPermissions are checked before installing, so that a missing one fails the install before anything is
+// deployed, rather than halfway through the charts.
+var installPermissions = []clusterPermission{
+	{verb: "create", group: "apiextensions.k8s.io", resource: "customresourcedefinitions"},
+	{verb: "create", group: "rbac.authorization.k8s.io", resource: "clusterroles"},
+	{verb: "create", group: "rbac.authorization.k8s.io", resource: "clusterrolebindings"},
+	{verb: "create", group: "admissionregistration.k8s.io", resource: "mutatingwebhookconfigurations"},
+	{verb: "create", group: "apps", resource: "deployments", namespaced: true},
+	{verb: "create", group: "apps", resource: "statefulsets", namespaced: true},
+	{verb: "create", resource: "secrets", namespaced: true},
+	{verb: "create", resource: "configmaps", namespaced: true},
+}
+
+func (p clusterPermission) describe(namespace string) string {
+	resource := p.resource
+	if p.group != "" {
+		resource += "." + p.group
+	}
+	if p.namespaced {
+		return fmt.Sprintf("%s %s in namespace %s", p.verb, resource, namespace)
+	}
+	return p.verb + " " + resource
+}
+
+// InstallRecord is what the CLI recorded of the install in a namespace.
+type InstallRecord struct {
+	RuntimeVersion   string
+	DashboardVersion string
+	ImageRegistry    string
+	InstalledAt      time.Time
+}
+
+// describeContext names the kubeconfig context in the errors.
+func describeContext(name string) string {
+	if name == "" {
+		return "the current Kubernetes context"
+	}
+	return "the Kubernetes context " + strconv.Quote(name)
+}
+
+// checkClusterAccess checks that the cluster of kubeContext is reachable, and that its user has the permissions
+// the install in namespace needs.
+func checkClusterAccess(ctx context.Context, client k8s.Interface, kubeContext, namespace string) error {
+	if _, err := client.Discovery().ServerVersion(); err != nil {
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			return clusterError(kubeContext, err)
+		}
+		return fmt.Errorf("can't reach the cluster of %s, check that it is running and that it is the context to install to with `kubectl config current-context`: %w", kubeContext, err)
+	}
+
+	permissions := installPermissions
+	_, err := client.CoreV1().Namespaces().Get(ctx, namespace, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		permissions = append([]clusterPermission{{verb: "create", resource: "namespaces"}}, permissions...)
+	} else if err != nil && !apierrors.IsForbidden(err) {
+		return clusterError(kubeContext, err)
+	}
+
+	denied := []string{}
+	for _, p := range permissions {
+		review := &authorization_v1.SelfSubjectAccessReview{
+			Spec: authorization_v1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorization_v1.ResourceAttributes{
+					Verb:     p.verb,
+					Group:    p.group,
+					Resource: p.resource,
+				},
+			},
+		}
+		if p.namespaced {
+			review.Spec.ResourceAttributes.Namespace = namespace
+		}
+		res, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, meta_v1.CreateOptions{})
+		if err != nil {
+			return clusterError(kubeContext, err)
+		}
+		if !res.Status.Allowed {
+			denied = append(denied, p.describe(namespace))
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("the user of %s isn't allowed to %s, which the install needs. Ask a cluster admin for these permissions, or switch to a context with the cluster-admin role", kubeContext, strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// clusterError explains the errors of the cluster which are about the user of kubeContext.
+func clusterError(kubeContext string, err error) error {
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return fmt.Errorf("the cluster rejects the credentials of %s, log in again or refresh them: %w", kubeContext, err)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("the user of %s isn't allowed to install Dapr, ask a cluster admin for the permissions or switch to a context with the cluster-admin role: %w", kubeContext, err)
+	}
+	return err
+}
+
+// waitForRelease waits for the deployments and stateful sets of the Helm release in namespace to become ready,
+// reporting which ones it still waits for.
+func waitForRelease(ctx context.Context, client k8s.Interface, namespace, releaseName, prettyName string) error {
+	r := print.NewProgressRenderer(os.Stdout)
+	defer r.Stop()
+	step := "Waiting for the " + prettyName + " to become ready"
+	r.Send(print.ProgressEvent{Step: step, Type: print.ProgressStepStarted})
+
+	for {
+		ready, pending, err := releaseWorkloads(ctx, client, namespace, releaseName)
+		if err != nil {
+			r.Send(print.ProgressEvent{Step: step, Type: print.ProgressStepFailed, Message: err.Error()})
+			return fmt.Errorf("error getting the workloads of the %s: %w", prettyName, err)
+		}
+		total := len(ready) + len(pending)
+		if total > 0 && len(pending) == 0 {
+			r.Send(print.ProgressEvent{Step: step, Type: print.ProgressStepSucceeded, Message: fmt.Sprintf("%d/%d ready", total, total)})
+			return nil
+		}
+		message := "waiting for the workloads to be created"
+		if total > 0 {
+			message = fmt.Sprintf("%d/%d ready, waiting for %s", len(ready), total, strings.Join(pending, ", "))
+		}
+		r.Send(print.ProgressEvent{Step: step, Type: print.ProgressStepUpdated, Message: message})
+
+		select {
+		case <-ctx.Done():
+			r.Send(print.ProgressEvent{Step: step, Type: print.ProgressStepFailed, Message: message})
+			if len(pending) == 0 {
+				return fmt.Errorf("timed out waiting for the workloads of the %s in namespace %s to be created", prettyName, namespace)
+			}
+			return fmt.Errorf("timed out waiting for %s in namespace %s to become ready, see `kubectl get pods -n %s`", strings.Join(pending, ", "), namespace, namespace)
+		case <-time.After(workloadsReadyInterval):
+		}
+	}
+}
+
+// releaseWorkloads returns the names of the ready and of the pending deployments and stateful sets of the Helm
+// release in namespace, sorted.
+func releaseWorkloads(ctx context.Context, client k8s.Interface, namespace, releaseName string) ([]string, []string, error) {
+	ready, pending := []string{}, []string{}
+	add := func(meta meta_v1.ObjectMeta, isReady bool) {
+		if meta.Annotations[helmReleaseAnnotation] != releaseName {
+			return
+		}
+		if isReady {
+			ready = append(ready, meta.Name)
+		} else {
+			pending = append(pending, meta.Name)
+		}
+	}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, meta_v1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range deployments.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		add(d.ObjectMeta, d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedReplicas >= replicas && d.Status.AvailableReplicas >= replicas)
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, meta_v1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range statefulSets.Items {
+		replicas := int32(1)
+		if s.Spec.Replicas != nil {
+			replicas = *s.Spec.Replicas
+		}
+		add(s.ObjectMeta, s.Status.ObservedGeneration >= s.Generation && s.Status.ReadyReplicas >= replicas)
+	}
+	sort.Strings(ready)
+	sort.Strings(pending)
+	return ready, pending, nil
+}
+
+// recordInstall records the install in a ConfigMap of namespace, replacing the record of a previous install.
+func recordInstall(ctx context.Context, client k8s.Interface, namespace string, record InstallRecord) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      installRecordName,
+			Namespace: namespace,
+			Labels:    map[string]string{managedByLabel: cliManagedBy},
+		},
+		Data: map[string]string{
+			"runtimeVersion":   record.RuntimeVersion,
+			"dashboardVersion": record.DashboardVersion,
+			"imageRegistry":    record.ImageRegistry,
+			"installedAt":      record.InstalledAt.UTC().Format(time.RFC3339),
+		},
+	}
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	_, err := configMaps.Create(ctx, cm, meta_v1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(ctx, cm, meta_v1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error recording the install in the ConfigMap %s/%s: %w", namespace, installRecordName, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps_v1 "k8s.io/api/apps/v1"
+	authorization_v1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// allowAccess makes the access reviews of client allow everything but the resources in denied.
+func allowAccess(client *fake.Clientset, denied ...string) {
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorization_v1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		for _, d := range denied {
+			if review.Spec.ResourceAttributes.Resource == d {
+				review.Status.Allowed = false
+			}
+		}
+		return true, review, nil
+	})
+}
+
+func newReleaseDeployment(name, release string, replicas, available int32) *apps_v1.Deployment {
+	return &apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        name,
+			Namespace:   "dapr-system",
+			Annotations: map[string]string{helmReleaseAnnotation: release},
+		},
+		Spec:   apps_v1.DeploymentSpec{Replicas: &replicas},
+		Status: apps_v1.DeploymentStatus{UpdatedReplicas: available, AvailableReplicas: available},
+	}
+}
+
+func TestCheckClusterAccess(t *testing.T) {
+	kubeContext := describeContext("kind-dev")
+
+	client := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-system"}})
+	allowAccess(client)
+	assert.NoError(t, checkClusterAccess(context.Background(), client, kubeContext, "dapr-system"))
+
+	client = fake.NewSimpleClientset()
+	allowAccess(client, "namespaces", "clusterroles")
+	err := checkClusterAccess(context.Background(), client, kubeContext, "dapr-system")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `the user of the Kubernetes context "kind-dev" isn't allowed to create namespaces, create clusterroles.rbac.authorization.k8s.io, which the install needs`)
+
+	// The namespace exists, so creating it isn't needed.
+	client = fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-system"}})
+	allowAccess(client, "namespaces", "deployments")
+	err = checkClusterAccess(context.Background(), client, kubeContext, "dapr-system")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "isn't allowed to create deployments.apps in namespace dapr-system,")
+}
+
+func TestClusterError(t *testing.T) {
+	kubeContext := describeContext("")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "dapr-system", nil)
+	assert.ErrorIs(t, clusterError(kubeContext, forbidden), forbidden)
+	assert.Contains(t, clusterError(kubeContext, forbidden).Error(), "the user of the current Kubernetes context isn't allowed to install Dapr")
+	assert.Contains(t, clusterError(kubeContext, apierrors.NewUnauthorized("expired")).Error(), "the cluster rejects the credentials of the current Kubernetes context")
+
+	other := apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "dapr-system")
+	assert.Equal(t, other, clusterError(kubeContext, other))
+}
+
+func TestReleaseWorkloads(t *testing.T) {
+	placementReplicas := int32(3)
+	client := fake.NewSimpleClientset(
+		newReleaseDeployment("dapr-operator", daprReleaseName, 1, 1),
+		newReleaseDeployment("dapr-sentry", daprReleaseName, 1, 0),
+		newReleaseDeployment("dapr-dashboard", dashboardReleaseName, 1, 0),
+		newReleaseDeployment("myapp", "", 1, 0),
+		&apps_v1.StatefulSet{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "dapr-placement-server",
+				Namespace:   "dapr-system",
+				Annotations: map[string]string{helmReleaseAnnotation: daprReleaseName},
+			},
+			Spec:   apps_v1.StatefulSetSpec{Replicas: &placementReplicas},
+			Status: apps_v1.StatefulSetStatus{ReadyReplicas: 2},
+		},
+	)
+
+	ready, pending, err := releaseWorkloads(context.Background(), client, "dapr-system", daprReleaseName)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dapr-operator"}, ready)
+	assert.Equal(t, []string{"dapr-placement-server", "dapr-sentry"}, pending)
+}
+
+func TestWaitForRelease(t *testing.T) {
+	interval := workloadsReadyInterval
+	workloadsReadyInterval = 10 * time.Millisecond
+	t.Cleanup(func() { workloadsReadyInterval = interval })
+
+	client := fake.NewSimpleClientset(newReleaseDeployment("dapr-operator", daprReleaseName, 1, 1))
+	assert.NoError(t, waitForRelease(context.Background(), client, "dapr-system", daprReleaseName, "Dapr control plane"))
+
+	client = fake.NewSimpleClientset(newReleaseDeployment("dapr-sentry", daprReleaseName, 1, 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := waitForRelease(ctx, client, "dapr-system", daprReleaseName, "Dapr control plane")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out waiting for dapr-sentry in namespace dapr-system")
+}
+
+func TestRecordInstall(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	installedAt := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, recordInstall(context.Background(), client, "dapr-system", InstallRecord{RuntimeVersion: "1.11.0", InstalledAt: installedAt}))
+	require.NoError(t, recordInstall(context.Background(), client, "dapr-system", InstallRecord{RuntimeVersion: "1.12.0", DashboardVersion: "0.14.0", InstalledAt: installedAt}))
+
+	cm, err := client.CoreV1().ConfigMaps("dapr-system").Get(context.Background(), installRecordName, meta_v1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, cliManagedBy, cm.Labels[managedByLabel])
+	assert.Equal(t, "1.12.0", cm.Data["runtimeVersion"])
+	assert.Equal(t, "0.14.0", cm.Data["dashboardVersion"])
+	assert.Equal(t, "2023-07-01T10:00:00Z", cm.Data["installedAt"])
+}
@@ -24,10 +24,12 @@ import (
 	helm "helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
 	v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/helm/pkg/strvals"
 
 	"github.com/dapr/cli/pkg/print"
@@ -42,6 +44,23 @@ const (
 	latestVersion        = "latest"
 )
 
+// HelmRepo is the Helm repository the charts are pulled from, along with its credentials.
+type HelmRepo struct {
+	URL      string
+	Username string
+	Password string
+}
+
+var helmRepo = HelmRepo{URL: daprHelmRepo}
+
+// SetHelmRepo sets the Helm repository the charts are pulled from. An empty URL is the repository of the dapr charts.
+func SetHelmRepo(repo HelmRepo) {
+	if repo.URL == "" {
+		repo.URL = daprHelmRepo
+	}
+	helmRepo = repo
+}
+
 type InitConfiguration struct {
 	Version                   string
 	DashboardVersion          string
@@ -56,44 +75,83 @@ type InitConfiguration struct {
 	RootCertificateFilePath   string
 	IssuerCertificateFilePath string
 	IssuerPrivateKeyFilePath  string
+	ValuesFile                string
 }
 
-// Init deploys the Dapr operator using the supplied runtime version.
+// Init deploys the Dapr operator using the supplied runtime version, to the cluster of the current kubeconfig
+// context, and records the deployed versions in the namespace.
 func Init(config InitConfiguration) error {
-	err := installWithConsole(daprReleaseName, config.Version, "Dapr control plane", config)
+	_, client, err := GetKubeConfigClient()
 	if err != nil {
+		return fmt.Errorf("can't connect to a Kubernetes cluster: %w", err)
+	}
+	kubeContext := describeContext(currentContext())
+	if err = checkClusterAccess(context.TODO(), client, kubeContext, config.Namespace); err != nil {
 		return err
 	}
 
+	// The versions are resolved once, so that the ones deployed are the ones recorded.
+	version, err := getVersion(daprReleaseName, config.Version)
+	if err != nil {
+		return err
+	}
+	record := InstallRecord{RuntimeVersion: version, ImageRegistry: config.ImageRegistryURI}
+	err = installWithConsole(client, daprReleaseName, version, "Dapr control plane", config)
+	if err != nil {
+		return clusterError(kubeContext, err)
+	}
+
 	for _, dashboardClusterRole := range []string{"dashboard-reader", "dapr-dashboard"} {
 		// Detect Dapr Dashboard using a cluster-level resource (not dependent on namespace).
 		_, err = utils.RunCmdAndWait("kubectl", "describe", "clusterrole", dashboardClusterRole)
 		if err == nil {
 			// No need to install Dashboard since it is already present.
 			// Charts for versions < 1.11 contain Dashboard already.
-			return nil
+			return writeInstallRecord(client, config.Namespace, record)
 		}
 	}
 
-	err = installWithConsole(dashboardReleaseName, config.DashboardVersion, "Dapr dashboard", config)
+	dashboardVersion, err := getVersion(dashboardReleaseName, config.DashboardVersion)
 	if err != nil {
 		return err
 	}
+	err = installWithConsole(client, dashboardReleaseName, dashboardVersion, "Dapr dashboard", config)
+	if err != nil {
+		return clusterError(kubeContext, err)
+	}
+	record.DashboardVersion = dashboardVersion
+
+	return writeInstallRecord(client, config.Namespace, record)
+}
 
+// writeInstallRecord records the install, which has succeeded even if recording it fails.
+func writeInstallRecord(client k8s.Interface, namespace string, record InstallRecord) error {
+	record.InstalledAt = time.Now()
+	if err := recordInstall(context.TODO(), client, namespace, record); err != nil {
+		print.WarningStatusEvent(os.Stderr, "%s", err)
+	}
 	return nil
 }
 
-func installWithConsole(releaseName string, releaseVersion string, prettyName string, config InitConfiguration) error {
+func installWithConsole(client k8s.Interface, releaseName string, releaseVersion string, prettyName string, config InitConfiguration) error {
 	installSpinning := print.Spinner(os.Stdout, "Deploying the "+prettyName+" with "+releaseVersion+" version to your cluster...")
 	defer installSpinning(print.Failure)
 
+	// The workloads are waited for below instead of by Helm, to report the ones which aren't ready yet.
+	wait := config.Wait
+	config.Wait = false
 	err := install(releaseName, releaseVersion, config)
 	if err != nil {
 		return err
 	}
 	installSpinning(print.Success)
 
-	return nil
+	if !wait {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(config.Timeout)*time.Second)
+	defer cancel()
+	return waitForRelease(ctx, client, config.Namespace, releaseName, prettyName)
 }
 
 func createNamespace(namespace string) error {
@@ -104,7 +162,8 @@ func createNamespace(namespace string) error {
 
 	ns := &v1.Namespace{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name: namespace,
+			Name:   namespace,
+			Labels: map[string]string{managedByLabel: cliManagedBy},
 		},
 	}
 	// try to create the namespace if it doesn't exist. ok to ignore error.
@@ -158,9 +217,9 @@ func locateChartFile(dirPath string) (string, error) {
 
 func daprChart(version string, releaseName string, config *helm.Configuration) (*chart.Chart, error) {
 	pull := helm.NewPullWithOpts(helm.WithConfig(config))
-	pull.RepoURL = utils.GetEnv("DAPR_HELM_REPO_URL", daprHelmRepo)
-	pull.Username = utils.GetEnv("DAPR_HELM_REPO_USERNAME", "")
-	pull.Password = utils.GetEnv("DAPR_HELM_REPO_PASSWORD", "")
+	pull.RepoURL = helmRepo.URL
+	pull.Username = helmRepo.Username
+	pull.Password = helmRepo.Password
 
 	pull.Settings = &cli.EnvSettings{}
 
@@ -190,6 +249,13 @@ func daprChart(version string, releaseName string, config *helm.Configuration) (
 
 func chartValues(config InitConfiguration, version string) (map[string]interface{}, error) {
 	chartVals := map[string]interface{}{}
+	if config.ValuesFile != "" {
+		fileVals, err := chartutil.ReadValuesFile(config.ValuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the values file %s: %w", config.ValuesFile, err)
+		}
+		chartVals = fileVals.AsMap()
+	}
 	err := utils.ValidateImageVariant(config.ImageVariant)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	helm "helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dapr/cli/utils"
+)
+
+// clusterScopedKinds are the kinds of the charts which have no namespace.
+var clusterScopedKinds = []string{
+	"APIService",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"PersistentVolume",
+	"PriorityClass",
+	"StorageClass",
+	"ValidatingWebhookConfiguration",
+}
+
+// renderedManifest is a resource of the rendered charts.
+type renderedManifest struct {
+	kind   string
+	name   string
+	object map[string]interface{}
+}
+
+// Render returns the manifests Init deploys with config, to apply them with another tool instead: the namespace,
+// the CRDs and the resources of the control plane and dashboard charts, with the namespace of the namespaced
+// resources set. They are ordered by kind in the order Helm installs them, and by name, so that the output of the
+// same configuration is always the same. The cluster isn't contacted.
+func Render(config InitConfiguration) (string, error) {
+	version, err := getVersion(daprReleaseName, config.Version)
+	if err != nil {
+		return "", err
+	}
+	controlPlaneChart, err := daprChart(version, daprReleaseName, &helm.Configuration{})
+	if err != nil {
+		return "", err
+	}
+	values, err := chartValues(config, version)
+	if err != nil {
+		return "", err
+	}
+	manifests, err := renderChart(controlPlaneChart, daprReleaseName, config.Namespace, values)
+	if err != nil {
+		return "", err
+	}
+
+	// Charts for versions < 1.11 contain Dashboard already.
+	if !hasDashboard(manifests) {
+		dashboardVersion, err := getVersion(dashboardReleaseName, config.DashboardVersion)
+		if err != nil {
+			return "", err
+		}
+		dashboardChart, err := daprChart(dashboardVersion, dashboardReleaseName, &helm.Configuration{})
+		if err != nil {
+			return "", err
+		}
+		dashboardValues, err := chartValues(config, dashboardVersion)
+		if err != nil {
+			return "", err
+		}
+		dashboardManifests, err := renderChart(dashboardChart, dashboardReleaseName, config.Namespace, dashboardValues)
+		if err != nil {
+			return "", err
+		}
+		manifests = append(manifests, dashboardManifests...)
+	}
+
+	manifests = append(manifests, renderedManifest{
+		kind: "Namespace",
+		name: config.Namespace,
+		object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name":   config.Namespace,
+				"labels": map[string]interface{}{managedByLabel: cliManagedBy},
+			},
+		},
+	})
+	return joinManifests(manifests)
+}
+
+// renderChart renders the resources of chrt, including its CRDs and hooks but not its tests, for the release
+// releaseName in namespace.
+func renderChart(chrt *chart.Chart, releaseName, namespace string, values map[string]interface{}) ([]renderedManifest, error) {
+	installClient := helm.NewInstall(&helm.Configuration{})
+	installClient.DryRun = true
+	installClient.ClientOnly = true
+	installClient.IncludeCRDs = true
+	installClient.ReleaseName = releaseName
+	installClient.Namespace = namespace
+	rel, err := installClient.Run(chrt, values)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering the %s chart: %w", releaseName, err)
+	}
+
+	docs := []string{}
+	for _, doc := range releaseutil.SplitManifests(rel.Manifest) {
+		docs = append(docs, doc)
+	}
+	for _, hook := range rel.Hooks {
+		if !isTestHook(hook) {
+			docs = append(docs, hook.Manifest)
+		}
+	}
+
+	manifests := []renderedManifest{}
+	for _, doc := range docs {
+		object := map[string]interface{}{}
+		if err = yaml.Unmarshal([]byte(doc), &object); err != nil {
+			return nil, fmt.Errorf("error parsing a manifest of the %s chart: %w", releaseName, err)
+		}
+		kind, _ := object["kind"].(string)
+		if kind == "" {
+			continue
+		}
+		metadata, _ := object["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+			object["metadata"] = metadata
+		}
+		name, _ := metadata["name"].(string)
+		if _, ok := metadata["namespace"]; !ok && !utils.Contains(clusterScopedKinds, kind) {
+			metadata["namespace"] = namespace
+		}
+		manifests = append(manifests, renderedManifest{kind: kind, name: name, object: object})
+	}
+	return manifests, nil
+}
+
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDashboard tells whether the manifests of the control plane chart contain the dashboard.
+func hasDashboard(manifests []renderedManifest) bool {
+	for _, m := range manifests {
+		if m.kind == "ClusterRole" && (m.name == "dashboard-reader" || m.name == "dapr-dashboard") {
+			return true
+		}
+	}
+	return false
+}
+
+// joinManifests sorts the manifests by kind, the namespace and the CRDs first as Helm installs them before the
+// charts and then in the install order of Helm, and then by name, and joins them in a multi-document YAML.
+func joinManifests(manifests []renderedManifest) (string, error) {
+	kinds := append([]string{"Namespace", "CustomResourceDefinition"}, releaseutil.InstallOrder...)
+	order := map[string]int{}
+	for i, kind := range kinds {
+		if _, ok := order[kind]; !ok {
+			order[kind] = i
+		}
+	}
+	rank := func(kind string) int {
+		if i, ok := order[kind]; ok {
+			return i
+		}
+		// The kinds unknown to Helm, e.g. the custom resources, go last as it does.
+		return len(kinds)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		if ri, rj := rank(manifests[i].kind), rank(manifests[j].kind); ri != rj {
+			return ri < rj
+		}
+		if manifests[i].kind != manifests[j].kind {
+			return manifests[i].kind < manifests[j].kind
+		}
+		return manifests[i].name < manifests[j].name
+	})
+
+	var sb strings.Builder
+	for _, m := range manifests {
+		b, err := yaml.Marshal(m.object)
+		if err != nil {
+			return "", fmt.Errorf("error writing the manifest of %s %s: %w", m.kind, m.name, err)
+		}
+		sb.WriteString("---\n")
+		sb.Write(b)
+	}
+	return sb.String(), nil
+}
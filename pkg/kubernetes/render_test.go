@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// newRenderChart returns a chart with a CRD, cluster-scoped and namespaced resources, a hook and a test.
+func newRenderChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: daprReleaseName, Version: "1.11.0"},
+		Values:   map[string]interface{}{"replicaCount": 1},
+		Templates: []*chart.File{
+			{Name: "templates/operator.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dapr-operator
+spec:
+  replicas: {{ .Values.replicaCount }}
+  template:
+    spec:
+      containers:
+      - name: dapr-operator
+        image: {{ .Values.global.registry }}/operator:{{ .Values.global.tag }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: dapr-api
+  namespace: {{ .Release.Namespace }}
+`)},
+			{Name: "templates/rbac.yaml", Data: []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: dapr-operator-admin
+`)},
+			{Name: "templates/hook.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dapr-hook
+  annotations:
+    helm.sh/hook: pre-install
+`)},
+			{Name: "templates/test.yaml", Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: dapr-test
+  annotations:
+    helm.sh/hook: test
+`)},
+		},
+		Files: []*chart.File{
+			{Name: "crds/components.yaml", Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: components.dapr.io
+`)},
+		},
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	values, err := chartValues(InitConfiguration{ImageRegistryURI: "registry.example.com/dapr"}, "1.11.0")
+	require.NoError(t, err)
+	manifests, err := renderChart(newRenderChart(), daprReleaseName, "my-dapr", values)
+	require.NoError(t, err)
+
+	out, err := joinManifests(manifests)
+	require.NoError(t, err)
+	docs := strings.Split(strings.TrimPrefix(out, "---\n"), "---\n")
+	kinds := []string{}
+	for _, doc := range docs {
+		var resource struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &resource))
+		assert.NotEmpty(t, resource.APIVersion)
+		assert.NotEmpty(t, resource.Metadata.Name)
+		kinds = append(kinds, resource.Kind+"/"+resource.Metadata.Name+"@"+resource.Metadata.Namespace)
+	}
+	assert.Equal(t, []string{
+		"CustomResourceDefinition/components.dapr.io@",
+		"ConfigMap/dapr-hook@my-dapr",
+		"ClusterRole/dapr-operator-admin@",
+		"Service/dapr-api@my-dapr",
+		"Deployment/dapr-operator@my-dapr",
+	}, kinds)
+	assert.Contains(t, out, "image: registry.example.com/dapr/operator:1.11.0")
+
+	// The output of the same chart doesn't change.
+	for i := 0; i < 5; i++ {
+		manifests, err = renderChart(newRenderChart(), daprReleaseName, "my-dapr", values)
+		require.NoError(t, err)
+		again, err := joinManifests(manifests)
+		require.NoError(t, err)
+		assert.Equal(t, out, again)
+	}
+}
+
+func TestChartValuesFile(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte("replicaCount: 3\nglobal:\n  tag: ignored\n  logAsJson: true\n"), 0o600))
+
+	values, err := chartValues(InitConfiguration{ValuesFile: valuesFile, Args: []string{"replicaCount=2"}}, "1.11.0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), values["replicaCount"])
+	global := values["global"].(map[string]interface{})
+	assert.Equal(t, "1.11.0", global["tag"])
+	assert.Equal(t, true, global["logAsJson"])
+
+	values, err = chartValues(InitConfiguration{ValuesFile: valuesFile}, "1.11.0")
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), values["replicaCount"])
+
+	_, err = chartValues(InitConfiguration{ValuesFile: filepath.Join(t.TempDir(), "missing.yaml")}, "1.11.0")
+	assert.ErrorContains(t, err, "error reading the values file")
+}
+
+func TestHasDashboard(t *testing.T) {
+	assert.True(t, hasDashboard([]renderedManifest{{kind: "ClusterRole", name: "dashboard-reader"}}))
+	assert.False(t, hasDashboard([]renderedManifest{{kind: "ClusterRole", name: "dapr-operator-admin"}}))
+	assert.NotEmpty(t, releaseutil.InstallOrder)
+}
@@ -17,9 +17,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
+	v1 "k8s.io/api/core/v1"
 	k8s "k8s.io/client-go/kubernetes"
 
 	"github.com/dapr/cli/pkg/age"
@@ -35,6 +37,9 @@ var controlPlaneLabels = []string{
 	"dapr-dashboard",
 }
 
+// versionLabel is the label of the version on the pods of the charts.
+const versionLabel = "app.kubernetes.io/version"
+
 type StatusClient struct {
 	client k8s.Interface
 }
@@ -47,6 +52,8 @@ type StatusOutput struct {
 	Status    string `csv:"STATUS"`
 	Replicas  int    `csv:"REPLICAS"`
 	Version   string `csv:"VERSION"`
+	Ready     string `csv:"READY"`
+	Restarts  int    `csv:"RESTARTS"`
 	Age       string `csv:"AGE"`
 	Created   string `csv:"CREATED"`
 }
@@ -87,64 +94,21 @@ func (s *StatusClient) Status() ([]StatusOutput, error) {
 				return
 			}
 
-			if len(p.Items) == 0 {
-				return
-			}
-			pod := p.Items[0]
-			replicas := len(p.Items)
-			image := pod.Spec.Containers[0].Image
-			namespace := pod.GetNamespace()
-			age := age.GetAge(pod.CreationTimestamp.Time)
-			created := pod.CreationTimestamp.Format("2006-01-02 15:04.05")
-
-			// Version is part of the docker image tag which is expected to be present at the end of image uri.
-			// expected format: <image>:<tag>. For example: daprio/dapr:1.8.0.
-			// tag can be either <version> or <version>-<image-variant>. For example: 1.8.0-mariner.
-			version := image[strings.LastIndex(image, ":")+1:]
-			status := ""
-
-			// loop through all replicas and update to Running/Healthy status only if all instances are Running and Healthy.
-			healthy := "False"
-			running := true
-
-			for _, p := range p.Items {
-				if len(p.Status.ContainerStatuses) == 0 {
-					status = string(p.Status.Phase)
-				} else if p.Status.ContainerStatuses[0].State.Waiting != nil {
-					status = fmt.Sprintf("Waiting (%s)", p.Status.ContainerStatuses[0].State.Waiting.Reason)
-				} else if pod.Status.ContainerStatuses[0].State.Terminated != nil {
-					status = "Terminated"
-				}
-
-				if len(p.Status.ContainerStatuses) == 0 ||
-					p.Status.ContainerStatuses[0].State.Running == nil {
-					running = false
-
-					break
-				}
-
-				if p.Status.ContainerStatuses[0].Ready {
-					healthy = "True"
+			// The control plane may be installed in more than one namespace, each gets its own status.
+			byNamespace := map[string][]v1.Pod{}
+			namespaces := []string{}
+			for _, pod := range p.Items {
+				if _, ok := byNamespace[pod.Namespace]; !ok {
+					namespaces = append(namespaces, pod.Namespace)
 				}
+				byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod)
 			}
-
-			if running {
-				status = "Running"
-			}
-
-			s := StatusOutput{
-				Name:      label,
-				Namespace: namespace,
-				Created:   created,
-				Age:       age,
-				Status:    status,
-				Version:   version,
-				Healthy:   healthy,
-				Replicas:  replicas,
-			}
+			sort.Strings(namespaces)
 
 			m.Lock()
-			statuses = append(statuses, s)
+			for _, namespace := range namespaces {
+				statuses = append(statuses, podsStatus(label, byNamespace[namespace]))
+			}
 			m.Unlock()
 		}(lbl)
 	}
@@ -152,3 +116,73 @@ func (s *StatusClient) Status() ([]StatusOutput, error) {
 	wg.Wait()
 	return statuses, nil
 }
+
+// podsStatus returns the status of the control plane service label from its pods, which are in the same namespace.
+func podsStatus(label string, pods []v1.Pod) StatusOutput {
+	pod := pods[0]
+	image := pod.Spec.Containers[0].Image
+
+	// Version is part of the docker image tag which is expected to be present at the end of image uri.
+	// expected format: <image>:<tag>. For example: daprio/dapr:1.8.0.
+	// tag can be either <version> or <version>-<image-variant>. For example: 1.8.0-mariner.
+	// The images pinned by digest have no tag, their version is the one of the version label.
+	version := image[strings.LastIndex(image, ":")+1:]
+	if v := pod.Labels[versionLabel]; v != "" && strings.Contains(image, "@") {
+		version = v
+	}
+	status := ""
+
+	// loop through all replicas and update to Running/Healthy status only if all instances are Running and Healthy.
+	healthy := "False"
+	running := true
+	ready := 0
+	restarts := 0
+
+	for _, p := range pods {
+		for _, c := range p.Status.ContainerStatuses {
+			restarts += int(c.RestartCount)
+		}
+		if len(p.Status.ContainerStatuses) > 0 && p.Status.ContainerStatuses[0].Ready {
+			ready++
+		}
+		if !running {
+			continue
+		}
+
+		if len(p.Status.ContainerStatuses) == 0 {
+			status = string(p.Status.Phase)
+		} else if p.Status.ContainerStatuses[0].State.Waiting != nil {
+			status = fmt.Sprintf("Waiting (%s)", p.Status.ContainerStatuses[0].State.Waiting.Reason)
+		} else if p.Status.ContainerStatuses[0].State.Terminated != nil {
+			status = "Terminated"
+		}
+
+		if len(p.Status.ContainerStatuses) == 0 ||
+			p.Status.ContainerStatuses[0].State.Running == nil {
+			running = false
+
+			continue
+		}
+
+		if p.Status.ContainerStatuses[0].Ready {
+			healthy = "True"
+		}
+	}
+
+	if running {
+		status = "Running"
+	}
+
+	return StatusOutput{
+		Name:      label,
+		Namespace: pod.GetNamespace(),
+		Created:   pod.CreationTimestamp.Format("2006-01-02 15:04.05"),
+		Age:       age.GetAge(pod.CreationTimestamp.Time),
+		Status:    status,
+		Version:   version,
+		Healthy:   healthy,
+		Replicas:  len(pods),
+		Ready:     fmt.Sprintf("%d/%d", ready, len(pods)),
+		Restarts:  restarts,
+	}
+}
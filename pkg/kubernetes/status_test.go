@@ -14,11 +14,13 @@ limitations under the License.
 package kubernetes
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -308,3 +310,33 @@ func TestControlPlaneVersion(t *testing.T) {
 		assert.Equal(t, tc.expectedVersion, stat.Version, "expected version to match")
 	}
 }
+
+func TestStatusNamespaces(t *testing.T) {
+	objects := []runtime.Object{}
+	for i, namespace := range []string{"dapr-system", "dapr-system", "team-a"} {
+		pod := newDaprControlPlanePod(podDetails{
+			name:      fmt.Sprintf("dapr-operator-%d", i),
+			appName:   "dapr-operator",
+			createdAt: time.Now().Add(-20 * time.Minute),
+			state:     v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			ready:     i != 1,
+			imageURI:  "ghcr.io/dapr/operator@sha256:0123",
+		})
+		pod.Namespace = namespace
+		pod.Labels[versionLabel] = "1.11.0"
+		pod.Status.ContainerStatuses[0].RestartCount = int32(i)
+		objects = append(objects, pod)
+	}
+
+	status, err := newTestSimpleK8s(objects...).Status()
+	require.NoError(t, err)
+	require.Len(t, status, 2)
+	assert.Equal(t, "dapr-system", status[0].Namespace)
+	assert.Equal(t, 2, status[0].Replicas)
+	assert.Equal(t, "1/2", status[0].Ready)
+	assert.Equal(t, 1, status[0].Restarts)
+	assert.Equal(t, "1.11.0", status[0].Version)
+	assert.Equal(t, "team-a", status[1].Namespace)
+	assert.Equal(t, "1/1", status[1].Ready)
+	assert.Equal(t, 2, status[1].Restarts)
+}
@@ -14,17 +14,29 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	helm "helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
 
 	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/utils"
 )
 
-// Uninstall removes Dapr from a Kubernetes cluster.
-func Uninstall(namespace string, uninstallAll bool, timeout uint) error {
+// Uninstall removes Dapr from a Kubernetes cluster: the Helm releases in namespace, the install record and the
+// namespace if the CLI created them, and the CRDs with uninstallAll. With dryRun, it only prints what it removes.
+func Uninstall(namespace string, uninstallAll bool, timeout uint, dryRun bool) error {
 	config, err := helmConfig(namespace)
 	if err != nil {
 		return err
@@ -40,6 +52,73 @@ func Uninstall(namespace string, uninstallAll bool, timeout uint) error {
 		return nil
 	}
 
+	_, client, err := GetKubeConfigClient()
+	if err != nil {
+		return fmt.Errorf("can't connect to a Kubernetes cluster: %w", err)
+	}
+	ctx := context.TODO()
+
+	releases := []*release.Release{}
+	for _, name := range []string{dashboardReleaseName, daprReleaseName} {
+		if rel, getErr := helm.NewGet(config).Run(name); getErr == nil && rel != nil {
+			releases = append(releases, rel)
+		}
+	}
+	releaseNames := []string{}
+	for _, rel := range releases {
+		releaseNames = append(releaseNames, rel.Name)
+	}
+
+	// The namespace is only removed if nothing but the releases is left in it.
+	removeNamespace := false
+	ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, meta_v1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && ns.Labels[managedByLabel] == cliManagedBy {
+		remaining, resErr := userResources(ctx, client, namespace, releaseNames)
+		if resErr != nil {
+			return resErr
+		}
+		if len(remaining) == 0 {
+			removeNamespace = true
+		} else {
+			print.InfoStatusEvent(os.Stdout, "Keeping the namespace %s created by init, which holds %s", namespace, strings.Join(remaining, ", "))
+		}
+	}
+
+	// The CRDs are shared by the installs of all the namespaces.
+	others := []string{}
+	if uninstallAll {
+		others, err = otherInstalls(client, namespace)
+		if err != nil {
+			return err
+		}
+		if len(others) > 0 {
+			print.WarningStatusEvent(os.Stdout, "Keeping the CRDs, which the Dapr installs in namespace %s still use", strings.Join(others, ", "))
+		}
+	}
+
+	if dryRun {
+		for _, rel := range releases {
+			for _, r := range releaseResources(rel) {
+				print.InfoStatusEvent(os.Stdout, "Would remove %s of the %s release", r, rel.Name)
+			}
+		}
+		if _, recordErr := client.CoreV1().ConfigMaps(namespace).Get(ctx, installRecordName, meta_v1.GetOptions{}); recordErr == nil {
+			print.InfoStatusEvent(os.Stdout, "Would remove configmap/%s", installRecordName)
+		}
+		if removeNamespace {
+			print.InfoStatusEvent(os.Stdout, "Would remove namespace/%s", namespace)
+		}
+		if uninstallAll && len(others) == 0 {
+			for _, crd := range crdsFullResources {
+				print.InfoStatusEvent(os.Stdout, "Would remove customresourcedefinition/%s", crd)
+			}
+		}
+		return nil
+	}
+
 	uninstallClient := helm.NewUninstall(config)
 	uninstallClient.Timeout = time.Duration(timeout) * time.Second
 
@@ -54,7 +133,18 @@ func Uninstall(namespace string, uninstallAll bool, timeout uint) error {
 		return err
 	}
 
-	if uninstallAll {
+	err = client.CoreV1().ConfigMaps(namespace).Delete(ctx, installRecordName, meta_v1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		print.WarningStatusEvent(os.Stdout, "Failed to remove the install record %s/%s: %s", namespace, installRecordName, err)
+	}
+	if removeNamespace {
+		err = client.CoreV1().Namespaces().Delete(ctx, namespace, meta_v1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			print.WarningStatusEvent(os.Stdout, "Failed to remove the namespace %s: %s", namespace, err)
+		}
+	}
+
+	if uninstallAll && len(others) == 0 {
 		for _, crd := range crdsFullResources {
 			_, err := utils.RunCmdAndWait("kubectl", "delete", "crd", crd)
 			if err != nil {
@@ -65,3 +155,115 @@ func Uninstall(namespace string, uninstallAll bool, timeout uint) error {
 
 	return nil
 }
+
+// releaseResources returns the kind/name of the resources of the manifest of rel, sorted.
+func releaseResources(rel *release.Release) []string {
+	resources := []string{}
+	for _, manifest := range releaseutil.SplitManifests(rel.Manifest) {
+		var resource struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(manifest), &resource); err != nil || resource.Kind == "" {
+			continue
+		}
+		resources = append(resources, strings.ToLower(resource.Kind)+"/"+resource.Metadata.Name)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// userResources returns the kind/name of the resources of namespace which aren't of the Helm releases, the CLI or
+// Kubernetes itself, i.e. the ones a removal of the namespace would take along.
+func userResources(ctx context.Context, client k8s.Interface, namespace string, releaseNames []string) ([]string, error) {
+	ofRelease := func(meta meta_v1.ObjectMeta) bool {
+		return utils.Contains(releaseNames, meta.Annotations[helmReleaseAnnotation])
+	}
+	opts := meta_v1.ListOptions{}
+	resources := []string{}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		if !ofRelease(d.ObjectMeta) {
+			resources = append(resources, "deployment/"+d.Name)
+		}
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets.Items {
+		if !ofRelease(s.ObjectMeta) {
+			resources = append(resources, "statefulset/"+s.Name)
+		}
+	}
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range daemonSets.Items {
+		if !ofRelease(d.ObjectMeta) {
+			resources = append(resources, "daemonset/"+d.Name)
+		}
+	}
+	// The pods of the workloads are covered by them.
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pods.Items {
+		if len(p.OwnerReferences) == 0 {
+			resources = append(resources, "pod/"+p.Name)
+		}
+	}
+	services, err := client.CoreV1().Services(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range services.Items {
+		if !ofRelease(s.ObjectMeta) {
+			resources = append(resources, "service/"+s.Name)
+		}
+	}
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configMaps.Items {
+		if !ofRelease(c.ObjectMeta) && c.Name != installRecordName && c.Name != "kube-root-ca.crt" {
+			resources = append(resources, "configmap/"+c.Name)
+		}
+	}
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range secrets.Items {
+		if !ofRelease(s.ObjectMeta) && s.Type != v1.SecretTypeServiceAccountToken && s.Type != "helm.sh/release.v1" {
+			resources = append(resources, "secret/"+s.Name)
+		}
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// otherInstalls returns the namespaces other than namespace where the control plane runs, sorted.
+func otherInstalls(client k8s.Interface, namespace string) ([]string, error) {
+	pods, err := ListPodsInterface(client, map[string]string{"app": "dapr-operator"})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{}
+	for _, p := range pods.Items {
+		if p.Namespace != namespace && !utils.Contains(namespaces, p.Namespace) {
+			namespaces = append(namespaces, p.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReleaseResources(t *testing.T) {
+	rel := &release.Release{Name: daprReleaseName, Manifest: `---
+# Source: dapr/charts/dapr_operator/templates/dapr_operator_deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dapr-operator
+---
+# Source: dapr/charts/dapr_rbac/templates/dapr_operator.yaml
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: dapr-operator-admin
+`}
+	assert.Equal(t, []string{"clusterrole/dapr-operator-admin", "deployment/dapr-operator"}, releaseResources(rel))
+}
+
+func TestUserResources(t *testing.T) {
+	ofRelease := meta_v1.ObjectMeta{Namespace: "dapr-system", Annotations: map[string]string{helmReleaseAnnotation: daprReleaseName}}
+	operator := newReleaseDeployment("dapr-operator", daprReleaseName, 1, 1)
+	operatorPod := &v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-operator-5d8f", Namespace: "dapr-system", OwnerReferences: []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: "dapr-operator-5d8f"}}}}
+	trustBundle := &v1.Secret{ObjectMeta: ofRelease}
+	trustBundle.Name = "dapr-trust-bundle"
+	helmRelease := &v1.Secret{ObjectMeta: meta_v1.ObjectMeta{Name: "sh.helm.release.v1.dapr.v1", Namespace: "dapr-system"}, Type: "helm.sh/release.v1"}
+	record := &v1.ConfigMap{ObjectMeta: meta_v1.ObjectMeta{Name: installRecordName, Namespace: "dapr-system"}}
+	rootCA := &v1.ConfigMap{ObjectMeta: meta_v1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "dapr-system"}}
+
+	client := fake.NewSimpleClientset(operator, operatorPod, trustBundle, helmRelease, record, rootCA)
+	resources, err := userResources(context.Background(), client, "dapr-system", []string{daprReleaseName})
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+
+	// The workloads of the users are kept along with the namespace.
+	client = fake.NewSimpleClientset(operator, operatorPod,
+		newReleaseDeployment("myapp", "", 1, 1),
+		&v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "debug", Namespace: "dapr-system"}},
+		&v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "myapp", Namespace: "dapr-system"}},
+	)
+	resources, err = userResources(context.Background(), client, "dapr-system", []string{daprReleaseName})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deployment/myapp", "pod/debug", "service/myapp"}, resources)
+}
+
+func TestOtherInstalls(t *testing.T) {
+	newOperatorPod := func(name, namespace string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "dapr-operator"}}}
+	}
+	client := fake.NewSimpleClientset(
+		newOperatorPod("dapr-operator-1", "dapr-system"),
+		newOperatorPod("dapr-operator-2", "team-b"),
+		newOperatorPod("dapr-operator-3", "team-a"),
+		newOperatorPod("dapr-operator-4", "team-a"),
+	)
+	others, err := otherInstalls(client, "dapr-system")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, others)
+}
@@ -16,12 +16,14 @@ package metadata
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 
@@ -29,11 +31,58 @@ import (
 	"github.com/dapr/cli/utils"
 )
 
+var (
+	// ErrUnhealthy is returned by Health when the sidecar answers that it isn't healthy.
+	ErrUnhealthy = errors.New("the sidecar is not healthy")
+	// ErrNotSupported is returned by Get when the sidecar doesn't have the metadata endpoint.
+	ErrNotSupported = errors.New("the sidecar doesn't support the metadata endpoint")
+)
+
 // Get retrieves the metadata of a given app's sidecar.
 func Get(httpPort int, appID, socket string) (*api.Metadata, error) {
-	url := makeMetadataGetEndpoint(httpPort)
+	return GetWithTimeout(httpPort, appID, socket, 0)
+}
+
+// GetWithTimeout retrieves the metadata of a given app's sidecar, failing after timeout unless it is 0.
+func GetWithTimeout(httpPort int, appID, socket string, timeout time.Duration) (*api.Metadata, error) {
+	httpc, err := newClient(appID, socket, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := httpc.Get(makeMetadataGetEndpoint(httpPort))
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+	return handleMetadataResponse(r)
+}
+
+// Health checks the health endpoint of a given app's sidecar, failing after timeout unless it is 0. The error wraps
+// ErrUnhealthy if the sidecar answered.
+func Health(httpPort int, appID, socket string, timeout time.Duration) error {
+	httpc, err := newClient(appID, socket, timeout)
+	if err != nil {
+		return err
+	}
+
+	r, err := httpc.Get(makeHealthEndpoint(httpPort))
+	if err != nil {
+		return err
+	}
 
-	var httpc http.Client
+	defer r.Body.Close()
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return fmt.Errorf("%w: health endpoint returned status %s", ErrUnhealthy, r.Status)
+	}
+	return nil
+}
+
+// newClient returns a client of the sidecar, which connects to its unix domain socket if socket is set: either the
+// socket itself or the directory of the sockets.
+func newClient(appID, socket string, timeout time.Duration) (*http.Client, error) {
+	httpc := &http.Client{Timeout: timeout}
 	if socket != "" {
 		fileInfo, err := os.Stat(socket)
 		if err != nil {
@@ -45,19 +94,13 @@ func Get(httpPort int, appID, socket string) (*api.Metadata, error) {
 		}
 
 		httpc.Transport = &http.Transport{
-			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-				return net.Dial("unix", socket)
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
 			},
 		}
 	}
-
-	r, err := httpc.Get(url)
-	if err != nil {
-		return nil, err
-	}
-
-	defer r.Body.Close()
-	return handleMetadataResponse(r)
+	return httpc, nil
 }
 
 // Put sets one metadata attribute on a given app's sidecar.
@@ -100,6 +143,13 @@ func makeMetadataGetEndpoint(httpPort int) string {
 	return fmt.Sprintf("http://127.0.0.1:%v/v%s/metadata", httpPort, api.RuntimeAPIVersion)
 }
 
+func makeHealthEndpoint(httpPort int) string {
+	if httpPort == 0 {
+		return fmt.Sprintf("http://unix/v%s/healthz", api.RuntimeAPIVersion)
+	}
+	return fmt.Sprintf("http://127.0.0.1:%v/v%s/healthz", httpPort, api.RuntimeAPIVersion)
+}
+
 func makeMetadataPutEndpoint(httpPort int, key string) string {
 	if httpPort == 0 {
 		return fmt.Sprintf("http://unix/v%s/metadata/%s", api.RuntimeAPIVersion, key)
@@ -113,6 +163,13 @@ func handleMetadataResponse(response *http.Response) (*api.Metadata, error) {
 		return nil, err
 	}
 
+	switch {
+	case response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusMethodNotAllowed || response.StatusCode == http.StatusNotImplemented:
+		return nil, fmt.Errorf("%w: status %s", ErrNotSupported, response.Status)
+	case response.StatusCode < 200 || response.StatusCode > 299:
+		return nil, fmt.Errorf("metadata endpoint returned status %s", response.Status)
+	}
+
 	var m api.Metadata
 	err = json.Unmarshal(rb, &m)
 	if err != nil {
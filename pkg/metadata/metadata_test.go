@@ -15,6 +15,9 @@ package metadata
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,3 +29,23 @@ func TestMakeMetadataGetEndpoint(t *testing.T) {
 	actual := makeMetadataGetEndpoint(9999)
 	assert.Equal(t, fmt.Sprintf("http://127.0.0.1:9999/v%s/metadata", api.RuntimeAPIVersion), actual, "expected strings to match")
 }
+
+func TestMakeHealthEndpoint(t *testing.T) {
+	actual := makeHealthEndpoint(9999)
+	assert.Equal(t, fmt.Sprintf("http://127.0.0.1:9999/v%s/healthz", api.RuntimeAPIVersion), actual, "expected strings to match")
+}
+
+func TestHandleMetadataResponse(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed} {
+		_, err := handleMetadataResponse(&http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(strings.NewReader(""))})
+		assert.ErrorIs(t, err, ErrNotSupported)
+	}
+
+	_, err := handleMetadataResponse(&http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error", Body: io.NopCloser(strings.NewReader("{}"))})
+	assert.EqualError(t, err, "metadata endpoint returned status 500 Internal Server Error")
+
+	m, err := handleMetadataResponse(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":"app","components":[{"name":"statestore","type":"state.redis","version":"v1"}]}`))})
+	assert.NoError(t, err)
+	assert.Equal(t, "app", m.ID)
+	assert.Len(t, m.RegisteredComponents, 1)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+var (
+	colorDisabled bool
+
+	// isTerminal reports whether w is a terminal. It is a variable so tests can pretend they write to one.
+	isTerminal = func(w io.Writer) bool {
+		f, ok := w.(*os.File)
+		return ok && term.IsTerminal(int(f.Fd()))
+	}
+)
+
+var (
+	glyphPrefixes = map[logStatus]string{
+		LogSuccess: "✅  ",
+		LogFailure: "❌  ",
+		LogWarning: "⚠  ",
+		LogPending: "⌛  ",
+		LogInfo:    "ℹ️  ",
+		LogDebug:   "[debug] ",
+	}
+	plainPrefixes = map[logStatus]string{
+		LogSuccess: "[ok] ",
+		LogFailure: "[err] ",
+		LogWarning: "[warn] ",
+		LogPending: "[wait] ",
+		LogInfo:    "[info] ",
+		LogDebug:   "[debug] ",
+	}
+)
+
+// DisableColor turns off colors and glyphs for all output, e.g. for --no-color.
+func DisableColor() {
+	colorDisabled = true
+	color.NoColor = true
+}
+
+// decorate reports whether output written to w gets colors and glyphs. They are disabled by --no-color, the
+// NO_COLOR environment variable and when w is not a terminal. Windows consoles don't reliably render the glyphs.
+func decorate(w io.Writer) bool {
+	if colorDisabled || os.Getenv("NO_COLOR") != "" || runtime.GOOS == windowsOS {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// statusPrefix returns the prefix printed before a status event message written to w.
+func statusPrefix(w io.Writer, status logStatus) string {
+	if decorate(w) {
+		return glyphPrefixes[status]
+	}
+	return plainPrefixes[status]
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+// pretendTerminal makes every writer look like a terminal for the duration of the test.
+func pretendTerminal(t *testing.T) {
+	t.Helper()
+	origIsTerminal := isTerminal
+	isTerminal = func(io.Writer) bool { return true }
+	t.Cleanup(func() { isTerminal = origIsTerminal })
+}
+
+func printEvents(w io.Writer) {
+	SuccessStatusEvent(w, "installed")
+	WarningStatusEvent(w, "slow")
+	FailureStatusEvent(w, "failed")
+}
+
+func TestColorPolicy(t *testing.T) {
+	t.Run("terminal gets glyphs", func(t *testing.T) {
+		if runtime.GOOS == windowsOS {
+			t.Skip("glyphs are never used on windows")
+		}
+		pretendTerminal(t)
+		t.Setenv("NO_COLOR", "")
+
+		var buf bytes.Buffer
+		printEvents(&buf)
+		assert.Equal(t, "✅  installed\n⚠  slow\n❌  failed\n", buf.String())
+	})
+
+	t.Run("non terminal gets plain prefixes", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+
+		var buf bytes.Buffer
+		printEvents(&buf)
+		assert.Equal(t, "[ok] installed\n[warn] slow\n[err] failed\n", buf.String())
+	})
+
+	t.Run("NO_COLOR gets plain prefixes", func(t *testing.T) {
+		pretendTerminal(t)
+		t.Setenv("NO_COLOR", "1")
+
+		var buf bytes.Buffer
+		printEvents(&buf)
+		assert.Equal(t, "[ok] installed\n[warn] slow\n[err] failed\n", buf.String())
+	})
+
+	t.Run("--no-color gets plain prefixes", func(t *testing.T) {
+		pretendTerminal(t)
+		t.Setenv("NO_COLOR", "")
+		origNoColor := color.NoColor
+		DisableColor()
+		t.Cleanup(func() {
+			colorDisabled = false
+			color.NoColor = origNoColor
+		})
+
+		var buf bytes.Buffer
+		printEvents(&buf)
+		assert.Equal(t, "[ok] installed\n[warn] slow\n[err] failed\n", buf.String())
+		assert.Equal(t, "text", Yellow("text"))
+	})
+}
+
+func TestProgressRendererColor(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("glyphs are never used on windows")
+	}
+	pretendTerminal(t)
+	t.Setenv("NO_COLOR", "")
+
+	var buf syncBuffer
+	r := newProgressRenderer(&buf, progressModeANSI)
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepSucceeded})
+	r.Stop()
+
+	assert.Contains(t, buf.String(), "✔")
+	assert.Contains(t, buf.String(), "daprd binary")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LogLevel is the minimum severity of the status events which are printed.
+type LogLevel int
+
+const (
+	// LogLevelDebug also prints tracing of the commands run and the URLs requested.
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+var logLevel = LogLevelInfo
+
+// ParseLogLevel parses one of debug, info, warn or error.
+func ParseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info", "":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	default:
+		return LogLevelInfo, fmt.Errorf("invalid log level: %s. Valid values are: debug, info, warn, error", level)
+	}
+}
+
+// SetLogLevel sets the minimum severity of the status events which are printed.
+func SetLogLevel(level LogLevel) {
+	logLevel = level
+}
+
+// EnableQuietMode only prints errors: nothing is printed by a successful command apart from its results.
+func EnableQuietMode() {
+	logLevel = LogLevelError
+}
+
+// IsDebugEnabled reports whether debug status events are printed.
+func IsDebugEnabled() bool {
+	return logLevel <= LogLevelDebug
+}
+
+func levelEnabled(status logStatus) bool {
+	switch status {
+	case LogFailure:
+		return logLevel <= LogLevelError
+	case LogWarning:
+		return logLevel <= LogLevelWarn
+	case LogDebug:
+		return logLevel <= LogLevelDebug
+	default:
+		return logLevel <= LogLevelInfo
+	}
+}
+
+// DebugStatusEvent reports verbose tracing information, which is only printed with the debug log level.
+func DebugStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	printStatus(w, LogDebug, fmt.Sprintf(fmtstr, a...))
+}
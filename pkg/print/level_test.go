@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	for input, expected := range map[string]LogLevel{
+		"debug": LogLevelDebug,
+		"INFO":  LogLevelInfo,
+		"":      LogLevelInfo,
+		"warn":  LogLevelWarn,
+		"error": LogLevelError,
+	} {
+		level, err := ParseLogLevel(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, level, input)
+	}
+
+	_, err := ParseLogLevel("fatal")
+	assert.Error(t, err)
+}
+
+func TestLogLevel(t *testing.T) {
+	t.Cleanup(func() { SetLogLevel(LogLevelInfo) })
+
+	printAll := func() string {
+		var buf bytes.Buffer
+		DebugStatusEvent(&buf, "debug")
+		InfoStatusEvent(&buf, "info")
+		PendingStatusEvent(&buf, "pending")
+		SuccessStatusEvent(&buf, "success")
+		WarningStatusEvent(&buf, "warning")
+		FailureStatusEvent(&buf, "failure")
+		StatusEvent(&buf, LogWarning, "status warning")
+		return buf.String()
+	}
+
+	// A buffer is not a terminal, so plain prefixes are used.
+	var (
+		debug    = "[debug] debug\n"
+		info     = "[info] info\n[wait] pending\n[ok] success\n"
+		warnings = "[warn] warning\n"
+		failure  = "[err] failure\n"
+		status   = "[warn] status warning\n"
+	)
+	tests := []struct {
+		name     string
+		set      func()
+		expected string
+	}{
+		{
+			name:     "debug",
+			set:      func() { SetLogLevel(LogLevelDebug) },
+			expected: debug + info + warnings + failure + status,
+		},
+		{
+			name:     "info",
+			set:      func() { SetLogLevel(LogLevelInfo) },
+			expected: info + warnings + failure + status,
+		},
+		{
+			name:     "warn",
+			set:      func() { SetLogLevel(LogLevelWarn) },
+			expected: warnings + failure + status,
+		},
+		{
+			name:     "quiet",
+			set:      EnableQuietMode,
+			expected: failure,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.set()
+			assert.Equal(t, tc.expected, printAll())
+		})
+	}
+}
+
+func TestProgressRendererQuiet(t *testing.T) {
+	EnableQuietMode()
+	t.Cleanup(func() { SetLogLevel(LogLevelInfo) })
+
+	var buf syncBuffer
+	r := NewProgressRenderer(&buf)
+	sendSteps(r)
+	r.Stop()
+
+	assert.Regexp(t, `^\d\d:\d\d:\d\d \[err\] Redis state store: port 6379 in use\n$`, buf.String())
+}
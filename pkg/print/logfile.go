@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+// PrivateDirMode and PrivateFileMode are the permissions of the directories and the files the CLI writes which only
+// their owner may read, as the logs, which may hold secrets, and the state of the runs and the services.
+const (
+	PrivateDirMode  os.FileMode = 0o700
+	PrivateFileMode os.FileMode = 0o600
+)
+
+// RotatingFile is a log file which is rotated once it grows beyond its maximum size.
+// The previous files are kept as <path>.1 (the most recent) to <path>.<backups>.
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+
+	lock sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it and its directory if needed.
+func OpenRotatingFile(path string, maxSize int64, backups int) (*RotatingFile, error) {
+	err := os.MkdirAll(filepath.Dir(path), PrivateDirMode)
+	if err != nil {
+		return nil, fmt.Errorf("error creating log directory: %w", err)
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, backups: backups}
+	err = r.open()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the current log file.
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, PrivateFileMode)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	r.f = f
+	r.size = fi.Size()
+	return nil
+}
+
+// Write appends p to the log file, rotating it first if p doesn't fit.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups by one and starts a new file. It must be called with the lock held.
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	r.f = nil
+	for i := r.backups; i > 0; i-- {
+		src := r.path
+		if i > 1 {
+			src = fmt.Sprintf("%s.%d", r.path, i-1)
+		}
+		err := os.Rename(src, fmt.Sprintf("%s.%d", r.path, i))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error rotating log file: %w", err)
+		}
+	}
+	if r.backups == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error rotating log file: %w", err)
+		}
+	}
+	return r.open()
+}
+
+// Close closes the log file.
+func (r *RotatingFile) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+var (
+	logFile     io.Writer
+	logFileLock sync.Mutex
+	ansiRegexp  = regexp.MustCompile("\x1b\\[[\\d;]*[A-Za-z]")
+)
+
+// SetLogFile makes all status events, including debug ones, also be written to w regardless of the log level.
+// Pass nil to stop writing to it.
+func SetLogFile(w io.Writer) {
+	logFileLock.Lock()
+	defer logFileLock.Unlock()
+	logFile = w
+}
+
+// LogToFile writes a line to the log file set with SetLogFile, if any, without printing it.
+func LogToFile(status logStatus, fmtstr string, a ...interface{}) {
+	writeLogFile(status, fmt.Sprintf(fmtstr, a...))
+}
+
+func writeLogFile(status logStatus, msg string) {
+	logFileLock.Lock()
+	defer logFileLock.Unlock()
+	if logFile == nil {
+		return
+	}
+	msg = secret.Redact(ansiRegexp.ReplaceAllString(msg, ""))
+	fmt.Fprintf(logFile, "%s [%s] %s\n", time.Now().UTC().Format(time.RFC3339Nano), status, msg)
+}
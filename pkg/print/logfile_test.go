@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "cli.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer f.Close()
+
+	readFile := func(path string) string {
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "fourth\n", readFile(path))
+	assert.Equal(t, "third\n", readFile(path+".1"))
+	assert.Equal(t, "second\n", readFile(path+".2"))
+	assert.NoFileExists(t, path+".3")
+
+	// The logs may hold secrets, only their owner can read them.
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Equal(t, PrivateDirMode, fi.Mode().Perm())
+		fi, err = os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, PrivateFileMode, fi.Mode().Perm())
+	}
+}
+
+func TestRotatingFileLongWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+
+	f, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n", "a line longer than the max\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	// A write is never split across files, even if it is longer than the max.
+	read := func(path string) string {
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, "a line longer than the max\n", read(path))
+	assert.Equal(t, "line4\n", read(path+".1"))
+	assert.Equal(t, "line3\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous run\n"), 0o644))
+
+	f, err := OpenRotatingFile(path, 1024, 1)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("this run\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous run\nthis run\n", string(b))
+}
+
+func TestSetLogFile(t *testing.T) {
+	var logBuf bytes.Buffer
+	SetLogFile(&logBuf)
+	EnableQuietMode()
+	t.Cleanup(func() {
+		SetLogFile(nil)
+		SetLogLevel(LogLevelInfo)
+	})
+
+	var out bytes.Buffer
+	DebugStatusEvent(&out, "running: docker ps")
+	InfoStatusEvent(&out, "installing")
+	FailureStatusEvent(&out, "failed")
+
+	// The console only gets what the log level allows, the log file gets everything.
+	assert.Equal(t, "[err] failed\n", out.String())
+	lines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[0], " [debug] running: docker ps"))
+	assert.True(t, strings.HasSuffix(lines[1], " [info] installing"))
+	assert.True(t, strings.HasSuffix(lines[2], " [failure] failed"))
+}
+
+func TestSecretsRedacted(t *testing.T) {
+	token := secret.New("ghp_printredacted0123")
+	var logBuf bytes.Buffer
+	SetLogFile(&logBuf)
+	SetLogLevel(LogLevelDebug)
+	t.Cleanup(func() {
+		SetLogFile(nil)
+		SetLogLevel(LogLevelInfo)
+	})
+
+	var out bytes.Buffer
+	DebugStatusEvent(&out, "GET https://api.github.com?access_token=%s", token.Reveal())
+	FailureStatusEvent(&out, "bad credentials %s", token.Reveal())
+	LogToFile(LogInfo, "token %s", token.Reveal())
+
+	var progress syncBuffer
+	for _, mode := range []progressMode{progressModeJSON, progressModeJSONLines, progressModePlain} {
+		r := newProgressRenderer(&progress, mode)
+		r.Send(ProgressEvent{Step: "login " + token.Reveal(), Type: ProgressStepFailed, Message: "rejected " + token.Reveal()})
+		r.Stop()
+	}
+
+	table := NewTable(TableColumn{Name: "Token", Key: "token"})
+	table.AddRow(token.Reveal())
+	require.NoError(t, table.Render(&out, OutputJSON))
+
+	for name, s := range map[string]string{"verbose output": out.String(), "log file": logBuf.String(), "progress": progress.String()} {
+		assert.NotContains(t, s, token.Reveal(), name)
+		assert.Contains(t, s, "****(21)", name)
+	}
+}
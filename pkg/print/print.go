@@ -20,12 +20,12 @@ import (
 	"os"
 	"reflect"
 	"regexp"
-	"runtime"
 	"sync"
 	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
+
+	"github.com/dapr/cli/pkg/secret"
 )
 
 const (
@@ -40,6 +40,7 @@ const (
 	LogWarning logStatus = "warning"
 	LogInfo    logStatus = "info"
 	LogPending logStatus = "pending"
+	LogDebug   logStatus = "debug"
 )
 
 type Result bool
@@ -59,6 +60,21 @@ var (
 	WhiteBold = color.New(color.FgWhite, color.Bold).SprintFunc()
 )
 
+// appColors are the colors of the output prefixes of the apps of a run file, so that the apps can be told apart.
+var appColors = []func(a ...interface{}) string{
+	Blue,
+	color.New(color.FgHiMagenta, color.Bold).SprintFunc(),
+	color.New(color.FgHiCyan, color.Bold).SprintFunc(),
+	Green,
+	Yellow,
+	color.New(color.FgHiWhite, color.Bold).SprintFunc(),
+}
+
+// AppColor returns the color of the output prefix of the i-th app of a run file.
+func AppColor(i int) func(a ...interface{}) string {
+	return appColors[i%len(appColors)]
+}
+
 var logAsJSON bool
 
 func EnableJSONFormat() {
@@ -69,116 +85,122 @@ func IsJSONLogEnabled() bool {
 	return logAsJSON
 }
 
+// OutputFormat is the format of the results printed by a command on stdout.
+type OutputFormat string
+
+const (
+	// OutputText is the default human readable output.
+	OutputText OutputFormat = ""
+	// OutputJSON prints the result of the command as a single JSON document.
+	OutputJSON OutputFormat = "json"
+	// OutputJSONLines prints progress events as JSON, one per line.
+	OutputJSONLines OutputFormat = "jsonl"
+	// OutputWide is the human readable output, without truncating tables to the width of the terminal.
+	OutputWide OutputFormat = "wide"
+)
+
+var outputFormat = OutputText
+
+// SetOutputFormat sets the format of the command results. With a machine readable format, status events are
+// written without any decoration.
+func SetOutputFormat(format OutputFormat) {
+	outputFormat = format
+}
+
+// GetOutputFormat returns the format of the command results.
+func GetOutputFormat() OutputFormat {
+	return outputFormat
+}
+
+// isMachineOutput reports whether stdout is reserved for machine readable results.
+func isMachineOutput() bool {
+	return outputFormat == OutputJSON || outputFormat == OutputJSONLines
+}
+
+// statusToStdout makes status output meant for stdout be written there with the human readable output formats.
+var statusToStdout bool
+
+// SetStatusToStdout sets whether status output is written to stdout, as it was before it moved to stderr.
+// It has no effect with machine readable output formats, which always reserve stdout for the results.
+func SetStatusToStdout(enabled bool) {
+	statusToStdout = enabled
+}
+
+// StatusWriter returns the writer that status output meant for w must be written to. Status output meant for
+// stdout is written to stderr, so that stdout only holds the results of commands, like JSON reports or generated
+// files, and can be piped to other commands.
+func StatusWriter(w io.Writer) io.Writer {
+	if w == os.Stdout && (isMachineOutput() || !statusToStdout) {
+		return os.Stderr
+	}
+	return w
+}
+
 // StatusEvent reports a event log with given status.
 func StatusEvent(w io.Writer, status logStatus, fmtstr string, a ...any) {
-	if logAsJSON {
-		logJSON(w, string(status), fmt.Sprintf(fmtstr, a...))
-		return
-	}
-	if (w != os.Stdout && w != os.Stderr) || runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-		return
-	}
-	switch status {
-	case LogSuccess:
-		fmt.Fprintf(w, "✅  %s\n", fmt.Sprintf(fmtstr, a...))
-	case LogFailure:
-		fmt.Fprintf(w, "❌  %s\n", fmt.Sprintf(fmtstr, a...))
-	case LogWarning:
-		fmt.Fprintf(w, "⚠  %s\n", fmt.Sprintf(fmtstr, a...))
-	case LogPending:
-		fmt.Fprintf(w, "⌛  %s\n", fmt.Sprintf(fmtstr, a...))
-	case LogInfo:
-		fmt.Fprintf(w, "ℹ️  %s\n", fmt.Sprintf(fmtstr, a...))
-	default:
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	}
+	printStatus(w, status, fmt.Sprintf(fmtstr, a...))
 }
 
 // SuccessStatusEvent reports on a success event.
 func SuccessStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
-	if logAsJSON {
-		logJSON(w, string(LogSuccess), fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	} else {
-		fmt.Fprintf(w, "✅  %s\n", fmt.Sprintf(fmtstr, a...))
-	}
+	printStatus(w, LogSuccess, fmt.Sprintf(fmtstr, a...))
 }
 
 // FailureStatusEvent reports on a failure event.
 func FailureStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
-	if logAsJSON {
-		logJSON(w, string(LogFailure), fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	} else {
-		fmt.Fprintf(w, "❌  %s\n", fmt.Sprintf(fmtstr, a...))
-	}
+	printStatus(w, LogFailure, fmt.Sprintf(fmtstr, a...))
 }
 
 // WarningStatusEvent reports on a failure event.
 func WarningStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
-	if logAsJSON {
-		logJSON(w, string(LogWarning), fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	} else {
-		fmt.Fprintf(w, "⚠  %s\n", fmt.Sprintf(fmtstr, a...))
-	}
+	printStatus(w, LogWarning, fmt.Sprintf(fmtstr, a...))
 }
 
 // PendingStatusEvent reports on a pending event.
 func PendingStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
-	if logAsJSON {
-		logJSON(w, string(LogPending), fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	} else {
-		fmt.Fprintf(w, "⌛  %s\n", fmt.Sprintf(fmtstr, a...))
-	}
+	printStatus(w, LogPending, fmt.Sprintf(fmtstr, a...))
 }
 
 // InfoStatusEvent reports status information on an event.
 func InfoStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	printStatus(w, LogInfo, fmt.Sprintf(fmtstr, a...))
+}
+
+// printStatus prints a status event according to the log level, output format and color policy.
+func printStatus(w io.Writer, status logStatus, msg string) {
+	msg = secret.Redact(msg)
+	writeLogFile(status, msg)
+	if !levelEnabled(status) {
+		return
+	}
+	w = StatusWriter(w)
 	if logAsJSON {
-		logJSON(w, string(LogInfo), fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
-	} else {
-		fmt.Fprintf(w, "ℹ️  %s\n", fmt.Sprintf(fmtstr, a...))
+		logJSON(w, string(status), msg)
+		return
+	}
+	if isMachineOutput() {
+		fmt.Fprintf(w, "%s\n", msg)
+		return
 	}
+	fmt.Fprintf(w, "%s%s\n", statusPrefix(w, status), msg)
 }
 
+// Spinner renders a single step with the given message until the returned func is called with its result.
 func Spinner(w io.Writer, fmtstr string, a ...interface{}) func(result Result) {
 	msg := fmt.Sprintf(fmtstr, a...)
 	var once sync.Once
-	var s *spinner.Spinner
-
-	if logAsJSON {
-		logJSON(w, string(LogPending), msg)
-	} else if runtime.GOOS == windowsOS {
-		fmt.Fprintf(w, "%s\n", msg)
 
-		return func(Result) {} // Return a dummy func
-	} else {
-		s = spinner.New(spinner.CharSets[0], 100*time.Millisecond)
-		s.Writer = w
-		s.Color("cyan")
-		s.Suffix = fmt.Sprintf("  %s", msg)
-		s.Start()
-	}
+	r := NewProgressRenderer(w)
+	r.Send(ProgressEvent{Step: msg, Type: ProgressStepStarted})
 
 	return func(result Result) {
 		once.Do(func() {
-			if s != nil {
-				s.Stop()
-			}
 			if result {
-				SuccessStatusEvent(w, msg)
+				r.Send(ProgressEvent{Step: msg, Type: ProgressStepSucceeded})
 			} else {
-				FailureStatusEvent(w, msg)
+				r.Send(ProgressEvent{Step: msg, Type: ProgressStepFailed})
 			}
+			r.Stop()
 		})
 	}
 }
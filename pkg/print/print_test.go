@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdio replaces os.Stdout and os.Stderr while f runs and returns what was written to each of them.
+func captureStdio(t *testing.T, f func()) (string, string) {
+	t.Helper()
+
+	read := func(r *os.File, out *string, done chan<- struct{}) {
+		b, _ := io.ReadAll(r)
+		*out = string(b)
+		close(done)
+	}
+
+	stdoutR, stdoutW, err := os.Pipe()
+	require.NoError(t, err)
+	stderrR, stderrW, err := os.Pipe()
+	require.NoError(t, err)
+
+	var stdout, stderr string
+	stdoutDone, stderrDone := make(chan struct{}), make(chan struct{})
+	go read(stdoutR, &stdout, stdoutDone)
+	go read(stderrR, &stderr, stderrDone)
+
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	defer func() {
+		os.Stdout, os.Stderr = origStdout, origStderr
+	}()
+
+	f()
+
+	stdoutW.Close()
+	stderrW.Close()
+	<-stdoutDone
+	<-stderrDone
+	return stdout, stderr
+}
+
+func TestStatusStream(t *testing.T) {
+	t.Cleanup(func() { SetStatusToStdout(false) })
+
+	t.Run("status output goes to stderr and results to stdout", func(t *testing.T) {
+		stdout, stderr := captureStdio(t, func() {
+			InfoStatusEvent(os.Stdout, "installing")
+			r := NewProgressRenderer(os.Stdout)
+			r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepStarted})
+			r.Stop()
+			fmt.Fprintln(os.Stdout, "result")
+		})
+		assert.Equal(t, "result\n", stdout)
+		assert.Contains(t, stderr, "installing")
+		assert.Contains(t, stderr, "daprd binary")
+	})
+
+	t.Run("status output can be written to stdout", func(t *testing.T) {
+		SetStatusToStdout(true)
+		stdout, stderr := captureStdio(t, func() {
+			InfoStatusEvent(os.Stdout, "installing")
+		})
+		assert.Contains(t, stdout, "installing")
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("status output meant for stderr stays there", func(t *testing.T) {
+		SetStatusToStdout(true)
+		stdout, stderr := captureStdio(t, func() {
+			FailureStatusEvent(os.Stderr, "failed")
+		})
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, "failed")
+	})
+}
+
+func TestOutputFormat(t *testing.T) {
+	t.Cleanup(func() {
+		SetOutputFormat(OutputText)
+		SetStatusToStdout(false)
+	})
+	// Machine readable output reserves stdout for the results even if status output is configured to go there.
+	SetStatusToStdout(true)
+
+	for _, format := range []OutputFormat{OutputJSON, OutputJSONLines} {
+		t.Run("status events go to stderr without decoration with "+string(format), func(t *testing.T) {
+			SetOutputFormat(format)
+			stdout, stderr := captureStdio(t, func() {
+				InfoStatusEvent(os.Stdout, "installing")
+				SuccessStatusEvent(os.Stdout, "installed")
+				StatusEvent(os.Stdout, LogWarning, "slow")
+			})
+			assert.Empty(t, stdout)
+			assert.Equal(t, "installing\ninstalled\nslow\n", stderr)
+		})
+	}
+
+	t.Run("progress events are the results with jsonl", func(t *testing.T) {
+		SetOutputFormat(OutputJSONLines)
+		stdout, stderr := captureStdio(t, func() {
+			r := NewProgressRenderer(os.Stdout)
+			r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepStarted})
+			r.Stop()
+		})
+		assert.Contains(t, stdout, `"step":"daprd binary","type":"started"`)
+		assert.Empty(t, stderr)
+	})
+}
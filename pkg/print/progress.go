@@ -0,0 +1,331 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"golang.org/x/term"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+// ProgressEventType is the type of a ProgressEvent.
+type ProgressEventType string
+
+const (
+	ProgressStepStarted   ProgressEventType = "started"
+	ProgressStepUpdated   ProgressEventType = "updated"
+	ProgressStepSucceeded ProgressEventType = "succeeded"
+	ProgressStepFailed    ProgressEventType = "failed"
+	// ProgressStepWarning reports a non-fatal issue of a step, which doesn't change its state.
+	ProgressStepWarning ProgressEventType = "warning"
+)
+
+// ProgressEvent is a structured update on a single step of a long running operation.
+type ProgressEvent struct {
+	Step string            `json:"step"`
+	Type ProgressEventType `json:"type"`
+	// Message is an optional detail, e.g. the download progress of a started step or the error of a failed step.
+	Message string    `json:"msg,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+type progressMode int
+
+const (
+	// progressModePlain appends a line for each step transition, plus periodic lines for steps still running.
+	progressModePlain progressMode = iota
+	// progressModeANSI renders one line per step and updates it in place.
+	progressModeANSI
+	// progressModeJSON logs each event as JSON.
+	progressModeJSON
+	// progressModeJSONLines writes each ProgressEvent as a line of JSON, for --output jsonl.
+	progressModeJSONLines
+)
+
+var (
+	// progressRefreshInterval is how often the lines are redrawn in ANSI mode.
+	progressRefreshInterval = 100 * time.Millisecond
+	// progressHeartbeatInterval is how often a line is printed for each running step in plain mode.
+	progressHeartbeatInterval = 15 * time.Second
+)
+
+type progressStep struct {
+	name     string
+	status   ProgressEventType
+	message  string
+	started  time.Time
+	finished time.Time
+}
+
+// ProgressRenderer renders the progress of a set of steps which may run concurrently.
+// On terminals supporting ANSI escape sequences each step gets its own status line which is updated in place.
+// Otherwise, e.g. when the output is redirected to a file, transitions are appended as timestamped lines.
+type ProgressRenderer struct {
+	w    io.Writer
+	mode progressMode
+
+	lock       sync.Mutex
+	steps      []*progressStep
+	stepsIndex map[string]*progressStep
+	linesDrawn int
+	frame      int
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewProgressRenderer creates a ProgressRenderer writing to w and starts rendering. Stop must be called once all
+// the steps have finished. With --output jsonl the events themselves are the results of the command, otherwise
+// they are status events filtered by the log level. Lines are only updated in place when w is a terminal.
+func NewProgressRenderer(w io.Writer) *ProgressRenderer {
+	if outputFormat == OutputJSONLines && w == os.Stdout {
+		return newProgressRenderer(w, progressModeJSONLines)
+	}
+
+	w = StatusWriter(w)
+	mode := progressModePlain
+	if logAsJSON {
+		mode = progressModeJSON
+	} else if f, ok := w.(*os.File); ok && !isMachineOutput() && logLevel <= LogLevelInfo && term.IsTerminal(int(f.Fd())) && enableVirtualTerminal(f) {
+		mode = progressModeANSI
+	}
+	return newProgressRenderer(w, mode)
+}
+
+func newProgressRenderer(w io.Writer, mode progressMode) *ProgressRenderer {
+	r := &ProgressRenderer{
+		w:          w,
+		mode:       mode,
+		stepsIndex: map[string]*progressStep{},
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+	go r.loop()
+	return r
+}
+
+// Send records the event and renders it.
+func (r *ProgressRenderer) Send(ev ProgressEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	ev.Step, ev.Message = secret.Redact(ev.Step), secret.Redact(ev.Message)
+	logMsg := fmt.Sprintf("step %s %s", ev.Step, ev.Type)
+	if ev.Message != "" {
+		logMsg += ": " + ev.Message
+	}
+	writeLogFile(progressLogStatus(ev.Type), logMsg)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if ev.Type == ProgressStepWarning {
+		r.sendWarning(ev)
+		return
+	}
+
+	step, ok := r.stepsIndex[ev.Step]
+	if !ok {
+		step = &progressStep{name: ev.Step, status: ProgressStepStarted, started: ev.Time}
+		r.steps = append(r.steps, step)
+		r.stepsIndex[ev.Step] = step
+	}
+	switch ev.Type {
+	case ProgressStepStarted:
+		step.status = ProgressStepStarted
+		step.started = ev.Time
+		step.message = ev.Message
+	case ProgressStepUpdated:
+		step.message = ev.Message
+	case ProgressStepSucceeded, ProgressStepFailed:
+		step.status = ev.Type
+		step.message = ev.Message
+		step.finished = ev.Time
+	}
+
+	switch r.mode {
+	case progressModeJSON:
+		logJSON(r.w, string(progressLogStatus(ev.Type)), formatProgressMessage(ev.Step, ev.Message))
+	case progressModeJSONLines:
+		ev.Time = ev.Time.UTC()
+		if b, err := json.Marshal(ev); err == nil {
+			fmt.Fprintf(r.w, "%s\n", b)
+		}
+	case progressModePlain:
+		// Updates are only shown in the periodic lines, they can be too frequent to print each of them.
+		if ev.Type != ProgressStepUpdated {
+			r.printLine(ev.Time, progressLogStatus(ev.Type), formatProgressMessage(ev.Step, ev.Message))
+		}
+	case progressModeANSI:
+		r.draw()
+	}
+}
+
+// sendWarning renders a warning event. Warnings are not drawn with the human readable output formats, where they
+// would get lost among the step lines: the caller collects them and prints them once all the steps have finished.
+// It must be called with the lock held.
+func (r *ProgressRenderer) sendWarning(ev ProgressEvent) {
+	switch r.mode {
+	case progressModeJSON:
+		logJSON(r.w, string(LogWarning), formatProgressMessage(ev.Step, ev.Message))
+	case progressModeJSONLines:
+		ev.Time = ev.Time.UTC()
+		if b, err := json.Marshal(ev); err == nil {
+			fmt.Fprintf(r.w, "%s\n", b)
+		}
+	}
+}
+
+// Stop stops rendering, drawing the final state of all the steps.
+func (r *ProgressRenderer) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		<-r.doneCh
+
+		if r.mode == progressModeANSI {
+			r.lock.Lock()
+			r.draw()
+			r.lock.Unlock()
+		}
+	})
+}
+
+func (r *ProgressRenderer) loop() {
+	defer close(r.doneCh)
+
+	var interval time.Duration
+	switch r.mode {
+	case progressModeANSI:
+		interval = progressRefreshInterval
+	case progressModePlain:
+		interval = progressHeartbeatInterval
+	default:
+		<-r.stopCh
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.lock.Lock()
+			if r.mode == progressModeANSI {
+				r.frame++
+				r.draw()
+			} else {
+				r.heartbeat()
+			}
+			r.lock.Unlock()
+		}
+	}
+}
+
+// draw redraws the lines of all the steps in place. It must be called with the lock held.
+func (r *ProgressRenderer) draw() {
+	var sb strings.Builder
+	if r.linesDrawn > 0 {
+		// Move the cursor back to the first line.
+		fmt.Fprintf(&sb, "\x1b[%dA", r.linesDrawn)
+	}
+	frames := spinner.CharSets[14]
+	now := time.Now()
+	for _, step := range r.steps {
+		// Clear the line before drawing it, it may be shorter than before.
+		sb.WriteString("\x1b[2K\r")
+		switch step.status {
+		case ProgressStepSucceeded:
+			sb.WriteString(r.stepGlyph(LogSuccess) + formatProgressMessage(step.name, step.message))
+		case ProgressStepFailed:
+			sb.WriteString(r.stepGlyph(LogFailure) + formatProgressMessage(step.name, step.message))
+		default:
+			frame := frames[r.frame%len(frames)]
+			if decorate(r.w) {
+				frame = color.New(color.FgCyan).Sprint(frame)
+			}
+			fmt.Fprintf(&sb, "%s  %s (%s)", frame, formatProgressMessage(step.name, step.message), now.Sub(step.started).Truncate(time.Second))
+		}
+		sb.WriteString("\n")
+	}
+	r.linesDrawn = len(r.steps)
+	fmt.Fprint(r.w, sb.String())
+}
+
+// heartbeat prints a line for each step which is still running, so that long steps don't look frozen.
+// It must be called with the lock held.
+func (r *ProgressRenderer) heartbeat() {
+	now := time.Now()
+	for _, step := range r.steps {
+		if step.status != ProgressStepStarted {
+			continue
+		}
+		r.printLine(now, LogPending, fmt.Sprintf("still working on %s... (%s)", formatProgressMessage(step.name, step.message), now.Sub(step.started).Truncate(time.Second)))
+	}
+}
+
+// printLine appends a timestamped line in plain mode. It must be called with the lock held.
+func (r *ProgressRenderer) printLine(t time.Time, status logStatus, msg string) {
+	if !levelEnabled(status) {
+		return
+	}
+	prefix := ""
+	if !isMachineOutput() {
+		prefix = statusPrefix(r.w, status)
+	}
+	fmt.Fprintf(r.w, "%s %s%s\n", t.Format("15:04:05"), prefix, msg)
+}
+
+// stepGlyph returns the mark drawn before a finished step in ANSI mode.
+func (r *ProgressRenderer) stepGlyph(status logStatus) string {
+	if !decorate(r.w) {
+		return statusPrefix(r.w, status)
+	}
+	if status == LogSuccess {
+		return color.New(color.FgHiGreen).Sprint("✔") + "  "
+	}
+	return color.New(color.FgHiRed).Sprint("✖") + "  "
+}
+
+func formatProgressMessage(step, message string) string {
+	if message == "" {
+		return step
+	}
+	return step + ": " + message
+}
+
+func progressLogStatus(t ProgressEventType) logStatus {
+	switch t {
+	case ProgressStepSucceeded:
+		return LogSuccess
+	case ProgressStepFailed:
+		return LogFailure
+	case ProgressStepWarning:
+		return LogWarning
+	default:
+		return LogPending
+	}
+}
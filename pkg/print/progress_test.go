@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer which can be written by the renderer loop while the test reads it.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func sendSteps(r *ProgressRenderer) {
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepStarted})
+	r.Send(ProgressEvent{Step: "Redis state store", Type: ProgressStepStarted})
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepUpdated, Message: "downloading"})
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepSucceeded})
+	r.Send(ProgressEvent{Step: "Redis state store", Type: ProgressStepFailed, Message: "port 6379 in use"})
+}
+
+func TestProgressRendererPlain(t *testing.T) {
+	var buf syncBuffer
+	r := NewProgressRenderer(&buf)
+	sendSteps(r)
+	r.Stop()
+
+	// Not a terminal, so transitions are appended as timestamped lines without escape sequences.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	for i, expected := range []string{
+		"[wait] daprd binary",
+		"[wait] Redis state store",
+		"[ok] daprd binary",
+		"[err] Redis state store: port 6379 in use",
+	} {
+		assert.Regexp(t, `^\d\d:\d\d:\d\d `+regexp.QuoteMeta(expected)+`$`, lines[i])
+	}
+}
+
+func TestProgressRendererPlainHeartbeat(t *testing.T) {
+	defaultInterval := progressHeartbeatInterval
+	progressHeartbeatInterval = 10 * time.Millisecond
+	t.Cleanup(func() { progressHeartbeatInterval = defaultInterval })
+
+	var buf syncBuffer
+	r := newProgressRenderer(&buf, progressModePlain)
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepStarted})
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepUpdated, Message: "downloading"})
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "still working on daprd binary: downloading... (")
+	}, time.Second, 5*time.Millisecond)
+
+	r.Send(ProgressEvent{Step: "daprd binary", Type: ProgressStepSucceeded})
+	r.Stop()
+}
+
+func TestProgressRendererANSI(t *testing.T) {
+	var buf syncBuffer
+	r := newProgressRenderer(&buf, progressModeANSI)
+	sendSteps(r)
+	r.Stop()
+
+	out := buf.String()
+	// Lines are redrawn in place by moving the cursor up.
+	assert.Contains(t, out, "\x1b[2A")
+	assert.Contains(t, out, "\x1b[2K")
+
+	// The last redraw holds the final state of each step.
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.GreaterOrEqual(t, len(lines), 2)
+	assert.True(t, strings.HasSuffix(lines[len(lines)-2], "[ok] daprd binary"))
+	assert.True(t, strings.HasSuffix(lines[len(lines)-1], "[err] Redis state store: port 6379 in use"))
+}
+
+func TestProgressRendererJSON(t *testing.T) {
+	var buf syncBuffer
+	r := newProgressRenderer(&buf, progressModeJSON)
+	sendSteps(r)
+	r.Stop()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+
+	var last struct {
+		Status  string `json:"status"`
+		Message string `json:"msg"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[4]), &last))
+	assert.Equal(t, string(LogFailure), last.Status)
+	assert.Equal(t, "Redis state store: port 6379 in use", last.Message)
+}
+
+func TestProgressRendererWarnings(t *testing.T) {
+	warning := ProgressEvent{Step: "Redis state store", Type: ProgressStepWarning, Message: "reusing container"}
+
+	t.Run("not drawn with plain output", func(t *testing.T) {
+		var buf syncBuffer
+		r := newProgressRenderer(&buf, progressModePlain)
+		r.Send(ProgressEvent{Step: "Redis state store", Type: ProgressStepStarted})
+		r.Send(warning)
+		r.Send(ProgressEvent{Step: "Redis state store", Type: ProgressStepSucceeded})
+		r.Stop()
+
+		assert.NotContains(t, buf.String(), "reusing container")
+		assert.Contains(t, buf.String(), "[ok] Redis state store\n")
+	})
+
+	t.Run("written with jsonl output", func(t *testing.T) {
+		var buf syncBuffer
+		r := newProgressRenderer(&buf, progressModeJSONLines)
+		r.Send(warning)
+		r.Stop()
+
+		var ev ProgressEvent
+		require.NoError(t, json.Unmarshal([]byte(buf.String()), &ev))
+		assert.Equal(t, ProgressStepWarning, ev.Type)
+		assert.Equal(t, "reusing container", ev.Message)
+	})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	assumeYes bool
+
+	promptLock sync.Mutex
+	// promptInput and promptOutput are variables so tests can answer prompts.
+	promptInput  io.Reader = os.Stdin
+	promptOutput io.Writer = os.Stderr
+	// promptInteractive reports whether the user can be asked questions.
+	promptInteractive = func() bool { return isTerminal(os.Stdin) }
+)
+
+// SetAssumeYes makes every prompt take its affirmative answer without asking.
+func SetAssumeYes(yes bool) {
+	assumeYes = yes
+}
+
+// Confirm asks a yes/no question when stdin is a terminal and returns the answer. It returns defaultYes when the
+// user can't be asked or answers with an empty line, and true when SetAssumeYes has been called.
+func Confirm(question string, defaultYes bool) (bool, error) {
+	if assumeYes {
+		LogToFile(LogInfo, "%s: yes (assumed)", question)
+		return true, nil
+	}
+	if !promptInteractive() {
+		LogToFile(LogInfo, "%s: %t (default, not a terminal)", question, defaultYes)
+		return defaultYes, nil
+	}
+
+	hint := "[y/N]"
+	if defaultYes {
+		hint = "[Y/n]"
+	}
+	answer, err := ask(fmt.Sprintf("%s %s ", question, hint), func(answer string) (bool, bool) {
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultYes, true
+		case "y", "yes":
+			return true, true
+		case "n", "no":
+			return false, true
+		default:
+			return false, false
+		}
+	})
+	if err != nil {
+		return defaultYes, err
+	}
+	LogToFile(LogInfo, "%s: %t", question, answer)
+	return answer, nil
+}
+
+// Choose asks the user to pick one of options when stdin is a terminal and returns its index. It returns
+// defaultOption when the user can't be asked, answers with an empty line or SetAssumeYes has been called.
+// The user can answer with the number or the text of an option.
+func Choose(question string, options []string, defaultOption int) (int, error) {
+	if defaultOption < 0 || defaultOption >= len(options) {
+		return 0, fmt.Errorf("invalid default option %d for %d options", defaultOption, len(options))
+	}
+	if assumeYes || !promptInteractive() {
+		LogToFile(LogInfo, "%s: %s (default)", question, options[defaultOption])
+		return defaultOption, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", question)
+	for i, option := range options {
+		fmt.Fprintf(&sb, "  %d) %s\n", i+1, option)
+	}
+	fmt.Fprintf(&sb, "Choose an option [%d]: ", defaultOption+1)
+	choice, err := ask(sb.String(), func(answer string) (int, bool) {
+		if answer == "" {
+			return defaultOption, true
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, true
+		}
+		for i, option := range options {
+			if strings.EqualFold(answer, option) {
+				return i, true
+			}
+		}
+		return 0, false
+	})
+	if err != nil {
+		return defaultOption, err
+	}
+	LogToFile(LogInfo, "%s: %s", question, options[choice])
+	return choice, nil
+}
+
+// ask writes prompt and reads lines until parse accepts one. The end of the input counts as an empty line.
+func ask[T any](prompt string, parse func(answer string) (T, bool)) (T, error) {
+	promptLock.Lock()
+	defer promptLock.Unlock()
+
+	reader := bufio.NewReader(promptInput)
+	for {
+		fmt.Fprint(promptOutput, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			var zero T
+			return zero, fmt.Errorf("error reading answer: %w", err)
+		}
+		v, ok := parse(strings.TrimSpace(line))
+		if ok {
+			return v, nil
+		}
+		if errors.Is(err, io.EOF) {
+			v, _ = parse("")
+			return v, nil
+		}
+		fmt.Fprintf(promptOutput, "Invalid answer: %s\n", strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answerPrompts makes prompts read input, as if typed in a terminal when interactive is true, and returns
+// the buffer the prompts are written to.
+func answerPrompts(t *testing.T, interactive bool, input string) *bytes.Buffer {
+	t.Helper()
+	defaultInput, defaultOutput, defaultInteractive := promptInput, promptOutput, promptInteractive
+	var out bytes.Buffer
+	promptInput = strings.NewReader(input)
+	promptOutput = &out
+	promptInteractive = func() bool { return interactive }
+	t.Cleanup(func() {
+		promptInput, promptOutput, promptInteractive = defaultInput, defaultOutput, defaultInteractive
+		SetAssumeYes(false)
+	})
+	return &out
+}
+
+func TestConfirm(t *testing.T) {
+	t.Run("terminal", func(t *testing.T) {
+		for _, tc := range []struct {
+			input      string
+			defaultYes bool
+			expected   bool
+		}{
+			{"y\n", false, true},
+			{"YES\n", false, true},
+			{"n\n", true, false},
+			{"\n", true, true},
+			{"\n", false, false},
+			{"", true, true},
+		} {
+			out := answerPrompts(t, true, tc.input)
+			ok, err := Confirm("Remove everything?", tc.defaultYes)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ok, "input %q", tc.input)
+			assert.Contains(t, out.String(), "Remove everything?")
+		}
+	})
+
+	t.Run("terminal asks again on invalid answers", func(t *testing.T) {
+		out := answerPrompts(t, true, "maybe\ny\n")
+		ok, err := Confirm("Remove everything?", false)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 2, strings.Count(out.String(), "Remove everything? [y/N]"))
+		assert.Contains(t, out.String(), "Invalid answer: maybe")
+	})
+
+	t.Run("not a terminal returns the default", func(t *testing.T) {
+		out := answerPrompts(t, false, "y\n")
+		ok, err := Confirm("Remove everything?", false)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("assume yes", func(t *testing.T) {
+		out := answerPrompts(t, true, "n\n")
+		SetAssumeYes(true)
+		ok, err := Confirm("Remove everything?", false)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestChoose(t *testing.T) {
+	options := []string{"docker", "podman"}
+
+	t.Run("terminal", func(t *testing.T) {
+		for _, tc := range []struct {
+			input    string
+			expected int
+		}{
+			{"2\n", 1},
+			{"podman\n", 1},
+			{"\n", 0},
+			{"3\nDocker\n", 0},
+		} {
+			out := answerPrompts(t, true, tc.input)
+			choice, err := Choose("Container runtime?", options, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, choice, "input %q", tc.input)
+			assert.Contains(t, out.String(), "  2) podman\n")
+		}
+	})
+
+	t.Run("not a terminal returns the default", func(t *testing.T) {
+		answerPrompts(t, false, "1\n")
+		choice, err := Choose("Container runtime?", options, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, choice)
+	})
+
+	t.Run("assume yes returns the default", func(t *testing.T) {
+		answerPrompts(t, true, "1\n")
+		SetAssumeYes(true)
+		choice, err := Choose("Container runtime?", options, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, choice)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		_, err := Choose("Container runtime?", options, 2)
+		assert.Error(t, err)
+	})
+}
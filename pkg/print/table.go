@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+const (
+	tableColumnSeparator = "  "
+	// tableMinColumnWidth is the width columns are never truncated below.
+	tableMinColumnWidth = 8
+	tableEllipsis       = "..."
+)
+
+// terminalWidth returns the width of the terminal w writes to, or 0 if w is not a terminal.
+// It is a variable so tests can pretend they write to a terminal of a given width.
+var terminalWidth = func(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(w) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// TableColumn is a column of a Table.
+type TableColumn struct {
+	// Name is printed uppercased as the header of the column.
+	Name string
+	// Key is the name of the field holding the values of the column in JSON output.
+	Key string
+	// Truncate allows the values of the column to be truncated to fit in narrow terminals.
+	Truncate bool
+}
+
+// Table renders rows of values as aligned columns, or as JSON.
+type Table struct {
+	columns []TableColumn
+	rows    [][]string
+}
+
+// NewTable creates a table with the given columns.
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow appends a row. Missing values are left empty and extra values are ignored, the secrets are redacted.
+func (t *Table) AddRow(values ...string) {
+	row := make([]string, len(t.columns))
+	copy(row, values)
+	for i := range row {
+		row[i] = secret.Redact(row[i])
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Render writes the table to w in the given format. Columns are truncated to fit in the width of the terminal
+// unless the format is OutputWide, and the rows are written as a JSON array of objects if it is OutputJSON.
+func (t *Table) Render(w io.Writer, format OutputFormat) error {
+	switch format {
+	case OutputJSON:
+		return t.renderJSON(w)
+	case OutputWide:
+		return t.renderText(w, 0)
+	default:
+		return t.renderText(w, terminalWidth(w))
+	}
+}
+
+// RenderStatus writes the table as status output, which is subject to the log level and written to stderr
+// with machine readable output formats.
+func (t *Table) RenderStatus(w io.Writer) {
+	if !levelEnabled(LogInfo) {
+		return
+	}
+	format := OutputText
+	if outputFormat == OutputWide {
+		format = OutputWide
+	}
+	// Writing status output is best effort, like status events.
+	_ = t.Render(StatusWriter(w), format)
+}
+
+func (t *Table) renderJSON(w io.Writer) error {
+	rows := make([]map[string]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		obj := make(map[string]string, len(t.columns))
+		for i, col := range t.columns {
+			obj[col.Key] = row[i]
+		}
+		rows = append(rows, obj)
+	}
+	b, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// renderText writes the table as aligned columns, truncating columns so the lines fit in maxWidth if it is not 0.
+func (t *Table) renderText(w io.Writer, maxWidth int) error {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = utf8.RuneCountInString(col.Name)
+		for _, row := range t.rows {
+			if n := utf8.RuneCountInString(row[i]); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	if maxWidth > 0 {
+		t.shrink(widths, maxWidth)
+	}
+
+	var sb strings.Builder
+	writeLine := func(values []string) {
+		var line strings.Builder
+		for i, v := range values {
+			if i > 0 {
+				line.WriteString(tableColumnSeparator)
+			}
+			v = truncate(v, widths[i])
+			line.WriteString(v)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v)))
+		}
+		sb.WriteString(strings.TrimRight(line.String(), " "))
+		sb.WriteString("\n")
+	}
+
+	header := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		header[i] = strings.ToUpper(col.Name)
+	}
+	writeLine(header)
+	for _, row := range t.rows {
+		writeLine(row)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// shrink reduces the widths of the truncatable columns, widest first, until the lines fit in maxWidth or
+// all of them are at their minimum width.
+func (t *Table) shrink(widths []int, maxWidth int) {
+	total := func() int {
+		sum := len(tableColumnSeparator) * (len(widths) - 1)
+		for _, width := range widths {
+			sum += width
+		}
+		return sum
+	}
+
+	for total() > maxWidth {
+		widest := -1
+		for i, col := range t.columns {
+			if col.Truncate && widths[i] > tableMinColumnWidth && (widest == -1 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest == -1 {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-len(tableEllipsis)]) + tableEllipsis
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package print
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTable() *Table {
+	table := NewTable(
+		TableColumn{Name: "Name", Key: "name"},
+		TableColumn{Name: "Status", Key: "status"},
+		TableColumn{Name: "Location", Key: "location", Truncate: true},
+	)
+	table.AddRow("daprd", "installed", "/home/user/.dapr/bin")
+	table.AddRow("dapr_placement", "running")
+	return table
+}
+
+func pretendTerminalWidth(t *testing.T, width int) {
+	t.Helper()
+	defaultTerminalWidth := terminalWidth
+	terminalWidth = func(io.Writer) int { return width }
+	t.Cleanup(func() { terminalWidth = defaultTerminalWidth })
+}
+
+func TestTableRender(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestTable().Render(&buf, OutputText))
+	assert.Equal(t, ""+
+		"NAME            STATUS     LOCATION\n"+
+		"daprd           installed  /home/user/.dapr/bin\n"+
+		"dapr_placement  running\n", buf.String())
+}
+
+func TestTableRenderTruncate(t *testing.T) {
+	pretendTerminalWidth(t, 40)
+
+	t.Run("truncates to the terminal width", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newTestTable().Render(&buf, OutputText))
+		assert.Equal(t, ""+
+			"NAME            STATUS     LOCATION\n"+
+			"daprd           installed  /home/user...\n"+
+			"dapr_placement  running\n", buf.String())
+	})
+
+	t.Run("wide does not truncate", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newTestTable().Render(&buf, OutputWide))
+		assert.Contains(t, buf.String(), "/home/user/.dapr/bin")
+	})
+
+	t.Run("columns are not truncated below the minimum width", func(t *testing.T) {
+		pretendTerminalWidth(t, 10)
+		var buf bytes.Buffer
+		require.NoError(t, newTestTable().Render(&buf, OutputText))
+		assert.Contains(t, buf.String(), "daprd           installed  /home...\n")
+	})
+}
+
+func TestTableRenderJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestTable().Render(&buf, OutputJSON))
+
+	var rows []map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Equal(t, []map[string]string{
+		{"name": "daprd", "status": "installed", "location": "/home/user/.dapr/bin"},
+		{"name": "dapr_placement", "status": "running", "location": ""},
+	}, rows)
+}
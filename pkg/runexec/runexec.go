@@ -18,6 +18,7 @@ import (
 	"io"
 	"os/exec"
 
+	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/pkg/standalone"
 )
 
@@ -35,6 +36,8 @@ type RunExec struct {
 	DaprHTTPPort   int
 	DaprGRPCPort   int
 	DaprMetricPort int
+	// AppColor colors the prefix of the output lines of the app.
+	AppColor func(a ...interface{}) string
 }
 
 // RunOutput represents the run execution.
@@ -56,6 +59,7 @@ func New(config *standalone.RunConfig, daprCmdProcess *CmdProcess, appCmdProcess
 		DaprHTTPPort:   config.HTTPPort,
 		DaprGRPCPort:   config.GRPCPort,
 		DaprMetricPort: config.MetricsPort,
+		AppColor:       print.Blue,
 	}
 }
 
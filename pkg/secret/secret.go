@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secret holds the credential values handled by the CLI, such as tokens and private keys, so that they can't
+// end up in its output: a Secret prints and serializes masked, and the output paths of the CLI pass what they write
+// through Redact, which masks the values of all the secrets created so far.
+package secret
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// mask replaces the value of a secret in the output.
+	mask = "****"
+	// minRedactLength is the length under which the values are not redacted from the output by Redact, as they would
+	// match too much of it. They are still masked when printed as Secret.
+	minRedactLength = 4
+)
+
+var (
+	registry     = map[string]struct{}{}
+	registryLock sync.RWMutex
+)
+
+// Secret is a credential value. It shows as `****` followed by the length of the value when it is printed with the
+// fmt verbs or serialized to JSON, YAML or text, Reveal returns the value itself.
+type Secret struct {
+	value string
+}
+
+// New returns a Secret of value, which from now on is also redacted by Redact.
+func New(value string) Secret {
+	Register(value)
+	return Secret{value: value}
+}
+
+// Register makes Redact mask value, for the credentials which are not held in a Secret, like those passed through
+// to another process. The lines of a multi-line value, like a PEM private key, are also masked on their own, except
+// for the armor lines.
+func Register(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minRedactLength {
+		return
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[value] = struct{}{}
+	if !strings.Contains(value, "\n") {
+		return
+	}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) >= minRedactLength && !strings.HasPrefix(line, "-----") {
+			registry[line] = struct{}{}
+		}
+	}
+}
+
+// Reveal returns the value of the secret, it must only be used to hand it to what consumes the credential.
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+// IsEmpty reports whether the secret has no value.
+func (s Secret) IsEmpty() bool {
+	return s.value == ""
+}
+
+// String returns the masked value.
+func (s Secret) String() string {
+	return Mask(s.value)
+}
+
+// GoString returns the masked value, for the %#v verb.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("secret.Secret(%q)", s.String())
+}
+
+// Format masks the value with all the fmt verbs, including %x and %q.
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprint(f, s.GoString())
+			return
+		}
+	case 'q':
+		fmt.Fprintf(f, "%q", s.String())
+		return
+	}
+	fmt.Fprint(f, s.String())
+}
+
+// MarshalText masks the value, for the text based encodings.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// MarshalJSON masks the value.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", s.String())), nil
+}
+
+// MarshalYAML masks the value.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// Mask returns the masked form of value: `****` followed by its length, or nothing if it is empty.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s(%d)", mask, len(value))
+}
+
+// Redact masks the values of all the secrets created or registered so far in s.
+func Redact(s string) string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if len(registry) == 0 || s == "" {
+		return s
+	}
+	// The longest values go first, so that a secret containing another is masked as a whole.
+	values := make([]string, 0, len(registry))
+	for v := range registry {
+		if strings.Contains(s, v) {
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Mask(v))
+	}
+	return s
+}
+
+// RedactBytes is Redact for byte slices.
+func RedactBytes(b []byte) []byte {
+	redacted := Redact(string(b))
+	if redacted == string(b) {
+		return b
+	}
+	return []byte(redacted)
+}
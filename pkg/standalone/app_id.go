@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strconv"
+	"strings"
+)
+
+// projectConfigFileName is the name of the project configuration file, in the .dapr directory of a project.
+const projectConfigFileName = "project.json"
+
+// ProjectConfig holds the settings of a project, shared by the people working on it.
+type ProjectConfig struct {
+	// AppID pins the app id of the project, used when --app-id isn't given.
+	AppID string `json:"appId,omitempty"`
+}
+
+// GetProjectConfigPath returns the path of the project configuration file of projectDir.
+func GetProjectConfigPath(projectDir string) string {
+	return path_filepath.Join(projectDir, DefaultDaprDirName, projectConfigFileName)
+}
+
+// ReadProjectConfig reads the project configuration file of projectDir. A missing file is an empty configuration.
+func ReadProjectConfig(projectDir string) (ProjectConfig, error) {
+	var config ProjectConfig
+	path := GetProjectConfigPath(projectDir)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("error reading project config file %s: %w", path, err)
+	}
+	if err = json.Unmarshal(b, &config); err != nil {
+		return config, fmt.Errorf("error parsing project config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ResolvedAppID is an app id derived for a project, along with where it comes from.
+type ResolvedAppID struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+func (id ResolvedAppID) String() string {
+	return fmt.Sprintf("%s (from %s)", id.ID, id.Source)
+}
+
+// SanitizeAppID turns name into an app id: lowercased, with the runs of characters other than letters and digits
+// replaced with a dash. It is empty if name has no letters or digits.
+func SanitizeAppID(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// ProjectAppID returns the app id of the project in projectDir: the app id of its project configuration file if
+// set, otherwise its directory name as sanitized by SanitizeAppID. The id is empty if neither gives one.
+func ProjectAppID(projectDir string) (ResolvedAppID, error) {
+	config, err := ReadProjectConfig(projectDir)
+	if err != nil {
+		return ResolvedAppID{}, err
+	}
+	if id := strings.TrimSpace(config.AppID); id != "" {
+		return ResolvedAppID{ID: id, Source: "project config file " + GetProjectConfigPath(projectDir)}, nil
+	}
+	absDir, err := path_filepath.Abs(projectDir)
+	if err != nil {
+		return ResolvedAppID{}, err
+	}
+	return ResolvedAppID{ID: SanitizeAppID(path_filepath.Base(absDir)), Source: "directory name " + absDir}, nil
+}
+
+// DeriveAppID returns the app id of the project in projectDir for an instance, as for ProjectAppID. If an instance
+// with the id is already running, as listed by ListInstances with daprRuntimePath, a -2 style suffix is added with
+// allowDuplicate, otherwise it is an error. The id is empty if the project doesn't give one.
+func DeriveAppID(projectDir, daprRuntimePath string, allowDuplicate bool) (ResolvedAppID, error) {
+	resolved, err := ProjectAppID(projectDir)
+	if err != nil || resolved.ID == "" {
+		return resolved, err
+	}
+	instances, err := ListInstances(daprRuntimePath)
+	if err != nil {
+		return ResolvedAppID{}, err
+	}
+	running := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		running[instance.AppID] = true
+	}
+	return disambiguateAppID(resolved, func(id string) bool { return running[id] }, allowDuplicate)
+}
+
+func disambiguateAppID(resolved ResolvedAppID, exists func(string) bool, allowDuplicate bool) (ResolvedAppID, error) {
+	if !exists(resolved.ID) {
+		return resolved, nil
+	}
+	if !allowDuplicate {
+		return ResolvedAppID{}, fmt.Errorf("app id %s is already running: stop it, give another one with --app-id, or use --allow-duplicate-id for a suffixed id", resolved)
+	}
+	for n := 2; ; n++ {
+		if id := resolved.ID + "-" + strconv.Itoa(n); !exists(id) {
+			return ResolvedAppID{ID: id, Source: resolved.Source + ", suffixed as it is already running"}, nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeAppID(t *testing.T) {
+	for name, expected := range map[string]string{
+		"orders":            "orders",
+		"My Service":        "my-service",
+		"checkout_api.v2":   "checkout-api-v2",
+		"--Node--App--":     "node-app",
+		"façade":            "fa-ade",
+		"!!!":               "",
+		"OrderProcessor123": "orderprocessor123",
+	} {
+		assert.Equal(t, expected, SanitizeAppID(name), name)
+	}
+}
+
+func TestProjectAppID(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "Order_Processor")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, DefaultDaprDirName), 0o755))
+
+	resolved, err := ProjectAppID(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "order-processor", resolved.ID)
+	assert.Equal(t, "directory name "+projectDir, resolved.Source)
+
+	configPath := GetProjectConfigPath(projectDir)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"appId": "orders"}`), 0o600))
+	resolved, err = ProjectAppID(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "orders (from project config file "+configPath+")", resolved.String())
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{`), 0o600))
+	_, err = ProjectAppID(projectDir)
+	assert.ErrorContains(t, err, configPath)
+}
+
+func TestDisambiguateAppID(t *testing.T) {
+	running := map[string]bool{"orders": true, "orders-2": true}
+	exists := func(id string) bool { return running[id] }
+
+	resolved, err := disambiguateAppID(ResolvedAppID{ID: "checkout", Source: "directory name"}, exists, false)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", resolved.ID)
+
+	_, err = disambiguateAppID(ResolvedAppID{ID: "orders", Source: "directory name"}, exists, false)
+	assert.ErrorContains(t, err, "--allow-duplicate-id")
+
+	resolved, err = disambiguateAppID(ResolvedAppID{ID: "orders", Source: "directory name"}, exists, true)
+	require.NoError(t, err)
+	assert.Equal(t, "orders-3", resolved.ID)
+}
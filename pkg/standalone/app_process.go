@@ -0,0 +1,53 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setAppProcessGroup makes the app start in its own process group, so that the processes it starts are signaled
+// with it.
+func setAppProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// StartAppProcess starts the app command returned by GetAppCommand.
+func StartAppProcess(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+// TerminateAppProcess asks the app process started by StartAppProcess to exit with SIGTERM, along with the processes
+// it started.
+func TerminateAppProcess(p *os.Process) error {
+	return signalProcessGroup(p.Pid, syscall.SIGTERM)
+}
+
+// KillAppProcess kills the app process started by StartAppProcess along with the processes it started.
+func KillAppProcess(p *os.Process) error {
+	return signalProcessGroup(p.Pid, syscall.SIGKILL)
+}
+
+// signalProcessGroup sends sig to the process group of pid if pid leads it, as the app processes do, or to pid.
+func signalProcessGroup(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		return syscall.Kill(-pid, sig)
+	}
+	return syscall.Kill(pid, sig)
+}
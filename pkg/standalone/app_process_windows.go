@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// appJobs are the job objects of the app processes, by pid.
+var appJobs sync.Map
+
+// setAppProcessGroup is a no-op on Windows, where the app processes are assigned to a job object once started.
+func setAppProcessGroup(cmd *exec.Cmd) {}
+
+// StartAppProcess starts the app command returned by GetAppCommand, and assigns it to a job object which kills the
+// processes it started along with it. The job object is closed when the CLI exits, which kills the remaining
+// processes even if the CLI didn't stop them.
+func StartAppProcess(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// The job object is a best effort: without it, only the app process itself is killed.
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE},
+	}
+	_, err = windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)))
+	if err == nil {
+		var process windows.Handle
+		process, err = windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid))
+		if err == nil {
+			err = windows.AssignProcessToJobObject(job, process)
+			windows.CloseHandle(process)
+		}
+	}
+	if err != nil {
+		windows.CloseHandle(job)
+		return nil
+	}
+	appJobs.Store(cmd.Process.Pid, job)
+	return nil
+}
+
+// TerminateAppProcess kills the app process started by StartAppProcess, as the console apps can't be asked to exit
+// gracefully on Windows.
+func TerminateAppProcess(p *os.Process) error {
+	return KillAppProcess(p)
+}
+
+// KillAppProcess kills the app process started by StartAppProcess along with the processes it started.
+func KillAppProcess(p *os.Process) error {
+	job, ok := appJobs.LoadAndDelete(p.Pid)
+	if !ok {
+		return p.Kill()
+	}
+	defer windows.CloseHandle(job.(windows.Handle))
+	return windows.TerminateJobObject(job.(windows.Handle), 1)
+}
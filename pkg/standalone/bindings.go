@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/pkg/api"
+	"github.com/dapr/cli/utils"
+)
+
+// DefaultBindingOperation is the operation of the binding requests which don't set one.
+const DefaultBindingOperation = "create"
+
+const bindingTypePrefix = "bindings."
+
+// BindingRequest is a request to a binding component, sent by InvokeBinding.
+type BindingRequest struct {
+	// Operation is DefaultBindingOperation if empty.
+	Operation string
+	Metadata  map[string]string
+	// Data is embedded as is if it is JSON, as a string otherwise.
+	Data []byte
+}
+
+// FindBindingComponent returns the binding component name defined in the resources directories dirs. If there is
+// none, the error suggests the bindings with a close name, or lists them.
+func FindBindingComponent(dirs []string, name string) (ComponentInfo, error) {
+	var bindings []string
+	for _, dir := range dirs {
+		components, err := ListComponents(dir)
+		if err != nil {
+			return ComponentInfo{}, err
+		}
+		for _, c := range components {
+			if c.Name == "" {
+				continue
+			}
+			isBinding := strings.HasPrefix(c.Type, bindingTypePrefix)
+			if c.Name == name {
+				if !isBinding {
+					return ComponentInfo{}, fmt.Errorf("component %s of %s is a %s component, not a binding", name, c.File, c.Type)
+				}
+				return c, nil
+			}
+			if isBinding {
+				bindings = append(bindings, c.Name)
+			}
+		}
+	}
+
+	where := strings.Join(dirs, ", ")
+	if len(bindings) == 0 {
+		return ComponentInfo{}, fmt.Errorf("binding %s not found, there is no binding component in %s", name, where)
+	}
+	if suggestions := suggestNames(name, bindings); len(suggestions) > 0 {
+		return ComponentInfo{}, fmt.Errorf("binding %s not found in %s, did you mean %s?", name, where, strings.Join(suggestions, " or "))
+	}
+	sort.Strings(bindings)
+	return ComponentInfo{}, fmt.Errorf("binding %s not found in %s, the bindings are: %s", name, where, strings.Join(bindings, ", "))
+}
+
+// InvokeBinding sends req to the binding component name, through the sidecar of appID. The result is returned along
+// with the error of a non-2xx response, which includes the body of the response.
+func (s *Standalone) InvokeBinding(appID, name string, req BindingRequest) (InvokeResult, error) {
+	list, err := s.process.List()
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	lo, err := findRunningApp(list, appID)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	body := map[string]interface{}{"operation": req.Operation}
+	if req.Operation == "" {
+		body["operation"] = DefaultBindingOperation
+	}
+	if len(req.Metadata) > 0 {
+		body["metadata"] = req.Metadata
+	}
+	switch {
+	case len(req.Data) == 0:
+	case json.Valid(req.Data):
+		body["data"] = json.RawMessage(req.Data)
+	default:
+		body["data"] = string(req.Data)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	u := fmt.Sprintf("http://127.0.0.1:%d/v%s/bindings/%s", lo.HTTPPort, api.RuntimeAPIVersion, url.PathEscape(name))
+	r, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	var httpc http.Client
+	if lo.UnixDomainSocket != "" {
+		httpc.Transport = &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", utils.GetSocket(lo.UnixDomainSocket, appID, "http"))
+			},
+		}
+	}
+
+	start := time.Now()
+	resp, err := httpc.Do(r)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	defer resp.Body.Close()
+	return handleResponse(resp, start)
+}
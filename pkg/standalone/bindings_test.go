@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bindingsTestComponents = `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: checkout
+spec:
+  type: bindings.http
+  version: v1
+---
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: cron
+spec:
+  type: bindings.cron
+  version: v1
+---
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  version: v1
+`
+
+func TestFindBindingComponent(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "components.yaml"), []byte(bindingsTestComponents), 0o600))
+
+	c, err := FindBindingComponent([]string{dir}, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "bindings.http", c.Type)
+
+	_, err = FindBindingComponent([]string{dir}, "chekout")
+	assert.EqualError(t, err, "binding chekout not found in "+dir+", did you mean checkout?")
+
+	_, err = FindBindingComponent([]string{dir}, "queue")
+	assert.EqualError(t, err, "binding queue not found in "+dir+", the bindings are: checkout, cron")
+
+	_, err = FindBindingComponent([]string{dir}, "statestore")
+	assert.EqualError(t, err, "component statestore of "+filepath.Join(dir, "components.yaml")+" is a state.redis component, not a binding")
+
+	_, err = FindBindingComponent([]string{t.TempDir()}, "checkout")
+	assert.ErrorContains(t, err, "there is no binding component in")
+}
+
+func TestInvokeBinding(t *testing.T) {
+	var path, body string
+	ts, port := getTestServerFunc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	ts.Start()
+	defer ts.Close()
+
+	client := &Standalone{process: &mockDaprProcess{Lo: []ListOutput{{AppID: "orders", HTTPPort: port}}}}
+
+	result, err := client.InvokeBinding("orders", "checkout", BindingRequest{Metadata: map[string]string{"key": "order-1"}, Data: []byte(`{"id":1}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "/v1.0/bindings/checkout", path)
+	assert.JSONEq(t, `{"operation":"create","metadata":{"key":"order-1"},"data":{"id":1}}`, body)
+	assert.Equal(t, `{"ok":true}`, string(result.Body))
+
+	_, err = client.InvokeBinding("orders", "checkout", BindingRequest{Operation: "get", Data: []byte("hello")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"operation":"get","data":"hello"}`, body)
+}
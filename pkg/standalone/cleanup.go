@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	path_filepath "path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	ps "github.com/mitchellh/go-ps"
+	process "github.com/shirou/gopsutil/process"
+
+	dapr_runtime "github.com/dapr/dapr/pkg/runtime"
+)
+
+// OrphanLeftRunning is the result of an orphaned runtime process which Cleanup didn't stop.
+const OrphanLeftRunning = "left running"
+
+// CleanupOptions are the options of Cleanup.
+type CleanupOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath string
+	// KillOrphans stops the orphaned runtime processes without asking.
+	KillOrphans bool
+	// Confirm asks whether to stop an orphaned runtime process, when KillOrphans is not set. The orphans are left
+	// running if it is nil.
+	Confirm func(orphan OrphanProcess) (bool, error)
+}
+
+// CleanupReport is the result of Cleanup.
+type CleanupReport struct {
+	// RemovedRecords are the run records whose processes are gone.
+	RemovedRecords []RunRecord     `json:"removedRecords,omitempty"`
+	Orphans        []OrphanProcess `json:"orphans,omitempty"`
+	// FreedPorts are the ports of the instances of the removed records and of the stopped orphans.
+	FreedPorts []int `json:"freedPorts,omitempty"`
+}
+
+// OrphanProcess is a daprd process of the install dir which no run record tracks and whose parent is gone, left
+// behind by a run command which crashed.
+type OrphanProcess struct {
+	PID        int    `json:"pid"`
+	AppID      string `json:"appId,omitempty"`
+	Executable string `json:"executable"`
+	Ports      []int  `json:"ports"`
+	Result     string `json:"result"`
+	Err        error  `json:"-"`
+}
+
+// runtimeProcess is a running daprd process.
+type runtimeProcess struct {
+	pid        int
+	ppid       int
+	executable string
+	cmdLine    []string
+}
+
+// listRuntimeProcesses returns the running daprd processes. It is a variable for the tests.
+var listRuntimeProcesses = func() ([]runtimeProcess, error) {
+	processes, err := ps.Processes()
+	if err != nil {
+		return nil, err
+	}
+	var list []runtimeProcess
+	for _, proc := range processes {
+		executable := strings.ToLower(proc.Executable())
+		if executable != daprRuntimeFilePrefix && executable != daprRuntimeFilePrefix+".exe" {
+			continue
+		}
+		details, err := process.NewProcess(int32(proc.Pid()))
+		if err != nil {
+			continue
+		}
+		// The processes whose executable or command line can't be read, e.g. of other users, aren't ours to stop.
+		exe, err := details.Exe()
+		if err != nil {
+			continue
+		}
+		cmdLine, err := details.CmdlineSlice()
+		if err != nil {
+			continue
+		}
+		list = append(list, runtimeProcess{pid: proc.Pid(), ppid: proc.PPid(), executable: exe, cmdLine: cmdLine})
+	}
+	return list, nil
+}
+
+// Cleanup removes the run records of the install dir whose processes are gone, and finds the orphaned daprd
+// processes, which run the runtime binary of the install dir while no run record tracks them and their parent, the
+// run command, is gone. The orphans are stopped with KillOrphans or once Confirm agrees, the processes which don't
+// clearly belong to the install dir are never touched.
+func Cleanup(opts CleanupOptions) (*CleanupReport, error) {
+	daprDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	records, removed, err := pruneRunRecords(daprDir)
+	if err != nil {
+		return nil, err
+	}
+	report := &CleanupReport{RemovedRecords: removed}
+	freed := map[int]bool{}
+	for _, r := range removed {
+		for _, port := range []int{r.HTTPPort, r.GRPCPort, r.AppPort} {
+			if port > 0 {
+				freed[port] = true
+			}
+		}
+	}
+
+	processes, err := listRuntimeProcesses()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the runtime processes: %w", err)
+	}
+	report.Orphans = findOrphans(processes, records, getDaprBinPath(daprDir))
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		stop := opts.KillOrphans
+		if !stop && opts.Confirm != nil {
+			if stop, err = opts.Confirm(*orphan); err != nil {
+				return nil, err
+			}
+		}
+		if !stop {
+			orphan.Result = OrphanLeftRunning
+			continue
+		}
+		result := stopProcesses([]stopProcess{{name: daprRuntimeFilePrefix, pid: orphan.PID, httpPort: orphan.Ports[0]}}, DefaultStopGracePeriod, false)[0]
+		orphan.Result, orphan.Err = result.Result, result.Err
+		if result.Err == nil {
+			for _, port := range orphan.Ports {
+				freed[port] = true
+			}
+		}
+	}
+
+	for port := range freed {
+		report.FreedPorts = append(report.FreedPorts, port)
+	}
+	sort.Ints(report.FreedPorts)
+	return report, nil
+}
+
+// findOrphans returns the processes which run the runtime binary of binDir while no record tracks them and their
+// parent process is gone, sorted by pid. Their executable tells them from the daprd processes of other installs.
+func findOrphans(processes []runtimeProcess, records []RunRecord, binDir string) []OrphanProcess {
+	tracked := make(map[int]bool, len(records))
+	for _, r := range records {
+		tracked[r.DaprdPID] = true
+	}
+	var orphans []OrphanProcess
+	for _, p := range processes {
+		if tracked[p.pid] || !sameDir(path_filepath.Dir(p.executable), binDir) {
+			continue
+		}
+		// The run command is the parent of daprd: an orphan is adopted by init on Unix, and keeps the pid of its
+		// exited parent on Windows.
+		if p.ppid > 1 && pidAlive(p.ppid) {
+			continue
+		}
+		args := parseDaprdArgs(p.cmdLine)
+		orphans = append(orphans, OrphanProcess{
+			PID:        p.pid,
+			AppID:      args["--app-id"],
+			Executable: p.executable,
+			Ports: []int{
+				getIntArg(args, "--dapr-http-port", dapr_runtime.DefaultDaprHTTPPort),
+				getIntArg(args, "--dapr-grpc-port", dapr_runtime.DefaultDaprAPIGRPCPort),
+			},
+		})
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].PID < orphans[j].PID })
+	return orphans
+}
+
+// sameDir reports whether the directories a and b are the same, ignoring the case on Windows.
+func sameDir(a, b string) bool {
+	if resolved, err := path_filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	a, b = path_filepath.Clean(a), path_filepath.Clean(b)
+	if runtime.GOOS == daprWindowsOS {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeRuntimeProcesses(t *testing.T, processes ...runtimeProcess) {
+	t.Helper()
+	original := listRuntimeProcesses
+	t.Cleanup(func() { listRuntimeProcesses = original })
+	listRuntimeProcesses = func() ([]runtimeProcess, error) { return processes, nil }
+}
+
+func TestFindOrphans(t *testing.T) {
+	binDir := t.TempDir()
+	fakePIDs(t, 10, 100, 200, 300, 400)
+	daprd := filepath.Join(binDir, daprRuntimeFilePrefix)
+	processes := []runtimeProcess{
+		// Tracked by a run record.
+		{pid: 100, ppid: 1, executable: daprd, cmdLine: []string{daprd, "--app-id", "orders"}},
+		// Its run command still runs.
+		{pid: 200, ppid: 10, executable: daprd, cmdLine: []string{daprd, "--app-id", "checkout"}},
+		// Another install's runtime binary.
+		{pid: 300, ppid: 1, executable: filepath.Join(t.TempDir(), daprRuntimeFilePrefix), cmdLine: []string{"daprd", "--app-id", "other"}},
+		{pid: 400, ppid: 1, executable: daprd, cmdLine: []string{daprd, "--app-id", "crashed", "--dapr-http-port", "3601"}},
+		// The parent exited, without being reparented, as on Windows.
+		{pid: 500, ppid: 20, executable: daprd, cmdLine: []string{daprd, "--app-id", "windows", "--dapr-grpc-port", "50010"}},
+	}
+	orphans := findOrphans(processes, []RunRecord{{AppID: "orders", DaprdPID: 100}}, binDir)
+	assert.Equal(t, []OrphanProcess{
+		{PID: 400, AppID: "crashed", Executable: daprd, Ports: []int{3601, 50001}},
+		{PID: 500, AppID: "windows", Executable: daprd, Ports: []int{3500, 50010}},
+	}, orphans)
+}
+
+func TestCleanup(t *testing.T) {
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	fakePIDs(t, 100, 400)
+	started := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "orders", DaprdPID: 100, CliPID: 10, HTTPPort: 3500, GRPCPort: 50001, Started: started}))
+	require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "stale", DaprdPID: 300, CliPID: 30, HTTPPort: 3501, GRPCPort: 50002, AppPort: 8080, Started: started}))
+	daprd := filepath.Join(getDaprBinPath(daprDir), daprRuntimeFilePrefix)
+	fakeRuntimeProcesses(t, runtimeProcess{pid: 400, ppid: 1, executable: daprd, cmdLine: []string{daprd, "--app-id", "crashed", "--dapr-http-port", "3601"}})
+
+	var asked []int
+	report, err := Cleanup(CleanupOptions{DaprRuntimePath: runtimePath, Confirm: func(orphan OrphanProcess) (bool, error) {
+		asked = append(asked, orphan.PID)
+		return false, nil
+	}})
+	require.NoError(t, err)
+	require.Len(t, report.RemovedRecords, 1)
+	assert.Equal(t, "stale", report.RemovedRecords[0].AppID)
+	assert.Equal(t, []int{400}, asked)
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, OrphanLeftRunning, report.Orphans[0].Result)
+	// The ports of the orphan left running are still held.
+	assert.Equal(t, []int{3501, 8080, 50002}, report.FreedPorts)
+	records, err := ReadRunRecords(daprDir)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	// The orphan exits before it is stopped.
+	fakePIDs(t, 100)
+	report, err = Cleanup(CleanupOptions{DaprRuntimePath: runtimePath, KillOrphans: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.RemovedRecords)
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, ProcessNotRunning, report.Orphans[0].Result)
+	assert.Equal(t, []int{3601, 50001}, report.FreedPorts)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dapr/cli/pkg/print"
+	cli_ver "github.com/dapr/cli/pkg/version"
+)
+
+const (
+	cliBinaryFilePrefix = "dapr"
+	// staleCLISuffix is the suffix of the previous CLI executable on Windows, which can't be removed while it runs.
+	staleCLISuffix = ".old"
+)
+
+// ErrCLIUpToDate is returned by UpgradeCLI when the running CLI is already the target version.
+var ErrCLIUpToDate = errors.New("is already the running CLI version")
+
+// CLIUpgradeOptions are the options of UpgradeCLI.
+type CLIUpgradeOptions struct {
+	// Version is the version to upgrade the CLI to, or latest.
+	Version string
+	// CurrentVersion is the version of the running CLI.
+	CurrentVersion string
+	// Force reinstalls the CLI even if it is already the target version.
+	Force bool
+	// Signature tells how the downloaded archive is verified against the signed checksums of its release.
+	Signature SignatureOptions
+}
+
+// CLIUpgradeReport describes an upgrade of the CLI.
+type CLIUpgradeReport struct {
+	PreviousVersion string `json:"previousVersion"`
+	Version         string `json:"version"`
+	Path            string `json:"path"`
+}
+
+// UpgradeCLI replaces the executable of the running CLI with the release of another version: the archive for this
+// platform is downloaded next to the executable, verified against the checksum published with the release, and the
+// extracted binary is verified by running it before it replaces the executable with a rename. On Windows, where
+// a running executable can't be replaced, it is renamed out of the way first and removed by the next run with
+// RemoveStaleCLI. The CLIs installed with a package manager are left to it.
+func UpgradeCLI(ctx context.Context, opts CLIUpgradeOptions) (*CLIUpgradeReport, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate the running CLI: %w", err)
+	}
+	if resolved, resolveErr := path_filepath.EvalSymlinks(exe); resolveErr == nil {
+		exe = resolved
+	}
+	report := &CLIUpgradeReport{PreviousVersion: opts.CurrentVersion, Path: exe}
+	if manager, command := cliPackageManager(exe, runtime.GOOS, os.Getenv("ProgramFiles")); manager != "" {
+		return report, fmt.Errorf("the CLI at %s was installed with %s, upgrade it with %s", exe, manager, command)
+	}
+
+	if err = useDownloadURL(""); err != nil {
+		return report, err
+	}
+	signatures, err := newReleaseVerifier(opts.Signature)
+	if err != nil {
+		return report, err
+	}
+	target := strings.TrimPrefix(strings.TrimSpace(opts.Version), "v")
+	if target == "" || target == latestVersion {
+		if target, err = cli_ver.GetCLIVersion(); err != nil {
+			return report, fmt.Errorf("cannot get the latest CLI release version: '%w'. Try specifying --cli-version=<desired_version>", err)
+		}
+	}
+	report.Version = target
+	if target == opts.CurrentVersion && !opts.Force {
+		return report, fmt.Errorf("CLI %s %w, use --force to reinstall it", target, ErrCLIUpToDate)
+	}
+
+	// The new binary is extracted next to the executable, so that renaming it over the executable is atomic.
+	dir := path_filepath.Dir(exe)
+	tmpDir, err := os.MkdirTemp(dir, ".dapr-upgrade-")
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			if brewBin := homebrewBinDir(); runtime.GOOS == "darwin" && brewBin != "" {
+				return report, fmt.Errorf("no permission to replace the CLI in %s, run the upgrade as a user who can write to it, or reinstall the CLI in %s, which is writable without sudo, with the install script and DAPR_INSTALL_DIR=%s: %w", dir, brewBin, brewBin, err)
+			}
+			return report, fmt.Errorf("no permission to replace the CLI in %s, run the upgrade as a user who can write to it: %w", dir, err)
+		}
+		return report, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	print.InfoStatusEvent(os.Stdout, "Downloading the CLI %s", target)
+	archive, err := downloadBinary(ctx, tmpDir, target, cliBinaryFilePrefix, cli_ver.CLIGitHubRepo, nil)
+	if err != nil {
+		return report, fmt.Errorf("error downloading the CLI: %w", err)
+	}
+	verified, err := verifyDownloadSignature(ctx, signatures, target, cliBinaryFilePrefix, cli_ver.CLIGitHubRepo, archive)
+	if err != nil {
+		return report, err
+	}
+	if !verified {
+		checksum, checksumErr := fetchPublishedChecksum(ctx, tmpDir, target, cliBinaryFilePrefix, cli_ver.CLIGitHubRepo)
+		if checksumErr != nil {
+			return report, fmt.Errorf("no checksum to verify the CLI archive against: %w", checksumErr)
+		}
+		if _, err = verifyArchiveChecksum(archive, cliBinaryFilePrefix, checksum); err != nil {
+			return report, err
+		}
+	}
+	extracted, err := extractFile(archive.path, tmpDir, cliBinaryFilePrefix)
+	if err != nil {
+		return report, err
+	}
+	if err = checkBinaryRunnable(extracted); err != nil {
+		return report, incompatibleLibcError(err, "the CLI", target)
+	}
+	if err = makeExecutable(extracted); err != nil {
+		return report, fmt.Errorf("error making the CLI executable: %w", err)
+	}
+	// The CLI prints its version along with the one of the runtime.
+	out, err := runtimeBinaryVersion(extracted)
+	if err != nil {
+		return report, fmt.Errorf("error verifying the downloaded CLI: %w", err)
+	}
+	if !strings.Contains(out, target) {
+		return report, fmt.Errorf("the downloaded CLI reports %q instead of version %s", firstLine(out), target)
+	}
+
+	if err = replaceExecutable(exe, extracted); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// replaceExecutable replaces the executable at exe with the one at replacement, on the same file system.
+func replaceExecutable(exe, replacement string) error {
+	if runtime.GOOS == daprWindowsOS {
+		stale := exe + staleCLISuffix
+		if err := os.Remove(stale); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error removing the previous CLI %s: %w", stale, err)
+		}
+		if err := os.Rename(exe, stale); err != nil {
+			return fmt.Errorf("error moving the running CLI out of the way: %w", err)
+		}
+		if err := os.Rename(replacement, exe); err != nil {
+			if restoreErr := os.Rename(stale, exe); restoreErr != nil {
+				return fmt.Errorf("error replacing the CLI: %w, and restoring it from %s failed: %w", err, stale, restoreErr)
+			}
+			return fmt.Errorf("error replacing the CLI: %w", err)
+		}
+		return nil
+	}
+	if err := os.Rename(replacement, exe); err != nil {
+		return fmt.Errorf("error replacing the CLI: %w", err)
+	}
+	return nil
+}
+
+// RemoveStaleCLI removes the previous executable of the CLI left by an upgrade on Windows, once it no longer runs.
+func RemoveStaleCLI() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if resolved, resolveErr := path_filepath.EvalSymlinks(exe); resolveErr == nil {
+		exe = resolved
+	}
+	// Best effort, it is tried again on the next run.
+	_ = os.Remove(exe + staleCLISuffix)
+}
+
+// cliPackageManager returns the package manager which installed the CLI executable at exe, told from its path on
+// goos, along with the command upgrading it, or empty strings if there is none. programFiles is the Program Files
+// directory on Windows, where the MSI installer installs the CLI.
+func cliPackageManager(exe, goos, programFiles string) (string, string) {
+	path := strings.ToLower(strings.ReplaceAll(exe, `\`, "/"))
+	if goos != daprWindowsOS {
+		if strings.Contains(path, "/cellar/") || strings.HasPrefix(path, "/opt/homebrew/") || strings.HasPrefix(path, "/home/linuxbrew/") {
+			formula := cellarFormula(exe)
+			if formula == "" {
+				formula = "dapr/tap/dapr-cli"
+			}
+			return "Homebrew", fmt.Sprintf("`brew upgrade %s`", formula)
+		}
+		return "", ""
+	}
+	switch {
+	case strings.Contains(path, "/chocolatey/"):
+		return "Chocolatey", "`choco upgrade dapr-cli`"
+	case strings.Contains(path, "/scoop/"):
+		return "Scoop", "`scoop update dapr-cli`"
+	case strings.Contains(path, "/winget/"):
+		return "winget", "`winget upgrade Dapr.CLI`"
+	case programFiles != "" && strings.HasPrefix(path, strings.ToLower(strings.ReplaceAll(programFiles, `\`, "/"))+"/"):
+		return "the MSI installer", "the installer of the new release"
+	}
+	return "", ""
+}
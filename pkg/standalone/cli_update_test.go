@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLIPackageManager(t *testing.T) {
+	tests := []struct {
+		exe     string
+		goos    string
+		manager string
+	}{
+		{exe: "/usr/local/bin/dapr", goos: "linux"},
+		{exe: "/opt/homebrew/Cellar/dapr-cli/1.11.0/bin/dapr", goos: "darwin", manager: "Homebrew"},
+		{exe: "/usr/local/Cellar/dapr-cli/1.11.0/bin/dapr", goos: "darwin", manager: "Homebrew"},
+		{exe: "/home/linuxbrew/.linuxbrew/bin/dapr", goos: "linux", manager: "Homebrew"},
+		{exe: `C:\dapr\dapr.exe`, goos: "windows"},
+		{exe: `C:\Program Files\Dapr\dapr.exe`, goos: "windows", manager: "the MSI installer"},
+		{exe: `C:\ProgramData\chocolatey\lib\dapr-cli\tools\dapr.exe`, goos: "windows", manager: "Chocolatey"},
+		{exe: `C:\Users\me\scoop\apps\dapr-cli\current\dapr.exe`, goos: "windows", manager: "Scoop"},
+		{exe: `C:\Program Files Extra\dapr.exe`, goos: "windows"},
+	}
+	for _, tc := range tests {
+		manager, command := cliPackageManager(tc.exe, tc.goos, `C:\Program Files`)
+		assert.Equal(t, tc.manager, manager, tc.exe)
+		assert.Equal(t, tc.manager == "", command == "", tc.exe)
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "dapr")
+	replacement := filepath.Join(dir, ".dapr-upgrade", "dapr")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(replacement), 0o755))
+	require.NoError(t, os.WriteFile(replacement, []byte("new"), 0o755))
+
+	require.NoError(t, replaceExecutable(exe, replacement))
+	b, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(b))
+	assert.NoFileExists(t, replacement)
+	if runtime.GOOS == daprWindowsOS {
+		b, err = os.ReadFile(exe + staleCLISuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(b))
+	} else {
+		assert.NoFileExists(t, exe+staleCLISuffix)
+	}
+}
@@ -0,0 +1,649 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	// cliConfigFileName is the name of the CLI configuration file in the dapr install dir. It is distinct from
+	// config.yaml, which is the configuration of the runtime.
+	cliConfigFileName = "config.json"
+	// userCLIConfigDirName is the directory of the CLI configuration file in the user config dir, $XDG_CONFIG_HOME on
+	// Linux.
+	userCLIConfigDirName = "dapr"
+
+	componentsPathEnvVar = "DAPR_COMPONENTS_PATH"
+	defaultHelmRepoURL   = "https://dapr.github.io/helm-charts"
+)
+
+// The settings of the CLI, as named in the CLI config file.
+const (
+	SettingRuntimePath      = "runtimePath"
+	SettingComponentsPath   = "componentsPath"
+	SettingImageRegistry    = "imageRegistry"
+	SettingContainerRuntime = "containerRuntime"
+	SettingRedisPort        = "redisPort"
+	SettingRuntimeVersion   = "runtimeVersion"
+	SettingDashboardVersion = "dashboardVersion"
+	SettingNetwork          = "network"
+	SettingSlimMode         = "slimMode"
+	SettingDownloadURL      = "downloadURL"
+	SettingProfile          = "profile"
+	SettingHelmRepoURL      = "helmRepoURL"
+	SettingHelmRepoUsername = "helmRepoUsername"
+	SettingHelmRepoPassword = "helmRepoPassword"
+	SettingPullConcurrency  = "pullConcurrency"
+)
+
+// The CLI config files config set and config unset write to.
+const (
+	// ConfigScopeUser is the CLI config file of the user config dir, see GetUserCLIConfigPath.
+	ConfigScopeUser = "user"
+	// ConfigScopeInstall is the CLI config file of the dapr install dir, see GetCLIConfigPath.
+	ConfigScopeInstall = "install"
+)
+
+// The sources of the values of the settings, along with the path of the file for a config file.
+const (
+	SettingSourceFlag    = "flag"
+	SettingSourceDefault = "default"
+)
+
+// CLIConfig holds the settings persisted in the CLI configuration file.
+type CLIConfig struct {
+	// RuntimePath is the directory of the dapr install dir, as --runtime-path. It is only read from the CLI config
+	// file of the user config dir, as the one of the install dir is in it.
+	RuntimePath      string `json:"runtimePath,omitempty"`
+	ComponentsPath   string `json:"componentsPath,omitempty"`
+	ImageRegistry    string `json:"imageRegistry,omitempty"`
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+	RedisPort        int    `json:"redisPort,omitempty"`
+	RuntimeVersion   string `json:"runtimeVersion,omitempty"`
+	DashboardVersion string `json:"dashboardVersion,omitempty"`
+	Network          string `json:"network,omitempty"`
+	SlimMode         *bool  `json:"slimMode,omitempty"`
+	// DownloadURL is the base URL of a mirror of the GitHub releases the binaries are downloaded from.
+	DownloadURL string `json:"downloadURL,omitempty"`
+	// Profile is the profile the commands use by default. As RuntimePath, it is only read from the CLI config file of
+	// the user config dir.
+	Profile string `json:"profile,omitempty"`
+	// HelmRepoURL is the Helm repository the charts of dapr are pulled from, with the credentials of HelmRepoUsername
+	// and HelmRepoPassword.
+	HelmRepoURL      string `json:"helmRepoURL,omitempty"`
+	HelmRepoUsername string `json:"helmRepoUsername,omitempty"`
+	HelmRepoPassword string `json:"helmRepoPassword,omitempty"`
+	// PullConcurrency is the number of image pulls and downloads init runs at once.
+	PullConcurrency int `json:"pullConcurrency,omitempty"`
+	// UpdateCheck enables the check for new releases when set to true, it is disabled by default.
+	UpdateCheck *bool `json:"updateCheck,omitempty"`
+	// Hooks run around the steps of `dapr init`.
+	Hooks []ShellHook `json:"hooks,omitempty"`
+}
+
+// settingKind is the type of the value of a setting.
+type settingKind int
+
+const (
+	settingString settingKind = iota
+	settingInt
+	settingBool
+)
+
+// cliSetting describes a setting of the CLI, and where its value is looked up.
+type cliSetting struct {
+	name   string
+	flag   string
+	envVar string
+	kind   settingKind
+	// userOnly is set for the settings which are only read from the CLI config file of the user config dir.
+	userOnly bool
+	// values are the valid values of the setting, any value if empty.
+	values []string
+	// secret is set for the settings which are elided in config view, and written to a CLI config file only readable
+	// by its owner.
+	secret       bool
+	defaultValue func(daprDir string) (string, error)
+}
+
+// cliSettings are the settings of the CLI, in the order config view lists them.
+var cliSettings = []cliSetting{
+	{name: SettingRuntimePath, flag: "runtime-path", envVar: "DAPR_RUNTIME_PATH", userOnly: true, defaultValue: func(string) (string, error) {
+		return os.UserHomeDir()
+	}},
+	{name: SettingComponentsPath, flag: "components-path", envVar: componentsPathEnvVar, defaultValue: func(daprDir string) (string, error) {
+		return GetDaprComponentsPath(daprDir), nil
+	}},
+	{name: SettingImageRegistry, flag: "image-registry", envVar: "DAPR_IMAGE_REGISTRY", defaultValue: func(string) (string, error) {
+		return "", nil
+	}},
+	{name: SettingContainerRuntime, flag: "container-runtime", envVar: "DAPR_CONTAINER_RUNTIME", values: []string{string(utils.DOCKER), string(utils.PODMAN)}, defaultValue: func(string) (string, error) {
+		return string(utils.DOCKER), nil
+	}},
+	{name: SettingRedisPort, envVar: "DAPR_REDIS_PORT", kind: settingInt, defaultValue: func(string) (string, error) {
+		return strconv.Itoa(redisServicePort), nil
+	}},
+	{name: SettingRuntimeVersion, flag: "runtime-version", envVar: "DAPR_RUNTIME_VERSION", defaultValue: func(string) (string, error) {
+		return latestVersion, nil
+	}},
+	{name: SettingDashboardVersion, flag: "dashboard-version", envVar: "DAPR_DASHBOARD_VERSION", defaultValue: func(string) (string, error) {
+		return latestVersion, nil
+	}},
+	{name: SettingNetwork, flag: "network", envVar: "DAPR_NETWORK", defaultValue: func(string) (string, error) {
+		return "", nil
+	}},
+	{name: SettingSlimMode, flag: "slim", envVar: "DAPR_SLIM_MODE", kind: settingBool, defaultValue: func(string) (string, error) {
+		return "false", nil
+	}},
+	{name: SettingDownloadURL, envVar: "DAPR_DOWNLOAD_URL", defaultValue: func(string) (string, error) {
+		return defaultDownloadURL, nil
+	}},
+	{name: SettingProfile, flag: "profile", envVar: "DAPR_PROFILE", userOnly: true, defaultValue: func(string) (string, error) {
+		return DefaultProfile, nil
+	}},
+	{name: SettingPullConcurrency, envVar: "DAPR_PULL_CONCURRENCY", kind: settingInt, defaultValue: func(string) (string, error) {
+		return strconv.Itoa(defaultPullConcurrency), nil
+	}},
+	{name: SettingHelmRepoURL, envVar: "DAPR_HELM_REPO_URL", defaultValue: func(string) (string, error) {
+		return defaultHelmRepoURL, nil
+	}},
+	{name: SettingHelmRepoUsername, envVar: "DAPR_HELM_REPO_USERNAME", defaultValue: func(string) (string, error) {
+		return "", nil
+	}},
+	{name: SettingHelmRepoPassword, envVar: "DAPR_HELM_REPO_PASSWORD", secret: true, defaultValue: func(string) (string, error) {
+		return "", nil
+	}},
+}
+
+// cliEnvVars are the environment variables with the DAPR_ prefix the CLI reads, other than those of its settings:
+// those set for the apps and the hooks, which are found in their shells, and those of the other options.
+var cliEnvVars = []string{
+	"DAPR_DEFAULT_IMAGE_REGISTRY", disableUpdateCheckEnvVar, "DAPR_HTTP_PORT", "DAPR_GRPC_PORT", "DAPR_METRICS_PORT",
+	"DAPR_PROFILE_PORT", "DAPR_API_TOKEN", "DAPR_APP_ID", "DAPR_CERT_CHAIN", "DAPR_CERT_KEY", "DAPR_TRUST_ANCHORS",
+	"DAPR_HOST_IP", "DAPR_PLACEMENT_HOST_ADDRESS", "DAPR_INSTALL_DIR", daprHomeEnvVar,
+}
+
+// Setting is the value of a setting of the CLI, along with where it comes from: SettingSourceFlag, the environment
+// variable, the CLI config file or SettingSourceDefault.
+type Setting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// EnvVar is the environment variable of the setting.
+	EnvVar string `json:"envVar"`
+	Source string `json:"source"`
+	// Secret is set for the settings whose value is elided, see DisplayValue.
+	Secret bool `json:"-"`
+}
+
+// DisplayValue returns the value of s to print, elided for a secret.
+func (s Setting) DisplayValue() string {
+	if s.Secret && s.Value != "" {
+		return redacted
+	}
+	return s.Value
+}
+
+// Explicit reports whether the value of s is set for the command, with a flag or an environment variable, rather
+// than persisted in a CLI config file or the default.
+func (s Setting) Explicit() bool {
+	return s.Source == SettingSourceFlag || strings.HasSuffix(s.Source, " environment variable")
+}
+
+// FlagLookup returns the value of the command line flag name, and whether it is set.
+type FlagLookup func(name string) (string, bool)
+
+// GetCLIConfigPath returns the path of the CLI configuration file in daprDir.
+func GetCLIConfigPath(daprDir string) string {
+	return path_filepath.Join(daprDir, cliConfigFileName)
+}
+
+// GetUserCLIConfigPath returns the path of the CLI configuration file in the user config dir, which holds the
+// settings of all the install dirs: $XDG_CONFIG_HOME/dapr/config.json or $HOME/.config/dapr/config.json on Linux.
+func GetUserCLIConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return path_filepath.Join(dir, userCLIConfigDirName, cliConfigFileName), nil
+}
+
+// cliConfigFile is a CLI configuration file, along with the settings it sets.
+type cliConfigFile struct {
+	path string
+	// data is the content of the file, nil if it doesn't exist.
+	data   []byte
+	values map[string]json.RawMessage
+}
+
+// readCLIConfigFile reads the CLI configuration file at path. A missing file sets no settings.
+func readCLIConfigFile(path string) (*cliConfigFile, error) {
+	f := &cliConfigFile{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CLI config file %s: %w", path, err)
+	}
+	if err = json.Unmarshal(b, &f.values); err != nil {
+		return nil, cliConfigParseError(path, b, err)
+	}
+	// The settings of the wrong type are reported here, with their position.
+	var config CLIConfig
+	if err = json.Unmarshal(b, &config); err != nil {
+		return nil, cliConfigParseError(path, b, err)
+	}
+	f.data = b
+	return f, nil
+}
+
+// cliConfigParseError returns the error parsing the CLI configuration file at path, with content b, with the line
+// and the column it happened at when err tells it.
+func cliConfigParseError(path string, b []byte, err error) error {
+	var offset int64 = -1
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset is the one after the invalid character.
+		offset = syntaxErr.Offset - 1
+	case errors.As(err, &typeErr):
+		// The offset is the one of the end of the value.
+		offset = typeErr.Offset
+		err = fmt.Errorf("%s must be a %s, not a %s", typeErr.Field, jsonTypeName(typeErr.Type.Kind()), typeErr.Value)
+	}
+	if offset < 0 || offset > int64(len(b)) {
+		return fmt.Errorf("error parsing CLI config file %s: %w", path, err)
+	}
+	line, column := textPosition(b, int(offset))
+	return fmt.Errorf("error parsing CLI config file %s at line %d, column %d: %w", path, line, column, err)
+}
+
+// jsonTypeName returns the name of the JSON type of the values of kind.
+func jsonTypeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return kind.String()
+	}
+}
+
+// textPosition returns the line and the column, both starting at 1, of the byte at offset in b.
+func textPosition(b []byte, offset int) (int, int) {
+	before := b[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// value returns the value of the setting s in f as a string, and whether f sets it.
+func (f *cliConfigFile) value(s cliSetting) (string, bool) {
+	raw, ok := f.values[s.name]
+	if !ok || string(raw) == "null" {
+		return "", false
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return strings.TrimSpace(str), strings.TrimSpace(str) != ""
+	}
+	return string(raw), true
+}
+
+// cliConfigFiles returns the CLI configuration files of daprDir in their order of precedence: the one of the install
+// dir, then the one of the user config dir. daprDir is empty to only read the one of the user config dir.
+func cliConfigFiles(daprDir string) ([]*cliConfigFile, error) {
+	var files []*cliConfigFile
+	if daprDir != "" {
+		f, err := readCLIConfigFile(GetCLIConfigPath(daprDir))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if userPath, err := GetUserCLIConfigPath(); err == nil {
+		f, err := readCLIConfigFile(userPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// readCLIConfig reads the CLI configuration files of daprDir, the settings of the one of the install dir overriding
+// those of the one of the user config dir. Missing files are an empty configuration.
+func readCLIConfig(daprDir string) (CLIConfig, error) {
+	var config CLIConfig
+	files, err := cliConfigFiles(daprDir)
+	if err != nil {
+		return config, err
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i].data == nil {
+			continue
+		}
+		if err = json.Unmarshal(files[i].data, &config); err != nil {
+			return config, cliConfigParseError(files[i].path, files[i].data, err)
+		}
+	}
+	return config, nil
+}
+
+// LoadCLIConfig checks that the CLI configuration files can be parsed and that the settings in them are valid, so
+// that a mistake in them fails every command rather than being silently ignored. daprRuntimePath is based on the
+// --runtime-path command line flag, as for GetDaprRuntimePath.
+func LoadCLIConfig(daprRuntimePath string) error {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return err
+	}
+	files, err := cliConfigFiles(daprDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		for _, s := range cliSettings {
+			if v, ok := f.value(s); ok {
+				if err = s.validate(v, "CLI config file "+f.path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the value v of the setting s, which comes from source.
+func (s cliSetting) validate(v, source string) error {
+	switch s.kind {
+	case settingInt:
+		if _, err := strconv.Atoi(v); err != nil {
+			return fmt.Errorf("invalid value %q of %s in %s, it must be an integer", v, s.name, source)
+		}
+	case settingBool:
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value %q of %s in %s, it must be true or false", v, s.name, source)
+		}
+	}
+	if len(s.values) > 0 && !utils.Contains(s.values, v) {
+		return fmt.Errorf("invalid value %q of %s in %s, it must be one of %s", v, s.name, source, strings.Join(s.values, ", "))
+	}
+	return nil
+}
+
+// lookupCLISetting returns the setting name.
+func lookupCLISetting(name string) (cliSetting, error) {
+	for _, s := range cliSettings {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return cliSetting{}, fmt.Errorf("unknown setting %q", name)
+}
+
+// ResolveSetting returns the value of the setting name of the CLI. The order of precedence is:
+//  1. the command line flag of the setting, as returned by flags, which may be nil
+//  2. the environment variable of the setting
+//  3. the setting in the CLI config file of the dapr install dir
+//  4. the setting in the CLI config file of the user config dir, see GetUserCLIConfigPath
+//  5. the default value
+//
+// daprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func ResolveSetting(name string, flags FlagLookup, daprRuntimePath string) (Setting, error) {
+	s, err := lookupCLISetting(name)
+	if err != nil {
+		return Setting{}, err
+	}
+	return s.resolve(flags, daprRuntimePath)
+}
+
+func (s cliSetting) resolve(flags FlagLookup, daprRuntimePath string) (Setting, error) {
+	setting := Setting{Name: s.name, EnvVar: s.envVar, Secret: s.secret}
+	if flags != nil && s.flag != "" {
+		if v, ok := flags(s.flag); ok && strings.TrimSpace(v) != "" {
+			setting.Value, setting.Source = strings.TrimSpace(v), SettingSourceFlag
+			return setting, s.validate(setting.Value, "--"+s.flag)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(s.envVar)); v != "" {
+		setting.Value, setting.Source = v, s.envVar+" environment variable"
+		return setting, s.validate(v, setting.Source)
+	}
+
+	daprDir := ""
+	if !s.userOnly {
+		var err error
+		if daprDir, err = GetDaprRuntimePath(daprRuntimePath); err != nil {
+			return setting, err
+		}
+	}
+	files, err := cliConfigFiles(daprDir)
+	if err != nil {
+		return setting, err
+	}
+	for _, f := range files {
+		if v, ok := f.value(s); ok {
+			setting.Value, setting.Source = v, "CLI config file "+f.path
+			return setting, s.validate(v, setting.Source)
+		}
+	}
+	if setting.Value, err = s.defaultValue(daprDir); err != nil {
+		return setting, err
+	}
+	setting.Source = SettingSourceDefault
+	return setting, nil
+}
+
+// CLISettings returns all the settings of the CLI, resolved as ResolveSetting does.
+func CLISettings(flags FlagLookup, daprRuntimePath string) ([]Setting, error) {
+	settings := make([]Setting, 0, len(cliSettings))
+	for _, s := range cliSettings {
+		setting, err := s.resolve(flags, daprRuntimePath)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// ResolvedPath is a path resolved from the settings, along with where it comes from.
+type ResolvedPath struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+}
+
+func (p ResolvedPath) String() string {
+	return fmt.Sprintf("%s (from %s)", p.Path, p.Source)
+}
+
+// ResolveComponentsPath returns the components directory all the commands use, flagValue being the one of the
+// command line flag of the command, resolved as the componentsPath setting by ResolveSetting.
+func ResolveComponentsPath(flagValue, daprRuntimePath string) (ResolvedPath, error) {
+	setting, err := ResolveSetting(SettingComponentsPath, func(string) (string, bool) {
+		return flagValue, flagValue != ""
+	}, daprRuntimePath)
+	if err != nil {
+		return ResolvedPath{}, err
+	}
+	return ResolvedPath{Path: setting.Value, Source: setting.Source}, nil
+}
+
+// UnrecognizedEnvVars returns the names of the variables of environ, as returned by os.Environ, which have the DAPR_
+// prefix of the variables of the CLI but aren't one of them, which is often a typo. The names are sorted.
+func UnrecognizedEnvVars(environ []string) []string {
+	known := map[string]bool{}
+	for _, s := range cliSettings {
+		known[s.envVar] = true
+	}
+	for _, v := range cliEnvVars {
+		known[v] = true
+	}
+	var unknown []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, "DAPR_") || known[name] || strings.HasPrefix(name, "DAPR_HOOK_") {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// cliConfigFilePath returns the path of the CLI config file of scope in which the setting s is written.
+func cliConfigFilePath(s cliSetting, scope, daprRuntimePath string) (string, error) {
+	switch scope {
+	case ConfigScopeUser, "":
+		return GetUserCLIConfigPath()
+	case ConfigScopeInstall:
+		if s.userOnly {
+			return "", fmt.Errorf("%s is only read from the CLI config file of the user config dir, set it without --scope %s", s.name, ConfigScopeInstall)
+		}
+		daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+		if err != nil {
+			return "", err
+		}
+		return GetCLIConfigPath(daprDir), nil
+	default:
+		return "", fmt.Errorf("invalid scope %q, it must be %s or %s", scope, ConfigScopeUser, ConfigScopeInstall)
+	}
+}
+
+// SetCLISetting sets the setting name to value in the CLI config file of scope, ConfigScopeUser or
+// ConfigScopeInstall, and returns the path of the file. The value is checked against the type and the valid values
+// of the setting, and the other keys of the file, including those unknown to this version of the CLI, are kept.
+// daprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func SetCLISetting(name, value, scope, daprRuntimePath string) (string, error) {
+	s, err := lookupCLISetting(name)
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("no value for %s, use config unset to remove it", name)
+	}
+	if err = s.validate(value, "the command line"); err != nil {
+		return "", err
+	}
+	path, err := cliConfigFilePath(s, scope, daprRuntimePath)
+	if err != nil {
+		return "", err
+	}
+	f, err := readCLIConfigFile(path)
+	if err != nil {
+		return path, err
+	}
+
+	var raw json.RawMessage
+	switch s.kind {
+	case settingInt:
+		n, _ := strconv.Atoi(value)
+		raw, err = json.Marshal(n)
+	case settingBool:
+		b, _ := strconv.ParseBool(value)
+		raw, err = json.Marshal(b)
+	default:
+		raw, err = json.Marshal(value)
+	}
+	if err != nil {
+		return path, err
+	}
+	if f.values == nil {
+		f.values = map[string]json.RawMessage{}
+	}
+	f.values[s.name] = raw
+	return path, writeCLIConfigFile(path, f.values)
+}
+
+// UnsetCLISetting removes the setting name from the CLI config file of scope, as SetCLISetting, and returns the path
+// of the file and whether the setting was set in it.
+func UnsetCLISetting(name, scope, daprRuntimePath string) (string, bool, error) {
+	s, err := lookupCLISetting(name)
+	if err != nil {
+		return "", false, err
+	}
+	path, err := cliConfigFilePath(s, scope, daprRuntimePath)
+	if err != nil {
+		return "", false, err
+	}
+	f, err := readCLIConfigFile(path)
+	if err != nil {
+		return path, false, err
+	}
+	if _, ok := f.values[s.name]; !ok {
+		return path, false, nil
+	}
+	delete(f.values, s.name)
+	return path, true, writeCLIConfigFile(path, f.values)
+}
+
+// writeCLIConfigFile writes the CLI config file at path with values. The file is replaced with a rename, so that a
+// failed write never leaves a partial file behind, and is only readable by its owner when it holds a secret.
+func writeCLIConfigFile(path string, values map[string]json.RawMessage) error {
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	var mode os.FileMode = 0o644
+	for _, s := range cliSettings {
+		if _, ok := values[s.name]; ok && s.secret {
+			mode = 0o600
+		}
+	}
+
+	dir := path_filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating the directory of CLI config file %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+cliConfigFileName+"-")
+	if err != nil {
+		return fmt.Errorf("error writing CLI config file %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing CLI config file %s: %w", path, err)
+	}
+	return nil
+}
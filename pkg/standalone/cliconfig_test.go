@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveComponentsPath(t *testing.T) {
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	t.Setenv(componentsPathEnvVar, "")
+
+	t.Run("default", func(t *testing.T) {
+		p, err := ResolveComponentsPath("", runtimePath)
+		require.NoError(t, err)
+		assert.Equal(t, ResolvedPath{Path: filepath.Join(daprDir, "components"), Source: "default"}, p)
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"componentsPath": "/from/config"}`), 0o600))
+		t.Cleanup(func() { os.Remove(GetCLIConfigPath(daprDir)) })
+
+		p, err := ResolveComponentsPath("", runtimePath)
+		require.NoError(t, err)
+		assert.Equal(t, "/from/config", p.Path)
+		assert.Equal(t, "CLI config file "+GetCLIConfigPath(daprDir), p.Source)
+
+		t.Run("environment variable", func(t *testing.T) {
+			t.Setenv(componentsPathEnvVar, "/from/env")
+			p, err := ResolveComponentsPath("", runtimePath)
+			require.NoError(t, err)
+			assert.Equal(t, ResolvedPath{Path: "/from/env", Source: "DAPR_COMPONENTS_PATH environment variable"}, p)
+
+			t.Run("flag", func(t *testing.T) {
+				p, err := ResolveComponentsPath("/from/flag", runtimePath)
+				require.NoError(t, err)
+				assert.Equal(t, ResolvedPath{Path: "/from/flag", Source: "flag"}, p)
+			})
+		})
+	})
+
+	t.Run("malformed config file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"componentsPath": `), 0o600))
+		t.Cleanup(func() { os.Remove(GetCLIConfigPath(daprDir)) })
+
+		_, err := ResolveComponentsPath("", runtimePath)
+		assert.ErrorContains(t, err, GetCLIConfigPath(daprDir))
+	})
+}
+
+// isolateUserConfig points the user config dir to a temporary directory, and returns the path of its CLI config file.
+func isolateUserConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+	path, err := GetUserCLIConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	return path
+}
+
+func TestResolveSetting(t *testing.T) {
+	userConfig := isolateUserConfig(t)
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	t.Setenv("DAPR_IMAGE_REGISTRY", "")
+	t.Setenv("DAPR_REDIS_PORT", "")
+
+	s, err := ResolveSetting(SettingRedisPort, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, Setting{Name: SettingRedisPort, Value: "6379", EnvVar: "DAPR_REDIS_PORT", Source: SettingSourceDefault}, s)
+
+	require.NoError(t, os.WriteFile(userConfig, []byte(`{"redisPort": 6380, "imageRegistry": "user.example.com"}`), 0o600))
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"imageRegistry": "install.example.com"}`), 0o600))
+	s, err = ResolveSetting(SettingRedisPort, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, Setting{Name: SettingRedisPort, Value: "6380", EnvVar: "DAPR_REDIS_PORT", Source: "CLI config file " + userConfig}, s)
+	s, err = ResolveSetting(SettingImageRegistry, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "install.example.com", s.Value)
+	assert.False(t, s.Explicit())
+
+	t.Setenv("DAPR_IMAGE_REGISTRY", "env.example.com")
+	s, err = ResolveSetting(SettingImageRegistry, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, Setting{Name: SettingImageRegistry, Value: "env.example.com", EnvVar: "DAPR_IMAGE_REGISTRY", Source: "DAPR_IMAGE_REGISTRY environment variable"}, s)
+	assert.True(t, s.Explicit())
+
+	flags := func(name string) (string, bool) { return "flag.example.com", name == "image-registry" }
+	s, err = ResolveSetting(SettingImageRegistry, flags, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, Setting{Name: SettingImageRegistry, Value: "flag.example.com", EnvVar: "DAPR_IMAGE_REGISTRY", Source: SettingSourceFlag}, s)
+
+	t.Setenv("DAPR_REDIS_PORT", "abc")
+	_, err = ResolveSetting(SettingRedisPort, nil, runtimePath)
+	assert.EqualError(t, err, `invalid value "abc" of redisPort in DAPR_REDIS_PORT environment variable, it must be an integer`)
+
+	t.Setenv("DAPR_SLIM_MODE", "yes")
+	_, err = ResolveSetting(SettingSlimMode, nil, runtimePath)
+	assert.EqualError(t, err, `invalid value "yes" of slimMode in DAPR_SLIM_MODE environment variable, it must be true or false`)
+	t.Setenv("DAPR_SLIM_MODE", "")
+	flags = func(name string) (string, bool) { return "true", name == "slim" }
+	s, err = ResolveSetting(SettingSlimMode, flags, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "true", s.Value)
+
+	_, err = ResolveSetting("nope", nil, runtimePath)
+	assert.Error(t, err)
+}
+
+func TestRuntimePathFromUserConfig(t *testing.T) {
+	userConfig := isolateUserConfig(t)
+	t.Setenv("DAPR_RUNTIME_PATH", "")
+	require.NoError(t, os.WriteFile(userConfig, []byte(`{"runtimePath": "/opt/dapr"}`), 0o600))
+
+	daprDir, err := GetDaprRuntimePath("")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/opt/dapr", DefaultDaprDirName), daprDir)
+	daprDir, err = GetDaprRuntimePath("/from/flag")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/from/flag", DefaultDaprDirName), daprDir)
+
+	// The install dir can't relocate itself.
+	runtimePath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(runtimePath, DefaultDaprDirName), 0o755))
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(filepath.Join(runtimePath, DefaultDaprDirName)), []byte(`{"runtimePath": "/elsewhere"}`), 0o600))
+	settings, err := CLISettings(func(name string) (string, bool) { return runtimePath, name == "runtime-path" }, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, Setting{Name: SettingRuntimePath, Value: runtimePath, EnvVar: "DAPR_RUNTIME_PATH", Source: SettingSourceFlag}, settings[0])
+	t.Setenv("DAPR_RUNTIME_PATH", "")
+	settings, err = CLISettings(nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/dapr", settings[0].Value)
+}
+
+func TestLoadCLIConfig(t *testing.T) {
+	userConfig := isolateUserConfig(t)
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	require.NoError(t, LoadCLIConfig(runtimePath))
+
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte("{\n  \"imageRegistry\": \"example.com\",\n}\n"), 0o600))
+	err := LoadCLIConfig(runtimePath)
+	assert.ErrorContains(t, err, "error parsing CLI config file "+GetCLIConfigPath(daprDir)+" at line 3, column 1")
+
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"redisPort": "6380"}`), 0o600))
+	assert.ErrorContains(t, LoadCLIConfig(runtimePath), "at line 1, column 21: redisPort must be a number, not a string")
+
+	require.NoError(t, os.Remove(GetCLIConfigPath(daprDir)))
+	require.NoError(t, os.WriteFile(userConfig, []byte(`{"componentsPath": ["a"]}`), 0o600))
+	assert.ErrorContains(t, LoadCLIConfig(runtimePath), userConfig)
+}
+
+func TestUnrecognizedEnvVars(t *testing.T) {
+	environ := []string{"PATH=/bin", "DAPR_RUNTIME_VERSION=1.11.0", "DAPR_RUNTIME_VESION=1.11.0", "DAPR_HOOK_STEP=runtime", "DAPR_HTTP_PORT=3500", "DAPR_NETWROK=x", "DAPRD=1"}
+	assert.Equal(t, []string{"DAPR_NETWROK", "DAPR_RUNTIME_VESION"}, UnrecognizedEnvVars(environ))
+	assert.Empty(t, UnrecognizedEnvVars([]string{"DAPR_REDIS_PORT=6380"}))
+}
+
+func TestSetCLISetting(t *testing.T) {
+	userConfig := isolateUserConfig(t)
+	t.Setenv("DAPR_REDIS_PORT", "")
+	t.Setenv("DAPR_CONTAINER_RUNTIME", "")
+	t.Setenv("DAPR_HELM_REPO_PASSWORD", "")
+	runtimePath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Dir(userConfig), 0o755))
+	require.NoError(t, os.WriteFile(userConfig, []byte(`{"fromNewerCLI": {"a": 1}, "network": "mynet"}`), 0o644))
+
+	path, err := SetCLISetting(SettingRedisPort, "6380", ConfigScopeUser, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, userConfig, path)
+	_, err = SetCLISetting(SettingSlimMode, "true", "", runtimePath)
+	require.NoError(t, err)
+	b, err := os.ReadFile(userConfig)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fromNewerCLI": {"a": 1}, "network": "mynet", "redisPort": 6380, "slimMode": true}`, string(b))
+	setting, err := ResolveSetting(SettingRedisPort, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "6380", setting.Value)
+
+	_, err = SetCLISetting(SettingRedisPort, "port", ConfigScopeUser, runtimePath)
+	assert.ErrorContains(t, err, "it must be an integer")
+	_, err = SetCLISetting(SettingContainerRuntime, "containerd", ConfigScopeUser, runtimePath)
+	assert.ErrorContains(t, err, "it must be one of docker, podman")
+	_, err = SetCLISetting("redisProt", "6380", ConfigScopeUser, runtimePath)
+	assert.ErrorContains(t, err, `unknown setting "redisProt"`)
+	_, err = SetCLISetting(SettingRuntimePath, "/opt", ConfigScopeInstall, runtimePath)
+	assert.Error(t, err)
+
+	path, err = SetCLISetting(SettingContainerRuntime, "podman", ConfigScopeInstall, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, GetCLIConfigPath(filepath.Join(runtimePath, DefaultDaprDirName)), path)
+	path, removed, err := UnsetCLISetting(SettingContainerRuntime, ConfigScopeInstall, runtimePath)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(b))
+	_, removed, err = UnsetCLISetting(SettingContainerRuntime, ConfigScopeInstall, runtimePath)
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	_, err = SetCLISetting(SettingHelmRepoPassword, "s3cret", ConfigScopeUser, runtimePath)
+	require.NoError(t, err)
+	if runtime.GOOS != daprWindowsOS {
+		info, statErr := os.Stat(userConfig)
+		require.NoError(t, statErr)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	setting, err = ResolveSetting(SettingHelmRepoPassword, nil, runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", setting.Value)
+	assert.Equal(t, redacted, setting.DisplayValue())
+}
+
+func TestUseDownloadURL(t *testing.T) {
+	isolateUserConfig(t)
+	t.Cleanup(func() { downloadURL = defaultDownloadURL })
+
+	t.Setenv("DAPR_DOWNLOAD_URL", "https://mirror.example.com/github/")
+	require.NoError(t, useDownloadURL(t.TempDir()))
+	assert.Equal(t, "https://mirror.example.com/github/dapr/dapr/releases/download/v1.11.0", releaseDownloadURL("1.11.0", "dapr"))
+
+	t.Setenv("DAPR_DOWNLOAD_URL", "")
+	require.NoError(t, useDownloadURL(t.TempDir()))
+	assert.Equal(t, "https://github.com/dapr/dapr/releases/download/v1.11.0", releaseDownloadURL("1.11.0", "dapr"))
+}
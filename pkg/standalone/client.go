@@ -23,8 +23,20 @@ type daprProcess struct{}
 type Client interface {
 	// Invoke is a command to invoke a remote or local dapr instance.
 	Invoke(appID, method string, data []byte, verb string, socket string) (string, error)
+	// InvokeMethod is a command to invoke a method of a local dapr instance, with the response of the app.
+	InvokeMethod(appID, method string, data []byte, verb string, opts InvokeOptions) (InvokeResult, error)
 	// Publish is used to publish event to a topic in a pubsub for an app ID.
 	Publish(publishAppID, pubsubName, topic string, payload []byte, socket string, metadata map[string]interface{}) error
+	// PublishEvent is used to publish event to a topic in a pubsub, with the response of the sidecar.
+	PublishEvent(publishAppID, pubsubName, topic string, payload []byte, opts PublishOptions) (PublishResult, error)
+	// InvokeBinding sends a request to a binding component, through the sidecar of an app ID.
+	InvokeBinding(appID, name string, req BindingRequest) (InvokeResult, error)
+	// GetState returns the value of a key in a state store, through the sidecar of an app ID.
+	GetState(appID, store, key string) (StateItem, error)
+	// SaveState sets the value of a key in a state store, through the sidecar of an app ID.
+	SaveState(appID, store, key string, value []byte) error
+	// DeleteState deletes a key from a state store, through the sidecar of an app ID.
+	DeleteState(appID, store, key string) error
 }
 
 type Standalone struct {
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultCloudEventSource is the source of the CloudEvents envelopes made by NewCloudEvent.
+	DefaultCloudEventSource = "dapr-cli"
+	// DefaultCloudEventType is the type of the CloudEvents envelopes made by NewCloudEvent.
+	DefaultCloudEventType = "com.dapr.cli.event"
+
+	cloudEventSpecVersion = "1.0"
+)
+
+var traceIDRegexp = regexp.MustCompile("^[0-9a-f]{32}$")
+
+// CloudEventOptions are the attributes of the envelope made by NewCloudEvent.
+type CloudEventOptions struct {
+	// Source is DefaultCloudEventSource if empty.
+	Source string
+	// Type is DefaultCloudEventType if empty.
+	Type string
+	// ContentType is the content type of the data. It is detected from the data if empty.
+	ContentType string
+	// TraceID is the W3C trace id of the traceparent of the event, which has none if empty.
+	TraceID string
+}
+
+// CloudEvent is a CloudEvents 1.0 envelope in the structured content mode.
+type CloudEvent struct {
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	SpecVersion     string      `json:"specversion"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data,omitempty"`
+	TraceParent     string      `json:"traceparent,omitempty"`
+}
+
+// NewCloudEvent wraps payload in a CloudEvents envelope. A JSON payload is embedded as is, any other payload as a
+// string.
+func NewCloudEvent(payload []byte, opts CloudEventOptions) (CloudEvent, error) {
+	event := CloudEvent{
+		ID:              uuid.NewString(),
+		Source:          opts.Source,
+		Type:            opts.Type,
+		SpecVersion:     cloudEventSpecVersion,
+		DataContentType: opts.ContentType,
+	}
+	if event.Source == "" {
+		event.Source = DefaultCloudEventSource
+	}
+	if event.Type == "" {
+		event.Type = DefaultCloudEventType
+	}
+	if event.DataContentType == "" {
+		event.DataContentType = "text/plain"
+		if json.Valid(payload) {
+			event.DataContentType = "application/json"
+		}
+	}
+
+	switch {
+	case len(payload) == 0:
+	case strings.HasSuffix(event.DataContentType, "json") && json.Valid(payload):
+		event.Data = json.RawMessage(payload)
+	default:
+		event.Data = string(payload)
+	}
+
+	if opts.TraceID != "" {
+		traceParent, err := newTraceParent(opts.TraceID)
+		if err != nil {
+			return CloudEvent{}, err
+		}
+		event.TraceParent = traceParent
+	}
+	return event, nil
+}
+
+// newTraceParent returns a W3C traceparent of traceID, with a random parent id and sampled.
+func newTraceParent(traceID string) (string, error) {
+	traceID = strings.ToLower(strings.TrimSpace(traceID))
+	if !traceIDRegexp.MatchString(traceID) || traceID == strings.Repeat("0", 32) {
+		return "", fmt.Errorf("invalid trace id %q: it must be 32 hexadecimal characters, not all zeros", traceID)
+	}
+	parentID := make([]byte, 8)
+	if _, err := rand.Read(parentID); err != nil {
+		return "", fmt.Errorf("error generating the parent id of the traceparent: %w", err)
+	}
+	return fmt.Sprintf("00-%s-%s-01", traceID, hex.EncodeToString(parentID)), nil
+}
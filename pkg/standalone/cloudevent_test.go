@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCloudEvent(t *testing.T) {
+	t.Run("json payload", func(t *testing.T) {
+		event, err := NewCloudEvent([]byte(`{"id":1}`), CloudEventOptions{})
+		require.NoError(t, err)
+		_, err = uuid.Parse(event.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultCloudEventSource, event.Source)
+		assert.Equal(t, DefaultCloudEventType, event.Type)
+		assert.Equal(t, "1.0", event.SpecVersion)
+		assert.Equal(t, "application/json", event.DataContentType)
+		assert.Empty(t, event.TraceParent)
+
+		b, err := json.Marshal(event)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"`+event.ID+`","source":"dapr-cli","type":"com.dapr.cli.event","specversion":"1.0","datacontenttype":"application/json","data":{"id":1}}`, string(b))
+		assert.Equal(t, "application/cloudevents+json", DetectPayloadContentType(b))
+	})
+
+	t.Run("text payload with overrides", func(t *testing.T) {
+		event, err := NewCloudEvent([]byte("hello"), CloudEventOptions{Source: "tests", Type: "com.example.greeting"})
+		require.NoError(t, err)
+		assert.Equal(t, "tests", event.Source)
+		assert.Equal(t, "com.example.greeting", event.Type)
+		assert.Equal(t, "text/plain", event.DataContentType)
+		assert.Equal(t, "hello", event.Data)
+	})
+
+	t.Run("json payload as text", func(t *testing.T) {
+		event, err := NewCloudEvent([]byte(`{"id":1}`), CloudEventOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":1}`, event.Data)
+	})
+
+	t.Run("trace id", func(t *testing.T) {
+		event, err := NewCloudEvent(nil, CloudEventOptions{TraceID: "4BF92F3577B34DA6A3CE929D0E0E4736"})
+		require.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile("^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$"), event.TraceParent)
+		assert.Nil(t, event.Data)
+	})
+
+	t.Run("invalid trace id", func(t *testing.T) {
+		for _, traceID := range []string{"123", "00000000000000000000000000000000", "4bf92f3577b34da6a3ce929d0e0e473g"} {
+			_, err := NewCloudEvent(nil, CloudEventOptions{TraceID: traceID})
+			assert.Error(t, err, traceID)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+const redacted = "<redacted>"
+
+// secretFlagPattern matches the names of the flags whose values are secrets.
+var secretFlagPattern = regexp.MustCompile(`(?i)(token|secret|password|passwd|credential|key)`)
+
+// Valid values of the runtime options validated by the CLI.
+var (
+	appProtocols = []string{"grpc", "grpcs", "http", "https", "h2c"}
+	logLevels    = []string{"debug", "info", "warn", "error", "fatal", "panic"}
+)
+
+// RedactedCommandLine returns the command line of cmd for the output, quoted as for a shell, with the values of the
+// flags which look like secrets elided and the known secrets masked.
+func RedactedCommandLine(cmd *exec.Cmd) string {
+	args := append([]string{}, cmd.Args...)
+	if len(args) > 0 {
+		args[0] = cmd.Path
+	}
+	for i := 1; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !secretFlagPattern.MatchString(name) {
+			continue
+		}
+		if hasValue {
+			args[i] = args[i][:strings.Index(args[i], "=")+1] + redacted
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			args[i] = redacted
+		}
+	}
+	for i, arg := range args {
+		args[i] = shellQuote(secret.Redact(arg))
+	}
+	return strings.Join(args, " ")
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`*?[]{}()<>|&;#~") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func validateOneOf(name, value string, valid []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range valid {
+		if strings.EqualFold(value, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q, valid values are: %s", name, value, strings.Join(valid, ", "))
+}
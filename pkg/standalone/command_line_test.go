@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+func TestRedactedCommandLine(t *testing.T) {
+	cmd := &exec.Cmd{
+		Path: "/home/user/.dapr/bin/daprd",
+		Args: []string{
+			"daprd", "--app-id", "orders", "--x-token=abc", "--api-key", "s3cr3t",
+			"--enable-api-key", "--log-level", "debug", "--config", "/path with spaces/config.yaml",
+		},
+	}
+	assert.Equal(t,
+		"/home/user/.dapr/bin/daprd --app-id orders '--x-token=<redacted>' --api-key '<redacted>' "+
+			"--enable-api-key --log-level debug --config '/path with spaces/config.yaml'",
+		RedactedCommandLine(cmd))
+
+	// The known secrets are masked wherever they are.
+	token := secret.New("s3cr3t-app-token")
+	cmd.Args = []string{"daprd", "--app-id", "orders", "--metadata", "token=" + token.Reveal()}
+	assert.Equal(t, "/home/user/.dapr/bin/daprd --app-id orders --metadata 'token=****(16)'", RedactedCommandLine(cmd))
+}
+
+func TestValidateOneOf(t *testing.T) {
+	assert.NoError(t, validateOneOf("log level", "", logLevels))
+	assert.NoError(t, validateOneOf("log level", "WARN", logLevels))
+	assert.EqualError(t, validateOneOf("app protocol", "tcp", appProtocols),
+		`invalid app protocol "tcp", valid values are: grpc, grpcs, http, https, h2c`)
+}
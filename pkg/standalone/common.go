@@ -14,10 +14,14 @@ limitations under the License.
 package standalone
 
 import (
+	"fmt"
 	"os"
 	path_filepath "path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/utils"
 )
 
 const (
@@ -27,31 +31,76 @@ const (
 
 	defaultDaprBinDirName    = "bin"
 	defaultComponentsDirName = "components"
+	defaultLogsDirName       = "logs"
+	cliLogFileName           = "cli.log"
+
+	// cliLogMaxSize is the size the CLI log file is rotated at, a single previous file is kept.
+	cliLogMaxSize = 5 * 1024 * 1024
+	cliLogBackups = 1
+
+	// daprHomeEnvVar is the environment variable setting the dapr install dir itself, to keep it on a persistent
+	// volume or to give each test run an environment of its own.
+	daprHomeEnvVar = "DAPR_HOME"
 )
 
 // GetDaprRuntimePath returns the dapr runtime installation path.
 // daprRuntimePath is based on the --runtime-path command line flag.
 // The order of precedence is:
 //  1. From --runtime-path command line flag appended with `.dapr`
-//  2. From DAPR_RUNTIME_PATH environment variable appended with `.dapr`
-//  3. default $HOME/.dapr
+//  2. From DAPR_HOME environment variable, as is
+//  3. From DAPR_RUNTIME_PATH environment variable appended with `.dapr`
+//  4. From runtimePath in the CLI config file of the user config dir appended with `.dapr`
+//  5. default $HOME/.dapr
+//
+// The install dir of a profile other than the default one is the profiles/<name> directory of that directory.
 func GetDaprRuntimePath(daprRuntimePath string) (string, error) {
+	daprDir, err := baseDaprDir(daprRuntimePath)
+	if err != nil {
+		return "", err
+	}
+	profile, err := resolveProfile()
+	if err != nil {
+		return "", err
+	}
+	return profileDir(daprDir, profile), nil
+}
+
+// baseDaprDir returns the install dir of the default profile, as GetDaprRuntimePath.
+func baseDaprDir(daprRuntimePath string) (string, error) {
 	runtimePath := strings.TrimSpace(daprRuntimePath)
 	if runtimePath != "" {
 		return path_filepath.Join(runtimePath, DefaultDaprDirName), nil
 	}
+	if daprHomeSet() {
+		return daprHomeDir(strings.TrimSpace(os.Getenv(daprHomeEnvVar)))
+	}
 
-	envRuntimePath := strings.TrimSpace(os.Getenv("DAPR_RUNTIME_PATH"))
-	if envRuntimePath != "" {
-		return path_filepath.Join(envRuntimePath, DefaultDaprDirName), nil
+	setting, err := ResolveSetting(SettingRuntimePath, nil, "")
+	if err != nil {
+		return "", err
 	}
+	return path_filepath.Join(setting.Value, DefaultDaprDirName), nil
+}
 
-	homeDir, err := os.UserHomeDir()
+// daprHomeDir returns the absolute path of the install dir set with DAPR_HOME, which is created if it doesn't exist,
+// so that the commands which only read it agree with init on where it is.
+func daprHomeDir(home string) (string, error) {
+	home, err := utils.ResolveHomeDir(home)
 	if err != nil {
 		return "", err
 	}
+	if home, err = path_filepath.Abs(home); err != nil {
+		return "", fmt.Errorf("invalid %s %s: %w", daprHomeEnvVar, home, err)
+	}
+	if err = os.MkdirAll(home, 0o755); err != nil {
+		return "", fmt.Errorf("error creating the %s directory %s: %w", daprHomeEnvVar, home, err)
+	}
+	return home, nil
+}
 
-	return path_filepath.Join(homeDir, DefaultDaprDirName), nil
+// daprHomeSet reports whether the install dir is set with DAPR_HOME.
+func daprHomeSet() bool {
+	return strings.TrimSpace(os.Getenv(daprHomeEnvVar)) != ""
 }
 
 func getDaprBinPath(daprDir string) string {
@@ -82,3 +131,17 @@ func GetDaprComponentsPath(daprDir string) string {
 func GetDaprConfigPath(daprDir string) string {
 	return path_filepath.Join(daprDir, DefaultConfigFileName)
 }
+
+func getCLILogFilePath(daprDir string) string {
+	return path_filepath.Join(daprDir, defaultLogsDirName, cliLogFileName)
+}
+
+// OpenCLILog opens the CLI log file in the logs directory of the dapr install dir for appending.
+// The file is rotated once it grows beyond 5MB.
+func OpenCLILog(daprRuntimePath string) (*print.RotatingFile, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	return print.OpenRotatingFile(getCLILogFilePath(daprDir), cliLogMaxSize, cliLogBackups)
+}
@@ -67,3 +67,67 @@ func TestGetDaprPath(t *testing.T) {
 		assert.Equal(t, path_filepath.Join(input, ".dapr"), p, "path should be /path/to/dapr/.dapr")
 	})
 }
+
+func TestDaprHome(t *testing.T) {
+	t.Run("with DAPR_HOME", func(t *testing.T) {
+		home := path_filepath.Join(t.TempDir(), "cache", "dapr")
+		t.Setenv(daprHomeEnvVar, home)
+		t.Setenv("DAPR_RUNTIME_PATH", path_filepath.Join("path", "to", "dapr"))
+		p, err := GetDaprRuntimePath("")
+		require.NoError(t, err)
+		assert.Equal(t, home, p, "DAPR_HOME is the install dir, without .dapr, and takes precedence over DAPR_RUNTIME_PATH")
+		fi, err := os.Stat(home)
+		require.NoError(t, err)
+		assert.True(t, fi.IsDir())
+
+		input := path_filepath.Join("path", "to", "flag")
+		p, err = GetDaprRuntimePath(input)
+		require.NoError(t, err)
+		assert.Equal(t, path_filepath.Join(input, DefaultDaprDirName), p, "the flag takes precedence over DAPR_HOME")
+	})
+
+	t.Run("relative DAPR_HOME", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { os.Chdir(wd) })
+		t.Setenv(daprHomeEnvVar, "dapr-home")
+		p, err := GetDaprRuntimePath("")
+		require.NoError(t, err)
+		assert.True(t, path_filepath.IsAbs(p))
+		assert.Equal(t, "dapr-home", path_filepath.Base(p))
+	})
+
+	t.Run("isolated environments", func(t *testing.T) {
+		fakePIDs(t, 100, 200)
+		t.Setenv(componentsPathEnvVar, "")
+		homes := []string{path_filepath.Join(t.TempDir(), "env1"), path_filepath.Join(t.TempDir(), "env2")}
+		for i, home := range homes {
+			t.Setenv(daprHomeEnvVar, home)
+			daprDir, err := GetDaprRuntimePath("")
+			require.NoError(t, err)
+			require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "app", DaprdPID: 100 * (i + 1), CliPID: 100 * (i + 1)}))
+			log, err := OpenCLILog("")
+			require.NoError(t, err)
+			log.Close()
+		}
+		for i, home := range homes {
+			t.Setenv(daprHomeEnvVar, home)
+			records, err := ReadRunRecords(home)
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, 100*(i+1), records[0].DaprdPID)
+
+			daprd, err := lookupBinaryFilePath("", daprRuntimeFilePrefix)
+			require.NoError(t, err)
+			assert.Equal(t, getDaprBinPath(home), path_filepath.Dir(daprd))
+			components, err := ResolveComponentsPath("", "")
+			require.NoError(t, err)
+			assert.Equal(t, GetDaprComponentsPath(home), components.Path)
+			manifest, err := LoadInstallManifest("")
+			require.NoError(t, err)
+			assert.Nil(t, manifest)
+			assert.FileExists(t, getCLILogFilePath(home))
+		}
+	})
+}
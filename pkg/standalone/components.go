@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dapr/cli/utils"
+)
+
+// componentNameRegexp matches the names accepted for components, which must be valid Kubernetes resource names.
+var componentNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// componentTemplateField is a metadata item of a component template.
+type componentTemplateField struct {
+	name string
+	// value is written when no value is given for the field, it is either a default or a placeholder.
+	value string
+	// option is the name of the NewComponentOptions field which sets the value, if any.
+	option string
+	// secret fields are written as a reference to PasswordSecret when it is set.
+	secret bool
+}
+
+type componentTemplate struct {
+	version string
+	fields  []componentTemplateField
+}
+
+// componentTemplates are the component types NewComponent can scaffold.
+var componentTemplates = map[string]componentTemplate{
+	"state.redis": {
+		version: "v1",
+		fields: []componentTemplateField{
+			{name: "redisHost", value: "localhost:6379", option: "host"},
+			{name: "redisPassword", secret: true},
+			{name: "actorStateStore", value: "true"},
+		},
+	},
+	"pubsub.redis": {
+		version: "v1",
+		fields: []componentTemplateField{
+			{name: "redisHost", value: "localhost:6379", option: "host"},
+			{name: "redisPassword", secret: true},
+		},
+	},
+	"secretstores.local.file": {
+		version: "v1",
+		fields: []componentTemplateField{
+			{name: "secretsFile", value: "<path-to-secrets-file>", option: "secrets-file"},
+			{name: "nestedSeparator", value: ":"},
+		},
+	},
+}
+
+// NewComponentOptions are the options of NewComponent.
+type NewComponentOptions struct {
+	// Type is the component type, e.g. state.redis.
+	Type string
+	// Name is the name of the component, the file is named after it.
+	Name string
+	// TargetDir is the directory the component file is written to.
+	TargetDir string
+	// Force overwrites an existing component file.
+	Force bool
+	// Host is the address of the service backing the component, for the types which connect to one.
+	Host string
+	// PasswordSecret references the secret holding the password, as name or name:key.
+	PasswordSecret string
+	// SecretStore is the name of the secret store PasswordSecret is read from.
+	SecretStore string
+	// SecretsFile is the path of the secrets file of a local file secret store.
+	SecretsFile string
+	// Metadata sets metadata items, overriding the values of the template.
+	Metadata map[string]string
+}
+
+type componentSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type componentAuth struct {
+	SecretStore string `yaml:"secretStore"`
+}
+
+// ComponentTypes returns the component types NewComponent can scaffold.
+func ComponentTypes() []string {
+	types := make([]string, 0, len(componentTemplates))
+	for t := range componentTemplates {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewComponent writes a component file from the template of opts.Type and returns its path.
+// It fails if the file exists, unless opts.Force is set.
+func NewComponent(opts NewComponentOptions) (string, error) {
+	tmpl, ok := componentTemplates[opts.Type]
+	if !ok {
+		return "", fmt.Errorf("unsupported component type %q, supported types are: %s", opts.Type, strings.Join(ComponentTypes(), ", "))
+	}
+	if !componentNameRegexp.MatchString(opts.Name) {
+		return "", fmt.Errorf("invalid component name %q: it must consist of lower case alphanumeric characters, '-' or '.', and start and end with an alphanumeric character", opts.Name)
+	}
+
+	b, err := renderComponent(tmpl, opts)
+	if err != nil {
+		return "", err
+	}
+
+	err = utils.CreateDirectory(opts.TargetDir)
+	if err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", opts.TargetDir, err)
+	}
+	filePath := path_filepath.Join(opts.TargetDir, opts.Name+".yaml")
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opts.Force {
+		flags |= os.O_EXCL
+	}
+	// #nosec G302
+	f, err := os.OpenFile(filePath, flags, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("%s already exists, use --force to overwrite it", filePath)
+		}
+		return "", fmt.Errorf("error writing %s: %w", filePath, err)
+	}
+	defer f.Close()
+	if _, err = f.Write(b); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", filePath, err)
+	}
+	return filePath, nil
+}
+
+func renderComponent(tmpl componentTemplate, opts NewComponentOptions) ([]byte, error) {
+	c := component{
+		APIVersion: "dapr.io/v1alpha1",
+		Kind:       "Component",
+	}
+	c.Metadata.Name = opts.Name
+	c.Spec.Type = opts.Type
+	c.Spec.Version = tmpl.version
+
+	options := map[string]string{
+		"host":         opts.Host,
+		"secrets-file": opts.SecretsFile,
+	}
+	var secretRef *componentSecretKeyRef
+	if opts.PasswordSecret != "" {
+		name, key, _ := strings.Cut(opts.PasswordSecret, ":")
+		if key == "" {
+			key = name
+		}
+		secretRef = &componentSecretKeyRef{Name: name, Key: key}
+	}
+
+	overridden := map[string]bool{}
+	for _, field := range tmpl.fields {
+		item := componentMetadataItem{Name: field.name, Value: field.value}
+		if v := options[field.option]; field.option != "" && v != "" {
+			item.Value = v
+		}
+		if field.secret && secretRef != nil {
+			item.SecretKeyRef = secretRef
+		}
+		if v, ok := opts.Metadata[field.name]; ok {
+			item.Value = v
+			item.SecretKeyRef = nil
+			overridden[field.name] = true
+		}
+		c.Spec.Metadata = append(c.Spec.Metadata, item)
+	}
+	// Metadata items which are not part of the template are appended in a stable order.
+	extra := make([]string, 0, len(opts.Metadata))
+	for name := range opts.Metadata {
+		if !overridden[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		c.Spec.Metadata = append(c.Spec.Metadata, componentMetadataItem{Name: name, Value: opts.Metadata[name]})
+	}
+
+	if opts.SecretStore != "" {
+		c.Auth = &componentAuth{SecretStore: opts.SecretStore}
+	}
+	return yaml.Marshal(&c)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergedComponent is a resource of a merged components directory.
+type MergedComponent struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	// File is the resource file the resource comes from.
+	File string `json:"file"`
+	// Overrides are the files of the same-named resources of the lower layers.
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// MergedComponents is a temporary directory with the resources of several components directories.
+type MergedComponents struct {
+	Dir        string
+	Components []MergedComponent
+}
+
+// Cleanup removes the merged directory.
+func (m *MergedComponents) Cleanup() error {
+	return os.RemoveAll(m.Dir)
+}
+
+type mergedEntry struct {
+	component MergedComponent
+	node      *yaml.Node
+	// raw is the content of a file which couldn't be parsed, copied as is for the runtime to report the error.
+	raw []byte
+}
+
+// MergeComponentDirs merges the resource files of dirs into a temporary directory, in order: a resource of a dir
+// replaces the resource of the same kind and name of the previous dirs. Each resource is written to its own file.
+// Call Cleanup on the result once the directory isn't used anymore.
+func MergeComponentDirs(dirs ...string) (*MergedComponents, error) {
+	var entries []*mergedEntry
+	byKey := map[string]*mergedEntry{}
+	for _, dir := range dirs {
+		files, err := resourceFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			docs, fileProblems := loadResourceFile(file)
+			if len(fileProblems) > 0 {
+				raw, err := os.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("error reading resource file %s: %w", file, err)
+				}
+				entries = append(entries, &mergedEntry{component: MergedComponent{File: file}, raw: raw})
+				continue
+			}
+			for _, doc := range docs {
+				entry := &mergedEntry{
+					component: MergedComponent{Kind: doc.kind, Name: doc.name, Type: doc.componentType, File: file},
+					node:      doc.node,
+				}
+				if doc.kind == "" || doc.name == "" {
+					entries = append(entries, entry)
+					continue
+				}
+				key := doc.kind + "/" + doc.name
+				if previous, ok := byKey[key]; ok {
+					entry.component.Overrides = append(previous.component.Overrides, previous.component.File)
+					*previous = *entry
+					continue
+				}
+				byKey[key] = entry
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "dapr-components-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating merged components directory: %w", err)
+	}
+	merged := &MergedComponents{Dir: dir, Components: make([]MergedComponent, 0, len(entries))}
+	for i, entry := range entries {
+		content := entry.raw
+		if content == nil {
+			var buf bytes.Buffer
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err = encoder.Encode(entry.node); err == nil {
+				err = encoder.Close()
+			}
+			if err != nil {
+				merged.Cleanup()
+				return nil, fmt.Errorf("error encoding resource from %s: %w", entry.component.File, err)
+			}
+			content = buf.Bytes()
+		}
+		name := fmt.Sprintf("%03d-%s", i, path_filepath.Base(entry.component.File))
+		if err = os.WriteFile(path_filepath.Join(dir, name), content, 0o600); err != nil {
+			merged.Cleanup()
+			return nil, fmt.Errorf("error writing merged components directory: %w", err)
+		}
+		merged.Components = append(merged.Components, entry.component)
+	}
+	return merged, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeComponentDirs(t *testing.T) {
+	component := func(name, componentType string) string {
+		return "apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: " + name + "\nspec:\n  type: " + componentType + "\n"
+	}
+	global := writeResourceFiles(t, map[string]string{
+		"statestore.yaml": component("statestore", "state.redis"),
+		"pubsub.yaml":     component("pubsub", "pubsub.redis"),
+	})
+	project := writeResourceFiles(t, map[string]string{
+		"components.yaml": component("statestore", "state.postgresql") + "---\n" +
+			"apiVersion: dapr.io/v1alpha1\nkind: Configuration\nmetadata:\n  name: pubsub\n",
+	})
+	local := writeResourceFiles(t, map[string]string{
+		"state.yaml":  component("statestore", "state.in-memory"),
+		"broken.yaml": "kind: [\n",
+	})
+
+	merged, err := MergeComponentDirs(global, project, local)
+	require.NoError(t, err)
+	t.Cleanup(func() { merged.Cleanup() })
+
+	assert.Equal(t, []MergedComponent{
+		{Kind: componentKind, Name: "pubsub", Type: "pubsub.redis", File: filepath.Join(global, "pubsub.yaml")},
+		{
+			Kind: componentKind, Name: "statestore", Type: "state.in-memory", File: filepath.Join(local, "state.yaml"),
+			Overrides: []string{filepath.Join(global, "statestore.yaml"), filepath.Join(project, "components.yaml")},
+		},
+		{Kind: "Configuration", Name: "pubsub", File: filepath.Join(project, "components.yaml")},
+		{File: filepath.Join(local, "broken.yaml")},
+	}, merged.Components)
+
+	components, err := ListComponents(merged.Dir)
+	require.NoError(t, err)
+	require.Len(t, components, 3)
+	assert.Equal(t, "pubsub.redis", components[0].Type)
+	assert.Equal(t, "state.in-memory", components[1].Type)
+	b, err := os.ReadFile(components[2].File)
+	require.NoError(t, err)
+	assert.Equal(t, "kind: [\n", string(b))
+
+	require.NoError(t, merged.Cleanup())
+	assert.NoDirExists(t, merged.Dir)
+
+	_, err = MergeComponentDirs(global, filepath.Join(local, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The providers of the state store and pubsub components set up by init.
+const (
+	// ComponentsProviderRedis runs Redis for the components, in a container or as a service of slim mode.
+	ComponentsProviderRedis = "redis"
+	// ComponentsProviderInMemory writes the components of the in-memory state store and pubsub of the runtime, which
+	// need nothing to run.
+	ComponentsProviderInMemory = "in-memory"
+	// ComponentsProviderNone sets up no components, for the ones of the user.
+	ComponentsProviderNone = "none"
+)
+
+// IsValidComponentsProvider reports whether provider is one of the providers init --components-provider accepts.
+func IsValidComponentsProvider(provider string) bool {
+	return provider == ComponentsProviderRedis || provider == ComponentsProviderInMemory || provider == ComponentsProviderNone
+}
+
+// componentsProvider returns the components provider the manifest was installed with, Redis for the manifests which
+// predate the choice.
+func (m *InstallManifest) componentsProvider() string {
+	if m.ComponentsProvider == "" {
+		return ComponentsProviderRedis
+	}
+	return m.ComponentsProvider
+}
+
+// createInMemoryComponents writes the in-memory state store, for the actors too, and pubsub components in
+// componentsPath. The existing component files are kept, with a warning to info if they are of another type.
+func createInMemoryComponents(info initInfo, componentsPath string) error {
+	for _, c := range []struct {
+		name, file, componentType string
+		metadata                  []componentMetadataItem
+	}{
+		{name: "statestore", file: stateStoreYamlFileName, componentType: "state.in-memory", metadata: []componentMetadataItem{{Name: "actorStateStore", Value: "true"}}},
+		{name: "pubsub", file: pubSubYamlFileName, componentType: "pubsub.in-memory", metadata: []componentMetadataItem{}},
+	} {
+		inMemory := component{
+			APIVersion: "dapr.io/v1alpha1",
+			Kind:       "Component",
+		}
+		inMemory.Metadata.Name = c.name
+		inMemory.Spec.Type = c.componentType
+		inMemory.Spec.Version = "v1"
+		inMemory.Spec.Metadata = c.metadata
+
+		b, err := yaml.Marshal(&inMemory)
+		if err != nil {
+			return err
+		}
+		filePath := path_filepath.Join(componentsPath, c.file)
+		if existing, readErr := os.ReadFile(filePath); readErr == nil && !bytes.Equal(existing, b) {
+			info.warn("%s already exists and is kept, remove it for init to write the %s component", filePath, c.componentType)
+			continue
+		}
+		if err = checkAndOverWriteFile(filePath, b); err != nil {
+			return fmt.Errorf("error creating the in-memory %s component file: %w", c.name, err)
+		}
+	}
+	return nil
+}
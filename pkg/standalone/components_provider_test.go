@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidComponentsProvider(t *testing.T) {
+	assert.True(t, IsValidComponentsProvider(ComponentsProviderRedis))
+	assert.True(t, IsValidComponentsProvider(ComponentsProviderInMemory))
+	assert.True(t, IsValidComponentsProvider(ComponentsProviderNone))
+	assert.False(t, IsValidComponentsProvider("memory"))
+	assert.False(t, IsValidComponentsProvider(""))
+
+	// The manifests written before the choice were all Redis.
+	assert.Equal(t, ComponentsProviderRedis, (&InstallManifest{}).componentsProvider())
+	assert.Equal(t, ComponentsProviderNone, (&InstallManifest{ComponentsProvider: ComponentsProviderNone}).componentsProvider())
+}
+
+func TestCreateInMemoryComponents(t *testing.T) {
+	dir := t.TempDir()
+	var warnings []string
+	info := initInfo{reportWarning: func(msg string) { warnings = append(warnings, msg) }}
+
+	require.NoError(t, createInMemoryComponents(info, dir))
+	state, err := os.ReadFile(filepath.Join(dir, stateStoreYamlFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(state), "type: state.in-memory")
+	assert.Contains(t, string(state), "actorStateStore")
+	pubsub, err := os.ReadFile(filepath.Join(dir, pubSubYamlFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(pubsub), "type: pubsub.in-memory")
+	assert.Empty(t, warnings)
+
+	// Running it again is a no-op, and the components of the user are kept.
+	require.NoError(t, createInMemoryComponents(info, dir))
+	assert.Empty(t, warnings)
+	edited := []byte("apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: pubsub\nspec:\n  type: pubsub.redis\n  version: v1\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, pubSubYamlFileName), edited, 0o644))
+	require.NoError(t, createInMemoryComponents(info, dir))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "is kept")
+	pubsub, err = os.ReadFile(filepath.Join(dir, pubSubYamlFileName))
+	require.NoError(t, err)
+	assert.Equal(t, edited, pubsub)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewComponent(t *testing.T) {
+	t.Run("templates", func(t *testing.T) {
+		for _, tc := range []struct {
+			opts     NewComponentOptions
+			expected string
+		}{
+			{
+				opts: NewComponentOptions{Type: "state.redis", Name: "statestore"},
+				expected: `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  version: v1
+  metadata:
+  - name: redisHost
+    value: localhost:6379
+  - name: redisPassword
+    value: ""
+  - name: actorStateStore
+    value: "true"
+`,
+			},
+			{
+				opts: NewComponentOptions{Type: "pubsub.redis", Name: "pubsub", Host: "redis:6379", PasswordSecret: "redis", SecretStore: "local"},
+				expected: `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: pubsub
+spec:
+  type: pubsub.redis
+  version: v1
+  metadata:
+  - name: redisHost
+    value: redis:6379
+  - name: redisPassword
+    secretKeyRef:
+      name: redis
+      key: redis
+auth:
+  secretStore: local
+`,
+			},
+			{
+				opts: NewComponentOptions{Type: "secretstores.local.file", Name: "local", Metadata: map[string]string{"nestedSeparator": ".", "multiValued": "true"}},
+				expected: `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: local
+spec:
+  type: secretstores.local.file
+  version: v1
+  metadata:
+  - name: secretsFile
+    value: <path-to-secrets-file>
+  - name: nestedSeparator
+    value: .
+  - name: multiValued
+    value: "true"
+`,
+			},
+		} {
+			t.Run(tc.opts.Type, func(t *testing.T) {
+				tc.opts.TargetDir = t.TempDir()
+				filePath, err := NewComponent(tc.opts)
+				require.NoError(t, err)
+				assert.Equal(t, filepath.Join(tc.opts.TargetDir, tc.opts.Name+".yaml"), filePath)
+
+				b, err := os.ReadFile(filePath)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, string(b))
+			})
+		}
+	})
+
+	t.Run("refuses to overwrite without force", func(t *testing.T) {
+		opts := NewComponentOptions{Type: "state.redis", Name: "statestore", TargetDir: t.TempDir()}
+		filePath, err := NewComponent(opts)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filePath, []byte("edited"), 0o600))
+
+		_, err = NewComponent(opts)
+		require.ErrorContains(t, err, "already exists")
+		b, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.Equal(t, "edited", string(b))
+
+		opts.Force = true
+		_, err = NewComponent(opts)
+		require.NoError(t, err)
+		b, err = os.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "state.redis")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := NewComponent(NewComponentOptions{Type: "state.unknown", Name: "statestore", TargetDir: t.TempDir()})
+		assert.ErrorContains(t, err, "supported types are: pubsub.redis, secretstores.local.file, state.redis")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := NewComponent(NewComponentOptions{Type: "state.redis", Name: "State_Store", TargetDir: t.TempDir()})
+		assert.ErrorContains(t, err, "invalid component name")
+	})
+}
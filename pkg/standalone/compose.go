@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"net"
+	"os"
+	path_filepath "path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// composeFileVersion is the compose file format of the sidecar block, which docker-compose v1 needs to merge it.
+	composeFileVersion = "3"
+	// composeResourcesPath is where the components directory is mounted in the sidecar container.
+	composeResourcesPath = "/components"
+	// composeHostGateway is the hostname the containers outside of the network of init reach the host with.
+	composeHostGateway = "host.docker.internal"
+	// placementContainerPort is the port the placement service listens on in its container.
+	placementContainerPort = 50005
+)
+
+// ComposeSidecarOptions are the options of ComposeSidecar.
+type ComposeSidecarOptions struct {
+	DaprRuntimePath string
+	AppID           string
+	AppPort         int
+	// AppService is the compose service of the app, the app ID by default.
+	AppService string
+	// DockerNetwork is the network of the containers of init, the one of the install by default.
+	DockerNetwork string
+	// RuntimeVersion is the version of the runtime image, the installed one by default.
+	RuntimeVersion string
+	// ComponentsPath is the components directory mounted in the sidecar, the one of the install by default.
+	ComponentsPath string
+	DaprHTTPPort   int
+	DaprGRPCPort   int
+}
+
+// ComposeSidecar is a compose service block of a sidecar, along with how it reaches the placement service.
+type ComposeSidecar struct {
+	// File is the compose file with the sidecar service, to merge into the compose file of the app.
+	File string
+	// PlacementAddress is the address the sidecar reaches the placement service at.
+	PlacementAddress string
+	// InNetwork is set if the sidecar reaches the placement container by its alias in the network of init, rather
+	// than through the host.
+	InNetwork bool
+}
+
+type composeFile struct {
+	Version  string                    `yaml:"version"`
+	Services map[string]composeService `yaml:"services"`
+	Networks map[string]composeNetwork `yaml:"networks,omitempty"`
+}
+
+type composeService struct {
+	Image       string   `yaml:"image,omitempty"`
+	Command     []string `yaml:"command,omitempty"`
+	NetworkMode string   `yaml:"network_mode,omitempty"`
+	Networks    []string `yaml:"networks,omitempty"`
+	ExtraHosts  []string `yaml:"extra_hosts,omitempty"`
+	Volumes     []string `yaml:"volumes,omitempty"`
+	DependsOn   []string `yaml:"depends_on,omitempty"`
+}
+
+type composeNetwork struct {
+	External bool `yaml:"external"`
+}
+
+// GenerateComposeSidecar returns a compose file with a sidecar service for the app service of a compose project,
+// built from the runtime image of the install. The sidecar shares the network namespace of the app service, so that
+// they reach each other on localhost as with dapr run, and the app service is attached to the network of init to
+// reach the placement container by its network alias. Outside of a network, the placement service is reached
+// through the host. The file is meant to be merged with the one of the app, with docker-compose -f app.yml -f
+// sidecar.yml, which adds the network to the networks of the app service.
+func GenerateComposeSidecar(opts ComposeSidecarOptions) (*ComposeSidecar, error) {
+	if opts.AppID == "" {
+		return nil, fmt.Errorf("the app ID is required")
+	}
+	if opts.AppService == "" {
+		opts.AppService = opts.AppID
+	}
+	daprDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := ReadInstallManifest(daprDir)
+	if err != nil {
+		return nil, err
+	}
+
+	image := ""
+	placementHostPort := defaultPlacementHostPort()
+	if manifest != nil {
+		if opts.DockerNetwork == "" {
+			opts.DockerNetwork = manifest.DockerNetwork
+		}
+		if opts.ComponentsPath == "" {
+			opts.ComponentsPath = manifest.ComponentsPath
+		}
+		// The placement image is the runtime image, run with another entrypoint.
+		if opts.RuntimeVersion == "" || opts.RuntimeVersion == manifest.RuntimeVersion {
+			image = manifest.PlacementImage
+			opts.RuntimeVersion = manifest.RuntimeVersion
+		}
+		for _, c := range manifest.Containers {
+			if c.Name == profileContainerName(DaprPlacementContainerName, manifest.DockerNetwork) && len(c.HostPorts) > 0 {
+				placementHostPort = c.HostPorts[0]
+			}
+		}
+	}
+	if opts.RuntimeVersion == "" {
+		return nil, fmt.Errorf("no runtime is installed in %s, run `dapr init` or give the runtime version", daprDir)
+	}
+	if image == "" {
+		if image, err = runtimeImage(opts.RuntimeVersion); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ComponentsPath == "" {
+		opts.ComponentsPath = GetDaprComponentsPath(daprDir)
+	}
+	if opts.ComponentsPath, err = path_filepath.Abs(opts.ComponentsPath); err != nil {
+		return nil, err
+	}
+
+	sidecar := &ComposeSidecar{InNetwork: opts.DockerNetwork != ""}
+	if sidecar.InNetwork {
+		sidecar.PlacementAddress = net.JoinHostPort(DaprPlacementContainerName, strconv.Itoa(placementContainerPort))
+	} else {
+		sidecar.PlacementAddress = net.JoinHostPort(composeHostGateway, strconv.Itoa(placementHostPort))
+	}
+	sidecar.File, err = composeSidecarFile(opts, image, sidecar.PlacementAddress)
+	if err != nil {
+		return nil, err
+	}
+	return sidecar, nil
+}
+
+// runtimeImage returns the runtime image of version in the default registry. Unlike utils.GetDefaultRegistry, it
+// prints nothing, as the compose file goes to stdout.
+func runtimeImage(version string) (string, error) {
+	registry := dockerContainerRegistryName
+	if strings.EqualFold(os.Getenv("DAPR_DEFAULT_IMAGE_REGISTRY"), githubContainerRegistryName) {
+		registry = githubContainerRegistryName
+	}
+	name, err := resolveImageURI(daprImageInfo{
+		ghcrImageName:      daprGhcrImageName,
+		dockerHubImageName: daprDockerImageName,
+		imageRegistryName:  registry,
+	})
+	if err != nil {
+		return "", err
+	}
+	return name + ":" + strings.TrimPrefix(version, "v"), nil
+}
+
+// composeSidecarFile returns the compose file of the sidecar of opts, running image and reaching the placement
+// service at placementAddress.
+func composeSidecarFile(opts ComposeSidecarOptions, image, placementAddress string) (string, error) {
+	command := []string{
+		"./daprd",
+		"--app-id", opts.AppID,
+		"--dapr-http-port", strconv.Itoa(opts.DaprHTTPPort),
+		"--dapr-grpc-port", strconv.Itoa(opts.DaprGRPCPort),
+		"--placement-host-address", placementAddress,
+		"--resources-path", composeResourcesPath,
+	}
+	if opts.AppPort > 0 {
+		command = append(command, "--app-port", strconv.Itoa(opts.AppPort))
+	}
+
+	// The app service keeps the default network of the project along with the one of init.
+	app := composeService{}
+	file := composeFile{Version: composeFileVersion}
+	if opts.DockerNetwork != "" {
+		app.Networks = []string{"default", opts.DockerNetwork}
+		file.Networks = map[string]composeNetwork{opts.DockerNetwork: {External: true}}
+	} else {
+		app.ExtraHosts = []string{composeHostGateway + ":host-gateway"}
+	}
+	file.Services = map[string]composeService{
+		opts.AppService: app,
+		opts.AppID + "-dapr": {
+			Image:       image,
+			Command:     command,
+			NetworkMode: "service:" + opts.AppService,
+			Volumes:     []string{opts.ComponentsPath + ":" + composeResourcesPath + ":ro"},
+			DependsOn:   []string{opts.AppService},
+		},
+	}
+	b, err := yaml.Marshal(file)
+	if err != nil {
+		return "", fmt.Errorf("error generating the compose file: %w", err)
+	}
+	return string(b), nil
+}
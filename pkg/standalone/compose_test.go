@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func writeComposeManifest(t *testing.T, network string) string {
+	t.Helper()
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	m := &InstallManifest{
+		RuntimeVersion: "1.11.0",
+		PlacementImage: "ghcr.io/dapr/dapr:1.11.0",
+		DockerNetwork:  network,
+		ComponentsPath: filepath.Join(daprDir, "components"),
+		Containers:     []ManifestContainer{{Name: DaprPlacementContainerName, Image: "ghcr.io/dapr/dapr:1.11.0", HostPorts: []int{6060}}},
+	}
+	require.NoError(t, writeInstallManifest(daprDir, m))
+	return runtimePath
+}
+
+func TestGenerateComposeSidecar(t *testing.T) {
+	runtimePath := writeComposeManifest(t, "dapr-net")
+	sidecar, err := GenerateComposeSidecar(ComposeSidecarOptions{DaprRuntimePath: runtimePath, AppID: "x", AppPort: 3000, DaprHTTPPort: 3500, DaprGRPCPort: 50001})
+	require.NoError(t, err)
+	assert.True(t, sidecar.InNetwork)
+	assert.Equal(t, "dapr_placement:50005", sidecar.PlacementAddress)
+
+	var file composeFile
+	require.NoError(t, yaml.Unmarshal([]byte(sidecar.File), &file))
+	assert.Equal(t, composeFileVersion, file.Version)
+	assert.Equal(t, map[string]composeNetwork{"dapr-net": {External: true}}, file.Networks)
+	assert.Equal(t, []string{"default", "dapr-net"}, file.Services["x"].Networks)
+	daprd := file.Services["x-dapr"]
+	assert.Equal(t, "ghcr.io/dapr/dapr:1.11.0", daprd.Image)
+	assert.Equal(t, "service:x", daprd.NetworkMode)
+	assert.Equal(t, []string{"x"}, daprd.DependsOn)
+	assert.Equal(t, []string{filepath.Join(runtimePath, DefaultDaprDirName, "components") + ":/components:ro"}, daprd.Volumes)
+	assert.Equal(t, []string{
+		"./daprd", "--app-id", "x", "--dapr-http-port", "3500", "--dapr-grpc-port", "50001",
+		"--placement-host-address", "dapr_placement:50005", "--resources-path", "/components", "--app-port", "3000",
+	}, daprd.Command)
+}
+
+func TestGenerateComposeSidecarWithoutNetwork(t *testing.T) {
+	runtimePath := writeComposeManifest(t, "")
+	sidecar, err := GenerateComposeSidecar(ComposeSidecarOptions{DaprRuntimePath: runtimePath, AppID: "x", AppService: "web", RuntimeVersion: "1.11.0"})
+	require.NoError(t, err)
+	assert.False(t, sidecar.InNetwork)
+	assert.Equal(t, "host.docker.internal:6060", sidecar.PlacementAddress)
+
+	var file composeFile
+	require.NoError(t, yaml.Unmarshal([]byte(sidecar.File), &file))
+	assert.Empty(t, file.Networks)
+	assert.Equal(t, []string{"host.docker.internal:host-gateway"}, file.Services["web"].ExtraHosts)
+	assert.Equal(t, "service:web", file.Services["x-dapr"].NetworkMode)
+	assert.NotContains(t, file.Services["x-dapr"].Command, "--app-port")
+
+	_, err = GenerateComposeSidecar(ComposeSidecarOptions{DaprRuntimePath: t.TempDir(), AppID: "x"})
+	assert.ErrorContains(t, err, "no runtime is installed")
+}
+
+// composeCommand returns the compose command of the docker CLI, or nil if there is none.
+func composeCommand() []string {
+	if _, err := exec.LookPath("docker-compose"); err == nil {
+		return []string{"docker-compose"}
+	}
+	if err := exec.Command("docker", "compose", "version").Run(); err == nil {
+		return []string{"docker", "compose"}
+	}
+	return nil
+}
+
+func TestComposeSidecarMerge(t *testing.T) {
+	compose := composeCommand()
+	if compose == nil {
+		t.Skip("docker-compose is not installed")
+	}
+	runtimePath := writeComposeManifest(t, "dapr-net")
+	sidecar, err := GenerateComposeSidecar(ComposeSidecarOptions{DaprRuntimePath: runtimePath, AppID: "x", AppPort: 3000, DaprHTTPPort: 3500, DaprGRPCPort: 50001})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "a.yml")
+	require.NoError(t, os.WriteFile(base, []byte("version: \"3\"\nservices:\n  x:\n    image: myapp\n    ports:\n      - \"3000:3000\"\n"), 0o644))
+	override := filepath.Join(dir, "b.yml")
+	require.NoError(t, os.WriteFile(override, []byte(sidecar.File), 0o644))
+
+	args := append(compose[1:], "-f", base, "-f", override, "config")
+	out, err := exec.Command(compose[0], args...).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	var merged struct {
+		Services map[string]struct {
+			Image       string         `yaml:"image"`
+			NetworkMode string         `yaml:"network_mode"`
+			Networks    map[string]any `yaml:"networks"`
+		} `yaml:"services"`
+	}
+	require.NoError(t, yaml.Unmarshal(out, &merged))
+	assert.Equal(t, "myapp", merged.Services["x"].Image)
+	assert.Contains(t, merged.Services["x"].Networks, "dapr-net")
+	assert.Contains(t, merged.Services["x"].Networks, "default")
+	assert.Equal(t, "service:x", merged.Services["x-dapr"].NetworkMode)
+}
@@ -14,6 +14,8 @@ limitations under the License.
 package standalone
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -21,6 +23,7 @@ import (
 	path_filepath "path/filepath"
 	"strings"
 
+	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/utils"
 )
 
@@ -34,7 +37,7 @@ func loadContainerFromReader(in io.Reader, containerRuntime string) error {
 	}
 	defer stdin.Close()
 
-	subProcess.Stdout = os.Stdout
+	subProcess.Stdout = print.StatusWriter(os.Stdout)
 	subProcess.Stderr = os.Stderr
 
 	if err = subProcess.Start(); err != nil {
@@ -80,7 +83,7 @@ func confirmContainerIsRunningOrExists(containerName string, isRunning bool, run
 	}
 
 	args = append(args, "--format", "{{.Names}}")
-	response, err := utils.RunCmdAndWait(runtimeCmd, args...)
+	response, err := runCmd(runtimeCmd, args...)
 	response = strings.TrimSuffix(response, "\n")
 
 	// If 'docker ps' failed due to some reason.
@@ -88,8 +91,9 @@ func confirmContainerIsRunningOrExists(containerName string, isRunning bool, run
 		//nolint
 		return false, fmt.Errorf("unable to confirm whether %s is running or exists. error\n%v", containerName, err.Error())
 	}
-	// 'docker ps' worked fine, but the response did not have the container name.
-	if response == "" || response != containerName {
+	// 'docker ps' worked fine, but the response did not have the container name. The filter matches the names
+	// which contain it, such as the ones of the containers of the other profiles.
+	if !utils.Contains(strings.Split(response, "\n"), containerName) {
 		if isRunning {
 			return false, fmt.Errorf("container %s is not running", containerName)
 		}
@@ -99,6 +103,53 @@ func confirmContainerIsRunningOrExists(containerName string, isRunning bool, run
 	return true, nil
 }
 
+// Actions of init on one of its containers, see existingContainerAction.
+type containerAction int
+
+const (
+	// containerCreate runs a new container, as there is none.
+	containerCreate containerAction = iota
+	// containerStart starts the stopped container.
+	containerStart
+	// containerSkip leaves the running container as it is.
+	containerSkip
+)
+
+// existingContainerAction returns what init does with the container containerName, which it runs with one of
+// images, along with the image of the existing container if there is one: create it, start it if it is stopped, or
+// nothing if it is running. A container with another image is a conflict, as reusing it would leave the environment
+// at another version than the one installed. With force, the existing container is removed to be created again.
+func existingContainerAction(ctx context.Context, containerName string, images []string, runtimeCmd string, force bool) (containerAction, string, error) {
+	exists, err := confirmContainerIsRunningOrExists(containerName, false, runtimeCmd)
+	if err != nil || !exists {
+		return containerCreate, "", err
+	}
+	out, err := runCmd(runtimeCmd, "inspect", containerName)
+	if err != nil {
+		return containerCreate, "", fmt.Errorf("error inspecting the %s container: %w", containerName, err)
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if errors.Is(err, errContainerNotFound) {
+		return containerCreate, "", nil
+	} else if err != nil {
+		return containerCreate, "", err
+	}
+	image := details.Config.Image
+	if force {
+		if _, err = runContainerCmd(ctx, runtimeCmd, "rm", "--force", containerName); err != nil {
+			return containerCreate, image, fmt.Errorf("error removing the %s container: %w", containerName, err)
+		}
+		return containerCreate, image, nil
+	}
+	if !utils.Contains(images, image) {
+		return containerCreate, image, fmt.Errorf("the %s container exists with the image %s instead of %s, %s", containerName, image, images[0], errInstallTemplate)
+	}
+	if details.State.Running {
+		return containerSkip, image, nil
+	}
+	return containerStart, image, nil
+}
+
 func isContainerRunError(err error) bool {
 	//nolint
 	if exitError, ok := err.(*exec.ExitError); ok {
@@ -122,12 +173,10 @@ func parseContainerRuntimeError(component string, err error) error {
 	return err
 }
 
-func tryPullImage(imageName, containerRuntime string) bool {
+func tryPullImage(ctx context.Context, imageName, containerRuntime string) bool {
 	runtimeCmd := utils.GetContainerRuntimeCmd(containerRuntime)
-	args := []string{
-		"pull",
-		imageName,
-	}
-	_, err := utils.RunCmdAndWait(runtimeCmd, args...)
+	args := append([]string{"pull"}, platformArgs()...)
+	args = append(args, imageName)
+	_, err := runContainerCmd(ctx, runtimeCmd, args...)
 	return err == nil
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeContainerRuntime answers ps and inspect for the container named name, which doesn't exist if inspect is
+// empty, and records the other commands.
+func fakeContainerRuntime(t *testing.T, name, inspect string) *[]string {
+	t.Helper()
+	var commands []string
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(_ string, args ...string) (string, error) {
+		switch args[0] {
+		case "ps":
+			if inspect == "" {
+				return "", nil
+			}
+			return name + "\n", nil
+		case "inspect":
+			if inspect == "" {
+				return "[]", errors.New("no such object")
+			}
+			return inspect, nil
+		}
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+	return &commands
+}
+
+func TestExistingContainerAction(t *testing.T) {
+	images := []string{"ghcr.io/dapr/placement:1.11.0", "docker.io/daprio/placement:1.11.0"}
+	tests := []struct {
+		name    string
+		inspect string
+		force   bool
+		action  containerAction
+		err     string
+		removed bool
+	}{
+		{name: "missing", action: containerCreate},
+		{name: "running", inspect: `[{"State": {"Running": true}, "Config": {"Image": "docker.io/daprio/placement:1.11.0"}}]`, action: containerSkip},
+		{name: "stopped", inspect: `[{"State": {"Running": false}, "Config": {"Image": "ghcr.io/dapr/placement:1.11.0"}}]`, action: containerStart},
+		{name: "other version", inspect: `[{"State": {"Running": true}, "Config": {"Image": "ghcr.io/dapr/placement:1.10.0"}}]`, err: "exists with the image ghcr.io/dapr/placement:1.10.0 instead of ghcr.io/dapr/placement:1.11.0"},
+		{name: "forced", inspect: `[{"State": {"Running": true}, "Config": {"Image": "ghcr.io/dapr/placement:1.10.0"}}]`, force: true, action: containerCreate, removed: true},
+		{name: "forced missing", force: true, action: containerCreate},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commands := fakeContainerRuntime(t, DaprPlacementContainerName, tc.inspect)
+			action, _, err := existingContainerAction(context.Background(), DaprPlacementContainerName, images, "docker", tc.force)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.action, action)
+			if tc.removed {
+				assert.Equal(t, []string{"rm --force " + DaprPlacementContainerName}, *commands)
+			} else {
+				assert.Empty(t, *commands)
+			}
+		})
+	}
+}
+
+func TestRunZipkinExistingContainer(t *testing.T) {
+	info := initInfo{imageRegistryURL: "registry.example.com", containerRuntime: "docker", ports: InstallPorts{}.withDefaults()}
+	image := "registry.example.com/" + zipkinGhcrImageName
+
+	commands := fakeContainerRuntime(t, DaprZipkinContainerName, "")
+	require.NoError(t, runZipkin(context.Background(), info))
+	require.Len(t, *commands, 2)
+	assert.Equal(t, "image inspect "+image, (*commands)[0])
+	assert.True(t, strings.HasPrefix((*commands)[1], "run --name dapr_zipkin"), (*commands)[1])
+
+	commands = fakeContainerRuntime(t, DaprZipkinContainerName, `[{"State": {"Running": true}, "Config": {"Image": "`+image+`"}}]`)
+	require.NoError(t, runZipkin(context.Background(), info))
+	assert.Empty(t, *commands)
+
+	commands = fakeContainerRuntime(t, DaprZipkinContainerName, `[{"State": {"Running": false}, "Config": {"Image": "`+image+`"}}]`)
+	require.NoError(t, runZipkin(context.Background(), info))
+	assert.Equal(t, []string{"start dapr_zipkin"}, *commands)
+
+	commands = fakeContainerRuntime(t, DaprZipkinContainerName, `[{"State": {"Running": true}, "Config": {"Image": "openzipkin/zipkin:2.23"}}]`)
+	assert.ErrorContains(t, runZipkin(context.Background(), info), "please run `dapr uninstall` first")
+	assert.Empty(t, *commands)
+
+	// --force recreates the container and pulls its image again, even when it is present.
+	info.force = true
+	commands = fakeContainerRuntime(t, DaprZipkinContainerName, `[{"State": {"Running": true}, "Config": {"Image": "openzipkin/zipkin:2.23"}}]`)
+	require.NoError(t, runZipkin(context.Background(), info))
+	require.Len(t, *commands, 4)
+	assert.Equal(t, "rm --force dapr_zipkin", (*commands)[0])
+	assert.Equal(t, "image inspect "+image, (*commands)[1])
+	assert.True(t, strings.HasPrefix((*commands)[2], "pull "), (*commands)[2])
+	assert.True(t, strings.HasPrefix((*commands)[3], "run --name dapr_zipkin"), (*commands)[3])
+}
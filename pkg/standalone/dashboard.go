@@ -14,14 +14,61 @@ limitations under the License.
 package standalone
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/phayes/freeport"
+
+	"github.com/dapr/cli/pkg/age"
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	dashboardRecordFileName = "dashboard.json"
+	// dashboardReadyTimeout is how long the dashboard has to answer once started.
+	dashboardReadyTimeout = 30 * time.Second
+	// dashboardStopGrace is how long the dashboard has to exit once asked to, before it is killed.
+	dashboardStopGrace = 5 * time.Second
 )
 
+// dashboardPollInterval is how often the dashboard is probed while it starts. It is a variable for the tests.
+var dashboardPollInterval = 250 * time.Millisecond
+
+// ErrDashboardNotInstalled is returned by RunDashboard when init didn't install the dashboard.
+var ErrDashboardNotInstalled = errors.New("the dashboard isn't installed")
+
+// DashboardRecord describes the dashboard started by RunDashboard. The record is kept in the dapr install dir while
+// the dashboard runs.
+type DashboardRecord struct {
+	PID     int       `json:"pid"`
+	CliPID  int       `json:"cliPid"`
+	Port    int       `json:"port"`
+	URL     string    `json:"url"`
+	Started time.Time `json:"started"`
+}
+
+// DashboardOptions are the options of RunDashboard.
+type DashboardOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath string
+	// Port is the port the dashboard listens on, a free one if 0.
+	Port int
+	// Logs receives the output of the dashboard.
+	Logs io.Writer
+	// OnReady is called with the URL of the dashboard once it answers.
+	OnReady func(url string)
+}
+
 // NewDashboardCmd creates the command to run dashboard.
 func NewDashboardCmd(inputInstallPath string, port int) (*exec.Cmd, error) {
 	if port == 0 {
@@ -44,3 +91,153 @@ func NewDashboardCmd(inputInstallPath string, port int) (*exec.Cmd, error) {
 		Stdout: os.Stdout,
 	}, nil
 }
+
+// RunDashboard runs the dashboard binary installed by init until ctx is done, which stops it, or until it exits.
+// It returns ErrDashboardNotInstalled if there is no dashboard binary, and an error if a dashboard started by
+// another RunDashboard still runs.
+func RunDashboard(ctx context.Context, opts DashboardOptions) error {
+	daprDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return err
+	}
+	if record := runningDashboard(daprDir); record != nil {
+		return fmt.Errorf("the dashboard is already running at %s, with pid %d", record.URL, record.PID)
+	}
+	cmd, err := NewDashboardCmd(opts.DaprRuntimePath, opts.Port)
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(cmd.Path); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w in %s", ErrDashboardNotInstalled, daprDir)
+	}
+	port, _ := strconv.Atoi(cmd.Args[len(cmd.Args)-1])
+	cmd.Stdout, cmd.Stderr = opts.Logs, opts.Logs
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("error starting the dashboard: %w", err)
+	}
+	exited := make(chan struct{})
+	var exitErr error
+	go func() {
+		exitErr = cmd.Wait()
+		close(exited)
+	}()
+	defer stopDashboard(cmd.Process, exited)
+
+	record := DashboardRecord{PID: cmd.Process.Pid, CliPID: os.Getpid(), Port: port, URL: fmt.Sprintf("http://localhost:%d", port), Started: time.Now()}
+	if err = writeDashboardRecord(daprDir, record); err != nil {
+		return err
+	}
+	defer os.Remove(dashboardRecordPath(daprDir))
+
+	if err = waitForDashboard(ctx, record.URL, exited, &exitErr); err != nil {
+		return err
+	}
+	if opts.OnReady != nil {
+		opts.OnReady(record.URL)
+	}
+	select {
+	case <-exited:
+		return fmt.Errorf("the dashboard exited: %v", exitErr)
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// waitForDashboard waits for the dashboard at url to answer, for at most dashboardReadyTimeout.
+func waitForDashboard(ctx context.Context, url string, exited <-chan struct{}, exitErr *error) error {
+	ctx, cancel := context.WithTimeout(ctx, dashboardReadyTimeout)
+	defer cancel()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := utils.HTTPClient().Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("the dashboard exited while starting: %v", *exitErr)
+		case <-ctx.Done():
+			return fmt.Errorf("the dashboard doesn't answer at %s", url)
+		case <-time.After(dashboardPollInterval):
+		}
+	}
+}
+
+// stopDashboard asks the dashboard process to exit, and kills it if it doesn't within dashboardStopGrace.
+func stopDashboard(p *os.Process, exited <-chan struct{}) {
+	select {
+	case <-exited:
+		return
+	default:
+	}
+	if err := TerminateAppProcess(p); err == nil {
+		select {
+		case <-exited:
+			return
+		case <-time.After(dashboardStopGrace):
+		}
+	}
+	_ = KillAppProcess(p)
+	<-exited
+}
+
+func dashboardRecordPath(daprDir string) string {
+	return filepath.Join(daprDir, dashboardRecordFileName)
+}
+
+func writeDashboardRecord(daprDir string, record DashboardRecord) error {
+	b, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(dashboardRecordPath(daprDir), b, 0o600); err != nil {
+		return fmt.Errorf("error writing the dashboard record: %w", err)
+	}
+	return nil
+}
+
+// runningDashboard returns the record of the dashboard started by RunDashboard in daprDir, or nil if it doesn't run.
+func runningDashboard(daprDir string) *DashboardRecord {
+	b, err := os.ReadFile(dashboardRecordPath(daprDir))
+	if err != nil {
+		return nil
+	}
+	var record DashboardRecord
+	if err = json.Unmarshal(b, &record); err != nil || !pidAlive(record.PID) {
+		return nil
+	}
+	return &record
+}
+
+// GetDashboardRecord returns the record of the dashboard started by the dashboard command, or nil if it doesn't run.
+// daprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func GetDashboardRecord(daprRuntimePath string) (*DashboardRecord, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	return runningDashboard(daprDir), nil
+}
+
+// dashboardComponent returns the status of the dashboard binary of daprDir, and whether it is installed.
+func dashboardComponent(daprDir, daprRuntimePath string) (EnvironmentComponent, bool) {
+	c := EnvironmentComponent{Name: dashboardFilePrefix, Kind: "binary", Status: RuntimeInstalled}
+	if _, err := os.Stat(binaryFilePathWithDir(getDaprBinPath(daprDir), dashboardFilePrefix)); err != nil {
+		return c, false
+	}
+	if version, err := GetDashboardVersion(daprRuntimePath); err == nil {
+		c.Version = strings.TrimSpace(version)
+	}
+	if record := runningDashboard(daprDir); record != nil {
+		c.Status = ContainerRunning
+		c.Uptime = age.GetAge(record.Started)
+		c.Ports = []string{"localhost:" + strconv.Itoa(record.Port)}
+	}
+	return c, true
+}
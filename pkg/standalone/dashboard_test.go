@@ -14,9 +14,17 @@ limitations under the License.
 package standalone
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDashboardRun(t *testing.T) {
@@ -39,3 +47,81 @@ func TestDashboardRun(t *testing.T) {
 		assert.NotEqual(t, cmd.Args[2], "0")
 	})
 }
+
+func TestRunDashboardNotInstalled(t *testing.T) {
+	runtimePath := t.TempDir()
+	err := RunDashboard(context.Background(), DashboardOptions{DaprRuntimePath: runtimePath, Port: 9090})
+	assert.ErrorIs(t, err, ErrDashboardNotInstalled)
+}
+
+func TestRunningDashboard(t *testing.T) {
+	alive := pidAlive
+	t.Cleanup(func() { pidAlive = alive })
+	pidAlive = func(pid int) bool { return pid == 42 }
+
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	assert.Nil(t, runningDashboard(daprDir))
+	record := DashboardRecord{PID: 42, Port: 9090, URL: "http://localhost:9090", Started: time.Now()}
+	require.NoError(t, writeDashboardRecord(daprDir, record))
+	running := runningDashboard(daprDir)
+	require.NotNil(t, running)
+	assert.Equal(t, 9090, running.Port)
+
+	// A dashboard still running isn't started again.
+	err := RunDashboard(context.Background(), DashboardOptions{DaprRuntimePath: runtimePath})
+	assert.ErrorContains(t, err, "already running at http://localhost:9090")
+
+	record.PID = 43
+	require.NoError(t, writeDashboardRecord(daprDir, record))
+	assert.Nil(t, runningDashboard(daprDir))
+}
+
+func TestDashboardComponent(t *testing.T) {
+	alive := pidAlive
+	t.Cleanup(func() { pidAlive = alive })
+	pidAlive = func(pid int) bool { return pid == 42 }
+
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	_, ok := dashboardComponent(daprDir, runtimePath)
+	assert.False(t, ok)
+
+	binDir := getDaprBinPath(daprDir)
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.WriteFile(binaryFilePathWithDir(binDir, dashboardFilePrefix), []byte("#!/bin/sh\necho 0.14.0\n"), 0o755))
+	c, ok := dashboardComponent(daprDir, runtimePath)
+	require.True(t, ok)
+	assert.Equal(t, RuntimeInstalled, c.Status)
+
+	require.NoError(t, writeDashboardRecord(daprDir, DashboardRecord{PID: 42, Port: 9090, Started: time.Now()}))
+	c, _ = dashboardComponent(daprDir, runtimePath)
+	assert.Equal(t, ContainerRunning, c.Status)
+	assert.Equal(t, []string{"localhost:9090"}, c.Ports)
+}
+
+func TestWaitForDashboard(t *testing.T) {
+	interval := dashboardPollInterval
+	t.Cleanup(func() { dashboardPollInterval = interval })
+	dashboardPollInterval = 10 * time.Millisecond
+
+	ready := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			ready = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	var exitErr error
+	assert.NoError(t, waitForDashboard(context.Background(), ts.URL, make(chan struct{}), &exitErr))
+
+	exited := make(chan struct{})
+	exitErr = errors.New("exit status 1")
+	close(exited)
+	err := waitForDashboard(context.Background(), "http://127.0.0.1:1", exited, &exitErr)
+	assert.ErrorContains(t, err, "the dashboard exited while starting: exit status 1")
+}
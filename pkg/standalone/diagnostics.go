@@ -0,0 +1,409 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dapr/cli/pkg/secret"
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	diagnosticsDirName = "diagnostics"
+	// containerLogTailLines is the number of lines of each container's logs included in a diagnostics bundle.
+	containerLogTailLines = "200"
+	// cliLogTailLines is the number of lines of the CLI log included in an exported diagnostics bundle.
+	cliLogTailLines = 1000
+)
+
+// sensitiveNameRegexp matches the names of the component metadata items and the environment variables whose values
+// are left out of the diagnostics bundles.
+var sensitiveNameRegexp = regexp.MustCompile(`(?i)pass|secret|token|key|credential|connectionstring|auth|sas|cert`)
+
+// writeInitDiagnosticsBundle writes a zip file with the step log, environment information, the install manifest, the
+// CLI log and recent container logs to the diagnostics directory under the dapr install dir, and returns its path.
+func writeInitDiagnosticsBundle(info initInfo, stepLog *initStepLog, initErr error) (string, error) {
+	diagnosticsDir := path_filepath.Join(info.installDir, diagnosticsDirName)
+	err := os.MkdirAll(diagnosticsDir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("error creating diagnostics directory %s: %w", diagnosticsDir, err)
+	}
+
+	bundlePath := path_filepath.Join(diagnosticsDir, "init-"+time.Now().UTC().Format("20060102T150405Z")+".zip")
+	files := map[string]string{
+		"error.txt":       fmt.Sprintf("%s\n", initErr),
+		"steps.log":       stepLog.String(),
+		"environment.txt": initEnvironmentInfo(info),
+	}
+	if !info.slimMode {
+		runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
+		for _, container := range []string{DaprPlacementContainerName, DaprRedisContainerName, DaprZipkinContainerName} {
+			containerName := profileContainerName(container, info.dockerNetwork)
+			// Errors are included in the bundle, the container may not have been created yet.
+			out, err := runCmd(runtimeCmd, "logs", "--tail", containerLogTailLines, containerName)
+			if err != nil {
+				out = fmt.Sprintf("%s\nerror getting logs: %s\n", out, err)
+			}
+			files["containers/"+containerName+".log"] = out
+		}
+	}
+
+	// Include the CLI log and its last rotation.
+	cliLogPath := getCLILogFilePath(info.installDir)
+	for _, logPath := range []string{cliLogPath, fmt.Sprintf("%s.%d", cliLogPath, 1)} {
+		if b, err := os.ReadFile(logPath); err == nil {
+			files["logs/"+path_filepath.Base(logPath)] = string(b)
+		}
+	}
+	// The manifest is the one of the previous install, if any, as init only writes it once it succeeds.
+	if b, err := os.ReadFile(GetInstallManifestPath(info.installDir)); err == nil {
+		files[installManifestFileName] = string(b)
+	}
+
+	if err = writeDiagnosticsBundle(bundlePath, files); err != nil {
+		return "", err
+	}
+	return bundlePath, nil
+}
+
+// writeDiagnosticsBundle writes files, by their name in the bundle, to a zip file at bundlePath.
+func writeDiagnosticsBundle(bundlePath string, files map[string]string) error {
+	f, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("error creating diagnostics bundle %s: %w", bundlePath, err)
+	}
+	defer f.Close()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("error writing %s to diagnostics bundle: %w", name, err)
+		}
+		if _, err = w.Write([]byte(files[name])); err != nil {
+			return fmt.Errorf("error writing %s to diagnostics bundle: %w", name, err)
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return fmt.Errorf("error writing diagnostics bundle %s: %w", bundlePath, err)
+	}
+	return f.Close()
+}
+
+// ExportDiagnosticsOptions are the options of ExportDiagnostics.
+type ExportDiagnosticsOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath  string
+	DockerNetwork    string
+	ContainerRuntime string
+	// ComponentsPath is the --components-path command line flag, as for ResolveComponentsPath.
+	ComponentsPath string
+	// IncludeComponents includes the contents of the component files, with the values of their secrets redacted.
+	IncludeComponents bool
+	// Output is the path of the bundle, by default a new file in the diagnostics directory of the install dir.
+	Output string
+}
+
+// DiagnosticsBundle describes a bundle written by ExportDiagnostics.
+type DiagnosticsBundle struct {
+	Path  string   `json:"path"`
+	Size  int64    `json:"size"`
+	Files []string `json:"files"`
+}
+
+// ExportDiagnostics writes a zip file with what support requests need to know of the local environment: the
+// versions of the CLI and the runtime, the install manifest and the lockfile, the tail of the CLI log, the version
+// and info of the container runtime, the inspect output and recent logs of the containers set up by init, the list
+// of the component files, along with their contents if IncludeComponents is set, the run records of the instances and
+// the results of doctor. The values of the secrets of the components and of the environment of the containers are
+// redacted, and the certificates, the keys and the CLI config file are left out. What can't be collected is listed
+// in errors.txt, the bundle is written regardless.
+func ExportDiagnostics(opts ExportDiagnosticsOptions) (*DiagnosticsBundle, error) {
+	daprDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	bundlePath := opts.Output
+	if bundlePath == "" {
+		diagnosticsDir := path_filepath.Join(daprDir, diagnosticsDirName)
+		if err = os.MkdirAll(diagnosticsDir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating diagnostics directory %s: %w", diagnosticsDir, err)
+		}
+		bundlePath = path_filepath.Join(diagnosticsDir, "diagnostics-"+time.Now().UTC().Format("20060102T150405Z")+".zip")
+	}
+
+	files := map[string]string{}
+	var collectErrs []string
+	collectErr := func(name string, err error) {
+		collectErrs = append(collectErrs, fmt.Sprintf("%s: %s", name, err))
+	}
+
+	files["versions.txt"] = diagnosticsVersions(opts.DaprRuntimePath)
+	slimMode := false
+	for _, path := range []string{GetInstallManifestPath(daprDir), GetLockfilePath(daprDir)} {
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			collectErr(path_filepath.Base(path), err)
+			continue
+		}
+		files[path_filepath.Base(path)] = string(b)
+	}
+	if manifest, err := ReadInstallManifest(daprDir); err == nil && manifest != nil {
+		slimMode = manifest.SlimMode
+	}
+
+	if b, err := os.ReadFile(getCLILogFilePath(daprDir)); err == nil {
+		files["logs/"+cliLogFileName] = secret.Redact(lastLines(string(b), cliLogTailLines))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		collectErr(cliLogFileName, err)
+	}
+
+	if !slimMode {
+		runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(opts.ContainerRuntime))
+		for _, args := range [][]string{{"version"}, {"info"}} {
+			out, err := runCmd(runtimeCmd, args...)
+			if err != nil {
+				out = fmt.Sprintf("%s\nerror: %s\n", out, err)
+			}
+			files[runtimeCmd+"/"+args[0]+".txt"] = secret.Redact(out)
+		}
+		for _, container := range []string{DaprPlacementContainerName, DaprRedisContainerName, DaprZipkinContainerName} {
+			containerName := profileContainerName(container, opts.DockerNetwork)
+			// The container may not have been created, which the errors tell.
+			out, err := runCmd(runtimeCmd, "inspect", containerName)
+			if err != nil {
+				out = fmt.Sprintf("%s\nerror inspecting the container: %s\n", out, err)
+			} else {
+				out = redactContainerInspect(out)
+			}
+			files["containers/"+containerName+".json"] = out
+			out, err = runCmd(runtimeCmd, "logs", "--tail", containerLogTailLines, containerName)
+			if err != nil {
+				out = fmt.Sprintf("%s\nerror getting logs: %s\n", out, err)
+			}
+			files["containers/"+containerName+".log"] = secret.Redact(out)
+		}
+	}
+
+	if componentsPath, err := ResolveComponentsPath(opts.ComponentsPath, opts.DaprRuntimePath); err != nil {
+		collectErr("components", err)
+	} else if componentFiles, err := resourceFiles(componentsPath.Path); err != nil {
+		collectErr("components", err)
+	} else {
+		var list strings.Builder
+		fmt.Fprintf(&list, "%s\n", componentsPath)
+		for _, file := range componentFiles {
+			fmt.Fprintf(&list, "%s\n", path_filepath.Base(file))
+			if !opts.IncludeComponents {
+				continue
+			}
+			content, err := redactResourceFile(file)
+			if err != nil {
+				collectErr("components/"+path_filepath.Base(file), err)
+				continue
+			}
+			files["components/"+path_filepath.Base(file)] = content
+		}
+		files["components/files.txt"] = list.String()
+	}
+
+	if records, err := ReadRunRecords(daprDir); err != nil {
+		collectErr("run records", err)
+	} else if b, err := json.MarshalIndent(records, "", "  "); err == nil {
+		files["runs.json"] = secret.Redact(string(b))
+	}
+
+	report, err := RunDoctor(DoctorOptions{
+		DaprRuntimePath:  opts.DaprRuntimePath,
+		DockerNetwork:    opts.DockerNetwork,
+		ContainerRuntime: opts.ContainerRuntime,
+		ComponentsPath:   opts.ComponentsPath,
+	})
+	if err != nil {
+		collectErr("doctor", err)
+	} else if b, err := json.MarshalIndent(report, "", "  "); err == nil {
+		files["doctor.json"] = string(b)
+	}
+
+	if len(collectErrs) > 0 {
+		files["errors.txt"] = strings.Join(collectErrs, "\n") + "\n"
+	}
+	if err = writeDiagnosticsBundle(bundlePath, files); err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &DiagnosticsBundle{Path: bundlePath, Size: fi.Size()}
+	for name := range files {
+		bundle.Files = append(bundle.Files, name)
+	}
+	sort.Strings(bundle.Files)
+	return bundle, nil
+}
+
+// diagnosticsVersions describes the platform and the versions of the CLI and of the runtime and the dashboard
+// installed in daprRuntimePath.
+func diagnosticsVersions(daprRuntimePath string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "os: %s\n", runtime.GOOS)
+	fmt.Fprintf(&sb, "arch: %s\n", runtime.GOARCH)
+	fmt.Fprintf(&sb, "cli version: %s\n", cli_ver.CLI.Version)
+	if cli_ver.CLI.GitCommit != "" {
+		fmt.Fprintf(&sb, "cli git commit: %s\n", cli_ver.CLI.GitCommit)
+	}
+	runtimeVersion, err := GetRuntimeVersion(daprRuntimePath)
+	if err != nil {
+		runtimeVersion = fmt.Sprintf("%s (%s)", runtimeVersion, err)
+	}
+	fmt.Fprintf(&sb, "runtime version: %s\n", strings.TrimSpace(runtimeVersion))
+	dashboardVersion, err := GetDashboardVersion(daprRuntimePath)
+	if err != nil {
+		dashboardVersion = fmt.Sprintf("%s (%s)", dashboardVersion, err)
+	}
+	fmt.Fprintf(&sb, "dashboard version: %s\n", strings.TrimSpace(dashboardVersion))
+	return sb.String()
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "")
+}
+
+// redactContainerInspect returns the inspect output of a container with the values of its sensitive environment
+// variables redacted.
+func redactContainerInspect(out string) string {
+	var containers []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &containers); err != nil {
+		return secret.Redact(out)
+	}
+	for _, c := range containers {
+		config, _ := c["Config"].(map[string]interface{})
+		env, _ := config["Env"].([]interface{})
+		for i, v := range env {
+			kv, _ := v.(string)
+			if name, _, ok := strings.Cut(kv, "="); ok && sensitiveNameRegexp.MatchString(name) {
+				env[i] = name + "=" + redacted
+			}
+		}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(containers); err != nil {
+		return secret.Redact(out)
+	}
+	return secret.Redact(buf.String())
+}
+
+// redactResourceFile returns the content of the resource file with the values of the sensitive metadata items of its
+// components and the data of its secrets redacted. A file which can't be parsed is left out, as what would need to be
+// redacted can't be told.
+func redactResourceFile(file string) (string, error) {
+	docs, problems := loadResourceFile(file)
+	if len(problems) > 0 {
+		return "", fmt.Errorf("left out, it can't be parsed: %s", problems[0].Message)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		root := doc.node.Content[0]
+		switch doc.kind {
+		case componentKind:
+			if spec := mappingValue(root, "spec"); spec != nil {
+				if items := mappingValue(spec, "metadata"); items != nil && items.Kind == yaml.SequenceNode {
+					for _, item := range items.Content {
+						name, value := mappingValue(item, "name"), mappingValue(item, "value")
+						if name != nil && value != nil && value.Value != "" && sensitiveNameRegexp.MatchString(name.Value) {
+							redactNode(value)
+						}
+					}
+				}
+			}
+		case "Secret":
+			for _, key := range []string{"data", "stringData"} {
+				if data := mappingValue(root, key); data != nil && data.Kind == yaml.MappingNode {
+					for i := 1; i < len(data.Content); i += 2 {
+						redactNode(data.Content[i])
+					}
+				}
+			}
+		}
+		if err := encoder.Encode(doc.node); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return secret.Redact(buf.String()), nil
+}
+
+func redactNode(node *yaml.Node) {
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: redacted}
+}
+
+func initEnvironmentInfo(info initInfo) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "os: %s\n", runtime.GOOS)
+	fmt.Fprintf(&sb, "arch: %s\n", runtime.GOARCH)
+	fmt.Fprintf(&sb, "runtime version: %s\n", info.runtimeVersion)
+	fmt.Fprintf(&sb, "dashboard version: %s\n", info.dashboardVersion)
+	fmt.Fprintf(&sb, "install dir: %s\n", info.installDir)
+	fmt.Fprintf(&sb, "components dir: %s\n", info.componentsDir)
+	fmt.Fprintf(&sb, "slim mode: %t\n", info.slimMode)
+	fmt.Fprintf(&sb, "air-gapped: %t\n", info.fromDir != "")
+	if !info.slimMode {
+		runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
+		fmt.Fprintf(&sb, "container runtime: %s\n", runtimeCmd)
+		out, err := runCmd(runtimeCmd, "version")
+		if err != nil {
+			out = fmt.Sprintf("%s\nerror: %s", out, err)
+		}
+		fmt.Fprintf(&sb, "\n%s version:\n%s\n", runtimeCmd, out)
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteInitDiagnosticsBundle(t *testing.T) {
+	info := initInfo{
+		installDir:     t.TempDir(),
+		slimMode:       true,
+		runtimeVersion: "1.10.0",
+	}
+	stepLog := &initStepLog{}
+	stepLog.record("daprd binary: failed: connection reset")
+
+	logPath := getCLILogFilePath(info.installDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0o755))
+	require.NoError(t, os.WriteFile(logPath, []byte("current\n"), 0o644))
+	require.NoError(t, os.WriteFile(logPath+".1", []byte("rotated\n"), 0o644))
+
+	bundlePath, err := writeInitDiagnosticsBundle(info, stepLog, errors.New("connection reset"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(info.installDir, diagnosticsDirName), filepath.Dir(bundlePath))
+
+	zr, err := zip.OpenReader(bundlePath)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	contents := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		contents[f.Name] = string(b)
+	}
+
+	assert.Len(t, contents, 5)
+	assert.Equal(t, "current\n", contents["logs/cli.log"])
+	assert.Equal(t, "rotated\n", contents["logs/cli.log.1"])
+	assert.Equal(t, "connection reset\n", contents["error.txt"])
+	assert.Contains(t, contents["steps.log"], "daprd binary: failed: connection reset")
+	assert.Contains(t, contents["environment.txt"], "os: "+runtime.GOOS)
+	assert.Contains(t, contents["environment.txt"], "runtime version: 1.10.0")
+}
+
+func TestWriteInitDiagnosticsBundleManifest(t *testing.T) {
+	info := initInfo{installDir: t.TempDir(), slimMode: true}
+	require.NoError(t, writeInstallManifest(info.installDir, &InstallManifest{RuntimeVersion: "1.10.0"}))
+
+	bundlePath, err := writeInitDiagnosticsBundle(info, &initStepLog{}, errors.New("connection reset"))
+	require.NoError(t, err)
+	zr, err := zip.OpenReader(bundlePath)
+	require.NoError(t, err)
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, installManifestFileName)
+}
+
+func TestExportDiagnostics(t *testing.T) {
+	t.Setenv("DAPR_COMPONENTS_PATH", "")
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	componentsDir := filepath.Join(t.TempDir(), "components")
+	require.NoError(t, os.MkdirAll(componentsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(componentsDir, "statestore.yaml"), []byte("apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: statestore\nspec:\n  type: state.redis\n  version: v1\n  metadata:\n  - name: redisHost\n    value: localhost:6379\n  - name: redisPassword\n    value: hunter2\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(componentsDir, "broken.yaml"), []byte("password: [\n"), 0o644))
+	logPath := getCLILogFilePath(daprDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0o755))
+	require.NoError(t, os.WriteFile(logPath, []byte(strings.Repeat("earlier\n", cliLogTailLines)+"latest\n"), 0o644))
+	serveReleases(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(_ string, args ...string) (string, error) {
+		switch args[0] {
+		case "inspect":
+			return `[{"Name": "/dapr_redis", "Config": {"Env": ["PATH=/usr/bin", "REDIS_PASSWORD=hunter2"]}}]`, nil
+		case "logs":
+			return "Ready to accept connections\n", nil
+		}
+		return args[0] + " output\n", nil
+	}
+
+	contents := func(bundle *DiagnosticsBundle) map[string]string {
+		zr, err := zip.OpenReader(bundle.Path)
+		require.NoError(t, err)
+		defer zr.Close()
+		contents := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			b, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			contents[f.Name] = string(b)
+		}
+		return contents
+	}
+
+	bundle, err := ExportDiagnostics(ExportDiagnosticsOptions{DaprRuntimePath: runtimePath, ComponentsPath: componentsDir})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(daprDir, diagnosticsDirName), filepath.Dir(bundle.Path))
+	fi, err := os.Stat(bundle.Path)
+	require.NoError(t, err)
+	assert.Equal(t, fi.Size(), bundle.Size)
+	files := contents(bundle)
+	assert.Len(t, files, len(bundle.Files))
+	assert.Contains(t, files["versions.txt"], "os: "+runtime.GOOS)
+	assert.Equal(t, cliLogTailLines, strings.Count(files["logs/cli.log"], "\n"))
+	assert.True(t, strings.HasSuffix(files["logs/cli.log"], "earlier\nlatest\n"))
+	assert.Equal(t, "info output\n", files["docker/info.txt"])
+	assert.Contains(t, files["containers/dapr_redis.json"], `"REDIS_PASSWORD=<redacted>"`)
+	assert.Contains(t, files["containers/dapr_redis.json"], `"PATH=/usr/bin"`)
+	assert.Equal(t, "Ready to accept connections\n", files["containers/dapr_redis.log"])
+	assert.Contains(t, files["components/files.txt"], "broken.yaml\nstatestore.yaml\n")
+	assert.NotContains(t, files, "components/statestore.yaml")
+	assert.Contains(t, files, "doctor.json")
+	assert.Equal(t, "null", files["runs.json"])
+
+	bundle, err = ExportDiagnostics(ExportDiagnosticsOptions{DaprRuntimePath: runtimePath, ComponentsPath: componentsDir, IncludeComponents: true, Output: filepath.Join(t.TempDir(), "bundle.zip")})
+	require.NoError(t, err)
+	files = contents(bundle)
+	assert.Contains(t, files["components/statestore.yaml"], "value: localhost:6379")
+	assert.Contains(t, files["components/statestore.yaml"], "value: <redacted>")
+	for name, content := range files {
+		assert.NotContains(t, content, "hunter2", name)
+	}
+	// What would need to be redacted from a file which can't be parsed can't be told.
+	assert.NotContains(t, files, "components/broken.yaml")
+	assert.Contains(t, files["errors.txt"], "components/broken.yaml: left out")
+}
+
+func TestRedactResourceFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "resources.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: pubsub
+spec:
+  type: pubsub.kafka
+  version: v1
+  metadata:
+  - name: brokers
+    value: localhost:9092
+  - name: saslPassword
+    secretKeyRef:
+      name: kafka
+      key: password
+  - name: accessKey
+    value: AKIA1234
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: kafka
+stringData:
+  password: s3cr3t
+`), 0o644))
+
+	out, err := redactResourceFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, out, "value: localhost:9092")
+	assert.Contains(t, out, "key: password")
+	assert.NotContains(t, out, "AKIA1234")
+	assert.NotContains(t, out, "s3cr3t")
+	assert.Equal(t, 2, strings.Count(out, redacted))
+}
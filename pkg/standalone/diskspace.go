@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	// extractionFactor is how much larger the extracted binaries are than their archives, rounded up.
+	extractionFactor = 3
+	// defaultArchiveSize is the size assumed for a release archive whose size can't be told before downloading it.
+	defaultArchiveSize = 64 << 20
+	// diskSpaceMargin is kept free on top of what init needs, for the logs, the components and the configuration.
+	diskSpaceMargin = 32 << 20
+	// initInodes and imageInodes are the inodes init needs for the files of the binaries, along with the dashboard
+	// web assets, and for the layers of the images.
+	initInodes  = 2000
+	imageInodes = 20000
+
+	artifactSizeTimeout = 10 * time.Second
+)
+
+// imageSizeEstimates are the disk space the images of the containers take once pulled, rounded up. They are only
+// used when the images aren't loaded from a local bundle, whose files tell their sizes.
+var imageSizeEstimates = map[string]uint64{
+	DaprPlacementContainerName: 100 << 20,
+	DaprRedisContainerName:     150 << 20,
+	DaprZipkinContainerName:    300 << 20,
+}
+
+// ErrInsufficientDiskSpace is returned when a file system doesn't have the free space or inodes init needs.
+var ErrInsufficientDiskSpace = errors.New("not enough free disk space")
+
+// spaceNeed is the disk space and inodes init needs in a directory, for what.
+type spaceNeed struct {
+	dir    string
+	bytes  uint64
+	inodes uint64
+	what   string
+}
+
+// checkInitDiskSpace checks, before anything is downloaded, that the file systems init writes to have the free space
+// for it: the bin dir for the archives and the extracted binaries, and the data root of the container runtime for the
+// images, if it's on this machine. The file systems whose usage can't be read aren't checked.
+func checkInitDiskSpace(ctx context.Context, info initInfo) error {
+	return checkSpaceNeeds(initSpaceNeeds(ctx, info), disk.Usage)
+}
+
+// initSpaceNeeds returns the disk space init needs with info, from the sizes of the archives to download, told by
+// HEAD requests, or of the bundle files for an air gap install.
+func initSpaceNeeds(ctx context.Context, info initInfo) []spaceNeed {
+	binDir := getDaprBinPath(info.installDir)
+	binaries := []struct {
+		name, version, repo string
+	}{{daprRuntimeFilePrefix, info.runtimeVersion, cli_ver.DaprGitHubRepo}}
+	if info.slimMode {
+		binaries = append(binaries, struct{ name, version, repo string }{placementServiceFilePrefix, info.runtimeVersion, cli_ver.DaprGitHubRepo})
+	}
+	if info.dashboardVersion != "" {
+		binaries = append(binaries, struct{ name, version, repo string }{dashboardFilePrefix, info.dashboardVersion, cli_ver.DashboardGitHubRepo})
+	}
+	var needs []spaceNeed
+	for _, b := range binaries {
+		size := uint64(defaultArchiveSize)
+		factor := uint64(1 + extractionFactor)
+		if isAirGapInit {
+			// The archive is extracted from the bundle, it isn't copied.
+			factor = extractionFactor
+			if fi, err := os.Stat(path_filepath.Join(info.fromDir, *info.bundleDet.BinarySubDir, binaryName(b.name))); err == nil {
+				size = uint64(fi.Size())
+			}
+		} else if s := releaseArchiveSize(ctx, b.version, b.name, b.repo); s > 0 {
+			size = uint64(s)
+		}
+		needs = append(needs, spaceNeed{dir: binDir, bytes: size * factor, inodes: initInodes, what: b.name + " " + b.version})
+	}
+	if info.slimMode {
+		return needs
+	}
+	dataRoot := containerDataRoot(utils.GetContainerRuntimeCmd(info.containerRuntime))
+	if dataRoot == "" {
+		return needs
+	}
+	if isAirGapInit {
+		if size := dirSize(path_filepath.Join(info.fromDir, *info.bundleDet.ImageSubDir)); size > 0 {
+			needs = append(needs, spaceNeed{dir: dataRoot, bytes: size, inodes: imageInodes, what: "the images of the bundle"})
+		}
+		return needs
+	}
+	for _, name := range []string{DaprPlacementContainerName, DaprRedisContainerName, DaprZipkinContainerName} {
+		if name == DaprRedisContainerName && info.components != "" && info.components != ComponentsProviderRedis {
+			continue
+		}
+		needs = append(needs, spaceNeed{dir: dataRoot, bytes: imageSizeEstimates[name], inodes: imageInodes, what: "the image of " + name})
+	}
+	return needs
+}
+
+// releaseArchiveSize returns the size of the release archive of binaryFilePrefix for this machine, from a HEAD
+// request, or -1 if it can't be told.
+func releaseArchiveSize(ctx context.Context, version, binaryFilePrefix, githubRepo string) int64 {
+	ctx, cancel := context.WithTimeout(ctx, artifactSizeTimeout)
+	defer cancel()
+	arch, _, err := resolveArtifactArch(ctx, version, binaryFilePrefix, githubRepo)
+	if err != nil {
+		return -1
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, binaryDownloadURL(version, binaryFilePrefix, githubRepo, arch), nil)
+	if err != nil {
+		return -1
+	}
+	req.Header.Set("User-Agent", cli_ver.CLI.UserAgent())
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return -1
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// containerDataRoot returns the directory the container runtime stores its images in, if it is on this machine, or
+// an empty string. The data root of a remote daemon, or of the VM of Docker Desktop, isn't on this machine.
+func containerDataRoot(runtimeCmd string) string {
+	format := "{{.DockerRootDir}}"
+	if runtimeCmd == string(utils.PODMAN) {
+		format = "{{.Store.GraphRoot}}"
+	} else if host := os.Getenv("DOCKER_HOST"); host != "" && !strings.HasPrefix(host, "unix://") {
+		return ""
+	}
+	out, err := runCmd(runtimeCmd, "info", "--format", format)
+	if err != nil {
+		return ""
+	}
+	dir := strings.TrimSpace(out)
+	if fi, statErr := os.Stat(dir); dir == "" || statErr != nil || !fi.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// dirSize returns the total size of the files under dir.
+func dirSize(dir string) uint64 {
+	var size uint64
+	_ = path_filepath.Walk(dir, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			size += uint64(fi.Size())
+		}
+		return nil
+	})
+	return size
+}
+
+// existingDir returns dir, or its closest parent which exists.
+func existingDir(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil || path_filepath.Dir(dir) == dir {
+			return dir
+		}
+		dir = path_filepath.Dir(dir)
+	}
+}
+
+// checkSpaceNeeds checks needs against the free space and inodes of their file systems, read with usage, adding up
+// the needs of the directories on the same file system.
+func checkSpaceNeeds(needs []spaceNeed, usage func(path string) (*disk.UsageStat, error)) error {
+	type fileSystem struct {
+		dir    string
+		stat   *disk.UsageStat
+		bytes  uint64
+		inodes uint64
+		what   []string
+	}
+	var order []string
+	fileSystems := map[string]*fileSystem{}
+	for _, n := range needs {
+		dir := existingDir(n.dir)
+		stat, err := usage(dir)
+		if err != nil {
+			continue
+		}
+		// The directories whose file systems have the same size and free space are taken to be on the same one,
+		// gopsutil doesn't tell the mount point.
+		key := fmt.Sprintf("%s/%d/%d/%d", stat.Fstype, stat.Total, stat.Free, stat.InodesTotal)
+		fs, ok := fileSystems[key]
+		if !ok {
+			fs = &fileSystem{dir: dir, stat: stat}
+			fileSystems[key] = fs
+			order = append(order, key)
+		}
+		fs.bytes += n.bytes
+		fs.inodes += n.inodes
+		fs.what = append(fs.what, n.what)
+	}
+	var problems []string
+	for _, key := range order {
+		fs := fileSystems[key]
+		sort.Strings(fs.what)
+		if needed := fs.bytes + diskSpaceMargin; fs.stat.Free < needed {
+			problems = append(problems, fmt.Sprintf("%s has %d MiB free, %d MiB are needed for %s", fs.dir, fs.stat.Free>>20, needed>>20, strings.Join(fs.what, ", ")))
+		}
+		// The file systems without a fixed number of inodes report none.
+		if fs.stat.InodesTotal > 0 && fs.stat.InodesFree < fs.inodes {
+			problems = append(problems, fmt.Sprintf("%s has %d free inodes, %d are needed for %s", fs.dir, fs.stat.InodesFree, fs.inodes, strings.Join(fs.what, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s. Free some disk space, for instance with `docker system prune`, or install in another directory with --runtime-path", ErrInsufficientDiskSpace, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shirou/gopsutil/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSpaceNeeds(t *testing.T) {
+	binDir := filepath.Join(t.TempDir(), ".dapr", "bin")
+	dataRoot := t.TempDir()
+	usages := map[string]*disk.UsageStat{}
+	usage := func(path string) (*disk.UsageStat, error) {
+		if u, ok := usages[path]; ok {
+			return u, nil
+		}
+		return nil, errors.New("no usage")
+	}
+	needs := []spaceNeed{
+		{dir: binDir, bytes: 100 << 20, inodes: initInodes, what: "daprd 1.11.0"},
+		{dir: dataRoot, bytes: 300 << 20, inodes: imageInodes, what: "the image of dapr_zipkin"},
+	}
+
+	// The file systems whose usage can't be read aren't checked.
+	require.NoError(t, checkSpaceNeeds(needs, usage))
+
+	// The bin dir doesn't exist yet, the usage of its closest existing parent is checked.
+	installParent := filepath.Dir(filepath.Dir(binDir))
+	usages[installParent] = &disk.UsageStat{Fstype: "ext4", Total: 10 << 30, Free: 1 << 30, InodesTotal: 1 << 20, InodesFree: 1 << 20}
+	usages[dataRoot] = &disk.UsageStat{Fstype: "xfs", Total: 20 << 30, Free: 200 << 20}
+	err := checkSpaceNeeds(needs, usage)
+	require.ErrorIs(t, err, ErrInsufficientDiskSpace)
+	assert.Contains(t, err.Error(), dataRoot+" has 200 MiB free, 332 MiB are needed for the image of dapr_zipkin")
+	assert.NotContains(t, err.Error(), installParent)
+
+	// The needs of the directories on the same file system add up.
+	usages[dataRoot] = usages[installParent]
+	usages[installParent].Free = 400 << 20
+	err = checkSpaceNeeds(needs, usage)
+	require.ErrorIs(t, err, ErrInsufficientDiskSpace)
+	assert.Contains(t, err.Error(), "432 MiB are needed for daprd 1.11.0, the image of dapr_zipkin")
+
+	// Running out of inodes fails even with free space.
+	usages[installParent].Free = 10 << 30
+	usages[installParent].InodesFree = 100
+	err = checkSpaceNeeds(needs, usage)
+	require.ErrorIs(t, err, ErrInsufficientDiskSpace)
+	assert.Contains(t, err.Error(), "has 100 free inodes, 22000 are needed")
+}
+
+func TestExistingDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, dir, existingDir(filepath.Join(dir, "a", "b")))
+	assert.Equal(t, dir, existingDir(dir))
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "images"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "images", "a.tar.gz"), make([]byte, 1000), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b"), make([]byte, 24), 0o644))
+	assert.Equal(t, uint64(1024), dirSize(dir))
+	assert.Zero(t, dirSize(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,690 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	path_filepath "path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+// Statuses of the checks of doctor.
+const (
+	DoctorPass = "pass"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+)
+
+const (
+	// doctorDownloadTimeout is how long the download endpoint has to answer.
+	doctorDownloadTimeout = 10 * time.Second
+	// doctorLowDiskSpace and doctorMinDiskSpace are the free disk space in the dapr install dir below which doctor
+	// warns and fails: init needs a few hundred MiB for the binaries and the images of the containers.
+	doctorLowDiskSpace = 2 << 30
+	doctorMinDiskSpace = 512 << 20
+
+	defaultDockerSocket = "/var/run/docker.sock"
+)
+
+// DoctorCheck is the result of a check of doctor, with a hint to remediate warnings and failures.
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// DoctorOptions are the options of RunDoctor.
+type DoctorOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath  string
+	DockerNetwork    string
+	ContainerRuntime string
+	// ComponentsPath is the --components-path command line flag, as for ResolveComponentsPath.
+	ComponentsPath string
+	// Fix restricts the permissions of the paths of the dapr install dir which are more open than the policy.
+	Fix bool
+}
+
+// DoctorReport is the result of RunDoctor.
+type DoctorReport struct {
+	Checks   []DoctorCheck `json:"checks"`
+	Failures int           `json:"failures"`
+	Warnings int           `json:"warnings"`
+	// Permissions are the paths of the dapr install dir breaking the permissions policy, fixed with Fix.
+	Permissions []PermissionViolation `json:"permissions,omitempty"`
+}
+
+// RunDoctor checks the local environment for the common problems which break init and run: the container runtime
+// and its socket, the ports used by the containers and the sidecars, the runtime binary and the CLI on PATH, whether
+// they run natively, the containers set up by init, the components directory, the connectivity to the download
+// endpoint, the free disk space, the permissions of the install dir, on Windows PATH and in WSL the docker
+// integration.
+func RunDoctor(opts DoctorOptions) (*DoctorReport, error) {
+	daprDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(opts.ContainerRuntime))
+	environment, err := GetEnvironmentStatus(opts.DaprRuntimePath, opts.DockerNetwork, opts.ContainerRuntime)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []DoctorCheck
+	checks = append(checks, checkContainerRuntime(runtimeCmd, environment.SlimMode))
+	if _, lookErr := exec.LookPath(runtimeCmd); lookErr == nil && runtimeCmd == string(utils.DOCKER) && runtime.GOOS != daprWindowsOS {
+		checks = append(checks, checkDockerSocket(os.Getenv("DOCKER_HOST")))
+	}
+	checks = append(checks, checkPorts(environment, opts.DockerNetwork)...)
+	checks = append(checks, checkRuntimeBinary(opts.DaprRuntimePath), checkCLIOnPath())
+	checks = append(checks, checkPlatform(opts.DaprRuntimePath, environment, runtimeCmd))
+	if runtime.GOOS == "linux" {
+		checks = append(checks, checkLibc(opts.DaprRuntimePath))
+	}
+	if environment.WSL {
+		checks = append(checks, checkWSL(runtimeCmd, environment.SlimMode))
+	}
+	checks = append(checks, checkEnvironmentComponents(environment, runtimeCmd)...)
+	checks = append(checks, checkRedisHardening(environment)...)
+	checks = append(checks, checkComponentsDir(opts.ComponentsPath, opts.DaprRuntimePath))
+	// The endpoint init and upgrade download the binaries from.
+	if err = useDownloadURL(opts.DaprRuntimePath); err != nil {
+		return nil, err
+	}
+	checks = append(checks, checkDownloadEndpoint(releasesURL(cli_ver.DaprGitHubRepo)))
+	checks = append(checks, checkEnvVars(os.Environ()))
+	checks = append(checks, checkDiskSpace(daprDir))
+	permissions, violations := checkPermissions(daprDir, opts.Fix)
+	checks = append(checks, permissions)
+	if runtime.GOOS == daprWindowsOS {
+		checks = append(checks, checkWindowsPath(os.Getenv("PATH")))
+	}
+
+	report := &DoctorReport{Checks: checks, Permissions: violations}
+	for _, c := range checks {
+		switch c.Status {
+		case DoctorFail:
+			report.Failures++
+		case DoctorWarn:
+			report.Warnings++
+		}
+	}
+	return report, nil
+}
+
+// checkContainerRuntime checks that the container runtime is installed and its daemon answers. It is only a warning
+// in slim mode, which runs no containers.
+func checkContainerRuntime(runtimeCmd string, slimMode bool) DoctorCheck {
+	c := DoctorCheck{Name: "container runtime"}
+	problem := DoctorFail
+	if slimMode {
+		problem = DoctorWarn
+	}
+	if _, err := exec.LookPath(runtimeCmd); err != nil {
+		c.Status = problem
+		c.Message = fmt.Sprintf("%s is not installed", runtimeCmd)
+		c.Hint = "install Docker or Podman, or use `dapr init --slim` to run without containers"
+		return c
+	}
+	out, err := runCmd(runtimeCmd, "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		c.Status = problem
+		c.Message = fmt.Sprintf("the %s daemon is not reachable: %s", runtimeCmd, firstLine(err.Error()))
+		if strings.Contains(err.Error(), "permission denied") {
+			c.Hint = fmt.Sprintf("allow your user to use %s, see the socket check", runtimeCmd)
+		} else {
+			c.Hint = fmt.Sprintf("start %s, for instance Docker Desktop or `podman machine start`", runtimeCmd)
+		}
+		return c
+	}
+	c.Status = DoctorPass
+	c.Message = fmt.Sprintf("%s %s is running", runtimeCmd, strings.TrimSpace(out))
+	return c
+}
+
+// checkDockerSocket checks that the socket of the docker daemon given by dockerHost, the DOCKER_HOST environment
+// variable, can be connected to by the user.
+func checkDockerSocket(dockerHost string) DoctorCheck {
+	c := DoctorCheck{Name: "docker socket"}
+	socket := defaultDockerSocket
+	if dockerHost != "" {
+		if !strings.HasPrefix(dockerHost, "unix://") {
+			c.Status = DoctorPass
+			c.Message = fmt.Sprintf("the docker daemon is at %s", dockerHost)
+			return c
+		}
+		socket = strings.TrimPrefix(dockerHost, "unix://")
+	}
+	if _, err := os.Stat(socket); err != nil {
+		// Docker Desktop and docker contexts may use another socket, which the container runtime check covers.
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("there is no docker socket at %s", socket)
+		c.Hint = "set DOCKER_HOST to the socket of the docker daemon if the container runtime check fails"
+		return c
+	}
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Message = fmt.Sprintf("can't connect to %s: %s", socket, err)
+		if errors.Is(err, os.ErrPermission) {
+			c.Hint = "add your user to the docker group with `sudo usermod -aG docker $USER`, then log in again"
+		} else {
+			c.Hint = "start the docker daemon"
+		}
+		return c
+	}
+	conn.Close()
+	c.Status = DoctorPass
+	c.Message = fmt.Sprintf("%s is accessible", socket)
+	return c
+}
+
+// doctorPort is a port checked by doctor, published by a container of init or used by default by the sidecars.
+type doctorPort struct {
+	port int
+	// container is the name of the container publishing the port, empty for the ports of the sidecars.
+	container string
+	// flag is the flag of run to use another port, for the ports of the sidecars.
+	flag string
+}
+
+// checkPorts checks that the ports published by the containers of init, unless they run in a docker network, and
+// the default ports of the sidecars are free or used by Dapr. A port of a container taken by another process
+// fails, as init can't publish it, while a port of the sidecars only warns, as run picks the next free port.
+func checkPorts(environment *EnvironmentStatus, dockerNetwork string) []DoctorCheck {
+	var ports []doctorPort
+	if !environment.SlimMode && dockerNetwork == "" {
+		ports = append(ports,
+			doctorPort{port: defaultPlacementHostPort(), container: DaprPlacementContainerName},
+			doctorPort{port: 6379, container: DaprRedisContainerName},
+			doctorPort{port: 9411, container: DaprZipkinContainerName})
+	}
+	ports = append(ports, doctorPort{port: 3500, flag: "--dapr-http-port"}, doctorPort{port: 50001, flag: "--dapr-grpc-port"})
+
+	meta, err := newDaprMeta()
+	if err != nil {
+		meta = &DaprMeta{}
+	}
+	checks := make([]DoctorCheck, 0, len(ports))
+	for _, p := range ports {
+		checks = append(checks, checkPort(p, environment, meta))
+	}
+	return checks
+}
+
+func checkPort(p doctorPort, environment *EnvironmentStatus, meta *DaprMeta) DoctorCheck {
+	c := DoctorCheck{Name: fmt.Sprintf("port %d", p.port), Status: DoctorPass}
+	if utils.CheckIfPortAvailable(p.port) == nil {
+		c.Message = "free"
+		return c
+	}
+	if p.container != "" && containerRunning(environment, p.container) {
+		c.Message = fmt.Sprintf("published by %s", p.container)
+		return c
+	}
+	owner := meta.portOwner(p.port)
+	if strings.HasPrefix(owner, "the Dapr app") {
+		c.Message = "used by " + owner
+		return c
+	}
+	if owner == "" {
+		owner = "another process"
+	}
+	if p.container != "" {
+		c.Status = DoctorFail
+		c.Message = fmt.Sprintf("used by %s, %s can't publish it", owner, p.container)
+		c.Hint = fmt.Sprintf("stop %s, or run the containers in a docker network with `dapr init --network`", owner)
+	} else {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("used by %s, run picks the next free port", owner)
+		c.Hint = fmt.Sprintf("stop %s, or give another port with %s", owner, p.flag)
+	}
+	return c
+}
+
+// containerRunning reports whether the container of init named name, without the suffix of the docker network, is
+// running in the environment.
+func containerRunning(environment *EnvironmentStatus, name string) bool {
+	for _, c := range environment.Components {
+		if c.Kind == "container" && strings.HasPrefix(c.Name, name) && (c.Status == ContainerRunning || c.Status == ContainerUnreachable) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRuntimeBinary checks that the runtime binary is installed, built for this platform and runs.
+func checkRuntimeBinary(daprRuntimePath string) DoctorCheck {
+	c := DoctorCheck{Name: daprRuntimeFilePrefix + " binary"}
+	info := GetRuntimeInfo(daprRuntimePath)
+	if info.Binary == "" {
+		c.Status = DoctorFail
+		c.Message = "the runtime is not installed"
+		c.Hint = "run `dapr init`"
+		return c
+	}
+	if platform, err := binaryPlatform(info.Binary); err == nil && platform != hostPlatform() {
+		c.Message = fmt.Sprintf("%s is built for %s, this machine is %s", info.Binary, platform, hostPlatform())
+		c.Hint = "reinstall the runtime with `dapr uninstall` and `dapr init`"
+		c.Status = DoctorFail
+		if platform == "darwin/amd64" && hostPlatform() == "darwin/arm64" {
+			// It runs with Rosetta, only slower.
+			c.Status = DoctorWarn
+		}
+		return c
+	}
+	if info.Status != RuntimeInstalled {
+		c.Status = DoctorFail
+		c.Message = fmt.Sprintf("%s is %s: %s", info.Binary, info.Status, info.Error)
+		c.Hint = "reinstall the runtime with `dapr upgrade --force`"
+		return c
+	}
+	c.Status = DoctorPass
+	c.Message = fmt.Sprintf("%s %s", info.Binary, info.Version)
+	return c
+}
+
+// checkPlatform checks whether the CLI, the runtime binary and the running containers are native builds for this
+// machine, or run under emulation.
+func checkPlatform(daprRuntimePath string, environment *EnvironmentStatus, runtimeCmd string) DoctorCheck {
+	var emulated []string
+	if processTranslated() {
+		emulated = append(emulated, fmt.Sprintf("the CLI is %s/amd64", runtime.GOOS))
+	}
+	if info := GetRuntimeInfo(daprRuntimePath); info.Binary != "" {
+		if platform, err := binaryPlatform(info.Binary); err == nil && platform != hostPlatform() {
+			emulated = append(emulated, fmt.Sprintf("%s is %s", daprRuntimeFilePrefix, platform))
+		}
+	}
+	if expected := containerPlatform(runtime.GOOS, hostArch()); expected != "" {
+		for _, c := range environment.Components {
+			if c.Kind != "container" || c.Status != ContainerRunning || c.Version == "" {
+				continue
+			}
+			out, err := runCmd(runtimeCmd, "image", "inspect", "--format", "{{.Os}}/{{.Architecture}}", c.Version)
+			// The expected platform may have a variant, as linux/arm/v7.
+			if platform := strings.TrimSpace(out); err == nil && platform != expected && !strings.HasPrefix(expected, platform+"/") {
+				emulated = append(emulated, fmt.Sprintf("%s is %s", c.Name, platform))
+			}
+		}
+	}
+	return platformCheck(hostPlatform(), emulated)
+}
+
+// platformCheck returns the result of checkPlatform on the host platform, with the pieces which run under emulation.
+func platformCheck(host string, emulated []string) DoctorCheck {
+	c := DoctorCheck{Name: "platform"}
+	if len(emulated) == 0 {
+		c.Status = DoctorPass
+		c.Message = "native " + host
+		return c
+	}
+	c.Status = DoctorWarn
+	c.Message = fmt.Sprintf("emulated on %s: %s", host, strings.Join(emulated, ", "))
+	c.Hint = fmt.Sprintf("install the %s build of the CLI, then reinstall with `dapr uninstall --all` and `dapr init` to get the native runtime and images where they are published", host)
+	return c
+}
+
+// checkLibc checks that the runtime binary can run with the C library of this Linux system.
+func checkLibc(daprRuntimePath string) DoctorCheck {
+	var binaryErr error
+	if info := GetRuntimeInfo(daprRuntimePath); info.Binary != "" {
+		binaryErr = checkBinaryRunnable(info.Binary)
+	}
+	return libcCheck(hostLibc(), binaryErr)
+}
+
+// libcCheck returns the result of checkLibc for the detected C library, with the error of checkBinaryRunnable for the
+// runtime binary.
+func libcCheck(detected string, binaryErr error) DoctorCheck {
+	c := DoctorCheck{Name: "libc", Status: DoctorPass, Message: detected}
+	if detected == "" {
+		c.Message = "no glibc or musl loader found, only static binaries run"
+	}
+	if binaryErr != nil {
+		c.Status = DoctorFail
+		c.Message = binaryErr.Error()
+		c.Hint = "reinstall the runtime with `dapr uninstall` and `dapr init` to get its static build where one is published, or install the glibc compatibility layer, as the gcompat package of Alpine"
+	}
+	return c
+}
+
+// checkWSL checks that docker can be used in the WSL distro the CLI runs in, either through the WSL integration of
+// Docker Desktop or installed in the distro.
+func checkWSL(runtimeCmd string, slimMode bool) DoctorCheck {
+	c := DoctorCheck{Name: "WSL", Status: DoctorPass}
+	switch {
+	case runtimeCmd != string(utils.DOCKER) || slimMode:
+		c.Message = wslDistroName()
+	case usesDockerDesktop(runtimeCmd):
+		c.Message = fmt.Sprintf("%s, with the docker of Docker Desktop: its ports are published at %s", wslDistroName(), wslLoopbackHost)
+	default:
+		if err := checkWSLDocker(); err != nil {
+			c.Status = DoctorFail
+			c.Message, c.Hint, _ = strings.Cut(err.Error(), ": ")
+			return c
+		}
+		c.Message = wslDistroName() + ", with docker installed in the distro"
+	}
+	return c
+}
+
+// binaryPlatform returns the os/arch the executable at path is built for, told from its ELF, Mach-O or PE header.
+// Linux is assumed for ELF executables.
+func binaryPlatform(path string) (string, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		arch, ok := map[elf.Machine]string{elf.EM_X86_64: "amd64", elf.EM_AARCH64: "arm64", elf.EM_ARM: "arm", elf.EM_386: "386"}[f.Machine]
+		if !ok {
+			arch = strings.ToLower(strings.TrimPrefix(f.Machine.String(), "EM_"))
+		}
+		// The FreeBSD executables are marked with its ABI, the Linux ones mostly with the generic System V one.
+		if f.OSABI == elf.ELFOSABI_FREEBSD {
+			return "freebsd/" + arch, nil
+		}
+		return "linux/" + arch, nil
+	}
+	machoArch := map[macho.Cpu]string{macho.CpuAmd64: "amd64", macho.CpuArm64: "arm64"}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return "darwin/" + machoArch[f.Cpu], nil
+	}
+	if f, err := macho.OpenFat(path); err == nil {
+		defer f.Close()
+		// A universal binary runs natively if one of its architectures is the one of the machine.
+		for _, a := range f.Arches {
+			if runtime.GOOS == "darwin" && machoArch[a.Cpu] == hostArch() {
+				return "darwin/" + hostArch(), nil
+			}
+		}
+		return "darwin/" + machoArch[f.Arches[0].Cpu], nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		arch := map[uint16]string{pe.IMAGE_FILE_MACHINE_AMD64: "amd64", pe.IMAGE_FILE_MACHINE_ARM64: "arm64", pe.IMAGE_FILE_MACHINE_I386: "386"}[f.Machine]
+		return "windows/" + arch, nil
+	}
+	return "", fmt.Errorf("%s is not a known executable format", path)
+}
+
+// checkCLIOnPath checks that the dapr found in PATH is this CLI.
+func checkCLIOnPath() DoctorCheck {
+	c := DoctorCheck{Name: "dapr on PATH"}
+	self, err := os.Executable()
+	if err == nil {
+		self, _ = path_filepath.EvalSymlinks(self)
+	}
+	found, err := exec.LookPath("dapr")
+	if err != nil {
+		c.Status = DoctorWarn
+		c.Message = "the dapr CLI is not on PATH"
+		c.Hint = fmt.Sprintf("add %s to PATH", path_filepath.Dir(self))
+		return c
+	}
+	if resolved, resolveErr := path_filepath.EvalSymlinks(found); resolveErr == nil {
+		found = resolved
+	}
+	if self != "" && !sameFile(found, self) {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("the dapr on PATH is %s, not this CLI %s", found, self)
+		c.Hint = "remove the other installation of the CLI, or reorder PATH"
+		return c
+	}
+	c.Status = DoctorPass
+	c.Message = found
+	return c
+}
+
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fa, fb)
+}
+
+// checkEnvironmentComponents checks that the containers set up by init, or the placement binary in slim mode, are
+// running and healthy. The runtime binary is covered by checkRuntimeBinary.
+func checkEnvironmentComponents(environment *EnvironmentStatus, runtimeCmd string) []DoctorCheck {
+	var checks []DoctorCheck
+	for _, component := range environment.Components {
+		if component.Name == daprRuntimeFilePrefix {
+			continue
+		}
+		c := DoctorCheck{Name: component.Name, Status: DoctorPass, Message: component.Status}
+		if component.Version != "" {
+			c.Message += ", " + component.Version
+		}
+		if !component.Healthy() {
+			c.Status = DoctorFail
+			if component.Error != "" {
+				c.Message += ": " + component.Error
+			}
+			switch component.Status {
+			case RuntimeNotInstalled:
+				c.Hint = "run `dapr init`"
+			case ContainerUnknown:
+				c.Hint = "see the container runtime check"
+			case ContainerUnreachable:
+				c.Hint = fmt.Sprintf("restart it with `%s restart %s`", runtimeCmd, component.Name)
+			default:
+				c.Hint = fmt.Sprintf("start it with `%s start %s`, or see `%s logs %s`", runtimeCmd, component.Name, runtimeCmd, component.Name)
+			}
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkRedisHardening checks that the Redis containers were created hardened, the ones created by the CLIs before
+// the hardening have no label. The containers created with --unhardened pass.
+func checkRedisHardening(environment *EnvironmentStatus) []DoctorCheck {
+	var checks []DoctorCheck
+	for _, component := range environment.Components {
+		if component.Kind != "container" || !strings.HasPrefix(component.Name, DaprRedisContainerName) || component.Status == RuntimeNotInstalled || component.Status == ContainerUnknown {
+			continue
+		}
+		c := DoctorCheck{Name: component.Name + " hardening", Status: DoctorPass}
+		switch component.Hardening {
+		case "":
+			c.Status = DoctorWarn
+			c.Message = "created by an older CLI, it runs as root with a writable root filesystem"
+			c.Hint = "recreate it hardened with `dapr uninstall --all` and `dapr init`"
+		case redisUnhardened:
+			c.Message = "created with --unhardened"
+		default:
+			c.Message = "hardened, version " + component.Hardening
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkComponentsDir checks that the components directory exists and its resource files are valid.
+func checkComponentsDir(flagValue, daprRuntimePath string) DoctorCheck {
+	c := DoctorCheck{Name: "components directory"}
+	dir, err := ResolveComponentsPath(flagValue, daprRuntimePath)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Message = err.Error()
+		c.Hint = "fix the CLI config file"
+		return c
+	}
+	if _, err = os.Stat(dir.Path); err != nil {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("%s does not exist", dir)
+		c.Hint = "run `dapr init` to create the default components"
+		return c
+	}
+	problems, err := ValidateComponents(dir.Path)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Message = err.Error()
+		return c
+	}
+	if len(problems) > 0 {
+		c.Status = DoctorFail
+		c.Message = fmt.Sprintf("%d problem(s) in %s, the first one being %s", len(problems), dir.Path, problems[0])
+		c.Hint = fmt.Sprintf("run `dapr components validate %s`", dir.Path)
+		return c
+	}
+	c.Status = DoctorPass
+	c.Message = dir.String()
+	return c
+}
+
+// checkEnvVars checks that the variables of environ with the DAPR_ prefix of the variables of the CLI are ones it
+// reads, as a typo in the name of a variable silently leaves the setting to its default.
+func checkEnvVars(environ []string) DoctorCheck {
+	c := DoctorCheck{Name: "environment variables"}
+	unknown := UnrecognizedEnvVars(environ)
+	if len(unknown) == 0 {
+		c.Status = DoctorPass
+		c.Message = "no unrecognized DAPR_ variables"
+		return c
+	}
+	c.Status = DoctorWarn
+	c.Message = fmt.Sprintf("unrecognized: %s", strings.Join(unknown, ", "))
+	c.Hint = "check them for typos, see `dapr config view` for the settings of the CLI and their variables"
+	return c
+}
+
+// checkDownloadEndpoint checks that url, the endpoint the binaries are downloaded from, can be reached. It is only a
+// warning, as it is not needed once init is done.
+func checkDownloadEndpoint(url string) DoctorCheck {
+	c := DoctorCheck{Name: "download endpoint"}
+	ctx, cancel := context.WithTimeout(context.Background(), doctorDownloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		c.Status = DoctorWarn
+		c.Message = err.Error()
+		return c
+	}
+	req.Header.Set("User-Agent", cli_ver.CLI.UserAgent())
+	resp, err := utils.HTTPClient().Do(req)
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			err = fmt.Errorf("it answered with %s", resp.Status)
+		}
+	}
+	if err != nil {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("can't reach %s: %s", url, err)
+		c.Hint = "check the network and the HTTPS_PROXY environment variable, or init from a bundle with `dapr init --from-dir`"
+		return c
+	}
+	c.Status = DoctorPass
+	c.Message = fmt.Sprintf("%s is reachable", url)
+	return c
+}
+
+// checkDiskSpace checks the free disk space where the dapr install dir is, or would be created.
+func checkDiskSpace(daprDir string) DoctorCheck {
+	c := DoctorCheck{Name: "disk space"}
+	dir := daprDir
+	for {
+		if _, err := os.Stat(dir); err == nil || path_filepath.Dir(dir) == dir {
+			break
+		}
+		dir = path_filepath.Dir(dir)
+	}
+	usage, err := disk.Usage(dir)
+	if err != nil {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("can't get the free disk space of %s: %s", dir, err)
+		return c
+	}
+	return diskSpaceCheck(c, dir, usage.Free)
+}
+
+func diskSpaceCheck(c DoctorCheck, dir string, free uint64) DoctorCheck {
+	c.Message = fmt.Sprintf("%d MiB free in %s", free>>20, dir)
+	switch {
+	case free < doctorMinDiskSpace:
+		c.Status = DoctorFail
+		c.Hint = "free some disk space, for instance with `docker system prune`"
+	case free < doctorLowDiskSpace:
+		c.Status = DoctorWarn
+		c.Hint = "free some disk space, for instance with `docker system prune`"
+	default:
+		c.Status = DoctorPass
+	}
+	return c
+}
+
+// checkWindowsPath checks the entries of pathEnv, the PATH environment variable on Windows, for the mistakes which
+// keep executables from being found: quotes, which Windows doesn't strip, and directories which don't exist.
+func checkWindowsPath(pathEnv string) DoctorCheck {
+	c := DoctorCheck{Name: "PATH"}
+	problems := pathProblems(pathEnv, ";")
+	if len(problems) == 0 {
+		c.Status = DoctorPass
+		c.Message = fmt.Sprintf("%d entries", len(strings.Split(pathEnv, ";")))
+		return c
+	}
+	c.Status = DoctorWarn
+	c.Message = strings.Join(problems, "; ")
+	c.Hint = "fix PATH in the environment variables of the system settings"
+	return c
+}
+
+func pathProblems(pathEnv, separator string) []string {
+	var problems []string
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(pathEnv, separator) {
+		switch {
+		case strings.TrimSpace(entry) == "":
+			continue
+		case strings.ContainsRune(entry, '"'):
+			problems = append(problems, fmt.Sprintf("%s is quoted", entry))
+		case strings.TrimSpace(entry) != entry:
+			problems = append(problems, fmt.Sprintf("%q has surrounding spaces", entry))
+		case seen[strings.ToLower(entry)]:
+			problems = append(problems, fmt.Sprintf("%s is listed more than once", entry))
+		default:
+			if fi, err := os.Stat(entry); err != nil || !fi.IsDir() {
+				problems = append(problems, fmt.Sprintf("%s is not a directory", entry))
+			}
+		}
+		seen[strings.ToLower(entry)] = true
+	}
+	return problems
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(strings.TrimSpace(s), "\n", 2)[0]
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckContainerRuntimeNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	c := checkContainerRuntime("docker", false)
+	assert.Equal(t, DoctorFail, c.Status)
+	assert.Equal(t, "docker is not installed", c.Message)
+
+	// Slim mode runs no containers.
+	assert.Equal(t, DoctorWarn, checkContainerRuntime("docker", true).Status)
+}
+
+func TestCheckDockerSocket(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("docker uses a named pipe on Windows")
+	}
+	assert.Equal(t, DoctorPass, checkDockerSocket("tcp://10.0.0.1:2375").Status)
+	assert.Equal(t, DoctorWarn, checkDockerSocket("unix://"+filepath.Join(t.TempDir(), "docker.sock")).Status)
+
+	// Unix socket paths are limited to about 100 characters, which the temporary directory of the test can exceed.
+	dir, err := os.MkdirTemp("", "doctor")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "docker.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	c := checkDockerSocket("unix://" + socket)
+	assert.Equal(t, DoctorPass, c.Status, c.Message)
+}
+
+func TestCheckPorts(t *testing.T) {
+	ports := map[string]string{}
+	for _, c := range checkPorts(&EnvironmentStatus{SlimMode: true}, "") {
+		ports[c.Name] = c.Status
+	}
+	// Slim mode publishes no container ports.
+	assert.NotContains(t, ports, "port 6379")
+	assert.Contains(t, ports, "port 3500")
+
+	ln, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer ln.Close()
+	busy := ln.Addr().(*net.TCPAddr).Port
+	meta := &DaprMeta{portApps: map[int]string{}}
+	environment := &EnvironmentStatus{Components: []EnvironmentComponent{{Name: DaprRedisContainerName + "_mynet", Kind: "container", Status: ContainerRunning}}}
+
+	assert.Equal(t, DoctorPass, checkPort(doctorPort{port: busy, container: DaprRedisContainerName}, environment, meta).Status)
+	c := checkPort(doctorPort{port: busy, container: DaprZipkinContainerName}, environment, meta)
+	assert.Equal(t, DoctorFail, c.Status)
+	assert.Contains(t, c.Message, "dapr_zipkin can't publish it")
+	c = checkPort(doctorPort{port: busy, flag: "--dapr-http-port"}, environment, meta)
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Contains(t, c.Hint, "--dapr-http-port")
+	meta.portApps[busy] = "myapp"
+	assert.Equal(t, DoctorPass, checkPort(doctorPort{port: busy, flag: "--dapr-http-port"}, environment, meta).Status)
+}
+
+func TestBinaryPlatform(t *testing.T) {
+	self, err := os.Executable()
+	require.NoError(t, err)
+	platform, err := binaryPlatform(self)
+	require.NoError(t, err)
+	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" || runtime.GOOS == "freebsd" || runtime.GOOS == daprWindowsOS {
+		assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, platform)
+	}
+
+	script := filepath.Join(t.TempDir(), "daprd")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho 1.11.0\n"), 0o755))
+	_, err = binaryPlatform(script)
+	assert.Error(t, err)
+}
+
+func TestCheckRuntimeBinary(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+	runtimePath := t.TempDir()
+
+	c := checkRuntimeBinary(runtimePath)
+	assert.Equal(t, DoctorFail, c.Status)
+	assert.Equal(t, "run `dapr init`", c.Hint)
+
+	binDir := getDaprBinPath(filepath.Join(runtimePath, DefaultDaprDirName))
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	daprd := filepath.Join(binDir, daprRuntimeFilePrefix)
+	require.NoError(t, os.WriteFile(daprd, []byte("#!/bin/sh\necho 1.11.0\n"), 0o755))
+	c = checkRuntimeBinary(runtimePath)
+	assert.Equal(t, DoctorPass, c.Status)
+	assert.Equal(t, daprd+" 1.11.0", c.Message)
+
+	require.NoError(t, os.WriteFile(daprd, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+	assert.Equal(t, DoctorFail, checkRuntimeBinary(runtimePath).Status)
+}
+
+func TestCheckEnvironmentComponents(t *testing.T) {
+	checks := checkEnvironmentComponents(&EnvironmentStatus{Components: []EnvironmentComponent{
+		{Name: daprRuntimeFilePrefix, Kind: "binary", Status: RuntimeInstalled},
+		{Name: DaprPlacementContainerName, Kind: "container", Status: ContainerRunning, Version: "ghcr.io/dapr/placement:1.11.0"},
+		{Name: DaprRedisContainerName, Kind: "container", Status: "exited"},
+		{Name: DaprZipkinContainerName, Kind: "container", Status: RuntimeNotInstalled},
+	}}, "docker")
+	require.Len(t, checks, 3)
+	assert.Equal(t, DoctorCheck{Name: DaprPlacementContainerName, Status: DoctorPass, Message: "running, ghcr.io/dapr/placement:1.11.0"}, checks[0])
+	assert.Equal(t, DoctorFail, checks[1].Status)
+	assert.Equal(t, "start it with `docker start dapr_redis`, or see `docker logs dapr_redis`", checks[1].Hint)
+	assert.Equal(t, "run `dapr init`", checks[2].Hint)
+}
+
+func TestCheckRedisHardening(t *testing.T) {
+	checks := checkRedisHardening(&EnvironmentStatus{Components: []EnvironmentComponent{
+		{Name: DaprPlacementContainerName, Kind: "container", Status: ContainerRunning},
+		{Name: DaprRedisContainerName, Kind: "container", Status: ContainerRunning},
+		{Name: DaprRedisContainerName + "_mynet", Kind: "container", Status: "exited", Hardening: redisHardeningVersion},
+		{Name: DaprRedisContainerName + "_other", Kind: "container", Status: ContainerRunning, Hardening: redisUnhardened},
+		{Name: DaprRedisContainerName + "_missing", Kind: "container", Status: RuntimeNotInstalled},
+	}})
+	require.Len(t, checks, 3)
+	assert.Equal(t, DoctorWarn, checks[0].Status)
+	assert.Contains(t, checks[0].Hint, "dapr uninstall --all")
+	assert.Equal(t, DoctorPass, checks[1].Status)
+	assert.Equal(t, DoctorPass, checks[2].Status)
+	assert.Equal(t, "created with --unhardened", checks[2].Message)
+}
+
+func TestCheckComponentsDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "components")
+	assert.Equal(t, DoctorWarn, checkComponentsDir(dir, t.TempDir()).Status)
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "statestore.yaml"), []byte("apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: statestore\nspec:\n  type: state.redis\n  version: v1\n"), 0o644))
+	assert.Equal(t, DoctorPass, checkComponentsDir(dir, t.TempDir()).Status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("kind: [\n"), 0o644))
+	c := checkComponentsDir(dir, t.TempDir())
+	assert.Equal(t, DoctorFail, c.Status)
+	assert.Contains(t, c.Hint, "dapr components validate")
+}
+
+func TestCheckEnvVars(t *testing.T) {
+	assert.Equal(t, DoctorPass, checkEnvVars([]string{"DAPR_NETWORK=mynet"}).Status)
+	c := checkEnvVars([]string{"DAPR_NETWROK=mynet", "DAPR_REDIS_PROT=6380"})
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Equal(t, "unrecognized: DAPR_NETWROK, DAPR_REDIS_PROT", c.Message)
+}
+
+func TestCheckDownloadEndpoint(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	assert.Equal(t, DoctorPass, checkDownloadEndpoint(ts.URL).Status)
+	status = http.StatusBadGateway
+	c := checkDownloadEndpoint(ts.URL)
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Contains(t, c.Message, "502")
+}
+
+func TestDiskSpaceCheck(t *testing.T) {
+	assert.Equal(t, DoctorPass, diskSpaceCheck(DoctorCheck{}, "/", 10<<30).Status)
+	c := diskSpaceCheck(DoctorCheck{}, "/", 1<<30)
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Equal(t, "1024 MiB free in /", c.Message)
+	assert.Equal(t, DoctorFail, diskSpaceCheck(DoctorCheck{}, "/", 100<<20).Status)
+
+	// The install dir doesn't need to exist yet.
+	dir := t.TempDir()
+	assert.Contains(t, checkDiskSpace(filepath.Join(dir, "missing", ".dapr")).Message, "free in "+dir)
+}
+
+func TestPathProblems(t *testing.T) {
+	dir := t.TempDir()
+	entries := []string{dir, `"` + dir + `"`, "", dir + " ", strings.ToUpper(dir), filepath.Join(dir, "missing")}
+	problems := pathProblems(strings.Join(entries, ";"), ";")
+	require.Len(t, problems, 4, strings.Join(problems, "\n"))
+	assert.Contains(t, problems[0], "is quoted")
+	assert.Contains(t, problems[1], "has surrounding spaces")
+	assert.Contains(t, problems[2], "is listed more than once")
+	assert.Contains(t, problems[3], "is not a directory")
+	assert.Empty(t, pathProblems(dir+";;", ";"))
+}
+
+func TestPlatformCheck(t *testing.T) {
+	assert.Equal(t, DoctorCheck{Name: "platform", Status: DoctorPass, Message: "native darwin/arm64"}, platformCheck("darwin/arm64", nil))
+
+	c := platformCheck("darwin/arm64", []string{"daprd is darwin/amd64", "dapr_placement is linux/amd64"})
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Equal(t, "emulated on darwin/arm64: daprd is darwin/amd64, dapr_placement is linux/amd64", c.Message)
+	assert.Contains(t, c.Hint, "darwin/arm64 build of the CLI")
+}
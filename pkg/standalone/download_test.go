@@ -0,0 +1,252 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/utils"
+)
+
+// redirectTransport sends all the requests to target, such as a httptest server standing for GitHub.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// serveReleases makes the requests of the CLI go to handler for the duration of the test.
+func serveReleases(t *testing.T, handler http.HandlerFunc) {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	target, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	previous := utils.SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+	t.Cleanup(func() { utils.SetHTTPClient(previous) })
+}
+
+func TestDownloadBinaryRetry(t *testing.T) {
+	defaultBackoff := initStepRetryBackoff
+	initStepRetryBackoff = time.Millisecond
+	t.Cleanup(func() { initStepRetryBackoff = defaultBackoff })
+
+	var downloads int32
+	serveReleases(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, muslArtifactSuffix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet && atomic.AddInt32(&downloads, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("archive"))
+	})
+
+	dir := t.TempDir()
+	var archive downloadedFile
+	step := initStep{name: StepDaprdBinary, retryable: true, run: func(ctx context.Context, _ initInfo) error {
+		var err error
+		archive, err = downloadBinary(ctx, dir, "1.11.0", daprRuntimeFilePrefix, "dapr", nil)
+		return err
+	}}
+	require.NoError(t, runInitStep(context.Background(), step, initInfo{retries: 1}, discardInitEvents))
+	assert.Equal(t, int32(2), downloads)
+	assert.FileExists(t, archive.path)
+}
+
+func TestDownloadBinaryVersionNotFound(t *testing.T) {
+	serveReleases(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/dapr/dapr/releases" {
+			w.Write([]byte(`[{"tag_name": "v1.12.0"}, {"tag_name": "v1.11.3"}, {"tag_name": "v1.11.2"}, {"tag_name": "v1.11.1"}, {"tag_name": "v1.11.0"}, {"tag_name": "v1.10.0"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := downloadBinary(context.Background(), t.TempDir(), "1.11.9", daprRuntimeFilePrefix, "dapr", nil)
+	assert.ErrorIs(t, err, ErrNoBuildPublished)
+	assert.ErrorContains(t, err, "daprd 1.11.9 isn't released, did you mean 1.11.3 or 1.11.2 or 1.11.1?")
+
+	_, err = downloadBinary(context.Background(), t.TempDir(), "1.13.0", daprRuntimeFilePrefix, "dapr", nil)
+	assert.ErrorContains(t, err, "did you mean 1.12.0?")
+
+	// A released version merely has no build for this machine.
+	_, err = downloadBinary(context.Background(), t.TempDir(), "1.11.3", daprRuntimeFilePrefix, "dapr", nil)
+	assert.ErrorIs(t, err, ErrNoBuildPublished)
+	assert.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestDownloadBinaryChecksumMismatch(t *testing.T) {
+	sum := sha256.Sum256([]byte("the published archive"))
+	serveReleases(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, muslArtifactSuffix):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, ".sha256"):
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  " + binaryName(daprRuntimeFilePrefix) + "\n"))
+		default:
+			w.Write([]byte("a tampered archive"))
+		}
+	})
+
+	dir := t.TempDir()
+	archive, err := downloadBinary(context.Background(), dir, "1.11.0", daprRuntimeFilePrefix, "dapr", nil)
+	require.NoError(t, err)
+	checksum, err := fetchPublishedChecksum(context.Background(), dir, "1.11.0", daprRuntimeFilePrefix, "dapr")
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), checksum)
+	_, err = verifyArchiveChecksum(archive, daprRuntimeFilePrefix, checksum)
+	assert.ErrorContains(t, err, "the checksum of the downloaded daprd archive is")
+	assert.NoFileExists(t, archive.path)
+}
+
+// serveArchive serves content, honoring the Range header if ranges is set, and counts the bytes it sends in sent.
+func serveArchive(t testing.TB, content []byte, ranges bool, sent *atomic.Int64) *[]string {
+	var requested []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Header.Get("Range"))
+		if !ranges {
+			r.Header.Del("Range")
+		}
+		cw := &countingWriter{ResponseWriter: w, n: sent}
+		http.ServeContent(cw, r, "daprd.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(ts.Close)
+	target, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	previous := utils.SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+	t.Cleanup(func() { utils.SetHTTPClient(previous) })
+	return &requested
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	content := bytes.Repeat([]byte("daprd archive "), 4096)
+	sum := sha256.Sum256(content)
+	const downloadURL = "https://github.com/dapr/dapr/releases/download/v1.11.0/daprd_linux_amd64.tar.gz"
+	dir := "/downloads"
+	path := filepath.Join(dir, "daprd_linux_amd64.tar.gz")
+	half := int64(len(content) / 2)
+
+	for _, ranges := range []bool{true, false} {
+		m := newMemFS(t)
+		require.NoError(t, m.MkdirAll(dir, 0o755))
+		m.writeFile(partialDownloadPath(path, downloadURL), content[:half], 0o644)
+		var sent atomic.Int64
+		requested := serveArchive(t, content, ranges, &sent)
+
+		archive, err := downloadFile(context.Background(), dir, downloadURL, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{fmt.Sprintf("bytes=%d-", half)}, *requested)
+		assert.Equal(t, downloadedFile{path: path, sha256: hex.EncodeToString(sum[:])}, archive)
+		data, _, ok := m.readFile(path)
+		require.True(t, ok)
+		assert.Equal(t, content, data)
+		assert.NotContains(t, memFiles(m), partialDownloadPath(path, downloadURL))
+		// The part already downloaded is read once to resume the checksum, and only read again if it wasn't resumed.
+		assert.Equal(t, half, m.read.Load())
+		if ranges {
+			assert.Equal(t, int64(len(content))-half, sent.Load())
+		} else {
+			assert.Equal(t, int64(len(content)), sent.Load())
+		}
+	}
+}
+
+func TestDownloadFileKeepsInterruptedDownload(t *testing.T) {
+	m := newMemFS(t)
+	require.NoError(t, m.MkdirAll("/downloads", 0o755))
+	serveReleases(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("daprd"))
+	})
+
+	const downloadURL = "https://github.com/dapr/dapr/releases/download/v1.11.0/daprd_linux_amd64.tar.gz"
+	_, err := downloadFile(context.Background(), "/downloads", downloadURL, nil)
+	require.Error(t, err)
+	data, _, ok := m.readFile(partialDownloadPath("/downloads/daprd_linux_amd64.tar.gz", downloadURL))
+	require.True(t, ok)
+	assert.Equal(t, "daprd", string(data))
+	// The partial download of another version isn't resumed.
+	assert.NotEqual(t, partialDownloadPath("/downloads/daprd_linux_amd64.tar.gz", downloadURL), partialDownloadPath("/downloads/daprd_linux_amd64.tar.gz", strings.Replace(downloadURL, "1.11.0", "1.12.0", 1)))
+}
+
+func TestDownloadVerifiesWithoutReading(t *testing.T) {
+	m := newMemFS(t)
+	require.NoError(t, m.MkdirAll("/downloads", 0o755))
+	content := []byte("daprd archive")
+	sum := sha256.Sum256(content)
+	var sent atomic.Int64
+	serveArchive(t, content, true, &sent)
+
+	archive, err := downloadFile(context.Background(), "/downloads", "https://github.com/dapr/dapr/releases/download/v1.11.0/daprd_linux_amd64.tar.gz", nil)
+	require.NoError(t, err)
+	_, err = verifyArchiveChecksum(archive, daprRuntimeFilePrefix, hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	_, err = verifyLockedChecksum(initInfo{lock: &Lockfile{Binaries: []LockedBinary{{Name: daprRuntimeFilePrefix, SHA256: hex.EncodeToString(sum[:])}}}}, daprRuntimeFilePrefix, archive)
+	require.NoError(t, err)
+	assert.Zero(t, m.read.Load())
+}
+
+// BenchmarkDownloadFile resumes a download of which half is already present, and reports the passes over the content
+// it makes, reading from the disk and the network, which is 1.
+func BenchmarkDownloadFile(b *testing.B) {
+	content := bytes.Repeat([]byte("daprd archive "), 1<<16)
+	const downloadURL = "https://github.com/dapr/dapr/releases/download/v1.11.0/daprd_linux_amd64.tar.gz"
+	m := newMemFS(b)
+	require.NoError(b, m.MkdirAll("/downloads", 0o755))
+	var sent atomic.Int64
+	serveArchive(b, content, true, &sent)
+	partial := partialDownloadPath("/downloads/daprd_linux_amd64.tar.gz", downloadURL)
+
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.writeFile(partial, content[:len(content)/2], 0o644)
+		if _, err := downloadFile(context.Background(), "/downloads", downloadURL, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(m.read.Load()+sent.Load())/float64(int64(b.N)*int64(len(content))), "passes/op")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+)
+
+const (
+	// ElevationNotNeeded, ElevationPerUser and ElevationUAC are the decisions of PlanElevation for an install on
+	// Windows: it runs as is, it is moved to the per-user location, or it is relaunched through a UAC prompt.
+	ElevationNotNeeded = "not-needed"
+	ElevationPerUser   = "per-user"
+	ElevationUAC       = "uac"
+
+	// ElevatedReportFlag is the hidden flag of init the install relaunched through a UAC prompt writes its report
+	// to, for the install which relaunched it.
+	ElevatedReportFlag = "elevated-report"
+)
+
+// ErrElevationDeclined is returned when the UAC prompt of an install relaunched by RunElevated is declined.
+var ErrElevationDeclined = errors.New("the UAC prompt to run the install as administrator was declined")
+
+// ElevationReport records how an install on Windows dealt with a prompt which isn't elevated.
+type ElevationReport struct {
+	Elevated     bool   `json:"elevated"`
+	Decision     string `json:"decision"`
+	Reason       string `json:"reason,omitempty"`
+	RequestedDir string `json:"requestedDir,omitempty"`
+	InstallDir   string `json:"installDir"`
+	// ExitCode is the exit code of the install relaunched through a UAC prompt.
+	ExitCode *int `json:"exitCode,omitempty"`
+}
+
+// PlanElevation decides how to run an install in the runtime path flagPath, with initSystem, from the current prompt.
+// The installs which need administrator rights, to write to a location the user can't or to register Windows
+// services, are relaunched through a UAC prompt if they were explicitly requested with --runtime-path, DAPR_HOME or
+// --init-system, or moved to the per-user location otherwise.
+func PlanElevation(flagPath, initSystem string) (*ElevationReport, error) {
+	installDir, err := GetDaprRuntimePath(flagPath)
+	if err != nil {
+		return nil, err
+	}
+	report := &ElevationReport{Elevated: isElevated(), InstallDir: installDir}
+	reason := ""
+	switch {
+	case initSystem == InitSystemWindowsService:
+		reason = "the Windows services are registered for the whole machine"
+	case !installDirWritable(installDir):
+		reason = installDir + " is not writable by the current user"
+	}
+	explicit := strings.TrimSpace(flagPath) != "" || daprHomeSet() || initSystem == InitSystemWindowsService
+	report.Decision = elevationDecision(report.Elevated, reason != "", explicit)
+	if report.Decision == ElevationNotNeeded {
+		return report, nil
+	}
+	report.Reason = reason
+	if report.Decision == ElevationPerUser {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		report.RequestedDir = installDir
+		report.InstallDir = path_filepath.Join(home, DefaultDaprDirName)
+	}
+	return report, nil
+}
+
+// elevationDecision returns the decision of PlanElevation for an install which needs administrator rights, from a
+// prompt which is elevated or not, explicitly requested or not.
+func elevationDecision(elevated, needsAdmin, explicit bool) string {
+	switch {
+	case elevated || !needsAdmin:
+		return ElevationNotNeeded
+	case explicit:
+		return ElevationUAC
+	default:
+		return ElevationPerUser
+	}
+}
+
+// installDirWritable reports whether the current user can create installDir, or write to it if it exists.
+func installDirWritable(installDir string) bool {
+	dir := installDir
+	for {
+		if info, err := os.Stat(dir); err == nil {
+			return info.IsDir() && dirWritable(dir)
+		}
+		parent := path_filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// RunElevated relaunches the CLI with args through a UAC prompt, waits for it to exit and returns the report the
+// relaunched init wrote, with its exit code in the elevation report.
+func RunElevated(args []string, elevation *ElevationReport) (*InitReport, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate the running CLI: %w", err)
+	}
+	f, err := os.CreateTemp("", "dapr-elevated-init-*.json")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	code, err := runElevated(exe, append(append([]string{}, args...), "--"+ElevatedReportFlag, f.Name()))
+	if err != nil {
+		return nil, err
+	}
+	elevation.ExitCode = &code
+	report := &InitReport{}
+	b, err := os.ReadFile(f.Name())
+	if err == nil && len(b) > 0 {
+		err = json.Unmarshal(b, report)
+	}
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("the install run as administrator exited with code %d without a report", code)
+	}
+	report.Elevation = elevation
+	if code != 0 && report.Error == "" {
+		report.Error = fmt.Sprintf("the install run as administrator exited with code %d", code)
+	}
+	return report, nil
+}
+
+// WriteElevatedReport writes report to path, the file given with ElevatedReportFlag by RunElevated.
+func WriteElevatedReport(path string, report *InitReport) error {
+	b, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
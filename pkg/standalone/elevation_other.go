@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"os"
+)
+
+// isElevated reports whether the CLI runs as root.
+func isElevated() bool {
+	return os.Geteuid() == 0
+}
+
+func runElevated(exe string, args []string) (int, error) {
+	return 0, errors.New("running the install through a UAC prompt is only available on Windows")
+}
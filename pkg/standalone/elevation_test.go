@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElevationDecision(t *testing.T) {
+	assert.Equal(t, ElevationNotNeeded, elevationDecision(true, true, true))
+	assert.Equal(t, ElevationNotNeeded, elevationDecision(false, false, true))
+	assert.Equal(t, ElevationUAC, elevationDecision(false, true, true))
+	assert.Equal(t, ElevationPerUser, elevationDecision(false, true, false))
+}
+
+func TestInstallDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	assert.True(t, installDirWritable(dir))
+	assert.True(t, installDirWritable(filepath.Join(dir, "missing", DefaultDaprDirName)))
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	assert.False(t, installDirWritable(file))
+
+	if runtime.GOOS == daprWindowsOS || os.Geteuid() == 0 {
+		t.Skip("the permissions of the directory don't apply")
+	}
+	readOnly := filepath.Join(dir, "read-only")
+	require.NoError(t, os.Mkdir(readOnly, 0o555))
+	assert.False(t, installDirWritable(filepath.Join(readOnly, DefaultDaprDirName)))
+
+	report, err := PlanElevation(readOnly, InitSystemNone)
+	require.NoError(t, err)
+	assert.Equal(t, ElevationUAC, report.Decision)
+	assert.Contains(t, report.Reason, "is not writable")
+
+	t.Setenv("DAPR_RUNTIME_PATH", readOnly)
+	report, err = PlanElevation("", InitSystemNone)
+	require.NoError(t, err)
+	assert.Equal(t, ElevationPerUser, report.Decision)
+	assert.Equal(t, filepath.Join(readOnly, DefaultDaprDirName), report.RequestedDir)
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, DefaultDaprDirName), report.InstallDir)
+}
+
+func TestPlanElevationNotNeeded(t *testing.T) {
+	report, err := PlanElevation(t.TempDir(), InitSystemNone)
+	require.NoError(t, err)
+	assert.Equal(t, ElevationNotNeeded, report.Decision)
+	assert.Empty(t, report.Reason)
+}
+
+func TestWriteElevatedReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, WriteElevatedReport(path, &InitReport{RuntimeVersion: "1.11.0", Error: "failed"}))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"runtimeVersion":"1.11.0","installDir":"","binDir":"","slimMode":false,"steps":null,"error":"failed"}`, string(b))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procShellExecuteEx = windows.NewLazySystemDLL("shell32.dll").NewProc("ShellExecuteExW")
+
+// shellExecuteInfo is the SHELLEXECUTEINFOW structure of ShellExecuteExW.
+type shellExecuteInfo struct {
+	size       uint32
+	mask       uint32
+	hwnd       windows.Handle
+	verb       *uint16
+	file       *uint16
+	parameters *uint16
+	directory  *uint16
+	show       int32
+	instApp    windows.Handle
+	idList     uintptr
+	class      *uint16
+	keyClass   windows.Handle
+	hotKey     uint32
+	icon       windows.Handle
+	process    windows.Handle
+}
+
+// isElevated reports whether the CLI runs from an elevated prompt, with administrator rights.
+func isElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+// runElevated runs exe with args as administrator, through a UAC prompt, and returns its exit code once it exits.
+func runElevated(exe string, args []string) (int, error) {
+	const (
+		seeMaskNoCloseProcess = 0x00000040
+		swShowNormal          = 1
+	)
+	verb, err := windows.UTF16PtrFromString("runas")
+	if err != nil {
+		return 0, err
+	}
+	file, err := windows.UTF16PtrFromString(exe)
+	if err != nil {
+		return 0, err
+	}
+	parameters, err := windows.UTF16PtrFromString(joinWindowsArgs(args))
+	if err != nil {
+		return 0, err
+	}
+	info := shellExecuteInfo{mask: seeMaskNoCloseProcess, verb: verb, file: file, parameters: parameters, show: swShowNormal}
+	info.size = uint32(unsafe.Sizeof(info))
+	if ok, _, callErr := procShellExecuteEx.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		if errors.Is(callErr, windows.ERROR_CANCELLED) {
+			return 0, ErrElevationDeclined
+		}
+		return 0, fmt.Errorf("error running the install as administrator: %w", callErr)
+	}
+	defer windows.CloseHandle(info.process)
+	if _, err = windows.WaitForSingleObject(info.process, windows.INFINITE); err != nil {
+		return 0, fmt.Errorf("error waiting for the install run as administrator: %w", err)
+	}
+	var code uint32
+	if err = windows.GetExitCodeProcess(info.process, &code); err != nil {
+		return 0, err
+	}
+	return int(code), nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dapr/cli/utils"
+)
+
+// Endpoints are the endpoints of an instance and the settings in effect, as summarized by the run command once the
+// instance started. They are also reported by the wide output of the list command and by the metadata command, so
+// that scripts don't need to parse the output of run.
+type Endpoints struct {
+	AppID                string   `json:"appId"                          yaml:"appId"`
+	HTTPEndpoint         string   `json:"httpEndpoint"                   yaml:"httpEndpoint"`
+	GRPCEndpoint         string   `json:"grpcEndpoint"                   yaml:"grpcEndpoint"`
+	AppPort              int      `json:"appPort,omitempty"              yaml:"appPort,omitempty"`
+	ResourcesPaths       []string `json:"resourcesPaths,omitempty"       yaml:"resourcesPaths,omitempty"`
+	ConfigFile           string   `json:"configFile,omitempty"           yaml:"configFile,omitempty"`
+	PlacementHostAddress string   `json:"placementHostAddress,omitempty" yaml:"placementHostAddress,omitempty"`
+	DaprdLogPath         string   `json:"daprdLogPath,omitempty"         yaml:"daprdLogPath,omitempty"`
+	AppLogPath           string   `json:"appLogPath,omitempty"           yaml:"appLogPath,omitempty"`
+	// AppProtocol is the protocol daprd talks to the app with, e.g. https for an app serving TLS, and AppEndpoint
+	// the address of the app with its scheme, for the tools calling the app directly.
+	AppProtocol string `json:"appProtocol,omitempty" yaml:"appProtocol,omitempty"`
+	AppEndpoint string `json:"appEndpoint,omitempty" yaml:"appEndpoint,omitempty"`
+}
+
+// Endpoints returns the endpoints of the instance of a validated config.
+func (config *RunConfig) Endpoints() Endpoints {
+	resourcesPaths := config.ResourcesPaths
+	if len(resourcesPaths) == 0 && config.ComponentsPath != "" {
+		resourcesPaths = []string{config.ComponentsPath}
+	}
+	appPort := 0
+	if config.AppPort > 0 {
+		appPort = config.AppPort
+	}
+	return newEndpoints(Endpoints{
+		AppID:                config.AppID,
+		AppPort:              appPort,
+		AppProtocol:          EffectiveAppProtocol(config.AppProtocol, config.AppSSL),
+		ResourcesPaths:       resourcesPaths,
+		ConfigFile:           config.ConfigFile,
+		PlacementHostAddress: config.PlacementHostAddr,
+	}, config.HTTPPort, config.GRPCPort, config.UnixDomainSocket)
+}
+
+// Endpoints returns the endpoints of a listed instance.
+func (l ListOutput) Endpoints() Endpoints {
+	return newEndpoints(Endpoints{
+		AppID:                l.AppID,
+		AppPort:              l.AppPort,
+		AppProtocol:          l.AppProtocol,
+		ResourcesPaths:       l.ResourcesPaths,
+		ConfigFile:           l.ConfigFile,
+		PlacementHostAddress: l.PlacementHostAddress,
+		DaprdLogPath:         l.DaprDLogPath,
+		AppLogPath:           l.AppLogPath,
+	}, l.HTTPPort, l.GRPCPort, l.UnixDomainSocket)
+}
+
+func newEndpoints(e Endpoints, httpPort, grpcPort int, unixDomainSocket string) Endpoints {
+	if unixDomainSocket != "" {
+		e.HTTPEndpoint = "unix://" + utils.GetSocket(unixDomainSocket, e.AppID, "http")
+		e.GRPCEndpoint = "unix://" + utils.GetSocket(unixDomainSocket, e.AppID, "grpc")
+	} else {
+		e.HTTPEndpoint = fmt.Sprintf("http://localhost:%d", httpPort)
+		e.GRPCEndpoint = fmt.Sprintf("localhost:%d", grpcPort)
+	}
+	if e.AppPort <= 0 {
+		e.AppProtocol = ""
+		return e
+	}
+	if e.AppProtocol == "" {
+		e.AppProtocol = "http"
+	}
+	switch e.AppProtocol {
+	case "https":
+		e.AppEndpoint = fmt.Sprintf("https://localhost:%d", e.AppPort)
+	case "grpc", "grpcs":
+		e.AppEndpoint = fmt.Sprintf("localhost:%d", e.AppPort)
+	default:
+		e.AppEndpoint = fmt.Sprintf("http://localhost:%d", e.AppPort)
+	}
+	return e
+}
+
+// EffectiveAppProtocol returns the protocol daprd talks to the app with: protocol, or its TLS variant if ssl is set,
+// as with the deprecated --app-ssl flag.
+func EffectiveAppProtocol(protocol string, ssl bool) string {
+	if protocol == "" {
+		protocol = "http"
+	}
+	if ssl {
+		switch protocol {
+		case "http":
+			return "https"
+		case "grpc":
+			return "grpcs"
+		}
+	}
+	return protocol
+}
+
+// IsTLSAppProtocol returns true if daprd talks to the app with protocol over TLS.
+func IsTLSAppProtocol(protocol string) bool {
+	return protocol == "https" || protocol == "grpcs"
+}
+
+// Rows returns the labels and values of the endpoints which are set, in the order they are printed.
+func (e Endpoints) Rows() [][2]string {
+	rows := [][2]string{
+		{"App ID", e.AppID},
+		{"Dapr HTTP", e.HTTPEndpoint},
+		{"Dapr gRPC", e.GRPCEndpoint},
+	}
+	if e.AppPort > 0 {
+		rows = append(rows, [2]string{"App port", strconv.Itoa(e.AppPort)})
+		// The apps talked to with plain http, the most of them, aren't told apart.
+		switch e.AppProtocol {
+		case "", "http":
+		case "https":
+			rows = append(rows, [2]string{"App", e.AppEndpoint})
+		default:
+			rows = append(rows, [2]string{"App", e.AppEndpoint + " (" + e.AppProtocol + ")"})
+		}
+	}
+	for _, row := range [][2]string{
+		{"Resources", strings.Join(e.ResourcesPaths, ", ")},
+		{"Config", e.ConfigFile},
+		{"Placement", e.PlacementHostAddress},
+		{"Dapr logs", e.DaprdLogPath},
+		{"App logs", e.AppLogPath},
+	} {
+		if row[1] != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Banner returns the endpoints as aligned lines of labels and values.
+func (e Endpoints) Banner() []string {
+	rows := e.Rows()
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width+1, row[0]+":", row[1]))
+	}
+	return lines
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoints(t *testing.T) {
+	t.Run("run config", func(t *testing.T) {
+		config := &RunConfig{
+			AppID:    "orders",
+			AppPort:  -1,
+			HTTPPort: 3500,
+			GRPCPort: 50001,
+			SharedRunConfig: SharedRunConfig{
+				ComponentsPath:    "/home/user/.dapr/components",
+				ConfigFile:        "/home/user/.dapr/config.yaml",
+				PlacementHostAddr: "localhost:50005",
+			},
+		}
+		e := config.Endpoints()
+		assert.Equal(t, "http://localhost:3500", e.HTTPEndpoint)
+		assert.Equal(t, "localhost:50001", e.GRPCEndpoint)
+		assert.Equal(t, 0, e.AppPort)
+		assert.Equal(t, []string{"/home/user/.dapr/components"}, e.ResourcesPaths)
+		assert.Equal(t, []string{
+			"App ID:     orders",
+			"Dapr HTTP:  http://localhost:3500",
+			"Dapr gRPC:  localhost:50001",
+			"Resources:  /home/user/.dapr/components",
+			"Config:     /home/user/.dapr/config.yaml",
+			"Placement:  localhost:50005",
+		}, e.Banner())
+	})
+
+	t.Run("listed instance with a socket", func(t *testing.T) {
+		e := ListOutput{
+			AppID:            "checkout",
+			AppPort:          8080,
+			UnixDomainSocket: "/tmp",
+			ResourcesPaths:   []string{"./a", "./b"},
+			DaprDLogPath:     "/logs/daprd.log",
+		}.Endpoints()
+		assert.Equal(t, "unix:///tmp/dapr-checkout-http.socket", e.HTTPEndpoint)
+		assert.Equal(t, [][2]string{
+			{"App ID", "checkout"},
+			{"Dapr HTTP", "unix:///tmp/dapr-checkout-http.socket"},
+			{"Dapr gRPC", "unix:///tmp/dapr-checkout-grpc.socket"},
+			{"App port", "8080"},
+			{"Resources", "./a, ./b"},
+			{"Dapr logs", "/logs/daprd.log"},
+		}, e.Rows())
+	})
+
+	t.Run("app serving tls", func(t *testing.T) {
+		config := &RunConfig{
+			AppID:           "orders",
+			AppPort:         3000,
+			HTTPPort:        3500,
+			GRPCPort:        50001,
+			SharedRunConfig: SharedRunConfig{AppProtocol: "http", AppSSL: true},
+		}
+		e := config.Endpoints()
+		assert.Equal(t, "https", e.AppProtocol)
+		assert.Equal(t, "https://localhost:3000", e.AppEndpoint)
+		assert.Contains(t, e.Rows(), [2]string{"App", "https://localhost:3000"})
+
+		e = ListOutput{AppID: "orders", AppPort: 3000, AppProtocol: "grpcs"}.Endpoints()
+		assert.Equal(t, "localhost:3000", e.AppEndpoint)
+		assert.Contains(t, e.Rows(), [2]string{"App", "localhost:3000 (grpcs)"})
+
+		// The ones without an app port have no app endpoint.
+		e = (&RunConfig{AppID: "orders", AppPort: -1, SharedRunConfig: SharedRunConfig{AppProtocol: "https"}}).Endpoints()
+		assert.Empty(t, e.AppProtocol)
+		assert.Empty(t, e.AppEndpoint)
+	})
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnvConflict is an environment variable of the app defined more than once, with the value which is used.
+type EnvConflict struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+func (c EnvConflict) String() string {
+	return fmt.Sprintf("%s is defined more than once, using %q from %s", c.Key, c.Value, c.Source)
+}
+
+// ParseEnvFile parses an env file: a KEY=VALUE definition per line, optionally prefixed with export. Empty lines and
+// the lines starting with # are skipped. Values can be quoted with single quotes, taken as they are, or with double
+// quotes, where \n, \t, \" and \\ are unescaped. Unquoted values end at a # preceded by a space.
+func ParseEnvFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading env file: %w", err)
+	}
+	env := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value, err = parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		env[key] = value
+	}
+	return env, scanner.Err()
+}
+
+func parseEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '\'', '"':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value %s", value)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after the quoted value", rest)
+		}
+		value = value[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return value, nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// ResolveAppEnv returns the environment variables of the app from an env file, if not empty, and the KEY=VALUE
+// definitions of env, which win over the env file. The variables defined more than once are returned as conflicts.
+func ResolveAppEnv(envFile string, env []string) (map[string]string, []EnvConflict, error) {
+	resolved := map[string]string{}
+	var conflicts []EnvConflict
+	set := func(key, value, source string) {
+		if _, ok := resolved[key]; ok {
+			conflicts = append(conflicts, EnvConflict{Key: key, Value: value, Source: source})
+		}
+		resolved[key] = value
+	}
+
+	if envFile != "" {
+		fileEnv, err := ParseEnvFile(envFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range sortedKeys(fileEnv) {
+			set(key, fileEnv[key], envFile)
+		}
+	}
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, nil, fmt.Errorf("invalid environment variable %q: expected KEY=VALUE", kv)
+		}
+		set(key, value, "--env")
+	}
+	return resolved, conflicts, nil
+}
+
+// EnvConflicts returns the environment variables of Env which are overridden by the variables set by the CLI for
+// the app, such as the ports of the sidecar. It is meant to be called once the config is validated.
+func (config *RunConfig) EnvConflicts() []EnvConflict {
+	var conflicts []EnvConflict
+	for _, kv := range config.sidecarEnv() {
+		key, value, _ := strings.Cut(kv, "=")
+		if _, ok := config.Env[key]; ok {
+			conflicts = append(conflicts, EnvConflict{Key: key, Value: value, Source: "the Dapr sidecar"})
+		}
+	}
+	return conflicts
+}
+
+// sidecarEnv returns the environment variables set by the CLI for the app, from the env tags of RunConfig.
+func (config *RunConfig) sidecarEnv() []string {
+	env := []string{}
+	schema := reflect.ValueOf(*config)
+	for i := 0; i < schema.NumField(); i++ {
+		valueField := schema.Field(i).Interface()
+		typeField := schema.Type().Field(i)
+		key := typeField.Tag.Get("env")
+		if len(key) == 0 {
+			continue
+		}
+		if value, ok := valueField.(int); ok && value <= 0 {
+			// ignore unset numeric variables.
+			continue
+		}
+
+		value := fmt.Sprintf("%v", reflect.ValueOf(valueField))
+		env = append(env, fmt.Sprintf("%s=%v", key, value))
+	}
+	return env
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
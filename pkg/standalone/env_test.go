@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	writeEnvFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("definitions", func(t *testing.T) {
+		path := writeEnvFile(t, `# the database
+DB_HOST=localhost
+export DB_PORT = 5432
+GREETING="hello \"world\"\n" # quoted
+LITERAL='a \n b'
+URL=http://host/#anchor
+NAME=orders # a comment
+EMPTY=
+`)
+		env, err := ParseEnvFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"DB_HOST":  "localhost",
+			"DB_PORT":  "5432",
+			"GREETING": "hello \"world\"\n",
+			"LITERAL":  `a \n b`,
+			"URL":      "http://host/#anchor",
+			"NAME":     "orders",
+			"EMPTY":    "",
+		}, env)
+	})
+
+	for name, content := range map[string]string{
+		"missing equal sign": "DB_HOST\n",
+		"unterminated quote": "DB_HOST=\"localhost\n",
+		"text after quotes":  "DB_HOST='local' host\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseEnvFile(writeEnvFile(t, content))
+			assert.ErrorContains(t, err, ":1:")
+		})
+	}
+}
+
+func TestResolveAppEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("DB_HOST=db\nLOG_LEVEL=info\n"), 0o600))
+
+	env, conflicts, err := ResolveAppEnv(path, []string{"LOG_LEVEL=debug", "MODE=a=b", "MODE=c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_HOST": "db", "LOG_LEVEL": "debug", "MODE": "c"}, env)
+	assert.Equal(t, []EnvConflict{
+		{Key: "LOG_LEVEL", Value: "debug", Source: "--env"},
+		{Key: "MODE", Value: "c", Source: "--env"},
+	}, conflicts)
+
+	_, _, err = ResolveAppEnv("", []string{"=value"})
+	assert.ErrorContains(t, err, "expected KEY=VALUE")
+}
+
+func TestGetEnvSidecarWins(t *testing.T) {
+	config := &RunConfig{
+		AppID:           "orders",
+		HTTPPort:        3500,
+		SharedRunConfig: SharedRunConfig{Env: map[string]string{"DAPR_HTTP_PORT": "80", "DB_HOST": "db"}},
+	}
+	env := config.getEnv()
+	assert.Equal(t, "DB_HOST=db", env[1])
+	assert.Contains(t, env[2:], "DAPR_HTTP_PORT=3500")
+	assert.Equal(t, []EnvConflict{{Key: "DAPR_HTTP_PORT", Value: "3500", Source: "the Dapr sidecar"}}, config.EnvConflicts())
+}
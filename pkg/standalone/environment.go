@@ -0,0 +1,303 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/pkg/age"
+	"github.com/dapr/cli/utils"
+)
+
+// Verdicts of the local environment.
+const (
+	EnvironmentHealthy  = "healthy"
+	EnvironmentDegraded = "degraded"
+)
+
+// Statuses of the pieces of the local environment, along with RuntimeInstalled, RuntimeNotInstalled and
+// RuntimeNotExecutable for the binaries.
+const (
+	ContainerRunning     = "running"
+	ContainerUnreachable = "unreachable"
+	ContainerUnknown     = "unknown"
+)
+
+// environmentPortProbeTimeout is how long a published port of a container has to accept a connection.
+const environmentPortProbeTimeout = time.Second
+
+// EnvironmentComponent is a piece of the local environment set up by init: a binary or a container.
+type EnvironmentComponent struct {
+	Name string `json:"name"`
+	// Kind is binary or container.
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	// Version is the version of a binary, or the image of a container.
+	Version string   `json:"version,omitempty"`
+	Uptime  string   `json:"uptime,omitempty"`
+	Ports   []string `json:"ports,omitempty"`
+	// Hardening is the hardening of the Redis container, see redisHardeningLabel.
+	Hardening string `json:"hardening,omitempty"`
+	// Drift describes how the image of a container differs from the one recorded in the install manifest.
+	Drift string `json:"drift,omitempty"`
+	Error string `json:"error,omitempty"`
+	// imageID is the ID of the image of a container.
+	imageID string
+}
+
+// Healthy reports whether the component is installed and, for a container, running with its ports reachable.
+func (c EnvironmentComponent) Healthy() bool {
+	return c.Status == RuntimeInstalled || c.Status == ContainerRunning
+}
+
+// EnvironmentStatus is the status of the local environment, as reported by GetEnvironmentStatus.
+type EnvironmentStatus struct {
+	Verdict    string `json:"verdict"`
+	InstallDir string `json:"installDir"`
+	// Platform is the GOOS/GOARCH platform the environment was installed for.
+	Platform string `json:"platform"`
+	// WSL is set if the environment is in a WSL distro.
+	WSL        bool                   `json:"wsl"`
+	SlimMode   bool                   `json:"slimMode"`
+	Components []EnvironmentComponent `json:"components"`
+	// MTLS are the mTLS credentials generated for the sidecars, if any.
+	MTLS *MTLSCredentials `json:"mtls,omitempty"`
+	// ComponentsProvider is the provider of the state store and pubsub components set up by init.
+	ComponentsProvider string `json:"componentsProvider"`
+}
+
+// GetEnvironmentStatus reports the status of the runtime binary and of the placement, Redis and Zipkin containers
+// set up by init, or of the placement binary and the services run by init --init-system in slim mode, as recorded in
+// the install manifest. The pieces which are missing are reported as not installed, and make the environment
+// degraded. The containers running another image than the one recorded have their Drift set, which doesn't degrade
+// it. daprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func GetEnvironmentStatus(daprRuntimePath, dockerNetwork, containerRuntime string) (*EnvironmentStatus, error) {
+	installDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	status := &EnvironmentStatus{InstallDir: installDir, Platform: hostPlatform(), WSL: IsWSL(), ComponentsProvider: ComponentsProviderRedis}
+	status.MTLS, _ = ReadMTLSCredentials(daprRuntimePath)
+	runtimeInfo := GetRuntimeInfo(daprRuntimePath)
+	status.Components = append(status.Components, EnvironmentComponent{
+		Name:    daprRuntimeFilePrefix,
+		Kind:    "binary",
+		Status:  runtimeInfo.Status,
+		Version: runtimeInfo.Version,
+		Error:   runtimeInfo.Error,
+	})
+	// The dashboard is optional, it is only reported when init installed it.
+	if dashboard, ok := dashboardComponent(installDir, daprRuntimePath); ok {
+		status.Components = append(status.Components, dashboard)
+	}
+
+	placementBinary := binaryFilePathWithDir(getDaprBinPath(installDir), placementServiceFilePrefix)
+	manifest, _ := ReadInstallManifest(installDir)
+	var containerNames []string
+	recorded := map[string]ManifestContainer{}
+	if manifest != nil {
+		status.SlimMode = manifest.SlimMode
+		status.ComponentsProvider = manifest.componentsProvider()
+		if manifest.Platform != "" {
+			status.Platform = manifest.Platform
+		}
+		if dockerNetwork == "" {
+			dockerNetwork = manifest.DockerNetwork
+		}
+		if strings.TrimSpace(containerRuntime) == "" {
+			containerRuntime = manifest.ContainerRuntime
+		}
+		for _, c := range manifest.Containers {
+			containerNames = append(containerNames, c.Name)
+			recorded[c.Name] = c
+		}
+	} else if _, statErr := os.Stat(placementBinary); statErr == nil {
+		status.SlimMode = true
+	}
+	if len(containerNames) == 0 {
+		for _, name := range []string{DaprPlacementContainerName, DaprRedisContainerName, DaprZipkinContainerName} {
+			containerNames = append(containerNames, profileContainerName(name, dockerNetwork))
+		}
+	}
+
+	if status.SlimMode {
+		placement := EnvironmentComponent{Name: placementServiceFilePrefix, Kind: "binary", Status: RuntimeNotInstalled}
+		if _, statErr := os.Stat(placementBinary); statErr == nil {
+			placement.Status = RuntimeInstalled
+			placement.Version = runtimeInfo.Version
+		}
+		status.Components = append(status.Components, placement)
+		if manifest != nil {
+			for _, s := range manifest.Services {
+				service := EnvironmentComponent{Name: s.serviceName(), Kind: "service", Version: manifest.InitSystem}
+				if service.Status, err = serviceState(manifest, s); err != nil {
+					service.Status = ContainerUnknown
+					service.Error = err.Error()
+				}
+				status.Components = append(status.Components, service)
+			}
+		}
+	} else {
+		runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(containerRuntime))
+		available := utils.IsContainerRuntimeInstalled(containerRuntime)
+		for _, containerName := range containerNames {
+			if !available {
+				status.Components = append(status.Components, EnvironmentComponent{
+					Name:   containerName,
+					Kind:   "container",
+					Status: ContainerUnknown,
+					Error:  fmt.Sprintf("could not connect to %s", runtimeCmd),
+				})
+				continue
+			}
+			c := inspectEnvironmentContainer(containerName, runtimeCmd)
+			if r, ok := recorded[containerName]; ok {
+				c.Drift = r.drift(c.imageID)
+			}
+			status.Components = append(status.Components, c)
+		}
+	}
+
+	status.Verdict = EnvironmentHealthy
+	for _, c := range status.Components {
+		if !c.Healthy() {
+			status.Verdict = EnvironmentDegraded
+		}
+	}
+	return status, nil
+}
+
+// containerInspect holds the fields of the output of the inspect command of the container runtime used by status.
+type containerInspect struct {
+	// Image is the ID of the image of the container.
+	Image string `json:"Image"`
+	State struct {
+		Status    string    `json:"Status"`
+		Running   bool      `json:"Running"`
+		StartedAt time.Time `json:"StartedAt"`
+	} `json:"State"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// errContainerNotFound is returned by parseContainerInspect when the container runtime found no container.
+var errContainerNotFound = errors.New("no such container")
+
+func parseContainerInspect(out []byte) (*containerInspect, error) {
+	var containers []containerInspect
+	if err := json.Unmarshal(out, &containers); err != nil {
+		return nil, fmt.Errorf("error parsing the container details: %w", err)
+	}
+	if len(containers) == 0 {
+		return nil, errContainerNotFound
+	}
+	return &containers[0], nil
+}
+
+// publishedPorts returns the host addresses the ports of the container are published on, as host:port.
+func (c *containerInspect) publishedPorts() []string {
+	var ports []string
+	for _, bindings := range c.NetworkSettings.Ports {
+		for _, b := range bindings {
+			host := b.HostIP
+			if host == "" || host == "0.0.0.0" || host == "::" {
+				host = "localhost"
+			}
+			ports = append(ports, net.JoinHostPort(host, b.HostPort))
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// hostPorts returns the distinct host ports the ports of the container are published on.
+func (c *containerInspect) hostPorts() []int {
+	seen := map[int]bool{}
+	var ports []int
+	for _, bindings := range c.NetworkSettings.Ports {
+		for _, b := range bindings {
+			if port, err := strconv.Atoi(b.HostPort); err == nil && !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// inspectEnvironmentContainer reports the state of containerName and probes its published ports.
+func inspectEnvironmentContainer(containerName, runtimeCmd string) EnvironmentComponent {
+	c := EnvironmentComponent{Name: containerName, Kind: "container", Status: RuntimeNotInstalled}
+	if exists, err := confirmContainerIsRunningOrExists(containerName, false, runtimeCmd); err != nil {
+		c.Status = ContainerUnknown
+		c.Error = err.Error()
+		return c
+	} else if !exists {
+		return c
+	}
+	out, err := runCmd(runtimeCmd, "inspect", containerName)
+	if err != nil {
+		c.Status = ContainerUnknown
+		c.Error = err.Error()
+		return c
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if err != nil {
+		c.Status = ContainerUnknown
+		c.Error = err.Error()
+		return c
+	}
+	c.Status = details.State.Status
+	c.Version = details.Config.Image
+	c.Ports = details.publishedPorts()
+	c.Hardening = details.Config.Labels[redisHardeningLabel]
+	c.imageID = details.Image
+	if !details.State.Running {
+		return c
+	}
+	c.Status = ContainerRunning
+	if !details.State.StartedAt.IsZero() {
+		c.Uptime = age.GetAge(details.State.StartedAt)
+	}
+	for _, port := range c.Ports {
+		if host := loopbackHost(runtimeCmd); host != daprDefaultHost {
+			port = strings.Replace(port, daprDefaultHost+":", host+":", 1)
+		}
+		conn, dialErr := net.DialTimeout("tcp", port, environmentPortProbeTimeout)
+		if dialErr != nil {
+			c.Status = ContainerUnreachable
+			c.Error = dialErr.Error()
+			break
+		}
+		conn.Close()
+	}
+	return c
+}
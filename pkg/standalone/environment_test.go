@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContainerInspect(t *testing.T) {
+	out := `[{
+		"State": {"Status": "running", "Running": true, "StartedAt": "2023-06-01T10:00:00.123456789Z"},
+		"Config": {"Image": "ghcr.io/dapr/placement:1.11.0", "Labels": {"io.dapr.cli.hardening": "1"}},
+		"NetworkSettings": {"Ports": {
+			"50005/tcp": [{"HostIp": "0.0.0.0", "HostPort": "50005"}, {"HostIp": "::", "HostPort": "50005"}],
+			"8080/tcp": [{"HostIp": "127.0.0.1", "HostPort": "8080"}],
+			"9090/tcp": null
+		}}
+	}]`
+
+	details, err := parseContainerInspect([]byte(out))
+	require.NoError(t, err)
+	assert.True(t, details.State.Running)
+	assert.Equal(t, time.Date(2023, 6, 1, 10, 0, 0, 123456789, time.UTC), details.State.StartedAt)
+	assert.Equal(t, "ghcr.io/dapr/placement:1.11.0", details.Config.Image)
+	assert.Equal(t, redisHardeningVersion, details.Config.Labels[redisHardeningLabel])
+	assert.Equal(t, []string{"127.0.0.1:8080", "localhost:50005", "localhost:50005"}, details.publishedPorts())
+
+	_, err = parseContainerInspect([]byte("[]"))
+	assert.ErrorIs(t, err, errContainerNotFound)
+
+	_, err = parseContainerInspect([]byte("Error: no such object"))
+	assert.Error(t, err)
+}
+
+func TestGetEnvironmentStatusSlim(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	binDir := getDaprBinPath(daprDir)
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	daprd := filepath.Join(binDir, daprRuntimeFilePrefix)
+	require.NoError(t, os.WriteFile(daprd, []byte("#!/bin/sh\necho 1.11.0\n"), 0o755))
+	require.NoError(t, writeInstallManifest(daprDir, &InstallManifest{RuntimeVersion: "1.11.0", RuntimeBinary: daprd, SlimMode: true}))
+
+	status, err := GetEnvironmentStatus(runtimePath, "", "docker")
+	require.NoError(t, err)
+	assert.True(t, status.SlimMode)
+	assert.Equal(t, EnvironmentDegraded, status.Verdict)
+	assert.Equal(t, []EnvironmentComponent{
+		{Name: daprRuntimeFilePrefix, Kind: "binary", Status: RuntimeInstalled, Version: "1.11.0"},
+		{Name: placementServiceFilePrefix, Kind: "binary", Status: RuntimeNotInstalled},
+	}, status.Components)
+
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, placementServiceFilePrefix), []byte("#!/bin/sh\n"), 0o755))
+	status, err = GetEnvironmentStatus(runtimePath, "", "docker")
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentHealthy, status.Verdict)
+	assert.Equal(t, RuntimeInstalled, status.Components[1].Status)
+}
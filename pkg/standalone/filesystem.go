@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// fileSystem is the file system the install extracts the archives to and moves the binaries in with. It is the one
+// of the OS, but in the tests, which run the install against an in-memory one failing where they need it to.
+type fileSystem interface {
+	Open(name string) (file, error)
+	OpenFile(name string, flag int, perm os.FileMode) (file, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	RemoveAll(path string) error
+	Chmod(name string, mode os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Stat(name string) (os.FileInfo, error)
+}
+
+// file is a file opened with a fileSystem.
+type file interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// fsys is the file system of the install.
+var fsys fileSystem = osFileSystem{}
+
+// osFileSystem is the fileSystem of the OS.
+type osFileSystem struct{}
+
+func (osFileSystem) Open(name string) (file, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (osFileSystem) OpenFile(name string, flag int, perm os.FileMode) (file, error) {
+	f, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (osFileSystem) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (osFileSystem) Remove(name string) error { return os.Remove(name) }
+
+func (osFileSystem) RemoveAll(path string) error { return os.RemoveAll(path) }
+
+func (osFileSystem) Chmod(name string, mode os.FileMode) error { return os.Chmod(name, mode) }
+
+func (osFileSystem) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (osFileSystem) Stat(name string) (os.FileInfo, error) { return os.Stat(name) }
+
+// moveFile moves the file src to dst, replacing it if it exists. A file can't be renamed to another file system,
+// where it is copied instead, with the permissions of src, before src is removed.
+func moveFile(src, dst string) error {
+	err := fsys.Rename(src, dst)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+	in, err := fsys.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := fsys.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("error copying %s to %s: %w", src, dst, err)
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	// The permissions of a file which existed are kept by OpenFile.
+	if err = fsys.Chmod(dst, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	in.Close()
+	if err = fsys.Remove(src); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
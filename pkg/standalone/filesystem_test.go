@@ -0,0 +1,514 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memFS is an in-memory fileSystem, which fails the operations fail returns an error for. It counts the bytes read
+// from its files in read.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string]*memFileData
+	dirs  map[string]bool
+	fail  func(op, name string) error
+	read  atomic.Int64
+}
+
+type memFileData struct {
+	data []byte
+	mode os.FileMode
+}
+
+func newMemFS(t testing.TB) *memFS {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the paths are made absolute and long on Windows, and the install dir is added to the user PATH")
+	}
+	m := &memFS{files: map[string]*memFileData{}, dirs: map[string]bool{}}
+	prev := fsys
+	fsys = m
+	t.Cleanup(func() { fsys = prev })
+	return m
+}
+
+func (m *memFS) err(op, name string) error {
+	if m.fail == nil {
+		return nil
+	}
+	if err := m.fail(op, filepath.Clean(name)); err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return nil
+}
+
+func (m *memFS) writeFile(name string, data []byte, mode os.FileMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filepath.Clean(name)] = &memFileData{data: data, mode: mode}
+}
+
+func (m *memFS) readFile(name string) ([]byte, os.FileMode, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, 0, false
+	}
+	return f.data, f.mode, true
+}
+
+func (m *memFS) Open(name string) (file, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *memFS) OpenFile(name string, flag int, perm os.FileMode) (file, error) {
+	if err := m.err("open", name); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	f, ok := m.files[name]
+	if !ok {
+		if flag&os.O_CREATE == 0 {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		if !m.dirs[filepath.Dir(name)] {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		f = &memFileData{mode: perm}
+		m.files[name] = f
+	}
+	h := &memHandle{fs: m, name: name, mode: f.mode}
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		h.w = &bytes.Buffer{}
+		if flag&os.O_TRUNC == 0 {
+			h.w.Write(f.data)
+		}
+	} else {
+		h.r = bytes.NewReader(f.data)
+	}
+	return h, nil
+}
+
+func (m *memFS) Rename(oldpath, newpath string) error {
+	if err := m.err("rename", oldpath); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	if m.dirs[oldpath] {
+		for name, f := range m.files {
+			if strings.HasPrefix(name, oldpath+string(os.PathSeparator)) {
+				delete(m.files, name)
+				m.files[newpath+strings.TrimPrefix(name, oldpath)] = f
+			}
+		}
+		delete(m.dirs, oldpath)
+		m.dirs[newpath] = true
+		return nil
+	}
+	f, ok := m.files[oldpath]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldpath)
+	m.files[newpath] = f
+	return nil
+}
+
+func (m *memFS) Remove(name string) error {
+	if err := m.err("remove", name); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memFS) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path = filepath.Clean(path)
+	for name := range m.files {
+		if name == path || strings.HasPrefix(name, path+string(os.PathSeparator)) {
+			delete(m.files, name)
+		}
+	}
+	for name := range m.dirs {
+		if name == path || strings.HasPrefix(name, path+string(os.PathSeparator)) {
+			delete(m.dirs, name)
+		}
+	}
+	return nil
+}
+
+func (m *memFS) Chmod(name string, mode os.FileMode) error {
+	if err := m.err("chmod", name); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.files[filepath.Clean(name)]; ok {
+		f.mode = mode
+	}
+	return nil
+}
+
+func (m *memFS) MkdirAll(path string, perm os.FileMode) error {
+	if err := m.err("mkdir", path); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for p := filepath.Clean(path); !m.dirs[p]; p = filepath.Dir(p) {
+		m.dirs[p] = true
+	}
+	return nil
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if f, ok := m.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(f.data)), mode: f.mode}, nil
+	}
+	if m.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), mode: fs.ModeDir | 0o755}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// memHandle is a file opened with a memFS, which writes its content on Close.
+type memHandle struct {
+	fs   *memFS
+	name string
+	mode os.FileMode
+	r    *bytes.Reader
+	w    *bytes.Buffer
+}
+
+func (h *memHandle) Read(p []byte) (int, error) {
+	if h.r == nil {
+		return 0, errors.New("file not opened for reading")
+	}
+	n, err := h.r.Read(p)
+	h.fs.read.Add(int64(n))
+	return n, err
+}
+
+func (h *memHandle) ReadAt(p []byte, off int64) (int, error) {
+	if h.r == nil {
+		return 0, errors.New("file not opened for reading")
+	}
+	n, err := h.r.ReadAt(p, off)
+	h.fs.read.Add(int64(n))
+	return n, err
+}
+
+func (h *memHandle) Write(p []byte) (int, error) {
+	if h.w == nil {
+		return 0, errors.New("file not opened for writing")
+	}
+	return h.w.Write(p)
+}
+
+func (h *memHandle) Close() error {
+	if h.w != nil {
+		h.fs.mu.Lock()
+		if f, ok := h.fs.files[h.name]; ok {
+			f.data = h.w.Bytes()
+		}
+		h.fs.mu.Unlock()
+		h.w = nil
+	}
+	return nil
+}
+
+func (h *memHandle) Stat() (os.FileInfo, error) {
+	size := int64(0)
+	if h.r != nil {
+		size = h.r.Size()
+	} else if h.w != nil {
+		size = int64(h.w.Len())
+	}
+	return memFileInfo{name: filepath.Base(h.name), size: size, mode: h.mode}, nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi memFileInfo) Sys() interface{}   { return nil }
+
+// releaseArchive returns an archive of the release of binaryFilePrefix for this platform, with the binary content.
+func releaseArchive(t *testing.T, binaryFilePrefix, content string) []byte {
+	var buf bytes.Buffer
+	if archiveExt() == "zip" {
+		zw := zip.NewWriter(&buf)
+		w, err := zw.Create(binaryFilePrefix + ".exe")
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		return buf.Bytes()
+	}
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: binaryFilePrefix, Mode: 0o755, Size: int64(len(content))}))
+	_, err := tw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func memFiles(m *memFS) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestExtractFile(t *testing.T) {
+	m := newMemFS(t)
+	dir := filepath.Join(string(os.PathSeparator)+"home", "me", ".dapr", "bin")
+	archive := filepath.Join(dir, binaryName(daprRuntimeFilePrefix))
+	require.NoError(t, m.MkdirAll(dir, 0o755))
+	m.writeFile(archive, releaseArchive(t, daprRuntimeFilePrefix, "new"), 0o644)
+
+	// The binary of a previous install is replaced, even if it is longer.
+	binary := binaryFilePathWithDir(dir, daprRuntimeFilePrefix)
+	m.writeFile(binary, []byte("previous binary"), 0o755)
+	extracted, err := extractFile(archive, dir, daprRuntimeFilePrefix)
+	require.NoError(t, err)
+	assert.Equal(t, binary, extracted)
+	b, _, _ := m.readFile(binary)
+	assert.Equal(t, "new", string(b))
+
+	t.Run("permission denied", func(t *testing.T) {
+		m.fail = func(op, name string) error {
+			if op == "open" && name == binary {
+				return fs.ErrPermission
+			}
+			return nil
+		}
+		defer func() { m.fail = nil }()
+		_, err := extractFile(archive, dir, daprRuntimeFilePrefix)
+		assert.ErrorIs(t, err, fs.ErrPermission)
+		assert.Contains(t, err.Error(), "error extracting daprd binary")
+	})
+
+	t.Run("missing archive", func(t *testing.T) {
+		_, err := extractFile(filepath.Join(dir, "missing.tar.gz"), dir, daprRuntimeFilePrefix)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestMoveFileToPath(t *testing.T) {
+	root := string(os.PathSeparator) + "tmp"
+	src := filepath.Join(root, "extract", "daprd")
+	dest := filepath.Join(root, "bin", "daprd")
+	setup := func(t *testing.T) *memFS {
+		m := newMemFS(t)
+		require.NoError(t, m.MkdirAll(filepath.Dir(src), 0o755))
+		m.writeFile(src, []byte("new"), 0o755)
+		return m
+	}
+
+	t.Run("already exists", func(t *testing.T) {
+		m := setup(t)
+		require.NoError(t, m.MkdirAll(filepath.Dir(dest), 0o755))
+		m.writeFile(dest, []byte("previous binary"), 0o700)
+		moved, pathUpdated, err := moveFileToPath(src, filepath.Dir(dest))
+		require.NoError(t, err)
+		assert.False(t, pathUpdated)
+		assert.Equal(t, dest, moved)
+		b, mode, _ := m.readFile(dest)
+		assert.Equal(t, "new", string(b))
+		assert.Equal(t, os.FileMode(0o755), mode)
+		assert.Equal(t, []string{dest}, memFiles(m))
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		m := setup(t)
+		moved, _, err := moveFileToPath(src, filepath.Dir(src))
+		require.NoError(t, err)
+		assert.Equal(t, src, moved)
+		b, _, _ := m.readFile(src)
+		assert.Equal(t, "new", string(b))
+	})
+
+	t.Run("cross device", func(t *testing.T) {
+		m := setup(t)
+		m.fail = func(op, name string) error {
+			if op == "rename" {
+				return syscall.EXDEV
+			}
+			return nil
+		}
+		moved, _, err := moveFileToPath(src, filepath.Dir(dest))
+		require.NoError(t, err)
+		b, mode, ok := m.readFile(moved)
+		require.True(t, ok)
+		assert.Equal(t, "new", string(b))
+		assert.Equal(t, os.FileMode(0o755), mode)
+		assert.Equal(t, []string{dest}, memFiles(m))
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		m := setup(t)
+		m.fail = func(op, name string) error {
+			if op == "rename" {
+				return fs.ErrPermission
+			}
+			return nil
+		}
+		_, _, err := moveFileToPath(src, filepath.Dir(dest))
+		assert.ErrorIs(t, err, fs.ErrPermission)
+		assert.Contains(t, err.Error(), "please run with sudo")
+		assert.Equal(t, []string{src}, memFiles(m))
+	})
+
+	t.Run("permission denied across devices", func(t *testing.T) {
+		m := setup(t)
+		m.fail = func(op, name string) error {
+			switch {
+			case op == "rename":
+				return syscall.EXDEV
+			case op == "open" && name == dest:
+				return fs.ErrPermission
+			}
+			return nil
+		}
+		_, _, err := moveFileToPath(src, filepath.Dir(dest))
+		assert.ErrorIs(t, err, fs.ErrPermission)
+		assert.Contains(t, err.Error(), "please run with sudo")
+		// The source is kept when it can't be copied.
+		assert.Equal(t, []string{src}, memFiles(m))
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		newMemFS(t)
+		_, _, err := moveFileToPath(src, filepath.Dir(dest))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestInstallBinary(t *testing.T) {
+	bundle := filepath.Join(string(os.PathSeparator)+"bundle", "daprbundle")
+	installDir := filepath.Join(string(os.PathSeparator)+"home", "me", ".dapr")
+	binDir := getDaprBinPath(installDir)
+	subDir := "dist"
+	setAirGapInit(bundle)
+	t.Cleanup(func() { setAirGapInit("") })
+	info := initInfo{fromDir: bundle, installDir: installDir, bundleDet: &bundleDetails{BinarySubDir: &subDir}}
+	// The binary dir isn't in the PATH of the tests.
+	t.Setenv("PATH", "")
+	setup := func(t *testing.T) *memFS {
+		m := newMemFS(t)
+		require.NoError(t, m.MkdirAll(binDir, 0o755))
+		require.NoError(t, m.MkdirAll(filepath.Join(bundle, subDir), 0o755))
+		m.writeFile(filepath.Join(bundle, subDir, binaryName(daprRuntimeFilePrefix)), releaseArchive(t, daprRuntimeFilePrefix, "new"), 0o644)
+		return m
+	}
+	archive := filepath.Join(bundle, subDir, binaryName(daprRuntimeFilePrefix))
+	binary := binaryFilePathWithDir(binDir, daprRuntimeFilePrefix)
+
+	t.Run("already exists", func(t *testing.T) {
+		m := setup(t)
+		m.writeFile(binary, []byte("previous binary"), 0o755)
+		info := info
+		info.artifacts = &initArtifacts{}
+		require.NoError(t, installBinary(context.Background(), "1.11.0", daprRuntimeFilePrefix, "", info))
+		b, mode, _ := m.readFile(binary)
+		assert.Equal(t, "new", string(b))
+		assert.Equal(t, os.FileMode(0o777), mode)
+		// The archive of the bundle is kept.
+		assert.Equal(t, []string{archive, binary}, memFiles(m))
+		// The binary it overwrote isn't removed by a rollback.
+		assert.Empty(t, info.artifacts.artifacts)
+	})
+
+	t.Run("new", func(t *testing.T) {
+		setup(t)
+		info := info
+		info.artifacts = &initArtifacts{}
+		require.NoError(t, installBinary(context.Background(), "1.11.0", daprRuntimeFilePrefix, "", info))
+		assert.Equal(t, []InitArtifact{{Kind: ArtifactFile, Name: binary}}, info.artifacts.artifacts)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		m := setup(t)
+		m.fail = func(op, name string) error {
+			if op == "open" && name == binary {
+				return fs.ErrPermission
+			}
+			return nil
+		}
+		err := installBinary(context.Background(), "1.11.0", daprRuntimeFilePrefix, "", info)
+		assert.ErrorIs(t, err, fs.ErrPermission)
+	})
+
+	t.Run("not executable", func(t *testing.T) {
+		m := setup(t)
+		m.fail = func(op, name string) error {
+			if op == "chmod" {
+				return fs.ErrPermission
+			}
+			return nil
+		}
+		err := installBinary(context.Background(), "1.11.0", daprRuntimeFilePrefix, "", info)
+		assert.ErrorIs(t, err, fs.ErrPermission)
+		assert.Contains(t, err.Error(), "error making daprd binary executable")
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/pkg/metadata"
+)
+
+// DefaultHealthProbeTimeout is how long the sidecar of an instance has to answer a probe.
+const DefaultHealthProbeTimeout = 2 * time.Second
+
+// Health statuses of an instance.
+const (
+	InstanceHealthy     = "healthy"
+	InstanceUnhealthy   = "unhealthy"
+	InstanceUnreachable = "unreachable"
+)
+
+// InstanceHealth is the health of the sidecar of an instance, as probed on its health and metadata endpoints.
+type InstanceHealth struct {
+	AppID  string `json:"appId"  yaml:"appId"`
+	Status string `json:"status" yaml:"status"`
+	Uptime string `json:"uptime" yaml:"uptime"`
+	// Components is the number of components loaded by the sidecar, -1 if it couldn't be queried.
+	Components int    `json:"components"      yaml:"components"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ProbeInstance probes the sidecar of instance, waiting up to timeout for each endpoint.
+func ProbeInstance(instance ListOutput, timeout time.Duration) InstanceHealth {
+	health := InstanceHealth{AppID: instance.AppID, Status: InstanceHealthy, Uptime: instance.Age, Components: -1}
+	if err := metadata.Health(instance.HTTPPort, instance.AppID, instance.UnixDomainSocket, timeout); err != nil {
+		health.Status = InstanceUnreachable
+		if errors.Is(err, metadata.ErrUnhealthy) {
+			health.Status = InstanceUnhealthy
+		}
+		health.Error = err.Error()
+		if health.Status == InstanceUnreachable {
+			return health
+		}
+	}
+	m, err := metadata.GetWithTimeout(instance.HTTPPort, instance.AppID, instance.UnixDomainSocket, timeout)
+	if err != nil {
+		if health.Error == "" {
+			health.Error = err.Error()
+		}
+		return health
+	}
+	health.Components = len(m.RegisteredComponents)
+	return health
+}
+
+// ProbeInstances probes the sidecars of instances in parallel, so that an unresponsive sidecar only delays the
+// results by timeout. The results are in the order of instances.
+func ProbeInstances(instances []ListOutput, timeout time.Duration) []InstanceHealth {
+	results := make([]InstanceHealth, len(instances))
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = ProbeInstance(instances[i], timeout)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeSidecar(t *testing.T, healthStatus int, delay time.Duration) int {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		switch r.URL.Path {
+		case "/v1.0/healthz":
+			w.WriteHeader(healthStatus)
+		case "/v1.0/metadata":
+			w.Write([]byte(`{"id": "app", "components": [{"name": "statestore", "type": "state.redis", "version": "v1"}, {"name": "pubsub", "type": "pubsub.redis", "version": "v1"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return p
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbeInstances(t *testing.T) {
+	instances := []ListOutput{
+		{AppID: "healthy", HTTPPort: fakeSidecar(t, http.StatusNoContent, 0), Age: "5m"},
+		{AppID: "unhealthy", HTTPPort: fakeSidecar(t, http.StatusInternalServerError, 0)},
+		{AppID: "unreachable", HTTPPort: freePort(t)},
+		{AppID: "stuck", HTTPPort: fakeSidecar(t, http.StatusNoContent, time.Second)},
+	}
+
+	start := time.Now()
+	results := ProbeInstances(instances, 300*time.Millisecond)
+	assert.Less(t, time.Since(start), 900*time.Millisecond, "the instances are probed in parallel")
+	require.Len(t, results, 4)
+
+	assert.Equal(t, InstanceHealth{AppID: "healthy", Status: InstanceHealthy, Uptime: "5m", Components: 2}, results[0])
+
+	assert.Equal(t, InstanceUnhealthy, results[1].Status)
+	assert.Equal(t, 2, results[1].Components)
+	assert.Contains(t, results[1].Error, "500")
+
+	assert.Equal(t, InstanceUnreachable, results[2].Status)
+	assert.Equal(t, -1, results[2].Components)
+
+	assert.Equal(t, InstanceUnreachable, results[3].Status)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+)
+
+// ErrHomebrewManaged is returned when the runtime binaries were installed with a Homebrew formula, which upgrades and
+// removes them itself.
+var ErrHomebrewManaged = errors.New("is installed with Homebrew")
+
+// homebrewPrefixes returns the default prefixes of Homebrew for arch, in the order they are looked for: /opt/homebrew
+// on Apple Silicon, /usr/local on Intel, which Apple Silicon machines can also have for Homebrew under Rosetta.
+func homebrewPrefixes(arch string) []string {
+	if arch == "arm64" {
+		return []string{"/opt/homebrew", "/usr/local"}
+	}
+	return []string{"/usr/local"}
+}
+
+// homebrewPrefix returns the prefix of the Homebrew installation of this machine, from HOMEBREW_PREFIX, set by
+// `brew shellenv`, or its default location, or an empty string if there is none.
+func homebrewPrefix() string {
+	prefixes := homebrewPrefixes(hostArch())
+	if env := strings.TrimSpace(os.Getenv("HOMEBREW_PREFIX")); env != "" {
+		prefixes = append([]string{env}, prefixes...)
+	}
+	for _, prefix := range prefixes {
+		if info, err := os.Stat(path_filepath.Join(prefix, "bin", "brew")); err == nil && !info.IsDir() {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// cellarFormula returns the Homebrew formula the file at path belongs to, if it is in a Cellar, where Homebrew keeps
+// the files of the formulae it installs, linking them into its bin directory.
+func cellarFormula(path string) string {
+	parts := strings.Split(path_filepath.ToSlash(path), "/")
+	for i, part := range parts {
+		if strings.EqualFold(part, "Cellar") && i+1 < len(parts) && parts[i+1] != "" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// homebrewFormula returns the Homebrew formula which installed the file at path, following its symlinks, or an empty
+// string if it wasn't installed by Homebrew.
+func homebrewFormula(path string) string {
+	resolved, err := path_filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	return cellarFormula(resolved)
+}
+
+// runtimeHomebrewFormula returns the Homebrew formula which installed the runtime binaries in the bin dir of daprDir,
+// or an empty string if they weren't installed by Homebrew.
+func runtimeHomebrewFormula(daprDir string) string {
+	binDir := getDaprBinPath(daprDir)
+	if formula := homebrewFormula(binDir); formula != "" {
+		return formula
+	}
+	for _, binary := range []string{daprRuntimeFilePrefix, placementServiceFilePrefix} {
+		if formula := homebrewFormula(binaryFilePathWithDir(binDir, binary)); formula != "" {
+			return formula
+		}
+	}
+	return ""
+}
+
+// homebrewManagedError returns an ErrHomebrewManaged error for the runtime in daprDir installed with formula, with the
+// brew command doing the action instead.
+func homebrewManagedError(daprDir, formula, action string) error {
+	return fmt.Errorf("the runtime in %s %w, from the formula %s: %s it with `brew %s %s` so that the files Homebrew manages aren't changed behind its back", daprDir, ErrHomebrewManaged, formula, action, action, formula)
+}
+
+// homebrewBinDir returns the bin directory of Homebrew if it is writable by the current user, as Homebrew makes it,
+// or an empty string.
+func homebrewBinDir() string {
+	prefix := homebrewPrefix()
+	if prefix == "" {
+		return ""
+	}
+	dir := path_filepath.Join(prefix, "bin")
+	if !dirWritable(dir) {
+		return ""
+	}
+	return dir
+}
+
+// dirWritable reports whether the current user can create files in dir.
+func dirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".dapr-write-check-")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellarFormula(t *testing.T) {
+	assert.Equal(t, "dapr-cli", cellarFormula("/opt/homebrew/Cellar/dapr-cli/1.11.0/bin/dapr"))
+	assert.Equal(t, "daprd", cellarFormula("/usr/local/Cellar/daprd/1.11.0/bin/daprd"))
+	assert.Equal(t, "dapr-cli", cellarFormula("/home/linuxbrew/.linuxbrew/Cellar/dapr-cli/1.11.0/bin/dapr"))
+	assert.Empty(t, cellarFormula("/usr/local/bin/dapr"))
+	assert.Empty(t, cellarFormula("/opt/homebrew/Cellar/"))
+}
+
+func TestHomebrewPrefix(t *testing.T) {
+	assert.Equal(t, []string{"/opt/homebrew", "/usr/local"}, homebrewPrefixes("arm64"))
+	assert.Equal(t, []string{"/usr/local"}, homebrewPrefixes("amd64"))
+
+	prefix := t.TempDir()
+	t.Setenv("HOMEBREW_PREFIX", prefix)
+	if _, err := os.Stat("/usr/local/bin/brew"); err != nil {
+		assert.Empty(t, homebrewPrefix())
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(prefix, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(prefix, "bin", "brew"), nil, 0o755))
+	assert.Equal(t, prefix, homebrewPrefix())
+	assert.Equal(t, filepath.Join(prefix, "bin"), homebrewBinDir())
+}
+
+// homebrewRuntime links the bin dir of the install dir of runtimePath to the binaries of a daprd formula in a Cellar.
+func homebrewRuntime(t *testing.T, runtimePath string) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("Homebrew is not available on Windows")
+	}
+	cellarBin := filepath.Join(t.TempDir(), "Cellar", "daprd", "1.11.0", "bin")
+	require.NoError(t, os.MkdirAll(cellarBin, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(cellarBin, daprRuntimeFilePrefix), []byte("#!/bin/sh\necho 1.11.0\n"), 0o755))
+	binDir := getDaprBinPath(filepath.Join(runtimePath, DefaultDaprDirName))
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.Symlink(filepath.Join(cellarBin, daprRuntimeFilePrefix), filepath.Join(binDir, daprRuntimeFilePrefix)))
+}
+
+func TestRuntimeHomebrewFormula(t *testing.T) {
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	assert.Empty(t, runtimeHomebrewFormula(daprDir))
+
+	homebrewRuntime(t, runtimePath)
+	assert.Equal(t, "daprd", runtimeHomebrewFormula(daprDir))
+
+	_, err := Upgrade(context.Background(), UpgradeOptions{DaprInstallPath: runtimePath})
+	require.ErrorIs(t, err, ErrHomebrewManaged)
+	assert.Contains(t, err.Error(), "`brew upgrade daprd`")
+}
+
+func TestUninstallHomebrewRuntime(t *testing.T) {
+	runtimePath := t.TempDir()
+	homebrewRuntime(t, runtimePath)
+	t.Setenv("PATH", t.TempDir())
+
+	report, err := Uninstall(false, "", "docker", runtimePath, false)
+	require.NoError(t, err)
+	assert.Equal(t, "daprd", report.HomebrewFormula)
+	assert.FileExists(t, filepath.Join(getDaprBinPath(filepath.Join(runtimePath, DefaultDaprDirName)), daprRuntimeFilePrefix))
+}
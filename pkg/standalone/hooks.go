@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+// AllSteps is the step of the hooks which run around each step of the install.
+const AllSteps = "*"
+
+// HookPhase is when a hook runs, before or after its step.
+type HookPhase string
+
+const (
+	HookBefore HookPhase = "before"
+	HookAfter  HookPhase = "after"
+)
+
+// HookContext describes the step a hook runs around, and where the install puts its files and publishes its ports.
+type HookContext struct {
+	Step             string
+	Phase            HookPhase
+	InstallDir       string
+	BinDir           string
+	ComponentsDir    string
+	RuntimeVersion   string
+	DockerNetwork    string
+	ContainerRuntime string
+	SlimMode         bool
+	Ports            InstallPorts
+}
+
+// Hook is an action run before or after a step of the install, such as copying a file in the install dir once the
+// binaries are installed. The after hooks only run once their step succeeded.
+type Hook struct {
+	// Name identifies the hook in the errors and the warnings.
+	Name string
+	// Step is the name of the step the hook runs around, one of the Step constants, or AllSteps.
+	Step  string
+	Phase HookPhase
+	// BestEffort reports a failure of the hook as a warning of its step, instead of failing the install.
+	BestEffort bool
+	Run        func(ctx context.Context, hc HookContext) error
+}
+
+// ShellHook is a hook of the CLI config file, which runs Command with the shell of the platform. The context of the
+// step is in the DAPR_HOOK_* environment variables of the command, see HookContext.env.
+type ShellHook struct {
+	Name       string    `json:"name,omitempty"`
+	Step       string    `json:"step"`
+	Phase      HookPhase `json:"phase"`
+	Command    string    `json:"command"`
+	BestEffort bool      `json:"bestEffort,omitempty"`
+}
+
+// validateHook returns an error if the hook doesn't run around a step of the install.
+func validateHook(name, step string, phase HookPhase) error {
+	if phase != HookBefore && phase != HookAfter {
+		return fmt.Errorf("hook %s has phase %q, it must be %s or %s", name, phase, HookBefore, HookAfter)
+	}
+	if step == AllSteps {
+		return nil
+	}
+	for _, s := range initStepNames {
+		if s == step {
+			return nil
+		}
+	}
+	return fmt.Errorf("hook %s runs around step %q, which isn't one of: %s, or %s for all of them", name, step, strings.Join(initStepNames, ", "), AllSteps)
+}
+
+// hook returns the Hook running the command of h.
+func (h ShellHook) hook() Hook {
+	name := h.Name
+	if name == "" {
+		name = strconv.Quote(h.Command)
+	}
+	return Hook{Name: name, Step: h.Step, Phase: h.Phase, BestEffort: h.BestEffort, Run: func(ctx context.Context, hc HookContext) error {
+		var cmd *exec.Cmd
+		if runtime.GOOS == daprWindowsOS {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", h.Command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", h.Command)
+		}
+		cmd.Env = append(os.Environ(), hc.env()...)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			if output := strings.TrimSpace(out.String()); output != "" {
+				return fmt.Errorf("%w: %s", err, secret.Redact(output))
+			}
+			return err
+		}
+		return nil
+	}}
+}
+
+// shellHooks returns the hooks of the CLI config file in daprDir.
+func shellHooks(daprDir string) ([]Hook, error) {
+	config, err := readCLIConfig(daprDir)
+	if err != nil {
+		return nil, err
+	}
+	hooks := make([]Hook, 0, len(config.Hooks))
+	for i, h := range config.Hooks {
+		if strings.TrimSpace(h.Command) == "" {
+			return nil, fmt.Errorf("hook %d of CLI config file %s has no command", i+1, GetCLIConfigPath(daprDir))
+		}
+		hook := h.hook()
+		if err = validateHook(hook.Name, hook.Step, hook.Phase); err != nil {
+			return nil, fmt.Errorf("error in CLI config file %s: %w", GetCLIConfigPath(daprDir), err)
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// env returns the environment variables of the shell hooks with the context.
+func (hc HookContext) env() []string {
+	return []string{
+		"DAPR_HOOK_STEP=" + hc.Step,
+		"DAPR_HOOK_PHASE=" + string(hc.Phase),
+		"DAPR_HOOK_INSTALL_DIR=" + hc.InstallDir,
+		"DAPR_HOOK_BIN_DIR=" + hc.BinDir,
+		"DAPR_HOOK_COMPONENTS_DIR=" + hc.ComponentsDir,
+		"DAPR_HOOK_RUNTIME_VERSION=" + hc.RuntimeVersion,
+		"DAPR_HOOK_DOCKER_NETWORK=" + hc.DockerNetwork,
+		"DAPR_HOOK_CONTAINER_RUNTIME=" + hc.ContainerRuntime,
+		"DAPR_HOOK_SLIM_MODE=" + strconv.FormatBool(hc.SlimMode),
+		"DAPR_HOOK_PLACEMENT_PORT=" + strconv.Itoa(hc.Ports.Placement),
+		"DAPR_HOOK_REDIS_PORT=" + strconv.Itoa(hc.Ports.Redis),
+		"DAPR_HOOK_ZIPKIN_PORT=" + strconv.Itoa(hc.Ports.Zipkin),
+	}
+}
+
+// runHooks runs the hooks of info which run in phase around step, in order. A failing hook fails the step, unless
+// it is best effort, in which case it is a warning of the step.
+func runHooks(ctx context.Context, phase HookPhase, step string, info initInfo) error {
+	hc := HookContext{
+		Step:             step,
+		Phase:            phase,
+		InstallDir:       info.installDir,
+		BinDir:           getDaprBinPath(info.installDir),
+		ComponentsDir:    info.componentsDir,
+		RuntimeVersion:   info.runtimeVersion,
+		DockerNetwork:    info.dockerNetwork,
+		ContainerRuntime: info.containerRuntime,
+		SlimMode:         info.slimMode,
+		Ports:            info.ports,
+	}
+	for _, h := range info.hooks {
+		if h.Phase != phase || (h.Step != step && h.Step != AllSteps) {
+			continue
+		}
+		info.progress("running %s hook %s", phase, h.Name)
+		if err := h.Run(ctx, hc); err != nil {
+			if h.BestEffort {
+				info.warn("%s hook %s failed: %s", phase, h.Name, err)
+				continue
+			}
+			return fmt.Errorf("%s hook %s of %s failed: %w", phase, h.Name, step, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestRunInitStepsHooks(t *testing.T) {
+	var calls []string
+	hook := func(name, step string, phase HookPhase, err error) Hook {
+		return Hook{Name: name, Step: step, Phase: phase, Run: func(_ context.Context, hc HookContext) error {
+			calls = append(calls, name+" "+hc.Step)
+			return err
+		}}
+	}
+	step := func(name string, err error) []initStep {
+		return []initStep{{name: name, run: func(context.Context, initInfo) error {
+			calls = append(calls, "step "+name)
+			return err
+		}}}
+	}
+
+	t.Run("around the step", func(t *testing.T) {
+		calls = nil
+		info := initInfo{installDir: "/home/me/.dapr", hooks: []Hook{
+			hook("after", StepRedis, HookAfter, nil),
+			hook("before", StepRedis, HookBefore, nil),
+			hook("all", AllSteps, HookBefore, nil),
+			hook("other", StepZipkin, HookBefore, nil),
+		}}
+		require.NoError(t, runInitSteps(context.Background(), step(StepRedis, nil), info, discardInitEvents))
+		assert.Equal(t, []string{"before " + StepRedis, "all " + StepRedis, "step " + StepRedis, "after " + StepRedis}, calls)
+	})
+
+	t.Run("failing hook", func(t *testing.T) {
+		calls = nil
+		info := initInfo{hooks: []Hook{
+			hook("firewall", StepRedis, HookBefore, errors.New("exit status 1")),
+			hook("after", StepRedis, HookAfter, nil),
+		}}
+		err := runInitSteps(context.Background(), step(StepRedis, nil), info, discardInitEvents)
+		assert.EqualError(t, err, "before hook firewall of Redis state store failed: exit status 1")
+		assert.Equal(t, []string{"firewall " + StepRedis}, calls)
+	})
+
+	t.Run("best effort hook", func(t *testing.T) {
+		calls = nil
+		h := hook("firewall", StepRedis, HookBefore, errors.New("exit status 1"))
+		h.BestEffort = true
+		var events []print.ProgressEvent
+		err := runInitSteps(context.Background(), step(StepRedis, nil), initInfo{hooks: []Hook{h}}, func(ev print.ProgressEvent) {
+			events = append(events, ev)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"firewall " + StepRedis, "step " + StepRedis}, calls)
+		assert.Contains(t, events, print.ProgressEvent{Step: StepRedis, Type: print.ProgressStepWarning, Message: "before hook firewall failed: exit status 1"})
+	})
+
+	t.Run("failing step", func(t *testing.T) {
+		calls = nil
+		info := initInfo{hooks: []Hook{hook("after", StepRedis, HookAfter, nil)}}
+		assert.Error(t, runInitSteps(context.Background(), step(StepRedis, errors.New("port in use")), info, discardInitEvents))
+		assert.Equal(t, []string{"step " + StepRedis}, calls)
+	})
+}
+
+func TestValidateHook(t *testing.T) {
+	assert.NoError(t, validateHook("copy", StepDaprdBinary, HookAfter))
+	assert.NoError(t, validateHook("copy", AllSteps, HookBefore))
+	assert.ErrorContains(t, validateHook("copy", "daprd", HookAfter), `runs around step "daprd"`)
+	assert.ErrorContains(t, validateHook("copy", StepDaprdBinary, "during"), `has phase "during"`)
+}
+
+func TestShellHooks(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the hook commands are shell commands")
+	}
+	daprDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "hook.out")
+	config := `{"hooks": [
+		{"step": "daprd binary", "phase": "after", "command": "echo \"$DAPR_HOOK_STEP $DAPR_HOOK_PHASE $DAPR_HOOK_BIN_DIR $DAPR_HOOK_REDIS_PORT\" > ` + out + `"},
+		{"name": "firewall", "step": "Redis state store", "phase": "before", "command": "echo denied >&2; exit 3", "bestEffort": true}
+	]}`
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(config), 0o600))
+
+	hooks, err := shellHooks(daprDir)
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	assert.True(t, strings.HasPrefix(hooks[0].Name, `"echo`))
+	assert.Equal(t, "firewall", hooks[1].Name)
+	assert.True(t, hooks[1].BestEffort)
+
+	hc := HookContext{Step: StepDaprdBinary, Phase: HookAfter, BinDir: "/home/me/.dapr/bin", Ports: InstallPorts{Redis: 6380}}
+	require.NoError(t, hooks[0].Run(context.Background(), hc))
+	b, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "daprd binary after /home/me/.dapr/bin 6380\n", string(b))
+	assert.EqualError(t, hooks[1].Run(context.Background(), hc), "exit status 3: denied")
+
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"hooks": [{"step": "redis", "phase": "before", "command": "true"}]}`), 0o600))
+	_, err = shellHooks(daprDir)
+	assert.ErrorContains(t, err, `runs around step "redis"`)
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"hooks": [{"step": "*", "phase": "before"}]}`), 0o600))
+	_, err = shellHooks(daprDir)
+	assert.ErrorContains(t, err, "hook 1 of CLI config file")
+}
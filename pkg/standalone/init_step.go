@@ -0,0 +1,283 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+const maxInitStepRetryBackoff = 10 * time.Second
+
+// initStepRetryBackoff is the delay before the first retry of a failed step. It doubles on each retry.
+var initStepRetryBackoff = time.Second
+
+// The names of the steps of `dapr init`, which the hooks run around.
+const (
+	StepDaprdBinary       = "daprd binary"
+	StepDashboardBinary   = "dashboard binary"
+	StepSlimConfiguration = "slim configuration"
+	StepComponents        = "components and configuration"
+	StepPlacementBinary   = "placement binary"
+	StepPlacementService  = "placement service"
+	StepRedis             = "Redis state store"
+	StepZipkin            = "Zipkin tracing"
+)
+
+// initStepNames are the names of all the steps, in the order newInitSteps returns them.
+var initStepNames = []string{StepDaprdBinary, StepDashboardBinary, StepSlimConfiguration, StepComponents, StepPlacementBinary, StepPlacementService, StepRedis, StepZipkin}
+
+// initStep is a single unit of work executed by `dapr init`.
+type initStep struct {
+	name string
+	// retryable is set for steps which can fail transiently, e.g. downloads and image pulls.
+	retryable bool
+	run       func(ctx context.Context, info initInfo) error
+}
+
+// progress reports the progress of the step running with this info, if any.
+func (info initInfo) progress(format string, args ...interface{}) {
+	if info.reportProgress != nil {
+		info.reportProgress(fmt.Sprintf(format, args...))
+	}
+}
+
+// warn reports a non-fatal issue of the step running with this info, if any.
+func (info initInfo) warn(format string, args ...interface{}) {
+	if info.reportWarning != nil {
+		info.reportWarning(fmt.Sprintf(format, args...))
+	}
+}
+
+// note records an informational message of the step running with info, see initInfo.recordNote.
+func (info initInfo) note(format string, args ...interface{}) {
+	if info.recordNote != nil {
+		info.recordNote(InitWarning{Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// initStepPanicError is returned when a step panics instead of returning an error.
+type initStepPanicError struct {
+	step  string
+	value interface{}
+	stack []byte
+}
+
+func (e *initStepPanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v\n%s", e.step, e.value, e.stack)
+}
+
+// initStepLog records the outcome of each step so it can be included in a diagnostics bundle.
+// A nil *initStepLog discards all entries.
+type initStepLog struct {
+	lock    sync.Mutex
+	entries []string
+}
+
+func (l *initStepLog) record(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.entries = append(l.entries, time.Now().UTC().Format(time.RFC3339Nano)+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *initStepLog) String() string {
+	if l == nil {
+		return ""
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return strings.Join(l.entries, "\n") + "\n"
+}
+
+// newInitSteps returns the steps needed to initialize dapr with the given options.
+// Downloads and image pulls are retryable, filesystem only steps are not.
+func newInitSteps(info initInfo) []initStep {
+	steps := []initStep{
+		{name: StepDaprdBinary, run: installDaprRuntime, retryable: true},
+	}
+	if info.dashboardVersion != "" {
+		steps = append(steps, initStep{name: StepDashboardBinary, run: installDashboard, retryable: true})
+	}
+	if info.slimMode || isAirGapInit {
+		steps = append(steps, initStep{name: StepSlimConfiguration, run: createSlimConfiguration})
+	} else {
+		steps = append(steps, initStep{name: StepComponents, run: createComponentsAndConfiguration})
+	}
+	if info.slimMode {
+		steps = append(steps, initStep{name: StepPlacementBinary, run: installPlacement, retryable: true})
+	} else {
+		steps = append(steps, initStep{name: StepPlacementService, run: runPlacementService, retryable: true})
+	}
+	if !info.slimMode && !isAirGapInit {
+		// Redis is only run for the components it provides.
+		if info.components == "" || info.components == ComponentsProviderRedis {
+			steps = append(steps, initStep{name: StepRedis, run: runRedis, retryable: true})
+		}
+		steps = append(steps, initStep{name: StepZipkin, run: runZipkin, retryable: true})
+	}
+	return steps
+}
+
+// runInitSteps runs all the steps concurrently and returns the first error encountered.
+// Once a step fails, the remaining steps are cancelled. Progress of each step is reported to onEvent.
+// The steps still running once one fails, or once ctx is done, are waited for up to initInterruptGracePeriod, so that
+// what they created is known once it returns and they no longer write the step log or the containers.
+func runInitSteps(ctx context.Context, steps []initStep, info initInfo, onEvent func(print.ProgressEvent)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errorChan := make(chan error, len(steps))
+
+	wg.Add(len(steps))
+	for _, step := range steps {
+		go func(step initStep) {
+			defer wg.Done()
+			stepInfo := info
+			stepInfo.reportProgress = func(message string) {
+				onEvent(print.ProgressEvent{Step: step.name, Type: print.ProgressStepUpdated, Message: message})
+			}
+			stepInfo.reportWarning = func(message string) {
+				onEvent(print.ProgressEvent{Step: step.name, Type: print.ProgressStepWarning, Message: message})
+			}
+			if info.recordNote != nil {
+				stepInfo.recordNote = func(n InitWarning) {
+					n.Step = step.name
+					info.recordNote(n)
+				}
+			}
+			if info.recordTiming != nil {
+				stepInfo.recordTiming = func(t InitTiming) {
+					t.Step = step.name
+					info.recordTiming(t)
+				}
+			}
+			onEvent(print.ProgressEvent{Step: step.name, Type: print.ProgressStepStarted})
+			err := runHooks(ctx, HookBefore, step.name, stepInfo)
+			if err == nil {
+				err = runInitStep(ctx, step, stepInfo, onEvent)
+			}
+			if err == nil {
+				err = runHooks(ctx, HookAfter, step.name, stepInfo)
+			}
+			if err != nil {
+				onEvent(print.ProgressEvent{Step: step.name, Type: print.ProgressStepFailed, Message: initStepFailureMessage(err)})
+			} else {
+				onEvent(print.ProgressEvent{Step: step.name, Type: print.ProgressStepSucceeded})
+			}
+			errorChan <- err
+		}(step)
+	}
+
+	go func() {
+		wg.Wait()
+		close(errorChan)
+	}()
+
+	for err := range errorChan {
+		if err != nil {
+			cancel()
+			awaitInitSteps(errorChan, initInterruptGracePeriod)
+			return err
+		}
+	}
+	return nil
+}
+
+// awaitInitSteps waits up to timeout for the steps reporting their result to errorChan to finish.
+func awaitInitSteps(errorChan <-chan error, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-errorChan:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// runInitStep runs the step, retrying it with an exponential backoff if it is retryable.
+// Retries are reported to onEvent as updates of the step.
+func runInitStep(ctx context.Context, step initStep, info initInfo, onEvent func(print.ProgressEvent)) error {
+	attempts := 1
+	if step.retryable && info.retries > 0 {
+		attempts += info.retries
+	}
+
+	backoff := initStepRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := runInitStepRecovered(ctx, step, info)
+		var panicErr *initStepPanicError
+		if errors.As(err, &panicErr) {
+			// A panic is a bug, retrying will not help.
+			return err
+		}
+		if err == nil || attempt >= attempts {
+			return err
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", step.name, ctx.Err())
+		}
+
+		onEvent(print.ProgressEvent{
+			Step:    step.name,
+			Type:    print.ProgressStepWarning,
+			Message: fmt.Sprintf("attempt %d of %d failed, retrying in %s: %s", attempt, attempts, backoff, err),
+		})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w (last error: %s)", step.name, ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxInitStepRetryBackoff {
+			backoff = maxInitStepRetryBackoff
+		}
+	}
+}
+
+// initStepFailureMessage returns a one line description of a step failure, leaving out the stack of panics.
+func initStepFailureMessage(err error) string {
+	var panicErr *initStepPanicError
+	if errors.As(err, &panicErr) {
+		return fmt.Sprintf("panicked: %v", panicErr.value)
+	}
+	return err.Error()
+}
+
+// runInitStepRecovered runs the step once, converting a panic into an *initStepPanicError.
+func runInitStepRecovered(ctx context.Context, step initStep, info initInfo) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &initStepPanicError{step: step.name, value: r, stack: debug.Stack()}
+		}
+	}()
+	return step.run(ctx, info)
+}
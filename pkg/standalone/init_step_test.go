@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func discardInitEvents(print.ProgressEvent) {}
+
+func TestRunInitStep(t *testing.T) {
+	defaultBackoff := initStepRetryBackoff
+	initStepRetryBackoff = time.Millisecond
+	t.Cleanup(func() { initStepRetryBackoff = defaultBackoff })
+
+	errTransient := errors.New("transient")
+
+	flakyStep := func(failures int32, calls *int32) func(context.Context, initInfo) error {
+		return func(context.Context, initInfo) error {
+			if atomic.AddInt32(calls, 1) <= failures {
+				return errTransient
+			}
+			return nil
+		}
+	}
+
+	t.Run("retryable step succeeds after transient failures", func(t *testing.T) {
+		var calls int32
+		step := initStep{name: "download", retryable: true, run: flakyStep(2, &calls)}
+		err := runInitStep(context.Background(), step, initInfo{retries: 3}, discardInitEvents)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("retryable step gives up after retries", func(t *testing.T) {
+		var calls int32
+		step := initStep{name: "download", retryable: true, run: flakyStep(10, &calls)}
+		err := runInitStep(context.Background(), step, initInfo{retries: 2}, discardInitEvents)
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("non retryable step runs once", func(t *testing.T) {
+		var calls int32
+		step := initStep{name: "install", run: flakyStep(1, &calls)}
+		err := runInitStep(context.Background(), step, initInfo{retries: 3}, discardInitEvents)
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("retries stop when context is cancelled", func(t *testing.T) {
+		initStepRetryBackoff = time.Hour
+		t.Cleanup(func() { initStepRetryBackoff = time.Millisecond })
+
+		var calls int32
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		step := initStep{name: "download", retryable: true, run: flakyStep(10, &calls)}
+		err := runInitStep(ctx, step, initInfo{retries: 3}, discardInitEvents)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), calls)
+	})
+}
+
+func TestRunInitSteps(t *testing.T) {
+	errFailed := errors.New("failed")
+	var cancelled int32
+
+	steps := []initStep{
+		{name: "fail", run: func(context.Context, initInfo) error { return errFailed }},
+		{name: "wait", run: func(ctx context.Context, _ initInfo) error {
+			<-ctx.Done()
+			atomic.AddInt32(&cancelled, 1)
+			return ctx.Err()
+		}},
+	}
+
+	var lock sync.Mutex
+	var events []print.ProgressEvent
+	err := runInitSteps(context.Background(), steps, initInfo{}, func(ev print.ProgressEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+		events = append(events, ev)
+	})
+	assert.ErrorIs(t, err, errFailed)
+	// The cancelled steps are done once it returns.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cancelled))
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Contains(t, events, print.ProgressEvent{Step: "fail", Type: print.ProgressStepStarted})
+	assert.Contains(t, events, print.ProgressEvent{Step: "fail", Type: print.ProgressStepFailed, Message: "failed"})
+}
+
+func TestRunInitStepReportsRetries(t *testing.T) {
+	defaultBackoff := initStepRetryBackoff
+	initStepRetryBackoff = time.Millisecond
+	t.Cleanup(func() { initStepRetryBackoff = defaultBackoff })
+
+	var calls int32
+	step := initStep{name: "download", retryable: true, run: func(context.Context, initInfo) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("connection reset")
+		}
+		return nil
+	}}
+
+	var events []print.ProgressEvent
+	err := runInitStep(context.Background(), step, initInfo{retries: 1}, func(ev print.ProgressEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, print.ProgressStepWarning, events[0].Type)
+	assert.Equal(t, "attempt 1 of 2 failed, retrying in 1ms: connection reset", events[0].Message)
+}
+
+func TestNewInitSteps(t *testing.T) {
+	stepNames := func(steps []initStep) []string {
+		names := make([]string, 0, len(steps))
+		for _, step := range steps {
+			names = append(names, step.name)
+		}
+		return names
+	}
+
+	t.Run("full mode", func(t *testing.T) {
+		steps := newInitSteps(initInfo{dashboardVersion: "0.13.0"})
+		assert.ElementsMatch(t, []string{
+			"daprd binary", "dashboard binary", "components and configuration", "placement service", "Redis state store", "Zipkin tracing",
+		}, stepNames(steps))
+	})
+
+	t.Run("slim mode", func(t *testing.T) {
+		steps := newInitSteps(initInfo{slimMode: true, dashboardVersion: "0.13.0"})
+		assert.ElementsMatch(t, []string{"daprd binary", "dashboard binary", "slim configuration", "placement binary"}, stepNames(steps))
+	})
+
+	t.Run("in-memory components", func(t *testing.T) {
+		steps := newInitSteps(initInfo{dashboardVersion: "0.13.0", components: ComponentsProviderInMemory})
+		assert.NotContains(t, stepNames(steps), "Redis state store")
+	})
+
+	t.Run("dashboard unavailable", func(t *testing.T) {
+		steps := newInitSteps(initInfo{slimMode: true})
+		assert.NotContains(t, stepNames(steps), "dashboard binary")
+	})
+}
+
+func TestRunInitStepPanic(t *testing.T) {
+	var calls int32
+	step := initStep{name: "panicky", retryable: true, run: func(context.Context, initInfo) error {
+		atomic.AddInt32(&calls, 1)
+		panic("boom")
+	}}
+
+	err := runInitStep(context.Background(), step, initInfo{retries: 3}, discardInitEvents)
+	var panicErr *initStepPanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.value)
+	assert.Contains(t, err.Error(), "panicky panicked: boom")
+	assert.Contains(t, err.Error(), "runInitStepRecovered")
+	assert.Equal(t, int32(1), calls, "panics must not be retried")
+}
+
+func TestRunInitStepsReportsStepProgress(t *testing.T) {
+	steps := []initStep{
+		{name: "download", run: func(_ context.Context, info initInfo) error {
+			info.progress("downloading %s", formatDownloadProgress(1024*1024, 4*1024*1024))
+			return nil
+		}},
+	}
+
+	var events []print.ProgressEvent
+	err := runInitSteps(context.Background(), steps, initInfo{}, func(ev print.ProgressEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []print.ProgressEvent{
+		{Step: "download", Type: print.ProgressStepStarted},
+		{Step: "download", Type: print.ProgressStepUpdated, Message: "downloading 1.0/4.0 MB"},
+		{Step: "download", Type: print.ProgressStepSucceeded},
+	}, events)
+}
+
+func TestRunInitStepsReportsWarnings(t *testing.T) {
+	steps := []initStep{
+		{name: "Redis state store", run: func(_ context.Context, info initInfo) error {
+			info.warn("reusing the existing %s container", "dapr_redis")
+			return nil
+		}},
+	}
+
+	var events []print.ProgressEvent
+	err := runInitSteps(context.Background(), steps, initInfo{}, func(ev print.ProgressEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []print.ProgressEvent{
+		{Step: "Redis state store", Type: print.ProgressStepStarted},
+		{Step: "Redis state store", Type: print.ProgressStepWarning, Message: "reusing the existing dapr_redis container"},
+		{Step: "Redis state store", Type: print.ProgressStepSucceeded},
+	}, events)
+}
+
+func TestRunInitStepsRecordsNotes(t *testing.T) {
+	steps := []initStep{
+		{name: "daprd binary", run: func(_ context.Context, info initInfo) error {
+			info.note("%s is not in your PATH", "/home/me/.dapr/bin")
+			return nil
+		}},
+	}
+
+	var notes []InitWarning
+	var events []print.ProgressEvent
+	info := initInfo{recordNote: func(n InitWarning) { notes = append(notes, n) }}
+	err := runInitSteps(context.Background(), steps, info, func(ev print.ProgressEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []InitWarning{{Step: "daprd binary", Message: "/home/me/.dapr/bin is not in your PATH"}}, notes)
+	// Notes aren't warnings, which fail init with --strict.
+	for _, ev := range events {
+		assert.NotEqual(t, print.ProgressStepWarning, ev.Type)
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	var reports [][2]int64
+	r := &progressReader{
+		r:     strings.NewReader(strings.Repeat("a", 100)),
+		total: 100,
+		onProgress: func(read, total int64) {
+			reports = append(reports, [2]int64{read, total})
+		},
+	}
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, b, 100)
+	// The first read and EOF are always reported, reads in between are throttled.
+	require.GreaterOrEqual(t, len(reports), 2)
+	assert.Equal(t, [2]int64{100, 100}, reports[len(reports)-1])
+
+	assert.Equal(t, "12.3/41.2 MB", formatDownloadProgress(12900000, 43200000))
+	assert.Equal(t, "12.3 MB", formatDownloadProgress(12900000, -1))
+}
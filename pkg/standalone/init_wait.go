@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// Readiness statuses of the parts of the environment installed by init.
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not ready"
+)
+
+// readinessProbeTimeout is how long a part of the environment has to answer a probe.
+const readinessProbeTimeout = time.Second
+
+var (
+	// readinessPollInterval is how often the parts of the environment which aren't ready are probed again.
+	readinessPollInterval = 500 * time.Millisecond
+	// readinessReportInterval is how often the parts still being waited for are reported.
+	readinessReportInterval = 3 * time.Second
+)
+
+// http2SettingsFrameType is the type of the HTTP/2 frame a gRPC server starts the connection with.
+const http2SettingsFrameType = 0x4
+
+// ReadinessCheck is the readiness of a part of the environment installed by init.
+type ReadinessCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Address string `json:"address,omitempty"`
+	// WaitedMs is how long the part was waited for, until it was ready or the wait gave up.
+	WaitedMs int64  `json:"waitedMs"`
+	Error    string `json:"error,omitempty"`
+}
+
+// readinessTarget is a part of the environment to wait for. probe returns the address it probed, if any, and an error
+// while the part isn't ready.
+type readinessTarget struct {
+	name  string
+	probe func() (string, error)
+}
+
+// WaitForEnvironment waits until the environment installed by the init of report is usable: the runtime binary, and
+// the dashboard one if it was installed, run, and the placement, Redis and Zipkin containers, or the services of slim
+// mode, accept connections and answer their protocol. The parts still being waited for are passed to waiting every
+// few seconds along with the time waited so far. The readiness of each part is recorded in report, and
+// WaitForEnvironment fails with the parts which aren't ready once ctx is done. The time from the start of each container
+// until it served is recorded in the timings of report.
+func WaitForEnvironment(ctx context.Context, report *InitReport, containerRuntime string, waiting func(name string, waited time.Duration)) error {
+	start := time.Now()
+	checks, err := waitForTargets(ctx, readinessTargets(report, utils.GetContainerRuntimeCmd(containerRuntime)), waiting)
+	report.Readiness = checks
+	report.recordReadyTimings(checks, start)
+	report.DurationMs += time.Since(start).Milliseconds()
+	return err
+}
+
+// readinessTargets returns the parts of the environment installed by the init of report to wait for.
+func readinessTargets(report *InitReport, runtimeCmd string) []readinessTarget {
+	binary := func(name string) readinessTarget {
+		path := binaryFilePathWithDir(report.BinDir, name)
+		return readinessTarget{name: name, probe: func() (string, error) {
+			_, err := runtimeBinaryVersion(path)
+			return "", err
+		}}
+	}
+	targets := []readinessTarget{binary(daprRuntimeFilePrefix)}
+	if report.DashboardVersion != "" {
+		targets = append(targets, binary(dashboardFilePrefix))
+	}
+	for _, name := range report.Containers {
+		name := name
+		targets = append(targets, readinessTarget{name: name, probe: func() (string, error) {
+			return probeContainer(name, runtimeCmd)
+		}})
+	}
+	for _, name := range report.Services {
+		var address string
+		var probe func(string) error
+		switch name {
+		case placementServiceFilePrefix:
+			address, probe = net.JoinHostPort(daprDefaultHost, strconv.Itoa(defaultPlacementHostPort())), probeGRPC
+		case "redis":
+			address, probe = net.JoinHostPort(daprDefaultHost, strconv.Itoa(redisServicePort)), probeRedis
+		default:
+			continue
+		}
+		targets = append(targets, readinessTarget{name: name, probe: func() (string, error) {
+			return address, probe(address)
+		}})
+	}
+	return targets
+}
+
+// waitForTargets probes the targets until they are all ready or ctx is done, and returns their readiness.
+func waitForTargets(ctx context.Context, targets []readinessTarget, waiting func(name string, waited time.Duration)) ([]ReadinessCheck, error) {
+	start := time.Now()
+	lastReport := start
+	checks := make([]ReadinessCheck, len(targets))
+	for i, t := range targets {
+		checks[i] = ReadinessCheck{Name: t.name, Status: ReadinessNotReady}
+	}
+	for {
+		var pending []string
+		for i, t := range targets {
+			if checks[i].Status == ReadinessReady {
+				continue
+			}
+			address, err := t.probe()
+			checks[i].Address = address
+			checks[i].WaitedMs = time.Since(start).Milliseconds()
+			if err != nil {
+				checks[i].Error = err.Error()
+				pending = append(pending, t.name)
+				continue
+			}
+			checks[i].Status, checks[i].Error = ReadinessReady, ""
+		}
+		if len(pending) == 0 {
+			return checks, nil
+		}
+		if waiting != nil && time.Since(lastReport) >= readinessReportInterval {
+			for _, name := range pending {
+				waiting(name, time.Since(start))
+			}
+			lastReport = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			var details []string
+			for _, c := range checks {
+				if c.Status != ReadinessReady {
+					details = append(details, fmt.Sprintf("%s: %s", c.Name, c.Error))
+				}
+			}
+			return checks, fmt.Errorf("the environment isn't usable after %s, %s", time.Since(start).Round(time.Second), strings.Join(details, "; "))
+		case <-time.After(readinessPollInterval):
+		}
+	}
+}
+
+// probeContainer probes the container with name on the first port it publishes, with the protocol of the container
+// it was created from. A container whose ports aren't published, in a Docker network, is ready once it is running.
+func probeContainer(name, runtimeCmd string) (string, error) {
+	out, err := runCmd(runtimeCmd, "inspect", name)
+	if err != nil {
+		return "", err
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if err != nil {
+		return "", err
+	}
+	if !details.State.Running {
+		return "", fmt.Errorf("the container is %s", details.State.Status)
+	}
+	ports := details.publishedPorts()
+	if len(ports) == 0 {
+		return "", nil
+	}
+	address := ports[0]
+	if host := loopbackHost(runtimeCmd); host != daprDefaultHost {
+		address = strings.Replace(address, daprDefaultHost+":", host+":", 1)
+	}
+	switch {
+	case strings.HasPrefix(name, DaprPlacementContainerName):
+		return address, probeGRPC(address)
+	case strings.HasPrefix(name, DaprRedisContainerName):
+		return address, probeRedis(address)
+	case strings.HasPrefix(name, DaprZipkinContainerName):
+		return address, probeHTTP("http://" + address + "/health")
+	}
+	conn, err := net.DialTimeout("tcp", address, readinessProbeTimeout)
+	if err != nil {
+		return address, err
+	}
+	conn.Close()
+	return address, nil
+}
+
+// probeGRPC checks that a gRPC server serves at address. The server answers the HTTP/2 preface with its settings,
+// whereas a port forwarded by the container runtime to a server which isn't listening yet accepts the connection and
+// closes it.
+func probeGRPC(address string) error {
+	conn, err := net.DialTimeout("tcp", address, readinessProbeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(readinessProbeTimeout))
+	if _, err = conn.Write([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")); err != nil {
+		return err
+	}
+	header := make([]byte, 9)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("no HTTP/2 settings from the server: %w", err)
+	}
+	if header[3] != http2SettingsFrameType {
+		return fmt.Errorf("the server started with the HTTP/2 frame type %d instead of its settings", header[3])
+	}
+	return nil
+}
+
+// probeRedis checks that Redis answers a PING at address. An error reply, such as the one asking to authenticate,
+// means that it serves too, unless it is still loading its dataset.
+func probeRedis(address string) error {
+	conn, err := net.DialTimeout("tcp", address, readinessProbeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(readinessProbeTimeout))
+	if _, err = conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no reply to PING: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	switch {
+	case strings.HasPrefix(reply, "-LOADING"):
+		return errors.New("still loading its dataset")
+	case strings.HasPrefix(reply, "+"), strings.HasPrefix(reply, "-"):
+		return nil
+	}
+	return fmt.Errorf("unexpected reply to PING: %q", reply)
+}
+
+// probeHTTP checks that url answers with a 2xx status.
+func probeHTTP(url string) error {
+	client := &http.Client{Timeout: readinessProbeTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
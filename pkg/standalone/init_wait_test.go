@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveTCP serves each connection accepted on a local port with handle, and returns the address of the port.
+func serveTCP(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestProbeGRPC(t *testing.T) {
+	settings := serveTCP(t, func(conn net.Conn) {
+		conn.Write([]byte{0, 0, 0, http2SettingsFrameType, 0, 0, 0, 0, 0})
+	})
+	assert.NoError(t, probeGRPC(settings))
+
+	// The port forwarded to a server which isn't listening yet.
+	closed := serveTCP(t, func(net.Conn) {})
+	assert.ErrorContains(t, probeGRPC(closed), "no HTTP/2 settings")
+
+	other := serveTCP(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+	})
+	assert.Error(t, probeGRPC(other))
+}
+
+func TestProbeRedis(t *testing.T) {
+	reply := func(r string) string {
+		return serveTCP(t, func(conn net.Conn) {
+			buf := make([]byte, 6)
+			conn.Read(buf)
+			conn.Write([]byte(r))
+		})
+	}
+	assert.NoError(t, probeRedis(reply("+PONG\r\n")))
+	assert.NoError(t, probeRedis(reply("-NOAUTH Authentication required.\r\n")))
+	assert.ErrorContains(t, probeRedis(reply("-LOADING Redis is loading the dataset in memory\r\n")), "loading")
+	assert.ErrorContains(t, probeRedis(serveTCP(t, func(net.Conn) {})), "no reply to PING")
+}
+
+func TestProbeHTTP(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	assert.ErrorContains(t, probeHTTP(ts.URL+"/health"), "503")
+	status = http.StatusOK
+	assert.NoError(t, probeHTTP(ts.URL+"/health"))
+}
+
+func TestProbeContainer(t *testing.T) {
+	address := serveTCP(t, func(conn net.Conn) {
+		buf := make([]byte, 6)
+		conn.Read(buf)
+		conn.Write([]byte("+PONG\r\n"))
+	})
+	_, port, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+
+	fakeContainerRuntime(t, DaprRedisContainerName, fmt.Sprintf(`[{
+		"State": {"Status": "running", "Running": true},
+		"NetworkSettings": {"Ports": {"6379/tcp": [{"HostIp": "127.0.0.1", "HostPort": "%s"}]}}
+	}]`, port))
+	probed, err := probeContainer(DaprRedisContainerName, "docker")
+	assert.NoError(t, err)
+	assert.Equal(t, address, probed)
+
+	// In a Docker network the ports aren't published.
+	fakeContainerRuntime(t, DaprPlacementContainerName+"_mynet", `[{"State": {"Status": "running", "Running": true}}]`)
+	probed, err = probeContainer(DaprPlacementContainerName+"_mynet", "docker")
+	assert.NoError(t, err)
+	assert.Empty(t, probed)
+
+	fakeContainerRuntime(t, DaprZipkinContainerName, `[{"State": {"Status": "restarting", "Running": false}}]`)
+	_, err = probeContainer(DaprZipkinContainerName, "docker")
+	assert.EqualError(t, err, "the container is restarting")
+}
+
+func TestReadinessTargets(t *testing.T) {
+	names := func(targets []readinessTarget) []string {
+		var names []string
+		for _, target := range targets {
+			names = append(names, target.name)
+		}
+		return names
+	}
+	report := &InitReport{BinDir: t.TempDir(), DashboardVersion: "0.13.0", Containers: []string{DaprPlacementContainerName, DaprRedisContainerName}}
+	assert.Equal(t, []string{daprRuntimeFilePrefix, dashboardFilePrefix, DaprPlacementContainerName, DaprRedisContainerName}, names(readinessTargets(report, "docker")))
+
+	report = &InitReport{BinDir: t.TempDir(), SlimMode: true, Services: []string{placementServiceFilePrefix, "redis"}}
+	targets := readinessTargets(report, "docker")
+	assert.Equal(t, []string{daprRuntimeFilePrefix, placementServiceFilePrefix, "redis"}, names(targets))
+	address, err := targets[0].probe()
+	assert.Empty(t, address)
+	assert.Error(t, err, "the runtime binary isn't installed")
+	address, _ = targets[2].probe()
+	assert.Equal(t, fmt.Sprintf("%s:%d", daprDefaultHost, redisServicePort), address)
+}
+
+func TestWaitForTargets(t *testing.T) {
+	previousPoll, previousReport := readinessPollInterval, readinessReportInterval
+	t.Cleanup(func() { readinessPollInterval, readinessReportInterval = previousPoll, previousReport })
+	readinessPollInterval, readinessReportInterval = 10*time.Millisecond, 0
+
+	probes := 0
+	placement := readinessTarget{name: "placement", probe: func() (string, error) {
+		probes++
+		if probes < 3 {
+			return "localhost:50005", fmt.Errorf("connection refused")
+		}
+		return "localhost:50005", nil
+	}}
+	ready := readinessTarget{name: "daprd", probe: func() (string, error) { return "", nil }}
+	var waited []string
+	checks, err := waitForTargets(context.Background(), []readinessTarget{ready, placement}, func(name string, _ time.Duration) {
+		waited = append(waited, name)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"placement", "placement"}, waited)
+	require.Len(t, checks, 2)
+	assert.Equal(t, ReadinessCheck{Name: "daprd", Status: ReadinessReady}, checks[0])
+	assert.Equal(t, ReadinessReady, checks[1].Status)
+	assert.Equal(t, "localhost:50005", checks[1].Address)
+	assert.Empty(t, checks[1].Error)
+
+	t.Run("not ready before the timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		redis := readinessTarget{name: "dapr_redis", probe: func() (string, error) { return "localhost:6379", fmt.Errorf("no reply to PING") }}
+		checks, err := waitForTargets(ctx, []readinessTarget{ready, redis}, nil)
+		assert.ErrorContains(t, err, "dapr_redis: no reply to PING")
+		assert.NotContains(t, err.Error(), "daprd")
+		assert.Equal(t, ReadinessNotReady, checks[1].Status)
+	})
+}
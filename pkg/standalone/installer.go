@@ -0,0 +1,395 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/utils"
+)
+
+// zipkinContainerPort is the port Zipkin listens on in its container.
+const zipkinContainerPort = 9411
+
+// CommandRunner runs the command name with args to completion, returning its standard output, or an error with its
+// standard error if it fails. It runs the commands of the container runtime and of the init system.
+type CommandRunner func(name string, args ...string) (string, error)
+
+var (
+	// runCmd runs the commands of the install, the requests use utils.HTTPClient. An Installer swaps them for the
+	// options it's given while it runs an operation, installerLock makes the operations run one at a time.
+	runCmd        CommandRunner = utils.RunCmdAndWait
+	installerLock sync.Mutex
+
+	// runCmdContext runs the commands which must stop with the install, such as the pulls, killing them once ctx is
+	// done. It runs them with runCmd once that is replaced, which can't be cancelled.
+	runCmdContext = utils.RunCmdAndWaitContext
+)
+
+// runWithRunCmd runs the commands of runCmdContext with runCmd, and returns the function restoring them.
+func runWithRunCmd() func() {
+	previous, previousContext := runCmd, runCmdContext
+	runCmdContext = func(_ context.Context, name string, args ...string) (string, error) {
+		return runCmd(name, args...)
+	}
+	return func() { runCmd, runCmdContext = previous, previousContext }
+}
+
+// InstallPorts are the host ports the placement, Redis and Zipkin containers are published on, outside of a docker
+// network. The ports which are 0 are the default ones.
+type InstallPorts struct {
+	Placement int `json:"placement"`
+	Redis     int `json:"redis"`
+	Zipkin    int `json:"zipkin"`
+}
+
+// withDefaults returns the ports with the default ones set for those which are 0.
+func (p InstallPorts) withDefaults() InstallPorts {
+	if p.Placement == 0 {
+		p.Placement = defaultPlacementHostPort()
+	}
+	if p.Redis == 0 {
+		p.Redis = redisServicePort
+	}
+	if p.Zipkin == 0 {
+		p.Zipkin = zipkinContainerPort
+	}
+	return p
+}
+
+// Installer installs, uninstalls and reports the status of Dapr in self-hosted mode, as init, uninstall and status do.
+// It is the supported API to embed the install in other programs: the options, the methods and the reports they
+// return are kept compatible, while the other exported functions of the package serve the CLI and may change.
+//
+// An Installer is created with NewInstaller and the options, which default to the ones of the CLI: the latest
+// versions, installed in $HOME/.dapr with docker. Its operations run one at a time in a process, as the install
+// relies on process-wide state, and print their status events with the print package, which can be silenced with
+// print.EnableQuietMode.
+type Installer struct {
+	opts installerOptions
+}
+
+type installerOptions struct {
+	runtimeVersion    string
+	dashboardVersion  string
+	dockerNetwork     string
+	slimMode          bool
+	imageRegistryURL  string
+	fromDir           string
+	containerRuntime  string
+	imageVariant      string
+	runtimePath       string
+	retries           int
+	diagnosticsBundle bool
+	waitForLock       bool
+	strict            bool
+	force             bool
+	initSystem        string
+	lock              *Lockfile
+	signature         SignatureOptions
+	unhardened        bool
+	components        string
+	ports             InstallPorts
+	pullConcurrency   int
+	runner            CommandRunner
+	httpClient        *http.Client
+	progress          func(ev print.ProgressEvent)
+	uninstallAll      bool
+	hooks             []Hook
+}
+
+// InstallerOption is an option of NewInstaller.
+type InstallerOption func(o *installerOptions)
+
+// NewInstaller returns the Installer with opts.
+func NewInstaller(opts ...InstallerOption) *Installer {
+	o := installerOptions{
+		runtimeVersion:   latestVersion,
+		dashboardVersion: latestVersion,
+		containerRuntime: string(utils.DOCKER),
+		initSystem:       InitSystemNone,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Installer{opts: o}
+}
+
+// WithRuntimeVersion installs the version of the runtime, latest by default.
+func WithRuntimeVersion(version string) InstallerOption {
+	return func(o *installerOptions) {
+		o.runtimeVersion = version
+	}
+}
+
+// WithDashboardVersion installs the version of the dashboard, latest by default.
+func WithDashboardVersion(version string) InstallerOption {
+	return func(o *installerOptions) {
+		o.dashboardVersion = version
+	}
+}
+
+// WithDockerNetwork runs the containers in the docker network, instead of publishing their ports.
+func WithDockerNetwork(network string) InstallerOption {
+	return func(o *installerOptions) {
+		o.dockerNetwork = network
+	}
+}
+
+// WithSlimMode installs the binaries only, without the containers.
+func WithSlimMode(slim bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.slimMode = slim
+	}
+}
+
+// WithImageRegistry pulls the images from the private registry at url, as --image-registry.
+func WithImageRegistry(url string) InstallerOption {
+	return func(o *installerOptions) {
+		o.imageRegistryURL = url
+	}
+}
+
+// WithFromDir installs from the bundle in dir, without network access, as --from-dir.
+func WithFromDir(dir string) InstallerOption {
+	return func(o *installerOptions) {
+		o.fromDir = dir
+	}
+}
+
+// WithContainerRuntime runs the containers with docker, the default, or podman.
+func WithContainerRuntime(containerRuntime string) InstallerOption {
+	return func(o *installerOptions) {
+		if strings.TrimSpace(containerRuntime) != "" {
+			o.containerRuntime = containerRuntime
+		}
+	}
+}
+
+// WithImageVariant runs the image variant of the runtime, such as mariner.
+func WithImageVariant(variant string) InstallerOption {
+	return func(o *installerOptions) {
+		o.imageVariant = variant
+	}
+}
+
+// WithRuntimePath installs in the .dapr directory of path instead of the home directory, as --runtime-path.
+func WithRuntimePath(path string) InstallerOption {
+	return func(o *installerOptions) {
+		o.runtimePath = path
+	}
+}
+
+// WithRetries retries the steps failing transiently, such as the downloads, up to retries times.
+func WithRetries(retries int) InstallerOption {
+	return func(o *installerOptions) {
+		o.retries = retries
+	}
+}
+
+// WithPullConcurrency runs at most concurrency image pulls and downloads at once, the pullConcurrency setting of
+// the CLI by default.
+func WithPullConcurrency(concurrency int) InstallerOption {
+	return func(o *installerOptions) {
+		o.pullConcurrency = concurrency
+	}
+}
+
+// WithDiagnosticsBundle writes a diagnostics bundle in the install dir when the install fails.
+func WithDiagnosticsBundle(enabled bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.diagnosticsBundle = enabled
+	}
+}
+
+// WithWaitForLock waits for another install or uninstall running on the install dir to finish, instead of failing.
+func WithWaitForLock(wait bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.waitForLock = wait
+	}
+}
+
+// WithStrict fails the install on the warnings of its steps.
+func WithStrict(strict bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.strict = strict
+	}
+}
+
+// WithForce reinstalls the binaries and containers, and regenerates the files of the install which have been edited.
+func WithForce(force bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.force = force
+	}
+}
+
+// WithInitSystem runs the services of slim mode with the init system, one of the InitSystem constants.
+func WithInitSystem(initSystem string) InstallerOption {
+	return func(o *installerOptions) {
+		if initSystem != "" {
+			o.initSystem = initSystem
+		}
+	}
+}
+
+// WithLockfile reproduces the install of the lockfile, as --from-lockfile.
+func WithLockfile(lock *Lockfile) InstallerOption {
+	return func(o *installerOptions) {
+		o.lock = lock
+	}
+}
+
+// WithSignatureOptions sets how the downloaded archives are verified, including skipping the verification.
+func WithSignatureOptions(signature SignatureOptions) InstallerOption {
+	return func(o *installerOptions) {
+		o.signature = signature
+	}
+}
+
+// WithUnhardenedRedis runs the Redis container without its hardening, for images which can't run that way.
+func WithUnhardenedRedis(unhardened bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.unhardened = unhardened
+	}
+}
+
+// WithComponentsProvider sets up the state store and pubsub components with provider, one of the ComponentsProvider
+// constants, Redis by default.
+func WithComponentsProvider(provider string) InstallerOption {
+	return func(o *installerOptions) {
+		o.components = provider
+	}
+}
+
+// WithPorts publishes the containers on other host ports than the default ones.
+func WithPorts(ports InstallPorts) InstallerOption {
+	return func(o *installerOptions) {
+		o.ports = ports
+	}
+}
+
+// WithRunner runs the commands of the container runtime and of the init system with runner.
+func WithRunner(runner CommandRunner) InstallerOption {
+	return func(o *installerOptions) {
+		o.runner = runner
+	}
+}
+
+// WithHTTPClient makes the requests of the operations with client: the lookups of the latest versions, and the
+// downloads of the archives and their signatures.
+func WithHTTPClient(client *http.Client) InstallerOption {
+	return func(o *installerOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithProgress sends the progress events of the steps of the install to sink, instead of drawing them on stdout. The
+// sink may be called from several goroutines at once.
+func WithProgress(sink func(ev print.ProgressEvent)) InstallerOption {
+	return func(o *installerOptions) {
+		o.progress = sink
+	}
+}
+
+// WithHooks runs hooks around the steps of the install, before the hooks of the CLI config file. A hook without a
+// name is named after its position in hooks.
+func WithHooks(hooks ...Hook) InstallerOption {
+	return func(o *installerOptions) {
+		for _, h := range hooks {
+			if h.Name == "" {
+				h.Name = fmt.Sprintf("#%d", len(o.hooks)+1)
+			}
+			o.hooks = append(o.hooks, h)
+		}
+	}
+}
+
+// WithUninstallAll also removes the Redis and Zipkin containers and the install dir on Uninstall, as --all.
+func WithUninstallAll(all bool) InstallerOption {
+	return func(o *installerOptions) {
+		o.uninstallAll = all
+	}
+}
+
+// run runs the operation f with the runner and the HTTP client of the installer, once no other operation runs.
+func (i *Installer) run(ctx context.Context, f func() error) error {
+	installerLock.Lock()
+	defer installerLock.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if i.opts.runner != nil {
+		defer runWithRunCmd()()
+		runCmd = i.opts.runner
+	}
+	if i.opts.httpClient != nil {
+		previousClient := utils.SetHTTPClient(i.opts.httpClient)
+		defer utils.SetHTTPClient(previousClient)
+	}
+	return f()
+}
+
+// Install installs Dapr with the options of the installer. The steps stop when ctx is done.
+// Retryable steps are retried up to the retries of WithRetries times.
+// If another init or uninstall is running, Install fails unless WithWaitForLock is set, in which case it waits for it
+// to finish. Non-fatal issues reported by the steps are printed as warnings after the summary, and make Install fail
+// with WithStrict. With WithDiagnosticsBundle, a failed install writes a diagnostics bundle under the dapr install dir.
+// An existing configuration file which differs from the default one is kept unless WithForce is set, which also
+// replaces the installed binaries and recreates the containers, pulling their images again.
+// With WithLockfile, the images are pinned to its digests and the downloads checked against its checksums, failing if
+// any can't be satisfied. Install writes the lockfile of the installed environment in the dapr install dir.
+// In slim mode, the init system of WithInitSystem runs the placement binary, and a redis-server installed on the
+// machine, as services. The downloaded archives are verified as set by WithSignatureOptions. The Redis container is
+// hardened unless WithUnhardenedRedis is set, for images which can't run that way.
+// If ctx is cancelled with ErrInitInterrupted, the containers, files and directories created by the steps are rolled
+// back, as described by the Interrupted field of the report, and Install returns ErrInitInterrupted.
+// The returned report describes what was installed, it is never nil.
+func (i *Installer) Install(ctx context.Context) (*InitReport, error) {
+	report := &InitReport{SlimMode: i.opts.slimMode}
+	err := i.run(ctx, func() error {
+		var err error
+		report, err = i.install(ctx)
+		return err
+	})
+	return report, err
+}
+
+// Uninstall removes what Install installed, as Uninstall does with the options of the installer. ctx is only checked
+// before it starts. The returned report describes what was removed, it is never nil.
+func (i *Installer) Uninstall(ctx context.Context) (*UninstallReport, error) {
+	report := &UninstallReport{RemovedDirectories: []string{}, RemovedContainers: []string{}}
+	err := i.run(ctx, func() error {
+		var err error
+		report, err = Uninstall(i.opts.uninstallAll, i.opts.dockerNetwork, i.opts.containerRuntime, i.opts.runtimePath, i.opts.waitForLock)
+		return err
+	})
+	return report, err
+}
+
+// Status reports the status of the install, as GetEnvironmentStatus does with the options of the installer. ctx is
+// only checked before it starts.
+func (i *Installer) Status(ctx context.Context) (*EnvironmentStatus, error) {
+	var status *EnvironmentStatus
+	err := i.run(ctx, func() error {
+		var err error
+		status, err = GetEnvironmentStatus(i.opts.runtimePath, i.opts.dockerNetwork, i.opts.containerRuntime)
+		return err
+	})
+	return status, err
+}
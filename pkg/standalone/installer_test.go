@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/utils"
+)
+
+func ExampleInstaller() {
+	installer := NewInstaller(
+		WithRuntimeVersion("1.11.0"),
+		WithDashboardVersion("0.13.0"),
+		WithRuntimePath("/opt/myapp"),
+		WithPorts(InstallPorts{Redis: 16379}),
+		WithRetries(3),
+		WithProgress(func(ev print.ProgressEvent) {
+			fmt.Println(ev.Step, ev.Type, ev.Message)
+		}),
+	)
+	report, err := installer.Install(context.Background())
+	if err != nil {
+		fmt.Println("install failed:", err)
+		return
+	}
+	fmt.Println("installed in", report.InstallDir)
+
+	status, err := installer.Status(context.Background())
+	if err == nil {
+		fmt.Println("the environment is", status.Verdict)
+	}
+}
+
+func TestNewInstaller(t *testing.T) {
+	o := NewInstaller().opts
+	assert.Equal(t, latestVersion, o.runtimeVersion)
+	assert.Equal(t, latestVersion, o.dashboardVersion)
+	assert.Equal(t, "docker", o.containerRuntime)
+	assert.Equal(t, InitSystemNone, o.initSystem)
+
+	// The empty values of the flags keep the defaults.
+	o = NewInstaller(WithContainerRuntime(" "), WithInitSystem(""), WithContainerRuntime("podman"), WithSlimMode(true)).opts
+	assert.Equal(t, "podman", o.containerRuntime)
+	assert.Equal(t, InitSystemNone, o.initSystem)
+	assert.True(t, o.slimMode)
+
+	assert.Equal(t, InstallPorts{Placement: defaultPlacementHostPort(), Redis: 6379, Zipkin: 9411}, InstallPorts{}.withDefaults())
+	assert.Equal(t, 16379, InstallPorts{Redis: 16379}.withDefaults().Redis)
+
+	o = NewInstaller(WithHooks(Hook{Step: StepRedis, Phase: HookBefore}), WithHooks(Hook{Name: "copy", Step: StepDaprdBinary, Phase: HookAfter})).opts
+	require.Len(t, o.hooks, 2)
+	assert.Equal(t, "#1", o.hooks[0].Name)
+	assert.Equal(t, "copy", o.hooks[1].Name)
+}
+
+func TestInstallerRunner(t *testing.T) {
+	var commands []string
+	runner := func(name string, args ...string) (string, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	client := &http.Client{}
+	installer := NewInstaller(WithRuntimePath(t.TempDir()), WithRunner(runner), WithHTTPClient(client))
+	var usedClient *http.Client
+	require.NoError(t, installer.run(context.Background(), func() error {
+		usedClient = utils.HTTPClient()
+		_, err := runCmd("docker", "ps")
+		return err
+	}))
+	assert.Same(t, client, usedClient)
+	assert.Equal(t, []string{"docker ps"}, commands)
+	// The defaults are restored once the operation returns.
+	assert.NotSame(t, client, utils.HTTPClient())
+
+	status, err := installer.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentDegraded, status.Verdict)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := installer.Install(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotNil(t, report)
+}
+
+func TestInstallPortsConfiguration(t *testing.T) {
+	args := strings.Join(redisContainerArgs("dapr_redis", "redis:6", "", 16379, true), " ")
+	assert.Contains(t, args, "-p 127.0.0.1:16379:6379")
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, createDefaultConfiguration(initInfo{ports: InstallPorts{Zipkin: 19411}}, "localhost", file))
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "http://localhost:19411/api/v2/spans")
+
+	// The containers are reached on their own ports in a docker network.
+	require.NoError(t, createDefaultConfiguration(initInfo{ports: InstallPorts{Zipkin: 19411}, dockerNetwork: "mynet", force: true}, DaprZipkinContainerName, file))
+	b, err = os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "http://dapr_zipkin:9411/api/v2/spans")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// InterruptedExitCode is the exit code of an init interrupted with Ctrl-C, the one of the shells for SIGINT.
+const InterruptedExitCode = 130
+
+// The kinds of the artifacts an init creates, as rolled back when it is interrupted.
+const (
+	ArtifactContainer = "container"
+	ArtifactFile      = "file"
+	ArtifactDirectory = "directory"
+	// ArtifactStep is a step still running when the rollback started, whatever it creates is left behind.
+	ArtifactStep = "step"
+)
+
+// ErrInitInterrupted is the cause of the cancellation of the context of an init interrupted by the user, see
+// context.WithCancelCause. Init rolls back what it created when its context is cancelled with it.
+var ErrInitInterrupted = errors.New("init was interrupted")
+
+// initInterruptGracePeriod is how long an interrupted init waits for its running steps to observe the cancellation,
+// before rolling back what they created.
+var initInterruptGracePeriod = 10 * time.Second
+
+// InitArtifact is something an init created, a container, a file or a directory.
+type InitArtifact struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Reason is why the artifact was left behind by the rollback.
+	Reason string `json:"reason,omitempty"`
+}
+
+// InitRollback describes the rollback of an interrupted init.
+type InitRollback struct {
+	Removed    []InitArtifact `json:"removed"`
+	LeftBehind []InitArtifact `json:"leftBehind"`
+}
+
+// initArtifacts records the artifacts created by the steps of an init, so that they can be rolled back. It is safe
+// for concurrent use.
+type initArtifacts struct {
+	lock      sync.Mutex
+	artifacts []InitArtifact
+}
+
+func (a *initArtifacts) record(kind, name string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.artifacts = append(a.artifacts, InitArtifact{Kind: kind, Name: name})
+}
+
+// rollback removes the artifacts, the last created first, with runtimeCmd for the containers. The files which no
+// longer exist, e.g. the archives removed once extracted, are skipped. A directory is only removed if it is empty,
+// the ones still holding files which weren't created by the init are skipped.
+func (a *initArtifacts) rollback(runtimeCmd string) InitRollback {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	result := InitRollback{Removed: []InitArtifact{}, LeftBehind: []InitArtifact{}}
+	for i := len(a.artifacts) - 1; i >= 0; i-- {
+		artifact := a.artifacts[i]
+		var err error
+		switch artifact.Kind {
+		case ArtifactContainer:
+			_, err = runCmd(runtimeCmd, "rm", "--force", artifact.Name)
+		case ArtifactDirectory:
+			entries, readErr := os.ReadDir(artifact.Name)
+			if readErr != nil || len(entries) > 0 {
+				continue
+			}
+			err = fsys.Remove(artifact.Name)
+		default:
+			if _, statErr := fsys.Stat(artifact.Name); statErr != nil {
+				continue
+			}
+			err = fsys.RemoveAll(artifact.Name)
+		}
+		if err != nil {
+			artifact.Reason = err.Error()
+			result.LeftBehind = append(result.LeftBehind, artifact)
+			continue
+		}
+		result.Removed = append(result.Removed, artifact)
+	}
+	return result
+}
+
+// recordMissingPaths returns a function recording the paths which don't exist yet, and do when it is called, as
+// files created by the step running with this info.
+func (info initInfo) recordMissingPaths(paths ...string) func() {
+	var missing []string
+	for _, p := range paths {
+		if _, err := fsys.Stat(p); errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, p)
+		}
+	}
+	return func() {
+		for _, p := range missing {
+			if _, err := fsys.Stat(p); err == nil {
+				info.recordArtifact(ArtifactFile, p)
+			}
+		}
+	}
+}
+
+// recordArtifact records an artifact created by the step running with this info, if the artifacts are recorded.
+func (info initInfo) recordArtifact(kind, name string) {
+	if info.artifacts != nil {
+		info.artifacts.record(kind, name)
+	}
+}
+
+// runningInitSteps returns the steps which started, as recorded in started, and haven't finished yet.
+func runningInitSteps(finished []InitStepReport, started map[string]time.Time) []string {
+	done := map[string]bool{}
+	for _, s := range finished {
+		done[s.Name] = true
+	}
+	var running []string
+	for name := range started {
+		if !done[name] {
+			running = append(running, name)
+		}
+	}
+	sort.Strings(running)
+	return running
+}
+
+// rollbackInterruptedInit rolls back the artifacts of an interrupted init. The steps which didn't observe the
+// cancellation in time are still running, and may leave behind what they create from now on. The partial downloads
+// are kept, for the next init to resume them.
+func rollbackInterruptedInit(info initInfo, running []string) InitRollback {
+	result := info.artifacts.rollback(utils.GetContainerRuntimeCmd(info.containerRuntime))
+	for _, step := range running {
+		result.LeftBehind = append(result.LeftBehind, InitArtifact{Kind: ArtifactStep, Name: step, Reason: fmt.Sprintf("still running after %s", initInterruptGracePeriod)})
+	}
+	partials, _ := path_filepath.Glob(path_filepath.Join(getDaprBinPath(info.installDir), "*"+partialDownloadSuffix))
+	for _, p := range partials {
+		result.LeftBehind = append(result.LeftBehind, InitArtifact{Kind: ArtifactFile, Name: p, Reason: "kept to resume the download on the next init"})
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestInitArtifactsRollback(t *testing.T) {
+	commands := fakeContainerRuntime(t, DaprRedisContainerName, "")
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	componentsDir := filepath.Join(dir, "components")
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.MkdirAll(componentsDir, 0o755))
+	daprd := filepath.Join(binDir, "daprd")
+	require.NoError(t, os.WriteFile(daprd, []byte("daprd"), 0o755))
+	// The component of the user keeps its directory.
+	require.NoError(t, os.WriteFile(filepath.Join(componentsDir, "mine.yaml"), []byte("kind: Component\n"), 0o644))
+
+	artifacts := &initArtifacts{}
+	artifacts.record(ArtifactDirectory, binDir)
+	artifacts.record(ArtifactDirectory, componentsDir)
+	artifacts.record(ArtifactFile, filepath.Join(binDir, "daprd_linux_amd64.tar.gz"))
+	artifacts.record(ArtifactFile, daprd)
+	artifacts.record(ArtifactContainer, DaprRedisContainerName)
+
+	rollback := artifacts.rollback("docker")
+	assert.Equal(t, []InitArtifact{
+		{Kind: ArtifactContainer, Name: DaprRedisContainerName},
+		{Kind: ArtifactFile, Name: daprd},
+		{Kind: ArtifactDirectory, Name: binDir},
+	}, rollback.Removed)
+	assert.Empty(t, rollback.LeftBehind)
+	assert.Equal(t, []string{"rm --force dapr_redis"}, *commands)
+	assert.NoDirExists(t, binDir)
+	assert.FileExists(t, filepath.Join(componentsDir, "mine.yaml"))
+}
+
+func TestInitArtifactsRollbackLeavesFailures(t *testing.T) {
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(string, ...string) (string, error) {
+		return "", errors.New("cannot connect to the docker daemon")
+	}
+
+	artifacts := &initArtifacts{}
+	artifacts.record(ArtifactContainer, DaprZipkinContainerName)
+	rollback := artifacts.rollback("docker")
+	assert.Empty(t, rollback.Removed)
+	assert.Equal(t, []InitArtifact{{Kind: ArtifactContainer, Name: DaprZipkinContainerName, Reason: "cannot connect to the docker daemon"}}, rollback.LeftBehind)
+}
+
+func TestRecordMissingPaths(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "config.yaml")
+	created := filepath.Join(dir, "pubsub.yaml")
+	require.NoError(t, os.WriteFile(existing, []byte("kind: Configuration\n"), 0o644))
+
+	info := initInfo{artifacts: &initArtifacts{}}
+	record := info.recordMissingPaths(existing, created, filepath.Join(dir, "statestore.yaml"))
+	require.NoError(t, os.WriteFile(existing, []byte("kind: Configuration\n"), 0o644))
+	require.NoError(t, os.WriteFile(created, []byte("kind: Component\n"), 0o644))
+	record()
+	assert.Equal(t, []InitArtifact{{Kind: ArtifactFile, Name: created}}, info.artifacts.artifacts)
+}
+
+func TestRollbackInterruptedInit(t *testing.T) {
+	dir := t.TempDir()
+	binDir := getDaprBinPath(dir)
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	partial := filepath.Join(binDir, "daprd_linux_amd64.tar.gz.0a1b2c3d"+partialDownloadSuffix)
+	require.NoError(t, os.WriteFile(partial, []byte("part"), 0o644))
+
+	info := initInfo{installDir: dir, artifacts: &initArtifacts{}}
+	info.artifacts.record(ArtifactDirectory, binDir)
+	rollback := rollbackInterruptedInit(info, []string{StepRedis})
+	assert.Empty(t, rollback.Removed)
+	require.Len(t, rollback.LeftBehind, 2)
+	assert.Equal(t, InitArtifact{Kind: ArtifactStep, Name: StepRedis, Reason: "still running after " + initInterruptGracePeriod.String()}, rollback.LeftBehind[0])
+	assert.Equal(t, partial, rollback.LeftBehind[1].Name)
+	assert.FileExists(t, partial)
+}
+
+func TestRunningInitSteps(t *testing.T) {
+	started := map[string]time.Time{StepDaprdBinary: {}, StepZipkin: {}, StepRedis: {}}
+	assert.Equal(t, []string{StepRedis, StepZipkin}, runningInitSteps([]InitStepReport{{Name: StepDaprdBinary}}, started))
+}
+
+func TestRunInitStepsAwaitsCancelledSteps(t *testing.T) {
+	previous := initInterruptGracePeriod
+	t.Cleanup(func() { initInterruptGracePeriod = previous })
+
+	var finished atomic.Bool
+	steps := []initStep{
+		{name: "download", run: func(ctx context.Context, _ initInfo) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		{name: "container", run: func(ctx context.Context, _ initInfo) error {
+			<-ctx.Done()
+			// e.g. a container run which doesn't observe the cancellation.
+			time.Sleep(50 * time.Millisecond)
+			finished.Store(true)
+			return nil
+		}},
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(10*time.Millisecond, func() { cancel(ErrInitInterrupted) })
+	err := runInitSteps(ctx, steps, initInfo{}, func(print.ProgressEvent) {})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.True(t, finished.Load(), "the steps are awaited once cancelled")
+
+	// The steps which don't return in time are left running.
+	initInterruptGracePeriod = time.Millisecond
+	finished.Store(false)
+	ctx, cancel = context.WithCancelCause(context.Background())
+	time.AfterFunc(10*time.Millisecond, func() { cancel(ErrInitInterrupted) })
+	err = runInitSteps(ctx, steps, initInfo{}, func(print.ProgressEvent) {})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, finished.Load())
+}
@@ -20,66 +20,190 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/dapr/cli/pkg/api"
 	"github.com/dapr/cli/utils"
 )
 
+// InvokeOptions are the optional settings of InvokeMethod.
+type InvokeOptions struct {
+	// Socket is the directory of the unix domain sockets of the sidecar.
+	Socket string
+	// Headers are set on the request, in addition to its content type.
+	Headers http.Header
+}
+
+// InvokeResult is the response of an app method invoked through its sidecar.
+type InvokeResult struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Body        []byte
+	// Latency is the round-trip time of the request.
+	Latency time.Duration
+}
+
 // Invoke is a command to invoke a remote or local dapr instance.
 func (s *Standalone) Invoke(appID, method string, data []byte, verb string, path string) (string, error) {
-	list, err := s.process.List()
+	result, err := s.InvokeMethod(appID, method, data, verb, InvokeOptions{Socket: path})
 	if err != nil {
 		return "", err
 	}
+	return string(result.Body), nil
+}
+
+// InvokeMethod invokes method of the running instance of appID through its sidecar. The result is returned along
+// with the error of a non-2xx response, which includes the body of the response.
+func (s *Standalone) InvokeMethod(appID, method string, data []byte, verb string, opts InvokeOptions) (InvokeResult, error) {
+	list, err := s.process.List()
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	lo, err := findRunningApp(list, appID)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	url := makeEndpoint(lo, method)
+	req, err := http.NewRequest(verb, url, bytes.NewBuffer(data))
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, values := range opts.Headers {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
 
+	var httpc http.Client
+
+	socket := opts.Socket
+	if socket == "" {
+		socket = lo.UnixDomainSocket
+	}
+	if socket != "" {
+		httpc.Transport = &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", utils.GetSocket(socket, appID, "http"))
+			},
+		}
+	}
+
+	start := time.Now()
+	r, err := httpc.Do(req)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	defer r.Body.Close()
+	return handleResponse(r, start)
+}
+
+// findRunningApp returns the instance of appID in list. If there is none, the error suggests the app ids which are
+// close to appID, or lists the running ones.
+func findRunningApp(list []ListOutput, appID string) (ListOutput, error) {
+	known := make([]string, 0, len(list))
 	for _, lo := range list {
 		if lo.AppID == appID {
-			url := makeEndpoint(lo, method)
-			req, err := http.NewRequest(verb, url, bytes.NewBuffer(data))
-			if err != nil {
-				return "", err
-			}
-			req.Header.Set("Content-Type", "application/json")
+			return lo, nil
+		}
+		if lo.AppID != "" {
+			known = append(known, lo.AppID)
+		}
+	}
 
-			var httpc http.Client
+	if len(known) == 0 {
+		return ListOutput{}, fmt.Errorf("no such running app %s, no Dapr instance is running", appID)
+	}
+	if suggestions := suggestNames(appID, known); len(suggestions) > 0 {
+		return ListOutput{}, fmt.Errorf("no such running app %s, did you mean %s?", appID, strings.Join(suggestions, " or "))
+	}
+	sort.Strings(known)
+	return ListOutput{}, fmt.Errorf("no such running app %s, the running apps are: %s", appID, strings.Join(known, ", "))
+}
 
-			if path != "" {
-				httpc.Transport = &http.Transport{
-					DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-						return net.Dial("unix", utils.GetSocket(path, appID, "http"))
-					},
-				}
+// suggestNames returns the names of known which are a few edits away from name or contain it, closest first.
+func suggestNames(name string, known []string) []string {
+	maxDistance := len(name)/3 + 1
+	distances := make(map[string]int, len(known))
+	var suggestions []string
+	for _, k := range known {
+		d := editDistance(strings.ToLower(name), strings.ToLower(k))
+		if d <= maxDistance || strings.Contains(k, name) || strings.Contains(name, k) {
+			if _, ok := distances[k]; !ok {
+				suggestions = append(suggestions, k)
 			}
+			distances[k] = d
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
 
-			r, err := httpc.Do(req)
-			if err != nil {
-				return "", err
+// editDistance returns the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
 			}
-			defer r.Body.Close()
-			return handleResponse(r)
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
 		}
+		prev = curr
 	}
+	return prev[len(b)]
+}
 
-	return "", fmt.Errorf("app ID %s not found", appID)
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
 }
 
 func makeEndpoint(lo ListOutput, method string) string {
 	return fmt.Sprintf("http://127.0.0.1:%s/v%s/invoke/%s/method/%s", fmt.Sprintf("%v", lo.HTTPPort), api.RuntimeAPIVersion, lo.AppID, method)
 }
 
-func handleResponse(response *http.Response) (string, error) {
-	if response.StatusCode < 200 || response.StatusCode >= 400 {
-		return "", fmt.Errorf("%s", response.Status)
-	}
-
+func handleResponse(response *http.Response, start time.Time) (InvokeResult, error) {
 	rb, err := io.ReadAll(response.Body)
 	if err != nil {
-		return "", err
+		return InvokeResult{}, err
 	}
 
-	if len(rb) > 0 {
-		return string(rb), nil
+	result := InvokeResult{
+		StatusCode:  response.StatusCode,
+		Status:      response.Status,
+		ContentType: response.Header.Get("Content-Type"),
+		Body:        rb,
+		Latency:     time.Since(start),
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		if body := strings.TrimSpace(string(rb)); body != "" {
+			return result, fmt.Errorf("%s: %s", response.Status, body)
+		}
+		return result, fmt.Errorf("%s", response.Status)
 	}
 
-	return "", nil
+	return result, nil
 }
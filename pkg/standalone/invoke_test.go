@@ -15,11 +15,14 @@ package standalone
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dapr/cli/utils"
 )
@@ -47,7 +50,7 @@ func TestInvoke(t *testing.T) {
 			name:          "appID not found",
 			errorExpected: true,
 			appID:         "invalid",
-			errString:     "app ID invalid not found",
+			errString:     "no such running app invalid, the running apps are: testapp",
 			lo: ListOutput{
 				AppID: "testapp",
 			},
@@ -214,3 +217,66 @@ func TestInvoke(t *testing.T) {
 		}
 	}
 }
+
+func TestInvokeMethod(t *testing.T) {
+	ts, port := getTestServerFunc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1.0/invoke/orders/method/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"no such method"}`))
+			return
+		}
+		w.Write([]byte(`{"tenant":"` + r.Header.Get("X-Tenant") + `","type":"` + r.Header.Get("Content-Type") + `"}`))
+	}))
+	ts.Start()
+	defer ts.Close()
+
+	client := &Standalone{
+		process: &mockDaprProcess{
+			Lo: []ListOutput{{AppID: "orders", HTTPPort: port}},
+		},
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set("X-Tenant", "acme")
+		headers.Set("Content-Type", "text/plain")
+		result, err := client.InvokeMethod("orders", "list", nil, http.MethodGet, InvokeOptions{Headers: headers})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, "application/json", result.ContentType)
+		assert.Equal(t, `{"tenant":"acme","type":"text/plain"}`, string(result.Body))
+		assert.Greater(t, result.Latency, time.Duration(0))
+	})
+
+	t.Run("non-2xx", func(t *testing.T) {
+		result, err := client.InvokeMethod("orders", "missing", nil, http.MethodGet, InvokeOptions{})
+		assert.EqualError(t, err, `404 Not Found: {"error":"no such method"}`)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+	})
+
+	t.Run("did you mean", func(t *testing.T) {
+		_, err := client.InvokeMethod("order", "list", nil, http.MethodGet, InvokeOptions{})
+		assert.EqualError(t, err, "no such running app order, did you mean orders?")
+	})
+}
+
+func TestFindRunningApp(t *testing.T) {
+	list := []ListOutput{{AppID: "orders"}, {AppID: "checkout"}, {AppID: "order-processor"}, {AppID: "payments"}}
+
+	lo, err := findRunningApp(list, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", lo.AppID)
+
+	_, err = findRunningApp(list, "ordres")
+	assert.EqualError(t, err, "no such running app ordres, did you mean orders?")
+
+	_, err = findRunningApp(list, "order")
+	assert.EqualError(t, err, "no such running app order, did you mean orders or order-processor?")
+
+	_, err = findRunningApp(list, "inventory")
+	assert.EqualError(t, err, "no such running app inventory, the running apps are: checkout, order-processor, orders, payments")
+
+	_, err = findRunningApp(nil, "orders")
+	assert.EqualError(t, err, "no such running app orders, no Dapr instance is running")
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"runtime"
+	"sync"
+)
+
+const (
+	// LibcGlibc and LibcMusl are the C libraries of the Linux systems told apart by hostLibc.
+	LibcGlibc = "glibc"
+	LibcMusl  = "musl"
+
+	// muslArtifactSuffix is appended to the architecture in the names of the release artifacts built for musl, or
+	// built static, to run on Alpine and the other musl-based systems.
+	muslArtifactSuffix = "_musl"
+)
+
+// ErrIncompatibleLibc is returned for a binary which can't run on this system, because it is dynamically linked
+// against a C library this system doesn't have.
+var ErrIncompatibleLibc = errors.New("is linked against a C library this system doesn't have")
+
+var (
+	libcOnce sync.Once
+	libc     string
+)
+
+// hostLibc returns the C library of this machine, LibcGlibc or LibcMusl, or an empty string if it has neither or
+// isn't a Linux system.
+func hostLibc() string {
+	libcOnce.Do(func() {
+		if runtime.GOOS == "linux" {
+			libc = detectLibc("/")
+		}
+	})
+	return libc
+}
+
+// detectLibc returns the C library of the Linux file system at root, told from its dynamic loader.
+func detectLibc(root string) string {
+	if matches, _ := path_filepath.Glob(path_filepath.Join(root, "lib", "ld-musl-*.so.1")); len(matches) > 0 {
+		return LibcMusl
+	}
+	for _, pattern := range []string{"lib*/ld-linux*.so.*", "lib/*-linux-gnu*/ld-linux*.so.*", "usr/lib*/ld-linux*.so.*"} {
+		if matches, _ := path_filepath.Glob(path_filepath.Join(root, pattern)); len(matches) > 0 {
+			return LibcGlibc
+		}
+	}
+	return ""
+}
+
+// preferStaticArtifact reports whether the static, musl-compatible, release artifacts are to be preferred on this
+// machine, a Linux system without glibc.
+func preferStaticArtifact() bool {
+	return runtime.GOOS == "linux" && hostLibc() != LibcGlibc
+}
+
+// elfInterpreter returns the dynamic loader the ELF executable at path is run with, or an empty string if it is
+// statically linked or isn't an ELF executable.
+func elfInterpreter(path string) (string, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return "", nil //nolint:nilerr
+	}
+	defer f.Close()
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_INTERP {
+			continue
+		}
+		b := make([]byte, prog.Filesz)
+		if _, err = prog.ReadAt(b, 0); err != nil {
+			return "", fmt.Errorf("error reading the loader of %s: %w", path, err)
+		}
+		return string(bytes.TrimRight(b, "\x00")), nil
+	}
+	return "", nil
+}
+
+// checkBinaryRunnable returns an ErrIncompatibleLibc error if the executable at path needs a dynamic loader this
+// system doesn't have, as the glibc builds on Alpine, which would otherwise fail to run with a misleading "not
+// found" error.
+func checkBinaryRunnable(path string) error {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	interp, err := elfInterpreter(path)
+	if err != nil || interp == "" {
+		return err
+	}
+	if _, err = os.Stat(interp); err == nil {
+		return nil
+	}
+	system := "this"
+	if l := hostLibc(); l != "" {
+		system = "this " + l + "-based"
+	}
+	return fmt.Errorf("%s %w: it needs the loader %s, which %s system doesn't have", path_filepath.Base(path), ErrIncompatibleLibc, interp, system)
+}
+
+// incompatibleLibcError returns err, from checkBinaryRunnable for the build of the binary of version, with the ways
+// to get a binary which runs.
+func incompatibleLibcError(err error, binaryFilePrefix, version string) error {
+	return fmt.Errorf("%w. No static or musl build of %s %s is published, run it in a glibc-based image, or install the glibc compatibility layer, as the gcompat package of Alpine", err, binaryFilePrefix, version)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDynamicELF writes an amd64 ELF executable to path, dynamically linked with the loader interp.
+func writeDynamicELF(t *testing.T, path, interp string) {
+	var buf bytes.Buffer
+	header := elf.Header64{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     64,
+		Ehsize:    64,
+		Phentsize: 56,
+		Phnum:     1,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	size := uint64(len(interp) + 1)
+	prog := elf.Prog64{Type: uint32(elf.PT_INTERP), Flags: uint32(elf.PF_R), Off: 64 + 56, Filesz: size, Memsz: size, Align: 1}
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, header))
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, prog))
+	buf.WriteString(interp + "\x00")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o755))
+}
+
+func TestDetectLibc(t *testing.T) {
+	for loader, expected := range map[string]string{
+		"lib/ld-musl-x86_64.so.1":                      LibcMusl,
+		"lib/ld-musl-aarch64.so.1":                     LibcMusl,
+		"lib64/ld-linux-x86-64.so.2":                   LibcGlibc,
+		"lib/ld-linux-aarch64.so.1":                    LibcGlibc,
+		"lib/x86_64-linux-gnu/ld-linux-x86-64.so.2":    LibcGlibc,
+		"usr/lib64/ld-linux-x86-64.so.2":               LibcGlibc,
+		"lib/ld-something-else.so":                     "",
+		"usr/lib/x86_64-linux-gnu/libc.so.6.not-a-ldr": "",
+	} {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, loader)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, loader), nil, 0o755))
+		assert.Equal(t, expected, detectLibc(root), loader)
+	}
+	assert.Empty(t, detectLibc(t.TempDir()))
+}
+
+func TestElfInterpreter(t *testing.T) {
+	dir := t.TempDir()
+	daprd := filepath.Join(dir, "daprd")
+	writeDynamicELF(t, daprd, "/lib64/ld-linux-x86-64.so.2")
+	interp, err := elfInterpreter(daprd)
+	require.NoError(t, err)
+	assert.Equal(t, "/lib64/ld-linux-x86-64.so.2", interp)
+
+	// The other executables, and the files which aren't executables, have no loader to check.
+	script := filepath.Join(dir, "script")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0o755))
+	interp, err = elfInterpreter(script)
+	require.NoError(t, err)
+	assert.Empty(t, interp)
+}
+
+func TestCheckBinaryRunnable(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the loaders are only checked on Linux")
+	}
+	dir := t.TempDir()
+	daprd := filepath.Join(dir, "daprd")
+	writeDynamicELF(t, daprd, filepath.Join(dir, "missing", "ld-linux-x86-64.so.2"))
+	err := checkBinaryRunnable(daprd)
+	require.ErrorIs(t, err, ErrIncompatibleLibc)
+	assert.Contains(t, err.Error(), "it needs the loader "+filepath.Join(dir, "missing", "ld-linux-x86-64.so.2"))
+	assert.Contains(t, incompatibleLibcError(err, "daprd", "1.11.0").Error(), "No static or musl build of daprd 1.11.0 is published")
+
+	loader := filepath.Join(dir, "ld-linux-x86-64.so.2")
+	require.NoError(t, os.WriteFile(loader, nil, 0o755))
+	writeDynamicELF(t, daprd, loader)
+	assert.NoError(t, checkBinaryRunnable(daprd))
+}
+
+func TestLibcCheck(t *testing.T) {
+	assert.Equal(t, DoctorCheck{Name: "libc", Status: DoctorPass, Message: LibcMusl}, libcCheck(LibcMusl, nil))
+	assert.Equal(t, DoctorPass, libcCheck("", nil).Status)
+
+	c := libcCheck(LibcMusl, errors.New("daprd is linked against a C library this system doesn't have"))
+	assert.Equal(t, DoctorFail, c.Status)
+	assert.Contains(t, c.Hint, "gcompat")
+}
@@ -47,14 +47,167 @@ type ListOutput struct {
 	AppLogPath         string `csv:"APP_LOG_PATH"  json:"appLogPath"            yaml:"appLogPath"`
 	DaprDLogPath       string `csv:"DAPRD_LOG_PATH"  json:"daprdLogPath"            yaml:"daprdLogPath"`
 	RunTemplateName    string `json:"runTemplateName"            yaml:"runTemplateName"` // specifically omitted in csv output.
+	// Orphan is set for the instances not tracked by a run record, or whose CLI process is gone.
+	Orphan bool `csv:"-" json:"orphan,omitempty" yaml:"orphan,omitempty"`
+	// ExitReason is set once the daprd or the app process of the instance exited, from its run record.
+	ExitReason string `csv:"-" json:"exitReason,omitempty" yaml:"exitReason,omitempty"`
+	// Restarts and LastExitCode are the restarts of the processes which failed, from its run record.
+	Restarts     int `csv:"-" json:"restarts,omitempty"     yaml:"restarts,omitempty"`
+	LastExitCode int `csv:"-" json:"lastExitCode,omitempty" yaml:"lastExitCode,omitempty"`
+	// AttachedCliPID is the pid of the run --attach command running the app against the sidecar, if there is one.
+	AttachedCliPID int `csv:"-" json:"attachedCliPid,omitempty" yaml:"attachedCliPid,omitempty"`
+	// The settings in effect, reported by Endpoints. Not displayed in table, except for the wide output.
+	ResourcesPaths       []string `csv:"-" json:"resourcesPaths,omitempty"       yaml:"resourcesPaths,omitempty"`
+	ConfigFile           string   `csv:"-" json:"configFile,omitempty"           yaml:"configFile,omitempty"`
+	PlacementHostAddress string   `csv:"-" json:"placementHostAddress,omitempty" yaml:"placementHostAddress,omitempty"`
+	UnixDomainSocket     string   `csv:"-" json:"unixDomainSocket,omitempty"     yaml:"unixDomainSocket,omitempty"`
+	// AppProtocol is the protocol daprd talks to the app with, e.g. https for an app serving TLS.
+	AppProtocol string `csv:"-" json:"appProtocol,omitempty" yaml:"appProtocol,omitempty"`
 }
 
 func (d *daprProcess) List() ([]ListOutput, error) {
 	return List()
 }
 
-// List outputs all the applications.
+// List outputs all the applications, using the run records of the default dapr install dir.
 func List() ([]ListOutput, error) {
+	return ListInstances("")
+}
+
+// ListInstances outputs all the applications. The daprd processes are matched with the run records of the dapr
+// install dir, which is based on daprRuntimePath as for GetDaprRuntimePath.
+func ListInstances(daprRuntimePath string) ([]ListOutput, error) {
+	list, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	// The run records complement the metadata of the sidecars, so they are used as a best effort.
+	var records []RunRecord
+	if daprDir, err := GetDaprRuntimePath(daprRuntimePath); err == nil {
+		records, _ = ReadRunRecords(daprDir)
+	}
+	return mergeRunRecords(list, records), nil
+}
+
+// mergeRunRecords completes the instances of list with their run record, flagging the untracked ones as orphans,
+// and adds the instances of the remaining records.
+func mergeRunRecords(list []ListOutput, records []RunRecord) []ListOutput {
+	byPID := make(map[int]RunRecord, len(records))
+	for _, r := range records {
+		byPID[r.DaprdPID] = r
+	}
+	for i := range list {
+		r, ok := byPID[list[i].DaprdPID]
+		if !ok {
+			list[i].Orphan = true
+			continue
+		}
+		delete(byPID, r.DaprdPID)
+		fillFromRunRecord(&list[i], r)
+	}
+	for _, r := range records {
+		if _, ok := byPID[r.DaprdPID]; !ok {
+			continue
+		}
+		row := ListOutput{
+			AppID:    r.AppID,
+			DaprdPID: r.DaprdPID,
+			HTTPPort: r.HTTPPort,
+			GRPCPort: r.GRPCPort,
+			AppPort:  r.AppPort,
+			Created:  r.Started.Format("2006-01-02 15:04.05"),
+			Age:      age.GetAge(r.Started),
+		}
+		// The daprd process of the record exited, the pid is either gone or another process's.
+		if !recordedProcessAlive(r.DaprdPID, r.DaprdCreateTime) {
+			row.DaprdPID = 0
+		}
+		fillFromRunRecord(&row, r)
+		list = append(list, row)
+	}
+	return list
+}
+
+func fillFromRunRecord(row *ListOutput, r RunRecord) {
+	if r.Attached != nil && pidAlive(r.Attached.CliPID) {
+		row.AttachedCliPID = r.Attached.CliPID
+		r.AppPID, r.Command = r.Attached.AppPID, r.Attached.Command
+	}
+	if row.CliPID == 0 {
+		row.CliPID = r.CliPID
+	}
+	if row.AppPID == 0 {
+		row.AppPID = r.AppPID
+	}
+	if row.Command == "" {
+		row.Command = utils.TruncateString(r.Command, 20)
+	}
+	if row.AppLogPath == "" {
+		row.AppLogPath = r.AppLogPath
+	}
+	if row.DaprDLogPath == "" {
+		row.DaprDLogPath = r.DaprdLogPath
+	}
+	if row.RunTemplatePath == "" {
+		row.RunTemplatePath = r.RunTemplatePath
+	}
+	if row.RunTemplateName == "" {
+		row.RunTemplateName = r.RunTemplateName
+	}
+	if len(r.ResourcesPaths) > 0 {
+		row.ResourcesPaths = r.ResourcesPaths
+	}
+	if row.ConfigFile == "" {
+		row.ConfigFile = r.ConfigFile
+	}
+	if row.PlacementHostAddress == "" {
+		row.PlacementHostAddress = r.PlacementHostAddress
+	}
+	if row.UnixDomainSocket == "" {
+		row.UnixDomainSocket = r.UnixDomainSocket
+	}
+	if r.AppProtocol != "" {
+		row.AppProtocol = r.AppProtocol
+	}
+	row.ExitReason = r.ExitReason
+	row.Restarts, row.LastExitCode = r.Restarts, r.LastExitCode
+	cliAlive := pidAlive(row.CliPID)
+	if row.CliPID == r.CliPID {
+		cliAlive = recordedProcessAlive(r.CliPID, r.CliCreateTime)
+	}
+	row.Orphan = !cliAlive
+}
+
+// InstanceGroup is a set of instances started from the same run template.
+type InstanceGroup struct {
+	// RunTemplateName and RunTemplatePath are empty for the instances not started from a run template.
+	RunTemplateName string
+	RunTemplatePath string
+	Instances       []ListOutput
+}
+
+// GroupByRunTemplate groups the instances by the run template they were started from. The instances started on
+// their own come first, then the groups in the order of their first instance.
+func GroupByRunTemplate(list []ListOutput) []InstanceGroup {
+	groups := []InstanceGroup{{}}
+	index := map[string]int{"": 0}
+	for _, instance := range list {
+		i, ok := index[instance.RunTemplatePath]
+		if !ok {
+			i = len(groups)
+			index[instance.RunTemplatePath] = i
+			groups = append(groups, InstanceGroup{RunTemplateName: instance.RunTemplateName, RunTemplatePath: instance.RunTemplatePath})
+		}
+		groups[i].Instances = append(groups[i].Instances, instance)
+	}
+	if len(groups[0].Instances) == 0 {
+		groups = groups[1:]
+	}
+	return groups
+}
+
+// listProcesses lists the applications from the running daprd processes.
+func listProcesses() ([]ListOutput, error) {
 	list := []ListOutput{}
 
 	processes, err := ps.Processes()
@@ -80,18 +233,7 @@ func List() ([]ListOutput, error) {
 			if len(cmdLineItems) <= 1 {
 				continue
 			}
-
-			// Parse command line arguments, example format for cmdLine `daprd --flag1 value1 --enable-flag2 --flag3 value3`.
-			argumentsMap := make(map[string]string)
-			for i := 1; i < len(cmdLineItems)-1; {
-				if !strings.HasPrefix(cmdLineItems[i+1], "--") {
-					argumentsMap[cmdLineItems[i]] = cmdLineItems[i+1]
-					i += 2
-				} else {
-					argumentsMap[cmdLineItems[i]] = ""
-					i++
-				}
-			}
+			argumentsMap := parseDaprdArgs(cmdLineItems)
 
 			httpPort := getIntArg(argumentsMap, "--dapr-http-port", runtime.DefaultDaprHTTPPort)
 
@@ -118,7 +260,7 @@ func List() ([]ListOutput, error) {
 			daprdLogPath := ""
 			runTemplateName := ""
 			socket := argumentsMap["--unix-domain-socket"]
-			appMetadata, err := metadata.Get(httpPort, appID, socket)
+			appMetadata, err := metadata.GetWithTimeout(httpPort, appID, socket, DefaultHealthProbeTimeout)
 			if err == nil {
 				appCmd = appMetadata.Extended["appCommand"]
 				appPIDString = appMetadata.Extended["appPID"]
@@ -167,6 +309,11 @@ func List() ([]ListOutput, error) {
 				RunTemplateName:    runTemplateName,
 				AppLogPath:         appLogPath,
 				DaprDLogPath:       daprdLogPath,
+				// The resources paths, which can be repeated, are only known from the run records.
+				ConfigFile:           argumentsMap["--config"],
+				PlacementHostAddress: argumentsMap["--placement-host-address"],
+				UnixDomainSocket:     socket,
+				AppProtocol:          daprdAppProtocol(argumentsMap),
 			}
 
 			// filter only dashboard instance.
@@ -179,6 +326,28 @@ func List() ([]ListOutput, error) {
 	return list, nil
 }
 
+// daprdAppProtocol returns the protocol the daprd with the arguments of argumentsMap talks to the app with.
+func daprdAppProtocol(argumentsMap map[string]string) string {
+	ssl, ok := argumentsMap["--app-ssl"]
+	return EffectiveAppProtocol(argumentsMap["--app-protocol"], ok && ssl != "false")
+}
+
+// parseDaprdArgs parses the arguments of a daprd command line, in the format `daprd --flag1 value1 --enable-flag2
+// --flag3 value3`, to a map of the flags to their values.
+func parseDaprdArgs(cmdLineItems []string) map[string]string {
+	argumentsMap := make(map[string]string)
+	for i := 1; i < len(cmdLineItems)-1; {
+		if !strings.HasPrefix(cmdLineItems[i+1], "--") {
+			argumentsMap[cmdLineItems[i]] = cmdLineItems[i+1]
+			i += 2
+		} else {
+			argumentsMap[cmdLineItems[i]] = ""
+			i++
+		}
+	}
+	return argumentsMap
+}
+
 // getIntArg returns the value of the argument as an integer.
 // If the argument is not set, or is not an integer, it returns the default value.
 func getIntArg(argMap map[string]string, argKey string, argDef int) int {
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByRunTemplate(t *testing.T) {
+	groups := GroupByRunTemplate([]ListOutput{
+		{AppID: "orders", RunTemplateName: "shop", RunTemplatePath: "/shop/dapr.yaml"},
+		{AppID: "single"},
+		{AppID: "other", RunTemplatePath: "/other/dapr.yaml"},
+		{AppID: "checkout", RunTemplateName: "shop", RunTemplatePath: "/shop/dapr.yaml"},
+	})
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "", groups[0].RunTemplatePath)
+	assert.Equal(t, "single", groups[0].Instances[0].AppID)
+
+	assert.Equal(t, "shop", groups[1].RunTemplateName)
+	require.Len(t, groups[1].Instances, 2)
+	assert.Equal(t, "orders", groups[1].Instances[0].AppID)
+	assert.Equal(t, "checkout", groups[1].Instances[1].AppID)
+
+	assert.Equal(t, "/other/dapr.yaml", groups[2].RunTemplatePath)
+
+	assert.Empty(t, GroupByRunTemplate(nil))
+	groups = GroupByRunTemplate([]ListOutput{{AppID: "orders", RunTemplatePath: "/shop/dapr.yaml"}})
+	require.Len(t, groups, 1)
+	assert.Equal(t, "/shop/dapr.yaml", groups[0].RunTemplatePath)
+}
+
+func TestDaprdAppProtocol(t *testing.T) {
+	assert.Equal(t, "http", daprdAppProtocol(parseDaprdArgs([]string{"daprd", "--app-id", "orders", "--app-port", "3000"})))
+	assert.Equal(t, "grpc", daprdAppProtocol(parseDaprdArgs([]string{"daprd", "--app-protocol", "grpc", "--app-port", "3000"})))
+	assert.Equal(t, "https", daprdAppProtocol(parseDaprdArgs([]string{"daprd", "--app-protocol", "http", "--app-ssl", "--app-port", "3000"})))
+	assert.Equal(t, "grpcs", daprdAppProtocol(parseDaprdArgs([]string{"daprd", "--app-protocol", "grpc", "--app-ssl", "true", "--app-port", "3000"})))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"time"
+
+	"github.com/nightlyone/lockfile"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+const installLockFileName = "install.lock"
+
+// installLockPollInterval is how often a waiting init or uninstall retries to acquire the lock.
+var installLockPollInterval = 500 * time.Millisecond
+
+// InstallLockedError is returned when another init, uninstall or upgrade holds the install lock.
+type InstallLockedError struct {
+	Path     string
+	PID      int
+	Acquired time.Time
+}
+
+func (e *InstallLockedError) Error() string {
+	return fmt.Sprintf("another dapr init, uninstall or upgrade (PID %d) has held the lock %s since %s. Wait for it to finish or use --wait-for-lock", e.PID, e.Path, e.Acquired.Format(time.RFC3339))
+}
+
+// acquireInstallLock takes the advisory lock on the dapr install dir, reclaiming it if it was left behind by
+// a process which no longer exists. If wait is set, it blocks until the lock is released or ctx is done,
+// otherwise it fails immediately with an *InstallLockedError. The returned func releases the lock.
+func acquireInstallLock(ctx context.Context, installDir string, wait bool) (func(), error) {
+	installDir, err := path_filepath.Abs(installDir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(installDir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dapr install dir %s: %w", installDir, err)
+	}
+
+	lockPath := path_filepath.Join(installDir, installLockFileName)
+	lock, err := lockfile.New(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	waiting := false
+	for {
+		err = lock.TryLock()
+		if err == nil {
+			// The lock file may already be gone if uninstall --all removed the install dir.
+			return func() { _ = lock.Unlock() }, nil
+		}
+		if !errors.Is(err, lockfile.ErrBusy) && !errors.Is(err, lockfile.ErrNotExist) {
+			return nil, fmt.Errorf("error acquiring lock %s: %w", lockPath, err)
+		}
+
+		lockedErr := &InstallLockedError{Path: lockPath}
+		if owner, ownerErr := lock.GetOwner(); ownerErr == nil {
+			lockedErr.PID = owner.Pid
+		}
+		if fi, statErr := os.Stat(lockPath); statErr == nil {
+			lockedErr.Acquired = fi.ModTime()
+		}
+		if !wait {
+			return nil, lockedErr
+		}
+		if !waiting {
+			print.InfoStatusEvent(os.Stdout, "Waiting for another dapr init, uninstall or upgrade (PID %d) to release the lock %s", lockedErr.PID, lockPath)
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s", ctx.Err(), lockedErr)
+		case <-time.After(installLockPollInterval):
+		}
+	}
+}
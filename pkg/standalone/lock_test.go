@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLockFile simulates another process holding the install lock.
+func writeLockFile(t *testing.T, installDir string, pid int) string {
+	t.Helper()
+	lockPath := filepath.Join(installDir, installLockFileName)
+	require.NoError(t, os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", pid)), 0o644))
+	return lockPath
+}
+
+// deadPID returns the PID of a process which has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func TestAcquireInstallLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("PID liveness checks of the lock file are not reliable in this test on windows")
+	}
+
+	defaultPollInterval := installLockPollInterval
+	installLockPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { installLockPollInterval = defaultPollInterval })
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		installDir := t.TempDir()
+		unlock, err := acquireInstallLock(context.Background(), installDir, false)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(installDir, installLockFileName))
+
+		unlock()
+		assert.NoFileExists(t, filepath.Join(installDir, installLockFileName))
+	})
+
+	t.Run("creates the install dir", func(t *testing.T) {
+		installDir := filepath.Join(t.TempDir(), ".dapr")
+		unlock, err := acquireInstallLock(context.Background(), installDir, false)
+		require.NoError(t, err)
+		defer unlock()
+		assert.DirExists(t, installDir)
+	})
+
+	t.Run("fails immediately when held by a running process", func(t *testing.T) {
+		installDir := t.TempDir()
+		lockPath := writeLockFile(t, installDir, os.Getppid())
+
+		_, err := acquireInstallLock(context.Background(), installDir, false)
+		var lockedErr *InstallLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Equal(t, os.Getppid(), lockedErr.PID)
+		assert.Equal(t, lockPath, lockedErr.Path)
+		assert.False(t, lockedErr.Acquired.IsZero())
+		assert.Contains(t, err.Error(), fmt.Sprintf("PID %d", os.Getppid()))
+	})
+
+	t.Run("waits until the lock is released", func(t *testing.T) {
+		installDir := t.TempDir()
+		lockPath := writeLockFile(t, installDir, os.Getppid())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			os.Remove(lockPath)
+		}()
+
+		unlock, err := acquireInstallLock(context.Background(), installDir, true)
+		require.NoError(t, err)
+		unlock()
+	})
+
+	t.Run("waiting stops when the context is done", func(t *testing.T) {
+		installDir := t.TempDir()
+		writeLockFile(t, installDir, os.Getppid())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := acquireInstallLock(ctx, installDir, true)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("reclaims the lock of a crashed process", func(t *testing.T) {
+		installDir := t.TempDir()
+		writeLockFile(t, installDir, deadPID(t))
+
+		unlock, err := acquireInstallLock(context.Background(), installDir, false)
+		require.NoError(t, err)
+		defer unlock()
+
+		b, err := os.ReadFile(filepath.Join(installDir, installLockFileName))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%d\n", os.Getpid()), string(b))
+	})
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dapr/cli/utils"
+)
+
+// DefaultLockfileName is the name of the lockfile init writes in the dapr install dir.
+const DefaultLockfileName = "dapr.lock"
+
+// Lockfile pins the environment set up by init, so that init --from-lockfile reproduces it on another machine.
+// It holds no timestamps and its lists are sorted, so that it diffs cleanly when committed with a project.
+type Lockfile struct {
+	RuntimeVersion   string            `json:"runtimeVersion"`
+	DashboardVersion string            `json:"dashboardVersion,omitempty"`
+	SlimMode         bool              `json:"slimMode"`
+	Binaries         []LockedBinary    `json:"binaries"`
+	Containers       []LockedContainer `json:"containers"`
+}
+
+// LockedBinary is a binary downloaded by init, along with the checksum of its archive.
+type LockedBinary struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	SHA256  string `json:"sha256"`
+}
+
+// LockedContainer is a container run by init, along with the digest of its image.
+type LockedContainer struct {
+	// Name is the name of the container without the suffix of the docker network.
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Digest is the digest of the image, sha256:<hex>.
+	Digest string `json:"digest"`
+	// HostPorts are the ports the container is published on, none in a docker network.
+	HostPorts []int `json:"hostPorts,omitempty"`
+	// imageID is the ID of the image on this machine, recorded in the install manifest but not in the lockfile, as
+	// it depends on the platform.
+	imageID string
+}
+
+// PinnedImage returns the reference to the image of the container by its digest.
+func (c LockedContainer) PinnedImage() string {
+	repo := c.Image
+	if i := strings.LastIndex(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	// Strip the tag, but not the port of a registry host.
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo + "@" + c.Digest
+}
+
+// LockfileDrift is a difference between a lockfile and the environment.
+type LockfileDrift struct {
+	Item   string `json:"item"`
+	Locked string `json:"locked"`
+	Actual string `json:"actual"`
+}
+
+// GetLockfilePath returns the path of the lockfile init writes in the dapr install dir.
+func GetLockfilePath(daprDir string) string {
+	return path_filepath.Join(daprDir, DefaultLockfileName)
+}
+
+// ReadLockfile reads the lockfile at path.
+func ReadLockfile(path string) (*Lockfile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lockfile %s: %w", path, err)
+	}
+	var l Lockfile
+	if err = json.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("error parsing lockfile %s: %w", path, err)
+	}
+	if l.RuntimeVersion == "" {
+		return nil, fmt.Errorf("lockfile %s has no runtime version", path)
+	}
+	return &l, nil
+}
+
+// writeLockfile writes l to path, with its lists sorted.
+func writeLockfile(path string, l *Lockfile) error {
+	sort.Slice(l.Binaries, func(i, j int) bool { return l.Binaries[i].Name < l.Binaries[j].Name })
+	sort.Slice(l.Containers, func(i, j int) bool { return l.Containers[i].Name < l.Containers[j].Name })
+	for _, c := range l.Containers {
+		sort.Ints(c.HostPorts)
+	}
+	if l.Binaries == nil {
+		l.Binaries = []LockedBinary{}
+	}
+	if l.Containers == nil {
+		l.Containers = []LockedContainer{}
+	}
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing lockfile %s: %w", path, err)
+	}
+	return nil
+}
+
+func (l *Lockfile) binary(name string) *LockedBinary {
+	for i := range l.Binaries {
+		if l.Binaries[i].Name == name {
+			return &l.Binaries[i]
+		}
+	}
+	return nil
+}
+
+func (l *Lockfile) container(name string) *LockedContainer {
+	for i := range l.Containers {
+		if l.Containers[i].Name == name {
+			return &l.Containers[i]
+		}
+	}
+	return nil
+}
+
+// lockedImage returns the image init runs the container name with: image, or with a lockfile the image of the
+// lockfile by its digest.
+func lockedImage(info initInfo, name, image string) (string, error) {
+	if info.lock == nil {
+		return image, nil
+	}
+	locked := info.lock.container(name)
+	if locked == nil || locked.Digest == "" {
+		return "", fmt.Errorf("the lockfile has no image digest for %s", name)
+	}
+	return locked.PinnedImage(), nil
+}
+
+// verifyLockedChecksum checks the checksum of the archive of binary against the lockfile of info, if any, and returns
+// it.
+func verifyLockedChecksum(info initInfo, binary string, archive downloadedFile) (string, error) {
+	sum := archive.sha256
+	if info.lock == nil {
+		return sum, nil
+	}
+	locked := info.lock.binary(binary)
+	if locked == nil || locked.SHA256 == "" {
+		return "", fmt.Errorf("the lockfile has no checksum for %s", binary)
+	}
+	if locked.SHA256 != sum {
+		return "", fmt.Errorf("the checksum of the downloaded %s archive is %s, the lockfile expects %s", binary, sum, locked.SHA256)
+	}
+	return sum, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// inspectLockedContainer describes the container containerName as a locked container named name.
+func inspectLockedContainer(name, containerName, runtimeCmd string) (LockedContainer, error) {
+	c := LockedContainer{Name: name}
+	out, err := runCmd(runtimeCmd, "inspect", containerName)
+	if err != nil {
+		return c, err
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if err != nil {
+		return c, err
+	}
+	c.Image = details.Config.Image
+	c.HostPorts = details.hostPorts()
+	c.imageID = details.Image
+	out, err = runCmd(runtimeCmd, "image", "inspect", "--format", "{{json .RepoDigests}}", details.Image)
+	if err != nil {
+		return c, fmt.Errorf("error getting the digest of the image %s: %w", c.Image, err)
+	}
+	c.Digest = repoDigest(out, c.Image)
+	return c, nil
+}
+
+// repoDigest returns the digest of image among the repo digests printed by image inspect, as a JSON list of
+// repo@digest, or the first one if none is from the repo of image.
+func repoDigest(out, image string) string {
+	var digests []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &digests); err != nil || len(digests) == 0 {
+		return ""
+	}
+	repo := LockedContainer{Image: image, Digest: ""}.PinnedImage()
+	for _, d := range digests {
+		if strings.HasPrefix(d, repo) {
+			return strings.TrimPrefix(d, repo)
+		}
+	}
+	_, digest, _ := strings.Cut(digests[0], "@")
+	return digest
+}
+
+// CheckLockfile returns the differences between lock and the environment: the version of the installed runtime,
+// and the image digests and ports of the containers. daprRuntimePath is based on the --runtime-path command line
+// flag, as for GetDaprRuntimePath.
+func CheckLockfile(lock *Lockfile, daprRuntimePath, dockerNetwork, containerRuntime string) []LockfileDrift {
+	var drift []LockfileDrift
+	runtimeInfo := GetRuntimeInfo(daprRuntimePath)
+	if runtimeInfo.Version != lock.RuntimeVersion {
+		actual := runtimeInfo.Version
+		if actual == "" {
+			actual = runtimeInfo.Status
+		}
+		drift = append(drift, LockfileDrift{Item: daprRuntimeFilePrefix, Locked: lock.RuntimeVersion, Actual: actual})
+	}
+
+	runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(containerRuntime))
+	for _, locked := range lock.Containers {
+		containerName := profileContainerName(locked.Name, dockerNetwork)
+		actual, err := inspectLockedContainer(locked.Name, containerName, runtimeCmd)
+		if err != nil {
+			drift = append(drift, LockfileDrift{Item: locked.Name, Locked: locked.PinnedImage(), Actual: RuntimeNotInstalled})
+			continue
+		}
+		if actual.Digest != locked.Digest {
+			drift = append(drift, LockfileDrift{Item: locked.Name + " image", Locked: locked.PinnedImage(), Actual: actual.PinnedImage()})
+		}
+		if lockedPorts, actualPorts := formatPorts(locked.HostPorts), formatPorts(actual.HostPorts); lockedPorts != actualPorts {
+			drift = append(drift, LockfileDrift{Item: locked.Name + " ports", Locked: lockedPorts, Actual: actualPorts})
+		}
+	}
+	return drift
+}
+
+func formatPorts(ports []int) string {
+	sorted := append([]int(nil), ports...)
+	sort.Ints(sorted)
+	s := make([]string, 0, len(sorted))
+	for _, p := range sorted {
+		s = append(s, strconv.Itoa(p))
+	}
+	return strings.Join(s, ",")
+}
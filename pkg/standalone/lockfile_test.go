@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockedContainerPinnedImage(t *testing.T) {
+	const digest = "sha256:0123"
+	for image, expected := range map[string]string{
+		"ghcr.io/dapr/placement:1.11.0":          "ghcr.io/dapr/placement@" + digest,
+		"redis:6":                                "redis@" + digest,
+		"openzipkin/zipkin":                      "openzipkin/zipkin@" + digest,
+		"localhost:5000/dapr/placement:1.11.0":   "localhost:5000/dapr/placement@" + digest,
+		"localhost:5000/dapr/placement":          "localhost:5000/dapr/placement@" + digest,
+		"ghcr.io/dapr/placement@sha256:fedcba98": "ghcr.io/dapr/placement@" + digest,
+	} {
+		assert.Equal(t, expected, LockedContainer{Image: image, Digest: digest}.PinnedImage(), image)
+	}
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultLockfileName)
+	lock := &Lockfile{
+		RuntimeVersion: "1.11.0",
+		Binaries: []LockedBinary{
+			{Name: "dashboard", Version: "0.13.0", SHA256: "bb"},
+			{Name: "daprd", Version: "1.11.0", SHA256: "aa"},
+		},
+		Containers: []LockedContainer{
+			{Name: DaprZipkinContainerName, Image: "openzipkin/zipkin", Digest: "sha256:03", HostPorts: []int{9411}},
+			{Name: DaprPlacementContainerName, Image: "ghcr.io/dapr/placement:1.11.0", Digest: "sha256:01", HostPorts: []int{50005}},
+		},
+	}
+	require.NoError(t, writeLockfile(path, lock))
+	first, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	read, err := ReadLockfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "daprd", read.Binaries[0].Name)
+	assert.Equal(t, DaprPlacementContainerName, read.Containers[0].Name)
+	assert.Equal(t, "sha256:03", read.container(DaprZipkinContainerName).Digest)
+	assert.Nil(t, read.container(DaprRedisContainerName))
+
+	// Writing what was read gives the same file.
+	require.NoError(t, writeLockfile(path, read))
+	second, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"binaries": []}`), 0o644))
+	_, err = ReadLockfile(path)
+	assert.ErrorContains(t, err, "has no runtime version")
+}
+
+func TestVerifyLockedChecksum(t *testing.T) {
+	const sum = "44e6542dde90727238e0ef2c926780a565f25d3aab932efe85aaa5cd999d4c16"
+	archive := downloadedFile{path: filepath.Join(t.TempDir(), "daprd.tar.gz"), sha256: sum}
+
+	got, err := verifyLockedChecksum(initInfo{}, "daprd", archive)
+	require.NoError(t, err)
+	assert.Equal(t, sum, got)
+
+	info := initInfo{lock: &Lockfile{Binaries: []LockedBinary{{Name: "daprd", SHA256: sum}}}}
+	_, err = verifyLockedChecksum(info, "daprd", archive)
+	assert.NoError(t, err)
+
+	info.lock.Binaries[0].SHA256 = "00"
+	_, err = verifyLockedChecksum(info, "daprd", archive)
+	assert.ErrorContains(t, err, "the lockfile expects 00")
+
+	_, err = verifyLockedChecksum(info, "placement", archive)
+	assert.EqualError(t, err, "the lockfile has no checksum for placement")
+}
+
+func TestRepoDigest(t *testing.T) {
+	out := `["docker.io/library/redis@sha256:aaaa","redis@sha256:bbbb"]` + "\n"
+	assert.Equal(t, "sha256:bbbb", repoDigest(out, "redis:6"))
+	assert.Equal(t, "sha256:aaaa", repoDigest(out, "ghcr.io/someone/redis:6"))
+	assert.Equal(t, "", repoDigest("[]", "redis:6"))
+	assert.Equal(t, "", repoDigest("null", "redis:6"))
+}
+
+func TestContainerInspectHostPorts(t *testing.T) {
+	details, err := parseContainerInspect([]byte(`[{"NetworkSettings": {"Ports": {
+		"6379/tcp": [{"HostIp": "0.0.0.0", "HostPort": "6379"}, {"HostIp": "::", "HostPort": "6379"}],
+		"9000/tcp": [{"HostIp": "", "HostPort": "9000"}],
+		"9001/tcp": null
+	}}}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []int{6379, 9000}, details.hostPorts())
+}
+
+func TestCheckLockfileRuntime(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+	runtimePath := t.TempDir()
+	lock := &Lockfile{RuntimeVersion: "1.11.0", SlimMode: true}
+
+	assert.Equal(t, []LockfileDrift{{Item: "daprd", Locked: "1.11.0", Actual: RuntimeNotInstalled}}, CheckLockfile(lock, runtimePath, "", "docker"))
+
+	binDir := getDaprBinPath(filepath.Join(runtimePath, DefaultDaprDirName))
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, daprRuntimeFilePrefix), []byte("#!/bin/sh\necho 1.10.0\n"), 0o755))
+	assert.Equal(t, []LockfileDrift{{Item: "daprd", Locked: "1.11.0", Actual: "1.10.0"}}, CheckLockfile(lock, runtimePath, "", "docker"))
+
+	lock.RuntimeVersion = "1.10.0"
+	assert.Empty(t, CheckLockfile(lock, runtimePath, "", "docker"))
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the log sources of an instance.
+const (
+	LogSourceDaprd = "dapr"
+	LogSourceApp   = "app"
+)
+
+// Defaults of the log files of the detached instances.
+const (
+	DefaultLogFileMaxSize = 10 * 1024 * 1024
+	DefaultLogFileBackups = 3
+)
+
+// logTimestampLayout is the layout of the timestamps of the log lines of the detached instances.
+const logTimestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+const logsPollInterval = 250 * time.Millisecond
+
+// TimestampWriter writes the lines written to it to another writer, prefixed with the time they were written, so that
+// the log files of an instance can be interleaved. Incomplete lines are buffered until they are completed.
+type TimestampWriter struct {
+	w    io.Writer
+	now  func() time.Time
+	lock sync.Mutex
+	buf  []byte
+}
+
+// NewTimestampWriter returns a TimestampWriter writing to w.
+func NewTimestampWriter(w io.Writer) *TimestampWriter {
+	return &TimestampWriter{w: w, now: time.Now}
+}
+
+func (t *TimestampWriter) Write(p []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.buf = append(t.buf, p...)
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := append([]byte(t.now().Format(logTimestampLayout)+" "), t.buf[:i+1]...)
+		t.buf = t.buf[i+1:]
+		if _, err := t.w.Write(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// LogSource is a log file of an instance.
+type LogSource struct {
+	Name string
+	Path string
+}
+
+// LogLine is a line of a log file.
+type LogLine struct {
+	Source string
+	Time   time.Time
+	Text   string
+}
+
+// LogsOptions are the options of Logs.
+type LogsOptions struct {
+	// Follow keeps reading the files as they are written, until the context is done.
+	Follow bool
+	// Tail is the number of lines to output before following, or all of them if negative.
+	Tail int
+	// Since limits the output to the lines written in the duration, if not 0.
+	Since       time.Duration
+	RuntimeOnly bool
+	AppOnly     bool
+}
+
+// InstanceLogSources returns the log files of the instance of appID: the files of a detached instance, which stay
+// after it stopped, or the log files of an app of a run file.
+func InstanceLogSources(daprDir, appID string) ([]LogSource, error) {
+	records, err := ReadRunRecords(daprDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.AppID != appID {
+			continue
+		}
+		var sources []LogSource
+		if r.DaprdLogPath != "" {
+			sources = append(sources, LogSource{Name: LogSourceDaprd, Path: r.DaprdLogPath})
+		}
+		if r.AppLogPath != "" {
+			sources = append(sources, LogSource{Name: LogSourceApp, Path: r.AppLogPath})
+		}
+		if len(sources) == 0 {
+			return nil, fmt.Errorf("app id %s was not started with --detach: its logs are streamed to the terminal of its dapr run command", appID)
+		}
+		return sources, nil
+	}
+
+	logsDir := GetRunLogsPath(daprDir, appID)
+	if _, err = os.Stat(logsDir); err != nil {
+		return nil, fmt.Errorf("no logs found for app id %s: only the logs of the instances started with dapr run --detach are kept", appID)
+	}
+	return []LogSource{
+		{Name: LogSourceDaprd, Path: path_filepath.Join(logsDir, "daprd.log")},
+		{Name: LogSourceApp, Path: path_filepath.Join(logsDir, "app.log")},
+	}, nil
+}
+
+// Logs outputs the lines of the log files of sources to emit, interleaved by time. The rotated files are read too.
+func Logs(ctx context.Context, sources []LogSource, opts LogsOptions, emit func(LogLine)) error {
+	var followers []*logFollower
+	var lines []LogLine
+	for _, s := range sources {
+		if (opts.RuntimeOnly && s.Name != LogSourceDaprd) || (opts.AppOnly && s.Name != LogSourceApp) {
+			continue
+		}
+		f := &logFollower{source: s}
+		defer f.close()
+		sourceLines, err := f.readRotated()
+		if err != nil {
+			return err
+		}
+		lines = append(lines, sourceLines...)
+		followers = append(followers, f)
+	}
+	sortLogLines(lines)
+
+	if opts.Since > 0 {
+		since := time.Now().Add(-opts.Since)
+		i := sort.Search(len(lines), func(i int) bool { return !lines[i].Time.Before(since) })
+		lines = lines[i:]
+	}
+	if opts.Tail >= 0 && len(lines) > opts.Tail {
+		lines = lines[len(lines)-opts.Tail:]
+	}
+	for _, l := range lines {
+		emit(l)
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		lines = lines[:0]
+		for _, f := range followers {
+			newLines, err := f.poll()
+			if err != nil {
+				return err
+			}
+			lines = append(lines, newLines...)
+		}
+		sortLogLines(lines)
+		for _, l := range lines {
+			emit(l)
+		}
+	}
+}
+
+func sortLogLines(lines []LogLine) {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+}
+
+// logFollower reads the log files of a source, keeping the current file open to read what is written next.
+type logFollower struct {
+	source  LogSource
+	file    *os.File
+	reader  *bufio.Reader
+	partial string
+	// last is the time of the last line read, for the lines which don't have a timestamp.
+	last time.Time
+}
+
+// readRotated reads the rotated files of the source, oldest first, then the current file.
+func (f *logFollower) readRotated() ([]LogLine, error) {
+	var backups []string
+	for i := 1; ; i++ {
+		backup := fmt.Sprintf("%s.%d", f.source.Path, i)
+		if _, err := os.Stat(backup); err != nil {
+			break
+		}
+		backups = append(backups, backup)
+	}
+	var lines []LogLine
+	for i := len(backups) - 1; i >= 0; i-- {
+		b, err := os.ReadFile(backups[i])
+		if err != nil {
+			return nil, fmt.Errorf("error reading log file: %w", err)
+		}
+		for _, text := range strings.SplitAfter(string(b), "\n") {
+			if text != "" {
+				lines = append(lines, f.parse(text))
+			}
+		}
+	}
+	current, err := f.poll()
+	return append(lines, current...), err
+}
+
+// poll returns the lines written since the last call, switching to the new file once the current one is rotated.
+func (f *logFollower) poll() ([]LogLine, error) {
+	if f.file == nil {
+		file, err := os.Open(f.source.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading log file: %w", err)
+		}
+		f.file, f.reader = file, bufio.NewReader(file)
+	}
+
+	var lines []LogLine
+	for {
+		text, err := f.reader.ReadString('\n')
+		f.partial += text
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return lines, fmt.Errorf("error reading log file: %w", err)
+			}
+			break
+		}
+		lines = append(lines, f.parse(f.partial))
+		f.partial = ""
+	}
+
+	// Once the current file is drained, the new file is read if it was rotated.
+	current, err := f.file.Stat()
+	if err != nil {
+		return lines, nil
+	}
+	if latest, err := os.Stat(f.source.Path); err == nil && !os.SameFile(current, latest) {
+		f.close()
+		f.partial = ""
+		newLines, err := f.poll()
+		return append(lines, newLines...), err
+	}
+	return lines, nil
+}
+
+func (f *logFollower) parse(text string) LogLine {
+	text = strings.TrimRight(text, "\r\n")
+	if ts, rest, ok := strings.Cut(text, " "); ok {
+		if t, err := time.Parse(logTimestampLayout, ts); err == nil {
+			f.last = t
+			text = rest
+		}
+	}
+	return LogLine{Source: f.source.Name, Time: f.last, Text: text}
+}
+
+func (f *logFollower) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file, f.reader = nil, nil
+	}
+}
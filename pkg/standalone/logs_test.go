@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewTimestampWriter(&buf)
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, err := w.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	_, err = w.Write([]byte("ond\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "2023-05-01T10:00:00.000Z first\n2023-05-01T10:00:01.000Z second\n", buf.String())
+}
+
+func TestLogs(t *testing.T) {
+	dir := t.TempDir()
+	ts := func(minutesAgo int) string {
+		return time.Now().Add(-time.Duration(minutesAgo) * time.Minute).Format(logTimestampLayout)
+	}
+	daprdLog, appLog := filepath.Join(dir, "daprd.log"), filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(daprdLog+".1", []byte(ts(30)+" dapr 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(daprdLog, []byte(ts(20)+" dapr 2\n"+ts(5)+" dapr 3\n"), 0o600))
+	require.NoError(t, os.WriteFile(appLog, []byte(ts(25)+" app 1\ncontinued\n"+ts(1)+" app 2\n"), 0o600))
+	sources := []LogSource{{Name: LogSourceDaprd, Path: daprdLog}, {Name: LogSourceApp, Path: appLog}}
+
+	collect := func(opts LogsOptions) []string {
+		var lines []string
+		require.NoError(t, Logs(context.Background(), sources, opts, func(l LogLine) {
+			lines = append(lines, l.Source+": "+l.Text)
+		}))
+		return lines
+	}
+
+	assert.Equal(t, []string{"dapr: dapr 1", "app: app 1", "app: continued", "dapr: dapr 2", "dapr: dapr 3", "app: app 2"}, collect(LogsOptions{Tail: -1}))
+	assert.Equal(t, []string{"dapr: dapr 3", "app: app 2"}, collect(LogsOptions{Tail: 2}))
+	assert.Equal(t, []string{"dapr: dapr 3", "app: app 2"}, collect(LogsOptions{Tail: -1, Since: 10 * time.Minute}))
+	assert.Equal(t, []string{"dapr: dapr 1", "dapr: dapr 2", "dapr: dapr 3"}, collect(LogsOptions{Tail: -1, RuntimeOnly: true}))
+	assert.Equal(t, []string{"app: app 2"}, collect(LogsOptions{Tail: 1, AppOnly: true}))
+}
+
+func TestLogsFollow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := print.OpenRotatingFile(path, 40, 2)
+	require.NoError(t, err)
+	defer f.Close()
+	w := NewTimestampWriter(f)
+	_, err = w.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	var lock sync.Mutex
+	var lines []string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Logs(ctx, []LogSource{{Name: LogSourceApp, Path: path}}, LogsOptions{Follow: true, Tail: -1}, func(l LogLine) {
+			lock.Lock()
+			defer lock.Unlock()
+			lines = append(lines, l.Text)
+		})
+	}()
+
+	// Each line fills most of the file, so that it is rotated before every write.
+	expected := []string{"before"}
+	for _, line := range []string{"written while following", "written after rotation", "and another one"} {
+		time.Sleep(2 * logsPollInterval)
+		_, err = w.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+		expected = append(expected, line)
+	}
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(lines) == len(expected)
+	}, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, expected, lines)
+}
+
+func TestInstanceLogSources(t *testing.T) {
+	daprDir := t.TempDir()
+	fakePIDs(t, 100, 200)
+	require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "console", DaprdPID: 100}))
+	require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "files", DaprdPID: 200, AppLogPath: "/logs/app.log"}))
+	require.NoError(t, os.MkdirAll(GetRunLogsPath(daprDir, "stopped"), 0o755))
+
+	_, err := InstanceLogSources(daprDir, "console")
+	assert.ErrorContains(t, err, "was not started with --detach")
+
+	sources, err := InstanceLogSources(daprDir, "files")
+	require.NoError(t, err)
+	assert.Equal(t, []LogSource{{Name: LogSourceApp, Path: "/logs/app.log"}}, sources)
+
+	sources, err = InstanceLogSources(daprDir, "stopped")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(GetRunLogsPath(daprDir, "stopped"), "daprd.log"), sources[0].Path)
+
+	_, err = InstanceLogSources(daprDir, "unknown")
+	assert.ErrorContains(t, err, "no logs found")
+}
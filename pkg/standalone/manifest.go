@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	installManifestFileName = "install.json"
+	// installManifestSchemaVersion is the version of the format of the install manifest written by this CLI.
+	installManifestSchemaVersion = 2
+)
+
+// InstallManifest records what init installed in the dapr install dir, and is updated by upgrade and uninstall, so
+// that the other commands don't have to guess it.
+type InstallManifest struct {
+	// SchemaVersion is the version of the format of the manifest. The manifests which predate it are version 1.
+	SchemaVersion  int    `json:"schemaVersion"`
+	RuntimeVersion string `json:"runtimeVersion"`
+	// RuntimeBinary is the path of the installed daprd binary.
+	RuntimeBinary string `json:"runtimeBinary"`
+	// RuntimeBinarySHA256 is the SHA-256 checksum of RuntimeBinary when it was installed, for init to tell that it is
+	// already up to date.
+	RuntimeBinarySHA256 string `json:"runtimeBinarySHA256,omitempty"`
+	// Platform is the GOOS/GOARCH platform the binaries and the container images were installed for.
+	Platform string `json:"platform,omitempty"`
+	// SlimMode is set if init ran the placement binary instead of containers.
+	SlimMode bool `json:"slimMode"`
+	// ComponentsProvider is the provider of the state store and pubsub components, Redis if it is empty.
+	ComponentsProvider string `json:"componentsProvider,omitempty"`
+	// PlacementImage is the image of the placement container, empty in slim mode.
+	PlacementImage string `json:"placementImage,omitempty"`
+	// Containers are the containers set up by init.
+	Containers       []ManifestContainer `json:"containers,omitempty"`
+	DockerNetwork    string              `json:"dockerNetwork,omitempty"`
+	ContainerRuntime string              `json:"containerRuntime,omitempty"`
+	// DockerEndpoint is the DOCKER_HOST the containers were set up with, empty for the default one.
+	DockerEndpoint string `json:"dockerEndpoint,omitempty"`
+	ComponentsPath string `json:"componentsPath,omitempty"`
+	ConfigPath     string `json:"configPath,omitempty"`
+	// InitSystem runs the Services of slim mode, InitSystemNone if init started none.
+	InitSystem string            `json:"initSystem,omitempty"`
+	Services   []ManifestService `json:"services,omitempty"`
+	// PreviousVersion is the runtime version active before the last upgrade, kept for upgrade --rollback.
+	PreviousVersion string    `json:"previousVersion,omitempty"`
+	InstalledAt     time.Time `json:"installedAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	// History records the inits, upgrades, rollbacks and uninstalls of the install, oldest first.
+	History []InstallEvent `json:"history,omitempty"`
+	// ImageRegistry and ImageVariant are the --image-registry and --image-variant the images were pulled with.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	ImageVariant  string `json:"imageVariant,omitempty"`
+	// UnhardenedRedis is set if the Redis container was run unhardened, with --unhardened.
+	UnhardenedRedis bool `json:"unhardenedRedis,omitempty"`
+	// Ports are the host ports the containers were set up with, the default ones for the manifests which predate it.
+	Ports *InstallPorts `json:"ports,omitempty"`
+}
+
+// ManifestContainer is a container set up by init.
+type ManifestContainer struct {
+	// Name is the name of the container, with the suffix of the docker network.
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// HostPorts are the ports the container is published on, none in a docker network.
+	HostPorts []int `json:"hostPorts,omitempty"`
+	// Digest is the digest the image resolved to when it was pulled, sha256:<hex>.
+	Digest string `json:"digest,omitempty"`
+	// ImageID is the ID of the image the container was created from.
+	ImageID string `json:"imageId,omitempty"`
+}
+
+// ImageDigestChange is a change of the image digest recorded for a container.
+type ImageDigestChange struct {
+	Container string `json:"container"`
+	Previous  string `json:"previous,omitempty"`
+	Digest    string `json:"digest"`
+}
+
+// drift describes how the image of the container, running the image imageID, differs from the recorded one, or
+// returns an empty string if it doesn't, or if no image was recorded.
+func (c *ManifestContainer) drift(imageID string) string {
+	if c.ImageID == "" || imageID == "" || c.ImageID == imageID {
+		return ""
+	}
+	recorded := c.Image
+	if c.Digest != "" {
+		recorded = LockedContainer{Image: c.Image, Digest: c.Digest}.PinnedImage()
+	}
+	return fmt.Sprintf("runs the image %s instead of the recorded %s", shortImageID(imageID), recorded)
+}
+
+// shortImageID returns the first 12 characters of the hex of imageID, as the container runtimes print it.
+func shortImageID(imageID string) string {
+	id := strings.TrimPrefix(imageID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+// repinContainers records the images the containers of m run now, the placement one only unless all is set, and
+// returns the changes of their digests.
+func (m *InstallManifest) repinContainers(runtimeCmd string, all bool) []ImageDigestChange {
+	var changes []ImageDigestChange
+	placementName := profileContainerName(DaprPlacementContainerName, m.DockerNetwork)
+	for i := range m.Containers {
+		c := &m.Containers[i]
+		if !all && c.Name != placementName {
+			continue
+		}
+		locked, err := inspectLockedContainer(c.Name, c.Name, runtimeCmd)
+		if err != nil {
+			continue
+		}
+		if locked.Digest != c.Digest {
+			changes = append(changes, ImageDigestChange{Container: c.Name, Previous: c.Digest, Digest: locked.Digest})
+		}
+		c.Image, c.Digest, c.ImageID = locked.Image, locked.Digest, locked.imageID
+	}
+	return changes
+}
+
+// Actions of the install events.
+const (
+	InstallActionInit      = "init"
+	InstallActionUpgrade   = "upgrade"
+	InstallActionDowngrade = "downgrade"
+	InstallActionRollback  = "rollback"
+	InstallActionUninstall = "uninstall"
+	// InstallActionMigrate is the adoption of an install made by an older CLI, see MigrateLegacyInstall.
+	InstallActionMigrate = "migrate"
+)
+
+// InstallEvent is a change of the runtime version of an install.
+type InstallEvent struct {
+	Action          string `json:"action"`
+	RuntimeVersion  string `json:"runtimeVersion"`
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// Checksums are the SHA-256 checksums of the archives of the binaries downloaded, by binary name.
+	Checksums map[string]string `json:"checksums,omitempty"`
+	At        time.Time         `json:"at"`
+}
+
+// checksums returns the checksums recorded for the archives of the binaries of version, the latest ones if it was
+// downloaded more than once, or nil if there are none.
+func (m *InstallManifest) checksums(version string) map[string]string {
+	for i := len(m.History) - 1; i >= 0; i-- {
+		if e := m.History[i]; e.RuntimeVersion == version && len(e.Checksums) > 0 {
+			return e.Checksums
+		}
+	}
+	return nil
+}
+
+// container returns the container of init named name, without the suffix of the docker network, or nil if there is
+// none.
+func (m *InstallManifest) container(name string) *ManifestContainer {
+	containerName := profileContainerName(name, m.DockerNetwork)
+	for i := range m.Containers {
+		if m.Containers[i].Name == containerName {
+			return &m.Containers[i]
+		}
+	}
+	return nil
+}
+
+// installPorts returns the host ports the containers were set up with.
+func (m *InstallManifest) installPorts() InstallPorts {
+	if m.Ports == nil {
+		return InstallPorts{}.withDefaults()
+	}
+	return *m.Ports
+}
+
+// PlacementHostPort returns the host port the placement container is published on, or 0 if it isn't, in slim mode
+// or in a docker network.
+func (m *InstallManifest) PlacementHostPort() int {
+	if c := m.container(DaprPlacementContainerName); c != nil && len(c.HostPorts) > 0 {
+		return c.HostPorts[0]
+	}
+	return 0
+}
+
+// GetInstallManifestPath returns the path of the install manifest of the dapr install dir.
+func GetInstallManifestPath(daprDir string) string {
+	return path_filepath.Join(daprDir, installManifestFileName)
+}
+
+// LoadInstallManifest reads the install manifest of the dapr install dir, as ReadInstallManifest. daprRuntimePath is
+// based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func LoadInstallManifest(daprRuntimePath string) (*InstallManifest, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	return ReadInstallManifest(daprDir)
+}
+
+// ReadInstallManifest reads the install manifest of the dapr install dir, migrated to the current schema version. It
+// returns nil if there is none, for instance if the install predates the manifest.
+func ReadInstallManifest(daprDir string) (*InstallManifest, error) {
+	path := GetInstallManifestPath(daprDir)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading install manifest %s: %w", path, err)
+	}
+	var m InstallManifest
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("error parsing install manifest %s: %w", path, err)
+	}
+	if err = migrateInstallManifest(&m, daprDir); err != nil {
+		return nil, fmt.Errorf("error reading install manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// migrateInstallManifest migrates m, read from the install manifest of daprDir, to the current schema version.
+func migrateInstallManifest(m *InstallManifest, daprDir string) error {
+	if m.SchemaVersion > installManifestSchemaVersion {
+		return fmt.Errorf("its schema version %d is newer than the version %d this CLI supports, upgrade the CLI", m.SchemaVersion, installManifestSchemaVersion)
+	}
+	if m.SchemaVersion < 2 {
+		// Version 1 only recorded the runtime and the placement image, the rest is what init did then.
+		m.SlimMode = m.PlacementImage == ""
+		if !m.SlimMode {
+			m.Containers = []ManifestContainer{{Name: DaprPlacementContainerName, Image: m.PlacementImage, HostPorts: []int{defaultPlacementHostPort()}}}
+		}
+		m.ContainerRuntime = string(utils.DOCKER)
+		m.ComponentsPath = GetDaprComponentsPath(daprDir)
+		m.ConfigPath = GetDaprConfigPath(daprDir)
+		m.UpdatedAt = m.InstalledAt
+	}
+	m.SchemaVersion = installManifestSchemaVersion
+	return nil
+}
+
+// writeInstallManifest writes the install manifest of the dapr install dir, in the current schema version, and sets
+// its update time.
+func writeInstallManifest(daprDir string, m *InstallManifest) error {
+	m.SchemaVersion = installManifestSchemaVersion
+	m.UpdatedAt = time.Now().UTC()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := GetInstallManifestPath(daprDir)
+	if err = os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing install manifest %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInstallManifestMigratesVersion1(t *testing.T) {
+	daprDir := t.TempDir()
+	v1 := `{"runtimeVersion":"1.11.0","runtimeBinary":"/dapr/bin/daprd","placementImage":"daprio/dapr:1.11.0","installedAt":"2023-06-01T10:00:00Z"}`
+	require.NoError(t, os.WriteFile(GetInstallManifestPath(daprDir), []byte(v1), 0o644))
+
+	m, err := ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.Equal(t, installManifestSchemaVersion, m.SchemaVersion)
+	assert.False(t, m.SlimMode)
+	assert.Equal(t, []ManifestContainer{{Name: DaprPlacementContainerName, Image: "daprio/dapr:1.11.0", HostPorts: []int{defaultPlacementHostPort()}}}, m.Containers)
+	assert.Equal(t, defaultPlacementHostPort(), m.PlacementHostPort())
+	assert.Equal(t, "docker", m.ContainerRuntime)
+	assert.Equal(t, GetDaprComponentsPath(daprDir), m.ComponentsPath)
+	assert.Equal(t, GetDaprConfigPath(daprDir), m.ConfigPath)
+	assert.Equal(t, m.InstalledAt, m.UpdatedAt)
+
+	// Slim installs recorded no placement image.
+	require.NoError(t, os.WriteFile(GetInstallManifestPath(daprDir), []byte(`{"runtimeVersion":"1.11.0"}`), 0o644))
+	m, err = ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.True(t, m.SlimMode)
+	assert.Empty(t, m.Containers)
+	assert.Equal(t, 0, m.PlacementHostPort())
+}
+
+func TestReadInstallManifestNewerSchema(t *testing.T) {
+	daprDir := t.TempDir()
+	require.NoError(t, os.WriteFile(GetInstallManifestPath(daprDir), []byte(`{"schemaVersion":99,"runtimeVersion":"2.0.0"}`), 0o644))
+
+	_, err := ReadInstallManifest(daprDir)
+	assert.ErrorContains(t, err, "schema version 99 is newer")
+}
+
+func TestInstallManifestRoundTrip(t *testing.T) {
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	m, err := LoadInstallManifest(runtimePath)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	installedAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	written := &InstallManifest{
+		RuntimeVersion:   "1.11.0",
+		RuntimeBinary:    filepath.Join(daprDir, "bin", daprRuntimeFilePrefix),
+		PlacementImage:   "daprio/dapr:1.11.0",
+		DockerNetwork:    "mynet",
+		ContainerRuntime: "podman",
+		Containers: []ManifestContainer{
+			{Name: DaprPlacementContainerName + "_mynet", Image: "daprio/dapr:1.11.0"},
+			{Name: DaprRedisContainerName + "_mynet", Image: "redis:6"},
+		},
+		InstalledAt: installedAt,
+	}
+	require.NoError(t, writeInstallManifest(daprDir, written))
+	assert.Equal(t, installManifestSchemaVersion, written.SchemaVersion)
+	assert.True(t, written.UpdatedAt.After(installedAt))
+
+	read, err := LoadInstallManifest(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, written.Containers, read.Containers)
+	assert.Equal(t, installedAt, read.InstalledAt)
+	// The containers are run in the docker network, so they aren't published on the host.
+	require.NotNil(t, read.container(DaprRedisContainerName))
+	assert.Equal(t, "redis:6", read.container(DaprRedisContainerName).Image)
+	assert.Nil(t, read.container(DaprZipkinContainerName))
+	assert.Equal(t, 0, read.PlacementHostPort())
+}
+
+func TestRecordUninstall(t *testing.T) {
+	daprDir := t.TempDir()
+	manifest := &InstallManifest{
+		RuntimeVersion: "1.11.0",
+		PlacementImage: "daprio/dapr:1.11.0",
+		Containers: []ManifestContainer{
+			{Name: DaprPlacementContainerName, Image: "daprio/dapr:1.11.0"},
+			{Name: DaprRedisContainerName, Image: "redis:6"},
+		},
+	}
+	report := &UninstallReport{RemovedContainers: []string{DaprPlacementContainerName}}
+	require.NoError(t, recordUninstall(daprDir, manifest, report))
+
+	read, err := ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.Empty(t, read.RuntimeVersion)
+	assert.Equal(t, []ManifestContainer{{Name: DaprRedisContainerName, Image: "redis:6"}}, read.Containers)
+	require.Len(t, read.History, 1)
+	assert.Equal(t, InstallActionUninstall, read.History[0].Action)
+	assert.Equal(t, "1.11.0", read.History[0].PreviousVersion)
+}
+
+func TestManifestContainerDrift(t *testing.T) {
+	c := ManifestContainer{Name: DaprRedisContainerName, Image: "docker.io/redis:6", Digest: "sha256:aaa", ImageID: "sha256:0123456789abcdef"}
+	assert.Empty(t, c.drift("sha256:0123456789abcdef"))
+	assert.Empty(t, c.drift(""))
+	assert.Equal(t, "runs the image fedcba987654 instead of the recorded docker.io/redis@sha256:aaa", c.drift("sha256:fedcba9876543210"))
+
+	// The containers recorded before the digests have nothing to drift from.
+	assert.Empty(t, (&ManifestContainer{Name: DaprRedisContainerName, Image: "docker.io/redis:6"}).drift("sha256:fedcba9876543210"))
+}
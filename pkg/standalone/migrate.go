@@ -0,0 +1,375 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// Actions of the changes made by MigrateLegacyInstall.
+const (
+	// MigrationCopied is a legacy binary copied into the bin dir of the install dir.
+	MigrationCopied = "copied"
+	// MigrationRenamed is a legacy container renamed to the name of its service.
+	MigrationRenamed = "renamed"
+	// MigrationRecorded is the install manifest written for the adopted install.
+	MigrationRecorded = "recorded"
+)
+
+// legacyBinDirs returns the directories older CLIs installed the runtime binaries in, instead of the bin dir of the
+// install dir.
+var legacyBinDirs = func() []string {
+	if runtime.GOOS == daprWindowsOS {
+		return []string{`C:\dapr`}
+	}
+	return []string{"/usr/local/bin"}
+}
+
+// legacyServices are the services of the containers set up by older CLIs: the images they ran, matched as
+// substrings, and the host ports they were published on, which tell them from the other containers of the images.
+var legacyServices = []struct {
+	name   string
+	images []string
+	ports  []int
+}{
+	{name: DaprPlacementContainerName, images: []string{"daprio/dapr", "dapr/dapr", "/placement"}, ports: []int{50005, 6050}},
+	{name: DaprRedisContainerName, images: []string{"redis"}, ports: []int{6379}},
+	{name: DaprZipkinContainerName, images: []string{"zipkin"}, ports: []int{zipkinContainerPort}},
+}
+
+// MigrationChange is a change made by MigrateLegacyInstall, or which it would make with DryRun.
+type MigrationChange struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// MigrationReport describes the migration of a legacy install.
+type MigrationReport struct {
+	InstallDir string `json:"installDir"`
+	// Legacy is set if an install without a manifest was found.
+	Legacy bool `json:"legacy"`
+	DryRun bool `json:"dryRun,omitempty"`
+	// Changes are what the migration changed, none if there was nothing to migrate, or if adopting the install
+	// wasn't safe.
+	Changes []MigrationChange `json:"changes,omitempty"`
+	// Notes are what the migration left as it was, such as the containers it can't label.
+	Notes []string `json:"notes,omitempty"`
+	// Recommendation is what to do instead when adopting the install isn't safe.
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+// MigrateOptions are the options of MigrateLegacyInstall.
+type MigrateOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath  string
+	ContainerRuntime string
+	// DryRun reports the changes without making them.
+	DryRun bool
+	// WaitForLock waits for a concurrently running init, uninstall or upgrade to finish instead of failing.
+	WaitForLock bool
+}
+
+// legacyContainer is a container set up by an older CLI, adopted as the container of its service.
+type legacyContainer struct {
+	name string
+	// service is the name of the container of the service, which the container is renamed to if it differs.
+	service string
+	record  ManifestContainer
+}
+
+// MigrateLegacyInstall adopts an install made by an older CLI, which wrote no install manifest, so that the
+// commands relying on the manifest don't take it for no install: the runtime binaries of the legacy bin dirs are
+// copied into the bin dir of the install dir, the unlabeled containers running the images of placement, Redis and
+// Zipkin on their known ports are renamed to the names of their services, and the manifest recording them is
+// written. Nothing is changed if adopting the install isn't safe, for instance if the placement container runs
+// another version than daprd, and a clean reinstall is recommended instead. An install with a manifest has nothing
+// to migrate, which makes running it again a no-op. The changes are only made while holding the install lock.
+func MigrateLegacyInstall(opts MigrateOptions) (*MigrationReport, error) {
+	installDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	report := &MigrationReport{InstallDir: installDir, DryRun: opts.DryRun}
+	manifest, err := ReadInstallManifest(installDir)
+	if err != nil || manifest != nil {
+		return report, err
+	}
+
+	binDir := getDaprBinPath(installDir)
+	daprd, legacyDaprd := findLegacyBinary(binDir, daprRuntimeFilePrefix)
+	if daprd == "" {
+		return report, nil
+	}
+	if !opts.DryRun {
+		unlock, lockErr := acquireInstallLock(context.Background(), installDir, opts.WaitForLock)
+		if lockErr != nil {
+			return report, lockErr
+		}
+		defer unlock()
+		// An init may have installed over the legacy install while waiting for the lock.
+		if manifest, err = ReadInstallManifest(installDir); err != nil || manifest != nil {
+			return report, err
+		}
+	}
+	report.Legacy = true
+	const reinstall = "reinstall with `dapr uninstall --all` and `dapr init`"
+	version, err := runtimeBinaryVersion(daprd)
+	if err != nil {
+		report.Recommendation = fmt.Sprintf("%s %s, %s", daprd, err, reinstall)
+		return report, nil
+	}
+	version = strings.TrimPrefix(version, "v")
+
+	runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(opts.ContainerRuntime))
+	containers, err := findLegacyContainers(runtimeCmd)
+	placement, legacyPlacement := findLegacyBinary(binDir, placementServiceFilePrefix)
+	var unsafe *legacyAdoptionError
+	switch {
+	case errors.As(err, &unsafe):
+		report.Recommendation = fmt.Sprintf("%s, %s", unsafe.reason, reinstall)
+		return report, nil
+	case err != nil && placement == "":
+		// Without the containers, the install could be taken for one of slim mode.
+		report.Recommendation = fmt.Sprintf("the containers can't be listed with %s: %s, start it and run `dapr migrate` again", runtimeCmd, err)
+		return report, nil
+	}
+	var placementImage string
+	for _, c := range containers {
+		if c.service != DaprPlacementContainerName {
+			continue
+		}
+		placementImage = c.record.Image
+		if tag := imageTag(placementImage); tag != "" && tag != latestVersion && tag != version {
+			report.Recommendation = fmt.Sprintf("the placement container %s runs %s while daprd is %s, %s", c.name, tag, version, reinstall)
+			return report, nil
+		}
+	}
+	slimMode := placementImage == ""
+	if !slimMode {
+		placement, legacyPlacement = "", false
+	}
+
+	apply := func(change MigrationChange, do func() error) error {
+		if !opts.DryRun {
+			if err := do(); err != nil {
+				return err
+			}
+		}
+		report.Changes = append(report.Changes, change)
+		return nil
+	}
+	runtimeBinary := binaryFilePathWithDir(binDir, daprRuntimeFilePrefix)
+	for _, b := range []struct {
+		path   string
+		legacy bool
+		target string
+	}{
+		{path: daprd, legacy: legacyDaprd, target: runtimeBinary},
+		{path: placement, legacy: legacyPlacement, target: binaryFilePathWithDir(binDir, placementServiceFilePrefix)},
+	} {
+		if !b.legacy {
+			continue
+		}
+		if err = apply(MigrationChange{Action: MigrationCopied, Name: path_filepath.Base(b.path), Detail: fmt.Sprintf("from %s to %s", b.path, b.target)}, func() error {
+			return copyFile(b.path, b.target)
+		}); err != nil {
+			return report, fmt.Errorf("error copying %s: %w", b.path, err)
+		}
+		report.Notes = append(report.Notes, fmt.Sprintf("%s is left in place, remove it once nothing else uses it", b.path))
+	}
+	for _, c := range containers {
+		if c.name != c.service {
+			if err = apply(MigrationChange{Action: MigrationRenamed, Name: c.name, Detail: "to " + c.service}, func() error {
+				_, err := runCmd(runtimeCmd, "rename", c.name, c.service)
+				return err
+			}); err != nil {
+				return report, fmt.Errorf("error renaming the container %s: %w", c.name, err)
+			}
+		}
+		report.Notes = append(report.Notes, fmt.Sprintf("%s stays without the %s label, as %s can't label an existing container", c.service, profileLabel, runtimeCmd))
+	}
+
+	now := time.Now().UTC()
+	manifest = &InstallManifest{
+		RuntimeVersion:   version,
+		RuntimeBinary:    runtimeBinary,
+		Platform:         hostPlatform(),
+		SlimMode:         slimMode,
+		PlacementImage:   placementImage,
+		ContainerRuntime: runtimeCmd,
+		ComponentsPath:   GetDaprComponentsPath(installDir),
+		ConfigPath:       GetDaprConfigPath(installDir),
+		InstalledAt:      now,
+		History:          []InstallEvent{{Action: InstallActionMigrate, RuntimeVersion: version, At: now}},
+	}
+	for _, c := range containers {
+		manifest.Containers = append(manifest.Containers, c.record)
+	}
+	detail := fmt.Sprintf("runtime %s in slim mode", version)
+	if !slimMode {
+		detail = fmt.Sprintf("runtime %s with %d container(s)", version, len(containers))
+	}
+	if err = apply(MigrationChange{Action: MigrationRecorded, Name: installManifestFileName, Detail: detail}, func() error {
+		if err := os.MkdirAll(installDir, 0o755); err != nil {
+			return err
+		}
+		// Without a checksum the next init can't tell that the install is up to date, which isn't worth failing for.
+		manifest.RuntimeBinarySHA256, _ = fileSHA256(runtimeBinary)
+		return writeInstallManifest(installDir, manifest)
+	}); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// findLegacyBinary returns the path of the binary named name, in binDir or else in the legacy bin dirs, and whether
+// it is in one of the latter, or an empty path if there is none.
+func findLegacyBinary(binDir, name string) (string, bool) {
+	if path := binaryFilePathWithDir(binDir, name); isFile(path) {
+		return path, false
+	}
+	for _, dir := range legacyBinDirs() {
+		if path := binaryFilePathWithDir(dir, name); isFile(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+// legacyAdoptionError is returned by findLegacyContainers when the containers can't be adopted safely.
+type legacyAdoptionError struct {
+	reason string
+}
+
+func (e *legacyAdoptionError) Error() string {
+	return e.reason
+}
+
+// findLegacyContainers returns the containers of the legacy install, sorted by service: the ones running the images
+// of the legacyServices on their known ports, without the profile label of the current CLIs, which are the only
+// ones of their service and can take its name.
+func findLegacyContainers(runtimeCmd string) ([]legacyContainer, error) {
+	out, err := runCmd(runtimeCmd, "ps", "--all", "--format", "{{.Names}}\t{{.Image}}")
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	candidates := map[string][]legacyContainer{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		name, image, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		if name == "" {
+			continue
+		}
+		names[name] = true
+		for _, s := range legacyServices {
+			if !containsAny(image, s.images) {
+				continue
+			}
+			c, ok, err := inspectLegacyContainer(name, runtimeCmd, s.ports)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				c.service = s.name
+				candidates[s.name] = append(candidates[s.name], c)
+			}
+			break
+		}
+	}
+
+	var containers []legacyContainer
+	for _, s := range legacyServices {
+		found := candidates[s.name]
+		switch {
+		case len(found) == 0:
+			continue
+		case len(found) > 1:
+			others := make([]string, 0, len(found))
+			for _, c := range found {
+				others = append(others, c.name)
+			}
+			sort.Strings(others)
+			return nil, &legacyAdoptionError{reason: fmt.Sprintf("the containers %s all look like the legacy %s", strings.Join(others, ", "), s.name)}
+		}
+		c := found[0]
+		if c.name != c.service && names[c.service] {
+			return nil, &legacyAdoptionError{reason: fmt.Sprintf("the legacy container %s can't be renamed to %s, which is the name of another container", c.name, c.service)}
+		}
+		c.record.Name = c.service
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// inspectLegacyContainer inspects the container name, and reports whether it is a legacy container published on one
+// of ports.
+func inspectLegacyContainer(name, runtimeCmd string, ports []int) (legacyContainer, bool, error) {
+	c := legacyContainer{name: name}
+	out, err := runCmd(runtimeCmd, "inspect", name)
+	if err != nil {
+		return c, false, fmt.Errorf("error inspecting the container %s: %w", name, err)
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if err != nil {
+		return c, false, err
+	}
+	if profile, ok := details.Config.Labels[profileLabel]; ok && profile != "" {
+		// The containers of the current CLIs belong to an install with a manifest, of this profile or another.
+		return c, false, nil
+	}
+	hostPorts := details.hostPorts()
+	published := false
+	for _, port := range ports {
+		published = published || utils.Contains(hostPorts, port)
+	}
+	c.record = ManifestContainer{Image: details.Config.Image, HostPorts: hostPorts, ImageID: details.Image}
+	return c, published, nil
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageTag returns the tag of image without its v prefix, or an empty string if it has none.
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// The port of a registry host isn't a tag.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return strings.TrimPrefix(image[i+1:], "v")
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLegacyInstall writes a daprd printing version in a legacy bin dir, and answers ps and inspect for the
+// containers, by name, with their image and inspect output. It returns the runtime path and the renames.
+func fakeLegacyInstall(t *testing.T, version string, containers map[string][2]string) (string, *[]string) {
+	t.Helper()
+	legacyDir := t.TempDir()
+	previousDirs := legacyBinDirs
+	t.Cleanup(func() { legacyBinDirs = previousDirs })
+	legacyBinDirs = func() []string { return []string{legacyDir} }
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, daprRuntimeFilePrefix), []byte("#!/bin/sh\necho "+version+"\n"), 0o755))
+
+	var renames []string
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(_ string, args ...string) (string, error) {
+		switch args[0] {
+		case "ps":
+			var lines []string
+			for name, c := range containers {
+				lines = append(lines, name+"\t"+c[0])
+			}
+			return strings.Join(lines, "\n") + "\n", nil
+		case "inspect":
+			if c, ok := containers[args[1]]; ok {
+				return c[1], nil
+			}
+			return "[]", errors.New("no such object")
+		case "rename":
+			renames = append(renames, strings.Join(args[1:], " "))
+			containers[args[2]] = containers[args[1]]
+			delete(containers, args[1])
+		}
+		return "", nil
+	}
+	return t.TempDir(), &renames
+}
+
+func legacyInspect(image string, port string, labels string) string {
+	return `[{"Image": "sha256:111", "State": {"Running": true}, "Config": {"Image": "` + image + `", "Labels": {` + labels + `}}, "NetworkSettings": {"Ports": {"` + port + `/tcp": [{"HostIp": "0.0.0.0", "HostPort": "` + port + `"}]}}}]`
+}
+
+func TestMigrateLegacyInstall(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+
+	t.Run("nothing to migrate", func(t *testing.T) {
+		previousDirs := legacyBinDirs
+		t.Cleanup(func() { legacyBinDirs = previousDirs })
+		legacyBinDirs = func() []string { return []string{t.TempDir()} }
+		report, err := MigrateLegacyInstall(MigrateOptions{DaprRuntimePath: t.TempDir()})
+		require.NoError(t, err)
+		assert.False(t, report.Legacy)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("adopted", func(t *testing.T) {
+		runtimePath, renames := fakeLegacyInstall(t, "1.11.0", map[string][2]string{
+			"quirky_bell":           {"daprio/dapr:1.11.0", legacyInspect("daprio/dapr:1.11.0", "50005", "")},
+			DaprRedisContainerName:  {"redis:6", legacyInspect("redis:6", "6379", "")},
+			"other_redis":           {"redis:7", legacyInspect("redis:7", "7000", "")},
+			DaprZipkinContainerName: {"openzipkin/zipkin", legacyInspect("openzipkin/zipkin", "9411", `"`+profileLabel+`": "default"`)},
+		})
+		opts := MigrateOptions{DaprRuntimePath: runtimePath, ContainerRuntime: "docker", DryRun: true}
+		installDir := filepath.Join(runtimePath, DefaultDaprDirName)
+
+		report, err := MigrateLegacyInstall(opts)
+		require.NoError(t, err)
+		assert.True(t, report.Legacy)
+		require.Len(t, report.Changes, 3)
+		assert.Equal(t, []string{MigrationCopied, MigrationRenamed, MigrationRecorded}, []string{report.Changes[0].Action, report.Changes[1].Action, report.Changes[2].Action})
+		assert.Empty(t, *renames)
+		assert.NoFileExists(t, GetInstallManifestPath(installDir))
+
+		// The install is only changed while holding the lock.
+		opts.DryRun = false
+		require.NoError(t, os.MkdirAll(installDir, 0o755))
+		lockPath := writeLockFile(t, installDir, os.Getppid())
+		_, err = MigrateLegacyInstall(opts)
+		var lockedErr *InstallLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Empty(t, *renames)
+		require.NoError(t, os.Remove(lockPath))
+
+		report, err = MigrateLegacyInstall(opts)
+		require.NoError(t, err)
+		require.Len(t, report.Changes, 3)
+		assert.Equal(t, "to "+DaprPlacementContainerName, report.Changes[1].Detail)
+		assert.Equal(t, []string{"quirky_bell " + DaprPlacementContainerName}, *renames)
+		assert.FileExists(t, binaryFilePathWithDir(getDaprBinPath(installDir), daprRuntimeFilePrefix))
+		manifest, err := ReadInstallManifest(installDir)
+		require.NoError(t, err)
+		require.NotNil(t, manifest)
+		assert.Equal(t, "1.11.0", manifest.RuntimeVersion)
+		assert.False(t, manifest.SlimMode)
+		assert.Equal(t, "daprio/dapr:1.11.0", manifest.PlacementImage)
+		assert.NotEmpty(t, manifest.RuntimeBinarySHA256)
+		require.Len(t, manifest.Containers, 2)
+		assert.Equal(t, ManifestContainer{Name: DaprPlacementContainerName, Image: "daprio/dapr:1.11.0", HostPorts: []int{50005}, ImageID: "sha256:111"}, manifest.Containers[0])
+		assert.Equal(t, DaprRedisContainerName, manifest.Containers[1].Name)
+		assert.Equal(t, InstallActionMigrate, manifest.History[0].Action)
+
+		// Once the manifest is written, there is nothing left to migrate.
+		report, err = MigrateLegacyInstall(opts)
+		require.NoError(t, err)
+		assert.False(t, report.Legacy)
+		assert.Empty(t, report.Changes)
+		assert.Len(t, *renames, 1)
+	})
+
+	t.Run("slim mode", func(t *testing.T) {
+		runtimePath, _ := fakeLegacyInstall(t, "1.11.0", map[string][2]string{})
+		require.NoError(t, os.WriteFile(filepath.Join(legacyBinDirs()[0], placementServiceFilePrefix), []byte("#!/bin/sh\n"), 0o755))
+		report, err := MigrateLegacyInstall(MigrateOptions{DaprRuntimePath: runtimePath})
+		require.NoError(t, err)
+		require.Len(t, report.Changes, 3)
+		assert.Equal(t, placementServiceFilePrefix, report.Changes[1].Name)
+		manifest, err := ReadInstallManifest(filepath.Join(runtimePath, DefaultDaprDirName))
+		require.NoError(t, err)
+		assert.True(t, manifest.SlimMode)
+		assert.Empty(t, manifest.Containers)
+	})
+
+	unsafe := []struct {
+		name       string
+		containers map[string][2]string
+		reason     string
+	}{
+		{
+			name:       "other placement version",
+			containers: map[string][2]string{DaprPlacementContainerName: {"daprio/dapr:1.10.0", legacyInspect("daprio/dapr:1.10.0", "50005", "")}},
+			reason:     "runs 1.10.0 while daprd is 1.11.0",
+		},
+		{
+			name: "several candidates",
+			containers: map[string][2]string{
+				"quirky_bell": {"redis:6", legacyInspect("redis:6", "6379", "")},
+				"happy_cat":   {"redis:6", legacyInspect("redis:6", "6379", "")},
+			},
+			reason: "happy_cat, quirky_bell all look like the legacy dapr_redis",
+		},
+		{
+			name: "name taken",
+			containers: map[string][2]string{
+				"quirky_bell":          {"redis:6", legacyInspect("redis:6", "6379", "")},
+				DaprRedisContainerName: {"busybox", "[]"},
+			},
+			reason: "can't be renamed to dapr_redis",
+		},
+	}
+	for _, tc := range unsafe {
+		t.Run(tc.name, func(t *testing.T) {
+			runtimePath, renames := fakeLegacyInstall(t, "1.11.0", tc.containers)
+			report, err := MigrateLegacyInstall(MigrateOptions{DaprRuntimePath: runtimePath})
+			require.NoError(t, err)
+			assert.True(t, report.Legacy)
+			assert.Contains(t, report.Recommendation, tc.reason)
+			assert.Contains(t, report.Recommendation, "dapr uninstall --all")
+			assert.Empty(t, report.Changes)
+			assert.Empty(t, *renames)
+			assert.NoFileExists(t, GetInstallManifestPath(filepath.Join(runtimePath, DefaultDaprDirName)))
+		})
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "1.11.0", imageTag("daprio/dapr:1.11.0"))
+	assert.Equal(t, "1.11.0", imageTag("ghcr.io/dapr/dapr:v1.11.0@sha256:aaa"))
+	assert.Equal(t, "", imageTag("localhost:5000/dapr/dapr"))
+	assert.Equal(t, "", imageTag("openzipkin/zipkin"))
+}
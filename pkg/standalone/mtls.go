@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/secret"
+	"github.com/dapr/dapr/pkg/sentry/ca"
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+const (
+	// The file names of the mTLS credentials are the defaults of the issuer credentials of sentry, so that it can run
+	// with --issuer-credentials pointing to their directory.
+	mtlsCertsDirName   = "certs"
+	rootCertFileName   = "ca.crt"
+	issuerCertFileName = "issuer.crt"
+	issuerKeyFileName  = "issuer.key"
+	// MTLSConfigFileName is the configuration enabling mTLS, written next to the default one.
+	MTLSConfigFileName = "config-mtls.yaml"
+
+	// DefaultMTLSValidity is the lifetime of the generated root and issuer certificates.
+	DefaultMTLSValidity = 365 * 24 * time.Hour
+	// MTLSRenewBefore is how long before their expiry the certificates are reported as expiring.
+	MTLSRenewBefore = 30 * 24 * time.Hour
+
+	mtlsAllowedClockSkew = 15 * time.Minute
+	mtlsWorkloadCertTTL  = 24 * time.Hour
+
+	// The environment variables the sidecar reads its trust anchors and credentials from.
+	trustAnchorsEnvVar = "DAPR_TRUST_ANCHORS"
+	certChainEnvVar    = "DAPR_CERT_CHAIN"
+	certKeyEnvVar      = "DAPR_CERT_KEY"
+)
+
+// ErrMTLSCredentialsExist is returned by GenerateMTLSCredentials when the credentials exist and renew isn't set.
+var ErrMTLSCredentialsExist = errors.New("the mTLS credentials exist")
+
+// MTLSCredentials are the root and issuer certificates generated for the sidecars of the local environment.
+type MTLSCredentials struct {
+	Dir        string    `json:"dir"`
+	RootCert   string    `json:"rootCert"`
+	IssuerCert string    `json:"issuerCert"`
+	IssuerKey  string    `json:"issuerKey"`
+	ConfigFile string    `json:"configFile"`
+	Expiry     time.Time `json:"expiry"`
+}
+
+// Expiring reports whether the certificates expire within MTLSRenewBefore.
+func (c *MTLSCredentials) Expiring() bool {
+	return time.Until(c.Expiry) < MTLSRenewBefore
+}
+
+// GetMTLSCertsPath returns the directory of the mTLS credentials of the dapr install dir.
+func GetMTLSCertsPath(daprDir string) string {
+	return path_filepath.Join(daprDir, mtlsCertsDirName)
+}
+
+func mtlsCredentialsOf(daprDir string) *MTLSCredentials {
+	dir := GetMTLSCertsPath(daprDir)
+	return &MTLSCredentials{
+		Dir:        dir,
+		RootCert:   path_filepath.Join(dir, rootCertFileName),
+		IssuerCert: path_filepath.Join(dir, issuerCertFileName),
+		IssuerKey:  path_filepath.Join(dir, issuerKeyFileName),
+		ConfigFile: path_filepath.Join(daprDir, MTLSConfigFileName),
+	}
+}
+
+// GenerateMTLSCredentials generates a root certificate, and an issuer certificate and key signed by it, valid for
+// validity, in the certs directory of the dapr install dir, readable by the user only, along with the configuration
+// enabling mTLS. Existing credentials are only regenerated if renew is set. daprRuntimePath is based on the
+// --runtime-path command line flag, as for GetDaprRuntimePath.
+func GenerateMTLSCredentials(daprRuntimePath string, validity time.Duration, renew bool) (*MTLSCredentials, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	creds := mtlsCredentialsOf(daprDir)
+	if existing, readErr := ReadMTLSCredentials(daprRuntimePath); readErr == nil && existing != nil && !renew {
+		return existing, fmt.Errorf("%w in %s, they expire on %s, use --renew to regenerate them", ErrMTLSCredentialsExist, creds.Dir, existing.Expiry.Format(time.RFC1123))
+	}
+
+	rootKey, err := certs.GenerateECPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	_, rootCert, issuerCert, issuerKey, err := ca.GetNewSelfSignedCertificates(rootKey, validity, mtlsAllowedClockSkew)
+	if err != nil {
+		return nil, fmt.Errorf("error generating the mTLS certificates: %w", err)
+	}
+	if err = os.MkdirAll(creds.Dir, print.PrivateDirMode); err != nil {
+		return nil, err
+	}
+	for path, content := range map[string][]byte{creds.RootCert: rootCert, creds.IssuerCert: issuerCert, creds.IssuerKey: issuerKey} {
+		if err = os.WriteFile(path, content, print.PrivateFileMode); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", path, err)
+		}
+		// The permissions of a file which existed are kept by WriteFile.
+		if err = os.Chmod(path, print.PrivateFileMode); err != nil {
+			return nil, err
+		}
+	}
+	if err = writeMTLSConfig(creds.ConfigFile); err != nil {
+		return nil, err
+	}
+	if creds.Expiry, err = certificatesExpiry(rootCert, issuerCert); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// ReadMTLSCredentials returns the mTLS credentials of the dapr install dir, or nil if there are none.
+func ReadMTLSCredentials(daprRuntimePath string) (*MTLSCredentials, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	creds := mtlsCredentialsOf(daprDir)
+	rootCert, err := os.ReadFile(creds.RootCert)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	issuerCert, err := os.ReadFile(creds.IssuerCert)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(creds.IssuerKey); err != nil {
+		return nil, err
+	}
+	if creds.Expiry, err = certificatesExpiry(rootCert, issuerCert); err != nil {
+		return nil, fmt.Errorf("error reading the mTLS certificates in %s: %w", creds.Dir, err)
+	}
+	return creds, nil
+}
+
+// certificatesExpiry returns the earliest expiry of the PEM certificates.
+func certificatesExpiry(pems ...[]byte) (time.Time, error) {
+	var expiry time.Time
+	for _, p := range pems {
+		parsed, err := certs.DecodePEMCertificates(p)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, c := range parsed {
+			if expiry.IsZero() || c.NotAfter.Before(expiry) {
+				expiry = c.NotAfter
+			}
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, errors.New("no certificate")
+	}
+	return expiry, nil
+}
+
+// writeMTLSConfig writes the configuration enabling mTLS to path, with the tracing of the default configuration.
+func writeMTLSConfig(path string) error {
+	var config struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Spec struct {
+			MTLS struct {
+				Enabled          bool   `yaml:"enabled"`
+				WorkloadCertTTL  string `yaml:"workloadCertTTL"`
+				AllowedClockSkew string `yaml:"allowedClockSkew"`
+			} `yaml:"mtls"`
+			Tracing struct {
+				SamplingRate string `yaml:"samplingRate"`
+				Zipkin       struct {
+					EndpointAddress string `yaml:"endpointAddress"`
+				} `yaml:"zipkin"`
+			} `yaml:"tracing"`
+		} `yaml:"spec"`
+	}
+	config.APIVersion = "dapr.io/v1alpha1"
+	config.Kind = "Configuration"
+	config.Metadata.Name = "daprConfig"
+	config.Spec.MTLS.Enabled = true
+	config.Spec.MTLS.WorkloadCertTTL = mtlsWorkloadCertTTL.String()
+	config.Spec.MTLS.AllowedClockSkew = mtlsAllowedClockSkew.String()
+	config.Spec.Tracing.SamplingRate = "1"
+	config.Spec.Tracing.Zipkin.EndpointAddress = fmt.Sprintf("http://%s:9411/api/v2/spans", daprDefaultHost)
+	b, err := yaml.Marshal(&config)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// mtlsSidecarEnv returns the environment variables passing the mTLS credentials of the dapr install dir of
+// daprRuntimePath to a sidecar, or nil if there are none.
+func mtlsSidecarEnv(daprRuntimePath string) ([]string, error) {
+	creds, err := ReadMTLSCredentials(daprRuntimePath)
+	if err != nil || creds == nil {
+		return nil, err
+	}
+	var env []string
+	for name, path := range map[string]string{trustAnchorsEnvVar: creds.RootCert, certChainEnvVar: creds.IssuerCert, certKeyEnvVar: creds.IssuerKey} {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		value := strings.TrimSpace(string(b))
+		if name == certKeyEnvVar {
+			// The key reaches the output if the environment of the sidecar is ever printed.
+			secret.Register(value)
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, nil
+}
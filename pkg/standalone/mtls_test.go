@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/secret"
+)
+
+func TestGenerateMTLSCredentials(t *testing.T) {
+	runtimePath := t.TempDir()
+	creds, err := ReadMTLSCredentials(runtimePath)
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	creds, err = GenerateMTLSCredentials(runtimePath, 10*24*time.Hour, false)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*24*time.Hour), creds.Expiry, time.Hour)
+	assert.True(t, creds.Expiring())
+	for _, path := range []string{creds.RootCert, creds.IssuerCert, creds.IssuerKey} {
+		fi, statErr := os.Stat(path)
+		require.NoError(t, statErr)
+		if runtime.GOOS != daprWindowsOS {
+			assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm(), path)
+		}
+	}
+	assert.Equal(t, sentryDefaultAddress, mtlsEndpoint(creds.ConfigFile))
+
+	read, err := ReadMTLSCredentials(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, creds.Expiry.Unix(), read.Expiry.Unix())
+
+	// The existing credentials are only regenerated with renew.
+	_, err = GenerateMTLSCredentials(runtimePath, DefaultMTLSValidity, false)
+	assert.ErrorIs(t, err, ErrMTLSCredentialsExist)
+	renewed, err := GenerateMTLSCredentials(runtimePath, DefaultMTLSValidity, true)
+	require.NoError(t, err)
+	assert.False(t, renewed.Expiring())
+
+	env, err := mtlsSidecarEnv(runtimePath)
+	require.NoError(t, err)
+	require.Len(t, env, 3)
+	names := map[string]bool{}
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		names[name] = true
+		assert.True(t, strings.HasPrefix(value, "-----BEGIN "), name)
+		if name == certKeyEnvVar {
+			// The key is redacted from the output.
+			assert.NotContains(t, secret.Redact(e), strings.Split(value, "\n")[1])
+		}
+	}
+	assert.Equal(t, map[string]bool{trustAnchorsEnvVar: true, certChainEnvVar: true, certKeyEnvVar: true}, names)
+}
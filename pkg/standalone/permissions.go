@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	path_filepath "path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+// permissionRule is the policy of the permissions of a directory tree of the dapr install dir.
+type permissionRule struct {
+	// dir is the top directory of the tree in the dapr install dir, the empty rule covers the rest of it.
+	dir string
+	// dirMode and fileMode are the most open permissions of the directories and the files of the tree.
+	dirMode  os.FileMode
+	fileMode os.FileMode
+}
+
+// permissionPolicy is the policy AuditPermissions checks the dapr install dir against: nothing is writable by the
+// others, only the binaries are executable, and the credentials, the logs and the state of the runs are private.
+var permissionPolicy = []permissionRule{
+	{dir: "", dirMode: 0o755, fileMode: 0o644},
+	{dir: defaultDaprBinDirName, dirMode: 0o755, fileMode: 0o755},
+	{dir: mtlsCertsDirName, dirMode: print.PrivateDirMode, fileMode: print.PrivateFileMode},
+	{dir: defaultLogsDirName, dirMode: print.PrivateDirMode, fileMode: print.PrivateFileMode},
+	{dir: runRecordsDirName, dirMode: print.PrivateDirMode, fileMode: print.PrivateFileMode},
+	{dir: servicesDirName, dirMode: print.PrivateDirMode, fileMode: print.PrivateFileMode},
+}
+
+// PermissionViolation is a path of the dapr install dir whose permissions or owner break the policy.
+type PermissionViolation struct {
+	Path string `json:"path"`
+	// Mode is the permissions of the path, and Allowed the most open ones of the policy.
+	Mode    string `json:"mode"`
+	Allowed string `json:"allowed"`
+	Problem string `json:"problem"`
+	// Fixed is set once the permissions are restricted, and Error when that failed. The owner is never fixed.
+	Fixed bool   `json:"fixed"`
+	Error string `json:"error,omitempty"`
+}
+
+// AuditPermissions walks the dapr install dir daprDir and returns the paths whose permissions are more open than
+// the policy, or which belong to another user, restricting the permissions of the former if fix is set. The symbolic
+// links are neither followed nor changed, so the walk never leaves the tree. A missing install dir has no violations.
+// Nothing is audited on Windows, where the permissions are ACLs rather than modes.
+func AuditPermissions(daprDir string, fix bool) ([]PermissionViolation, error) {
+	if runtime.GOOS == daprWindowsOS {
+		return nil, nil
+	}
+	// The install dir itself may be a link, to another disk, its tree is the one of the target.
+	root, err := path_filepath.EvalSymlinks(daprDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	uid := os.Getuid()
+	var violations []PermissionViolation
+	err = path_filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			// An unreadable directory is reported by its own mode, its contents are skipped.
+			if path == root {
+				return walkErr
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := path_filepath.Rel(root, path)
+		rule := permissionRuleFor(rel)
+		allowed := rule.fileMode
+		if fi.IsDir() {
+			allowed = rule.dirMode
+		}
+		if v, ok := modeViolation(path, fi.Mode().Perm(), allowed); ok {
+			if fix {
+				if err = os.Chmod(path, fi.Mode().Perm()&allowed); err != nil {
+					v.Error = err.Error()
+				} else {
+					v.Fixed = true
+				}
+			}
+			violations = append(violations, v)
+		}
+		// Whoever runs the CLI as root owns whatever it finds.
+		if owner, ok := fileOwner(fi); ok && uid != 0 && owner != uid {
+			violations = append(violations, PermissionViolation{
+				Path:    path,
+				Mode:    formatMode(fi.Mode().Perm()),
+				Allowed: formatMode(allowed),
+				Problem: fmt.Sprintf("is owned by the user %d instead of %d", owner, uid),
+			})
+		}
+		return nil
+	})
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations, err
+}
+
+// permissionRuleFor returns the rule of the path rel, relative to the dapr install dir.
+func permissionRuleFor(rel string) permissionRule {
+	top, _, _ := strings.Cut(path_filepath.ToSlash(rel), "/")
+	for _, rule := range permissionPolicy[1:] {
+		if top == rule.dir {
+			return rule
+		}
+	}
+	return permissionPolicy[0]
+}
+
+// modeViolation returns the violation of path if its permissions mode are more open than allowed.
+func modeViolation(path string, mode, allowed os.FileMode) (PermissionViolation, bool) {
+	extra := mode &^ allowed
+	if extra == 0 {
+		return PermissionViolation{}, false
+	}
+	var problems []string
+	if extra&0o022 != 0 {
+		problems = append(problems, "writable by others")
+	}
+	if extra&0o044 != 0 {
+		problems = append(problems, "readable by others")
+	}
+	if extra&0o111 != 0 && allowed&0o100 == 0 {
+		problems = append(problems, "executable")
+	}
+	if len(problems) == 0 {
+		problems = append(problems, "too open")
+	}
+	return PermissionViolation{
+		Path:    path,
+		Mode:    formatMode(mode),
+		Allowed: formatMode(allowed),
+		Problem: "is " + strings.Join(problems, " and "),
+	}, true
+}
+
+func formatMode(mode os.FileMode) string {
+	return fmt.Sprintf("%04o", mode)
+}
+
+// checkPermissions is the doctor check of AuditPermissions.
+func checkPermissions(daprDir string, fix bool) (DoctorCheck, []PermissionViolation) {
+	c := DoctorCheck{Name: "permissions"}
+	if runtime.GOOS == daprWindowsOS {
+		c.Status = DoctorPass
+		c.Message = "not audited on Windows, where the permissions are ACLs"
+		return c, nil
+	}
+	violations, err := AuditPermissions(daprDir, fix)
+	if err != nil {
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("cannot audit %s: %s", daprDir, err)
+		return c, violations
+	}
+	return permissionsCheck(c, daprDir, violations), violations
+}
+
+// permissionsCheck returns the check c with the result of the violations found in daprDir.
+func permissionsCheck(c DoctorCheck, daprDir string, violations []PermissionViolation) DoctorCheck {
+	var fixed, open, owned []PermissionViolation
+	for _, v := range violations {
+		switch {
+		case v.Fixed:
+			fixed = append(fixed, v)
+		case strings.HasPrefix(v.Problem, "is owned by"):
+			owned = append(owned, v)
+		default:
+			open = append(open, v)
+		}
+	}
+	c.Status = DoctorPass
+	c.Message = fmt.Sprintf("%s follows the permissions policy", daprDir)
+	if len(fixed) > 0 {
+		c.Message = fmt.Sprintf("restricted the permissions of %d path(s) in %s", len(fixed), daprDir)
+	}
+	switch {
+	case len(open) > 0:
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("%d path(s) with too open permissions, e.g. %s %s (%s, at most %s)", len(open), open[0].Path, open[0].Problem, open[0].Mode, open[0].Allowed)
+		c.Hint = "run `dapr doctor --fix` to restrict them"
+		if open[0].Error != "" {
+			c.Hint = fmt.Sprintf("restricting them failed: %s", open[0].Error)
+		}
+	case len(owned) > 0:
+		c.Status = DoctorWarn
+		c.Message = fmt.Sprintf("%d path(s) owned by another user, e.g. %s %s", len(owned), owned[0].Path, owned[0].Problem)
+		c.Hint = fmt.Sprintf("they were likely installed with sudo, run `sudo chown -R $(id -u):$(id -g) %s`", daprDir)
+	}
+	return c
+}
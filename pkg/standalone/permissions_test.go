@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestAuditPermissions(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the permissions are ACLs on Windows")
+	}
+	daprDir := filepath.Join(t.TempDir(), DefaultDaprDirName)
+	outside := filepath.Join(t.TempDir(), "outside")
+	require.NoError(t, os.WriteFile(outside, nil, 0o600))
+	require.NoError(t, os.Chmod(outside, 0o777))
+	files := map[string]os.FileMode{
+		"config.yaml":                     0o666,
+		"components/statestore.yaml":      0o644,
+		"bin/daprd":                       0o777,
+		"bin/web/index.html":              0o644,
+		"certs/issuer.key":                0o644,
+		"logs/cli.log":                    0o644,
+		"run/myapp/daprd.log":             0o600,
+		runRecordsDirName + "/myapp.json": 0o600,
+	}
+	for name, mode := range files {
+		path := filepath.Join(daprDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		require.NoError(t, os.Chmod(path, mode))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(daprDir, "run", "myapp"), 0o700))
+	require.NoError(t, os.Symlink(outside, filepath.Join(daprDir, "bin", "link")))
+
+	violations, err := AuditPermissions(daprDir, false)
+	require.NoError(t, err)
+	problems := map[string]string{}
+	for _, v := range violations {
+		rel, _ := filepath.Rel(daprDir, v.Path)
+		problems[rel] = v.Mode + " " + v.Problem
+		assert.False(t, v.Fixed)
+	}
+	assert.Equal(t, map[string]string{
+		"config.yaml":      "0666 is writable by others",
+		"bin/daprd":        "0777 is writable by others",
+		"certs":            "0755 is readable by others",
+		"certs/issuer.key": "0644 is readable by others",
+		"logs":             "0755 is readable by others",
+		"logs/cli.log":     "0644 is readable by others",
+		"run":              "0755 is readable by others",
+	}, problems)
+	c := permissionsCheck(DoctorCheck{}, daprDir, violations)
+	assert.Equal(t, DoctorWarn, c.Status)
+	assert.Contains(t, c.Hint, "--fix")
+
+	violations, err = AuditPermissions(daprDir, true)
+	require.NoError(t, err)
+	require.Len(t, violations, 7)
+	for _, v := range violations {
+		assert.True(t, v.Fixed, v.Path)
+	}
+	assert.Equal(t, DoctorPass, permissionsCheck(DoctorCheck{}, daprDir, violations).Status)
+	for name, mode := range map[string]os.FileMode{"config.yaml": 0o644, "bin/daprd": 0o755, "certs": 0o700, "certs/issuer.key": 0o600, "logs/cli.log": 0o600} {
+		fi, err := os.Stat(filepath.Join(daprDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, mode, fi.Mode().Perm(), name)
+	}
+	// The links are not followed out of the tree.
+	fi, err := os.Stat(outside)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o777), fi.Mode().Perm())
+
+	violations, err = AuditPermissions(daprDir, false)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+	violations, err = AuditPermissions(filepath.Join(t.TempDir(), "missing"), false)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+// TestAuditPermissionsOfWrittenFiles audits the logs and the state of the runs and the services as the CLI writes
+// them, which must pass the policy without being fixed.
+func TestAuditPermissionsOfWrittenFiles(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the permissions are ACLs on Windows")
+	}
+	daprDir := filepath.Join(t.TempDir(), DefaultDaprDirName)
+	require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "myapp"}))
+	for _, path := range []string{getCLILogFilePath(daprDir), filepath.Join(GetRunLogsPath(daprDir, "myapp"), "daprd.log")} {
+		f, err := print.OpenRotatingFile(path, 1024, 1)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	script := filepath.Join(t.TempDir(), "placement")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nexit 0\n"), 0o755))
+	services := []ManifestService{{Name: "placement", Command: []string{script}}}
+	require.NoError(t, startPidfileServices(filepath.Join(daprDir, servicesDirName), services))
+	require.FileExists(t, services[0].PIDFile)
+	require.FileExists(t, services[0].LogFile)
+
+	violations, err := AuditPermissions(daprDir, false)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestModeViolation(t *testing.T) {
+	_, ok := modeViolation("/daprd", 0o750, 0o755)
+	assert.False(t, ok)
+	v, ok := modeViolation("/config.yaml", 0o755, 0o644)
+	require.True(t, ok)
+	assert.Equal(t, "is executable", v.Problem)
+	assert.Equal(t, "0644", v.Allowed)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/dapr/cli/pkg/print"
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+// ErrNoBuildPublished is returned when a release has no build for the platform of this machine.
+var ErrNoBuildPublished = errors.New("no build published for")
+
+// artifactArches maps the GOOS/GOARCH platforms to the architecture in the names of the release artifacts published
+// for them.
+var artifactArches = map[string]string{
+	"linux/amd64":   "amd64",
+	"linux/arm":     "arm",
+	"linux/arm64":   "arm64",
+	"darwin/amd64":  "amd64",
+	"darwin/arm64":  "arm64",
+	"windows/amd64": "amd64",
+	"freebsd/amd64": "amd64",
+	"freebsd/arm64": "arm64",
+}
+
+// containerPlatforms maps the architectures to the platforms of the container images run for them.
+var containerPlatforms = map[string]string{
+	"amd64": "linux/amd64",
+	"arm":   "linux/arm/v7",
+	"arm64": "linux/arm64",
+}
+
+// rosettaRuntimePath is installed along with Rosetta 2, which runs darwin/amd64 executables on Apple Silicon.
+const rosettaRuntimePath = "/Library/Apple/usr/share/rosetta/rosetta"
+
+var (
+	translatedOnce sync.Once
+	translated     bool
+)
+
+// processTranslated reports whether the CLI is a darwin/amd64 build running under Rosetta on Apple Silicon.
+func processTranslated() bool {
+	translatedOnce.Do(func() {
+		if runtime.GOOS != "darwin" || runtime.GOARCH != "amd64" {
+			return
+		}
+		out, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output()
+		translated = err == nil && strings.TrimSpace(string(out)) == "1"
+	})
+	return translated
+}
+
+// hostArch returns the architecture of this machine, which differs from the one of the CLI when an amd64 build of
+// the CLI runs under Rosetta on Apple Silicon.
+func hostArch() string {
+	if processTranslated() {
+		return "arm64"
+	}
+	return runtime.GOARCH
+}
+
+// hostPlatform returns the platform of this machine, as GOOS/GOARCH.
+func hostPlatform() string {
+	return runtime.GOOS + "/" + hostArch()
+}
+
+// rosettaInstalled reports whether Rosetta 2 is installed, to run darwin/amd64 executables on Apple Silicon.
+func rosettaInstalled() bool {
+	if processTranslated() {
+		return true
+	}
+	_, err := os.Stat(rosettaRuntimePath)
+	return err == nil
+}
+
+// artifactArch returns the architecture in the names of the release artifacts for goos and goarch, or an error if
+// none are published for them.
+func artifactArch(goos, goarch string) (string, error) {
+	arch, ok := artifactArches[goos+"/"+goarch]
+	if !ok {
+		return "", fmt.Errorf("%w %s/%s, the supported platforms are linux/amd64, linux/arm, linux/arm64, darwin/amd64, darwin/arm64, windows/amd64, freebsd/amd64 and freebsd/arm64", ErrNoBuildPublished, goos, goarch)
+	}
+	return arch, nil
+}
+
+// DefaultSlimMode reports whether init defaults to slim mode on this machine for containerRuntime: on FreeBSD, which
+// has no container runtime running the Linux images of the containers unless one was set up, init only installs the
+// binaries if containerRuntime isn't installed.
+func DefaultSlimMode(containerRuntime string) bool {
+	return runtime.GOOS == "freebsd" && !utils.IsContainerRuntimeInstalled(containerRuntime)
+}
+
+// containerPlatform returns the platform of the container images to run on goos and goarch, passed to the container
+// runtime with --platform so that it doesn't run images of another architecture under emulation. It is empty on
+// Windows, where the container runtime picks between Linux and Windows containers.
+func containerPlatform(goos, goarch string) string {
+	if goos == daprWindowsOS {
+		return ""
+	}
+	return containerPlatforms[goarch]
+}
+
+// platformArgs returns the --platform argument of the container runtime for the images of this machine, if any.
+func platformArgs() []string {
+	if platform := containerPlatform(runtime.GOOS, hostArch()); platform != "" {
+		return []string{"--platform", platform}
+	}
+	return nil
+}
+
+// isMissingPlatformError reports whether err is the error of the container runtime for an image with no variant for
+// the requested platform.
+func isMissingPlatformError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no matching manifest") || strings.Contains(msg, "no image found in manifest list")
+}
+
+// runContainerCmd runs the container runtime with args, built with platformArgs. If the image has no variant for the
+// platform of this machine and it isn't amd64, the amd64 variant is run under emulation instead, with a warning.
+// The container runtime is killed once ctx is done.
+func runContainerCmd(ctx context.Context, runtimeCmd string, args ...string) (string, error) {
+	out, err := runCmdContext(ctx, runtimeCmd, args...)
+	if err == nil || !isMissingPlatformError(err) {
+		return out, err
+	}
+	emulated := containerPlatforms["amd64"]
+	for i := range args[:len(args)-1] {
+		if args[i] == "--platform" && args[i+1] != emulated {
+			image := args[len(args)-1]
+			print.WarningStatusEvent(os.Stdout, "Only %s images of %s are published, it runs under emulation", emulated, image)
+			fallback := append([]string{}, args...)
+			fallback[i+1] = emulated
+			return runCmdContext(ctx, runtimeCmd, fallback...)
+		}
+	}
+	return out, err
+}
+
+// resolveArtifactArch returns the architecture of the release artifact of the binary to download for this machine,
+// and whether it runs under emulation. On Apple Silicon, the darwin/amd64 artifact is used under Rosetta when no
+// darwin/arm64 one is published. On the Linux systems without glibc, the static artifact is used when one is
+// published, the regular one is checked with checkBinaryRunnable once extracted otherwise.
+func resolveArtifactArch(ctx context.Context, version, binaryFilePrefix, githubRepo string) (string, bool, error) {
+	arch, err := artifactArch(runtime.GOOS, hostArch())
+	if err != nil {
+		return "", false, err
+	}
+	if preferStaticArtifact() {
+		static := arch + muslArtifactSuffix
+		if checkArtifactPublished(ctx, binaryDownloadURL(version, binaryFilePrefix, githubRepo, static), version) == nil {
+			return static, false, nil
+		}
+	}
+	err = checkArtifactPublished(ctx, binaryDownloadURL(version, binaryFilePrefix, githubRepo, arch), version)
+	if err == nil || !errors.Is(err, ErrNoBuildPublished) || runtime.GOOS != "darwin" || arch != "arm64" {
+		return arch, false, err
+	}
+	if !rosettaInstalled() {
+		return "", false, fmt.Errorf("%w, and the darwin/amd64 build needs Rosetta: install it with `softwareupdate --install-rosetta`", err)
+	}
+	if fallbackErr := checkArtifactPublished(ctx, binaryDownloadURL(version, binaryFilePrefix, githubRepo, "amd64"), version); fallbackErr != nil {
+		return "", false, err
+	}
+	return "amd64", true, nil
+}
+
+// checkArtifactPublished returns an error if the release artifact at url, the build of version for this machine,
+// doesn't exist. Only a missing artifact is reported, the other errors are left to the download.
+func checkArtifactPublished(ctx context.Context, url, version string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", cli_ver.CLI.UserAgent())
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w %s of version %s: %s", ErrNoBuildPublished, hostPlatform(), version, url)
+	}
+	return nil
+}
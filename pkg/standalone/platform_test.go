@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactArch(t *testing.T) {
+	arch, err := artifactArch("linux", "arm64")
+	require.NoError(t, err)
+	assert.Equal(t, "arm64", arch)
+	arch, err = artifactArch("linux", "arm")
+	require.NoError(t, err)
+	assert.Equal(t, "arm", arch)
+
+	arch, err = artifactArch("freebsd", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "amd64", arch)
+
+	_, err = artifactArch("windows", "arm64")
+	assert.ErrorIs(t, err, ErrNoBuildPublished)
+	_, err = artifactArch("linux", "386")
+	assert.ErrorContains(t, err, "no build published for linux/386")
+}
+
+func TestDefaultSlimMode(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	assert.Equal(t, runtime.GOOS == "freebsd", DefaultSlimMode("docker"))
+}
+
+func TestContainerPlatform(t *testing.T) {
+	assert.Equal(t, "linux/arm64", containerPlatform("linux", "arm64"))
+	assert.Equal(t, "linux/arm/v7", containerPlatform("linux", "arm"))
+	assert.Equal(t, "linux/arm64", containerPlatform("darwin", "arm64"))
+	assert.Equal(t, "linux/amd64", containerPlatform("linux", "amd64"))
+	assert.Empty(t, containerPlatform("windows", "amd64"))
+}
+
+func TestCheckArtifactPublished(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	require.NoError(t, checkArtifactPublished(context.Background(), ts.URL, "1.11.0"))
+	status = http.StatusNotFound
+	err := checkArtifactPublished(context.Background(), ts.URL, "1.11.0")
+	assert.ErrorIs(t, err, ErrNoBuildPublished)
+	assert.ErrorContains(t, err, "of version 1.11.0")
+	// The other errors are left to the download.
+	status = http.StatusBadGateway
+	assert.NoError(t, checkArtifactPublished(context.Background(), ts.URL, "1.11.0"))
+}
+
+func TestIsMissingPlatformError(t *testing.T) {
+	assert.True(t, isMissingPlatformError(errors.New("docker: no matching manifest for linux/arm64/v8 in the manifest list entries.")))
+	assert.True(t, isMissingPlatformError(errors.New("Error: choosing an image from manifest list docker.io/openzipkin/zipkin: no image found in manifest list for architecture arm64")))
+	assert.False(t, isMissingPlatformError(errors.New("docker: Error response from daemon: Conflict.")))
+}
+
+func TestRunContainerCmdFallsBackToAmd64(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake container runtime is a shell script")
+	}
+	dir := t.TempDir()
+	// The fake runtime has no arm64 variant of the images.
+	script := "#!/bin/sh\ncase \"$*\" in *linux/amd64*) echo \"$*\" ;; *) echo 'no matching manifest for linux/arm64/v8' >&2; exit 1 ;; esac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755))
+	t.Setenv("PATH", dir)
+
+	out, err := runContainerCmd(context.Background(), "docker", "run", "--platform", "linux/arm64", "-d", "openzipkin/zipkin")
+	require.NoError(t, err)
+	assert.Equal(t, "run --platform linux/amd64 -d openzipkin/zipkin", strings.TrimSpace(out))
+
+	// Without --platform there is nothing to fall back to.
+	_, err = runContainerCmd(context.Background(), "docker", "start", "dapr_zipkin")
+	assert.True(t, isMissingPlatformError(err))
+}
+
+func TestRunContainerCmdCancelled(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake container runtime is a shell script")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// An in-flight pull is killed once the install is cancelled or times out.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := runContainerCmd(ctx, "docker", "pull", "openzipkin/zipkin")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
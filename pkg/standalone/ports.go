@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/phayes/freeport"
+	psnet "github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+)
+
+// portScanRange is the number of ports scanned from the base port before falling back to a random free port.
+const portScanRange = 100
+
+// basePorts are the ports from which the free ports of the sidecars are scanned, the defaults of the runtime, so
+// that the first app gets the usual ports and the next ones the following ports.
+var basePorts = map[string]int{
+	"HTTPPort": 3500,
+	"GRPCPort": 50001,
+}
+
+// assignedPorts are the free ports assigned by this process. The sidecars of the apps of a run file are started one
+// after the other, and may not be listening yet on their ports when the next app is assigned ports.
+var assignedPorts = struct {
+	sync.Mutex
+	ports map[int]bool
+}{ports: map[int]bool{}}
+
+// freePort returns the first free port from base, or a random free port if base is 0 or no port in the scan range
+// is free. The port is reserved, so that it isn't returned again.
+func (meta *DaprMeta) freePort(base int) (int, error) {
+	assignedPorts.Lock()
+	defer assignedPorts.Unlock()
+	available := func(port int) bool {
+		if assignedPorts.ports[port] || meta.portExists(port) {
+			return false
+		}
+		assignedPorts.ports[port] = true
+		return true
+	}
+
+	if base > 0 {
+		for port := base; port < base+portScanRange; port++ {
+			if available(port) {
+				return port, nil
+			}
+		}
+	}
+	// A random port can still be a reserved one, in which case another one is picked.
+	for i := 0; ; i++ {
+		port, err := freeport.GetFreePort()
+		if err != nil {
+			return 0, err
+		}
+		if available(port) || i == portScanRange {
+			return port, nil
+		}
+	}
+}
+
+// portOwner describes what listens on port, a Dapr app or a process, or returns an empty string if it is unknown.
+func (meta *DaprMeta) portOwner(port int) string {
+	if appID, ok := meta.portApps[port]; ok {
+		return fmt.Sprintf("the Dapr app %q", appID)
+	}
+
+	connections, err := psnet.Connections("tcp")
+	if err != nil {
+		return ""
+	}
+	for _, c := range connections {
+		if c.Status != "LISTEN" || c.Laddr.Port != uint32(port) || c.Pid <= 0 {
+			continue
+		}
+		if p, err := process.NewProcess(c.Pid); err == nil {
+			if name, err := p.Name(); err == nil && name != "" {
+				return fmt.Sprintf("process %s (pid %d)", name, c.Pid)
+			}
+		}
+		return fmt.Sprintf("process %d", c.Pid)
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDaprMeta() *DaprMeta {
+	return &DaprMeta{ExistingIDs: map[string]bool{}, ExistingPorts: map[int]bool{}, portApps: map[int]string{}}
+}
+
+func TestFreePort(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	meta := newTestDaprMeta()
+	first, err := meta.freePort(busy)
+	require.NoError(t, err)
+	assert.Greater(t, first, busy)
+	assert.Less(t, first, busy+portScanRange)
+
+	second, err := meta.freePort(busy)
+	require.NoError(t, err)
+	assert.Greater(t, second, first, "reserved ports are not returned again")
+
+	random, err := meta.freePort(0)
+	require.NoError(t, err)
+	assert.NotContains(t, []int{busy, first, second}, random)
+}
+
+func TestValidatePortNamesOwner(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	config := &RunConfig{}
+	meta := newTestDaprMeta()
+	err = config.validatePort("HTTPPort", &busy, meta)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("Port %d is not available", busy))
+	if owner := meta.portOwner(busy); owner != "" {
+		assert.Contains(t, err.Error(), fmt.Sprintf("(pid %d)", os.Getpid()))
+	}
+
+	meta.ExistingPorts[busy] = true
+	meta.portApps[busy] = "myapp"
+	err = config.validatePort("HTTPPort", &busy, meta)
+	assert.ErrorContains(t, err, `it is used by the Dapr app "myapp"`)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	// DefaultProfile is the profile of the install dir itself, as before the profiles.
+	DefaultProfile = "default"
+	// profilesDirName is the directory of the install dirs of the other profiles, in the one of the default profile.
+	profilesDirName = "profiles"
+	// profileLabel is the label of the containers set up by init with the profile they belong to.
+	profileLabel = "io.dapr.cli.profile"
+)
+
+// profileNamePattern is the pattern of the profile names, which suffix the container names.
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+// activeProfile is the profile set with SetProfile, from the --profile command line flag.
+var activeProfile string
+
+// SetProfile makes the commands use the profile name, instead of the one of the DAPR_PROFILE environment variable or
+// of the CLI config file of the user config dir.
+func SetProfile(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	activeProfile = name
+	return nil
+}
+
+func validateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q, it must start with a letter or a digit, followed by at most 62 letters, digits, '_', '.' or '-'", name)
+	}
+	return nil
+}
+
+// resolveProfile returns the profile the commands use.
+func resolveProfile() (string, error) {
+	if activeProfile != "" {
+		return activeProfile, nil
+	}
+	setting, err := ResolveSetting(SettingProfile, nil, "")
+	if err != nil {
+		return "", err
+	}
+	if err = validateProfileName(setting.Value); err != nil {
+		return "", fmt.Errorf("%w, in %s", err, setting.Source)
+	}
+	return setting.Value, nil
+}
+
+// ActiveProfile returns the profile the commands use, the default one if it can't be resolved.
+func ActiveProfile() string {
+	profile, err := resolveProfile()
+	if err != nil {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// profileDir returns the install dir of profile, baseDir being the one of the default profile.
+func profileDir(baseDir, profile string) string {
+	if profile == DefaultProfile {
+		return baseDir
+	}
+	return path_filepath.Join(baseDir, profilesDirName, profile)
+}
+
+// profileContainerName returns the name of the container serviceContainerName of the active profile in
+// dockerNetwork: the containers of the profiles other than the default one are suffixed with their name.
+func profileContainerName(serviceContainerName, dockerNetwork string) string {
+	name := utils.CreateContainerName(serviceContainerName, dockerNetwork)
+	if profile := ActiveProfile(); profile != DefaultProfile {
+		name += "_" + profile
+	}
+	return name
+}
+
+// profileLabelArgs returns the arguments of the run command of the container runtime labelling a container with the
+// active profile.
+func profileLabelArgs() []string {
+	return []string{"--label", profileLabel + "=" + ActiveProfile()}
+}
+
+// Profile is a profile of the local environment, with its own install dir.
+type Profile struct {
+	Name   string `json:"name"`
+	Dir    string `json:"dir"`
+	Active bool   `json:"active"`
+	// RuntimeVersion is the version of the runtime installed in the profile, empty if none is.
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+}
+
+// ListProfiles returns the profiles of the install dir based on daprRuntimePath, as for GetDaprRuntimePath, the
+// default one first.
+func ListProfiles(daprRuntimePath string) ([]Profile, error) {
+	baseDir, err := baseDaprDir(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(path_filepath.Join(baseDir, profilesDirName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var others []string
+	for _, e := range entries {
+		if e.IsDir() && validateProfileName(e.Name()) == nil && e.Name() != DefaultProfile {
+			others = append(others, e.Name())
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	active := ActiveProfile()
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		p := Profile{Name: name, Dir: profileDir(baseDir, name), Active: name == active}
+		if m, readErr := ReadInstallManifest(p.Dir); readErr == nil && m != nil && m.RuntimeBinary != "" {
+			p.RuntimeVersion = m.RuntimeVersion
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates the install dir of the profile name, which `dapr init --profile <name>` then installs in.
+func CreateProfile(daprRuntimePath, name string) (*Profile, error) {
+	if err := validateProfileName(name); err != nil {
+		return nil, err
+	}
+	if name == DefaultProfile {
+		return nil, fmt.Errorf("the %s profile always exists", DefaultProfile)
+	}
+	baseDir, err := baseDaprDir(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	dir := profileDir(baseDir, name)
+	if _, err = os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("profile %s already exists in %s", name, dir)
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating the directory of profile %s: %w", name, err)
+	}
+	return &Profile{Name: name, Dir: dir, Active: name == ActiveProfile()}, nil
+}
+
+// DeleteProfile removes the install dir of the profile name. It fails if the profile still has an install, which
+// `dapr uninstall --all --profile <name>` removes along with its containers.
+func DeleteProfile(daprRuntimePath, name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("the %s profile can't be deleted", DefaultProfile)
+	}
+	baseDir, err := baseDaprDir(daprRuntimePath)
+	if err != nil {
+		return err
+	}
+	dir := profileDir(baseDir, name)
+	if _, err = os.Stat(dir); err != nil {
+		return fmt.Errorf("profile %s doesn't exist", name)
+	}
+	m, err := ReadInstallManifest(dir)
+	if err != nil {
+		return err
+	}
+	if m != nil && (m.RuntimeBinary != "" || len(m.Containers) > 0) {
+		return fmt.Errorf("profile %s has an install, remove it with `dapr uninstall --all --profile %s` first", name, name)
+	}
+	if err = os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("error removing the directory of profile %s: %w", name, err)
+	}
+	return nil
+}
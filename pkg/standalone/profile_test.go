@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useProfile makes the test use the profile name.
+func useProfile(t *testing.T, name string) {
+	t.Helper()
+	require.NoError(t, SetProfile(name))
+	t.Cleanup(func() { activeProfile = "" })
+}
+
+func TestProfileInstallDir(t *testing.T) {
+	isolateUserConfig(t)
+	t.Setenv("DAPR_PROFILE", "")
+	runtimePath := t.TempDir()
+	base := filepath.Join(runtimePath, DefaultDaprDirName)
+
+	daprDir, err := GetDaprRuntimePath(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, base, daprDir)
+	assert.Equal(t, "dapr_redis_mynet", profileContainerName(DaprRedisContainerName, "mynet"))
+
+	t.Setenv("DAPR_PROFILE", "clientA")
+	daprDir, err = GetDaprRuntimePath(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "profiles", "clientA"), daprDir)
+	assert.Equal(t, "dapr_redis_clientA", profileContainerName(DaprRedisContainerName, ""))
+	assert.Equal(t, []string{"--label", "io.dapr.cli.profile=clientA"}, profileLabelArgs())
+
+	useProfile(t, "clientB")
+	daprDir, err = GetDaprRuntimePath(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "profiles", "clientB"), daprDir)
+	assert.Equal(t, "dapr_placement_mynet_clientB", profileContainerName(DaprPlacementContainerName, "mynet"))
+
+	assert.Error(t, SetProfile("../x"))
+	t.Setenv("DAPR_PROFILE", "a b")
+	activeProfile = ""
+	_, err = GetDaprRuntimePath(runtimePath)
+	assert.ErrorContains(t, err, "DAPR_PROFILE environment variable")
+}
+
+func TestProfiles(t *testing.T) {
+	isolateUserConfig(t)
+	t.Setenv("DAPR_PROFILE", "")
+	runtimePath := t.TempDir()
+
+	p, err := CreateProfile(runtimePath, "clientA")
+	require.NoError(t, err)
+	assert.DirExists(t, p.Dir)
+	_, err = CreateProfile(runtimePath, "clientA")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = CreateProfile(runtimePath, DefaultProfile)
+	assert.Error(t, err)
+	require.NoError(t, writeInstallManifest(p.Dir, &InstallManifest{RuntimeVersion: "1.11.0", RuntimeBinary: "daprd"}))
+
+	profiles, err := ListProfiles(runtimePath)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, Profile{Name: DefaultProfile, Dir: filepath.Join(runtimePath, DefaultDaprDirName), Active: true}, profiles[0])
+	assert.Equal(t, Profile{Name: "clientA", Dir: p.Dir, RuntimeVersion: "1.11.0"}, profiles[1])
+
+	assert.ErrorContains(t, DeleteProfile(runtimePath, "clientA"), "dapr uninstall --all --profile clientA")
+	assert.Error(t, DeleteProfile(runtimePath, DefaultProfile))
+	require.NoError(t, os.Remove(GetInstallManifestPath(p.Dir)))
+	require.NoError(t, DeleteProfile(runtimePath, "clientA"))
+	assert.NoDirExists(t, p.Dir)
+	assert.ErrorContains(t, DeleteProfile(runtimePath, "clientA"), "doesn't exist")
+}
+
+func TestRemoveInstallDirKeepsProfiles(t *testing.T) {
+	isolateUserConfig(t)
+	t.Setenv("DAPR_PROFILE", "")
+	installDir := filepath.Join(t.TempDir(), DefaultDaprDirName)
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, "bin"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, profilesDirName, "clientA"), 0o755))
+
+	report := &UninstallReport{}
+	require.NoError(t, removeInstallDir(installDir, report))
+	assert.NoDirExists(t, filepath.Join(installDir, "bin"))
+	assert.DirExists(t, filepath.Join(installDir, profilesDirName, "clientA"))
+	assert.Equal(t, []string{installDir}, report.RemovedDirectories)
+
+	// The install dir of a profile is removed entirely.
+	useProfile(t, "clientA")
+	require.NoError(t, removeInstallDir(filepath.Join(installDir, profilesDirName, "clientA"), report))
+	assert.NoDirExists(t, filepath.Join(installDir, profilesDirName, "clientA"))
+}
+
+func TestContainerExistsAmongProfiles(t *testing.T) {
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(name string, args ...string) (string, error) {
+		return "dapr_redis_clientA\ndapr_redis\n", nil
+	}
+	exists, err := confirmContainerIsRunningOrExists("dapr_redis", false, "docker")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	runCmd = func(name string, args ...string) (string, error) {
+		return "dapr_redis_clientA\n", nil
+	}
+	exists, err = confirmContainerIsRunningOrExists("dapr_redis", false, "docker")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -19,37 +19,74 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/dapr/cli/pkg/api"
+	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/utils"
 )
 
+// PublishOptions are the optional settings of PublishEvent.
+type PublishOptions struct {
+	// Socket is the directory of the unix domain sockets of the sidecar.
+	Socket string
+	// Metadata is the publish metadata, passed as query parameters.
+	Metadata map[string]interface{}
+	// HTTPPort is the HTTP port of the sidecar. It is looked up from the running instances if 0.
+	HTTPPort int
+	// ContentType is the content type of the payload. It is detected from the payload if empty.
+	ContentType string
+}
+
+// PublishResult is the response of the sidecar to a publish request.
+type PublishResult struct {
+	StatusCode int
+	// Body is the body of the response, which holds the details of the error if the event wasn't published.
+	Body string
+}
+
 // Publish publishes payload to topic in pubsub referenced by pubsubName.
 func (s *Standalone) Publish(publishAppID, pubsubName, topic string, payload []byte, socket string, metadata map[string]interface{}) error {
-	if publishAppID == "" {
-		return errors.New("publishAppID is missing")
+	_, err := s.PublishEvent(publishAppID, pubsubName, topic, payload, PublishOptions{Socket: socket, Metadata: metadata})
+	return err
+}
+
+// PublishEvent publishes payload to topic in pubsub referenced by pubsubName, through the sidecar of publishAppID or
+// the one listening on opts.HTTPPort. The result is returned along with the error of a non-2xx response.
+func (s *Standalone) PublishEvent(publishAppID, pubsubName, topic string, payload []byte, opts PublishOptions) (PublishResult, error) {
+	if publishAppID == "" && (opts.HTTPPort == 0 || opts.Socket != "") {
+		return PublishResult{}, errors.New("publishAppID is missing")
 	}
 
 	if pubsubName == "" {
-		return errors.New("pubsubName is missing")
+		return PublishResult{}, errors.New("pubsubName is missing")
 	}
 
 	if topic == "" {
-		return errors.New("topic is missing")
+		return PublishResult{}, errors.New("topic is missing")
 	}
 
-	queryParams := getQueryParams(metadata)
+	queryParams := getQueryParams(opts.Metadata)
 
-	l, err := s.process.List()
-	if err != nil {
-		return err
-	}
+	httpPort, socket := opts.HTTPPort, opts.Socket
+	if httpPort == 0 {
+		l, err := s.process.List()
+		if err != nil {
+			return PublishResult{}, err
+		}
 
-	instance, err := getDaprInstance(l, publishAppID)
-	if err != nil {
-		return err
+		instance, err := getDaprInstance(l, publishAppID)
+		if err != nil {
+			return PublishResult{}, err
+		}
+		httpPort = instance.HTTPPort
+		if socket == "" {
+			socket = instance.UnixDomainSocket
+		}
 	}
 
 	url := fmt.Sprintf("http://unix/v%s/publish/%s/%s%s", api.RuntimeAPIVersion, pubsubName, topic, queryParams)
@@ -62,35 +99,59 @@ func (s *Standalone) Publish(publishAppID, pubsubName, topic string, payload []b
 			},
 		}
 	} else {
-		url = fmt.Sprintf("http://localhost:%s/v%s/publish/%s/%s%s", fmt.Sprintf("%v", instance.HTTPPort), api.RuntimeAPIVersion, pubsubName, topic, queryParams)
+		url = fmt.Sprintf("http://localhost:%s/v%s/publish/%s/%s%s", fmt.Sprintf("%v", httpPort), api.RuntimeAPIVersion, pubsubName, topic, queryParams)
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = DetectPayloadContentType(payload)
+	}
+
+	r, err := httpc.Post(url, contentType, bytes.NewBuffer(payload))
+	if err != nil {
+		return PublishResult{}, err
 	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return PublishResult{}, err
+	}
+	result := PublishResult{StatusCode: r.StatusCode, Body: strings.TrimSpace(string(body))}
+	if r.StatusCode >= 300 || r.StatusCode < 200 {
+		print.DebugStatusEvent(os.Stderr, "publish request to %s failed with status code %d", url, r.StatusCode)
+		err = fmt.Errorf("unexpected status code %d on publishing to %s in %s", r.StatusCode, topic, pubsubName)
+		if result.Body != "" {
+			err = fmt.Errorf("%w: %s", err, result.Body)
+		}
+		return result, err
+	}
+
+	return result, nil
+}
 
-	contentType := "application/json"
+// DetectPayloadContentType returns the content type of a payload to publish: application/cloudevents+json for a
+// CloudEvents envelope, application/json for JSON or an empty payload, and text/plain otherwise.
+func DetectPayloadContentType(payload []byte) string {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return "application/json"
+	}
+	if !json.Valid(payload) {
+		return "text/plain"
+	}
 
 	// Detect publishing with CloudEvents envelope.
 	var cloudEvent map[string]interface{}
-	if json.Unmarshal(payload, &cloudEvent); err == nil {
+	if err := json.Unmarshal(payload, &cloudEvent); err == nil {
 		_, hasID := cloudEvent["id"]
 		_, hasSource := cloudEvent["source"]
 		_, hasSpecVersion := cloudEvent["specversion"]
 		_, hasType := cloudEvent["type"]
 		_, hasData := cloudEvent["data"]
 		if hasID && hasSource && hasSpecVersion && hasType && hasData {
-			contentType = "application/cloudevents+json"
+			return "application/cloudevents+json"
 		}
 	}
-
-	r, err := httpc.Post(url, contentType, bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode >= 300 || r.StatusCode < 200 {
-		fmt.Println(url)
-		return fmt.Errorf("unexpected status code %d on publishing to %s in %s", r.StatusCode, topic, pubsubName)
-	}
-
-	return nil
+	return "application/json"
 }
 
 func getDaprInstance(list []ListOutput, publishAppID string) (ListOutput, error) {
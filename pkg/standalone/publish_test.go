@@ -228,3 +228,57 @@ func TestGetQueryParams(t *testing.T) {
 		assert.Equal(t, len(queryParams), strings.Count(queryParams, "&"), "expected query params to not contain any unexpected entries")
 	}
 }
+
+func TestPublishEvent(t *testing.T) {
+	var contentType string
+	ts, port := getTestServerFunc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if r.URL.Path == "/v1.0/publish/testPubsubName/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"ERR_PUBSUB_NOT_FOUND","message":"pubsub testPubsubName not found"}` + "\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	ts.Start()
+	defer ts.Close()
+
+	// The instances aren't listed when the port is given.
+	client := &Standalone{process: &mockDaprProcess{Err: assert.AnError}}
+
+	t.Run("explicit port", func(t *testing.T) {
+		result, err := client.PublishEvent("", "testPubsubName", "testTopic", []byte("hello"), PublishOptions{HTTPPort: port})
+		assert.NoError(t, err)
+		assert.Equal(t, PublishResult{StatusCode: http.StatusNoContent}, result)
+		assert.Equal(t, "text/plain", contentType)
+	})
+
+	t.Run("content type override", func(t *testing.T) {
+		_, err := client.PublishEvent("", "testPubsubName", "testTopic", []byte("<a/>"), PublishOptions{HTTPPort: port, ContentType: "application/xml"})
+		assert.NoError(t, err)
+		assert.Equal(t, "application/xml", contentType)
+	})
+
+	t.Run("error body", func(t *testing.T) {
+		result, err := client.PublishEvent("", "testPubsubName", "missing", []byte(`{"id":1}`), PublishOptions{HTTPPort: port})
+		assert.EqualError(t, err, `unexpected status code 404 on publishing to missing in testPubsubName: {"errorCode":"ERR_PUBSUB_NOT_FOUND","message":"pubsub testPubsubName not found"}`)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+		assert.Equal(t, "application/json", contentType)
+	})
+}
+
+func TestDetectPayloadContentType(t *testing.T) {
+	testCases := []struct {
+		payload  string
+		expected string
+	}{
+		{payload: "", expected: "application/json"},
+		{payload: `{"id":1}`, expected: "application/json"},
+		{payload: `[1, 2]`, expected: "application/json"},
+		{payload: "hello", expected: "text/plain"},
+		{payload: `{"id": "1234", "source": "test", "specversion": "1.0", "type": "product.v1", "data": {}}`, expected: "application/cloudevents+json"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, DetectPayloadContentType([]byte(tc.payload)), "payload %q", tc.payload)
+	}
+}
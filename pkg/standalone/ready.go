@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// DefaultAppReadyTimeout is how long the run command waits for the app to be ready with --wait-for-app.
+const DefaultAppReadyTimeout = 60 * time.Second
+
+const appReadyPollInterval = 250 * time.Millisecond
+
+// AppReadiness describes how to check that an app is ready: its health endpoint answers with a 2xx status if set,
+// otherwise its port accepts connections.
+type AppReadiness struct {
+	Port int
+	// HealthEndpoint is a URL, or a path on the app port.
+	HealthEndpoint string
+	// SSL is set for the apps serving TLS. Their certificates are verified against the CA certificates of CAFile if it
+	// is set, and aren't otherwise.
+	SSL    bool
+	CAFile string
+	// roots are the certificates of CAFile, read by WaitForApp.
+	roots *x509.CertPool
+}
+
+func (r AppReadiness) String() string {
+	if r.HealthEndpoint != "" {
+		return r.healthURL()
+	}
+	return net.JoinHostPort("localhost", strconv.Itoa(r.Port))
+}
+
+func (r AppReadiness) healthURL() string {
+	if strings.HasPrefix(r.HealthEndpoint, "http://") || strings.HasPrefix(r.HealthEndpoint, "https://") {
+		return r.HealthEndpoint
+	}
+	scheme := "http"
+	if r.SSL {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d/%s", scheme, r.Port, strings.TrimPrefix(r.HealthEndpoint, "/"))
+}
+
+func (r AppReadiness) check(timeout time.Duration) error {
+	if r.HealthEndpoint == "" {
+		if r.SSL {
+			// The app is ready once it completes a TLS handshake, not just once it accepts connections.
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: r.tlsConfig()}
+			conn, err := dialer.DialContext(ctx, "tcp", r.String())
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+		conn, err := net.DialTimeout("tcp", r.String(), timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	client := *utils.HTTPClient()
+	client.Timeout = timeout
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport = transport.Clone()
+		transport.TLSClientConfig = r.tlsConfig()
+		client.Transport = transport
+	}
+	resp, err := client.Get(r.healthURL())
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("health endpoint returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// tlsConfig returns the TLS configuration of the probe. The app serves its own certificate, which is only verified if
+// there are CA certificates to verify it against. Its host name isn't, as the app is reached on localhost, which it
+// may not be issued for, e.g. for a production certificate.
+func (r AppReadiness) tlsConfig() *tls.Config {
+	//nolint:gosec
+	config := &tls.Config{InsecureSkipVerify: true}
+	if r.roots == nil {
+		return config
+	}
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("the app presented no certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("the app presented an invalid certificate: %w", err)
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := certs[0].Verify(x509.VerifyOptions{Roots: r.roots, Intermediates: intermediates}); err != nil {
+			return fmt.Errorf("the certificate of the app isn't issued by the CA of %s: %w", r.CAFile, err)
+		}
+		return nil
+	}
+	return config
+}
+
+// readCAFile returns the pool of the PEM certificates of path.
+func readCAFile(path string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the app CA file: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("the app CA file %s has no PEM certificate", path)
+	}
+	return roots, nil
+}
+
+// ValidateAppCAFile returns an error if path isn't a file of PEM CA certificates, as set with run --app-ca-file.
+func ValidateAppCAFile(path string) error {
+	_, err := readCAFile(path)
+	return err
+}
+
+// WaitForApp waits for the app to be ready, up to timeout. It returns the last error of the checks if the app isn't
+// ready in time, or as soon as exited is closed, for an app which exited while starting.
+func WaitForApp(r AppReadiness, timeout time.Duration, exited <-chan struct{}) error {
+	if r.SSL && r.CAFile != "" {
+		roots, err := readCAFile(r.CAFile)
+		if err != nil {
+			return err
+		}
+		r.roots = roots
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		err := r.check(appReadyPollInterval)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-exited:
+			return errors.New("the app exited before it was ready")
+		default:
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the app is not ready on %s after %s: %w", r, timeout, err)
+		}
+		select {
+		case <-exited:
+		case <-time.After(appReadyPollInterval):
+		}
+	}
+}
+
+// RecentLines keeps the last lines of an output, to report them when something fails.
+type RecentLines struct {
+	lock  sync.Mutex
+	max   int
+	lines []string
+}
+
+// NewRecentLines returns a RecentLines keeping up to max lines.
+func NewRecentLines(max int) *RecentLines {
+	return &RecentLines{max: max}
+}
+
+// Add adds a line, dropping the oldest one once there are max lines.
+func (r *RecentLines) Add(line string) {
+	if r.max <= 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.lines) == r.max {
+		r.lines = r.lines[1:]
+	}
+	r.lines = append(r.lines, line)
+}
+
+// Lines returns the lines kept, oldest first.
+func (r *RecentLines) Lines() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string{}, r.lines...)
+}
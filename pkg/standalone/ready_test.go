@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForApp(t *testing.T) {
+	t.Run("port", func(t *testing.T) {
+		l, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		port := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			if l, err := net.Listen("tcp", l.Addr().String()); err == nil {
+				t.Cleanup(func() { l.Close() })
+			}
+		}()
+		assert.NoError(t, WaitForApp(AppReadiness{Port: port}, 5*time.Second, nil))
+	})
+
+	t.Run("health endpoint", func(t *testing.T) {
+		var ready atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/healthz" || !ready.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		port := server.Listener.Addr().(*net.TCPAddr).Port
+
+		err := WaitForApp(AppReadiness{Port: port, HealthEndpoint: "healthz"}, 300*time.Millisecond, nil)
+		assert.ErrorContains(t, err, "503 Service Unavailable")
+		assert.ErrorContains(t, err, "/healthz after 300ms")
+
+		ready.Store(true)
+		assert.NoError(t, WaitForApp(AppReadiness{HealthEndpoint: server.URL + "/healthz"}, time.Second, nil))
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		port := server.Listener.Addr().(*net.TCPAddr).Port
+		dir := t.TempDir()
+		// The certificate of the test server is self-signed, and isn't issued for localhost.
+		serverCA := filepath.Join(dir, "server-ca.pem")
+		require.NoError(t, os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+		otherCA := filepath.Join(dir, "other-ca.pem")
+		require.NoError(t, os.WriteFile(otherCA, selfSignedCertificate(t), 0o600))
+
+		assert.NoError(t, WaitForApp(AppReadiness{Port: port, SSL: true}, time.Second, nil))
+		assert.NoError(t, WaitForApp(AppReadiness{Port: port, HealthEndpoint: "/", SSL: true}, time.Second, nil))
+		assert.NoError(t, WaitForApp(AppReadiness{Port: port, SSL: true, CAFile: serverCA}, time.Second, nil))
+		assert.NoError(t, WaitForApp(AppReadiness{Port: port, HealthEndpoint: "/", SSL: true, CAFile: serverCA}, time.Second, nil))
+		err := WaitForApp(AppReadiness{Port: port, HealthEndpoint: "/", SSL: true, CAFile: otherCA}, 300*time.Millisecond, nil)
+		assert.ErrorContains(t, err, "isn't issued by the CA of "+otherCA)
+
+		// An app accepting connections without serving TLS isn't ready.
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer plain.Close()
+		assert.Error(t, WaitForApp(AppReadiness{Port: plain.Listener.Addr().(*net.TCPAddr).Port, SSL: true}, 300*time.Millisecond, nil))
+	})
+
+	t.Run("app exited", func(t *testing.T) {
+		exited := make(chan struct{})
+		close(exited)
+		err := WaitForApp(AppReadiness{Port: 1}, time.Minute, exited)
+		assert.EqualError(t, err, "the app exited before it was ready")
+	})
+}
+
+func TestValidateAppCAFile(t *testing.T) {
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(ca, selfSignedCertificate(t), 0o600))
+	assert.NoError(t, ValidateAppCAFile(ca))
+
+	notPEM := filepath.Join(dir, "ca.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+	assert.EqualError(t, ValidateAppCAFile(notPEM), "the app CA file "+notPEM+" has no PEM certificate")
+	assert.Error(t, ValidateAppCAFile(filepath.Join(dir, "missing.pem")))
+}
+
+// selfSignedCertificate returns the PEM of a new self-signed CA certificate.
+func selfSignedCertificate(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestRecentLines(t *testing.T) {
+	r := NewRecentLines(2)
+	assert.Empty(t, r.Lines())
+	for _, line := range []string{"one", "two", "three"} {
+		r.Add(line)
+	}
+	assert.Equal(t, []string{"two", "three"}, r.Lines())
+}
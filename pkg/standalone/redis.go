@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import "strconv"
+
+const (
+	// redisHardeningLabel labels the Redis containers with the version of the hardening they were created with, or
+	// redisUnhardened for the ones created with --unhardened. The containers created by older CLIs have no label.
+	redisHardeningLabel   = "io.dapr.cli.hardening"
+	redisHardeningVersion = "1"
+	redisUnhardened       = "none"
+
+	// redisUser is the redis user of the official image, which owns its data dir.
+	redisUser    = "999:999"
+	redisDataDir = "/data"
+	redisPort    = "6379"
+)
+
+// redisContainerArgs returns the arguments of the run command of the container runtime creating the Redis container
+// containerName from image, published on hostPort outside of dockerNetwork. The hardened container runs as the redis
+// user with a read-only root file system and no capabilities, and only writes to an anonymous volume for its data,
+// removed along with the container. Protected mode would refuse the connections forwarded from the published port,
+// so the port is published on the loopback interface only instead.
+func redisContainerArgs(containerName, image, dockerNetwork string, hostPort int, hardened bool) []string {
+	args := []string{
+		"run",
+		"--name", containerName,
+		"--restart", "always",
+		"-d",
+	}
+	args = append(args, platformArgs()...)
+	args = append(args, profileLabelArgs()...)
+	publish := strconv.Itoa(hostPort) + ":" + redisPort
+	if hardened {
+		args = append(args,
+			"--label", redisHardeningLabel+"="+redisHardeningVersion,
+			"--user", redisUser,
+			"--read-only",
+			"--tmpfs", "/tmp",
+			"--volume", redisDataDir,
+			"--cap-drop", "ALL",
+			"--security-opt", "no-new-privileges",
+		)
+		publish = "127.0.0.1:" + publish
+	} else {
+		args = append(args, "--label", redisHardeningLabel+"="+redisUnhardened)
+	}
+	if dockerNetwork != "" {
+		args = append(args,
+			"--network", dockerNetwork,
+			"--network-alias", DaprRedisContainerName)
+	} else {
+		args = append(args, "-p", publish)
+	}
+	args = append(args, image)
+	if hardened {
+		args = append(args, "redis-server", "--protected-mode", "no", "--appendonly", "yes", "--dir", redisDataDir)
+	}
+	return args
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisContainerArgs(t *testing.T) {
+	args := strings.Join(redisContainerArgs("dapr_redis", "redis:6", "", 6379, true), " ")
+	assert.Contains(t, args, "--label io.dapr.cli.hardening=1 --user 999:999 --read-only")
+	assert.Contains(t, args, "--cap-drop ALL")
+	assert.Contains(t, args, "-p 127.0.0.1:6379:6379 redis:6 redis-server --protected-mode no --appendonly yes --dir /data")
+
+	args = strings.Join(redisContainerArgs("dapr_redis_mynet", "redis:6", "mynet", 6379, true), " ")
+	assert.Contains(t, args, "--network mynet --network-alias dapr_redis redis:6 redis-server")
+	assert.NotContains(t, args, "-p ")
+
+	args = strings.Join(redisContainerArgs("dapr_redis", "redis:6", "", 6379, false), " ")
+	assert.Contains(t, args, "--label io.dapr.cli.hardening=none")
+	assert.NotContains(t, args, "--read-only")
+	assert.True(t, strings.HasSuffix(args, "-p 6379:6379 redis:6"), args)
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	go_version "github.com/hashicorp/go-version"
+
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	dockerHubRegistry = "docker.io"
+	// dockerHubRegistryHost is the host of the registry API of Docker Hub.
+	dockerHubRegistryHost = "registry-1.docker.io"
+	// maxTagListPages is the number of pages of tags listed from a registry, to suggest the nearby tags.
+	maxTagListPages = 10
+)
+
+// registryBaseURL returns the base URL of the registry API of the registry at host. It is replaced by the tests.
+var registryBaseURL = func(host string) string {
+	return "https://" + host
+}
+
+// bearerChallengeRegexp matches the parameters of the Bearer challenge of a registry, e.g.
+// `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:dapr/dapr:pull"`.
+var bearerChallengeRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// imageNotFoundError is returned when the image of a version doesn't exist in its registry, most likely because the
+// version isn't released.
+type imageNotFoundError struct {
+	image   string
+	version string
+	// tags are the released versions close to version, if the registry supports listing its tags.
+	tags []string
+}
+
+func (e *imageNotFoundError) Error() string {
+	msg := fmt.Sprintf("the image %s doesn't exist, version %s may not have been released", e.image, e.version)
+	if len(e.tags) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(e.tags, " or "))
+	}
+	return msg
+}
+
+func (e *imageNotFoundError) Unwrap() error {
+	return errVersionNotFound
+}
+
+// isImageNotFoundError returns true if err is the error of the container runtime pulling or running an image which
+// doesn't exist, in the formats of docker and podman.
+func isImageNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"manifest unknown", "not found: manifest", "image not known"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	// e.g. docker with the containerd image store: failed to resolve reference "ghcr.io/dapr/dapr:0.3.7": ghcr.io/dapr/dapr:0.3.7: not found
+	return (strings.Contains(msg, "manifest for ") || strings.Contains(msg, "failed to resolve reference")) && strings.HasSuffix(strings.TrimSpace(msg), "not found")
+}
+
+// imageNotFound returns an *imageNotFoundError for image of version, with the nearby tags of its repository, if err
+// is the error of the container runtime not finding it. It returns err otherwise.
+func imageNotFound(ctx context.Context, image, version string, err error) error {
+	if !isImageNotFoundError(err) {
+		return err
+	}
+	notFound := &imageNotFoundError{image: image, version: version}
+	if tags, listErr := listImageTags(ctx, image); listErr == nil {
+		notFound.tags = nearbyVersions(version, tags)
+	}
+	return notFound
+}
+
+// splitImageReference returns the host of the registry of image, with the one of Docker Hub if it has none, and its
+// repository, without the tag or the digest.
+func splitImageReference(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	host, repository := dockerHubRegistry, image
+	if first, rest, found := strings.Cut(image, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, repository = first, rest
+	}
+	if host == dockerHubRegistry {
+		host = dockerHubRegistryHost
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	return host, repository
+}
+
+// listImageTags lists the tags of the repository of image with the registry API, authenticating anonymously with the
+// token of the Bearer challenge of the registry if it requires one.
+func listImageTags(ctx context.Context, image string) ([]string, error) {
+	host, repository := splitImageReference(image)
+	base := registryBaseURL(host)
+	next := fmt.Sprintf("%s/v2/%s/tags/list?n=1000", base, repository)
+	token := ""
+	var tags []string
+	for page := 0; next != "" && page < maxTagListPages; page++ {
+		resp, err := registryGet(ctx, next, token)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && token == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if token, err = registryToken(ctx, challenge); err != nil {
+				return nil, err
+			}
+			if resp, err = registryGet(ctx, next, token); err != nil {
+				return nil, err
+			}
+		}
+		var list struct {
+			Tags []string `json:"tags"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("listing the tags of %s returned %s", repository, resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading the tags of %s: %w", repository, err)
+		}
+		tags = append(tags, list.Tags...)
+		next = nextPageURL(base, resp.Header.Get("Link"))
+	}
+	return tags, nil
+}
+
+// registryGet gets url from a registry API, with token if it is set.
+func registryGet(ctx context.Context, url, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return utils.HTTPClient().Do(req)
+}
+
+// registryToken returns the anonymous token of the Bearer challenge of a registry.
+func registryToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, m := range bearerChallengeRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", params["realm"])
+	}
+	query := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			query.Set(k, params[k])
+		}
+	}
+	realm.RawQuery = query.Encode()
+	resp, err := registryGet(ctx, realm.String(), "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting a registry token from %s returned %s", realm.Host, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error reading the registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", errors.New("the registry returned no token")
+	}
+	return token.Token, nil
+}
+
+// nextPageURL returns the URL of the next page of a list of the registry API at base, from the Link header of the
+// current page, or an empty string if it is the last one.
+func nextPageURL(base, link string) string {
+	// e.g. </v2/dapr/dapr/tags/list?last=1.11.0&n=1000>; rel="next"
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start || !strings.Contains(link[end:], `rel="next"`) {
+		return ""
+	}
+	next := link[start+1 : end]
+	if strings.HasPrefix(next, "/") {
+		next = base + next
+	}
+	return next
+}
+
+// nearbyVersions returns the versions among tags to suggest for version: the newest patches of its minor version,
+// or else the latest one. The tags of the variants and of the other platforms are left out, as the prereleases
+// unless version is one. There are none if version is one of the tags.
+func nearbyVersions(version string, tags []string) []string {
+	requested, err := go_version.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+	var versions []*go_version.Version
+	for _, tag := range tags {
+		v, parseErr := go_version.NewVersion(tag)
+		if parseErr != nil || (v.Prerelease() != "" && requested.Prerelease() == "") {
+			continue
+		}
+		if v.Equal(requested) {
+			return nil
+		}
+		versions = append(versions, v)
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	sort.Sort(sort.Reverse(go_version.Collection(versions)))
+	var nearby []string
+	for _, v := range versions {
+		if v.Segments()[0] == requested.Segments()[0] && v.Segments()[1] == requested.Segments()[1] && len(nearby) < maxVersionSuggestions {
+			nearby = append(nearby, v.Original())
+		}
+	}
+	if len(nearby) == 0 {
+		nearby = []string{versions[0].Original()}
+	}
+	return nearby
+}
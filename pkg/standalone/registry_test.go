@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The errors of the container runtimes for an image which doesn't exist, and for some which aren't about one.
+var (
+	dockerPullNotFound    = "Error response from daemon: manifest for registry.example.com/dapr/dapr:0.3.7 not found: manifest unknown: manifest unknown\n"
+	dockerRunNotFound     = "Unable to find image 'registry.example.com/dapr/dapr:0.3.7' locally\ndocker: Error response from daemon: manifest for registry.example.com/dapr/dapr:0.3.7 not found: manifest unknown: manifest unknown.\n"
+	containerdNotFound    = `Error response from daemon: failed to resolve reference "registry.example.com/dapr/dapr:0.3.7": registry.example.com/dapr/dapr:0.3.7: not found`
+	podmanPullNotFound    = "Trying to pull registry.example.com/dapr/dapr:0.3.7...\nError: initializing source docker://registry.example.com/dapr/dapr:0.3.7: reading manifest 0.3.7 in registry.example.com/dapr/dapr: manifest unknown\n"
+	podmanRunNotFound     = "Error: registry.example.com/dapr/dapr:0.3.7: image not known\n"
+	dockerPlatformMissing = "Error response from daemon: no matching manifest for linux/arm64/v8 in the manifest list entries\n"
+	dockerNotRunning      = "Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?\n"
+)
+
+func TestIsImageNotFoundError(t *testing.T) {
+	for _, msg := range []string{dockerPullNotFound, dockerRunNotFound, containerdNotFound, podmanPullNotFound, podmanRunNotFound} {
+		assert.True(t, isImageNotFoundError(errors.New(msg)), msg)
+	}
+	for _, msg := range []string{dockerPlatformMissing, dockerNotRunning, "exit status 125"} {
+		assert.False(t, isImageNotFoundError(errors.New(msg)), msg)
+	}
+	assert.False(t, isImageNotFoundError(nil))
+}
+
+func TestSplitImageReference(t *testing.T) {
+	tests := []struct {
+		image, host, repository string
+	}{
+		{"ghcr.io/dapr/dapr:1.11.0", "ghcr.io", "dapr/dapr"},
+		{"docker.io/daprio/dapr:1.11.0-mariner", dockerHubRegistryHost, "daprio/dapr"},
+		{"daprio/dapr:1.11.0", dockerHubRegistryHost, "daprio/dapr"},
+		{"redis:6", dockerHubRegistryHost, "library/redis"},
+		{"localhost:5000/dapr/dapr:1.11.0", "localhost:5000", "dapr/dapr"},
+		{"registry.example.com/dapr/dapr@sha256:0123", "registry.example.com", "dapr/dapr"},
+	}
+	for _, tc := range tests {
+		host, repository := splitImageReference(tc.image)
+		assert.Equal(t, tc.host, host, tc.image)
+		assert.Equal(t, tc.repository, repository, tc.image)
+	}
+}
+
+func TestNearbyVersions(t *testing.T) {
+	tags := []string{"latest", "0.2.0", "0.3.0", "0.3.0-mariner", "0.3.1", "0.3.1-linux-arm64", "1.0.0-rc.1", "0.11.0"}
+	assert.Equal(t, []string{"0.3.1", "0.3.0"}, nearbyVersions("0.3.7", tags))
+	assert.Equal(t, []string{"0.11.0"}, nearbyVersions("0.4.0", tags))
+	assert.Nil(t, nearbyVersions("0.3.0", tags))
+	assert.Nil(t, nearbyVersions("latest", tags))
+	assert.Equal(t, []string{"1.0.0-rc.1"}, nearbyVersions("1.0.0-rc.2", tags))
+}
+
+// serveRegistry serves the tags of the dapr/dapr repository in two pages, to the clients with the token of its
+// Bearer challenge. registryBaseURL points to it for the duration of the test.
+func serveRegistry(t *testing.T, tags ...string) {
+	t.Helper()
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.Equal(t, "repository:dapr/dapr:pull", r.URL.Query().Get("scope"))
+			json.NewEncoder(w).Encode(map[string]string{"token": "anonymous"})
+			return
+		case "/v2/dapr/dapr/tags/list":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+ts.URL+`/token",service="registry.example.com",scope="repository:dapr/dapr:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page := tags[:len(tags)/2]
+		if r.URL.Query().Get("last") != "" {
+			page = tags[len(tags)/2:]
+		} else {
+			w.Header().Set("Link", `</v2/dapr/dapr/tags/list?last=`+page[len(page)-1]+`&n=1000>; rel="next"`)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "dapr/dapr", "tags": page})
+	}))
+	t.Cleanup(ts.Close)
+	previous := registryBaseURL
+	t.Cleanup(func() { registryBaseURL = previous })
+	registryBaseURL = func(string) string { return ts.URL }
+}
+
+func TestListImageTags(t *testing.T) {
+	serveRegistry(t, "0.2.0", "0.3.0", "0.3.1", "1.0.0")
+	tags, err := listImageTags(context.Background(), "registry.example.com/dapr/dapr:0.3.7")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.2.0", "0.3.0", "0.3.1", "1.0.0"}, tags)
+
+	_, err = listImageTags(context.Background(), "registry.example.com/dapr/missing:0.3.7")
+	assert.Error(t, err)
+}
+
+func TestRunPlacementServiceImageNotFound(t *testing.T) {
+	setAirGapInit("")
+	serveRegistry(t, "0.2.0", "0.3.0", "0.3.1", "1.0.0")
+	tests := []struct {
+		name    string
+		runtime string
+		pullErr string
+		runErr  string
+	}{
+		{name: "docker pull", runtime: "docker", pullErr: dockerPullNotFound},
+		{name: "docker run", runtime: "docker", runErr: dockerRunNotFound},
+		{name: "podman pull", runtime: "podman", pullErr: podmanPullNotFound},
+		{name: "podman run", runtime: "podman", runErr: podmanRunNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(runWithRunCmd())
+			runCmd = func(runtimeCmd string, args ...string) (string, error) {
+				assert.Equal(t, tc.runtime, runtimeCmd)
+				switch args[0] {
+				case "image":
+					return "", errors.New("no such image")
+				case "pull":
+					if tc.pullErr != "" {
+						return "", errors.New(tc.pullErr)
+					}
+				case "run":
+					if tc.runErr != "" {
+						return "", errors.New(tc.runErr)
+					}
+				}
+				return "", nil
+			}
+
+			err := runPlacementService(context.Background(), initInfo{
+				runtimeVersion:   "0.3.7",
+				containerRuntime: tc.runtime,
+				imageRegistryURL: "registry.example.com",
+				pulls:            newWorkPool(1),
+			})
+			var notFound *imageNotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.ErrorIs(t, err, errVersionNotFound)
+			assert.Equal(t, "the image registry.example.com/dapr/dapr:0.3.7 doesn't exist, version 0.3.7 may not have been released, did you mean 0.3.1 or 0.3.0?", err.Error())
+		})
+	}
+
+	// The other failures are left as they are.
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(_ string, args ...string) (string, error) {
+		switch args[0] {
+		case "image":
+			return "", errors.New("no such image")
+		case "pull":
+			return "", errors.New(dockerNotRunning)
+		}
+		return "", nil
+	}
+	err := runPlacementService(context.Background(), initInfo{runtimeVersion: "0.3.7", containerRuntime: "docker", imageRegistryURL: "registry.example.com", pulls: newWorkPool(1)})
+	require.ErrorContains(t, err, "Cannot connect to the Docker daemon")
+	assert.NotErrorIs(t, err, errVersionNotFound)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"time"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+// InitReport describes the result of a self-hosted init.
+type InitReport struct {
+	RuntimeVersion   string            `json:"runtimeVersion"`
+	DashboardVersion string            `json:"dashboardVersion,omitempty"`
+	InstallDir       string            `json:"installDir"`
+	BinDir           string            `json:"binDir"`
+	ConfigFile       string            `json:"configFile,omitempty"`
+	Lockfile         string            `json:"lockfile,omitempty"`
+	SlimMode         bool              `json:"slimMode"`
+	Steps            []InitStepReport  `json:"steps"`
+	Containers       []string          `json:"containers,omitempty"`
+	Services         []string          `json:"services,omitempty"`
+	Elevation        *ElevationReport  `json:"elevation,omitempty"`
+	Signatures       []SignatureReport `json:"signatures,omitempty"`
+	MTLS             *MTLSCredentials  `json:"mtls,omitempty"`
+	Warnings         []InitWarning     `json:"warnings,omitempty"`
+	Error            string            `json:"error,omitempty"`
+	// UpToDate is set if the install was already the requested one, in which case init did nothing.
+	UpToDate bool `json:"upToDate,omitempty"`
+	// Interrupted describes the rollback of what init created, if it was interrupted.
+	Interrupted *InitRollback `json:"interrupted,omitempty"`
+	// ComponentsProvider is the provider of the state store and pubsub components.
+	ComponentsProvider string `json:"componentsProvider,omitempty"`
+	// Readiness is the readiness of the parts of the environment, if init waited for them.
+	Readiness []ReadinessCheck `json:"readiness,omitempty"`
+	// DurationMs is how long init took, including the wait for the environment to be usable.
+	DurationMs int64 `json:"durationMs,omitempty"`
+	// Timings are how long the downloads, extractions, image pulls and container starts of the steps took.
+	Timings []InitTiming `json:"timings,omitempty"`
+	// Notes are informational messages of the steps, such as the changes to PATH, which don't fail init with --strict.
+	Notes []InitWarning `json:"notes,omitempty"`
+	// KeptFiles are the files init left as they were, such as an edited configuration without --force.
+	KeptFiles []string `json:"keptFiles,omitempty"`
+}
+
+// InitWarning is a non-fatal issue reported by an init step.
+type InitWarning struct {
+	Step    string `json:"step"`
+	Message string `json:"msg"`
+}
+
+// InitStepReport describes the result of a single init step.
+type InitStepReport struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// addStepEvent records the warnings of the step the event is for, and its outcome once it has finished.
+// started holds the start time of each step. It is not safe for concurrent use.
+func (r *InitReport) addStepEvent(ev print.ProgressEvent, started map[string]time.Time) {
+	switch ev.Type {
+	case print.ProgressStepStarted:
+		started[ev.Step] = ev.Time
+	case print.ProgressStepSucceeded, print.ProgressStepFailed:
+		step := InitStepReport{
+			Name:       ev.Step,
+			Status:     string(ev.Type),
+			DurationMs: ev.Time.Sub(started[ev.Step]).Milliseconds(),
+		}
+		if ev.Type == print.ProgressStepFailed {
+			step.Error = ev.Message
+		}
+		r.Steps = append(r.Steps, step)
+	case print.ProgressStepWarning:
+		r.Warnings = append(r.Warnings, InitWarning{Step: ev.Step, Message: ev.Message})
+	}
+}
+
+// UninstallReport describes what a self-hosted uninstall removed.
+type UninstallReport struct {
+	RemovedDirectories []string `json:"removedDirectories"`
+	RemovedContainers  []string `json:"removedContainers"`
+	RemovedServices    []string `json:"removedServices,omitempty"`
+	NotFound           []string `json:"notFound,omitempty"`
+	// HomebrewFormula is the Homebrew formula which installed the runtime binaries, left for brew to remove.
+	HomebrewFormula string   `json:"homebrewFormula,omitempty"`
+	Errors          []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestInitReportAddStepEvent(t *testing.T) {
+	start := time.Now()
+	started := map[string]time.Time{}
+	report := &InitReport{}
+
+	report.addStepEvent(print.ProgressEvent{Step: "daprd binary", Type: print.ProgressStepStarted, Time: start}, started)
+	report.addStepEvent(print.ProgressEvent{Step: "Redis state store", Type: print.ProgressStepStarted, Time: start}, started)
+	report.addStepEvent(print.ProgressEvent{Step: "daprd binary", Type: print.ProgressStepUpdated, Time: start.Add(time.Second)}, started)
+	report.addStepEvent(print.ProgressEvent{Step: "Redis state store", Type: print.ProgressStepWarning, Message: "reusing container", Time: start}, started)
+	report.addStepEvent(print.ProgressEvent{Step: "daprd binary", Type: print.ProgressStepSucceeded, Time: start.Add(2 * time.Second)}, started)
+	report.addStepEvent(print.ProgressEvent{Step: "Redis state store", Type: print.ProgressStepFailed, Message: "port in use", Time: start.Add(time.Second)}, started)
+
+	assert.Equal(t, []InitStepReport{
+		{Name: "daprd binary", Status: "succeeded", DurationMs: 2000},
+		{Name: "Redis state store", Status: "failed", Error: "port in use", DurationMs: 1000},
+	}, report.Steps)
+	assert.Equal(t, []InitWarning{{Step: "Redis state store", Message: "reusing container"}}, report.Warnings)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	restartNo        = "no"
+	restartOnFailure = "on-failure"
+
+	restartBackoffMin = time.Second
+	restartBackoffMax = 30 * time.Second
+)
+
+// RestartPolicy is how the run command restarts the processes of an instance which exit.
+type RestartPolicy struct {
+	// OnFailure restarts the processes exiting with an error.
+	OnFailure bool
+	// MaxRestarts is the maximum number of restarts, unlimited if 0.
+	MaxRestarts int
+}
+
+// ParseRestartPolicy parses a restart policy: no, on-failure, or on-failure:max for at most max restarts.
+func ParseRestartPolicy(s string) (RestartPolicy, error) {
+	name, maxRestarts, hasMax := strings.Cut(strings.TrimSpace(s), ":")
+	switch {
+	case name == restartNo && !hasMax:
+		return RestartPolicy{}, nil
+	case name == restartOnFailure && !hasMax:
+		return RestartPolicy{OnFailure: true}, nil
+	case name == restartOnFailure:
+		n, err := strconv.Atoi(maxRestarts)
+		if err != nil || n <= 0 {
+			return RestartPolicy{}, fmt.Errorf("invalid restart policy %q: the maximum number of restarts must be a positive integer", s)
+		}
+		return RestartPolicy{OnFailure: true, MaxRestarts: n}, nil
+	}
+	return RestartPolicy{}, fmt.Errorf("invalid restart policy %q, valid values are: %s, %s, or %s:max", s, restartNo, restartOnFailure, restartOnFailure)
+}
+
+func (p RestartPolicy) String() string {
+	switch {
+	case !p.OnFailure:
+		return restartNo
+	case p.MaxRestarts > 0:
+		return fmt.Sprintf("%s:%d", restartOnFailure, p.MaxRestarts)
+	}
+	return restartOnFailure
+}
+
+// Allows reports whether a process which failed can be restarted once the instance was restarted restarts times.
+func (p RestartPolicy) Allows(restarts int) bool {
+	return p.OnFailure && (p.MaxRestarts == 0 || restarts < p.MaxRestarts)
+}
+
+// RestartBackoff returns how long to wait before the restart following restarts restarts: a second, doubled for
+// each restart, up to 30 seconds.
+func RestartBackoff(restarts int) time.Duration {
+	backoff := restartBackoffMin
+	for i := 0; i < restarts && backoff < restartBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > restartBackoffMax {
+		return restartBackoffMax
+	}
+	return backoff
+}
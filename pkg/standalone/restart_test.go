@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRestartPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected RestartPolicy
+	}{
+		{"no", RestartPolicy{}},
+		{"on-failure", RestartPolicy{OnFailure: true}},
+		{"on-failure:3", RestartPolicy{OnFailure: true, MaxRestarts: 3}},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			p, err := ParseRestartPolicy(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+			assert.Equal(t, tc.value, p.String())
+		})
+	}
+
+	for _, value := range []string{"always", "no:2", "on-failure:0", "on-failure:x", ""} {
+		t.Run("invalid "+value, func(t *testing.T) {
+			_, err := ParseRestartPolicy(value)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRestartPolicyAllows(t *testing.T) {
+	assert.False(t, RestartPolicy{}.Allows(0))
+	assert.True(t, RestartPolicy{OnFailure: true}.Allows(100))
+	p := RestartPolicy{OnFailure: true, MaxRestarts: 2}
+	assert.True(t, p.Allows(1))
+	assert.False(t, p.Allows(2))
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, RestartBackoff(0))
+	assert.Equal(t, 4*time.Second, RestartBackoff(2))
+	assert.Equal(t, 30*time.Second, RestartBackoff(5))
+	assert.Equal(t, 30*time.Second, RestartBackoff(1000))
+}
@@ -19,12 +19,10 @@ import (
 	"os"
 	"os/exec"
 	"reflect"
-	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/Pallinder/sillyname-go"
-	"github.com/phayes/freeport"
 	"gopkg.in/yaml.v2"
 
 	"github.com/dapr/cli/pkg/print"
@@ -71,6 +69,7 @@ type SharedRunConfig struct {
 	ComponentsPath      string            `arg:"components-path"` // Deprecated in run template file: use ResourcesPaths instead.
 	ResourcesPath       string            `yaml:"resourcesPath"`  // Deprecated in run template file: use ResourcesPaths instead.
 	ResourcesPaths      []string          `arg:"resources-path" yaml:"resourcesPaths"`
+	SkipValidation      bool              `yaml:"skipValidation"`
 	AppSSL              bool              `arg:"app-ssl" yaml:"appSSL"`
 	MaxRequestBodySize  int               `arg:"dapr-http-max-request-size" yaml:"daprHTTPMaxRequestSize" default:"-1"`
 	HTTPReadBufferSize  int               `arg:"dapr-http-read-buffer-size" yaml:"daprHTTPReadBufferSize" default:"-1"`
@@ -84,6 +83,8 @@ type SharedRunConfig struct {
 	Env                 map[string]string `yaml:"env"`
 	DaprdLogDestination LogDestType       `yaml:"daprdLogDestination"`
 	AppLogDestination   LogDestType       `yaml:"appLogDestination"`
+	// RuntimeArgs are appended as they are to the arguments of daprd.
+	RuntimeArgs []string `yaml:"runtimeArgs"`
 }
 
 func (meta *DaprMeta) newAppID() string {
@@ -95,6 +96,15 @@ func (meta *DaprMeta) newAppID() string {
 	}
 }
 
+// GenerateAppID returns a random app id which isn't used by a running instance.
+func GenerateAppID() (string, error) {
+	meta, err := newDaprMeta()
+	if err != nil {
+		return "", err
+	}
+	return meta.newAppID(), nil
+}
+
 func (config *RunConfig) validateResourcesPaths() error {
 	dirPath := config.ResourcesPaths
 	if len(dirPath) == 0 {
@@ -105,8 +115,18 @@ func (config *RunConfig) validateResourcesPaths() error {
 			return fmt.Errorf("error validating resources path %q : %w", dirPath, err)
 		}
 	}
+	if config.SkipValidation {
+		return nil
+	}
+	problems, err := ValidateComponents(dirPath...)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &ComponentsValidationError{Problems: problems}
+	}
 	componentsLoader := components.NewLocalComponents(dirPath...)
-	_, err := componentsLoader.LoadComponents()
+	_, err = componentsLoader.LoadComponents()
 	if err != nil {
 		return fmt.Errorf("error validating components in resources path %q : %w", dirPath, err)
 	}
@@ -119,11 +139,12 @@ func (config *RunConfig) validatePlacementHostAddr() error {
 		placementHostAddr = "localhost"
 	}
 	if indx := strings.Index(placementHostAddr, ":"); indx == -1 {
-		if runtime.GOOS == daprWindowsOS {
-			placementHostAddr = fmt.Sprintf("%s:6050", placementHostAddr)
-		} else {
-			placementHostAddr = fmt.Sprintf("%s:50005", placementHostAddr)
+		// The placement container may be published on another port than the default one, as recorded by init.
+		port := defaultPlacementHostPort()
+		if manifest, err := LoadInstallManifest(config.DaprdInstallPath); err == nil && manifest != nil && manifest.PlacementHostPort() > 0 {
+			port = manifest.PlacementHostPort()
 		}
+		placementHostAddr = fmt.Sprintf("%s:%d", placementHostAddr, port)
 	}
 	config.PlacementHostAddr = placementHostAddr
 	return nil
@@ -131,7 +152,7 @@ func (config *RunConfig) validatePlacementHostAddr() error {
 
 func (config *RunConfig) validatePort(portName string, portPtr *int, meta *DaprMeta) error {
 	if *portPtr <= 0 {
-		port, err := freeport.GetFreePort()
+		port, err := meta.freePort(basePorts[portName])
 		if err != nil {
 			return err
 		}
@@ -140,6 +161,9 @@ func (config *RunConfig) validatePort(portName string, portPtr *int, meta *DaprM
 	}
 
 	if meta.portExists(*portPtr) {
+		if owner := meta.portOwner(*portPtr); owner != "" {
+			return fmt.Errorf("invalid configuration for %s. Port %v is not available, it is used by %s", portName, *portPtr, owner)
+		}
 		return fmt.Errorf("invalid configuration for %s. Port %v is not available", portName, *portPtr)
 	}
 	return nil
@@ -153,6 +177,8 @@ func (config *RunConfig) Validate() error {
 
 	if config.AppID == "" {
 		config.AppID = meta.newAppID()
+	} else if meta.idExists(config.AppID) {
+		return fmt.Errorf("app id %q is already running, stop it first or use --replace", config.AppID)
 	}
 
 	err = config.validateResourcesPaths()
@@ -195,6 +221,13 @@ func (config *RunConfig) Validate() error {
 		}
 	}
 
+	if err = validateOneOf("app protocol", config.AppProtocol, appProtocols); err != nil {
+		return err
+	}
+	if err = validateOneOf("log level", config.LogLevel, logLevels); err != nil {
+		return err
+	}
+
 	if config.MaxConcurrency < 1 {
 		config.MaxConcurrency = -1
 	}
@@ -216,6 +249,8 @@ func (config *RunConfig) Validate() error {
 type DaprMeta struct {
 	ExistingIDs   map[string]bool
 	ExistingPorts map[int]bool
+	// portApps are the ids of the apps using the ports of the running instances.
+	portApps map[int]string
 }
 
 func (meta *DaprMeta) idExists(id string) bool {
@@ -248,6 +283,7 @@ func newDaprMeta() (*DaprMeta, error) {
 	meta := DaprMeta{}
 	meta.ExistingIDs = make(map[string]bool)
 	meta.ExistingPorts = make(map[int]bool)
+	meta.portApps = make(map[int]string)
 	dapr, err := List()
 	if err != nil {
 		return nil, err
@@ -257,6 +293,11 @@ func newDaprMeta() (*DaprMeta, error) {
 		meta.ExistingPorts[instance.AppPort] = true
 		meta.ExistingPorts[instance.HTTPPort] = true
 		meta.ExistingPorts[instance.GRPCPort] = true
+		for _, port := range []int{instance.AppPort, instance.HTTPPort, instance.GRPCPort} {
+			if port > 0 {
+				meta.portApps[port] = instance.AppID
+			}
+		}
 	}
 	return &meta, nil
 }
@@ -278,7 +319,7 @@ func (config *RunConfig) getArgs() []string {
 	if print.IsJSONLogEnabled() {
 		args = append(args, "--log-as-json")
 	}
-	return args
+	return append(args, config.RuntimeArgs...)
 }
 
 // Recursive function to get all the args from the config struct.
@@ -347,28 +388,14 @@ func (config *RunConfig) setDefaultFromSchemaRecursive(schema reflect.Value) {
 	}
 }
 
+// getEnv returns the environment variables of the app, set over the environment of the CLI. The variables set by
+// the CLI come last so that they win over the ones of Env.
 func (config *RunConfig) getEnv() []string {
 	env := []string{}
-	schema := reflect.ValueOf(*config)
-	for i := 0; i < schema.NumField(); i++ {
-		valueField := schema.Field(i).Interface()
-		typeField := schema.Type().Field(i)
-		key := typeField.Tag.Get("env")
-		if len(key) == 0 {
-			continue
-		}
-		if value, ok := valueField.(int); ok && value <= 0 {
-			// ignore unset numeric variables.
-			continue
-		}
-
-		value := fmt.Sprintf("%v", reflect.ValueOf(valueField))
-		env = append(env, fmt.Sprintf("%s=%v", key, value))
-	}
-	for k, v := range config.Env {
-		env = append(env, fmt.Sprintf("%s=%v", k, v))
+	for _, k := range sortedKeys(config.Env) {
+		env = append(env, fmt.Sprintf("%s=%v", k, config.Env[k]))
 	}
-	return env
+	return append(env, config.sidecarEnv()...)
 }
 
 func GetDaprCommand(config *RunConfig) (*exec.Cmd, error) {
@@ -379,6 +406,17 @@ func GetDaprCommand(config *RunConfig) (*exec.Cmd, error) {
 
 	args := config.getArgs()
 	cmd := exec.Command(daprCMD, args...)
+	if mtlsEndpoint(config.ConfigFile) != "" {
+		// The sidecar reads the trust anchors and the credentials generated by `dapr mtls generate` from its
+		// environment.
+		env, err := mtlsSidecarEnv(config.DaprdInstallPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the mTLS credentials: %w", err)
+		}
+		if env != nil {
+			cmd.Env = append(os.Environ(), env...)
+		}
+	}
 	return cmd, nil
 }
 
@@ -420,6 +458,7 @@ func GetAppCommand(config *RunConfig) *exec.Cmd {
 	cmd := exec.Command(command, args...)
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, config.getEnv()...)
+	setAppProcessGroup(cmd)
 
 	return cmd
 }
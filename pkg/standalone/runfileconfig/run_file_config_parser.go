@@ -163,15 +163,19 @@ func (a *RunFileConfig) setDefaultFields() error {
 	return nil
 }
 
-// Set AppID to the directory name of appDirPath.
+// Set AppID to the app id of the project in appDirPath: the app id pinned by its project config file, otherwise its
+// sanitized directory name.
 // appDirPath is a mandatory field in the run file and at this point it is already validated and resolved to its absolute path.
 func (a *RunFileConfig) setAppIDIfEmpty(app *App) error {
 	if app.AppID == "" {
-		basePath, err := a.getBasePathFromAbsPath(app.AppDirPath)
+		if _, err := a.getBasePathFromAbsPath(app.AppDirPath); err != nil {
+			return fmt.Errorf("error in setting the app id: %w", err)
+		}
+		resolved, err := standalone.ProjectAppID(app.AppDirPath)
 		if err != nil {
 			return fmt.Errorf("error in setting the app id: %w", err)
 		}
-		app.AppID = basePath
+		app.AppID = resolved.ID
 	}
 	return nil
 }
@@ -258,7 +262,7 @@ func (a *RunFileConfig) mergeCommonAndAppsEnv() {
 }
 
 // resolveResourcesFilePath resolves the resources path for the app.
-// Precedence order for resourcesPaths -> apps[i].resourcesPaths > apps[i].appDirPath/.dapr/resources > common.resourcesPaths > dapr default resources path.
+// Precedence order for resourcesPaths -> apps[i].resourcesPaths > apps[i].appDirPath/.dapr/resources > common.resourcesPaths > resolved components path.
 func (a *RunFileConfig) resolveResourcesFilePath(app *App) error {
 	if len(app.ResourcesPaths) > 0 {
 		return nil
@@ -269,17 +273,17 @@ func (a *RunFileConfig) resolveResourcesFilePath(app *App) error {
 	} else if len(a.Common.ResourcesPaths) > 0 {
 		app.ResourcesPaths = append(app.ResourcesPaths, a.Common.ResourcesPaths...)
 	} else {
-		daprDirPath, err := standalone.GetDaprRuntimePath(app.DaprdInstallPath)
+		componentsPath, err := standalone.ResolveComponentsPath("", app.DaprdInstallPath)
 		if err != nil {
-			return fmt.Errorf("error getting dapr install path: %w", err)
+			return fmt.Errorf("error getting components path: %w", err)
 		}
-		app.ResourcesPaths = []string{standalone.GetDaprComponentsPath(daprDirPath)}
+		app.ResourcesPaths = []string{componentsPath.Path}
 	}
 	return nil
 }
 
 // resolveConfigFilePath resolves the config file path for the app.
-// Precedence order for configFile -> apps[i].configFile > apps[i].appDirPath/.dapr/config.yaml > common.configFile > dapr default config file.
+// Precedence order for configFile -> apps[i].configFile > apps[i].appDirPath/.dapr/config.yaml > common.configFile > dapr default config file, if it exists.
 func (a *RunFileConfig) resolveConfigFilePath(app *App) error {
 	if app.ConfigFile != "" {
 		return nil
@@ -294,7 +298,10 @@ func (a *RunFileConfig) resolveConfigFilePath(app *App) error {
 		if err != nil {
 			return fmt.Errorf("error getting dapr install path: %w", err)
 		}
-		app.ConfigFile = standalone.GetDaprConfigPath(daprDirPath)
+		// The default config file is only passed to daprd if init has generated it.
+		if defaultConfigFile := standalone.GetDaprConfigPath(daprDirPath); utils.ValidateFilePath(defaultConfigFile) == nil {
+			app.ConfigFile = defaultConfigFile
+		}
 	}
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/dapr/cli/pkg/standalone"
+	"github.com/dapr/cli/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -353,6 +354,9 @@ func getResourcesAndConfigFilePaths(t *testing.T, daprInstallPath string) []stri
 	daprDirPath, err := standalone.GetDaprRuntimePath(daprInstallPath)
 	assert.NoError(t, err)
 	result[0] = standalone.GetDaprComponentsPath(daprDirPath)
-	result[1] = standalone.GetDaprConfigPath(daprDirPath)
+	// The default config file is only used if it exists.
+	if configFile := standalone.GetDaprConfigPath(daprDirPath); utils.ValidateFilePath(configFile) == nil {
+		result[1] = configFile
+	}
 	return result
 }
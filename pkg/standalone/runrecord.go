@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	process "github.com/shirou/gopsutil/process"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+const runRecordsDirName = "run"
+
+// RunRecord describes an instance started by the run command. The records are kept in the run directory of the
+// dapr install dir while the instances run.
+type RunRecord struct {
+	AppID           string    `json:"appId"`
+	DaprdPID        int       `json:"daprdPid"`
+	AppPID          int       `json:"appPid,omitempty"`
+	CliPID          int       `json:"cliPid"`
+	HTTPPort        int       `json:"httpPort"`
+	GRPCPort        int       `json:"grpcPort"`
+	AppPort         int       `json:"appPort,omitempty"`
+	Started         time.Time `json:"started"`
+	Command         string    `json:"command,omitempty"`
+	AppLogPath      string    `json:"appLogPath,omitempty"`
+	DaprdLogPath    string    `json:"daprdLogPath,omitempty"`
+	RunTemplatePath string    `json:"runTemplatePath,omitempty"`
+	RunTemplateName string    `json:"runTemplateName,omitempty"`
+	// ResourcesPaths, ConfigFile, PlacementHostAddress and UnixDomainSocket are the settings of daprd in effect.
+	ResourcesPaths       []string `json:"resourcesPaths,omitempty"`
+	ConfigFile           string   `json:"configFile,omitempty"`
+	PlacementHostAddress string   `json:"placementHostAddress,omitempty"`
+	UnixDomainSocket     string   `json:"unixDomainSocket,omitempty"`
+	// AppProtocol is the protocol daprd talks to the app with, e.g. https for an app serving TLS.
+	AppProtocol string `json:"appProtocol,omitempty"`
+	// Detached is set for the instances started in the background with run --detach.
+	Detached bool `json:"detached,omitempty"`
+	// ExitReason is set once the daprd or the app process of the instance exited.
+	ExitReason string `json:"exitReason,omitempty"`
+	// Restarts and LastExitCode are set once run --restart restarted a process of the instance which failed.
+	Restarts     int `json:"restarts,omitempty"`
+	LastExitCode int `json:"lastExitCode,omitempty"`
+	// Attached is the app run against the sidecar of the instance with run --attach, if there is one.
+	Attached *AttachedApp `json:"attached,omitempty"`
+	// DaprdCreateTime and CliCreateTime are the create times of the daprd and CLI processes, in milliseconds since the
+	// epoch, which tell them from the processes reusing their pids after they exited.
+	DaprdCreateTime int64 `json:"daprdCreateTime,omitempty"`
+	CliCreateTime   int64 `json:"cliCreateTime,omitempty"`
+}
+
+// AttachedApp is an app run with run --attach against the sidecar of an instance started by another run command.
+type AttachedApp struct {
+	CliPID  int       `json:"cliPid"`
+	AppPID  int       `json:"appPid"`
+	Command string    `json:"command,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// pidAlive reports whether a process with the pid is running. It is a variable for the tests.
+var pidAlive = func(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	exists, err := process.PidExists(int32(pid))
+	return err == nil && exists
+}
+
+// processCreateTime returns the create time of the process with the pid, in milliseconds since the epoch, or 0 if it
+// can't be told. It is a variable for the tests.
+var processCreateTime = func(pid int) int64 {
+	if pid <= 0 {
+		return 0
+	}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0
+	}
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return 0
+	}
+	return createTime
+}
+
+// ProcessCreateTime returns the create time of the process with the pid, for the run records, or 0 if it can't be
+// told.
+func ProcessCreateTime(pid int) int64 {
+	return processCreateTime(pid)
+}
+
+// recordedProcessAlive reports whether the process with the pid, recorded with createTime, is running, rather than
+// another process which reused its pid. The records without a create time only have the pid to go by.
+func recordedProcessAlive(pid int, createTime int64) bool {
+	if !pidAlive(pid) {
+		return false
+	}
+	if createTime == 0 {
+		return true
+	}
+	current := processCreateTime(pid)
+	return current == 0 || current == createTime
+}
+
+// GetRunRecordsPath returns the directory of the run records in daprDir.
+func GetRunRecordsPath(daprDir string) string {
+	return path_filepath.Join(daprDir, runRecordsDirName)
+}
+
+// GetRunLogsPath returns the directory of the log files of the detached instance of appID in daprDir.
+func GetRunLogsPath(daprDir, appID string) string {
+	return path_filepath.Join(GetRunRecordsPath(daprDir), appID)
+}
+
+func runRecordPath(daprDir, appID string) string {
+	return path_filepath.Join(GetRunRecordsPath(daprDir), appID+".json")
+}
+
+// WriteRunRecord writes the record of an instance to daprDir, replacing the record of the same app id.
+func WriteRunRecord(daprDir string, record RunRecord) error {
+	if err := os.MkdirAll(GetRunRecordsPath(daprDir), print.PrivateDirMode); err != nil {
+		return fmt.Errorf("error creating run records directory: %w", err)
+	}
+	b, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so that readers never see a partial record.
+	path := runRecordPath(daprDir, record.AppID)
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, b, print.PrivateFileMode); err != nil {
+		return fmt.Errorf("error writing run record %s: %w", path, err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing run record %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveRunRecord removes the record of the instance of appID from daprDir, if there is one.
+func RemoveRunRecord(daprDir, appID string) error {
+	err := os.Remove(runRecordPath(daprDir, appID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing run record: %w", err)
+	}
+	return nil
+}
+
+// SetRunRecordExitReason sets the exit reason of the record of the instance of appID in daprDir. A missing record,
+// for an instance which exited while starting, is not an error.
+func SetRunRecordExitReason(daprDir, appID, reason string) error {
+	return updateRunRecord(daprDir, appID, func(record *RunRecord) { record.ExitReason = reason })
+}
+
+// SetRunRecordAttachedApp sets the app attached to the instance of appID in daprDir, or removes it if app is nil. A
+// missing record, for an instance which exited, is not an error.
+func SetRunRecordAttachedApp(daprDir, appID string, app *AttachedApp) error {
+	return updateRunRecord(daprDir, appID, func(record *RunRecord) { record.Attached = app })
+}
+
+func updateRunRecord(daprDir, appID string, update func(*RunRecord)) error {
+	b, err := os.ReadFile(runRecordPath(daprDir, appID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading run record: %w", err)
+	}
+	var record RunRecord
+	if err = json.Unmarshal(b, &record); err != nil {
+		return fmt.Errorf("error reading run record: %w", err)
+	}
+	update(&record)
+	return WriteRunRecord(daprDir, record)
+}
+
+// FindAttachTarget returns the record of the instance of appID in daprDir, for run --attach to run an app against
+// its sidecar. The sidecar must be running, without an app of its own or attached.
+func FindAttachTarget(daprDir, appID string) (RunRecord, error) {
+	records, err := ReadRunRecords(daprDir)
+	if err != nil {
+		return RunRecord{}, err
+	}
+	for _, record := range records {
+		if record.AppID != appID {
+			continue
+		}
+		switch {
+		case !recordedProcessAlive(record.DaprdPID, record.DaprdCreateTime):
+			return RunRecord{}, fmt.Errorf("the Dapr sidecar of app id %q is not running anymore", appID)
+		case record.AppPID > 0 && pidAlive(record.AppPID):
+			return RunRecord{}, fmt.Errorf("app id %q already runs its app (pid %d): start its Dapr sidecar without an app command to attach to it", appID, record.AppPID)
+		case record.Attached != nil && pidAlive(record.Attached.CliPID):
+			return RunRecord{}, fmt.Errorf("an app is already attached to app id %q (pid %d)", appID, record.Attached.AppPID)
+		}
+		return record, nil
+	}
+	return RunRecord{}, fmt.Errorf("no Dapr sidecar started by dapr run is running for app id %q: start it first with dapr run --app-id %s", appID, appID)
+}
+
+// ReadRunRecords returns the records of the running instances in daprDir, sorted by app id. The records of the
+// instances whose daprd and CLI processes aren't running anymore, or whose pids were reused by other processes, are
+// stale and removed, and malformed records are skipped. An instance whose app keeps running after its daprd process exited still has its CLI process.
+func ReadRunRecords(daprDir string) ([]RunRecord, error) {
+	records, _, err := pruneRunRecords(daprDir)
+	return records, err
+}
+
+// pruneRunRecords returns the records of the running instances in daprDir, sorted by app id, along with the stale
+// records it removed, as ReadRunRecords.
+func pruneRunRecords(daprDir string) ([]RunRecord, []RunRecord, error) {
+	dir := GetRunRecordsPath(daprDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading run records directory: %w", err)
+	}
+
+	var records, removed []RunRecord
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := path_filepath.Join(dir, entry.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var record RunRecord
+		if err = json.Unmarshal(b, &record); err != nil || record.AppID == "" {
+			continue
+		}
+		if !recordedProcessAlive(record.DaprdPID, record.DaprdCreateTime) && !recordedProcessAlive(record.CliPID, record.CliCreateTime) {
+			if os.Remove(path) == nil {
+				removed = append(removed, record)
+			}
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].AppID < records[j].AppID })
+	sort.Slice(removed, func(i, j int) bool { return removed[i].AppID < removed[j].AppID })
+	return records, removed, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakePIDs(t *testing.T, alive ...int) {
+	t.Helper()
+	original := pidAlive
+	t.Cleanup(func() { pidAlive = original })
+	pidAlive = func(pid int) bool {
+		for _, p := range alive {
+			if p == pid {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestRunRecords(t *testing.T) {
+	daprDir := t.TempDir()
+	fakePIDs(t, 100, 200)
+
+	records, err := ReadRunRecords(daprDir)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	started := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, r := range []RunRecord{
+		{AppID: "orders", DaprdPID: 200, CliPID: 10, HTTPPort: 3501, GRPCPort: 50002, Started: started},
+		{AppID: "checkout", DaprdPID: 100, AppPID: 101, CliPID: 10, HTTPPort: 3500, GRPCPort: 50001, AppPort: 8080, Started: started, Command: "python app.py"},
+		{AppID: "stale", DaprdPID: 300, CliPID: 10, Started: started},
+	} {
+		require.NoError(t, WriteRunRecord(daprDir, r))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(GetRunRecordsPath(daprDir), "broken.json"), []byte("{"), 0o600))
+
+	records, err = ReadRunRecords(daprDir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "checkout", records[0].AppID)
+	assert.Equal(t, "python app.py", records[0].Command)
+	assert.True(t, records[0].Started.Equal(started))
+	assert.Equal(t, "orders", records[1].AppID)
+	assert.NoFileExists(t, filepath.Join(GetRunRecordsPath(daprDir), "stale.json"))
+
+	require.NoError(t, RemoveRunRecord(daprDir, "orders"))
+	require.NoError(t, RemoveRunRecord(daprDir, "orders"))
+	records, err = ReadRunRecords(daprDir)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	t.Run("app outliving daprd", func(t *testing.T) {
+		require.NoError(t, WriteRunRecord(daprDir, RunRecord{AppID: "lingering", DaprdPID: 300, AppPID: 301, CliPID: 200}))
+		require.NoError(t, SetRunRecordExitReason(daprDir, "lingering", "daprd exited: exit status 1"))
+		require.NoError(t, SetRunRecordExitReason(daprDir, "missing", "app exited"))
+
+		records, err := ReadRunRecords(daprDir)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "daprd exited: exit status 1", records[1].ExitReason)
+	})
+}
+
+func TestFindAttachTarget(t *testing.T) {
+	daprDir := t.TempDir()
+	fakePIDs(t, 10, 100, 200, 201, 300, 400, 401)
+	for _, r := range []RunRecord{
+		{AppID: "sidecar", DaprdPID: 100, CliPID: 10},
+		{AppID: "withapp", DaprdPID: 200, AppPID: 201, CliPID: 10},
+		{AppID: "attached", DaprdPID: 300, CliPID: 10, Attached: &AttachedApp{CliPID: 400, AppPID: 401}},
+		{AppID: "gone", DaprdPID: 500, CliPID: 10},
+	} {
+		require.NoError(t, WriteRunRecord(daprDir, r))
+	}
+
+	record, err := FindAttachTarget(daprDir, "sidecar")
+	require.NoError(t, err)
+	assert.Equal(t, 100, record.DaprdPID)
+
+	_, err = FindAttachTarget(daprDir, "withapp")
+	assert.ErrorContains(t, err, "already runs its app (pid 201)")
+	_, err = FindAttachTarget(daprDir, "attached")
+	assert.ErrorContains(t, err, "already attached")
+	_, err = FindAttachTarget(daprDir, "gone")
+	assert.ErrorContains(t, err, "is not running anymore")
+	_, err = FindAttachTarget(daprDir, "missing")
+	assert.ErrorContains(t, err, "dapr run --app-id missing")
+
+	require.NoError(t, SetRunRecordAttachedApp(daprDir, "attached", nil))
+	_, err = FindAttachTarget(daprDir, "attached")
+	assert.NoError(t, err)
+}
+
+func TestRunRecordsReusedPIDs(t *testing.T) {
+	daprDir := t.TempDir()
+	fakePIDs(t, 100, 200, 10)
+	original := processCreateTime
+	t.Cleanup(func() { processCreateTime = original })
+	// The pids 100 and 10 were reused by other processes since the instance recorded them.
+	processCreateTime = func(pid int) int64 { return int64(pid) * 1000 }
+
+	for _, r := range []RunRecord{
+		{AppID: "reused", DaprdPID: 100, DaprdCreateTime: 1, CliPID: 10, CliCreateTime: 2},
+		{AppID: "running", DaprdPID: 200, DaprdCreateTime: 200000, CliPID: 10, CliCreateTime: 2},
+		{AppID: "legacy", DaprdPID: 100, CliPID: 11},
+	} {
+		require.NoError(t, WriteRunRecord(daprDir, r))
+	}
+	records, err := ReadRunRecords(daprDir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "legacy", records[0].AppID, "the records without a create time only have the pid to go by")
+	assert.Equal(t, "running", records[1].AppID)
+
+	merged := mergeRunRecords(nil, []RunRecord{{AppID: "exited", DaprdPID: 100, DaprdCreateTime: 1, CliPID: 10, CliCreateTime: 10000}})
+	require.Len(t, merged, 1)
+	assert.Zero(t, merged[0].DaprdPID, "the pid of daprd is another process's")
+	assert.False(t, merged[0].Orphan)
+	merged = mergeRunRecords(nil, []RunRecord{{AppID: "exited", DaprdPID: 200, CliPID: 10, CliCreateTime: 2}})
+	assert.True(t, merged[0].Orphan, "the pid of the CLI is another process's")
+}
+
+func TestMergeRunRecords(t *testing.T) {
+	fakePIDs(t, 10, 100, 200, 300)
+	started := time.Now().Add(-time.Minute)
+	list := []ListOutput{
+		{AppID: "checkout", DaprdPID: 100, HTTPPort: 3500},
+		{AppID: "untracked", DaprdPID: 400},
+	}
+	records := []RunRecord{
+		{AppID: "checkout", DaprdPID: 100, AppPID: 101, CliPID: 10, Command: "python app.py", AppLogPath: "/logs/app.log"},
+		{AppID: "orders", DaprdPID: 200, CliPID: 11, HTTPPort: 3501, GRPCPort: 50002, Started: started},
+	}
+
+	merged := mergeRunRecords(list, records)
+	require.Len(t, merged, 3)
+
+	assert.Equal(t, 101, merged[0].AppPID)
+	assert.Equal(t, 10, merged[0].CliPID)
+	assert.Equal(t, "python app.py", merged[0].Command)
+	assert.Equal(t, "/logs/app.log", merged[0].AppLogPath)
+	assert.False(t, merged[0].Orphan)
+
+	assert.Equal(t, "untracked", merged[1].AppID)
+	assert.True(t, merged[1].Orphan)
+
+	assert.Equal(t, "orders", merged[2].AppID)
+	assert.Equal(t, 3501, merged[2].HTTPPort)
+	assert.Equal(t, "1m", merged[2].Age)
+	assert.True(t, merged[2].Orphan, "the CLI process of the record is gone")
+
+	t.Run("attached app", func(t *testing.T) {
+		merged := mergeRunRecords([]ListOutput{{AppID: "sidecar", DaprdPID: 100}}, []RunRecord{
+			{AppID: "sidecar", DaprdPID: 100, CliPID: 10, Attached: &AttachedApp{CliPID: 300, AppPID: 301, Command: "node app.js"}},
+		})
+		require.Len(t, merged, 1)
+		assert.Equal(t, 300, merged[0].AttachedCliPID)
+		assert.Equal(t, 301, merged[0].AppPID)
+		assert.Equal(t, "node app.js", merged[0].Command)
+	})
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	path_filepath "path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	scaffoldManifestFileName = "dapr.yaml"
+	scaffoldTemplateSuffix   = ".tmpl"
+)
+
+// scaffolds are the templates of the projects New scaffolds, one directory of app files by template, and the run
+// manifest. The files are Go templates, suffixed so that the Go files aren't built.
+//
+//go:embed scaffolds
+var scaffolds embed.FS
+
+// scaffoldCommands are the commands running the app of each template, from its directory.
+var scaffoldCommands = map[string][]string{
+	"go-http":     {"go", "run", "."},
+	"python-http": {"python3", "app.py"},
+	"node-http":   {"node", "app.js"},
+}
+
+// NewProjectOptions are the options of NewProject.
+type NewProjectOptions struct {
+	// Template is the language of the app, one of ProjectTemplates.
+	Template string
+	// Dir is the directory the project is written to, it must be empty unless Force is set.
+	Dir string
+	// AppID is the ID of the app, by default the name of Dir.
+	AppID        string
+	AppPort      int
+	DaprHTTPPort int
+	// Force writes the project to a directory which isn't empty, overwriting the files of the project.
+	Force bool
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath. The components
+	// connect to the Redis container of its install.
+	DaprRuntimePath string
+}
+
+// ProjectTemplates returns the templates NewProject can scaffold.
+func ProjectTemplates() []string {
+	templates := make([]string, 0, len(scaffoldCommands))
+	for t := range scaffoldCommands {
+		templates = append(templates, t)
+	}
+	sort.Strings(templates)
+	return templates
+}
+
+// NewProject scaffolds a project in opts.Dir: a minimal HTTP app in the language of the template, in a directory
+// named after the app ID, a components directory with the state store and pub/sub components of the Redis container
+// of init, and a run manifest explaining how to run it with run -f. The templates are embedded in the CLI. It
+// returns the paths of the files written, relative to opts.Dir.
+func NewProject(opts NewProjectOptions) ([]string, error) {
+	command, ok := scaffoldCommands[opts.Template]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q, the templates are %s", opts.Template, strings.Join(ProjectTemplates(), ", "))
+	}
+	if strings.TrimSpace(opts.Dir) == "" {
+		return nil, errors.New("the directory of the project is required")
+	}
+	dir, err := path_filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.AppID == "" {
+		opts.AppID = strings.ToLower(path_filepath.Base(dir))
+	}
+	if !componentNameRegexp.MatchString(opts.AppID) {
+		return nil, fmt.Errorf("invalid app ID %q: it must consist of lower case alphanumeric characters, '-' or '.', and start and end with an alphanumeric character, set one with --app-id", opts.AppID)
+	}
+	for _, port := range []int{opts.AppPort, opts.DaprHTTPPort} {
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %d", port)
+		}
+	}
+	if opts.AppPort == opts.DaprHTTPPort {
+		return nil, fmt.Errorf("the app and its sidecar can't both listen on port %d", opts.AppPort)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(entries) > 0 && !opts.Force {
+		return nil, fmt.Errorf("%s is not empty, use --force to scaffold the project in it anyway", dir)
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = strconv.Quote(arg)
+	}
+	data := struct {
+		Template     string
+		AppID        string
+		AppPort      int
+		DaprHTTPPort int
+		Command      string
+	}{opts.Template, opts.AppID, opts.AppPort, opts.DaprHTTPPort, strings.Join(quoted, ", ")}
+
+	files := map[string]string{scaffoldManifestFileName: "scaffolds/" + scaffoldManifestFileName + scaffoldTemplateSuffix}
+	appFiles, err := fs.ReadDir(scaffolds, "scaffolds/"+opts.Template)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range appFiles {
+		files[path_filepath.Join(opts.AppID, strings.TrimSuffix(f.Name(), scaffoldTemplateSuffix))] = "scaffolds/" + opts.Template + "/" + f.Name()
+	}
+	written := make([]string, 0, len(files)+2)
+	for name, source := range files {
+		tmpl, err := template.ParseFS(scaffolds, source)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("error rendering %s: %w", name, err)
+		}
+		path := path_filepath.Join(dir, name)
+		if err = os.MkdirAll(path_filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		// #nosec G306
+		if err = os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return nil, err
+		}
+		written = append(written, name)
+	}
+
+	componentsDir := path_filepath.Join(dir, defaultComponentsDirName)
+	if err = os.MkdirAll(componentsDir, 0o755); err != nil {
+		return nil, err
+	}
+	redisAddress := scaffoldRedisAddress(opts.DaprRuntimePath)
+	for name, create := range map[string]func(string, string) error{stateStoreYamlFileName: createRedisStateStore, pubSubYamlFileName: createRedisPubSub} {
+		// The component files aren't overwritten by create, they exist here only when Force is set.
+		if err = os.Remove(path_filepath.Join(componentsDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err = create(redisAddress, componentsDir); err != nil {
+			return nil, fmt.Errorf("error creating the %s component file: %w", name, err)
+		}
+		written = append(written, path_filepath.Join(defaultComponentsDirName, name))
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+// scaffoldRedisAddress returns the address the components of a new project reach the Redis container of the install
+// of daprRuntimePath at, from the host, on its default port if it isn't recorded.
+func scaffoldRedisAddress(daprRuntimePath string) string {
+	port := redisPort
+	if manifest, err := LoadInstallManifest(daprRuntimePath); err == nil && manifest != nil {
+		for _, c := range manifest.Containers {
+			if strings.HasPrefix(c.Name, DaprRedisContainerName) && len(c.HostPorts) > 0 {
+				port = strconv.Itoa(c.HostPorts[0])
+				break
+			}
+		}
+	}
+	return net.JoinHostPort("localhost", port)
+}
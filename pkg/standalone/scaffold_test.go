@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewProject(t *testing.T) {
+	for _, tmpl := range ProjectTemplates() {
+		t.Run(tmpl, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "Orders")
+			files, err := NewProject(NewProjectOptions{Template: tmpl, Dir: dir, AppPort: 3000, DaprHTTPPort: 3500, DaprRuntimePath: t.TempDir()})
+			require.NoError(t, err)
+			assert.Contains(t, files, scaffoldManifestFileName)
+			assert.Contains(t, files, filepath.Join("components", "statestore.yaml"))
+			assert.Contains(t, files, filepath.Join("components", "pubsub.yaml"))
+			for _, f := range files {
+				assert.FileExists(t, filepath.Join(dir, f))
+			}
+
+			b, err := os.ReadFile(filepath.Join(dir, scaffoldManifestFileName))
+			require.NoError(t, err)
+			var manifest struct {
+				Version int `yaml:"version"`
+				Common  struct {
+					ResourcesPaths []string `yaml:"resourcesPaths"`
+				} `yaml:"common"`
+				Apps []struct {
+					AppID        string   `yaml:"appID"`
+					AppDirPath   string   `yaml:"appDirPath"`
+					AppPort      int      `yaml:"appPort"`
+					DaprHTTPPort int      `yaml:"daprHTTPPort"`
+					Command      []string `yaml:"command"`
+				} `yaml:"apps"`
+			}
+			require.NoError(t, yaml.Unmarshal(b, &manifest))
+			assert.Equal(t, 1, manifest.Version)
+			assert.Equal(t, []string{"./components"}, manifest.Common.ResourcesPaths)
+			require.Len(t, manifest.Apps, 1)
+			assert.Equal(t, "orders", manifest.Apps[0].AppID)
+			assert.Equal(t, scaffoldCommands[tmpl], manifest.Apps[0].Command)
+			assert.Equal(t, 3000, manifest.Apps[0].AppPort)
+			assert.DirExists(t, filepath.Join(dir, manifest.Apps[0].AppDirPath))
+			assert.Contains(t, string(b), "dapr run -f .")
+
+			b, err = os.ReadFile(filepath.Join(dir, "components", "statestore.yaml"))
+			require.NoError(t, err)
+			assert.Contains(t, string(b), "localhost:6379")
+		})
+	}
+}
+
+func TestNewProjectFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewProject(NewProjectOptions{Template: "go-http", Dir: dir, AppID: "orders", AppPort: 8080, DaprHTTPPort: 3501})
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "orders", "main.go"), nil, parser.AllErrors)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(dir, "orders", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `env("APP_PORT", "8080")`)
+	assert.Contains(t, string(b), `env("DAPR_HTTP_PORT", "3501")`)
+
+	dir = t.TempDir()
+	_, err = NewProject(NewProjectOptions{Template: "node-http", Dir: dir, AppID: "orders", AppPort: 8080, DaprHTTPPort: 3501})
+	require.NoError(t, err)
+	b, err = os.ReadFile(filepath.Join(dir, "orders", "package.json"))
+	require.NoError(t, err)
+	var pkg map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &pkg))
+	assert.Equal(t, "orders", pkg["name"])
+}
+
+func TestNewProjectNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o644))
+	opts := NewProjectOptions{Template: "python-http", Dir: dir, AppID: "orders", AppPort: 3000, DaprHTTPPort: 3500}
+	_, err := NewProject(opts)
+	assert.ErrorContains(t, err, "use --force")
+	assert.NoFileExists(t, filepath.Join(dir, scaffoldManifestFileName))
+
+	opts.Force = true
+	_, err = NewProject(opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "orders", "app.py"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+	// The project can be scaffolded again over itself.
+	_, err = NewProject(opts)
+	require.NoError(t, err)
+
+	_, err = NewProject(NewProjectOptions{Template: "java-http", Dir: t.TempDir(), AppPort: 3000, DaprHTTPPort: 3500})
+	assert.ErrorContains(t, err, "the templates are go-http, node-http, python-http")
+	_, err = NewProject(NewProjectOptions{Template: "go-http", Dir: t.TempDir(), AppID: "Orders!", AppPort: 3000, DaprHTTPPort: 3500})
+	assert.ErrorContains(t, err, "invalid app ID")
+}
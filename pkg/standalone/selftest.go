@@ -0,0 +1,551 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	path_filepath "path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	SelftestPass    = "pass"
+	SelftestFail    = "fail"
+	SelftestSkipped = "skipped"
+
+	// The layers of the environment a selftest check exercises.
+	SelftestLayerContainer = "container"
+	SelftestLayerSidecar   = "sidecar"
+	SelftestLayerComponent = "component"
+
+	// DefaultSelftestTimeout bounds the checks through the sidecar, from its start to the receipt of the test message.
+	DefaultSelftestTimeout = 30 * time.Second
+
+	selftestAppIDPrefix    = "dapr-selftest-"
+	selftestTopic          = "dapr-selftest"
+	selftestTopicRoute     = "/dapr-selftest"
+	selftestEchoMethod     = "echo"
+	selftestRequestTimeout = 10 * time.Second
+	selftestPollInterval   = 250 * time.Millisecond
+)
+
+// SelftestOptions are the options of RunSelftest.
+type SelftestOptions struct {
+	// DaprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprRuntimePath  string
+	DockerNetwork    string
+	ContainerRuntime string
+	// StateStore and PubSub are the names of the components to test, statestore and pubsub by default, which init
+	// writes.
+	StateStore string
+	PubSub     string
+	Timeout    time.Duration
+}
+
+// SelftestCheck is the result of a check of the selftest, with the layer of the environment it exercises.
+type SelftestCheck struct {
+	Name    string `json:"name"`
+	Layer   string `json:"layer"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// SelftestReport is the result of RunSelftest.
+type SelftestReport struct {
+	AppID string `json:"appId"`
+	// DaprdLogFile is the log file of the sidecar of the selftest, which is kept.
+	DaprdLogFile string          `json:"daprdLogFile,omitempty"`
+	Checks       []SelftestCheck `json:"checks"`
+	Failures     int             `json:"failures"`
+}
+
+func (r *SelftestReport) add(c SelftestCheck) {
+	if c.Status == SelftestFail {
+		r.Failures++
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// RunSelftest exercises the local environment as an app would: it starts an echo app in the CLI, listening on a free
+// port, and a sidecar for it, with the components of the environment. Through the sidecar, it saves a state and
+// reads it back, publishes a message to a test topic which the app receives, and invokes the echo method of the
+// app. The sidecar and the app are stopped before it returns. Each failing check has the layer which broke, and a
+// hint with the logs to look at. The error is for the selftest which couldn't run at all.
+func RunSelftest(ctx context.Context, opts SelftestOptions) (*SelftestReport, error) {
+	if opts.StateStore == "" {
+		opts.StateStore = "statestore"
+	}
+	if opts.PubSub == "" {
+		opts.PubSub = "pubsub"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultSelftestTimeout
+	}
+	installDir, err := GetDaprRuntimePath(opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := selftestNonce()
+	if err != nil {
+		return nil, err
+	}
+	report := &SelftestReport{AppID: selftestAppIDPrefix + suffix}
+
+	environment, err := GetEnvironmentStatus(opts.DaprRuntimePath, opts.DockerNetwork, opts.ContainerRuntime)
+	if err != nil {
+		return nil, err
+	}
+	if !addSelftestEnvironmentChecks(report, environment, utils.GetContainerRuntimeCmd(opts.ContainerRuntime)) {
+		return report, nil
+	}
+
+	app, err := startSelftestApp(opts.PubSub)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the echo app: %w", err)
+	}
+	defer app.close()
+
+	sidecar, err := startSelftestSidecar(opts, installDir, report.AppID, app.port)
+	if sidecar != nil {
+		report.DaprdLogFile = sidecar.logFile
+		defer sidecar.stop()
+	}
+	logsHint := "see the sidecar log " + report.DaprdLogFile
+	if err != nil {
+		report.add(SelftestCheck{Name: "sidecar", Layer: SelftestLayerSidecar, Status: SelftestFail, Message: err.Error(), Hint: logsHint})
+		return report, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	if err = sidecar.waitReady(ctx); err != nil {
+		report.add(SelftestCheck{Name: "sidecar", Layer: SelftestLayerSidecar, Status: SelftestFail, Message: err.Error(), Hint: logsHint})
+		return report, nil
+	}
+	report.add(SelftestCheck{Name: "sidecar", Layer: SelftestLayerSidecar, Status: SelftestPass, Message: "started on port " + strconv.Itoa(sidecar.httpPort)})
+
+	runSelftestChecks(ctx, report, sidecar.client(), app, opts, environment.ComponentsProvider, logsHint, sidecar.componentsDir)
+	return report, nil
+}
+
+// addSelftestEnvironmentChecks adds the checks of the runtime binary and of the containers of environment to report,
+// and reports whether the selftest can go on.
+func addSelftestEnvironmentChecks(report *SelftestReport, environment *EnvironmentStatus, runtimeCmd string) bool {
+	for _, c := range environment.Components {
+		if c.Name == daprRuntimeFilePrefix && !c.Healthy() {
+			report.add(SelftestCheck{Name: daprRuntimeFilePrefix, Layer: SelftestLayerSidecar, Status: SelftestFail, Message: c.Status, Hint: "run `dapr init`"})
+			return false
+		}
+	}
+	if environment.SlimMode {
+		report.add(SelftestCheck{Name: "containers", Layer: SelftestLayerContainer, Status: SelftestSkipped, Message: "slim mode runs no containers"})
+	}
+	for _, c := range checkEnvironmentComponents(environment, runtimeCmd) {
+		// The sidecar doesn't need the dashboard.
+		if c.Name == dashboardFilePrefix {
+			continue
+		}
+		check := SelftestCheck{Name: c.Name, Layer: SelftestLayerContainer, Status: SelftestPass, Message: c.Message, Hint: c.Hint}
+		if c.Status != DoctorPass {
+			check.Status = SelftestFail
+		}
+		report.add(check)
+	}
+	return true
+}
+
+// selftestSidecar is the sidecar started by the selftest.
+type selftestSidecar struct {
+	httpPort      int
+	logFile       string
+	componentsDir string
+	process       *os.Process
+	exited        chan struct{}
+	exitErr       error
+}
+
+// startSelftestSidecar starts the sidecar of appID for the echo app on appPort, logging to the run logs dir of the
+// app in installDir. The sidecar is returned along with the error once it is started, for its log file.
+func startSelftestSidecar(opts SelftestOptions, installDir string, appID string, appPort int) (*selftestSidecar, error) {
+	componentsPath, err := ResolveComponentsPath("", opts.DaprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	config := &RunConfig{
+		AppID:   appID,
+		AppPort: appPort,
+		SharedRunConfig: SharedRunConfig{
+			ComponentsPath:   componentsPath.Path,
+			AppProtocol:      "http",
+			LogLevel:         "info",
+			DaprdInstallPath: opts.DaprRuntimePath,
+		},
+	}
+	if configFile := GetDaprConfigPath(installDir); utils.ValidateFilePath(configFile) == nil {
+		config.ConfigFile = configFile
+	}
+	config.SetDefaultFromSchema()
+	config.AppPort = appPort
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	cmd, err := GetDaprCommand(config)
+	if err != nil {
+		return nil, err
+	}
+
+	logsDir := GetRunLogsPath(installDir, appID)
+	if err = os.MkdirAll(logsDir, print.PrivateDirMode); err != nil {
+		return nil, err
+	}
+	sidecar := &selftestSidecar{
+		httpPort:      config.HTTPPort,
+		logFile:       path_filepath.Join(logsDir, "daprd.log"),
+		componentsDir: componentsPath.Path,
+		exited:        make(chan struct{}),
+	}
+	log, err := os.Create(sidecar.logFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout, cmd.Stderr = log, log
+	if err = cmd.Start(); err != nil {
+		log.Close()
+		return sidecar, fmt.Errorf("error starting daprd: %w", err)
+	}
+	sidecar.process = cmd.Process
+	go func() {
+		sidecar.exitErr = cmd.Wait()
+		log.Close()
+		close(sidecar.exited)
+	}()
+	return sidecar, nil
+}
+
+func (s *selftestSidecar) client() *selftestClient {
+	return &selftestClient{baseURL: fmt.Sprintf("http://localhost:%d", s.httpPort)}
+}
+
+// waitReady waits for the sidecar to report it is healthy, until ctx is done or the sidecar exited.
+func (s *selftestSidecar) waitReady(ctx context.Context) error {
+	c := s.client()
+	for {
+		status, _, err := c.do(ctx, http.MethodGet, "/v1.0/healthz", nil)
+		if err == nil && status == http.StatusNoContent {
+			return nil
+		}
+		select {
+		case <-s.exited:
+			return fmt.Errorf("daprd exited while starting: %v", s.exitErr)
+		case <-ctx.Done():
+			if err == nil {
+				err = fmt.Errorf("its health endpoint answers with %d", status)
+			}
+			return fmt.Errorf("daprd isn't healthy: %w", err)
+		case <-time.After(selftestPollInterval):
+		}
+	}
+}
+
+// stop asks the sidecar to shut down, and kills it if it doesn't.
+func (s *selftestSidecar) stop() {
+	if s.process == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := s.client().do(ctx, http.MethodPost, "/v1.0/shutdown", nil); err == nil {
+		select {
+		case <-s.exited:
+			return
+		case <-ctx.Done():
+		}
+	}
+	_ = s.process.Kill()
+	<-s.exited
+}
+
+// selftestClient calls the HTTP API of a sidecar.
+type selftestClient struct {
+	baseURL string
+}
+
+func (c *selftestClient) do(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, selftestRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, b, err
+}
+
+// expect calls the sidecar, and returns an error with the response unless its status is one of statuses.
+func (c *selftestClient) expect(ctx context.Context, method, path string, body []byte, statuses ...int) ([]byte, error) {
+	status, b, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statuses {
+		if status == s {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s answered with %d: %s", method, path, status, strings.TrimSpace(string(b)))
+}
+
+// runSelftestChecks runs the checks of the state store, the pubsub and the service invocation of the selftest
+// through the sidecar of c, for the echo app. The components are skipped if init set up none, as told by provider.
+func runSelftestChecks(ctx context.Context, report *SelftestReport, c *selftestClient, app *selftestApp, opts SelftestOptions, provider, logsHint, componentsDir string) {
+	components, err := selftestComponents(ctx, c)
+	if err != nil {
+		report.add(SelftestCheck{Name: "metadata", Layer: SelftestLayerSidecar, Status: SelftestFail, Message: err.Error(), Hint: logsHint})
+		return
+	}
+	if provider == ComponentsProviderNone {
+		for _, name := range []string{"state " + opts.StateStore, "pubsub " + opts.PubSub} {
+			report.add(SelftestCheck{Name: name, Layer: SelftestLayerComponent, Status: SelftestSkipped, Message: "init set up no components, with --components-provider " + ComponentsProviderNone})
+		}
+	} else {
+		runSelftestComponentChecks(ctx, report, c, app, opts, components, logsHint, componentsDir)
+	}
+
+	check := SelftestCheck{Name: "service invocation", Layer: SelftestLayerSidecar, Status: SelftestPass, Message: "invoked the echo method of the app"}
+	if err = selftestInvoke(ctx, c, report.AppID); err != nil {
+		check.Status, check.Message, check.Hint = SelftestFail, err.Error(), logsHint
+	}
+	report.add(check)
+}
+
+// runSelftestComponentChecks runs the checks of the state store and the pubsub of the selftest through the sidecar of
+// c, which loaded components.
+func runSelftestComponentChecks(ctx context.Context, report *SelftestReport, c *selftestClient, app *selftestApp, opts SelftestOptions, components map[string]string, logsHint, componentsDir string) {
+	componentHint := func(name string) string {
+		return fmt.Sprintf("check the component %s in %s, and %s", name, componentsDir, logsHint)
+	}
+	missing := func(name, kind string) SelftestCheck {
+		return SelftestCheck{Layer: SelftestLayerComponent, Status: SelftestSkipped, Message: fmt.Sprintf("the sidecar loaded no %s component named %s", kind, name), Hint: componentHint(name)}
+	}
+
+	var err error
+	check := SelftestCheck{Name: "state " + opts.StateStore, Layer: SelftestLayerComponent, Status: SelftestPass}
+	if _, ok := components[opts.StateStore]; !ok {
+		check = missing(opts.StateStore, "state store")
+		check.Name = "state " + opts.StateStore
+	} else if err = selftestState(ctx, c, opts.StateStore, report.AppID); err != nil {
+		check.Status, check.Message, check.Hint = SelftestFail, err.Error(), componentHint(opts.StateStore)
+	} else {
+		check.Message = "saved and read back a value"
+	}
+	report.add(check)
+
+	check = SelftestCheck{Name: "pubsub " + opts.PubSub, Layer: SelftestLayerComponent, Status: SelftestPass}
+	if _, ok := components[opts.PubSub]; !ok {
+		check = missing(opts.PubSub, "pubsub")
+		check.Name = "pubsub " + opts.PubSub
+	} else if err = selftestPubSub(ctx, c, app, opts.PubSub); err != nil {
+		check.Status, check.Message, check.Hint = SelftestFail, err.Error(), componentHint(opts.PubSub)
+	} else {
+		check.Message = "published and received a message on topic " + selftestTopic
+	}
+	report.add(check)
+}
+
+// selftestComponents returns the types of the components loaded by the sidecar of c, by name.
+func selftestComponents(ctx context.Context, c *selftestClient) (map[string]string, error) {
+	b, err := c.expect(ctx, http.MethodGet, "/v1.0/metadata", nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var metadata struct {
+		Components []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"components"`
+	}
+	if err = json.Unmarshal(b, &metadata); err != nil {
+		return nil, fmt.Errorf("error parsing the metadata of the sidecar: %w", err)
+	}
+	components := make(map[string]string, len(metadata.Components))
+	for _, component := range metadata.Components {
+		components[component.Name] = component.Type
+	}
+	return components, nil
+}
+
+// selftestState saves a value in store, reads it back and deletes it.
+func selftestState(ctx context.Context, c *selftestClient, store, key string) error {
+	value, err := selftestNonce()
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal([]map[string]string{{"key": key, "value": value}})
+	if _, err = c.expect(ctx, http.MethodPost, "/v1.0/state/"+store, body, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("error saving the state: %w", err)
+	}
+	defer c.do(context.Background(), http.MethodDelete, "/v1.0/state/"+store+"/"+key, nil) //nolint:errcheck
+	b, err := c.expect(ctx, http.MethodGet, "/v1.0/state/"+store+"/"+key, nil, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return fmt.Errorf("error reading the state back: %w", err)
+	}
+	var read string
+	if err = json.Unmarshal(b, &read); err != nil || read != value {
+		return fmt.Errorf("the state read back is %q instead of %q", strings.TrimSpace(string(b)), value)
+	}
+	return nil
+}
+
+// selftestPubSub publishes a message to the test topic of pubsub, and waits for the app to receive it.
+func selftestPubSub(ctx context.Context, c *selftestClient, app *selftestApp, pubsub string) error {
+	id, err := selftestNonce()
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]string{"id": id})
+	if _, err = c.expect(ctx, http.MethodPost, "/v1.0/publish/"+pubsub+"/"+selftestTopic, body, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("error publishing: %w", err)
+	}
+	if !app.waitMessage(ctx, id) {
+		return fmt.Errorf("the app didn't receive the message published on topic %s", selftestTopic)
+	}
+	return nil
+}
+
+// selftestInvoke invokes the echo method of appID, and checks that it answers with the request.
+func selftestInvoke(ctx context.Context, c *selftestClient, appID string) error {
+	nonce, err := selftestNonce()
+	if err != nil {
+		return err
+	}
+	body := []byte(strconv.Quote(nonce))
+	b, err := c.expect(ctx, http.MethodPost, "/v1.0/invoke/"+appID+"/method/"+selftestEchoMethod, body, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(b, body) {
+		return fmt.Errorf("the echo method answered %q instead of %q", b, body)
+	}
+	return nil
+}
+
+// selftestApp is the echo app of the selftest, which subscribes to the test topic of its pubsub.
+type selftestApp struct {
+	port     int
+	server   *http.Server
+	lock     sync.Mutex
+	received map[string]bool
+	notify   chan struct{}
+}
+
+// startSelftestApp starts the echo app on a free port of the loopback interface.
+func startSelftestApp(pubsub string) (*selftestApp, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	app := &selftestApp{port: ln.Addr().(*net.TCPAddr).Port, received: map[string]bool{}, notify: make(chan struct{}, 1)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dapr/subscribe", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]string{{"pubsubname": pubsub, "topic": selftestTopic, "route": selftestTopicRoute}}) //nolint:errcheck
+	})
+	mux.HandleFunc(selftestTopicRoute, app.receive)
+	mux.HandleFunc("/"+selftestEchoMethod, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body) //nolint:errcheck
+	})
+	app.server = &http.Server{Handler: mux, ReadHeaderTimeout: selftestRequestTimeout} //nolint:exhaustruct
+	go app.server.Serve(ln)                                                            //nolint:errcheck
+	return app, nil
+}
+
+// receive records the id of the cloud event of a message of the test topic.
+func (a *selftestApp) receive(w http.ResponseWriter, r *http.Request) {
+	var event struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.lock.Lock()
+	a.received[event.Data.ID] = true
+	a.lock.Unlock()
+	select {
+	case a.notify <- struct{}{}:
+	default:
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"SUCCESS"}`)) //nolint:errcheck
+}
+
+// waitMessage waits for the app to receive the message with id, and reports whether it did before ctx is done.
+func (a *selftestApp) waitMessage(ctx context.Context, id string) bool {
+	for {
+		a.lock.Lock()
+		received := a.received[id]
+		a.lock.Unlock()
+		if received {
+			return true
+		}
+		select {
+		case <-a.notify:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (a *selftestApp) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.server.Shutdown(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		a.server.Close()
+	}
+}
+
+// selftestNonce returns a random hex string, to tell the values of a selftest from the ones of another.
+func selftestNonce() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
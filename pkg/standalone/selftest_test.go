@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selftestSidecarAPI serves the parts of the API of a sidecar the selftest uses, for the app on appPort.
+func selftestSidecarAPI(t *testing.T, appPort int, components []string, stateStatus int) *selftestClient {
+	var lock sync.Mutex
+	state := map[string]string{}
+	forward := func(path string, body []byte) (*http.Response, error) {
+		return http.Post(fmt.Sprintf("http://127.0.0.1:%d%s", appPort, path), "application/json", bytes.NewReader(body))
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1.0/metadata":
+			var loaded []map[string]string
+			for _, c := range components {
+				loaded = append(loaded, map[string]string{"name": c})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"components": loaded})
+		case strings.HasPrefix(r.URL.Path, "/v1.0/state/"):
+			if stateStatus != http.StatusOK {
+				http.Error(w, `{"errorCode":"ERR_STATE_SAVE","message":"connection refused"}`, stateStatus)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if r.Method == http.MethodPost {
+				var items []map[string]string
+				require.NoError(t, json.Unmarshal(body, &items))
+				state[items[0]["key"]] = items[0]["value"]
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			b, _ := json.Marshal(state[strings.TrimPrefix(r.URL.Path, "/v1.0/state/statestore/")])
+			w.Write(b)
+		case strings.HasPrefix(r.URL.Path, "/v1.0/publish/pubsub/"+selftestTopic):
+			event, _ := json.Marshal(map[string]json.RawMessage{"data": body})
+			resp, err := forward(selftestTopicRoute, event)
+			require.NoError(t, err)
+			resp.Body.Close()
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/method/"+selftestEchoMethod):
+			resp, err := forward("/"+selftestEchoMethod, body)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			io.Copy(w, resp.Body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return &selftestClient{baseURL: ts.URL}
+}
+
+func TestSelftestApp(t *testing.T) {
+	app, err := startSelftestApp("pubsub")
+	require.NoError(t, err)
+	defer app.close()
+	base := fmt.Sprintf("http://127.0.0.1:%d", app.port)
+
+	resp, err := http.Get(base + "/dapr/subscribe")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.JSONEq(t, `[{"pubsubname": "pubsub", "topic": "dapr-selftest", "route": "/dapr-selftest"}]`, string(b))
+
+	resp, err = http.Post(base+selftestTopicRoute, "application/json", strings.NewReader(`{"data": {"id": "abc"}}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, app.waitMessage(ctx, "abc"))
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.False(t, app.waitMessage(ctx, "other"))
+}
+
+func TestRunSelftestChecks(t *testing.T) {
+	opts := SelftestOptions{StateStore: "statestore", PubSub: "pubsub"}
+	run := func(t *testing.T, components []string, stateStatus int, provider string) *SelftestReport {
+		app, err := startSelftestApp("pubsub")
+		require.NoError(t, err)
+		t.Cleanup(app.close)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		report := &SelftestReport{AppID: "dapr-selftest-1234"}
+		runSelftestChecks(ctx, report, selftestSidecarAPI(t, app.port, components, stateStatus), app, opts, provider, "see the sidecar log daprd.log", "/home/me/.dapr/components")
+		return report
+	}
+
+	t.Run("all pass", func(t *testing.T) {
+		report := run(t, []string{"statestore", "pubsub"}, http.StatusOK, ComponentsProviderRedis)
+		require.Len(t, report.Checks, 3)
+		for _, c := range report.Checks {
+			assert.Equal(t, SelftestPass, c.Status, c.Name+": "+c.Message)
+		}
+		assert.Zero(t, report.Failures)
+	})
+
+	t.Run("broken state store", func(t *testing.T) {
+		report := run(t, []string{"statestore"}, http.StatusInternalServerError, ComponentsProviderRedis)
+		require.Len(t, report.Checks, 3)
+		assert.Equal(t, SelftestCheck{
+			Name:    "state statestore",
+			Layer:   SelftestLayerComponent,
+			Status:  SelftestFail,
+			Message: `error saving the state: POST /v1.0/state/statestore answered with 500: {"errorCode":"ERR_STATE_SAVE","message":"connection refused"}`,
+			Hint:    "check the component statestore in /home/me/.dapr/components, and see the sidecar log daprd.log",
+		}, report.Checks[0])
+		// Slim mode has no pubsub component.
+		assert.Equal(t, SelftestSkipped, report.Checks[1].Status)
+		assert.Equal(t, "the sidecar loaded no pubsub component named pubsub", report.Checks[1].Message)
+		assert.Equal(t, SelftestPass, report.Checks[2].Status)
+		assert.Equal(t, 1, report.Failures)
+	})
+
+	t.Run("no components provider", func(t *testing.T) {
+		report := run(t, []string{"statestore"}, http.StatusInternalServerError, ComponentsProviderNone)
+		require.Len(t, report.Checks, 3)
+		assert.Equal(t, SelftestCheck{Name: "state statestore", Layer: SelftestLayerComponent, Status: SelftestSkipped, Message: "init set up no components, with --components-provider none"}, report.Checks[0])
+		assert.Equal(t, SelftestSkipped, report.Checks[1].Status)
+		assert.Equal(t, SelftestPass, report.Checks[2].Status)
+		assert.Zero(t, report.Failures)
+	})
+}
+
+func TestAddSelftestEnvironmentChecks(t *testing.T) {
+	report := &SelftestReport{}
+	assert.False(t, addSelftestEnvironmentChecks(report, &EnvironmentStatus{Components: []EnvironmentComponent{
+		{Name: daprRuntimeFilePrefix, Kind: "binary", Status: RuntimeNotInstalled},
+	}}, "docker"))
+	assert.Equal(t, []SelftestCheck{{Name: daprRuntimeFilePrefix, Layer: SelftestLayerSidecar, Status: SelftestFail, Message: RuntimeNotInstalled, Hint: "run `dapr init`"}}, report.Checks)
+
+	report = &SelftestReport{}
+	assert.True(t, addSelftestEnvironmentChecks(report, &EnvironmentStatus{Components: []EnvironmentComponent{
+		{Name: daprRuntimeFilePrefix, Kind: "binary", Status: RuntimeInstalled},
+		{Name: DaprPlacementContainerName, Kind: "container", Status: ContainerRunning},
+		{Name: DaprRedisContainerName, Kind: "container", Status: "exited"},
+	}}, "docker"))
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, SelftestPass, report.Checks[0].Status)
+	assert.Equal(t, SelftestCheck{Name: DaprRedisContainerName, Layer: SelftestLayerContainer, Status: SelftestFail, Message: "exited", Hint: "start it with `docker start dapr_redis`, or see `docker logs dapr_redis`"}, report.Checks[1])
+	assert.Equal(t, 1, report.Failures)
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	path_filepath "path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/pkg/print"
+	daprsyscall "github.com/dapr/cli/pkg/syscall"
+)
+
+// Init systems which run the services of slim mode.
+const (
+	// InitSystemNone leaves the placement binary of slim mode to be run by hand.
+	InitSystemNone = "none"
+	// InitSystemSystemd runs the services as systemd user units.
+	InitSystemSystemd = "systemd"
+	// InitSystemWindowsService runs the services as Windows services, hosted by the CLI executable.
+	InitSystemWindowsService = "windows-service"
+	// InitSystemPidfile runs the services in the background, with their PIDs in pidfiles, where systemd isn't
+	// available.
+	InitSystemPidfile = "pidfile"
+)
+
+// States of the services of slim mode, as reported by status.
+const (
+	ServiceRunning  = "running"
+	ServiceStarting = "starting"
+	ServiceStopped  = "stopped"
+	ServiceFailed   = "failed"
+)
+
+// ErrServiceNeedsElevation is returned when the Windows services are managed from a prompt which isn't elevated.
+var ErrServiceNeedsElevation = errors.New("managing Windows services needs an elevated prompt, run the command from a terminal started with Run as administrator")
+
+// WindowsServiceHostCommand is the hidden command of the CLI which hosts the Windows services of slim mode.
+const WindowsServiceHostCommand = "service-host"
+
+const (
+	servicesDirName    = "services"
+	serviceUnitPrefix  = "dapr-"
+	redisServerCommand = "redis-server"
+	redisServicePort   = 6379
+)
+
+// IsValidInitSystem reports whether initSystem is one of the init systems init --init-system accepts.
+func IsValidInitSystem(initSystem string) bool {
+	return initSystem == InitSystemNone || initSystem == InitSystemSystemd || initSystem == InitSystemWindowsService
+}
+
+// ManifestService is a service of slim mode run by the init system recorded in the install manifest.
+type ManifestService struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+	// Unit is the path of the systemd unit file of the service.
+	Unit string `json:"unit,omitempty"`
+	// PIDFile and LogFile are the files of the service run in the background.
+	PIDFile string `json:"pidFile,omitempty"`
+	LogFile string `json:"logFile,omitempty"`
+}
+
+// serviceName returns the name the init system knows the service as.
+func (s ManifestService) serviceName() string {
+	return serviceUnitPrefix + s.Name
+}
+
+// unitName returns the name of the systemd unit of the service.
+func (s ManifestService) unitName() string {
+	return s.serviceName() + ".service"
+}
+
+// windowsServiceHostArgs returns the arguments of the CLI executable hosting the Windows service of s.
+func windowsServiceHostArgs(s ManifestService) []string {
+	return append([]string{WindowsServiceHostCommand, "--name", s.serviceName(), "--"}, s.Command...)
+}
+
+// slimServices returns the services of slim mode: the placement binary installed in binDir, and, if withRedis is set,
+// the redis-server installed on the machine unless something already listens on the Redis port.
+func slimServices(binDir string, withRedis bool, warn func(format string, args ...any)) []ManifestService {
+	services := []ManifestService{{
+		Name:    placementServiceFilePrefix,
+		Command: []string{binaryFilePathWithDir(binDir, placementServiceFilePrefix), "--port", strconv.Itoa(defaultPlacementHostPort())},
+	}}
+	if !withRedis {
+		return services
+	}
+	redis, err := exec.LookPath(redisServerCommand)
+	if err != nil {
+		return services
+	}
+	if conn, dialErr := net.DialTimeout("tcp", net.JoinHostPort(daprDefaultHost, strconv.Itoa(redisServicePort)), time.Second); dialErr == nil {
+		conn.Close()
+		warn("Redis already listens on port %d, no service is installed for %s", redisServicePort, redis)
+		return services
+	}
+	return append(services, ManifestService{Name: "redis", Command: []string{redis, "--port", strconv.Itoa(redisServicePort)}})
+}
+
+// systemdAvailable reports whether the services can run as systemd user units on this machine.
+func systemdAvailable() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	_, err := runCmd("systemctl", "--user", "show-environment")
+	return err == nil
+}
+
+// systemdUserUnitDir returns the directory of the systemd user units.
+func systemdUserUnitDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return path_filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return path_filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// systemdUnit returns the content of the systemd unit file of the service.
+func systemdUnit(s ManifestService) string {
+	args := make([]string, len(s.Command))
+	for i, arg := range s.Command {
+		if strings.ContainsAny(arg, " \t\"\\") {
+			arg = strconv.Quote(arg)
+		}
+		// systemd expands the specifiers starting with %, which the install paths can contain.
+		arg = strings.ReplaceAll(arg, "%", "%%")
+		args[i] = arg
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Dapr %s service, installed by dapr init
+After=network.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`, s.Name, strings.Join(args, " "))
+}
+
+// setupServices installs and starts the services of slim mode with initSystem, and returns them along with the init
+// system which actually runs them: the services run in the background with pidfiles instead of systemd where it
+// isn't available, with a warning.
+func setupServices(installDir string, services []ManifestService, initSystem string, warn func(format string, args ...any)) ([]ManifestService, string, error) {
+	if initSystem == "" || initSystem == InitSystemNone {
+		return nil, InitSystemNone, nil
+	}
+	if initSystem == InitSystemSystemd && !systemdAvailable() {
+		warn("systemd is not available, the services run in the background with pidfiles in %s instead", path_filepath.Join(installDir, servicesDirName))
+		initSystem = InitSystemPidfile
+	}
+	var err error
+	switch initSystem {
+	case InitSystemSystemd:
+		err = startSystemdServices(services)
+	case InitSystemWindowsService:
+		var cliExe string
+		if cliExe, err = os.Executable(); err == nil {
+			err = installWindowsServices(cliExe, services)
+		}
+	default:
+		err = startPidfileServices(path_filepath.Join(installDir, servicesDirName), services)
+	}
+	return services, initSystem, err
+}
+
+// startSystemdServices writes the systemd user units of the services, and enables and starts them.
+func startSystemdServices(services []ManifestService) error {
+	dir, err := systemdUserUnitDir()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, print.PrivateDirMode); err != nil {
+		return err
+	}
+	for i := range services {
+		services[i].Unit = path_filepath.Join(dir, services[i].unitName())
+		if err = os.WriteFile(services[i].Unit, []byte(systemdUnit(services[i])), print.PrivateFileMode); err != nil {
+			return fmt.Errorf("error writing the systemd unit of %s: %w", services[i].Name, err)
+		}
+	}
+	if _, err = runCmd("systemctl", "--user", "daemon-reload"); err != nil {
+		return fmt.Errorf("error reloading the systemd user units: %w", err)
+	}
+	for _, s := range services {
+		if _, err = runCmd("systemctl", "--user", "enable", "--now", s.unitName()); err != nil {
+			return fmt.Errorf("error starting the %s service: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// startPidfileServices starts the services in the background, with their PIDs and logs in dir.
+func startPidfileServices(dir string, services []ManifestService) error {
+	if err := os.MkdirAll(dir, print.PrivateDirMode); err != nil {
+		return err
+	}
+	for i := range services {
+		s := &services[i]
+		s.PIDFile = path_filepath.Join(dir, s.Name+".pid")
+		s.LogFile = path_filepath.Join(dir, s.Name+".log")
+		if pid, err := readPIDFile(s.PIDFile); err == nil && pidAlive(pid) {
+			continue
+		}
+		logFile, err := os.OpenFile(s.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, print.PrivateFileMode)
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(s.Command[0], s.Command[1:]...)
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+		daprsyscall.DetachProcess(cmd)
+		err = cmd.Start()
+		logFile.Close()
+		if err != nil {
+			return fmt.Errorf("error starting the %s service: %w", s.Name, err)
+		}
+		// The service outlives the CLI, which only reaps it if it exits first.
+		go cmd.Wait() //nolint:errcheck
+		if err = os.WriteFile(s.PIDFile, []byte(strconv.Itoa(cmd.Process.Pid)), print.PrivateFileMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPIDFile(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// ServiceStopResult is the result of stopping a service of slim mode.
+type ServiceStopResult struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Err    error  `json:"-"`
+}
+
+// StopServices stops the services of slim mode run by the init system recorded in the install manifest, giving the
+// ones run with pidfiles grace to exit. daprRuntimePath is based on the --runtime-path command line flag, as for
+// GetDaprRuntimePath.
+func StopServices(daprRuntimePath string, grace time.Duration) ([]ServiceStopResult, error) {
+	manifest, err := LoadInstallManifest(daprRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil || len(manifest.Services) == 0 {
+		return nil, errors.New("init installed no services, see init --init-system")
+	}
+	return stopServices(manifest, grace), nil
+}
+
+func stopServices(manifest *InstallManifest, grace time.Duration) []ServiceStopResult {
+	var results []ServiceStopResult
+	for _, s := range manifest.Services {
+		r := ServiceStopResult{Name: s.Name, Result: ProcessStopped}
+		switch manifest.InitSystem {
+		case InitSystemSystemd:
+			if _, err := runCmd("systemctl", "--user", "stop", s.unitName()); err != nil {
+				r.Err = fmt.Errorf("error stopping the %s service: %w", s.Name, err)
+			}
+		case InitSystemWindowsService:
+			r.Result, r.Err = stopWindowsService(s)
+		case InitSystemPidfile:
+			pid, err := readPIDFile(s.PIDFile)
+			if err != nil {
+				r.Result = ProcessNotRunning
+				break
+			}
+			stopped := stopProcesses([]stopProcess{{name: s.Name, pid: pid}}, grace, false)[0]
+			r.Result, r.Err = stopped.Result, stopped.Err
+			if r.Err == nil {
+				_ = os.Remove(s.PIDFile)
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// serviceState returns the state of the service s run by the init system recorded in manifest.
+func serviceState(manifest *InstallManifest, s ManifestService) (string, error) {
+	switch manifest.InitSystem {
+	case InitSystemSystemd:
+		// is-active exits with an error for the units which aren't active, with their state as output.
+		out, _ := runCmd("systemctl", "--user", "is-active", s.unitName())
+		switch state := strings.TrimSpace(out); state {
+		case "active":
+			return ServiceRunning, nil
+		case "activating", "reloading":
+			return ServiceStarting, nil
+		case "failed":
+			return ServiceFailed, nil
+		default:
+			return ServiceStopped, nil
+		}
+	case InitSystemWindowsService:
+		return windowsServiceState(s)
+	default:
+		if pid, err := readPIDFile(s.PIDFile); err == nil && pidAlive(pid) {
+			return ServiceRunning, nil
+		}
+		return ServiceStopped, nil
+	}
+}
+
+// removeServices stops the services of slim mode and removes what the init system recorded in manifest needs to run
+// them, adding the errors to report.
+func removeServices(manifest *InstallManifest, report *UninstallReport) {
+	for _, r := range stopServices(manifest, DefaultStopGracePeriod) {
+		if r.Err != nil {
+			report.Errors = append(report.Errors, r.Err.Error())
+		}
+	}
+	for _, s := range manifest.Services {
+		print.InfoStatusEvent(os.Stdout, "Removing service: %s", s.Name)
+		if manifest.InitSystem == InitSystemWindowsService {
+			if err := removeWindowsService(s); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("could not remove the %s service: %s", s.Name, err))
+			}
+		}
+		if manifest.InitSystem == InitSystemSystemd {
+			if _, err := runCmd("systemctl", "--user", "disable", s.unitName()); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("could not disable the %s service: %s", s.Name, err))
+			}
+			if err := os.Remove(s.Unit); err != nil && !errors.Is(err, os.ErrNotExist) {
+				report.Errors = append(report.Errors, fmt.Sprintf("could not delete the unit of the %s service: %s", s.Name, err))
+			}
+		}
+		report.RemovedServices = append(report.RemovedServices, s.Name)
+	}
+	if manifest.InitSystem == InitSystemSystemd {
+		_, _ = runCmd("systemctl", "--user", "daemon-reload")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit(ManifestService{Name: "placement", Command: []string{"/home/me/my dapr/bin/placement", "--port", "50005"}})
+	assert.Contains(t, unit, "Description=Dapr placement service, installed by dapr init\n")
+	assert.Contains(t, unit, `ExecStart="/home/me/my dapr/bin/placement" --port 50005`+"\n")
+	assert.Contains(t, unit, "WantedBy=default.target\n")
+	unit = systemdUnit(ManifestService{Name: "placement", Command: []string{"/home/zoë/50%/placement"}})
+	assert.Contains(t, unit, "ExecStart=/home/zoë/50%%/placement\n")
+	assert.Equal(t, "dapr-placement.service", ManifestService{Name: "placement"}.unitName())
+}
+
+func TestSlimServices(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake redis-server is a shell script")
+	}
+	binDir := t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+	warn := func(format string, args ...any) {}
+	services := slimServices(binDir, true, warn)
+	require.Len(t, services, 1)
+	assert.Equal(t, []string{filepath.Join(binDir, placementServiceFilePrefix), "--port", "50005"}, services[0].Command)
+
+	pathDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pathDir, redisServerCommand), []byte("#!/bin/sh\n"), 0o755))
+	t.Setenv("PATH", pathDir)
+	services = slimServices(binDir, true, warn)
+	require.Len(t, services, 2)
+	assert.Equal(t, "redis", services[1].Name)
+}
+
+func TestSetupServicesNone(t *testing.T) {
+	services, initSystem, err := setupServices(t.TempDir(), []ManifestService{{Name: "placement"}}, InitSystemNone, nil)
+	require.NoError(t, err)
+	assert.Nil(t, services)
+	assert.Equal(t, InitSystemNone, initSystem)
+}
+
+func TestPidfileServices(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake service is a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "placement")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho started\nexec sleep 30\n"), 0o755))
+	services := []ManifestService{{Name: "placement", Command: []string{script}}}
+
+	require.NoError(t, startPidfileServices(filepath.Join(dir, servicesDirName), services))
+	pid, err := readPIDFile(services[0].PIDFile)
+	require.NoError(t, err)
+	assert.True(t, pidAlive(pid))
+	assert.Eventually(t, func() bool {
+		b, _ := os.ReadFile(services[0].LogFile)
+		return string(b) == "started\n"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	results := stopServices(&InstallManifest{InitSystem: InitSystemPidfile, Services: services}, 5*time.Second)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, ProcessStopped, results[0].Result)
+	assert.NoFileExists(t, services[0].PIDFile)
+
+	results = stopServices(&InstallManifest{InitSystem: InitSystemPidfile, Services: services}, time.Second)
+	assert.Equal(t, ProcessNotRunning, results[0].Result)
+}
+
+func TestWindowsServiceHostArgs(t *testing.T) {
+	s := ManifestService{Name: "placement", Command: []string{`C:\Users\me\.dapr\bin\placement.exe`, "--port", "6050"}}
+	assert.Equal(t, "dapr-placement", s.serviceName())
+	assert.Equal(t, []string{WindowsServiceHostCommand, "--name", "dapr-placement", "--", `C:\Users\me\.dapr\bin\placement.exe`, "--port", "6050"}, windowsServiceHostArgs(s))
+	assert.True(t, IsValidInitSystem(InitSystemWindowsService))
+	assert.False(t, IsValidInitSystem(InitSystemPidfile))
+}
+
+func TestServiceStatePidfile(t *testing.T) {
+	fakePIDs(t, 42)
+	dir := t.TempDir()
+	manifest := &InstallManifest{InitSystem: InitSystemPidfile}
+	s := ManifestService{Name: "placement", PIDFile: filepath.Join(dir, "placement.pid")}
+
+	state, err := serviceState(manifest, s)
+	require.NoError(t, err)
+	assert.Equal(t, ServiceStopped, state)
+	require.NoError(t, os.WriteFile(s.PIDFile, []byte("42\n"), 0o644))
+	state, err = serviceState(manifest, s)
+	require.NoError(t, err)
+	assert.Equal(t, ServiceRunning, state)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/dapr/cli/pkg/api"
+	"github.com/dapr/cli/pkg/metadata"
+)
+
+// MetadataAPIMinRuntimeVersion is the oldest runtime version whose metadata endpoint reports the loaded components.
+const MetadataAPIMinRuntimeVersion = "1.4.0"
+
+var (
+	// ErrSidecarNotRunning is returned by GetInstanceMetadata when the sidecar of the instance can't be reached.
+	ErrSidecarNotRunning = errors.New("the sidecar is not running")
+	// ErrMetadataNotSupported is returned by GetInstanceMetadata when the runtime of the instance doesn't have the
+	// metadata endpoint.
+	ErrMetadataNotSupported = errors.New("the runtime doesn't support the metadata endpoint")
+)
+
+// InstanceMetadata is the metadata of an instance: its endpoints and settings, and what its sidecar reports.
+type InstanceMetadata struct {
+	Endpoints  `yaml:",inline"`
+	Actors     []api.MetadataActiveActorsCount `json:"actors"     yaml:"actors"`
+	Components []api.MetadataComponent         `json:"components" yaml:"components"`
+	Extended   map[string]string               `json:"extended"   yaml:"extended"`
+}
+
+// GetInstanceMetadata queries the metadata endpoint of the sidecar of instance, waiting up to timeout. The error
+// wraps ErrSidecarNotRunning or ErrMetadataNotSupported when the sidecar can't tell.
+func GetInstanceMetadata(instance ListOutput, timeout time.Duration) (InstanceMetadata, error) {
+	m, err := metadata.GetWithTimeout(instance.HTTPPort, instance.AppID, instance.UnixDomainSocket, timeout)
+	switch {
+	case errors.Is(err, metadata.ErrNotSupported):
+		return InstanceMetadata{}, fmt.Errorf("%w, which needs runtime %s or later: %s", ErrMetadataNotSupported, MetadataAPIMinRuntimeVersion, err)
+	case err != nil && isConnectionError(err):
+		return InstanceMetadata{}, fmt.Errorf("%w: %s", ErrSidecarNotRunning, err)
+	case err != nil:
+		return InstanceMetadata{}, err
+	}
+	return InstanceMetadata{
+		Endpoints:  instance.Endpoints(),
+		Actors:     m.ActiveActorsCount,
+		Components: m.RegisteredComponents,
+		Extended:   m.Extended,
+	}, nil
+}
+
+// isConnectionError reports whether err is a failure to reach the sidecar, rather than an error in its response.
+func isConnectionError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/api"
+)
+
+func TestGetInstanceMetadata(t *testing.T) {
+	t.Run("loaded components", func(t *testing.T) {
+		instance := ListOutput{AppID: "app", HTTPPort: fakeSidecar(t, http.StatusNoContent, 0), GRPCPort: 50001}
+		m, err := GetInstanceMetadata(instance, time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, instance.Endpoints(), m.Endpoints)
+		assert.Equal(t, []api.MetadataComponent{
+			{Name: "statestore", Type: "state.redis", Version: "v1"},
+			{Name: "pubsub", Type: "pubsub.redis", Version: "v1"},
+		}, m.Components)
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		_, err := GetInstanceMetadata(ListOutput{AppID: "app", HTTPPort: freePort(t)}, time.Second)
+		assert.ErrorIs(t, err, ErrSidecarNotRunning)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		instance := ListOutput{AppID: "app", HTTPPort: server.Listener.Addr().(*net.TCPAddr).Port}
+		_, err := GetInstanceMetadata(instance, time.Second)
+		assert.ErrorIs(t, err, ErrMetadataNotSupported)
+		assert.ErrorContains(t, err, MetadataAPIMinRuntimeVersion)
+	})
+}
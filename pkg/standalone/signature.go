@@ -0,0 +1,390 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	// releaseChecksumsFileName is the manifest of the checksums of the archives of a release, in the format of
+	// sha256sum, signed with minisign in the file of the same name with signatureFileSuffix.
+	releaseChecksumsFileName = "checksums.txt"
+	signatureFileSuffix      = ".minisig"
+
+	// The statuses of the signature verification of a binary, in the install report.
+	SignatureVerified = "verified"
+	SignatureSkipped  = "skipped"
+	// SignatureUnverified is the status of the archives of a bundle which ships no signed checksums manifest.
+	SignatureUnverified = "unverified"
+
+	// The modes of the signature verification of a binary, in the install report: against the manifest downloaded
+	// with the release, or against the one of the bundle of an air-gapped install.
+	SignatureModeOnline  = "online"
+	SignatureModeOffline = "offline"
+
+	// maxSignatureFileSize bounds the size of the checksums manifest and its signature read in memory.
+	maxSignatureFileSize = 1 << 20
+)
+
+var (
+	// ErrSignatureMissing is returned when a release publishes no signed checksums manifest to verify an archive with.
+	ErrSignatureMissing = errors.New("no signature is published")
+	// ErrSignatureInvalid is returned when the signature of the checksums manifest of a release doesn't verify with
+	// the public key, or the archive isn't listed in the signed checksums or doesn't match its signed checksum.
+	ErrSignatureInvalid = errors.New("the signature is invalid")
+
+	errReleaseFileNotFound = errors.New("not found")
+)
+
+// SignatureOptions tell how init, upgrade and the upgrade of the CLI verify the signatures of the releases they
+// download.
+type SignatureOptions struct {
+	// PublicKeyFile is the minisign public key file to verify the signatures with, such as the one of a mirror. The
+	// Dapr releases aren't signed with a minisign key yet, so the CLI embeds none and the signatures are only
+	// verified when it is set.
+	PublicKeyFile string
+	// Skip installs the archives without verifying their signatures.
+	Skip bool
+	// ChecksumFile and SignatureFile are the checksums manifest and its signature the archives of the bundle of an
+	// air-gapped install are verified against. They default to the checksums.txt and checksums.txt.minisig files
+	// next to the archives, or at the root of the bundle, the archives being installed unverified if the bundle ships
+	// neither.
+	ChecksumFile  string
+	SignatureFile string
+}
+
+// bundleFiles returns the checksums manifest and signature files of the bundle in fromDir with its archives in
+// binarySubDir, defaulting those which aren't set to the first conventionally named files found in the bundle.
+func (o SignatureOptions) bundleFiles(fromDir, binarySubDir string) (string, string) {
+	checksums, signature := o.ChecksumFile, o.SignatureFile
+	if checksums == "" {
+		checksums = path_filepath.Join(fromDir, binarySubDir, releaseChecksumsFileName)
+		if _, err := os.Stat(checksums); err != nil {
+			root := path_filepath.Join(fromDir, releaseChecksumsFileName)
+			if _, err = os.Stat(root); err == nil {
+				checksums = root
+			}
+		}
+	}
+	if signature == "" {
+		signature = checksums + signatureFileSuffix
+	}
+	return checksums, signature
+}
+
+// SignatureReport is the result of the signature verification of a downloaded binary.
+type SignatureReport struct {
+	Binary  string `json:"binary"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Mode    string `json:"mode,omitempty"`
+	KeyID   string `json:"keyId,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// minisignKey is a minisign Ed25519 public key.
+type minisignKey struct {
+	id  [8]byte
+	key ed25519.PublicKey
+}
+
+// keyID returns the ID of the key, as minisign prints it.
+func (k *minisignKey) keyID() string {
+	id := make([]byte, len(k.id))
+	for i, b := range k.id {
+		id[len(id)-1-i] = b
+	}
+	return strings.ToUpper(hex.EncodeToString(id))
+}
+
+// parseMinisignPublicKey parses a minisign public key, either the base64 key alone or the contents of a public key
+// file, whose first line is an untrusted comment.
+func parseMinisignPublicKey(text string) (*minisignKey, error) {
+	var encoded string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "untrusted comment:") {
+			encoded = line
+			break
+		}
+	}
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(b) != 2+8+ed25519.PublicKeySize || string(b[:2]) != "Ed" {
+		return nil, errors.New("not a minisign public key")
+	}
+	k := &minisignKey{key: ed25519.PublicKey(b[10:])}
+	copy(k.id[:], b[2:10])
+	return k, nil
+}
+
+// verify checks signature, the contents of a minisign signature file, of message with the key, along with its
+// trusted comment.
+func (k *minisignKey) verify(message, signature []byte) error {
+	lines := strings.Split(strings.ReplaceAll(string(signature), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("%w: not a minisign signature", ErrSignatureInvalid)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: not a minisign signature", ErrSignatureInvalid)
+	}
+	if !bytes.Equal(sig[2:10], k.id[:]) {
+		keyID := &minisignKey{}
+		copy(keyID.id[:], sig[2:10])
+		return fmt.Errorf("%w: it was made with the key %s instead of %s", ErrSignatureInvalid, keyID.keyID(), k.keyID())
+	}
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		// The message is prehashed.
+		sum := blake2b.Sum512(message)
+		message = sum[:]
+	default:
+		return fmt.Errorf("%w: unsupported signature algorithm %q", ErrSignatureInvalid, sig[:2])
+	}
+	if !ed25519.Verify(k.key, message, sig[10:]) {
+		return fmt.Errorf("%w: it doesn't match the signed file", ErrSignatureInvalid)
+	}
+	// The global signature covers the trusted comment along with the signature.
+	comment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(k.key, append(sig[10:len(sig):len(sig)], comment...), globalSig) {
+		return fmt.Errorf("%w: its trusted comment doesn't verify", ErrSignatureInvalid)
+	}
+	return nil
+}
+
+// releaseVerifier verifies the downloaded archives against the signed checksums manifests of their releases, which it
+// downloads once per release, or the archives of a bundle against the signed checksums manifest of the bundle. It is
+// safe for concurrent use.
+type releaseVerifier struct {
+	key       *minisignKey
+	mu        sync.Mutex
+	manifests map[string]map[string]string
+	// checksumFile and signatureFile are the manifest and signature of the bundle of an air-gapped install.
+	checksumFile  string
+	signatureFile string
+	// bundleFilesSet is set if checksumFile or signatureFile are set with --checksum-file or --signature-file.
+	bundleFilesSet bool
+}
+
+// newReleaseVerifier returns the verifier for opts, or nil if the verification is skipped. The verifier has no key,
+// and verifies nothing, if opts sets no public key file.
+func newReleaseVerifier(opts SignatureOptions) (*releaseVerifier, error) {
+	if opts.Skip {
+		return nil, nil
+	}
+	if opts.PublicKeyFile == "" {
+		return &releaseVerifier{manifests: map[string]map[string]string{}}, nil
+	}
+	b, err := os.ReadFile(opts.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the public key file: %w", err)
+	}
+	key, err := parseMinisignPublicKey(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the public key %s: %w", opts.PublicKeyFile, err)
+	}
+	return &releaseVerifier{key: key, manifests: map[string]map[string]string{}}, nil
+}
+
+// checksum returns the checksum of the archive named archiveName in the signed checksums manifest of the release at
+// releaseURL, once the signature of the manifest is verified.
+func (v *releaseVerifier) checksum(ctx context.Context, releaseURL, archiveName string) (string, error) {
+	manifestURL := releaseURL + "/" + releaseChecksumsFileName
+	return v.manifestChecksum(ctx, manifestURL, manifestURL+signatureFileSuffix, archiveName, fetchReleaseFile)
+}
+
+// bundleChecksum returns the checksum of the archive named archiveName in the signed checksums manifest of the
+// bundle, once the signature of the manifest is verified. The files are read the same way the online ones are
+// downloaded, so that a missing or invalid file fails the same.
+func (v *releaseVerifier) bundleChecksum(ctx context.Context, archiveName string) (string, error) {
+	return v.manifestChecksum(ctx, v.checksumFile, v.signatureFile, archiveName, readBundleFile)
+}
+
+// bundleUnsigned reports whether the bundle ships no checksums manifest or signature, when they aren't set with
+// --checksum-file or --signature-file.
+func (v *releaseVerifier) bundleUnsigned() bool {
+	if v.bundleFilesSet {
+		return false
+	}
+	for _, path := range []string{v.checksumFile, v.signatureFile} {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestChecksum returns the checksum of archiveName in the manifest at manifestLocation, read with read along
+// with its signature at signatureLocation and cached once verified.
+func (v *releaseVerifier) manifestChecksum(ctx context.Context, manifestLocation, signatureLocation, archiveName string, read func(context.Context, string) ([]byte, error)) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	manifest, ok := v.manifests[manifestLocation]
+	if !ok {
+		contents, err := read(ctx, manifestLocation)
+		if err != nil {
+			return "", signatureFetchError(manifestLocation, err)
+		}
+		signature, err := read(ctx, signatureLocation)
+		if err != nil {
+			return "", signatureFetchError(signatureLocation, err)
+		}
+		if err = v.key.verify(contents, signature); err != nil {
+			return "", fmt.Errorf("%w: %s", err, signatureLocation)
+		}
+		manifest = parseChecksumsManifest(string(contents))
+		v.manifests[manifestLocation] = manifest
+	}
+	sum, ok := manifest[archiveName]
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not listed in the signed checksums of %s", ErrSignatureInvalid, archiveName, manifestLocation)
+	}
+	return sum, nil
+}
+
+// signatureFetchError returns the error of fetching the signature file, or checksums manifest, at url.
+func signatureFetchError(url string, err error) error {
+	if errors.Is(err, errReleaseFileNotFound) {
+		return fmt.Errorf("%w: %s is missing", ErrSignatureMissing, url)
+	}
+	return fmt.Errorf("error downloading %s: %w", url, err)
+}
+
+// parseChecksumsManifest parses a manifest in the format of sha256sum into the checksums by file name.
+func parseChecksumsManifest(contents string) map[string]string {
+	sums := map[string]string{}
+	for _, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks the files read in binary mode with a *.
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// fetchReleaseFile returns the contents of the small release file at url.
+func fetchReleaseFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", cli_ver.CLI.UserAgent())
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errReleaseFileNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSignatureFileSize))
+}
+
+// readBundleFile returns the contents of the small bundle file at path.
+func readBundleFile(_ context.Context, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errReleaseFileNotFound
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, maxSignatureFileSize))
+}
+
+// verifyReleaseSignature checks the downloaded archive of binaryFilePrefix against the signed checksums of the release
+// of version of githubRepo, or the archive of an air-gapped install against the signed checksums of its bundle,
+// unless the verification is skipped or there is no key to verify with, and records the result. A release which
+// publishes no signed checksums fails, and a bundle which ships none is installed unverified, with a warning.
+func verifyReleaseSignature(ctx context.Context, info initInfo, version, binaryFilePrefix, githubRepo string, archive downloadedFile) error {
+	result := SignatureReport{Binary: binaryFilePrefix, Version: version, Status: SignatureSkipped}
+	defer func() {
+		if info.recordSignature != nil && result.Status != "" {
+			info.recordSignature(result)
+		}
+	}()
+	if info.signatures == nil {
+		result.Reason = "--skip-signature-verification is set"
+		return nil
+	}
+	if info.signatures.key == nil {
+		result.Reason = "no --pubkey-file is set, the CLI embeds no release signing key"
+		return nil
+	}
+	result.Status = ""
+	var (
+		expected string
+		err      error
+	)
+	if isAirGapInit {
+		result.Mode = SignatureModeOffline
+		expected, err = info.signatures.bundleChecksum(ctx, path_filepath.Base(archive.path))
+	} else {
+		result.Mode = SignatureModeOnline
+		expected, err = info.signatures.checksum(ctx, releaseDownloadURL(version, githubRepo), path_filepath.Base(archive.path))
+	}
+	if err != nil {
+		if errors.Is(err, ErrSignatureMissing) && isAirGapInit && info.signatures.bundleUnsigned() {
+			result.Status = SignatureUnverified
+			result.Reason = err.Error()
+			info.warn("the %s archive of the bundle is installed unverified, %s. Use --checksum-file and --signature-file with the signed checksums of its archives to verify it", binaryFilePrefix, err)
+			return nil
+		}
+		if errors.Is(err, ErrSignatureMissing) && isAirGapInit {
+			return fmt.Errorf("cannot verify the %s archive of the bundle, %w. Use --checksum-file and --signature-file with the signed checksums of its archives, or --skip-signature-verification to install it unverified", binaryFilePrefix, err)
+		}
+		if errors.Is(err, ErrSignatureMissing) {
+			return fmt.Errorf("cannot verify the %s archive, %w. Use --pubkey-file with the key the releases are signed with, or --skip-signature-verification to install it unverified", binaryFilePrefix, err)
+		}
+		if errors.Is(err, ErrSignatureInvalid) {
+			return fmt.Errorf("cannot trust the %s archive, %w. The checksums of the release may have been tampered with, or signed with another key than the one of the release, set with --pubkey-file", binaryFilePrefix, err)
+		}
+		return fmt.Errorf("cannot verify the %s archive: %w", binaryFilePrefix, err)
+	}
+	if archive.sha256 != expected {
+		// The archives of a bundle are left alone, they aren't the CLI's to remove.
+		if !isAirGapInit {
+			os.Remove(archive.path)
+		}
+		return fmt.Errorf("cannot trust the %s archive, %w: its checksum is %s instead of the signed %s", binaryFilePrefix, ErrSignatureInvalid, archive.sha256, expected)
+	}
+	result.Status = SignatureVerified
+	result.KeyID = info.signatures.key.keyID()
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// testSigningKey returns a minisign key pair, the public key in the format of a public key file.
+func testSigningKey(t *testing.T, id string) (string, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	b := append([]byte("Ed"+id), pub...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(b) + "\n", priv
+}
+
+// minisign signs message like minisign, prehashing it if set.
+func minisign(priv ed25519.PrivateKey, id string, message []byte, prehashed bool) []byte {
+	alg := "Ed"
+	if prehashed {
+		alg = "ED"
+		sum := blake2b.Sum512(message)
+		message = sum[:]
+	}
+	sig := ed25519.Sign(priv, message)
+	comment := "timestamp:1686000000\tfile:checksums.txt"
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+	return []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append([]byte(alg+id), sig...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func TestParseMinisignPublicKey(t *testing.T) {
+	key, err := parseMinisignPublicKey("RWRGnQxBf+sVp/qFP/0yDVEKlMRBFo93+NWIC051htU3NobgAGjPgNqG")
+	require.NoError(t, err)
+	assert.Equal(t, "A715EB7F410C9D46", key.keyID())
+
+	pub, _ := testSigningKey(t, "12345678")
+	_, err = parseMinisignPublicKey(pub)
+	require.NoError(t, err)
+
+	_, err = parseMinisignPublicKey("untrusted comment: broken\nbm90IGEga2V5\n")
+	assert.Error(t, err)
+}
+
+func TestMinisignVerify(t *testing.T) {
+	pub, priv := testSigningKey(t, "12345678")
+	key, err := parseMinisignPublicKey(pub)
+	require.NoError(t, err)
+	message := []byte("abc  daprd_linux_amd64.tar.gz\n")
+
+	require.NoError(t, key.verify(message, minisign(priv, "12345678", message, false)))
+	require.NoError(t, key.verify(message, minisign(priv, "12345678", message, true)))
+	assert.ErrorIs(t, key.verify([]byte("tampered"), minisign(priv, "12345678", message, true)), ErrSignatureInvalid)
+	assert.ErrorIs(t, key.verify(message, []byte("not a signature")), ErrSignatureInvalid)
+
+	_, other := testSigningKey(t, "87654321")
+	err = key.verify(message, minisign(other, "87654321", message, false))
+	assert.ErrorIs(t, err, ErrSignatureInvalid)
+	assert.Contains(t, err.Error(), "made with the key")
+}
+
+func TestReleaseVerifierChecksum(t *testing.T) {
+	pub, priv := testSigningKey(t, "12345678")
+	keyFile := filepath.Join(t.TempDir(), "release.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte(pub), 0o644))
+	manifest := []byte("ABC123  daprd_linux_amd64.tar.gz\ndef456 *placement_linux_amd64.tar.gz\n")
+	signature := minisign(priv, "12345678", manifest, true)
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/v1.11.0/" + releaseChecksumsFileName, "/unsigned/" + releaseChecksumsFileName:
+			w.Write(manifest)
+		case "/v1.11.0/" + releaseChecksumsFileName + signatureFileSuffix:
+			w.Write(signature)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	v, err := newReleaseVerifier(SignatureOptions{PublicKeyFile: keyFile})
+	require.NoError(t, err)
+	sum, err := v.checksum(context.Background(), ts.URL+"/v1.11.0", "daprd_linux_amd64.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sum)
+	// The verified manifest is downloaded once per release.
+	sum, err = v.checksum(context.Background(), ts.URL+"/v1.11.0", "placement_linux_amd64.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", sum)
+	assert.Equal(t, 2, requests)
+
+	// An archive which isn't listed in the signed checksums can't be trusted.
+	_, err = v.checksum(context.Background(), ts.URL+"/v1.11.0", "dashboard_linux_amd64.tar.gz")
+	assert.ErrorIs(t, err, ErrSignatureInvalid)
+	_, err = v.checksum(context.Background(), ts.URL+"/unsigned", "daprd_linux_amd64.tar.gz")
+	assert.ErrorIs(t, err, ErrSignatureMissing)
+
+	// Another key didn't sign the manifest.
+	otherPub, _ := testSigningKey(t, "87654321")
+	otherKeyFile := filepath.Join(t.TempDir(), "other.pub")
+	require.NoError(t, os.WriteFile(otherKeyFile, []byte(otherPub), 0o644))
+	v, err = newReleaseVerifier(SignatureOptions{PublicKeyFile: otherKeyFile})
+	require.NoError(t, err)
+	_, err = v.checksum(context.Background(), ts.URL+"/v1.11.0", "daprd_linux_amd64.tar.gz")
+	assert.ErrorIs(t, err, ErrSignatureInvalid)
+	assert.NotErrorIs(t, err, ErrSignatureMissing)
+}
+
+func TestVerifyReleaseSignatureSkipped(t *testing.T) {
+	var results []SignatureReport
+	info := initInfo{recordSignature: func(result SignatureReport) { results = append(results, result) }}
+	require.NoError(t, verifyReleaseSignature(context.Background(), info, "1.11.0", daprRuntimeFilePrefix, "dapr", downloadedFile{path: filepath.Join(t.TempDir(), "daprd.tar.gz")}))
+	require.Len(t, results, 1)
+	assert.Equal(t, SignatureSkipped, results[0].Status)
+
+	// Without a public key file there is no key to verify with.
+	info.signatures, _ = newReleaseVerifier(SignatureOptions{})
+	require.NoError(t, verifyReleaseSignature(context.Background(), info, "1.11.0", daprRuntimeFilePrefix, "dapr", downloadedFile{path: filepath.Join(t.TempDir(), "daprd.tar.gz")}))
+	require.Len(t, results, 2)
+	assert.Equal(t, SignatureSkipped, results[1].Status)
+	assert.Contains(t, results[1].Reason, "--pubkey-file")
+
+	// A release which publishes no signed checksums fails.
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	downloadURL = ts.URL
+	t.Cleanup(func() { downloadURL = defaultDownloadURL })
+	pub, _ := testSigningKey(t, "12345678")
+	keyFile := filepath.Join(t.TempDir(), "release.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte(pub), 0o644))
+	info.signatures, _ = newReleaseVerifier(SignatureOptions{PublicKeyFile: keyFile})
+	err := verifyReleaseSignature(context.Background(), info, "1.11.0", daprRuntimeFilePrefix, "dapr", downloadedFile{path: filepath.Join(t.TempDir(), "daprd.tar.gz")})
+	assert.ErrorIs(t, err, ErrSignatureMissing)
+	assert.Contains(t, err.Error(), "--skip-signature-verification")
+
+	v, err := newReleaseVerifier(SignatureOptions{Skip: true})
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	_, err = newReleaseVerifier(SignatureOptions{PublicKeyFile: filepath.Join(t.TempDir(), "missing.pub")})
+	assert.Error(t, err)
+}
+
+func TestVerifyBundleSignature(t *testing.T) {
+	pub, priv := testSigningKey(t, "12345678")
+	bundle := t.TempDir()
+	binDir := filepath.Join(bundle, "dist")
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	keyFile := filepath.Join(bundle, "bundle.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte(pub), 0o644))
+	archive := filepath.Join(binDir, "daprd_linux_amd64.tar.gz")
+	require.NoError(t, os.WriteFile(archive, []byte("daprd"), 0o644))
+	sum, err := fileSHA256(archive)
+	require.NoError(t, err)
+	manifest := []byte(sum + "  daprd_linux_amd64.tar.gz\n")
+
+	// Without a manifest in the bundle, it defaults to the one next to the archives.
+	checksums, signature := SignatureOptions{}.bundleFiles(bundle, "dist")
+	assert.Equal(t, filepath.Join(binDir, releaseChecksumsFileName), checksums)
+	assert.Equal(t, checksums+signatureFileSuffix, signature)
+	require.NoError(t, os.WriteFile(filepath.Join(bundle, releaseChecksumsFileName), manifest, 0o644))
+	checksums, _ = SignatureOptions{}.bundleFiles(bundle, "dist")
+	assert.Equal(t, filepath.Join(bundle, releaseChecksumsFileName), checksums)
+	checksums, signature = SignatureOptions{ChecksumFile: "/sums.txt", SignatureFile: "/sums.sig"}.bundleFiles(bundle, "dist")
+	assert.Equal(t, []string{"/sums.txt", "/sums.sig"}, []string{checksums, signature})
+
+	setAirGapInit(bundle)
+	t.Cleanup(func() { setAirGapInit("") })
+	var (
+		results  []SignatureReport
+		warnings []string
+	)
+	verify := func(opts SignatureOptions) error {
+		v, err := newReleaseVerifier(opts)
+		require.NoError(t, err)
+		v.checksumFile, v.signatureFile = opts.bundleFiles(bundle, "dist")
+		v.bundleFilesSet = opts.ChecksumFile != "" || opts.SignatureFile != ""
+		info := initInfo{
+			signatures:      v,
+			recordSignature: func(result SignatureReport) { results = append(results, result) },
+			reportWarning:   func(message string) { warnings = append(warnings, message) },
+		}
+		bundled, err := hashFile(archive)
+		require.NoError(t, err)
+		return verifyReleaseSignature(context.Background(), info, "1.11.0", daprRuntimeFilePrefix, "dapr", bundled)
+	}
+
+	// The bundle ships no signature, its archives are installed unverified.
+	require.NoError(t, verify(SignatureOptions{PublicKeyFile: keyFile}))
+	require.Len(t, results, 1)
+	assert.Equal(t, SignatureUnverified, results[0].Status)
+	assert.Equal(t, SignatureModeOffline, results[0].Mode)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "--checksum-file")
+	// The files set with the flags must exist.
+	err = verify(SignatureOptions{PublicKeyFile: keyFile, SignatureFile: filepath.Join(bundle, "missing.minisig")})
+	assert.ErrorIs(t, err, ErrSignatureMissing)
+	assert.Contains(t, err.Error(), "--checksum-file")
+
+	results = nil
+	require.NoError(t, os.WriteFile(filepath.Join(bundle, releaseChecksumsFileName+signatureFileSuffix), minisign(priv, "12345678", manifest, true), 0o644))
+	require.NoError(t, verify(SignatureOptions{PublicKeyFile: keyFile}))
+	require.Len(t, results, 1)
+	assert.Equal(t, SignatureReport{Binary: daprRuntimeFilePrefix, Version: "1.11.0", Status: SignatureVerified, Mode: SignatureModeOffline, KeyID: "3837363534333231"}, results[0])
+
+	// Another key didn't sign the bundle.
+	otherPub, _ := testSigningKey(t, "87654321")
+	otherKeyFile := filepath.Join(bundle, "other.pub")
+	require.NoError(t, os.WriteFile(otherKeyFile, []byte(otherPub), 0o644))
+	assert.ErrorIs(t, verify(SignatureOptions{PublicKeyFile: otherKeyFile}), ErrSignatureInvalid)
+
+	require.NoError(t, os.WriteFile(archive, []byte("tampered"), 0o644))
+	assert.ErrorIs(t, verify(SignatureOptions{PublicKeyFile: keyFile}), ErrSignatureInvalid)
+	assert.FileExists(t, archive)
+}
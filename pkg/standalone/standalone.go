@@ -16,22 +16,25 @@ package standalone
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
-	"path"
 	path_filepath "path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/fatih/color"
+	go_version "github.com/hashicorp/go-version"
 	"gopkg.in/yaml.v2"
 
 	"github.com/dapr/cli/pkg/print"
@@ -46,8 +49,10 @@ const (
 
 	daprWindowsOS = "windows"
 
-	latestVersion   = "latest"
-	daprDefaultHost = "localhost"
+	latestVersion = "latest"
+	// defaultDownloadURL is the base URL of the GitHub releases.
+	defaultDownloadURL = "https://github.com"
+	daprDefaultHost    = "localhost"
 
 	pubSubYamlFileName     = "pubsub.yaml"
 	stateStoreYamlFileName = "statestore.yaml"
@@ -85,6 +90,9 @@ var (
 	isAirGapInit             bool
 )
 
+// errVersionNotFound is returned when the archive to download doesn't exist.
+var errVersionNotFound = errors.New("version not found from url")
+
 type configuration struct {
 	APIVersion string `yaml:"apiVersion"`
 	Kind       string `yaml:"kind"`
@@ -112,17 +120,35 @@ type component struct {
 		Version  string                  `yaml:"version"`
 		Metadata []componentMetadataItem `yaml:"metadata"`
 	} `yaml:"spec"`
+	Auth *componentAuth `yaml:"auth,omitempty"`
 }
 
 type componentMetadataItem struct {
-	Name  string `yaml:"name"`
-	Value string `yaml:"value"`
+	Name         string                 `yaml:"name"`
+	Value        string                 `yaml:"value"`
+	SecretKeyRef *componentSecretKeyRef `yaml:"secretKeyRef,omitempty"`
+}
+
+// MarshalYAML writes items referencing a secret without a value.
+func (i componentMetadataItem) MarshalYAML() (interface{}, error) {
+	if i.SecretKeyRef == nil {
+		type plain componentMetadataItem
+		return plain(i), nil
+	}
+	return struct {
+		Name         string                 `yaml:"name"`
+		SecretKeyRef *componentSecretKeyRef `yaml:"secretKeyRef"`
+	}{i.Name, i.SecretKeyRef}, nil
 }
 
 type initInfo struct {
-	fromDir          string
-	installDir       string
-	bundleDet        *bundleDetails
+	fromDir       string
+	installDir    string
+	componentsDir string
+	bundleDet     *bundleDetails
+	// force replaces the binaries, recreates the containers with images pulled again, and regenerates the files init
+	// writes, even if they have been edited.
+	force            bool
 	slimMode         bool
 	runtimeVersion   string
 	dashboardVersion string
@@ -130,6 +156,44 @@ type initInfo struct {
 	imageRegistryURL string
 	containerRuntime string
 	imageVariant     string
+	retries          int
+	// unhardened runs the Redis container without the hardening of redisContainerArgs.
+	unhardened bool
+	// components is the provider of the state store and pubsub components, one of the ComponentsProvider constants.
+	components string
+	// reportProgress reports the progress of the step running with this info, it is set by runInitSteps.
+	reportProgress func(message string)
+	// reportWarning reports a non-fatal issue of the step running with this info, it is set by runInitSteps.
+	reportWarning func(message string)
+	// recordPlacementImage records the image the placement container was started with, for the install manifest.
+	recordPlacementImage func(image string)
+	// lock is the lockfile init reproduces, the images are pinned to its digests and the archives checked against
+	// its checksums.
+	lock *Lockfile
+	// recordBinary records a downloaded binary, for the lockfile.
+	recordBinary func(binary LockedBinary)
+	// signatures verifies the downloaded archives against the signed checksums of their releases, it is nil if the
+	// verification is skipped.
+	signatures *releaseVerifier
+	// recordSignature records the result of the signature verification of a downloaded binary, for the install report.
+	recordSignature func(result SignatureReport)
+	// recordTiming records how long a part of a step took, for the install report. runInitSteps sets the step of the
+	// timings recorded with the info of each step.
+	recordTiming func(timing InitTiming)
+	// recordNote records a note of a step for the install report, which unlike a warning doesn't fail init with
+	// --strict. runInitSteps sets the step of the notes recorded with the info of each step.
+	recordNote func(note InitWarning)
+	// recordKept records a file which init kept as it was, for the install report.
+	recordKept func(path string)
+	// ports are the host ports the containers are published on outside of a docker network.
+	ports InstallPorts
+	// hooks run around the steps, see runHooks.
+	hooks []Hook
+	// pulls runs the image pulls and the downloads of the steps, a number of them at once.
+	pulls *workPool
+	// artifacts records what the steps create, for the rollback of an interrupted init. It is nil if they aren't
+	// recorded.
+	artifacts *initArtifacts
 }
 
 type daprImageInfo struct {
@@ -152,36 +216,130 @@ func isBinaryInstallationRequired(binaryFilePrefix, binInstallDir string) (bool,
 }
 
 // Init installs Dapr on a local machine using the supplied runtimeVersion.
+// The settings added since, such as the retries or a lockfile, are only set with NewInstaller, as the CLI does.
 func Init(runtimeVersion, dashboardVersion string, dockerNetwork string, slimMode bool, imageRegistryURL string, fromDir string, containerRuntime string, imageVariant string, daprInstallPath string) error {
+	_, err := NewInstaller(
+		WithRuntimeVersion(runtimeVersion),
+		WithDashboardVersion(dashboardVersion),
+		WithDockerNetwork(dockerNetwork),
+		WithSlimMode(slimMode),
+		WithImageRegistry(imageRegistryURL),
+		WithFromDir(fromDir),
+		WithContainerRuntime(containerRuntime),
+		WithImageVariant(imageVariant),
+		WithRuntimePath(daprInstallPath),
+	).Install(context.Background())
+	return err
+}
+
+// install is Init with the options of the installer.
+func (i *Installer) install(ctx context.Context) (*InitReport, error) {
+	o := i.opts
+	runtimeVersion, dashboardVersion, dockerNetwork, slimMode := o.runtimeVersion, o.dashboardVersion, o.dockerNetwork, o.slimMode
+	imageRegistryURL, fromDir, containerRuntime, imageVariant := o.imageRegistryURL, o.fromDir, o.containerRuntime, o.imageVariant
+	daprInstallPath, retries, diagnosticsBundle, waitForLock, strict := o.runtimePath, o.retries, o.diagnosticsBundle, o.waitForLock, o.strict
+	force, initSystem, lock, signature, unhardened := o.force, o.initSystem, o.lock, o.signature, o.unhardened
+	componentsProvider := o.components
+	if componentsProvider == "" {
+		componentsProvider = ComponentsProviderRedis
+	}
 	var err error
+	report := &InitReport{SlimMode: slimMode, ComponentsProvider: componentsProvider}
+	started := time.Now()
+	defer func() { report.DurationMs = time.Since(started).Milliseconds() }()
 	var bundleDet bundleDetails
 	containerRuntime = strings.TrimSpace(containerRuntime)
 	daprInstallPath = strings.TrimSpace(daprInstallPath)
 	// AirGap init flow is true when fromDir var is set i.e. --from-dir flag has value.
 	fromDir = strings.TrimSpace(fromDir)
 	setAirGapInit(fromDir)
+	signatures, err := newReleaseVerifier(signature)
+	if err != nil {
+		return report, err
+	}
+	if !IsValidComponentsProvider(componentsProvider) {
+		return report, fmt.Errorf("invalid components provider %q, supported values are %s, %s and %s", componentsProvider, ComponentsProviderRedis, ComponentsProviderInMemory, ComponentsProviderNone)
+	}
 	if !slimMode {
 		// If --slim installation is not requested, check if docker is installed.
+		if IsWSL() && utils.GetContainerRuntimeCmd(containerRuntime) == string(utils.DOCKER) {
+			if err = checkWSLDocker(); err != nil {
+				return report, err
+			}
+		}
 		containerRuntimeAvailable := utils.IsContainerRuntimeInstalled(containerRuntime)
 		if !containerRuntimeAvailable {
-			return fmt.Errorf("could not connect to %s. %s may not be installed or running", containerRuntime, containerRuntime)
+			return report, fmt.Errorf("could not connect to %s. %s may not be installed or running", containerRuntime, containerRuntime)
 		}
 
 		// Initialize default registry only if any of --slim or --image-registry or --from-dir are not given.
 		if len(strings.TrimSpace(imageRegistryURL)) == 0 && !isAirGapInit {
 			defaultImageRegistryName, err = utils.GetDefaultRegistry(githubContainerRegistryName, dockerContainerRegistryName)
 			if err != nil {
-				return err
+				return report, err
 			}
 		}
 	}
 
+	ports := o.ports
+	if ports.Redis == 0 {
+		redisPort, resolveErr := ResolveSetting(SettingRedisPort, nil, daprInstallPath)
+		if resolveErr != nil {
+			return report, resolveErr
+		}
+		// The setting is validated as an integer.
+		ports.Redis, _ = strconv.Atoi(redisPort.Value)
+	}
+	ports = ports.withDefaults()
+
+	// Re-running init for a pinned version which is already installed does nothing, without hitting the network.
+	if !force && !isAirGapInit && runtimeVersion != latestVersion {
+		installDir, dirErr := GetDaprRuntimePath(daprInstallPath)
+		if dirErr != nil {
+			return report, dirErr
+		}
+		reason := installUpToDate(installDir, upToDateRequest{
+			runtimeVersion:   runtimeVersion,
+			dashboardVersion: dashboardVersion,
+			slimMode:         slimMode,
+			dockerNetwork:    dockerNetwork,
+			runtimeCmd:       utils.GetContainerRuntimeCmd(containerRuntime),
+			lock:             lock,
+			components:       componentsProvider,
+			imageRegistry:    strings.TrimSpace(imageRegistryURL),
+			imageVariant:     imageVariant,
+			unhardened:       unhardened,
+			ports:            ports,
+			initSystem:       initSystem,
+		})
+		if reason == "" {
+			manifest, _ := ReadInstallManifest(installDir)
+			report.UpToDate = true
+			report.RuntimeVersion = manifest.RuntimeVersion
+			report.InstallDir = installDir
+			report.BinDir = getDaprBinPath(installDir)
+			report.ConfigFile = manifest.ConfigPath
+			report.Lockfile = GetLockfilePath(installDir)
+			if lockfile, lockErr := ReadLockfile(report.Lockfile); lockErr == nil {
+				report.DashboardVersion = lockfile.DashboardVersion
+			}
+			for _, c := range manifest.Containers {
+				report.Containers = append(report.Containers, c.Name)
+			}
+			for _, s := range manifest.Services {
+				report.Services = append(report.Services, s.Name)
+			}
+			return report, nil
+		}
+		print.DebugStatusEvent(os.Stdout, "installing, as the install isn't up to date: %s", reason)
+	}
+
 	// Set runtime version.
 
 	if runtimeVersion == latestVersion && !isAirGapInit {
 		runtimeVersion, err = cli_ver.GetDaprVersion()
 		if err != nil {
-			return fmt.Errorf("cannot get the latest release version: '%w'. Try specifying --runtime-version=<desired_version>", err)
+			return report, fmt.Errorf("cannot get the latest release version: '%w'. Try specifying --runtime-version=<desired_version>", err)
 		}
 	}
 
@@ -199,13 +357,17 @@ func Init(runtimeVersion, dashboardVersion string, dockerNetwork string, slimMod
 		detailsFilePath := path_filepath.Join(fromDir, bundleDetailsFileName)
 		err = bundleDet.readAndParseDetails(detailsFilePath)
 		if err != nil {
-			return fmt.Errorf("error parsing details file from bundle location: %w", err)
+			return report, fmt.Errorf("error parsing details file from bundle location: %w", err)
 		}
 
 		// Set runtime and dashboard versions from the bundle details parsed.
 
 		runtimeVersion = *bundleDet.RuntimeVersion
 		dashboardVersion = *bundleDet.DashboardVersion
+		if signatures != nil {
+			signatures.checksumFile, signatures.signatureFile = signature.bundleFiles(fromDir, *bundleDet.BinarySubDir)
+			signatures.bundleFilesSet = signature.ChecksumFile != "" || signature.SignatureFile != ""
+		}
 	}
 
 	// At this point the runtimeVersion variable is parsed either from the details file if --fromDir is specified or
@@ -214,49 +376,62 @@ func Init(runtimeVersion, dashboardVersion string, dockerNetwork string, slimMod
 	// After this point runtimeVersion will not be latest string but rather actual version.
 
 	print.InfoStatusEvent(os.Stdout, "Installing runtime version %s", runtimeVersion)
+	report.RuntimeVersion = runtimeVersion
+	report.DashboardVersion = dashboardVersion
 
 	installDir, err := GetDaprRuntimePath(daprInstallPath)
 	if err != nil {
-		return err
+		return report, err
 	}
-	daprBinDir := getDaprBinPath(installDir)
-	err = prepareDaprInstallDir(daprBinDir)
+	unlock, err := acquireInstallLock(ctx, installDir, waitForLock)
 	if err != nil {
-		return err
+		return report, err
 	}
+	defer unlock()
 
-	// confirm if installation is required.
-	if ok, er := isBinaryInstallationRequired(daprRuntimeFilePrefix, daprBinDir); !ok {
-		return er
+	daprBinDir := getDaprBinPath(installDir)
+	report.InstallDir = installDir
+	report.BinDir = daprBinDir
+	artifacts := &initArtifacts{}
+	if _, statErr := fsys.Stat(daprBinDir); errors.Is(statErr, os.ErrNotExist) {
+		artifacts.record(ArtifactDirectory, daprBinDir)
 	}
-
-	var wg sync.WaitGroup
-	errorChan := make(chan error)
-	initSteps := []func(*sync.WaitGroup, chan<- error, initInfo){
-		createSlimConfiguration,
-		createComponentsAndConfiguration,
-		installDaprRuntime,
-		installPlacement,
-		installDashboard,
-		runPlacementService,
-		runRedis,
-		runZipkin,
+	err = prepareDaprInstallDir(daprBinDir)
+	if err != nil {
+		return report, err
 	}
 
-	// Init other configurations, containers.
-	wg.Add(len(initSteps))
-
-	msg := "Downloading binaries and setting up components..."
-	if isAirGapInit {
-		msg = "Extracting binaries and setting up components..."
+	// confirm if installation is required, with force the binaries are replaced.
+	if ok, er := isBinaryInstallationRequired(daprRuntimeFilePrefix, daprBinDir); !ok && !force {
+		return report, er
 	}
-	stopSpinning := print.Spinner(os.Stdout, msg)
-	defer stopSpinning(print.Failure)
 
 	// Make default components directory.
-	err = makeDefaultComponentsDir(installDir)
+	componentsDir, err := ResolveComponentsPath("", daprInstallPath)
 	if err != nil {
-		return err
+		return report, err
+	}
+	print.DebugStatusEvent(os.Stdout, "using components directory %s", componentsDir)
+	if _, statErr := fsys.Stat(componentsDir.Path); errors.Is(statErr, os.ErrNotExist) {
+		artifacts.record(ArtifactDirectory, componentsDir.Path)
+	}
+	err = makeDefaultComponentsDir(componentsDir.Path)
+	if err != nil {
+		return report, err
+	}
+	if err = useDownloadURL(daprInstallPath); err != nil {
+		return report, err
+	}
+	pullConcurrency := o.pullConcurrency
+	if pullConcurrency == 0 {
+		setting, resolveErr := ResolveSetting(SettingPullConcurrency, nil, daprInstallPath)
+		if resolveErr != nil {
+			return report, resolveErr
+		}
+		pullConcurrency, _ = strconv.Atoi(setting.Value)
+	}
+	if pullConcurrency < 1 {
+		return report, fmt.Errorf("invalid pull concurrency %d, it must be at least 1", pullConcurrency)
 	}
 
 	info := initInfo{
@@ -264,6 +439,7 @@ func Init(runtimeVersion, dashboardVersion string, dockerNetwork string, slimMod
 		bundleDet:        &bundleDet,
 		fromDir:          fromDir,
 		installDir:       installDir,
+		componentsDir:    componentsDir.Path,
 		slimMode:         slimMode,
 		runtimeVersion:   runtimeVersion,
 		dashboardVersion: dashboardVersion,
@@ -271,88 +447,304 @@ func Init(runtimeVersion, dashboardVersion string, dockerNetwork string, slimMod
 		imageRegistryURL: imageRegistryURL,
 		containerRuntime: containerRuntime,
 		imageVariant:     imageVariant,
+		retries:          retries,
+		force:            force,
+		lock:             lock,
+		signatures:       signatures,
+		unhardened:       unhardened,
+		components:       componentsProvider,
+		ports:            ports,
+		pulls:            newWorkPool(pullConcurrency),
+		artifacts:        artifacts,
+	}
+	for _, h := range o.hooks {
+		if err = validateHook(h.Name, h.Step, h.Phase); err != nil {
+			return report, err
+		}
+		if h.Run == nil {
+			return report, fmt.Errorf("hook %s has no Run function", h.Name)
+		}
 	}
-	for _, step := range initSteps {
-		// Run init on the configurations and containers.
-		go step(&wg, errorChan, info)
+	configHooks, err := shellHooks(installDir)
+	if err != nil {
+		return report, err
+	}
+	info.hooks = append(append(info.hooks, o.hooks...), configHooks...)
+	// Fail before anything is downloaded, rather than with partial binaries or images left behind.
+	if err = checkInitDiskSpace(ctx, info); err != nil {
+		return report, err
+	}
+
+	var placementImage string
+	info.recordPlacementImage = func(image string) {
+		placementImage = image
+	}
+	lockfile := &Lockfile{RuntimeVersion: runtimeVersion, DashboardVersion: dashboardVersion, SlimMode: slimMode}
+	var lockfileMu sync.Mutex
+	info.recordBinary = func(binary LockedBinary) {
+		lockfileMu.Lock()
+		defer lockfileMu.Unlock()
+		lockfile.Binaries = append(lockfile.Binaries, binary)
+	}
+	var signaturesMu sync.Mutex
+	info.recordSignature = func(result SignatureReport) {
+		signaturesMu.Lock()
+		defer signaturesMu.Unlock()
+		report.Signatures = append(report.Signatures, result)
+	}
+	var timingsMu sync.Mutex
+	info.recordTiming = func(timing InitTiming) {
+		print.LogToFile(print.LogInfo, "timing: %s", timing)
+		timingsMu.Lock()
+		defer timingsMu.Unlock()
+		report.Timings = append(report.Timings, timing)
+	}
+	var notesMu sync.Mutex
+	info.recordNote = func(note InitWarning) {
+		notesMu.Lock()
+		defer notesMu.Unlock()
+		report.Notes = append(report.Notes, note)
+	}
+	info.recordKept = func(path string) {
+		notesMu.Lock()
+		defer notesMu.Unlock()
+		report.KeptFiles = append(report.KeptFiles, path)
 	}
 
-	go func() {
-		wg.Wait()
-		close(errorChan)
-	}()
+	msg := "Downloading binaries and setting up components..."
+	if isAirGapInit {
+		msg = "Extracting binaries and setting up components..."
+	}
+	print.InfoStatusEvent(os.Stdout, msg)
 
-	for err := range errorChan {
-		if err != nil {
-			return err
+	// Init other configurations, containers.
+	stepLog := &initStepLog{}
+	// The events go to the progress sink of the installer instead, if it has one.
+	var progress *print.ProgressRenderer
+	if o.progress == nil {
+		progress = print.NewProgressRenderer(os.Stdout)
+	}
+	var reportLock sync.Mutex
+	stepsStarted := map[string]time.Time{}
+	err = runInitSteps(ctx, newInitSteps(info), info, func(ev print.ProgressEvent) {
+		if ev.Time.IsZero() {
+			ev.Time = time.Now()
+		}
+		stepLog.record("%s: %s %s", ev.Step, ev.Type, ev.Message)
+		if progress != nil {
+			progress.Send(ev)
+		} else {
+			o.progress(ev)
 		}
-	}
 
-	stopSpinning(print.Success)
+		reportLock.Lock()
+		defer reportLock.Unlock()
+		report.addStepEvent(ev, stepsStarted)
+	})
+	if progress != nil {
+		progress.Stop()
+	}
+	if err != nil && errors.Is(context.Cause(ctx), ErrInitInterrupted) {
+		reportLock.Lock()
+		running := runningInitSteps(report.Steps, stepsStarted)
+		reportLock.Unlock()
+		rollback := rollbackInterruptedInit(info, running)
+		report.Interrupted = &rollback
+		return report, ErrInitInterrupted
+	}
+	if err != nil {
+		if diagnosticsBundle {
+			bundlePath, bundleErr := writeInitDiagnosticsBundle(info, stepLog, err)
+			if bundleErr != nil {
+				print.WarningStatusEvent(os.Stderr, "could not write diagnostics bundle: %s", bundleErr)
+			} else {
+				err = fmt.Errorf("%w\ndiagnostics bundle written to %s, please attach it when reporting an issue", err, bundlePath)
+			}
+		}
+		return report, err
+	}
 
 	msg = "Downloaded binaries and completed components set up."
 	if isAirGapInit {
 		msg = "Extracted binaries and completed components set up."
 	}
 	print.SuccessStatusEvent(os.Stdout, msg)
-	print.InfoStatusEvent(os.Stdout, "%s binary has been installed to %s.", daprRuntimeFilePrefix, daprBinDir)
+	var services []ManifestService
+	if slimMode {
+		warn := func(format string, args ...any) {
+			report.Warnings = append(report.Warnings, InitWarning{Step: "services", Message: fmt.Sprintf(format, args...)})
+		}
+		services, initSystem, err = setupServices(installDir, slimServices(daprBinDir, componentsProvider == ComponentsProviderRedis, warn), initSystem, warn)
+		if err != nil {
+			return report, err
+		}
+	}
+	initEvent := InstallEvent{Action: InstallActionInit, RuntimeVersion: runtimeVersion, At: time.Now().UTC()}
+	for _, b := range lockfile.Binaries {
+		if b.Name == daprRuntimeFilePrefix || b.Name == placementServiceFilePrefix {
+			if initEvent.Checksums == nil {
+				initEvent.Checksums = map[string]string{}
+			}
+			initEvent.Checksums[b.Name] = b.SHA256
+		}
+	}
+	report.ConfigFile = GetDaprConfigPath(installDir)
+	summary := print.NewTable(
+		print.TableColumn{Name: "Name", Key: "name"},
+		print.TableColumn{Name: "Type", Key: "type"},
+		print.TableColumn{Name: "Status", Key: "status"},
+		print.TableColumn{Name: "Location", Key: "location", Truncate: true},
+	)
+	summary.AddRow(daprRuntimeFilePrefix, "binary", "installed", daprBinDir)
+	configStatus := "written"
+	if utils.Contains(report.KeptFiles, report.ConfigFile) {
+		configStatus = "kept"
+	}
+	summary.AddRow(DefaultConfigFileName, "configuration", configStatus, report.ConfigFile)
+	runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
 	if slimMode {
 		// Print info on placement binary only on slim install.
-		print.InfoStatusEvent(os.Stdout, "%s binary has been installed to %s.", placementServiceFilePrefix, daprBinDir)
+		summary.AddRow(placementServiceFilePrefix, "binary", "installed", daprBinDir)
+		for _, s := range services {
+			location := s.Unit
+			if location == "" {
+				location = s.PIDFile
+			}
+			summary.AddRow(s.Name, "service", "running", location)
+			report.Services = append(report.Services, s.Name)
+		}
 	} else {
-		runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
 		dockerContainerNames := []string{DaprPlacementContainerName, DaprRedisContainerName, DaprZipkinContainerName}
+		if componentsProvider != ComponentsProviderRedis {
+			dockerContainerNames = []string{DaprPlacementContainerName, DaprZipkinContainerName}
+		}
 		// Skip redis and zipkin in local installation mode.
 		if isAirGapInit {
 			dockerContainerNames = []string{DaprPlacementContainerName}
 		}
 		for _, container := range dockerContainerNames {
-			containerName := utils.CreateContainerName(container, dockerNetwork)
+			containerName := profileContainerName(container, dockerNetwork)
 			ok, err := confirmContainerIsRunningOrExists(containerName, true, runtimeCmd)
 			if err != nil {
-				return err
+				return report, err
 			}
 			if ok {
-				print.InfoStatusEvent(os.Stdout, "%s container is running.", containerName)
+				summary.AddRow(containerName, "container", "running", runtimeCmd)
+				report.Containers = append(report.Containers, containerName)
+				locked, lockErr := inspectLockedContainer(container, containerName, runtimeCmd)
+				if lockErr != nil || locked.Digest == "" {
+					report.Warnings = append(report.Warnings, InitWarning{Step: "lockfile", Message: fmt.Sprintf("no image digest of %s to pin in the lockfile", containerName)})
+				}
+				lockfile.Containers = append(lockfile.Containers, locked)
 			}
 		}
+	}
+	report.Lockfile = GetLockfilePath(installDir)
+	if err = writeLockfile(report.Lockfile, lockfile); err != nil {
+		return report, err
+	}
+	runtimeBinary := binaryFilePathWithDir(daprBinDir, daprRuntimeFilePrefix)
+	// Without a checksum the next init can't tell that the install is up to date, which isn't worth failing for.
+	runtimeBinarySHA256, _ := fileSHA256(runtimeBinary)
+	manifest := &InstallManifest{
+		RuntimeVersion:      runtimeVersion,
+		RuntimeBinary:       runtimeBinary,
+		RuntimeBinarySHA256: runtimeBinarySHA256,
+		Platform:            hostPlatform(),
+		SlimMode:            slimMode,
+		ComponentsProvider:  componentsProvider,
+		PlacementImage:      placementImage,
+		DockerNetwork:       dockerNetwork,
+		ContainerRuntime:    runtimeCmd,
+		DockerEndpoint:      os.Getenv("DOCKER_HOST"),
+		ComponentsPath:      componentsDir.Path,
+		ConfigPath:          report.ConfigFile,
+		InitSystem:          initSystem,
+		Services:            services,
+		InstalledAt:         initEvent.At,
+		History:             []InstallEvent{initEvent},
+		ImageRegistry:       strings.TrimSpace(imageRegistryURL),
+		ImageVariant:        imageVariant,
+		UnhardenedRedis:     unhardened,
+		Ports:               &ports,
+	}
+	for _, c := range lockfile.Containers {
+		manifest.Containers = append(manifest.Containers, ManifestContainer{
+			Name:      profileContainerName(c.Name, dockerNetwork),
+			Image:     c.Image,
+			HostPorts: c.HostPorts,
+			Digest:    c.Digest,
+			ImageID:   c.imageID,
+		})
+	}
+	// Reinitializing an install keeps its history.
+	if previous, _ := ReadInstallManifest(installDir); previous != nil {
+		manifest.History = append(previous.History, initEvent)
+	}
+	if err = writeInstallManifest(installDir, manifest); err != nil {
+		return report, err
+	}
+	summary.AddRow(DefaultLockfileName, "lockfile", "written", report.Lockfile)
+	summary.RenderStatus(os.Stdout)
+	if !slimMode {
 		print.InfoStatusEvent(os.Stdout, "Use `%s ps` to check running containers.", runtimeCmd)
 	}
-	return nil
+	if len(report.Notes) > 0 {
+		print.InfoStatusEvent(os.Stdout, "Notes:")
+		for _, n := range report.Notes {
+			print.InfoStatusEvent(os.Stdout, "  %s: %s", n.Step, n.Message)
+		}
+	}
+	if len(report.Warnings) > 0 {
+		print.WarningStatusEvent(os.Stdout, "Warnings:")
+		for _, w := range report.Warnings {
+			print.WarningStatusEvent(os.Stdout, "  %s: %s", w.Step, w.Message)
+		}
+		if strict {
+			return report, fmt.Errorf("init completed with %d warning(s) and --strict is set", len(report.Warnings))
+		}
+	}
+	return report, nil
 }
 
-func runZipkin(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func runZipkin(ctx context.Context, info initInfo) error {
 	if info.slimMode || isAirGapInit {
-		return
+		return nil
 	}
 
-	zipkinContainerName := utils.CreateContainerName(DaprZipkinContainerName, info.dockerNetwork)
+	zipkinContainerName := profileContainerName(DaprZipkinContainerName, info.dockerNetwork)
 
 	runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
-	exists, err := confirmContainerIsRunningOrExists(zipkinContainerName, false, runtimeCmd)
+	imageName, err := resolveImageURI(daprImageInfo{
+		ghcrImageName:      zipkinGhcrImageName,
+		dockerHubImageName: zipkinDockerImageName,
+		imageRegistryURL:   info.imageRegistryURL,
+		imageRegistryName:  defaultImageRegistryName,
+	})
+	if err != nil {
+		return err
+	}
+	expected, err := lockedImage(info, DaprZipkinContainerName, imageName)
 	if err != nil {
-		errorChan <- err
-		return
+		return err
+	}
+	action, _, err := existingContainerAction(ctx, zipkinContainerName, []string{expected}, runtimeCmd, info.force)
+	if err != nil {
+		return err
 	}
 	args := []string{}
 
-	var imageName string
-
-	if exists {
-		// do not create container again if it exists.
+	switch action {
+	case containerSkip:
+		info.progress("%s is already running", zipkinContainerName)
+		return nil
+	case containerStart:
+		info.progress("starting the stopped %s container", zipkinContainerName)
 		args = append(args, "start", zipkinContainerName)
-	} else {
-		imageName, err = resolveImageURI(daprImageInfo{
-			ghcrImageName:      zipkinGhcrImageName,
-			dockerHubImageName: zipkinDockerImageName,
-			imageRegistryURL:   info.imageRegistryURL,
-			imageRegistryName:  defaultImageRegistryName,
-		})
-		if err != nil {
-			errorChan <- err
-			return
+	default:
+		imageName = expected
+		if err = pullImage(ctx, info, imageName); err != nil {
+			return err
 		}
 
 		args = append(args,
@@ -361,6 +753,8 @@ func runZipkin(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
 			"--restart", "always",
 			"-d",
 		)
+		args = append(args, platformArgs()...)
+		args = append(args, profileLabelArgs()...)
 
 		if info.dockerNetwork != "" {
 			args = append(
@@ -370,111 +764,96 @@ func runZipkin(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
 		} else {
 			args = append(
 				args,
-				"-p", "9411:9411")
+				"-p", fmt.Sprintf("%d:%d", info.ports.Zipkin, zipkinContainerPort))
 		}
 
 		args = append(args, imageName)
 	}
-	_, err = utils.RunCmdAndWait(runtimeCmd, args...)
+	start := time.Now()
+	_, err = runContainerCmd(ctx, runtimeCmd, args...)
 
 	if err != nil {
 		runError := isContainerRunError(err)
 		if !runError {
-			errorChan <- parseContainerRuntimeError("Zipkin tracing", err)
-		} else {
-			errorChan <- fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
+			return parseContainerRuntimeError("Zipkin tracing", err)
 		}
-		return
+		return fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
 	}
-	errorChan <- nil
+	info.recordDuration(TimingContainerStart, zipkinContainerName, start, 0)
+	if action == containerCreate {
+		info.recordArtifact(ArtifactContainer, zipkinContainerName)
+	}
+	return nil
 }
 
-func runRedis(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func runRedis(ctx context.Context, info initInfo) error {
 	if info.slimMode || isAirGapInit {
-		return
+		return nil
 	}
 
-	redisContainerName := utils.CreateContainerName(DaprRedisContainerName, info.dockerNetwork)
+	redisContainerName := profileContainerName(DaprRedisContainerName, info.dockerNetwork)
 
 	runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
-	exists, err := confirmContainerIsRunningOrExists(redisContainerName, false, runtimeCmd)
+	imageName, err := resolveImageURI(daprImageInfo{
+		ghcrImageName:      redisGhcrImageName,
+		dockerHubImageName: redisDockerImageName,
+		imageRegistryURL:   info.imageRegistryURL,
+		imageRegistryName:  defaultImageRegistryName,
+	})
 	if err != nil {
-		errorChan <- err
-		return
+		return err
+	}
+	expected, err := lockedImage(info, DaprRedisContainerName, imageName)
+	if err != nil {
+		return err
+	}
+	action, _, err := existingContainerAction(ctx, redisContainerName, []string{expected}, runtimeCmd, info.force)
+	if err != nil {
+		return err
 	}
 	args := []string{}
 
-	var imageName string
-	if exists {
-		// do not create container again if it exists.
+	switch action {
+	case containerSkip:
+		info.progress("%s is already running", redisContainerName)
+		return nil
+	case containerStart:
+		info.progress("starting the stopped %s container", redisContainerName)
 		args = append(args, "start", redisContainerName)
-	} else {
-		imageName, err = resolveImageURI(daprImageInfo{
-			ghcrImageName:      redisGhcrImageName,
-			dockerHubImageName: redisDockerImageName,
-			imageRegistryURL:   info.imageRegistryURL,
-			imageRegistryName:  defaultImageRegistryName,
-		})
-		if err != nil {
-			errorChan <- err
-			return
-		}
-
-		args = append(args,
-			"run",
-			"--name", redisContainerName,
-			"--restart", "always",
-			"-d",
-		)
-
-		if info.dockerNetwork != "" {
-			args = append(
-				args,
-				"--network", info.dockerNetwork,
-				"--network-alias", DaprRedisContainerName)
-		} else {
-			args = append(
-				args,
-				"-p", "6379:6379")
+	default:
+		imageName = expected
+		if err = pullImage(ctx, info, imageName); err != nil {
+			return err
 		}
-		args = append(args, imageName)
+		args = redisContainerArgs(redisContainerName, imageName, info.dockerNetwork, info.ports.Redis, !info.unhardened)
 	}
-	_, err = utils.RunCmdAndWait(runtimeCmd, args...)
+	start := time.Now()
+	_, err = runContainerCmd(ctx, runtimeCmd, args...)
 
 	if err != nil {
 		runError := isContainerRunError(err)
 		if !runError {
-			errorChan <- parseContainerRuntimeError("Redis state store", err)
-		} else {
-			errorChan <- fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
+			return parseContainerRuntimeError("Redis state store", err)
+		}
+		if action == containerCreate && !info.unhardened {
+			return fmt.Errorf("%s %s failed with: %w. If the Redis image can't run as the redis user with a read-only root filesystem, use --unhardened", runtimeCmd, args, err)
 		}
-		return
+		return fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
 	}
-	errorChan <- nil
+	info.recordDuration(TimingContainerStart, redisContainerName, start, 0)
+	if action == containerCreate {
+		info.recordArtifact(ArtifactContainer, redisContainerName)
+	}
+	return nil
 }
 
-func runPlacementService(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func runPlacementService(ctx context.Context, info initInfo) error {
 	if info.slimMode {
-		return
+		return nil
 	}
 
 	runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
-	placementContainerName := utils.CreateContainerName(DaprPlacementContainerName, info.dockerNetwork)
-
-	exists, err := confirmContainerIsRunningOrExists(placementContainerName, false, runtimeCmd)
-
-	if err != nil {
-		errorChan <- err
-		return
-	} else if exists {
-		errorChan <- fmt.Errorf("%s container exists or is running. %s", placementContainerName, errInstallTemplate)
-		return
-	}
-	var image string
+	placementContainerName := profileContainerName(DaprPlacementContainerName, info.dockerNetwork)
 
 	imgInfo := daprImageInfo{
 		ghcrImageName:      daprGhcrImageName,
@@ -482,6 +861,31 @@ func runPlacementService(wg *sync.WaitGroup, errorChan chan<- error, info initIn
 		imageRegistryURL:   info.imageRegistryURL,
 		imageRegistryName:  defaultImageRegistryName,
 	}
+	images, err := placementImages(imgInfo, info)
+	if err != nil {
+		return err
+	}
+	action, image, err := existingContainerAction(ctx, placementContainerName, images, runtimeCmd, info.force)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case containerSkip:
+		info.progress("%s is already running", placementContainerName)
+	case containerStart:
+		info.progress("starting the stopped %s container", placementContainerName)
+		start := time.Now()
+		if _, err = runContainerCmd(ctx, runtimeCmd, "start", placementContainerName); err != nil {
+			return parseContainerRuntimeError("placement service", err)
+		}
+		info.recordDuration(TimingContainerStart, placementContainerName, start, 0)
+	}
+	if action != containerCreate {
+		if info.recordPlacementImage != nil {
+			info.recordPlacementImage(image)
+		}
+		return nil
+	}
 
 	if isAirGapInit {
 		// if --from-dir flag is given load the image details from the installer-bundle.
@@ -489,68 +893,100 @@ func runPlacementService(wg *sync.WaitGroup, errorChan chan<- error, info initIn
 		image = info.bundleDet.getPlacementImageName()
 		err = loadContainer(dir, info.bundleDet.getPlacementImageFileName(), info.containerRuntime)
 		if err != nil {
-			errorChan <- err
-			return
+			return err
 		}
 	} else {
 		// otherwise load the image from the specified repository.
-		image, err = getPlacementImageName(imgInfo, info)
+		image, err = getPlacementImageName(ctx, imgInfo, info)
 		if err != nil {
-			errorChan <- err
-			return
+			return err
+		}
+		image, err = lockedImage(info, DaprPlacementContainerName, image)
+		if err != nil {
+			return err
+		}
+		if err = pullImage(ctx, info, image); err != nil {
+			return placementImageNotFound(ctx, info, image, err)
 		}
 	}
 
+	args := placementRunArgs(placementContainerName, info.dockerNetwork, info.ports.Placement, image)
+	start := time.Now()
+	_, err = runContainerCmd(ctx, runtimeCmd, args...)
+
+	if err != nil {
+		if isImageNotFoundError(err) {
+			return placementImageNotFound(ctx, info, image, err)
+		}
+		runError := isContainerRunError(err)
+		if !runError {
+			return parseContainerRuntimeError("placement service", err)
+		}
+		return fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
+	}
+	info.recordDuration(TimingContainerStart, placementContainerName, start, 0)
+	info.recordArtifact(ArtifactContainer, placementContainerName)
+	if info.recordPlacementImage != nil {
+		info.recordPlacementImage(image)
+	}
+	return nil
+}
+
+// placementImageNotFound returns an *imageNotFoundError if err is the error of the container runtime not finding the
+// placement image, as happens for a version which isn't released. An image pinned by the lockfile or loaded from the
+// bundle is left to err.
+func placementImageNotFound(ctx context.Context, info initInfo, image string, err error) error {
+	if isAirGapInit || info.lock != nil {
+		return err
+	}
+	return imageNotFound(ctx, image, info.runtimeVersion, err)
+}
+
+// defaultPlacementHostPort is the host port the placement container is published on outside of a docker network.
+func defaultPlacementHostPort() int {
+	if runtime.GOOS == daprWindowsOS {
+		return 6050
+	}
+	return 50005
+}
+
+// placementRunArgs returns the container runtime arguments to run the placement container with image, either in
+// dockerNetwork or, if it is empty, with its port published on hostPort.
+func placementRunArgs(containerName, dockerNetwork string, hostPort int, image string) []string {
 	args := []string{
 		"run",
-		"--name", placementContainerName,
+		"--name", containerName,
 		"--restart", "always",
 		"-d",
 		"--entrypoint", "./placement",
 	}
+	args = append(args, platformArgs()...)
+	args = append(args, profileLabelArgs()...)
 
-	if info.dockerNetwork != "" {
+	if dockerNetwork != "" {
 		args = append(args,
-			"--network", info.dockerNetwork,
+			"--network", dockerNetwork,
 			"--network-alias", DaprPlacementContainerName)
 	} else {
-		osPort := 50005
-		if runtime.GOOS == daprWindowsOS {
-			osPort = 6050
-		}
-
 		args = append(args,
-			"-p", fmt.Sprintf("%v:50005", osPort))
+			"-p", fmt.Sprintf("%v:50005", hostPort))
 	}
 
-	args = append(args, image)
-
-	_, err = utils.RunCmdAndWait(runtimeCmd, args...)
-
-	if err != nil {
-		runError := isContainerRunError(err)
-		if !runError {
-			errorChan <- parseContainerRuntimeError("placement service", err)
-		} else {
-			errorChan <- fmt.Errorf("%s %s failed with: %w", runtimeCmd, args, err)
-		}
-		return
-	}
-	errorChan <- nil
+	return append(args, image)
 }
 
 func moveDashboardFiles(extractedFilePath string, dir string) (string, error) {
 	// Move /release/os/web directory to /web.
 	oldPath := path_filepath.Join(path_filepath.Dir(extractedFilePath), "web")
 	newPath := path_filepath.Join(dir, "web")
-	err := os.Rename(oldPath, newPath)
+	err := fsys.Rename(oldPath, newPath)
 	if err != nil {
 		err = fmt.Errorf("failed to move dashboard files: %w", err)
 		return "", err
 	}
 
 	// Move binary from /release/<os>/web/dashboard(.exe) to /dashboard(.exe).
-	err = os.Rename(extractedFilePath, path_filepath.Join(dir, path_filepath.Base(extractedFilePath)))
+	err = fsys.Rename(extractedFilePath, path_filepath.Join(dir, path_filepath.Base(extractedFilePath)))
 	if err != nil {
 		err = fmt.Errorf("error moving %s binary to path: %w", path_filepath.Base(extractedFilePath), err)
 		return "", err
@@ -560,7 +996,7 @@ func moveDashboardFiles(extractedFilePath string, dir string) (string, error) {
 	extractedFilePath = path_filepath.Join(dir, path_filepath.Base(extractedFilePath))
 
 	// Remove the now-empty 'release' directory.
-	err = os.RemoveAll(path_filepath.Join(dir, "release"))
+	err = fsys.RemoveAll(path_filepath.Join(dir, "release"))
 	if err != nil {
 		err = fmt.Errorf("error moving dashboard files: %w", err)
 		return "", err
@@ -569,65 +1005,95 @@ func moveDashboardFiles(extractedFilePath string, dir string) (string, error) {
 	return extractedFilePath, nil
 }
 
-func installDaprRuntime(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
-	err := installBinary(info.runtimeVersion, daprRuntimeFilePrefix, cli_ver.DaprGitHubRepo, info)
-	if err != nil {
-		errorChan <- err
-	}
+func installDaprRuntime(ctx context.Context, info initInfo) error {
+	return installBinary(ctx, info.runtimeVersion, daprRuntimeFilePrefix, cli_ver.DaprGitHubRepo, info)
 }
 
-func installDashboard(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
+func installDashboard(ctx context.Context, info initInfo) error {
 	if info.dashboardVersion == "" {
-		return
+		return nil
 	}
 
-	err := installBinary(info.dashboardVersion, dashboardFilePrefix, cli_ver.DashboardGitHubRepo, info)
-	if err != nil {
-		errorChan <- err
-	}
+	return installBinary(ctx, info.dashboardVersion, dashboardFilePrefix, cli_ver.DashboardGitHubRepo, info)
 }
 
-func installPlacement(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func installPlacement(ctx context.Context, info initInfo) error {
 	if !info.slimMode {
-		return
+		return nil
 	}
 
-	err := installBinary(info.runtimeVersion, placementServiceFilePrefix, cli_ver.DaprGitHubRepo, info)
-	if err != nil {
-		errorChan <- err
-	}
+	return installBinary(ctx, info.runtimeVersion, placementServiceFilePrefix, cli_ver.DaprGitHubRepo, info)
 }
 
 // installBinary installs the daprd, placement or dashboard binaries and associated files inside the default dapr bin directory.
-func installBinary(version, binaryFilePrefix, githubRepo string, info initInfo) error {
+func installBinary(ctx context.Context, version, binaryFilePrefix, githubRepo string, info initInfo) error {
 	var (
-		err      error
-		filepath string
+		err     error
+		archive downloadedFile
 	)
 
 	dir := getDaprBinPath(info.installDir)
+	// The files of an existing install which are overwritten aren't removed if init is interrupted, only the new ones.
+	installed := []string{binaryFilePathWithDir(dir, binaryFilePrefix)}
+	if binaryFilePrefix == dashboardFilePrefix {
+		installed = append(installed, path_filepath.Join(dir, "release"), path_filepath.Join(dir, "web"))
+	}
+	defer info.recordMissingPaths(installed...)()
 	if isAirGapInit {
-		filepath = path_filepath.Join(info.fromDir, *info.bundleDet.BinarySubDir, binaryName(binaryFilePrefix))
+		archive, err = hashFile(path_filepath.Join(info.fromDir, *info.bundleDet.BinarySubDir, binaryName(binaryFilePrefix)))
+		if err != nil {
+			return err
+		}
+		if err = verifyReleaseSignature(ctx, info, version, binaryFilePrefix, githubRepo, archive); err != nil {
+			return err
+		}
 	} else {
-		filepath, err = downloadBinary(dir, version, binaryFilePrefix, githubRepo)
+		err = info.pulls.run(ctx, fmt.Sprintf("the download of %s %s", binaryFilePrefix, version), info.reportProgress, func(report func(string)) error {
+			var downloadErr error
+			start := time.Now()
+			archive, downloadErr = downloadBinary(ctx, dir, version, binaryFilePrefix, githubRepo, func(downloaded, total int64) {
+				report("downloading " + formatDownloadProgress(downloaded, total))
+			})
+			if downloadErr == nil {
+				var size int64
+				if stat, statErr := fsys.Stat(archive.path); statErr == nil {
+					size = stat.Size()
+				}
+				info.recordDuration(TimingDownload, path_filepath.Base(archive.path), start, size)
+			}
+			return downloadErr
+		})
 		if err != nil {
 			return fmt.Errorf("error downloading %s binary: %w", binaryFilePrefix, err)
 		}
+		info.recordArtifact(ArtifactFile, archive.path)
+		if err = verifyReleaseSignature(ctx, info, version, binaryFilePrefix, githubRepo, archive); err != nil {
+			return err
+		}
 	}
 
-	extractedFilePath, err := extractFile(filepath, dir, binaryFilePrefix)
+	checksum, err := verifyLockedChecksum(info, binaryFilePrefix, archive)
 	if err != nil {
 		return err
 	}
+	if info.recordBinary != nil {
+		info.recordBinary(LockedBinary{Name: binaryFilePrefix, Version: version, SHA256: checksum})
+	}
+
+	extractStart := time.Now()
+	extractedFilePath, err := extractFile(archive.path, dir, binaryFilePrefix)
+	if err != nil {
+		return err
+	}
+	info.recordDuration(TimingExtract, path_filepath.Base(archive.path), extractStart, 0)
+	if err = checkBinaryRunnable(extractedFilePath); err != nil {
+		fsys.Remove(extractedFilePath)
+		return incompatibleLibcError(err, binaryFilePrefix, version)
+	}
 
 	// remove downloaded archive from the default dapr bin path.
 	if !isAirGapInit {
-		err = os.Remove(filepath)
+		err = fsys.Remove(archive.path)
 		if err != nil {
 			return fmt.Errorf("failed to remove archive: %w", err)
 		}
@@ -640,10 +1106,15 @@ func installBinary(version, binaryFilePrefix, githubRepo string, info initInfo)
 		}
 	}
 
-	binaryPath, err := moveFileToPath(extractedFilePath, dir)
+	binaryPath, pathUpdated, err := moveFileToPath(extractedFilePath, dir)
 	if err != nil {
 		return fmt.Errorf("error moving %s binary to path: %w", binaryFilePrefix, err)
 	}
+	if pathUpdated {
+		info.note("added %s to the user PATH, restart your shell to run %s directly", dir, binaryFilePrefix)
+	} else if binaryFilePrefix == daprRuntimeFilePrefix && runtime.GOOS != daprWindowsOS && !isInPath(dir) {
+		info.note("%s is not in your PATH, run `export PATH=$PATH:%s` to run daprd directly", dir, dir)
+	}
 
 	err = makeExecutable(binaryPath)
 	if err != nil {
@@ -653,62 +1124,70 @@ func installBinary(version, binaryFilePrefix, githubRepo string, info initInfo)
 	return nil
 }
 
-func createComponentsAndConfiguration(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func createComponentsAndConfiguration(ctx context.Context, info initInfo) error {
 	if info.slimMode || isAirGapInit {
-		return
+		return nil
 	}
 
-	redisHost := daprDefaultHost
-	zipkinHost := daprDefaultHost
+	redisHost := loopbackHost(utils.GetContainerRuntimeCmd(info.containerRuntime))
+	zipkinHost := redisHost
+	redisAddress := net.JoinHostPort(redisHost, strconv.Itoa(info.ports.Redis))
 	if info.dockerNetwork != "" {
 		// Default to network scoped alias of the container names when a dockerNetwork is specified.
-		redisHost = DaprRedisContainerName
+		redisAddress = net.JoinHostPort(DaprRedisContainerName, redisPort)
 		zipkinHost = DaprZipkinContainerName
 	}
 	var err error
 
 	// Make default components & config.
-	componentsDir := GetDaprComponentsPath(info.installDir)
+	componentsDir := info.componentsDir
 	configPath := GetDaprConfigPath(info.installDir)
+	defer info.recordMissingPaths(path_filepath.Join(componentsDir, pubSubYamlFileName), path_filepath.Join(componentsDir, stateStoreYamlFileName), configPath)()
 
-	err = createRedisPubSub(redisHost, componentsDir)
-	if err != nil {
-		errorChan <- fmt.Errorf("error creating redis pubsub component file: %w", err)
-		return
-	}
-	err = createRedisStateStore(redisHost, componentsDir)
-	if err != nil {
-		errorChan <- fmt.Errorf("error creating redis statestore component file: %w", err)
-		return
+	switch info.components {
+	case ComponentsProviderInMemory:
+		if err = createInMemoryComponents(info, componentsDir); err != nil {
+			return err
+		}
+	case ComponentsProviderNone:
+	default:
+		err = createRedisPubSub(redisAddress, componentsDir)
+		if err != nil {
+			return fmt.Errorf("error creating redis pubsub component file: %w", err)
+		}
+		err = createRedisStateStore(redisAddress, componentsDir)
+		if err != nil {
+			return fmt.Errorf("error creating redis statestore component file: %w", err)
+		}
 	}
-	err = createDefaultConfiguration(zipkinHost, configPath)
+	err = createDefaultConfiguration(info, zipkinHost, configPath)
 	if err != nil {
-		errorChan <- fmt.Errorf("error creating default configuration file: %w", err)
-		return
+		return fmt.Errorf("error creating default configuration file: %w", err)
 	}
+	return nil
 }
 
-func createSlimConfiguration(wg *sync.WaitGroup, errorChan chan<- error, info initInfo) {
-	defer wg.Done()
-
+func createSlimConfiguration(ctx context.Context, info initInfo) error {
 	if !(info.slimMode || isAirGapInit) {
-		return
+		return nil
 	}
 
 	configPath := GetDaprConfigPath(info.installDir)
+	defer info.recordMissingPaths(configPath)()
 	// For --slim we pass empty string so that we do not configure zipkin.
-	err := createDefaultConfiguration("", configPath)
+	err := createDefaultConfiguration(info, "", configPath)
 	if err != nil {
-		errorChan <- fmt.Errorf("error creating default configuration file: %w", err)
-		return
+		return fmt.Errorf("error creating default configuration file: %w", err)
 	}
+	// The in-memory components need nothing to run, unlike Redis which slim mode leaves to the user.
+	if info.components == ComponentsProviderInMemory {
+		defer info.recordMissingPaths(path_filepath.Join(info.componentsDir, pubSubYamlFileName), path_filepath.Join(info.componentsDir, stateStoreYamlFileName))()
+		return createInMemoryComponents(info, info.componentsDir)
+	}
+	return nil
 }
 
-func makeDefaultComponentsDir(installDir string) error {
-	// Make default components directory.
-	componentsDir := GetDaprComponentsPath(installDir)
+func makeDefaultComponentsDir(componentsDir string) error {
 	//nolint
 	_, err := os.Stat(componentsDir)
 	if os.IsNotExist(err) {
@@ -724,7 +1203,7 @@ func makeDefaultComponentsDir(installDir string) error {
 
 func makeExecutable(filepath string) error {
 	if runtime.GOOS != daprWindowsOS {
-		err := os.Chmod(filepath, 0o777)
+		err := fsys.Chmod(filepath, 0o777)
 		if err != nil {
 			return err
 		}
@@ -750,7 +1229,7 @@ func extractFile(filepath, dir, binaryFilePrefix string) (string, error) {
 		extractFunc = untarExternalFile
 	}
 
-	extractedFilePath, err := extractFunc(filepath, dir, binaryFilePrefix)
+	extractedFilePath, err := extractFunc(longPath(filepath), longPath(dir), binaryFilePrefix)
 	if err != nil {
 		return "", fmt.Errorf("error extracting %s binary: %w", binaryFilePrefix, err)
 	}
@@ -759,13 +1238,21 @@ func extractFile(filepath, dir, binaryFilePrefix string) (string, error) {
 }
 
 func unzipExternalFile(filepath, dir, binaryFilePrefix string) (string, error) {
-	r, err := zip.OpenReader(filepath)
+	f, err := fsys.Open(filepath)
+	if err != nil {
+		return "", fmt.Errorf("error open zip file %s: %w", filepath, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("error open zip file %s: %w", filepath, err)
+	}
+	r, err := zip.NewReader(f, fi.Size())
 	if err != nil {
 		return "", fmt.Errorf("error open zip file %s: %w", filepath, err)
 	}
-	defer r.Close()
 
-	return unzip(&r.Reader, dir, binaryFilePrefix)
+	return unzip(r, dir, binaryFilePrefix)
 }
 
 func unzip(r *zip.Reader, targetDir string, binaryFilePrefix string) (string, error) {
@@ -781,15 +1268,15 @@ func unzip(r *zip.Reader, targetDir string, binaryFilePrefix string) (string, er
 		}
 
 		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
+			fsys.MkdirAll(fpath, os.ModePerm)
 			continue
 		}
 
-		if err = os.MkdirAll(path_filepath.Dir(fpath), os.ModePerm); err != nil {
+		if err = fsys.MkdirAll(path_filepath.Dir(fpath), os.ModePerm); err != nil {
 			return "", err
 		}
 
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		outFile, err := fsys.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
 		if err != nil {
 			return "", err
 		}
@@ -813,7 +1300,7 @@ func unzip(r *zip.Reader, targetDir string, binaryFilePrefix string) (string, er
 }
 
 func untarExternalFile(filepath, dir, binaryFilePrefix string) (string, error) {
-	reader, err := os.Open(filepath)
+	reader, err := fsys.Open(filepath)
 	if err != nil {
 		return "", fmt.Errorf("error open tar gz file %s: %w", filepath, err)
 	}
@@ -851,13 +1338,14 @@ func untar(reader io.Reader, targetDir string, binaryFilePrefix string) (string,
 
 		info := header.FileInfo()
 		if info.IsDir() {
-			if err = os.MkdirAll(path, info.Mode()); err != nil {
+			if err = fsys.MkdirAll(path, info.Mode()); err != nil {
 				return "", err
 			}
 			continue
 		}
 
-		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+		// A binary of a previous install is truncated, not overwritten in place.
+		f, err := fsys.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
 		if err != nil {
 			return "", err
 		}
@@ -876,56 +1364,56 @@ func untar(reader io.Reader, targetDir string, binaryFilePrefix string) (string,
 	return foundBinary, nil
 }
 
-func moveFileToPath(filepath string, installLocation string) (string, error) {
-	fileName := path_filepath.Base(filepath)
-	destFilePath := ""
+// isInPath reports whether dir is one of the directories of the PATH environment variable.
+func isInPath(dir string) bool {
+	for _, p := range path_filepath.SplitList(os.Getenv("PATH")) {
+		if path_filepath.Clean(p) == path_filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
 
+// moveFileToPath moves the file at filepath to installLocation, replacing the file there, and returns its new path.
+// On Windows, it also adds installLocation to the user PATH, and reports whether it did.
+func moveFileToPath(filepath string, installLocation string) (string, bool, error) {
 	destDir := installLocation
-	destFilePath = path.Join(destDir, fileName)
+	destFilePath := path_filepath.Join(destDir, path_filepath.Base(filepath))
 
-	input, err := os.ReadFile(filepath)
-	if err != nil {
-		return "", err
+	if _, err := fsys.Stat(longPath(filepath)); err != nil {
+		return "", false, err
 	}
 
-	err = utils.CreateDirectory(destDir)
+	err := fsys.MkdirAll(longPath(destDir), 0o777)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	// #nosec G306
-	if err = os.WriteFile(destFilePath, input, 0o644); err != nil {
-		if runtime.GOOS != daprWindowsOS && strings.Contains(err.Error(), "permission denied") {
-			err = errors.New(err.Error() + " - please run with sudo")
+	if path_filepath.Clean(filepath) != path_filepath.Clean(destFilePath) {
+		if err = moveFile(longPath(filepath), longPath(destFilePath)); err != nil {
+			if runtime.GOOS != daprWindowsOS && errors.Is(err, os.ErrPermission) {
+				err = fmt.Errorf("%w - please run with sudo", err)
+			}
+			return "", false, err
 		}
-		return "", err
 	}
 
 	if runtime.GOOS == daprWindowsOS {
-		p := os.Getenv("PATH")
-
-		if !strings.Contains(strings.ToLower(p), strings.ToLower(destDir)) {
-			pathCmd := "[System.Environment]::SetEnvironmentVariable('Path',[System.Environment]::GetEnvironmentVariable('Path','user') + '" + fmt.Sprintf(";%s", destDir) + "', 'user')"
-			_, err := utils.RunCmdAndWait("powershell", pathCmd)
-			if err != nil {
-				return "", err
-			}
+		// The user PATH is only edited if the directory isn't in the PATH of the process already.
+		if _, missing := appendPathListEntry(os.Getenv("PATH"), destDir); !missing {
+			return destFilePath, false, nil
 		}
-
-		return fmt.Sprintf("%s\\daprd.exe", destDir), nil
-	}
-
-	if strings.HasPrefix(fileName, daprRuntimeFilePrefix) && installLocation != "" {
-		color.Set(color.FgYellow)
-		fmt.Printf("\nDapr runtime installed to %s, you may run the following to add it to your path if you want to run daprd directly:\n", destDir)
-		fmt.Printf("    export PATH=$PATH:%s\n", destDir)
-		color.Unset()
+		added, err := addToUserPath(destDir)
+		if err != nil {
+			return "", false, err
+		}
+		return destFilePath, added, nil
 	}
 
-	return destFilePath, nil
+	return destFilePath, false, nil
 }
 
-func createRedisStateStore(redisHost string, componentsPath string) error {
+func createRedisStateStore(redisAddress string, componentsPath string) error {
 	redisStore := component{
 		APIVersion: "dapr.io/v1alpha1",
 		Kind:       "Component",
@@ -937,7 +1425,7 @@ func createRedisStateStore(redisHost string, componentsPath string) error {
 	redisStore.Spec.Metadata = []componentMetadataItem{
 		{
 			Name:  "redisHost",
-			Value: fmt.Sprintf("%s:6379", redisHost),
+			Value: redisAddress,
 		},
 		{
 			Name:  "redisPassword",
@@ -960,7 +1448,7 @@ func createRedisStateStore(redisHost string, componentsPath string) error {
 	return err
 }
 
-func createRedisPubSub(redisHost string, componentsPath string) error {
+func createRedisPubSub(redisAddress string, componentsPath string) error {
 	redisPubSub := component{
 		APIVersion: "dapr.io/v1alpha1",
 		Kind:       "Component",
@@ -972,7 +1460,7 @@ func createRedisPubSub(redisHost string, componentsPath string) error {
 	redisPubSub.Spec.Metadata = []componentMetadataItem{
 		{
 			Name:  "redisHost",
-			Value: fmt.Sprintf("%s:6379", redisHost),
+			Value: redisAddress,
 		},
 		{
 			Name:  "redisPassword",
@@ -991,7 +1479,9 @@ func createRedisPubSub(redisHost string, componentsPath string) error {
 	return err
 }
 
-func createDefaultConfiguration(zipkinHost, filePath string) error {
+// createDefaultConfiguration writes the default configuration of the runtime, with tracing to zipkinHost if set.
+// A configuration which differs from the default is kept, unless info.force is set, as it may have been edited.
+func createDefaultConfiguration(info initInfo, zipkinHost, filePath string) error {
 	defaultConfig := configuration{
 		APIVersion: "dapr.io/v1alpha1",
 		Kind:       "Configuration",
@@ -999,16 +1489,34 @@ func createDefaultConfiguration(zipkinHost, filePath string) error {
 	defaultConfig.Metadata.Name = "daprConfig"
 	if zipkinHost != "" {
 		defaultConfig.Spec.Tracing.SamplingRate = "1"
-		defaultConfig.Spec.Tracing.Zipkin.EndpointAddress = fmt.Sprintf("http://%s:9411/api/v2/spans", zipkinHost) //nolint:nosprintfhostport
+		// The zipkin container is reached on its own port in a docker network, and on the published one otherwise.
+		port := zipkinContainerPort
+		if info.dockerNetwork == "" && info.ports.Zipkin != 0 {
+			port = info.ports.Zipkin
+		}
+		defaultConfig.Spec.Tracing.Zipkin.EndpointAddress = fmt.Sprintf("http://%s:%d/api/v2/spans", zipkinHost, port) //nolint:nosprintfhostport
 	}
 	b, err := yaml.Marshal(&defaultConfig)
 	if err != nil {
 		return err
 	}
 
-	err = checkAndOverWriteFile(filePath, b)
-
-	return err
+	existing, err := os.ReadFile(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	case bytes.Equal(existing, b):
+		return nil
+	case !info.force:
+		info.note("kept %s as it differs from the default configuration, use --force to regenerate it", filePath)
+		if info.recordKept != nil {
+			info.recordKept(filePath)
+		}
+		return nil
+	}
+	// #nosec G306
+	return os.WriteFile(filePath, b, 0o644)
 }
 
 func checkAndOverWriteFile(filePath string, b []byte) error {
@@ -1023,12 +1531,12 @@ func checkAndOverWriteFile(filePath string, b []byte) error {
 }
 
 func prepareDaprInstallDir(daprBinDir string) error {
-	err := os.MkdirAll(daprBinDir, 0o777)
+	err := fsys.MkdirAll(daprBinDir, 0o777)
 	if err != nil {
 		return err
 	}
 
-	err = os.Chmod(daprBinDir, 0o777)
+	err = fsys.Chmod(daprBinDir, 0o777)
 	if err != nil {
 		return err
 	}
@@ -1045,72 +1553,248 @@ func archiveExt() string {
 	return ext
 }
 
-func downloadBinary(dir, version, binaryFilePrefix, githubRepo string) (string, error) {
-	fileURL := fmt.Sprintf(
-		"https://github.com/%s/%s/releases/download/v%s/%s",
-		cli_ver.DaprGitHubOrg,
-		githubRepo,
-		version,
-		binaryName(binaryFilePrefix))
+func downloadBinary(ctx context.Context, dir, version, binaryFilePrefix, githubRepo string, onProgress func(downloaded, total int64)) (downloadedFile, error) {
+	arch, emulated, err := resolveArtifactArch(ctx, version, binaryFilePrefix, githubRepo)
+	if err != nil {
+		return downloadedFile{}, versionNotFoundError(err, version, binaryFilePrefix, githubRepo)
+	}
+	if emulated {
+		print.WarningStatusEvent(os.Stdout, "No darwin/arm64 build of %s %s is published, installing the darwin/amd64 one to run under Rosetta", binaryFilePrefix, version)
+	}
+	archive, err := downloadFile(ctx, dir, binaryDownloadURL(version, binaryFilePrefix, githubRepo, arch), onProgress)
+	if err != nil {
+		return downloadedFile{}, versionNotFoundError(err, version, binaryFilePrefix, githubRepo)
+	}
+	return archive, nil
+}
+
+// maxVersionSuggestions is the number of versions suggested for one which isn't released.
+const maxVersionSuggestions = 3
+
+// versionNotFoundError adds the released versions close to version to err, if it is the error of an archive which
+// isn't published because version isn't released.
+func versionNotFoundError(err error, version, binaryFilePrefix, githubRepo string) error {
+	if !errors.Is(err, ErrNoBuildPublished) && !errors.Is(err, errVersionNotFound) {
+		return err
+	}
+	if suggestions := versionSuggestions(version, githubRepo); len(suggestions) > 0 {
+		return fmt.Errorf("%w\n%s %s isn't released, did you mean %s?", err, binaryFilePrefix, version, strings.Join(suggestions, " or "))
+	}
+	return err
+}
+
+// versionSuggestions returns the released versions of githubRepo to suggest for version: the newest patches of its
+// minor version, or else the latest release. There are none if version is released, or if the releases can't be
+// listed.
+func versionSuggestions(version, githubRepo string) []string {
+	requested, err := go_version.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+	releases, err := cli_ver.ListReleasesGithub(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=100", cli_ver.DaprGitHubOrg, githubRepo), requested.Prerelease() != "")
+	if err != nil {
+		return nil
+	}
+	var suggestions []string
+	for _, r := range releases {
+		released, err := go_version.NewVersion(r)
+		if err != nil {
+			continue
+		}
+		if released.Equal(requested) {
+			return nil
+		}
+		if released.Segments()[0] == requested.Segments()[0] && released.Segments()[1] == requested.Segments()[1] && len(suggestions) < maxVersionSuggestions {
+			suggestions = append(suggestions, r)
+		}
+	}
+	if len(suggestions) == 0 {
+		suggestions = releases[:1]
+	}
+	return suggestions
+}
+
+// binaryDownloadURL returns the URL of the release archive of the binary for arch on this OS.
+func binaryDownloadURL(version, binaryFilePrefix, githubRepo, arch string) string {
+	return releaseDownloadURL(version, githubRepo) + "/" + binaryNameForArch(binaryFilePrefix, arch)
+}
+
+// downloadURL is the base URL the releases are downloaded from, the one of GitHub or the one of a mirror of its
+// releases set with the downloadURL setting.
+var downloadURL = defaultDownloadURL
 
-	return downloadFile(dir, fileURL)
+// useDownloadURL downloads the releases from the base URL of the downloadURL setting of the install in
+// daprRuntimePath.
+func useDownloadURL(daprRuntimePath string) error {
+	setting, err := ResolveSetting(SettingDownloadURL, nil, daprRuntimePath)
+	if err != nil {
+		return err
+	}
+	downloadURL = strings.TrimSuffix(setting.Value, "/")
+	return nil
 }
 
+// releasesURL returns the URL of the releases of githubRepo.
+func releasesURL(githubRepo string) string {
+	return fmt.Sprintf("%s/%s/%s/releases", downloadURL, cli_ver.DaprGitHubOrg, githubRepo)
+}
+
+// releaseDownloadURL returns the URL the assets of the release of version of githubRepo are downloaded from.
+func releaseDownloadURL(version, githubRepo string) string {
+	return fmt.Sprintf("%s/download/v%s", releasesURL(githubRepo), version)
+}
+
+// binaryName returns the name of the release archive of the binary for this machine.
 func binaryName(binaryFilePrefix string) string {
-	return fmt.Sprintf("%s_%s_%s.%s", binaryFilePrefix, runtime.GOOS, runtime.GOARCH, archiveExt())
+	arch, err := artifactArch(runtime.GOOS, hostArch())
+	if err != nil {
+		arch = runtime.GOARCH
+	}
+	return binaryNameForArch(binaryFilePrefix, arch)
 }
 
-func downloadFile(dir string, url string) (string, error) {
+func binaryNameForArch(binaryFilePrefix, arch string) string {
+	return fmt.Sprintf("%s_%s_%s.%s", binaryFilePrefix, runtime.GOOS, arch, archiveExt())
+}
+
+// downloadedFile is a file downloaded by downloadFile, along with the SHA-256 checksum of its content.
+type downloadedFile struct {
+	path   string
+	sha256 string
+}
+
+// hashFile returns the file at path, such as an archive of a bundle, with its checksum. It reads it, while the
+// checksum of a downloaded file is computed as it is written.
+func hashFile(path string) (downloadedFile, error) {
+	sum, err := fileSHA256(path)
+	return downloadedFile{path: path, sha256: sum}, err
+}
+
+// downloadFile downloads url to dir, calling onProgress periodically with the number of bytes downloaded so far and the
+// total size, which is -1 if unknown. The checksum of the file is computed as it is written, so that verifying it
+// doesn't read the file again. An interrupted download is kept next to the file, and resumed by the next download of
+// url, whose checksum starts from the part already downloaded.
+func downloadFile(ctx context.Context, dir string, url string, onProgress func(downloaded, total int64)) (downloadedFile, error) {
 	tokens := strings.Split(url, "/")
 	fileName := tokens[len(tokens)-1]
 
-	filepath := path.Join(dir, fileName)
-	_, err := os.Stat(filepath)
-	if os.IsExist(err) {
-		return "", nil
-	}
-	client := http.Client{ //nolint:exhaustruct
-		Timeout: 0,
-		Transport: &http.Transport{ //nolint:exhaustruct
-			Dial: (&net.Dialer{ //nolint:exhaustruct
-				Timeout: 30 * time.Second,
-			}).Dial,
-			TLSHandshakeTimeout:   15 * time.Second,
-			ResponseHeaderTimeout: 15 * time.Second,
-			Proxy:                 http.ProxyFromEnvironment,
-		},
+	filepath := path_filepath.Join(dir, fileName)
+	partial := partialDownloadPath(filepath, url)
+	h := sha256.New()
+	offset, err := hashPartialDownload(partial, h)
+	if err != nil {
+		return downloadedFile{}, err
 	}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return downloadedFile{}, err
+	}
+	req.Header.Set("User-Agent", cli_ver.CLI.UserAgent())
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
 
-	resp, err := client.Do(req)
+	resp, err := utils.HTTPClient().Do(req)
 	if err != nil {
-		return "", err
+		return downloadedFile{}, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("version not found from url: %s", url)
-	} else if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download failed with %d", resp.StatusCode)
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return downloadedFile{}, fmt.Errorf("%w: %s", errVersionNotFound, url)
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The part downloaded isn't one of the file, which changed since.
+		if err = fsys.Remove(partial); err != nil {
+			return downloadedFile{}, err
+		}
+		return downloadFile(ctx, dir, url, onProgress)
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		// The server sends the whole file, without resuming the download.
+		offset = 0
+		h.Reset()
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	default:
+		return downloadedFile{}, fmt.Errorf("download failed with %d", resp.StatusCode)
 	}
 
-	out, err := os.Create(filepath)
+	out, err := fsys.OpenFile(partial, flag, 0o644)
 	if err != nil {
-		return "", err
+		return downloadedFile{}, err
+	}
+	total := resp.ContentLength
+	if total >= 0 {
+		total += offset
+	}
+	body := &progressReader{r: resp.Body, total: total, read: offset, onProgress: onProgress}
+	_, err = copyWithTimeout(ctx, io.MultiWriter(out, h), body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
 	}
-	defer out.Close()
-
-	_, err = copyWithTimeout(context.Background(), out, resp.Body)
 	if err != nil {
-		return "", err
+		return downloadedFile{}, err
+	}
+	if err = fsys.Rename(partial, filepath); err != nil {
+		return downloadedFile{}, err
+	}
+	return downloadedFile{path: filepath, sha256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// partialDownloadSuffix is the suffix of the downloads which haven't completed yet.
+const partialDownloadSuffix = ".partial"
+
+// partialDownloadPath returns the path the download of url to filepath is written to until it completes. It is
+// distinct for each url, as the archives of all the versions of a binary have the same name.
+func partialDownloadPath(filepath, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return fmt.Sprintf("%s.%s%s", filepath, hex.EncodeToString(sum[:4]), partialDownloadSuffix)
+}
+
+// hashPartialDownload writes the part of a download already at path to h, and returns its size, 0 if there is none.
+func hashPartialDownload(path string, h io.Writer) (int64, error) {
+	f, err := fsys.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
 	}
+	defer f.Close()
+	return io.Copy(h, f)
+}
+
+// downloadProgressInterval is the minimum time between two download progress reports.
+const downloadProgressInterval = 250 * time.Millisecond
 
-	return filepath, nil
+// progressReader reports the number of bytes read from r, at most once per downloadProgressInterval.
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	reported   time.Time
+	onProgress func(read, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.onProgress != nil && (time.Since(p.reported) >= downloadProgressInterval || errors.Is(err, io.EOF)) {
+		p.reported = time.Now()
+		p.onProgress(p.read, p.total)
+	}
+	return n, err
+}
+
+// formatDownloadProgress formats the download progress as e.g. 12.3/41.2 MB.
+func formatDownloadProgress(downloaded, total int64) string {
+	const mb = 1024 * 1024
+	if total <= 0 {
+		return fmt.Sprintf("%.1f MB", float64(downloaded)/mb)
+	}
+	return fmt.Sprintf("%.1f/%.1f MB", float64(downloaded)/mb, float64(total)/mb)
 }
 
 /*
@@ -1185,7 +1869,7 @@ func copyWithTimeout(ctx context.Context, dst io.Writer, src io.Reader) (int64,
 // getPlacementImageName returns the resolved placement image name for online `dapr init`.
 // It can either be resolved to the image-registry if given, otherwise GitHub container registry if
 // selected or fallback to Docker Hub.
-func getPlacementImageName(imageInfo daprImageInfo, info initInfo) (string, error) {
+func getPlacementImageName(ctx context.Context, imageInfo daprImageInfo, info initInfo) (string, error) {
 	image, err := resolveImageURI(imageInfo)
 	if err != nil {
 		return "", err
@@ -1198,7 +1882,7 @@ func getPlacementImageName(imageInfo daprImageInfo, info initInfo) (string, erro
 
 	// if default registry is GHCR and the image is not available in or cannot be pulled from GHCR
 	// fallback to using dockerhub.
-	if useGHCR(imageInfo, info.fromDir) && !tryPullImage(image, info.containerRuntime) {
+	if useGHCR(imageInfo, info.fromDir) && pullImage(ctx, info, image) != nil {
 		print.InfoStatusEvent(os.Stdout, "Placement image not found in Github container registry, pulling it from Docker Hub")
 		image, err = getPlacementImageWithTag(daprDockerImageName, info.runtimeVersion, info.imageVariant)
 		if err != nil {
@@ -1208,6 +1892,31 @@ func getPlacementImageName(imageInfo daprImageInfo, info initInfo) (string, erro
 	return image, nil
 }
 
+// placementImages returns the images the placement container may run with imageInfo, as getPlacementImageName
+// returns them, without pulling them.
+func placementImages(imageInfo daprImageInfo, info initInfo) ([]string, error) {
+	if isAirGapInit {
+		return []string{info.bundleDet.getPlacementImageName()}, nil
+	}
+	image, err := resolveImageURI(imageInfo)
+	if err != nil {
+		return nil, err
+	}
+	images := []string{image}
+	if useGHCR(imageInfo, info.fromDir) {
+		images = append(images, daprDockerImageName)
+	}
+	for i := range images {
+		if images[i], err = getPlacementImageWithTag(images[i], info.runtimeVersion, info.imageVariant); err != nil {
+			return nil, err
+		}
+		if images[i], err = lockedImage(info, DaprPlacementContainerName, images[i]); err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
+
 func getPlacementImageWithTag(name, version, imageVariant string) (string, error) {
 	err := utils.ValidateImageVariant(imageVariant)
 	if err != nil {
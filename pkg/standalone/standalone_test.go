@@ -37,7 +37,7 @@ spec:
       endpointAddress: http://test_zipkin_host:9411/api/v2/spans
 `
 		os.Remove(testFile)
-		createDefaultConfiguration("test_zipkin_host", testFile)
+		createDefaultConfiguration(initInfo{}, "test_zipkin_host", testFile)
 		assert.FileExists(t, testFile)
 		content, err := os.ReadFile(testFile)
 		assert.NoError(t, err)
@@ -52,13 +52,40 @@ metadata:
 spec: {}
 `
 		os.Remove(testFile)
-		createDefaultConfiguration("", testFile)
+		createDefaultConfiguration(initInfo{}, "", testFile)
 		assert.FileExists(t, testFile)
 		content, err := os.ReadFile(testFile)
 		assert.NoError(t, err)
 		assert.Equal(t, expectConfigSlim, string(content))
 	})
 
+	t.Run("Standalone config keeps edits unless forced", func(t *testing.T) {
+		var warnings, notes, kept []string
+		info := initInfo{
+			reportWarning: func(message string) { warnings = append(warnings, message) },
+			recordNote:    func(note InitWarning) { notes = append(notes, note.Message) },
+			recordKept:    func(path string) { kept = append(kept, path) },
+		}
+		os.WriteFile(testFile, []byte("edited"), 0o600)
+
+		createDefaultConfiguration(info, "", testFile)
+		content, err := os.ReadFile(testFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "edited", string(content))
+		// Keeping the edits is a note, which doesn't fail init with --strict.
+		assert.Empty(t, warnings)
+		assert.Len(t, notes, 1)
+		assert.Equal(t, []string{testFile}, kept)
+
+		info.force = true
+		createDefaultConfiguration(info, "", testFile)
+		content, err = os.ReadFile(testFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "daprConfig")
+		assert.Len(t, notes, 1)
+		assert.Len(t, kept, 1)
+	})
+
 	os.Remove(testFile)
 }
 
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dapr/cli/pkg/api"
+	"github.com/dapr/cli/utils"
+)
+
+// DefaultStateStore is the name of the state store component generated by init.
+const DefaultStateStore = "statestore"
+
+// ErrStateKeyNotFound is returned by GetState when the state store has no value for the key.
+var ErrStateKeyNotFound = errors.New("key not found")
+
+// StateItem is a value of a state store.
+type StateItem struct {
+	Key   string
+	Value []byte
+	ETag  string
+}
+
+// FormatStateValue returns value for printing: indented if it is JSON, as is if it is text, and base64 encoded
+// otherwise, in which case encoded is true.
+func FormatStateValue(value []byte) (formatted string, encoded bool) {
+	if json.Valid(value) {
+		var b bytes.Buffer
+		if err := json.Indent(&b, value, "", "  "); err == nil {
+			return b.String(), false
+		}
+	}
+	if utf8.Valid(value) {
+		return string(value), false
+	}
+	return base64.StdEncoding.EncodeToString(value), true
+}
+
+// sidecarError is the body of the error responses of the sidecar.
+type sidecarError struct {
+	ErrorCode string `json:"errorCode"`
+	Message   string `json:"message"`
+}
+
+// GetState returns the value of key in store, through the sidecar of appID. The error wraps ErrStateKeyNotFound if
+// the store has no value for the key.
+func (s *Standalone) GetState(appID, store, key string) (StateItem, error) {
+	r, err := s.stateRequest(appID, http.MethodGet, store, key, nil)
+	if err != nil {
+		return StateItem{}, err
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return StateItem{}, err
+	}
+	if r.StatusCode == http.StatusNoContent {
+		return StateItem{}, fmt.Errorf("%w: state store %s has no value for key %s", ErrStateKeyNotFound, store, key)
+	}
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return StateItem{}, stateError(appID, store, r.Status, body)
+	}
+	return StateItem{Key: key, Value: body, ETag: r.Header.Get("ETag")}, nil
+}
+
+// SaveState sets key to value in store, through the sidecar of appID. A value which isn't JSON is saved as a string.
+func (s *Standalone) SaveState(appID, store, key string, value []byte) error {
+	item := map[string]interface{}{"key": key}
+	if json.Valid(value) {
+		item["value"] = json.RawMessage(value)
+	} else {
+		item["value"] = string(value)
+	}
+	body, err := json.Marshal([]interface{}{item})
+	if err != nil {
+		return err
+	}
+	return s.stateChange(appID, http.MethodPost, store, "", body)
+}
+
+// DeleteState deletes key from store, through the sidecar of appID.
+func (s *Standalone) DeleteState(appID, store, key string) error {
+	return s.stateChange(appID, http.MethodDelete, store, key, nil)
+}
+
+func (s *Standalone) stateChange(appID, method, store, key string, body []byte) error {
+	r, err := s.stateRequest(appID, method, store, key, body)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		return stateError(appID, store, r.Status, b)
+	}
+	return nil
+}
+
+// stateRequest sends a request to the state API of the sidecar of appID, for key of store if set.
+func (s *Standalone) stateRequest(appID, method, store, key string, body []byte) (*http.Response, error) {
+	list, err := s.process.List()
+	if err != nil {
+		return nil, err
+	}
+	lo, err := findRunningApp(list, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v%s/state/%s", api.RuntimeAPIVersion, url.PathEscape(store))
+	if key != "" {
+		path += "/" + url.PathEscape(key)
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("http://127.0.0.1:%d%s", lo.HTTPPort, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var httpc http.Client
+	if lo.UnixDomainSocket != "" {
+		httpc.Transport = &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", utils.GetSocket(lo.UnixDomainSocket, appID, "http"))
+			},
+		}
+	}
+	return httpc.Do(req)
+}
+
+// stateError translates the error response of the state API into an error about the app and the store.
+func stateError(appID, store, status string, body []byte) error {
+	var e sidecarError
+	if err := json.Unmarshal(body, &e); err != nil || e.ErrorCode == "" {
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return fmt.Errorf("%s: %s", status, msg)
+		}
+		return errors.New(status)
+	}
+	switch e.ErrorCode {
+	case "ERR_STATE_STORE_NOT_FOUND":
+		return fmt.Errorf("state store %s not found in app %s: check the name given with --store and the components of the app", store, appID)
+	case "ERR_STATE_STORES_NOT_CONFIGURED":
+		return fmt.Errorf("app %s has no state store: add a state store component to its resources", appID)
+	}
+	return fmt.Errorf("%s: %s", e.ErrorCode, e.Message)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	values := map[string]json.RawMessage{}
+	ts, port := getTestServerFunc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1.0/state/"), "/")
+		switch store {
+		case "statestore":
+		case "":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errorCode":"ERR_STATE_STORES_NOT_CONFIGURED","message":"state store is not configured"}`))
+			return
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorCode":"ERR_STATE_STORE_NOT_FOUND","message":"state store ` + store + ` is not found"}`))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			v, ok := values[key]
+			if !ok {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("ETag", "1")
+			w.Write(v)
+		case http.MethodPost:
+			var items []struct {
+				Key   string          `json:"key"`
+				Value json.RawMessage `json:"value"`
+			}
+			b, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(b, &items); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for _, item := range items {
+				values[item.Key] = item.Value
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(values, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	ts.Start()
+	defer ts.Close()
+
+	client := &Standalone{process: &mockDaprProcess{Lo: []ListOutput{{AppID: "orders", HTTPPort: port}}}}
+
+	require.NoError(t, client.SaveState("orders", "statestore", "order-1", []byte(`{"id":1}`)))
+	require.NoError(t, client.SaveState("orders", "statestore", "note", []byte("hello")))
+
+	item, err := client.GetState("orders", "statestore", "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, StateItem{Key: "order-1", Value: []byte(`{"id":1}`), ETag: "1"}, item)
+
+	item, err = client.GetState("orders", "statestore", "note")
+	require.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(item.Value))
+
+	require.NoError(t, client.DeleteState("orders", "statestore", "order-1"))
+	_, err = client.GetState("orders", "statestore", "order-1")
+	assert.ErrorIs(t, err, ErrStateKeyNotFound)
+
+	_, err = client.GetState("orders", "cache", "order-1")
+	assert.EqualError(t, err, "state store cache not found in app orders: check the name given with --store and the components of the app")
+
+	err = client.SaveState("orders", "", "order-1", []byte("1"))
+	assert.EqualError(t, err, "app orders has no state store: add a state store component to its resources")
+
+	_, err = client.GetState("order", "statestore", "order-1")
+	assert.EqualError(t, err, "no such running app order, did you mean orders?")
+}
+
+func TestFormatStateValue(t *testing.T) {
+	testCases := []struct {
+		value    []byte
+		expected string
+		encoded  bool
+	}{
+		{value: []byte(`{"id":1}`), expected: "{\n  \"id\": 1\n}"},
+		{value: []byte(`"hello"`), expected: `"hello"`},
+		{value: []byte("plain text"), expected: "plain text"},
+		{value: []byte{0xff, 0x00, 0xfe}, expected: "/wD+", encoded: true},
+	}
+	for _, tc := range testCases {
+		formatted, encoded := FormatStateValue(tc.value)
+		assert.Equal(t, tc.expected, formatted)
+		assert.Equal(t, tc.encoded, encoded)
+	}
+}
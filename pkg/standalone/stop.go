@@ -19,30 +19,27 @@ package standalone
 import (
 	"fmt"
 	"syscall"
-
-	"github.com/dapr/cli/utils"
 )
 
-// Stop terminates the application process.
-func Stop(appID string, cliPIDToNoOfApps map[int]int, apps []ListOutput) error {
-	for _, a := range apps {
-		if a.AppID == appID {
-			var pid string
-			// Kill the Daprd process if Daprd was started without CLI, otherwise
-			// kill the CLI process which also kills the associated Daprd process.
-			if a.CliPID == 0 || cliPIDToNoOfApps[a.CliPID] > 1 {
-				pid = fmt.Sprintf("%v", a.DaprdPID)
-				cliPIDToNoOfApps[a.CliPID]--
-			} else {
-				pid = fmt.Sprintf("%v", a.CliPID)
-			}
-
-			_, err := utils.RunCmdAndWait("kill", pid)
+// terminateProcess asks the daprd and app processes to terminate with SIGTERM, the app along with the processes it
+// started. The CLI process isn't signaled, it exits once its daprd process has.
+func terminateProcess(p stopProcess) error {
+	switch p.name {
+	case "cli":
+		return nil
+	case "app":
+		return signalProcessGroup(p.pid, syscall.SIGTERM)
+	default:
+		return syscall.Kill(p.pid, syscall.SIGTERM)
+	}
+}
 
-			return err
-		}
+// killProcess forcefully terminates the process, the app along with the processes it started.
+func killProcess(p stopProcess) error {
+	if p.name == "app" {
+		return signalProcessGroup(p.pid, syscall.SIGKILL)
 	}
-	return fmt.Errorf("couldn't find app id %s", appID)
+	return syscall.Kill(p.pid, syscall.SIGKILL)
 }
 
 // StopAppsWithRunFile terminates the daprd and application processes with the given run file.
@@ -57,7 +54,7 @@ func StopAppsWithRunFile(runTemplatePath string) error {
 			pgid, err := syscall.Getpgid(a.CliPID)
 			if err != nil {
 				// Fall back to cliPID if pgid is not available.
-				_, err = utils.RunCmdAndWait("kill", fmt.Sprintf("%v", a.CliPID))
+				_, err = runCmd("kill", fmt.Sprintf("%v", a.CliPID))
 				return err
 			}
 			// Kill the whole process group.
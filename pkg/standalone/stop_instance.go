@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// DefaultStopGracePeriod is how long the processes of an instance have to exit before they are killed.
+const DefaultStopGracePeriod = 10 * time.Second
+
+const (
+	stopPollInterval = 100 * time.Millisecond
+	// shutdownRequestTimeout is how long the sidecar has to answer the shutdown request.
+	shutdownRequestTimeout = 5 * time.Second
+)
+
+// Results of stopping a process.
+const (
+	ProcessShutDown   = "shut down through the API"
+	ProcessStopped    = "stopped"
+	ProcessKilled     = "killed after the grace period"
+	ProcessNotRunning = "not running"
+)
+
+// errShutdownUnsupported is returned by requestSidecarShutdown for the runtime versions without the shutdown API.
+var errShutdownUnsupported = errors.New("the runtime version of the sidecar has no shutdown API")
+
+// ProcessStopResult is the result of stopping one of the processes of an instance.
+type ProcessStopResult struct {
+	Name   string `json:"name"`
+	PID    int    `json:"pid"`
+	Result string `json:"result"`
+	// Fallback is why the sidecar was signaled after it was asked to shut down through its API, if it was.
+	Fallback string `json:"fallback,omitempty"`
+	Err      error  `json:"-"`
+}
+
+// stopProcess is a process of an instance to stop.
+type stopProcess struct {
+	name string
+	pid  int
+	// httpPort is the HTTP port of the sidecar, for daprd.
+	httpPort int
+	// socket is the unix domain socket of the HTTP API of the sidecar, for daprd, which is used instead of httpPort.
+	socket string
+}
+
+// Stop terminates the processes of the instance of appID in apps, with the default grace period.
+func Stop(appID string, cliPIDToNoOfApps map[int]int, apps []ListOutput) error {
+	for _, a := range apps {
+		if a.AppID == appID {
+			for _, r := range StopInstance(a, cliPIDToNoOfApps, DefaultStopGracePeriod, false) {
+				if r.Err != nil {
+					return r.Err
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("couldn't find app id %s", appID)
+}
+
+// StopInstance stops the daprd and app processes of instance. The sidecar is first asked to shut down through its
+// API, unless force is set, and given up to grace to exit while the app keeps serving it. The processes still
+// running are then asked to terminate, and killed if they are still running after another grace. The CLI process
+// which started the instance is expected to exit with them unless it started other apps, as counted by
+// cliPIDToNoOfApps which is updated.
+func StopInstance(instance ListOutput, cliPIDToNoOfApps map[int]int, grace time.Duration, force bool) []ProcessStopResult {
+	daprd := stopProcess{name: "daprd", pid: instance.DaprdPID, httpPort: instance.HTTPPort}
+	if instance.UnixDomainSocket != "" {
+		daprd.socket = utils.GetSocket(instance.UnixDomainSocket, instance.AppID, "http")
+	}
+	processes := []stopProcess{daprd}
+	if instance.AppPID > 0 {
+		processes = append(processes, stopProcess{name: "app", pid: instance.AppPID})
+	}
+	if instance.CliPID > 0 {
+		if cliPIDToNoOfApps[instance.CliPID] <= 1 {
+			processes = append(processes, stopProcess{name: "cli", pid: instance.CliPID})
+		}
+		cliPIDToNoOfApps[instance.CliPID]--
+	}
+	return stopProcesses(processes, grace, force)
+}
+
+// TerminateSidecarProcess asks the daprd process with pid, serving the HTTP API on httpPort, to exit gracefully:
+// through its shutdown API, or with a termination signal for the runtime versions without it.
+func TerminateSidecarProcess(pid, httpPort int) error {
+	p := stopProcess{name: "daprd", pid: pid, httpPort: httpPort}
+	if err := requestSidecarShutdown(p); err == nil {
+		return nil
+	}
+	return terminateProcess(p)
+}
+
+func stopProcesses(processes []stopProcess, grace time.Duration, force bool) []ProcessStopResult {
+	results := make([]ProcessStopResult, len(processes))
+	for i, p := range processes {
+		results[i] = ProcessStopResult{Name: p.name, PID: p.pid, Result: ProcessStopped}
+		if !pidAlive(p.pid) {
+			results[i].Result = ProcessNotRunning
+		}
+	}
+
+	// The sidecars are shut down before the other processes are signaled, so that the apps serve them while they
+	// drain.
+	if !force {
+		for i, p := range processes {
+			if results[i].Result == ProcessNotRunning || (p.httpPort <= 0 && p.socket == "") {
+				continue
+			}
+			if err := requestSidecarShutdown(p); err != nil {
+				results[i].Fallback = err.Error()
+				continue
+			}
+			if waitForExit(p.pid, time.Now().Add(grace)) {
+				results[i].Result = ProcessShutDown
+			} else {
+				results[i].Fallback = fmt.Sprintf("still running %s after the shutdown request", grace)
+			}
+		}
+	}
+
+	for i, p := range processes {
+		if results[i].Result != ProcessStopped {
+			continue
+		}
+		if err := terminateProcess(p); err != nil {
+			results[i].Err = fmt.Errorf("error terminating %s process %d: %w", p.name, p.pid, err)
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	for i, p := range processes {
+		if results[i].Result != ProcessStopped {
+			continue
+		}
+		if waitForExit(p.pid, deadline) {
+			// Terminating the process may have failed because it was already exiting.
+			results[i].Err = nil
+			continue
+		}
+		results[i].Result = ProcessKilled
+		if err := killProcess(p); err != nil {
+			results[i].Err = fmt.Errorf("error killing %s process %d: %w", p.name, p.pid, err)
+		}
+	}
+	return results
+}
+
+// waitForExit waits for the process with pid to exit until deadline, and returns whether it did.
+func waitForExit(pid int, deadline time.Time) bool {
+	for pidAlive(pid) && time.Now().Before(deadline) {
+		time.Sleep(stopPollInterval)
+	}
+	return !pidAlive(pid)
+}
+
+// requestSidecarShutdown asks the sidecar of p to shut down through its API, with the API token of the environment
+// which the sidecars started from it require. It returns errShutdownUnsupported for the runtime versions without
+// the API.
+func requestSidecarShutdown(p stopProcess) error {
+	url := fmt.Sprintf("http://localhost:%d/v1.0/shutdown", p.httpPort)
+	client := &http.Client{Timeout: shutdownRequestTimeout}
+	if p.socket != "" {
+		url = "http://unix/v1.0/shutdown"
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", p.socket)
+			},
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	if token := os.Getenv("DAPR_API_TOKEN"); token != "" {
+		req.Header.Set("dapr-api-token", token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("the shutdown request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return errShutdownUnsupported
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("the shutdown request returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startProcess starts a process which is reaped once it exits, so that it doesn't linger as a zombie.
+func startProcess(t *testing.T, script string) int {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	require.NoError(t, cmd.Start())
+	go cmd.Wait()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return cmd.Process.Pid
+}
+
+func TestStopInstance(t *testing.T) {
+	daprd := startProcess(t, "sleep 30")
+	app := startProcess(t, `trap "" TERM; while true; do sleep 0.1; done`)
+	// The trap needs to be set up before the signal is sent.
+	time.Sleep(200 * time.Millisecond)
+
+	cliApps := map[int]int{}
+	results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, AppPID: app}, cliApps, time.Second, false)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, ProcessStopResult{Name: "daprd", PID: daprd, Result: ProcessStopped}, results[0])
+	assert.Equal(t, ProcessStopResult{Name: "app", PID: app, Result: ProcessKilled}, results[1])
+	assert.Eventually(t, func() bool { return !pidAlive(app) }, time.Second, 10*time.Millisecond)
+
+	t.Run("processes not running", func(t *testing.T) {
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, CliPID: app}, map[int]int{app: 1}, time.Second, false)
+		assert.Equal(t, []ProcessStopResult{
+			{Name: "daprd", PID: daprd, Result: ProcessNotRunning},
+			{Name: "cli", PID: app, Result: ProcessNotRunning},
+		}, results)
+	})
+
+	t.Run("cli of other apps is left running", func(t *testing.T) {
+		cliApps := map[int]int{42: 2}
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, CliPID: 42}, cliApps, time.Second, false)
+		require.Len(t, results, 1)
+		assert.Equal(t, 1, cliApps[42])
+	})
+}
+
+func TestStopInstanceShutdownAPI(t *testing.T) {
+	// serveShutdown serves the shutdown API on a port, killing daprd with status, and counts the requests.
+	serveShutdown := func(t *testing.T, daprd, status int) (int, *int) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1.0/shutdown", r.URL.Path)
+			assert.Equal(t, "token", r.Header.Get("dapr-api-token"))
+			if status == http.StatusNoContent {
+				process, err := os.FindProcess(daprd)
+				require.NoError(t, err)
+				process.Kill()
+			}
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(ts.Close)
+		return ts.Listener.Addr().(*net.TCPAddr).Port, &requests
+	}
+	t.Setenv("DAPR_API_TOKEN", "token")
+
+	t.Run("sidecar shuts down", func(t *testing.T) {
+		daprd := startProcess(t, "sleep 30")
+		port, requests := serveShutdown(t, daprd, http.StatusNoContent)
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, HTTPPort: port}, map[int]int{}, time.Second, false)
+		assert.Equal(t, []ProcessStopResult{{Name: "daprd", PID: daprd, Result: ProcessShutDown}}, results)
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("runtime without the shutdown api", func(t *testing.T) {
+		daprd := startProcess(t, "sleep 30")
+		port, _ := serveShutdown(t, daprd, http.StatusNotFound)
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, HTTPPort: port}, map[int]int{}, time.Second, false)
+		assert.Equal(t, []ProcessStopResult{{Name: "daprd", PID: daprd, Result: ProcessStopped, Fallback: errShutdownUnsupported.Error()}}, results)
+	})
+
+	t.Run("sidecar still running after the shutdown request", func(t *testing.T) {
+		daprd := startProcess(t, "sleep 30")
+		port, _ := serveShutdown(t, daprd, http.StatusOK)
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, HTTPPort: port}, map[int]int{}, 200*time.Millisecond, false)
+		require.Len(t, results, 1)
+		assert.Equal(t, ProcessStopped, results[0].Result)
+		assert.Equal(t, "still running 200ms after the shutdown request", results[0].Fallback)
+	})
+
+	t.Run("force skips the shutdown api", func(t *testing.T) {
+		daprd := startProcess(t, "sleep 30")
+		port, requests := serveShutdown(t, daprd, http.StatusNoContent)
+		results := StopInstance(ListOutput{AppID: "myapp", DaprdPID: daprd, HTTPPort: port}, map[int]int{}, time.Second, true)
+		assert.Equal(t, []ProcessStopResult{{Name: "daprd", PID: daprd, Result: ProcessStopped}}, results)
+		assert.Zero(t, *requests)
+	})
+}
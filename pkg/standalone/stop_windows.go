@@ -16,26 +16,38 @@ package standalone
 import (
 	"errors"
 	"fmt"
+	"os"
 	"syscall"
 
 	"golang.org/x/sys/windows"
 )
 
-// Stop terminates the application process.
-func Stop(appID string, cliPIDToNoOfApps map[int]int, apps []ListOutput) error {
-	for _, a := range apps {
-		if a.AppID == appID {
-			eventName, _ := syscall.UTF16FromString(fmt.Sprintf("dapr_cli_%v", a.CliPID))
-			eventHandle, err := windows.OpenEvent(windows.EVENT_MODIFY_STATE, false, &eventName[0])
-			if err != nil {
-				return err
-			}
-
-			err = windows.SetEvent(eventHandle)
+// terminateProcess asks the process to terminate. Unlike Linux/Mac there are no signals to send from another process:
+// daprd can only be asked to shut down through its API, which stopProcesses does first, and the CLI process is asked
+// with its named event, after which it stops the app.
+func terminateProcess(p stopProcess) error {
+	switch p.name {
+	case "cli":
+		eventName, _ := syscall.UTF16FromString(fmt.Sprintf("dapr_cli_%v", p.pid))
+		eventHandle, err := windows.OpenEvent(windows.EVENT_MODIFY_STATE, false, &eventName[0])
+		if err != nil {
 			return err
 		}
+		defer windows.CloseHandle(eventHandle)
+		return windows.SetEvent(eventHandle)
+	default:
+		return nil
+	}
+}
+
+// killProcess forcefully terminates the process. The processes started by the app are killed with the job object of
+// the app, once the CLI exits.
+func killProcess(p stopProcess) error {
+	process, err := os.FindProcess(p.pid)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("couldn't find app id %s", appID)
+	return process.Kill()
 }
 
 // StopAppsWithRunFile terminates the daprd and application processes with the given run file.
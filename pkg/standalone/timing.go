@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Kinds of the timings recorded by init.
+const (
+	TimingDownload       = "download"
+	TimingExtract        = "extract"
+	TimingPull           = "pull"
+	TimingContainerStart = "container start"
+	// TimingReady is from the start of a container until it served, as recorded by WaitForEnvironment.
+	TimingReady = "ready"
+)
+
+// SlowInitThreshold is how long init takes before its slowest timings are worth showing.
+const SlowInitThreshold = 2 * time.Minute
+
+// InitTiming is how long a part of a step of init took, such as the download of an archive or the pull of an image.
+type InitTiming struct {
+	Step       string    `json:"step"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Start      time.Time `json:"start"`
+	DurationMs int64     `json:"durationMs"`
+	// Bytes is the size of a download, and BytesPerSecond its average throughput.
+	Bytes          int64 `json:"bytes,omitempty"`
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+}
+
+func (t InitTiming) String() string {
+	s := fmt.Sprintf("%s of %s (%s): %s", t.Kind, t.Name, t.Step, (time.Duration(t.DurationMs) * time.Millisecond).Round(100*time.Millisecond))
+	if t.Bytes > 0 {
+		s += fmt.Sprintf(", %s at %s/s", formatBytes(t.Bytes), formatBytes(t.BytesPerSecond))
+	}
+	return s
+}
+
+// recordDuration records the timing of kind of name, which started at start, for the step running with this info.
+// bytes is the size of a download.
+func (info initInfo) recordDuration(kind, name string, start time.Time, bytes int64) {
+	if info.recordTiming == nil {
+		return
+	}
+	t := InitTiming{Kind: kind, Name: name, Start: start, DurationMs: time.Since(start).Milliseconds(), Bytes: bytes}
+	if bytes > 0 && t.DurationMs > 0 {
+		t.BytesPerSecond = bytes * 1000 / t.DurationMs
+	}
+	info.recordTiming(t)
+}
+
+// SlowestTimings returns the n timings of the report which took the longest, the longest first.
+func (r *InitReport) SlowestTimings(n int) []InitTiming {
+	timings := append([]InitTiming(nil), r.Timings...)
+	sort.SliceStable(timings, func(i, j int) bool { return timings[i].DurationMs > timings[j].DurationMs })
+	if len(timings) > n {
+		timings = timings[:n]
+	}
+	return timings
+}
+
+// recordReadyTimings records, for each container of checks which is ready, how long it took from its start until it
+// served, its readiness having been checked from waitStart.
+func (r *InitReport) recordReadyTimings(checks []ReadinessCheck, waitStart time.Time) {
+	starts := map[string]InitTiming{}
+	for _, t := range r.Timings {
+		if t.Kind == TimingContainerStart {
+			starts[t.Name] = t
+		}
+	}
+	for _, c := range checks {
+		start, ok := starts[c.Name]
+		if !ok || c.Status != ReadinessReady {
+			continue
+		}
+		ready := waitStart.Add(time.Duration(c.WaitedMs) * time.Millisecond)
+		r.Timings = append(r.Timings, InitTiming{Step: start.Step, Kind: TimingReady, Name: c.Name, Start: start.Start, DurationMs: ready.Sub(start.Start).Milliseconds()})
+	}
+}
+
+// formatBytes formats a number of bytes with a binary unit.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
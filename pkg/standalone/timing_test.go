@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitTimingString(t *testing.T) {
+	download := InitTiming{Step: StepDaprdBinary, Kind: TimingDownload, Name: "daprd_linux_amd64.tar.gz", DurationMs: 252_340, Bytes: 31 << 20, BytesPerSecond: 128 << 10}
+	assert.Equal(t, "download of daprd_linux_amd64.tar.gz (daprd binary): 4m12.3s, 31.0 MiB at 128.0 KiB/s", download.String())
+	pull := InitTiming{Step: StepRedis, Kind: TimingPull, Name: "redis:6", DurationMs: 1500}
+	assert.Equal(t, "pull of redis:6 (Redis state store): 1.5s", pull.String())
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2<<30))
+}
+
+func TestRecordDuration(t *testing.T) {
+	var timings []InitTiming
+	info := initInfo{recordTiming: func(timing InitTiming) { timings = append(timings, timing) }}
+	start := time.Now().Add(-2 * time.Second)
+	info.recordDuration(TimingDownload, "daprd_linux_amd64.tar.gz", start, 4<<20)
+	require.Len(t, timings, 1)
+	assert.Equal(t, start, timings[0].Start)
+	assert.InDelta(t, 2000, timings[0].DurationMs, 100)
+	assert.InDelta(t, 2<<20, timings[0].BytesPerSecond, 200<<10)
+
+	// Without a recorder the timings are discarded.
+	initInfo{}.recordDuration(TimingExtract, "daprd_linux_amd64.tar.gz", start, 0)
+}
+
+func TestRunInitStepsRecordsTheStepOfTimings(t *testing.T) {
+	var timings []InitTiming
+	info := initInfo{recordTiming: func(timing InitTiming) { timings = append(timings, timing) }}
+	steps := []initStep{{name: StepRedis, run: func(_ context.Context, info initInfo) error {
+		info.recordDuration(TimingPull, "redis:6", time.Now(), 0)
+		return nil
+	}}}
+	require.NoError(t, runInitSteps(context.Background(), steps, info, discardInitEvents))
+	require.Len(t, timings, 1)
+	assert.Equal(t, StepRedis, timings[0].Step)
+}
+
+func TestSlowestTimings(t *testing.T) {
+	report := &InitReport{Timings: []InitTiming{
+		{Name: "a", DurationMs: 10},
+		{Name: "b", DurationMs: 300},
+		{Name: "c", DurationMs: 20},
+		{Name: "d", DurationMs: 200},
+	}}
+	var names []string
+	for _, timing := range report.SlowestTimings(3) {
+		names = append(names, timing.Name)
+	}
+	assert.Equal(t, []string{"b", "d", "c"}, names)
+	assert.Equal(t, "a", report.Timings[0].Name, "the timings of the report are left in order")
+	assert.Len(t, report.SlowestTimings(10), 4)
+}
+
+func TestRecordReadyTimings(t *testing.T) {
+	start := time.Now().Add(-10 * time.Second)
+	waitStart := start.Add(4 * time.Second)
+	report := &InitReport{Timings: []InitTiming{
+		{Step: StepPlacementService, Kind: TimingContainerStart, Name: DaprPlacementContainerName, Start: start, DurationMs: 1000},
+		{Step: StepRedis, Kind: TimingContainerStart, Name: DaprRedisContainerName, Start: start, DurationMs: 1000},
+	}}
+	report.recordReadyTimings([]ReadinessCheck{
+		{Name: daprRuntimeFilePrefix, Status: ReadinessReady},
+		{Name: DaprPlacementContainerName, Status: ReadinessReady, WaitedMs: 2000},
+		{Name: DaprRedisContainerName, Status: ReadinessNotReady, WaitedMs: 5000},
+	}, waitStart)
+	require.Len(t, report.Timings, 3)
+	assert.Equal(t, InitTiming{Step: StepPlacementService, Kind: TimingReady, Name: DaprPlacementContainerName, Start: start, DurationMs: 6000}, report.Timings[2])
+}
@@ -14,80 +14,182 @@ limitations under the License.
 package standalone
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	path_filepath "path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dapr/cli/pkg/print"
 	"github.com/dapr/cli/utils"
 )
 
-func removeContainers(uninstallPlacementContainer, uninstallAll bool, dockerNetwork, runtimeCmd string) []error {
+func removeContainers(uninstallPlacementContainer, uninstallAll bool, dockerNetwork, runtimeCmd string, report *UninstallReport) []error {
 	var containerErrs []error
 
 	if uninstallPlacementContainer {
-		containerErrs = removeDockerContainer(containerErrs, DaprPlacementContainerName, dockerNetwork, runtimeCmd)
+		containerErrs = removeDockerContainer(containerErrs, DaprPlacementContainerName, dockerNetwork, runtimeCmd, report)
 	}
 
 	if uninstallAll {
-		containerErrs = removeDockerContainer(containerErrs, DaprRedisContainerName, dockerNetwork, runtimeCmd)
-		containerErrs = removeDockerContainer(containerErrs, DaprZipkinContainerName, dockerNetwork, runtimeCmd)
+		containerErrs = removeDockerContainer(containerErrs, DaprRedisContainerName, dockerNetwork, runtimeCmd, report)
+		containerErrs = removeDockerContainer(containerErrs, DaprZipkinContainerName, dockerNetwork, runtimeCmd, report)
 	}
 
 	return containerErrs
 }
 
-func removeDockerContainer(containerErrs []error, containerName, network, runtimeCmd string) []error {
-	container := utils.CreateContainerName(containerName, network)
+func removeDockerContainer(containerErrs []error, containerName, network, runtimeCmd string, report *UninstallReport) []error {
+	container := profileContainerName(containerName, network)
 	exists, _ := confirmContainerIsRunningOrExists(container, false, runtimeCmd)
 	if !exists {
 		print.WarningStatusEvent(os.Stdout, "WARNING: %s container does not exist", container)
+		report.NotFound = append(report.NotFound, container)
 		return containerErrs
 	}
 	print.InfoStatusEvent(os.Stdout, "Removing container: %s", container)
-	_, err := utils.RunCmdAndWait(
+	_, err := runCmd(
 		runtimeCmd, "rm",
 		"--force",
+		// The anonymous volumes, such as the data dir of Redis, are removed along with the container.
+		"--volumes",
 		container)
 	if err != nil {
 		containerErrs = append(
 			containerErrs,
 			fmt.Errorf("could not remove %s container: %w", container, err))
+		return containerErrs
 	}
+	report.RemovedContainers = append(report.RemovedContainers, container)
 	return containerErrs
 }
 
-func removeDir(dirPath string) error {
+// recordUninstall updates the install manifest of installDir after an uninstall which kept the install dir: the
+// runtime is no longer installed, and only the containers which weren't removed remain.
+func recordUninstall(installDir string, manifest *InstallManifest, report *UninstallReport) error {
+	removed := map[string]bool{}
+	for _, c := range append(report.RemovedContainers, report.NotFound...) {
+		removed[c] = true
+	}
+	var containers []ManifestContainer
+	for _, c := range manifest.Containers {
+		if !removed[c.Name] {
+			containers = append(containers, c)
+		}
+	}
+	event := InstallEvent{Action: InstallActionUninstall, PreviousVersion: manifest.RuntimeVersion, At: time.Now().UTC()}
+	manifest.RuntimeVersion = ""
+	manifest.RuntimeBinary = ""
+	manifest.RuntimeBinarySHA256 = ""
+	manifest.PlacementImage = ""
+	manifest.PreviousVersion = ""
+	manifest.Containers = containers
+	manifest.Services = nil
+	manifest.InitSystem = ""
+	manifest.History = append(manifest.History, event)
+	return writeInstallManifest(installDir, manifest)
+}
+
+func removeDir(dirPath string, report *UninstallReport) error {
 	_, err := os.Stat(dirPath)
 	if os.IsNotExist(err) {
 		print.WarningStatusEvent(os.Stdout, "WARNING: %s does not exist", dirPath)
+		report.NotFound = append(report.NotFound, dirPath)
 		return nil
 	}
 	print.InfoStatusEvent(os.Stdout, "Removing directory: %s", dirPath)
 	err = os.RemoveAll(dirPath)
+	if err == nil {
+		report.RemovedDirectories = append(report.RemovedDirectories, dirPath)
+	}
 	return err
 }
 
-// Uninstall reverts all changes made by init. Deletes all installed containers, removes default dapr folder,
-// removes the installed binary and unsets env variables.
-func Uninstall(uninstallAll bool, dockerNetwork string, containerRuntime string, inputInstallPath string) error {
+// removeInstallDir removes the install dir, except the install dirs of the other profiles in the one of the default
+// profile.
+func removeInstallDir(installDir string, report *UninstallReport) error {
+	profiles := path_filepath.Join(installDir, profilesDirName)
+	if ActiveProfile() != DefaultProfile {
+		return removeDir(installDir, report)
+	}
+	if entries, err := os.ReadDir(profiles); err != nil || len(entries) == 0 {
+		return removeDir(installDir, report)
+	}
+	entries, err := os.ReadDir(installDir)
+	if err != nil {
+		return err
+	}
+	print.InfoStatusEvent(os.Stdout, "Removing directory: %s, except the profiles in %s", installDir, profiles)
+	for _, e := range entries {
+		if e.Name() == profilesDirName {
+			continue
+		}
+		if err = os.RemoveAll(path_filepath.Join(installDir, e.Name())); err != nil {
+			return err
+		}
+	}
+	report.RemovedDirectories = append(report.RemovedDirectories, installDir)
+	return nil
+}
+
+// Uninstall reverts all changes made by init. Deletes all installed containers and services, removes default dapr
+// folder, removes the installed binary and unsets env variables. If another init or uninstall is running, Uninstall fails
+// unless wait is set, in which case it waits for it to finish. The returned report describes what was removed.
+func Uninstall(uninstallAll bool, dockerNetwork string, containerRuntime string, inputInstallPath string, wait bool) (*UninstallReport, error) {
 	var containerErrs []error
+	report := &UninstallReport{RemovedDirectories: []string{}, RemovedContainers: []string{}}
 	inputInstallPath = strings.TrimSpace(inputInstallPath)
 	installDir, err := GetDaprRuntimePath(inputInstallPath)
 	if err != nil {
-		return err
+		return report, err
+	}
+	// Nothing can race with us if dapr was never installed, so don't create the install dir just to lock it.
+	if _, statErr := os.Stat(installDir); statErr == nil {
+		unlock, lockErr := acquireInstallLock(context.Background(), installDir, wait)
+		if lockErr != nil {
+			return report, lockErr
+		}
+		defer unlock()
 	}
 	daprBinDir := getDaprBinPath(installDir)
 
-	placementFilePath := binaryFilePathWithDir(daprBinDir, placementServiceFilePrefix)
-	_, placementErr := os.Stat(placementFilePath) // check if the placement binary exists.
-	uninstallPlacementContainer := errors.Is(placementErr, fs.ErrNotExist)
-	// Remove .dapr/bin.
-	err = removeDir(daprBinDir)
+	manifest, err := ReadInstallManifest(installDir)
 	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	var uninstallPlacementContainer bool
+	if manifest != nil {
+		uninstallPlacementContainer = !manifest.SlimMode
+		if dockerNetwork == "" {
+			dockerNetwork = manifest.DockerNetwork
+		}
+		if strings.TrimSpace(containerRuntime) == "" {
+			containerRuntime = manifest.ContainerRuntime
+		}
+		// The services run the binaries which are about to be removed.
+		if len(manifest.Services) > 0 {
+			removeServices(manifest, report)
+		}
+	} else {
+		placementFilePath := binaryFilePathWithDir(daprBinDir, placementServiceFilePrefix)
+		_, placementErr := os.Stat(placementFilePath) // check if the placement binary exists.
+		uninstallPlacementContainer = errors.Is(placementErr, fs.ErrNotExist)
+	}
+	// Remove .dapr/bin, unless Homebrew manages the binaries in it.
+	if formula := runtimeHomebrewFormula(installDir); formula != "" {
+		report.HomebrewFormula = formula
+		print.WarningStatusEvent(os.Stdout, "WARNING: the runtime binaries in %s are installed with the Homebrew formula %s, remove them with `brew uninstall %s`", daprBinDir, formula, formula)
+	} else if err = removeDir(daprBinDir, report); err != nil {
 		print.WarningStatusEvent(os.Stdout, "WARNING: could not delete dapr bin dir: %s", daprBinDir)
+		report.Errors = append(report.Errors, fmt.Sprintf("could not delete dapr bin dir %s: %s", daprBinDir, err))
+	}
+	// Remove the runtime versions installed by upgrade, which the bin dir linked to.
+	err = removeDir(path_filepath.Join(installDir, versionsDirName), report)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not delete dapr versions dir: %s", err))
 	}
 
 	containerRuntime = strings.TrimSpace(containerRuntime)
@@ -95,25 +197,32 @@ func Uninstall(uninstallAll bool, dockerNetwork string, containerRuntime string,
 	containerRuntimeAvailable := false
 	containerRuntimeAvailable = utils.IsContainerRuntimeInstalled(containerRuntime)
 	if containerRuntimeAvailable {
-		containerErrs = removeContainers(uninstallPlacementContainer, uninstallAll, dockerNetwork, runtimeCmd)
+		containerErrs = removeContainers(uninstallPlacementContainer, uninstallAll, dockerNetwork, runtimeCmd, report)
+	}
+	if manifest != nil && !uninstallAll {
+		if err = recordUninstall(installDir, manifest, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("could not update install manifest: %s", err))
+		}
 	}
 
 	if uninstallAll {
-		err = removeDir(installDir)
+		err = removeInstallDir(installDir, report)
 		if err != nil {
 			print.WarningStatusEvent(os.Stdout, "WARNING: could not delete dapr dir %s: %s", installDir, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("could not delete dapr dir %s: %s", installDir, err))
 		}
 	}
 
 	err = errors.New("uninstall failed")
 
 	if len(containerErrs) == 0 {
-		return nil
+		return report, nil
 	}
 
 	// TODO move to use errors.Join once we move to go 1.20.
 	for _, e := range containerErrs {
 		err = fmt.Errorf("%w \n %w", err, e)
+		report.Errors = append(report.Errors, e.Error())
 	}
-	return err
+	return report, err
 }
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	path_filepath "path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-version"
+
+	cli_ver "github.com/dapr/cli/pkg/version"
+)
+
+const (
+	// updateCheckFileName is the name of the file caching the result of the last update check, in the dapr install dir.
+	updateCheckFileName = "update-check.json"
+	// updateCheckInterval is how long the result of an update check is cached.
+	updateCheckInterval = 24 * time.Hour
+
+	enableUpdateCheckEnvVar  = "DAPR_UPDATE_CHECK"
+	disableUpdateCheckEnvVar = "DAPR_DISABLE_UPDATE_CHECK"
+)
+
+// fetchLatestVersions returns the latest release versions of the runtime and of the CLI.
+var fetchLatestVersions = func() (string, string, error) {
+	runtimeVersion, err := cli_ver.GetLatestReleaseGithub(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", cli_ver.DaprGitHubOrg, cli_ver.DaprGitHubRepo))
+	if err != nil {
+		return "", "", err
+	}
+	cliVersion, err := cli_ver.GetCLIVersion()
+	if err != nil {
+		return "", "", err
+	}
+	return runtimeVersion, cliVersion, nil
+}
+
+// updateCheckCache is the content of the update check cache file.
+type updateCheckCache struct {
+	CheckedAt      time.Time `json:"checkedAt"`
+	RuntimeVersion string    `json:"runtimeVersion"`
+	CLIVersion     string    `json:"cliVersion"`
+}
+
+// UpdateCheck is a check for new releases of the runtime and of the CLI running in the background, started with
+// StartUpdateCheck.
+type UpdateCheck struct {
+	done   chan struct{}
+	mu     sync.Mutex
+	notice string
+}
+
+// UpdateCheckEnabled reports whether the update check is enabled. It is opt-in, with the DAPR_UPDATE_CHECK
+// environment variable or updateCheck set to true in the CLI config files, and always disabled in CI or by setting
+// the DAPR_DISABLE_UPDATE_CHECK environment variable. daprRuntimePath is based on the --runtime-path command line
+// flag, as for GetDaprRuntimePath.
+func UpdateCheckEnabled(daprRuntimePath string) bool {
+	if os.Getenv("CI") != "" {
+		return false
+	}
+	if v := os.Getenv(disableUpdateCheckEnvVar); v != "" {
+		if disabled, err := strconv.ParseBool(v); err != nil || disabled {
+			return false
+		}
+	}
+	if v := os.Getenv(enableUpdateCheckEnvVar); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		return err == nil && enabled
+	}
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return false
+	}
+	config, err := readCLIConfig(daprDir)
+	if err != nil {
+		return false
+	}
+	return config.UpdateCheck != nil && *config.UpdateCheck
+}
+
+// StartUpdateCheck starts checking for releases newer than the installed runtime and than cliVersion, in the
+// background. The latest versions are fetched at most once per day, the result being cached in the dapr install dir.
+// The notice of the previous result is available while the latest versions are fetched.
+func StartUpdateCheck(daprRuntimePath, cliVersion string) *UpdateCheck {
+	u := &UpdateCheck{done: make(chan struct{})}
+	go func() {
+		defer close(u.done)
+		daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+		if err != nil {
+			return
+		}
+		cache, ok := readUpdateCheckCache(daprDir, time.Now())
+		installed := GetRuntimeInfo(daprRuntimePath)
+		u.setNotice(updateNotice(cache, installed.Version, cliVersion))
+		if ok {
+			return
+		}
+		cache.RuntimeVersion, cache.CLIVersion, err = fetchLatestVersions()
+		if err != nil {
+			return
+		}
+		cache.CheckedAt = time.Now().UTC()
+		// The check is best effort, failing to cache it only means it runs again next time.
+		_ = writeUpdateCheckCache(daprDir, cache)
+		u.setNotice(updateNotice(cache, installed.Version, cliVersion))
+	}()
+	return u
+}
+
+func (u *UpdateCheck) setNotice(notice string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.notice = notice
+}
+
+// Notice returns the notice about the new releases found by the check, waiting up to timeout for the check to
+// complete, or not at all if it is 0. If the check didn't complete in time, it is the notice of the previous result,
+// if any.
+func (u *UpdateCheck) Notice(timeout time.Duration) string {
+	if u == nil {
+		return ""
+	}
+	if timeout > 0 {
+		select {
+		case <-u.done:
+		case <-time.After(timeout):
+		}
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.notice
+}
+
+// updateNotice returns the single line notice about the latest versions newer than the installed runtime and CLI.
+func updateNotice(latest updateCheckCache, runtimeVersion, cliVersion string) string {
+	var updates []string
+	if isNewerVersion(latest.RuntimeVersion, runtimeVersion) {
+		updates = append(updates, fmt.Sprintf("runtime %s (installed %s): run `dapr upgrade`", latest.RuntimeVersion, runtimeVersion))
+	}
+	if isNewerVersion(latest.CLIVersion, cliVersion) {
+		updates = append(updates, fmt.Sprintf("CLI %s (installed %s): see https://docs.dapr.io/getting-started/install-dapr-cli/", latest.CLIVersion, cliVersion))
+	}
+	if len(updates) == 0 {
+		return ""
+	}
+	return "A new Dapr release is available, " + strings.Join(updates, "; ")
+}
+
+// isNewerVersion reports whether latest is a newer version than installed. Versions which don't parse, such as edge
+// builds, are never outdated.
+func isNewerVersion(latest, installed string) bool {
+	l, err := version.NewVersion(latest)
+	if err != nil {
+		return false
+	}
+	i, err := version.NewVersion(strings.TrimSpace(installed))
+	if err != nil {
+		return false
+	}
+	return l.GreaterThan(i)
+}
+
+// readUpdateCheckCache reads the update check cache of daprDir, and reports whether it is still valid at now.
+func readUpdateCheckCache(daprDir string, now time.Time) (updateCheckCache, bool) {
+	var cache updateCheckCache
+	b, err := os.ReadFile(path_filepath.Join(daprDir, updateCheckFileName))
+	if err != nil {
+		return cache, false
+	}
+	if err = json.Unmarshal(b, &cache); err != nil {
+		return updateCheckCache{}, false
+	}
+	return cache, now.Sub(cache.CheckedAt) < updateCheckInterval && !cache.CheckedAt.After(now)
+}
+
+func writeUpdateCheckCache(daprDir string, cache updateCheckCache) error {
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(daprDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path_filepath.Join(daprDir, updateCheckFileName), b, 0o644)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateNotice(t *testing.T) {
+	latest := updateCheckCache{RuntimeVersion: "1.12.0", CLIVersion: "1.12.0"}
+
+	assert.Equal(t, "", updateNotice(latest, "1.12.0", "1.12.0"))
+	assert.Equal(t, "A new Dapr release is available, runtime 1.12.0 (installed 1.11.2): run `dapr upgrade`", updateNotice(latest, "1.11.2", "1.12.0"))
+	assert.Equal(t,
+		"A new Dapr release is available, runtime 1.12.0 (installed 1.11.0): run `dapr upgrade`; CLI 1.12.0 (installed 1.11.0): see https://docs.dapr.io/getting-started/install-dapr-cli/",
+		updateNotice(latest, "1.11.0", "1.11.0"))
+	// Edge builds of the CLI and runtimes which aren't installed are not reported as outdated.
+	assert.Equal(t, "", updateNotice(latest, "", "edge"))
+}
+
+func TestUpdateCheckCache(t *testing.T) {
+	daprDir := t.TempDir()
+	now := time.Now()
+	_, ok := readUpdateCheckCache(daprDir, now)
+	assert.False(t, ok)
+
+	require.NoError(t, writeUpdateCheckCache(daprDir, updateCheckCache{CheckedAt: now.Add(-time.Hour), RuntimeVersion: "1.12.0"}))
+	cache, ok := readUpdateCheckCache(daprDir, now)
+	assert.True(t, ok)
+	assert.Equal(t, "1.12.0", cache.RuntimeVersion)
+
+	_, ok = readUpdateCheckCache(daprDir, now.Add(updateCheckInterval))
+	assert.False(t, ok)
+}
+
+func TestStartUpdateCheck(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	origFetch := fetchLatestVersions
+	t.Cleanup(func() { fetchLatestVersions = origFetch })
+	fetches := 0
+	fetchLatestVersions = func() (string, string, error) {
+		fetches++
+		return "1.12.0", "1.12.0", nil
+	}
+
+	notice := StartUpdateCheck(runtimePath, "1.11.0").Notice(5 * time.Second)
+	assert.Contains(t, notice, "CLI 1.12.0 (installed 1.11.0)")
+	assert.NotContains(t, notice, "runtime")
+	_, err := os.Stat(filepath.Join(daprDir, updateCheckFileName))
+	require.NoError(t, err)
+
+	// The cached result is used for the next day.
+	StartUpdateCheck(runtimePath, "1.11.0").Notice(5 * time.Second)
+	assert.Equal(t, 1, fetches)
+
+	var u *UpdateCheck
+	assert.Equal(t, "", u.Notice(time.Second))
+
+	// Without waiting, the notice is the one of the previous result while the latest versions are fetched.
+	require.NoError(t, writeUpdateCheckCache(daprDir, updateCheckCache{CheckedAt: time.Now().Add(-2 * updateCheckInterval), CLIVersion: "1.12.0"}))
+	fetched := make(chan struct{})
+	fetchLatestVersions = func() (string, string, error) {
+		<-fetched
+		return "1.13.0", "1.13.0", nil
+	}
+	check := StartUpdateCheck(runtimePath, "1.11.0")
+	assert.Eventually(t, func() bool { return check.Notice(0) != "" }, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, check.Notice(0), "CLI 1.12.0")
+	close(fetched)
+	assert.Contains(t, check.Notice(5*time.Second), "CLI 1.13.0")
+}
+
+func TestUpdateCheckEnabled(t *testing.T) {
+	t.Setenv("CI", "")
+	t.Setenv(disableUpdateCheckEnvVar, "")
+	t.Setenv(enableUpdateCheckEnvVar, "")
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	// The check is opt-in.
+	assert.False(t, UpdateCheckEnabled(runtimePath))
+
+	t.Setenv(enableUpdateCheckEnvVar, "true")
+	assert.True(t, UpdateCheckEnabled(runtimePath))
+
+	t.Setenv(disableUpdateCheckEnvVar, "true")
+	assert.False(t, UpdateCheckEnabled(runtimePath))
+	t.Setenv(disableUpdateCheckEnvVar, "")
+
+	t.Setenv("CI", "true")
+	assert.False(t, UpdateCheckEnabled(runtimePath))
+	t.Setenv("CI", "")
+	t.Setenv(enableUpdateCheckEnvVar, "")
+
+	require.NoError(t, os.MkdirAll(daprDir, 0o755))
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"updateCheck": false}`), 0o644))
+	assert.False(t, UpdateCheckEnabled(runtimePath))
+	require.NoError(t, os.WriteFile(GetCLIConfigPath(daprDir), []byte(`{"updateCheck": true}`), 0o644))
+	assert.True(t, UpdateCheckEnabled(runtimePath))
+}
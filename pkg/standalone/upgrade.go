@@ -0,0 +1,606 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	path_filepath "path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dapr/cli/pkg/print"
+	cli_ver "github.com/dapr/cli/pkg/version"
+	"github.com/dapr/cli/utils"
+)
+
+// versionsDirName is the directory of the dapr install dir where upgrade installs each runtime version, in a
+// subdirectory named after it. The binaries of the bin directory link to those of the active version.
+const versionsDirName = "versions"
+
+var (
+	// ErrVersionAlreadyActive is returned by Upgrade when the target version is already the active one.
+	ErrVersionAlreadyActive = errors.New("is already the active runtime version")
+	// ErrVersionPruned is returned by Upgrade when the version to roll back to was removed from the versions
+	// directory, and UpgradeOptions.Redownload isn't set.
+	ErrVersionPruned = errors.New("is no longer installed")
+	// ErrIncompatiblePlacementData is returned by Upgrade when switching back to a version which can't read the data
+	// of the placement service of the active version, and UpgradeOptions.Force isn't set.
+	ErrIncompatiblePlacementData = errors.New("can't read the placement data of")
+)
+
+// placementDataVersions are the runtime versions whose placement service writes its data in a format the earlier
+// versions can't read, sorted.
+var placementDataVersions = []string{"1.13.0"}
+
+// UpgradeOptions are the options of Upgrade.
+type UpgradeOptions struct {
+	// RuntimeVersion is the version to upgrade or downgrade to, or latest.
+	RuntimeVersion string
+	// Rollback switches back to the version active before the last upgrade instead.
+	Rollback bool
+	// Downgrade only allows switching to a version older than the active one.
+	Downgrade bool
+	// Redownload downloads the version to roll back to again if it was removed from the versions directory,
+	// verifying the archives against the checksums recorded in the install manifest or published with the release.
+	Redownload bool
+	// Force reinstalls the target version even if it is already active or installed, and switches back to versions
+	// which can't read the placement data of the active one.
+	Force            bool
+	DockerNetwork    string
+	ImageRegistryURL string
+	ContainerRuntime string
+	ImageVariant     string
+	// DaprInstallPath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+	DaprInstallPath string
+	// Wait waits for another init, uninstall or upgrade to finish instead of failing.
+	Wait bool
+	// Signature tells how the downloaded archives are verified against the signed checksums of their releases.
+	Signature SignatureOptions
+}
+
+// UpgradeReport describes the runtime environment before and after an upgrade.
+type UpgradeReport struct {
+	PreviousVersion        string `json:"previousVersion"`
+	RuntimeVersion         string `json:"runtimeVersion"`
+	RuntimeBinary          string `json:"runtimeBinary"`
+	PreviousPlacementImage string `json:"previousPlacementImage,omitempty"`
+	PlacementImage         string `json:"placementImage,omitempty"`
+	SlimMode               bool   `json:"slimMode"`
+	// Digests are the changes of the image digests recorded for the containers.
+	Digests []ImageDigestChange `json:"digests,omitempty"`
+}
+
+// Upgrade switches the runtime environment of the dapr install dir to another runtime version: it installs the
+// runtime binary of the version, verified against its checksum and by running it, in its directory of the versions
+// directory, recreates the placement container with the image of the version, on the same port, and points the
+// binaries of the bin directory to the new version. The previous version is kept, so that it can be switched back to
+// with opts.Rollback, and the older ones are removed. The switch is recorded in the history of the install manifest.
+// In slim mode, the placement binary is installed and switched along with the runtime. The image digest of the
+// placement container is recorded again, and the ones of the other containers too if opts.Force is set.
+func Upgrade(ctx context.Context, opts UpgradeOptions) (*UpgradeReport, error) {
+	installDir, err := GetDaprRuntimePath(strings.TrimSpace(opts.DaprInstallPath))
+	if err != nil {
+		return nil, err
+	}
+	if err = useDownloadURL(opts.DaprInstallPath); err != nil {
+		return nil, err
+	}
+	signatures, err := newReleaseVerifier(opts.Signature)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := acquireInstallLock(ctx, installDir, opts.Wait)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if formula := runtimeHomebrewFormula(installDir); formula != "" {
+		return nil, homebrewManagedError(installDir, formula, "upgrade")
+	}
+
+	manifest, err := ReadInstallManifest(installDir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		// The placement container is upgraded where init set it up.
+		if opts.DockerNetwork == "" {
+			opts.DockerNetwork = manifest.DockerNetwork
+		}
+		if strings.TrimSpace(opts.ContainerRuntime) == "" {
+			opts.ContainerRuntime = manifest.ContainerRuntime
+		}
+	}
+	runtimeCmd := utils.GetContainerRuntimeCmd(strings.TrimSpace(opts.ContainerRuntime))
+	placementContainerName := profileContainerName(DaprPlacementContainerName, opts.DockerNetwork)
+	if manifest == nil {
+		// The install predates the manifest, describe it from the installed binary and containers.
+		info := GetRuntimeInfo(opts.DaprInstallPath)
+		if info.Status != RuntimeInstalled {
+			return nil, fmt.Errorf("no runtime installed in %s, run dapr init first", installDir)
+		}
+		manifest = &InstallManifest{RuntimeVersion: info.Version, RuntimeBinary: info.Binary, SlimMode: true, DockerNetwork: opts.DockerNetwork, ContainerRuntime: runtimeCmd}
+		if exists, _ := confirmContainerIsRunningOrExists(placementContainerName, false, runtimeCmd); exists {
+			manifest.PlacementImage, _ = containerImage(placementContainerName, runtimeCmd)
+			manifest.SlimMode = manifest.PlacementImage == ""
+		}
+	} else if manifest.RuntimeVersion == "" {
+		return nil, fmt.Errorf("no runtime installed in %s, run dapr init first", installDir)
+	}
+
+	report := &UpgradeReport{
+		PreviousVersion:        manifest.RuntimeVersion,
+		PreviousPlacementImage: manifest.PlacementImage,
+		SlimMode:               manifest.SlimMode,
+	}
+	target, err := resolveUpgradeTarget(manifest, opts)
+	if err != nil {
+		return report, err
+	}
+	report.RuntimeVersion = target
+
+	binaries := []string{daprRuntimeFilePrefix}
+	if report.SlimMode {
+		binaries = append(binaries, placementServiceFilePrefix)
+	}
+	binDir := getDaprBinPath(installDir)
+	if err = preserveActiveVersion(installDir, manifest.RuntimeVersion, binaries); err != nil {
+		return report, err
+	}
+	versionDir := getVersionDirPath(installDir, target)
+	checksums, err := installRuntimeVersion(ctx, versionDir, target, binaries, runtimeDownload{
+		existingOnly:    opts.Rollback && !opts.Redownload,
+		force:           opts.Force,
+		checksums:       manifest.checksums(target),
+		requireChecksum: opts.Rollback,
+		signatures:      signatures,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if !report.SlimMode {
+		report.PlacementImage, err = upgradePlacementContainer(ctx, placementContainerName, runtimeCmd, manifest.PlacementImage, initInfo{
+			runtimeVersion:   target,
+			dockerNetwork:    opts.DockerNetwork,
+			imageRegistryURL: opts.ImageRegistryURL,
+			containerRuntime: opts.ContainerRuntime,
+			imageVariant:     opts.ImageVariant,
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	print.InfoStatusEvent(os.Stdout, "Switching the active runtime version to %s", target)
+	if err = activateVersion(binDir, versionDir, binaries); err != nil {
+		return report, err
+	}
+	report.RuntimeBinary = binaryFilePathWithDir(binDir, daprRuntimeFilePrefix)
+
+	previous := manifest.RuntimeVersion
+	if previous == target {
+		previous = manifest.PreviousVersion
+	}
+	event := InstallEvent{
+		Action:          InstallActionUpgrade,
+		RuntimeVersion:  target,
+		PreviousVersion: manifest.RuntimeVersion,
+		Checksums:       checksums,
+		At:              time.Now().UTC(),
+	}
+	switch {
+	case opts.Rollback:
+		event.Action = InstallActionRollback
+	case isNewerVersion(manifest.RuntimeVersion, target):
+		event.Action = InstallActionDowngrade
+	}
+	manifest.RuntimeVersion = target
+	manifest.RuntimeBinary = report.RuntimeBinary
+	manifest.RuntimeBinarySHA256, _ = fileSHA256(report.RuntimeBinary)
+	manifest.Platform = hostPlatform()
+	manifest.PlacementImage = report.PlacementImage
+	manifest.PreviousVersion = previous
+	if c := manifest.container(DaprPlacementContainerName); c != nil {
+		c.Image = report.PlacementImage
+	}
+	if !report.SlimMode {
+		// The digests of the other containers are only re-pinned with force, to accept the images they run now.
+		report.Digests = manifest.repinContainers(runtimeCmd, opts.Force)
+	}
+	if manifest.InstalledAt.IsZero() {
+		manifest.InstalledAt = event.At
+	}
+	manifest.History = append(manifest.History, event)
+	if err = writeInstallManifest(installDir, manifest); err != nil {
+		return report, err
+	}
+	if err = pruneVersions(installDir, target, previous); err != nil {
+		print.WarningStatusEvent(os.Stdout, "could not remove old runtime versions: %s", err)
+	}
+	return report, nil
+}
+
+// resolveUpgradeTarget returns the version an upgrade of the install described by manifest switches to.
+func resolveUpgradeTarget(manifest *InstallManifest, opts UpgradeOptions) (string, error) {
+	target := strings.TrimPrefix(strings.TrimSpace(opts.RuntimeVersion), "v")
+	if opts.Rollback {
+		if manifest.PreviousVersion == "" {
+			return "", errors.New("there is no previous runtime version to roll back to")
+		}
+		return manifest.PreviousVersion, checkPlacementData(manifest.RuntimeVersion, manifest.PreviousVersion, opts.Force)
+	}
+	if target == "" || target == latestVersion {
+		var err error
+		target, err = cli_ver.GetDaprVersion()
+		if err != nil {
+			return "", fmt.Errorf("cannot get the latest release version: '%w'. Try specifying --runtime-version=<desired_version>", err)
+		}
+	}
+	if target == manifest.RuntimeVersion && !opts.Force {
+		return "", fmt.Errorf("runtime %s %w, use --force to reinstall it", target, ErrVersionAlreadyActive)
+	}
+	if opts.Downgrade && !isNewerVersion(manifest.RuntimeVersion, target) && target != manifest.RuntimeVersion {
+		return "", fmt.Errorf("runtime %s is not older than the active version %s, use dapr upgrade instead", target, manifest.RuntimeVersion)
+	}
+	return target, checkPlacementData(manifest.RuntimeVersion, target, opts.Force)
+}
+
+// checkPlacementData returns an error if switching from the runtime version from back to the version to loses the
+// data of the placement service, which to can't read, unless force is set.
+func checkPlacementData(from, to string, force bool) error {
+	if force || !isNewerVersion(from, to) {
+		return nil
+	}
+	for _, v := range placementDataVersions {
+		if isNewerVersion(v, to) && !isNewerVersion(v, from) {
+			return fmt.Errorf("runtime %s %w %s, which changed with %s: use --force to switch anyway and lose the actor placement data", to, ErrIncompatiblePlacementData, from, v)
+		}
+	}
+	return nil
+}
+
+func getVersionDirPath(daprDir, version string) string {
+	return path_filepath.Join(daprDir, versionsDirName, version)
+}
+
+// preserveActiveVersion copies the binaries installed by init in the bin directory to the directory of version, so
+// that it can be switched back to. Binaries which already link to a version are left as they are.
+func preserveActiveVersion(daprDir, version string, binaries []string) error {
+	versionDir := getVersionDirPath(daprDir, version)
+	if _, err := os.Stat(versionDir); err == nil || version == "" {
+		return nil
+	}
+	for _, binary := range binaries {
+		src := binaryFilePathWithDir(getDaprBinPath(daprDir), binary)
+		fi, err := os.Lstat(src)
+		if err != nil || fi.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		if err = copyFile(src, binaryFilePathWithDir(versionDir, binary)); err != nil {
+			return fmt.Errorf("error keeping %s %s: %w", binary, version, err)
+		}
+	}
+	return nil
+}
+
+// runtimeDownload tells installRuntimeVersion when and how to download a runtime version.
+type runtimeDownload struct {
+	// existingOnly fails instead of downloading binaries which aren't installed.
+	existingOnly bool
+	// force downloads the binaries even if they are installed.
+	force bool
+	// checksums are the checksums of the archives recorded when the version was last downloaded, by binary name.
+	// Otherwise, the archives are checked against the checksums published with the release.
+	checksums map[string]string
+	// requireChecksum fails if there is no checksum to check an archive against.
+	requireChecksum bool
+	// signatures verifies the archives against the signed checksums of the release, nil if it is skipped.
+	signatures *releaseVerifier
+}
+
+// installRuntimeVersion downloads the binaries of version to versionDir, unless they are installed already and
+// force isn't set, and returns the checksums of the downloaded archives.
+func installRuntimeVersion(ctx context.Context, versionDir, version string, binaries []string, download runtimeDownload) (map[string]string, error) {
+	daprdPath := binaryFilePathWithDir(versionDir, daprRuntimeFilePrefix)
+	if _, err := os.Stat(daprdPath); err == nil && (download.existingOnly || !download.force) {
+		print.InfoStatusEvent(os.Stdout, "Runtime %s is already installed in %s", version, versionDir)
+		return nil, nil
+	} else if download.existingOnly {
+		return nil, fmt.Errorf("runtime %s %w in %s", version, ErrVersionPruned, versionDir)
+	}
+
+	// Extracting over the files of a previous download would leave them truncated.
+	if err := os.RemoveAll(versionDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string, len(binaries))
+	for _, binary := range binaries {
+		print.InfoStatusEvent(os.Stdout, "Downloading %s %s", binary, version)
+		archive, err := downloadBinary(ctx, versionDir, version, binary, cli_ver.DaprGitHubRepo, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading %s binary: %w", binary, err)
+		}
+		verified, err := verifyDownloadSignature(ctx, download.signatures, version, binary, cli_ver.DaprGitHubRepo, archive)
+		if err != nil {
+			return nil, err
+		}
+		expected := download.checksums[binary]
+		if expected == "" && !verified {
+			if expected, err = fetchPublishedChecksum(ctx, versionDir, version, binary, cli_ver.DaprGitHubRepo); err != nil {
+				if download.requireChecksum {
+					return nil, fmt.Errorf("no checksum to verify the %s archive against: %w", binary, err)
+				}
+				print.WarningStatusEvent(os.Stdout, "Could not get the published checksum of the %s archive, it is only verified by running it: %s", binary, err)
+			}
+		}
+		if checksums[binary], err = verifyArchiveChecksum(archive, binary, expected); err != nil {
+			return nil, err
+		}
+		extracted, err := extractFile(archive.path, versionDir, binary)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkBinaryRunnable(extracted); err != nil {
+			return nil, incompatibleLibcError(err, binary, version)
+		}
+		if err = os.Remove(archive.path); err != nil {
+			return nil, fmt.Errorf("failed to remove archive: %w", err)
+		}
+		if err = makeExecutable(extracted); err != nil {
+			return nil, fmt.Errorf("error making %s binary executable: %w", binary, err)
+		}
+	}
+
+	installed, err := runtimeBinaryVersion(daprdPath)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying the downloaded runtime %s: %w", daprdPath, err)
+	}
+	if installed != version {
+		return nil, fmt.Errorf("the downloaded runtime %s reports version %s instead of %s", daprdPath, installed, version)
+	}
+	return checksums, nil
+}
+
+// verifyDownloadSignature checks the downloaded archive of binary against the signed checksums of the release of
+// version of githubRepo with signatures, as init does, and reports whether it was verified, or skipped.
+func verifyDownloadSignature(ctx context.Context, signatures *releaseVerifier, version, binary, githubRepo string, archive downloadedFile) (bool, error) {
+	var verified bool
+	info := initInfo{signatures: signatures, recordSignature: func(result SignatureReport) {
+		verified = result.Status == SignatureVerified
+	}}
+	if err := verifyReleaseSignature(ctx, info, version, binary, githubRepo, archive); err != nil {
+		return false, err
+	}
+	return verified, nil
+}
+
+// fetchPublishedChecksum returns the checksum of the archive of binary published with the release of version of
+// githubRepo, in a .sha256 file next to the archive, downloaded to dir.
+func fetchPublishedChecksum(ctx context.Context, dir, version, binary, githubRepo string) (string, error) {
+	arch, _, err := resolveArtifactArch(ctx, version, binary, githubRepo)
+	if err != nil {
+		return "", err
+	}
+	checksumFile, err := downloadFile(ctx, dir, binaryDownloadURL(version, binary, githubRepo, arch)+".sha256", nil)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(checksumFile.path)
+	b, err := os.ReadFile(checksumFile.path)
+	if err != nil {
+		return "", err
+	}
+	// The file is in the format of sha256sum, the checksum followed by the name of the archive.
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the checksum file of %s is empty", binary)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// verifyArchiveChecksum checks the checksum of the downloaded archive of binary against expected, if set, and returns
+// it.
+func verifyArchiveChecksum(archive downloadedFile, binary, expected string) (string, error) {
+	if expected != "" && archive.sha256 != expected {
+		os.Remove(archive.path)
+		return "", fmt.Errorf("the checksum of the downloaded %s archive is %s instead of %s", binary, archive.sha256, expected)
+	}
+	return archive.sha256, nil
+}
+
+// upgradePlacementContainer pulls the placement image of the version of info and recreates the placement container
+// with it, published on the same host port as before. If the new container fails to start, the container is
+// recreated with previousImage. It returns the new image.
+func upgradePlacementContainer(ctx context.Context, containerName, runtimeCmd, previousImage string, info initInfo) (string, error) {
+	var err error
+	registryName := defaultImageRegistryName
+	if strings.TrimSpace(info.imageRegistryURL) == "" {
+		registryName, err = utils.GetDefaultRegistry(githubContainerRegistryName, dockerContainerRegistryName)
+		if err != nil {
+			return "", err
+		}
+	}
+	image, err := getPlacementImageName(ctx, daprImageInfo{
+		ghcrImageName:      daprGhcrImageName,
+		dockerHubImageName: daprDockerImageName,
+		imageRegistryURL:   info.imageRegistryURL,
+		imageRegistryName:  registryName,
+	}, info)
+	if err != nil {
+		return "", err
+	}
+	print.InfoStatusEvent(os.Stdout, "Pulling placement image %s", image)
+	if !tryPullImage(ctx, image, info.containerRuntime) {
+		return "", fmt.Errorf("could not pull placement image %s", image)
+	}
+
+	hostPort := defaultPlacementHostPort()
+	if out, inspectErr := runCmd(runtimeCmd, "inspect", "--format", placementHostPortFormat, containerName); inspectErr == nil {
+		if port := parsePlacementHostPort(out); port > 0 {
+			hostPort = port
+		}
+	}
+
+	print.InfoStatusEvent(os.Stdout, "Recreating container %s", containerName)
+	if exists, _ := confirmContainerIsRunningOrExists(containerName, false, runtimeCmd); exists {
+		if _, err = runCmd(runtimeCmd, "rm", "--force", containerName); err != nil {
+			return "", fmt.Errorf("could not remove %s container: %w", containerName, err)
+		}
+	}
+	_, err = runContainerCmd(ctx, runtimeCmd, placementRunArgs(containerName, info.dockerNetwork, hostPort, image)...)
+	if err == nil {
+		return image, nil
+	}
+	err = parseContainerRuntimeError("placement service", err)
+	if previousImage != "" {
+		_, _ = runCmd(runtimeCmd, "rm", "--force", containerName)
+		if _, restoreErr := runContainerCmd(ctx, runtimeCmd, placementRunArgs(containerName, info.dockerNetwork, hostPort, previousImage)...); restoreErr != nil {
+			return "", fmt.Errorf("%w, and restoring it with %s failed: %w", err, previousImage, restoreErr)
+		}
+		return "", fmt.Errorf("%w, it was restored with %s", err, previousImage)
+	}
+	return "", err
+}
+
+// placementHostPortFormat is the inspect format printing the host ports the placement container is published on.
+const placementHostPortFormat = `{{range $p, $b := .HostConfig.PortBindings}}{{if eq $p "50005/tcp"}}{{range $b}}{{.HostPort}} {{end}}{{end}}{{end}}`
+
+// parsePlacementHostPort returns the first host port printed with placementHostPortFormat, 0 if there is none.
+func parsePlacementHostPort(out string) int {
+	for _, field := range strings.Fields(out) {
+		if port, err := strconv.Atoi(field); err == nil && port > 0 {
+			return port
+		}
+	}
+	return 0
+}
+
+// containerImage returns the image containerName was created with.
+func containerImage(containerName, runtimeCmd string) (string, error) {
+	out, err := runCmd(runtimeCmd, "inspect", "--format", "{{.Config.Image}}", containerName)
+	return strings.TrimSpace(out), err
+}
+
+// activateVersion points binaries in binDir to those of versionDir: they are symbolic links, except on Windows
+// where they are copies.
+func activateVersion(binDir, versionDir string, binaries []string) error {
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		return err
+	}
+	for _, binary := range binaries {
+		src := binaryFilePathWithDir(versionDir, binary)
+		dst := binaryFilePathWithDir(binDir, binary)
+		if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error replacing %s: %w", dst, err)
+		}
+		var err error
+		if runtime.GOOS == daprWindowsOS {
+			err = copyFile(src, dst)
+		} else {
+			err = os.Symlink(src, dst)
+		}
+		if err != nil {
+			return fmt.Errorf("error switching %s to %s: %w", dst, src, err)
+		}
+	}
+	return nil
+}
+
+// pruneVersions removes the versions installed in the versions directory, other than keep.
+func pruneVersions(daprDir string, keep ...string) error {
+	dir := path_filepath.Join(daprDir, versionsDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, v := range keep {
+		kept[v] = true
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !kept[entry.Name()] {
+			if err = os.RemoveAll(path_filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyFile copies the file at src to dst, creating the directory of dst and keeping the mode of src.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(path_filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// InstalledRuntimeVersions returns the active runtime version and the versions which can be switched to without
+// downloading them, the previous one kept by upgrade. The active version is empty if the runtime isn't installed.
+// daprRuntimePath is based on the --runtime-path command line flag, as for GetDaprRuntimePath.
+func InstalledRuntimeVersions(daprRuntimePath string) (string, []string, error) {
+	daprDir, err := GetDaprRuntimePath(daprRuntimePath)
+	if err != nil {
+		return "", nil, err
+	}
+	manifest, err := ReadInstallManifest(daprDir)
+	if err != nil {
+		return "", nil, err
+	}
+	active := ""
+	if manifest != nil {
+		active = manifest.RuntimeVersion
+	} else if info := GetRuntimeInfo(daprRuntimePath); info.Status == RuntimeInstalled {
+		active = info.Version
+	}
+
+	entries, err := os.ReadDir(path_filepath.Join(daprDir, versionsDirName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, err
+	}
+	var installed []string
+	for _, entry := range entries {
+		if _, statErr := os.Stat(binaryFilePathWithDir(path_filepath.Join(daprDir, versionsDirName, entry.Name()), daprRuntimeFilePrefix)); entry.IsDir() && statErr == nil {
+			installed = append(installed, entry.Name())
+		}
+	}
+	return active, installed, nil
+}
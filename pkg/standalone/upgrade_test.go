@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveUpgradeTarget(t *testing.T) {
+	manifest := &InstallManifest{RuntimeVersion: "1.11.0", PreviousVersion: "1.10.0"}
+
+	target, err := resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "v1.12.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.12.0", target)
+
+	_, err = resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "1.11.0"})
+	assert.ErrorIs(t, err, ErrVersionAlreadyActive)
+
+	target, err = resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "1.11.0", Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1.11.0", target)
+
+	target, err = resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "1.12.0", Rollback: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1.10.0", target)
+
+	_, err = resolveUpgradeTarget(&InstallManifest{RuntimeVersion: "1.11.0"}, UpgradeOptions{Rollback: true})
+	assert.EqualError(t, err, "there is no previous runtime version to roll back to")
+
+	_, err = resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "1.12.0", Downgrade: true})
+	assert.EqualError(t, err, "runtime 1.12.0 is not older than the active version 1.11.0, use dapr upgrade instead")
+	target, err = resolveUpgradeTarget(manifest, UpgradeOptions{RuntimeVersion: "1.9.0", Downgrade: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1.9.0", target)
+
+	_, err = resolveUpgradeTarget(&InstallManifest{RuntimeVersion: "1.13.1", PreviousVersion: "1.12.4"}, UpgradeOptions{Rollback: true})
+	assert.ErrorIs(t, err, ErrIncompatiblePlacementData)
+}
+
+func TestCheckPlacementData(t *testing.T) {
+	assert.NoError(t, checkPlacementData("1.12.0", "1.13.0", false))
+	assert.NoError(t, checkPlacementData("1.13.2", "1.13.0", false))
+	assert.NoError(t, checkPlacementData("1.12.0", "1.11.0", false))
+	err := checkPlacementData("1.13.0", "1.12.4", false)
+	assert.ErrorIs(t, err, ErrIncompatiblePlacementData)
+	assert.EqualError(t, err, "runtime 1.12.4 can't read the placement data of 1.13.0, which changed with 1.13.0: use --force to switch anyway and lose the actor placement data")
+	assert.NoError(t, checkPlacementData("1.14.0", "1.12.4", true))
+}
+
+func TestVerifyArchiveChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daprd.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte("daprd"), 0o644))
+	archive, err := hashFile(path)
+	require.NoError(t, err)
+	const sum = "44e6542dde90727238e0ef2c926780a565f25d3aab932efe85aaa5cd999d4c16"
+
+	got, err := verifyArchiveChecksum(archive, "daprd", "")
+	require.NoError(t, err)
+	assert.Equal(t, sum, got)
+	_, err = verifyArchiveChecksum(archive, "daprd", sum)
+	require.NoError(t, err)
+
+	_, err = verifyArchiveChecksum(archive, "daprd", "00")
+	assert.EqualError(t, err, "the checksum of the downloaded daprd archive is "+sum+" instead of 00")
+	// The corrupted archive is not left behind.
+	assert.NoFileExists(t, path)
+}
+
+func TestVerifyDownloadSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daprd_linux_amd64.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte("daprd"), 0o644))
+	archive, err := hashFile(path)
+	require.NoError(t, err)
+	pub, priv := testSigningKey(t, "12345678")
+	keyFile := filepath.Join(t.TempDir(), "release.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte(pub), 0o644))
+	manifest := []byte(archive.sha256 + "  daprd_linux_amd64.tar.gz\n")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dapr/dapr/releases/download/v1.11.0/" + releaseChecksumsFileName:
+			w.Write(manifest)
+		case "/dapr/dapr/releases/download/v1.11.0/" + releaseChecksumsFileName + signatureFileSuffix:
+			w.Write(minisign(priv, "12345678", manifest, true))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	downloadURL = ts.URL
+	t.Cleanup(func() { downloadURL = defaultDownloadURL })
+
+	v, err := newReleaseVerifier(SignatureOptions{PublicKeyFile: keyFile})
+	require.NoError(t, err)
+	verified, err := verifyDownloadSignature(context.Background(), v, "1.11.0", daprRuntimeFilePrefix, "dapr", archive)
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	// An upgrade to a release which isn't signed fails as init does.
+	_, err = verifyDownloadSignature(context.Background(), v, "1.12.0", daprRuntimeFilePrefix, "dapr", archive)
+	assert.ErrorIs(t, err, ErrSignatureMissing)
+
+	// Without a key, the archive is only checked against its published checksum.
+	v, err = newReleaseVerifier(SignatureOptions{})
+	require.NoError(t, err)
+	verified, err = verifyDownloadSignature(context.Background(), v, "1.12.0", daprRuntimeFilePrefix, "dapr", archive)
+	require.NoError(t, err)
+	assert.False(t, verified)
+}
+
+func TestInstallManifestChecksums(t *testing.T) {
+	m := &InstallManifest{History: []InstallEvent{
+		{Action: InstallActionInit, RuntimeVersion: "1.10.0", Checksums: map[string]string{"daprd": "aa"}},
+		{Action: InstallActionUpgrade, RuntimeVersion: "1.11.0", Checksums: map[string]string{"daprd": "bb"}},
+		{Action: InstallActionRollback, RuntimeVersion: "1.10.0"},
+	}}
+	assert.Equal(t, map[string]string{"daprd": "aa"}, m.checksums("1.10.0"))
+	assert.Nil(t, m.checksums("1.12.0"))
+}
+
+func TestParsePlacementHostPort(t *testing.T) {
+	assert.Equal(t, 50005, parsePlacementHostPort("50005 \n"))
+	assert.Equal(t, 6050, parsePlacementHostPort("6050 6050 "))
+	assert.Equal(t, 0, parsePlacementHostPort(""))
+	assert.Equal(t, 0, parsePlacementHostPort("<no value>"))
+}
+
+func TestPruneVersions(t *testing.T) {
+	daprDir := t.TempDir()
+	for _, v := range []string{"1.9.0", "1.10.0", "1.11.0"} {
+		require.NoError(t, os.MkdirAll(getVersionDirPath(daprDir, v), 0o755))
+	}
+
+	require.NoError(t, pruneVersions(daprDir, "1.11.0", "1.10.0"))
+	entries, err := os.ReadDir(filepath.Join(daprDir, versionsDirName))
+	require.NoError(t, err)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"1.10.0", "1.11.0"}, names)
+}
+
+func TestUpgradeRollback(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+	binDir := getDaprBinPath(daprDir)
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	// The runtime 1.11.0 was installed by init, 1.10.0 is kept from an earlier upgrade.
+	for dir, v := range map[string]string{binDir: "1.11.0", getVersionDirPath(daprDir, "1.10.0"): "1.10.0"} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		for _, binary := range []string{daprRuntimeFilePrefix, placementServiceFilePrefix} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, binary), []byte("#!/bin/sh\necho "+v+"\n"), 0o755))
+		}
+	}
+	require.NoError(t, writeInstallManifest(daprDir, &InstallManifest{
+		RuntimeVersion:  "1.11.0",
+		RuntimeBinary:   filepath.Join(binDir, daprRuntimeFilePrefix),
+		SlimMode:        true,
+		PreviousVersion: "1.10.0",
+		InstalledAt:     time.Now(),
+	}))
+
+	report, err := Upgrade(context.Background(), UpgradeOptions{Rollback: true, DaprInstallPath: runtimePath})
+	require.NoError(t, err)
+	assert.Equal(t, &UpgradeReport{
+		PreviousVersion: "1.11.0",
+		RuntimeVersion:  "1.10.0",
+		RuntimeBinary:   filepath.Join(binDir, daprRuntimeFilePrefix),
+		SlimMode:        true,
+	}, report)
+
+	for _, binary := range []string{daprRuntimeFilePrefix, placementServiceFilePrefix} {
+		target, err := os.Readlink(filepath.Join(binDir, binary))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(getVersionDirPath(daprDir, "1.10.0"), binary), target)
+		kept, err := os.ReadFile(filepath.Join(getVersionDirPath(daprDir, "1.11.0"), binary))
+		require.NoError(t, err)
+		assert.Contains(t, string(kept), "1.11.0")
+	}
+	info := GetRuntimeInfo(runtimePath)
+	assert.Equal(t, "1.10.0", info.Version)
+
+	manifest, err := ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.10.0", manifest.RuntimeVersion)
+	assert.Equal(t, "1.11.0", manifest.PreviousVersion)
+	require.Len(t, manifest.History, 1)
+	assert.Equal(t, InstallActionRollback, manifest.History[0].Action)
+	assert.Equal(t, "1.10.0", manifest.History[0].RuntimeVersion)
+	assert.Equal(t, "1.11.0", manifest.History[0].PreviousVersion)
+
+	// Rolling back again switches back to the version which was upgraded from.
+	report, err = Upgrade(context.Background(), UpgradeOptions{Rollback: true, DaprInstallPath: runtimePath})
+	require.NoError(t, err)
+	assert.Equal(t, "1.11.0", report.RuntimeVersion)
+	assert.Equal(t, "1.11.0", GetRuntimeInfo(runtimePath).Version)
+
+	// A version removed from the versions directory is only downloaded again on request.
+	require.NoError(t, os.RemoveAll(getVersionDirPath(daprDir, "1.10.0")))
+	_, err = Upgrade(context.Background(), UpgradeOptions{Rollback: true, DaprInstallPath: runtimePath})
+	assert.ErrorIs(t, err, ErrVersionPruned)
+	assert.Equal(t, "1.11.0", GetRuntimeInfo(runtimePath).Version)
+}
+
+func TestInstalledRuntimeVersions(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	runtimePath := t.TempDir()
+	daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+
+	active, installed, err := InstalledRuntimeVersions(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "", active)
+	assert.Empty(t, installed)
+
+	for _, v := range []string{"1.10.0", "1.11.0"} {
+		require.NoError(t, os.MkdirAll(getVersionDirPath(daprDir, v), 0o755))
+		require.NoError(t, os.WriteFile(binaryFilePathWithDir(getVersionDirPath(daprDir, v), daprRuntimeFilePrefix), nil, 0o755))
+	}
+	// An interrupted download doesn't count as installed.
+	require.NoError(t, os.MkdirAll(getVersionDirPath(daprDir, "1.12.0"), 0o755))
+	require.NoError(t, writeInstallManifest(daprDir, &InstallManifest{RuntimeVersion: "1.11.0"}))
+
+	active, installed, err = InstalledRuntimeVersions(runtimePath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.11.0", active)
+	assert.Equal(t, []string{"1.10.0", "1.11.0"}, installed)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// upToDateRequest is what an init asks for, compared by installUpToDate to what is installed.
+type upToDateRequest struct {
+	runtimeVersion   string
+	dashboardVersion string
+	slimMode         bool
+	dockerNetwork    string
+	runtimeCmd       string
+	lock             *Lockfile
+	components       string
+	imageRegistry    string
+	imageVariant     string
+	unhardened       bool
+	ports            InstallPorts
+	initSystem       string
+}
+
+// installUpToDate returns why the install of installDir isn't already the one init would set up for req, or an
+// empty string if it is: the runtime of the version, whose binary is unchanged since it was installed, set up with the
+// same image registry, image variant, ports and init system, and, outside of slim mode, the containers running the
+// images they were created with, pinned to the digests of the lockfile if there is one. It looks at the install dir
+// and the container runtime only, never at the network.
+func installUpToDate(installDir string, req upToDateRequest) string {
+	m, err := ReadInstallManifest(installDir)
+	if err != nil || m == nil {
+		return "no install manifest"
+	}
+	version := strings.TrimPrefix(req.runtimeVersion, "v")
+	switch {
+	case m.RuntimeVersion != version:
+		return fmt.Sprintf("runtime %s is installed", m.RuntimeVersion)
+	case m.SlimMode != req.slimMode:
+		return "installed in another mode"
+	case m.DockerNetwork != req.dockerNetwork:
+		return "installed in another docker network"
+	case req.components != "" && m.componentsProvider() != req.components:
+		return fmt.Sprintf("installed with the %s components provider", m.componentsProvider())
+	case !req.slimMode && m.ContainerRuntime != req.runtimeCmd:
+		return fmt.Sprintf("installed with %s", m.ContainerRuntime)
+	case m.ImageRegistry != req.imageRegistry:
+		return "installed from another image registry"
+	case m.ImageVariant != req.imageVariant:
+		return "installed with another image variant"
+	case m.UnhardenedRedis != req.unhardened:
+		return "installed with another hardening of the Redis container"
+	case m.installPorts() != req.ports:
+		return "installed on other host ports"
+	case manifestInitSystem(m.InitSystem) != manifestInitSystem(req.initSystem):
+		return fmt.Sprintf("installed with the %s init system", manifestInitSystem(m.InitSystem))
+	case m.RuntimeBinarySHA256 == "":
+		return "no checksum of the runtime binary recorded"
+	}
+	if sum, sumErr := fileSHA256(m.RuntimeBinary); sumErr != nil || sum != m.RuntimeBinarySHA256 {
+		return fmt.Sprintf("%s differs from the installed binary", m.RuntimeBinary)
+	}
+	if req.dashboardVersion != latestVersion {
+		lockfile, lockErr := ReadLockfile(GetLockfilePath(installDir))
+		if lockErr != nil || lockfile.DashboardVersion != strings.TrimPrefix(req.dashboardVersion, "v") {
+			return "another dashboard version is installed"
+		}
+	}
+
+	if req.slimMode {
+		placement := binaryFilePathWithDir(getDaprBinPath(installDir), placementServiceFilePrefix)
+		if _, err = os.Stat(placement); err != nil {
+			return fmt.Sprintf("%s is missing", placement)
+		}
+		return ""
+	}
+	if len(m.Containers) == 0 {
+		return "no containers recorded"
+	}
+	for _, c := range m.Containers {
+		if reason := containerUpToDate(c, req.runtimeCmd); reason != "" {
+			return reason
+		}
+	}
+	if req.lock != nil {
+		for _, locked := range req.lock.Containers {
+			if c := m.container(locked.Name); c == nil || c.Digest != locked.Digest {
+				return fmt.Sprintf("%s isn't pinned to the digest of the lockfile", profileContainerName(locked.Name, m.DockerNetwork))
+			}
+		}
+	}
+	return ""
+}
+
+// manifestInitSystem returns initSystem, InitSystemNone if it is empty.
+func manifestInitSystem(initSystem string) string {
+	if initSystem == "" {
+		return InitSystemNone
+	}
+	return initSystem
+}
+
+// containerUpToDate returns why the container c of the install manifest isn't running the image it was created with,
+// or an empty string if it is.
+func containerUpToDate(c ManifestContainer, runtimeCmd string) string {
+	out, err := runCmd(runtimeCmd, "inspect", c.Name)
+	if err != nil {
+		return fmt.Sprintf("%s doesn't exist", c.Name)
+	}
+	details, err := parseContainerInspect([]byte(out))
+	if errors.Is(err, errContainerNotFound) {
+		return fmt.Sprintf("%s doesn't exist", c.Name)
+	}
+	switch {
+	case err != nil:
+		return err.Error()
+	case !details.State.Running:
+		return fmt.Sprintf("%s isn't running", c.Name)
+	case details.Config.Image != c.Image:
+		return fmt.Sprintf("%s runs %s instead of %s", c.Name, details.Config.Image, c.Image)
+	case c.ImageID != "" && details.Image != c.ImageID:
+		return fmt.Sprintf("%s %s", c.Name, c.drift(details.Image))
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallUpToDate(t *testing.T) {
+	installDir := t.TempDir()
+	binDir := getDaprBinPath(installDir)
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	daprd := binaryFilePathWithDir(binDir, daprRuntimeFilePrefix)
+	require.NoError(t, os.WriteFile(daprd, []byte("daprd 1.11.0"), 0o755))
+	sum, err := fileSHA256(daprd)
+	require.NoError(t, err)
+	placement := ManifestContainer{Name: DaprPlacementContainerName, Image: "ghcr.io/dapr/placement:1.11.0", Digest: "sha256:aaa", ImageID: "sha256:111"}
+	require.NoError(t, writeInstallManifest(installDir, &InstallManifest{
+		RuntimeVersion:      "1.11.0",
+		RuntimeBinary:       daprd,
+		RuntimeBinarySHA256: sum,
+		ContainerRuntime:    "docker",
+		Containers:          []ManifestContainer{placement},
+	}))
+
+	inspect := `[{"Image": "sha256:111", "State": {"Running": true}, "Config": {"Image": "ghcr.io/dapr/placement:1.11.0"}}]`
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(name string, args ...string) (string, error) {
+		assert.Equal(t, []string{"inspect", DaprPlacementContainerName}, args)
+		return inspect, nil
+	}
+	req := upToDateRequest{runtimeVersion: "v1.11.0", dashboardVersion: latestVersion, runtimeCmd: "docker", ports: InstallPorts{}.withDefaults()}
+	assert.Empty(t, installUpToDate(installDir, req))
+
+	other := req
+	other.runtimeVersion = "1.12.0"
+	assert.Equal(t, "runtime 1.11.0 is installed", installUpToDate(installDir, other))
+	other = req
+	other.dockerNetwork = "mynet"
+	assert.Equal(t, "installed in another docker network", installUpToDate(installDir, other))
+	other = req
+	other.lock = &Lockfile{Containers: []LockedContainer{{Name: DaprPlacementContainerName, Digest: "sha256:bbb"}}}
+	assert.Contains(t, installUpToDate(installDir, other), "isn't pinned to the digest of the lockfile")
+	other.lock.Containers[0].Digest = "sha256:aaa"
+	assert.Empty(t, installUpToDate(installDir, other))
+	other = req
+	other.dashboardVersion = "0.13.0"
+	assert.Equal(t, "another dashboard version is installed", installUpToDate(installDir, other))
+	other = req
+	other.components = ComponentsProviderInMemory
+	assert.Equal(t, "installed with the redis components provider", installUpToDate(installDir, other))
+	other = req
+	other.imageRegistry = "registry.example.com"
+	assert.Equal(t, "installed from another image registry", installUpToDate(installDir, other))
+	other = req
+	other.imageVariant = "mariner"
+	assert.Equal(t, "installed with another image variant", installUpToDate(installDir, other))
+	other = req
+	other.unhardened = true
+	assert.Equal(t, "installed with another hardening of the Redis container", installUpToDate(installDir, other))
+	other = req
+	other.ports.Redis = 6380
+	assert.Equal(t, "installed on other host ports", installUpToDate(installDir, other))
+	other = req
+	other.initSystem = InitSystemSystemd
+	assert.Equal(t, "installed with the none init system", installUpToDate(installDir, other))
+	other.initSystem = InitSystemNone
+	assert.Empty(t, installUpToDate(installDir, other))
+
+	inspect = `[{"Image": "sha256:222", "State": {"Running": true}, "Config": {"Image": "ghcr.io/dapr/placement:1.11.0"}}]`
+	assert.Contains(t, installUpToDate(installDir, req), "runs the image 222 instead of the recorded")
+	inspect = `[{"Image": "sha256:111", "State": {"Running": false}, "Config": {"Image": "ghcr.io/dapr/placement:1.11.0"}}]`
+	assert.Equal(t, "dapr_placement isn't running", installUpToDate(installDir, req))
+	inspect = `[]`
+	assert.Equal(t, "dapr_placement doesn't exist", installUpToDate(installDir, req))
+
+	require.NoError(t, os.WriteFile(daprd, []byte("daprd 1.11.0, patched"), 0o755))
+	assert.Contains(t, installUpToDate(installDir, req), "differs from the installed binary")
+	assert.Equal(t, "no install manifest", installUpToDate(filepath.Join(installDir, "missing"), req))
+}
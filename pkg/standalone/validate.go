@@ -0,0 +1,294 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	path_filepath "path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const componentKind = "Component"
+
+// yamlErrorRegexp extracts the line and the message from the syntax errors of the YAML decoder.
+var yamlErrorRegexp = regexp.MustCompile(`^yaml: (?:line (\d+): )?(.*)$`)
+
+// ComponentProblem is an issue found in a resource file.
+type ComponentProblem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"msg"`
+}
+
+func (p ComponentProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// ComponentsValidationError is returned when the resources used by a command have problems.
+type ComponentsValidationError struct {
+	Problems []ComponentProblem
+}
+
+func (e *ComponentsValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("found %d problem(s) in the resource files:", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resourceDocument is a YAML document of a resource file.
+type resourceDocument struct {
+	file       string
+	line       int
+	apiVersion string
+	kind       string
+	name       string
+	// componentType is the spec.type of components.
+	componentType string
+	problems      []ComponentProblem
+	// node is the root node of the document.
+	node *yaml.Node
+}
+
+// ValidateComponents checks the structure of the resources in the YAML files of dirs and returns all the problems
+// found, like the runtime does when loading them: every document needs an apiVersion, a kind and a name which is
+// unique for its kind, and components a type and metadata items made of a name and a value or secret reference.
+func ValidateComponents(dirs ...string) ([]ComponentProblem, error) {
+	var problems []ComponentProblem
+	defined := map[string]resourceDocument{}
+	for _, dir := range dirs {
+		files, err := resourceFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			docs, fileProblems := loadResourceFile(file)
+			problems = append(problems, fileProblems...)
+			for _, doc := range docs {
+				problems = append(problems, doc.problems...)
+				if doc.kind == "" || doc.name == "" {
+					continue
+				}
+				key := doc.kind + "/" + doc.name
+				if first, ok := defined[key]; ok {
+					problems = append(problems, ComponentProblem{
+						File:    doc.file,
+						Line:    doc.line,
+						Message: fmt.Sprintf("duplicate %s name %q, also defined at %s:%d", doc.kind, doc.name, first.file, first.line),
+					})
+					continue
+				}
+				defined[key] = doc
+			}
+		}
+	}
+	return problems, nil
+}
+
+// resourceFiles returns the paths of the YAML files in dir, sorted.
+func resourceFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading resources directory %s: %w", dir, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := path_filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, path_filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadResourceFile parses the documents of a resource file. The problems which prevent reading the file, like YAML
+// syntax errors, are returned separately from the problems of each document.
+func loadResourceFile(file string) ([]resourceDocument, []ComponentProblem) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, []ComponentProblem{{File: file, Message: err.Error()}}
+	}
+	defer f.Close()
+
+	var docs []resourceDocument
+	decoder := yaml.NewDecoder(f)
+	for {
+		var node yaml.Node
+		err = decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			problem := ComponentProblem{File: file, Message: err.Error()}
+			if m := yamlErrorRegexp.FindStringSubmatch(err.Error()); m != nil {
+				problem.Line, _ = strconv.Atoi(m[1])
+				problem.Message = m[2]
+			}
+			return docs, []ComponentProblem{problem}
+		}
+		// Skip empty documents, e.g. after a trailing document separator.
+		if len(node.Content) == 0 || node.Content[0].Tag == "!!null" {
+			continue
+		}
+		doc := parseResourceDocument(file, node.Content[0])
+		doc.node = &node
+		docs = append(docs, doc)
+	}
+}
+
+func parseResourceDocument(file string, root *yaml.Node) resourceDocument {
+	doc := resourceDocument{file: file, line: root.Line}
+	problem := func(node *yaml.Node, format string, args ...interface{}) {
+		doc.problems = append(doc.problems, ComponentProblem{File: file, Line: node.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if root.Kind != yaml.MappingNode {
+		problem(root, "document is not a mapping")
+		return doc
+	}
+	doc.apiVersion = requiredString(root, "apiVersion", problem)
+	doc.kind = requiredString(root, "kind", problem)
+
+	metadata := mappingValue(root, "metadata")
+	if metadata == nil || metadata.Kind != yaml.MappingNode {
+		problem(nodeOr(metadata, root), "metadata must be a mapping with a name")
+	} else {
+		doc.name = requiredString(metadata, "name", problem)
+	}
+
+	if doc.kind != componentKind {
+		return doc
+	}
+	spec := mappingValue(root, "spec")
+	if spec == nil || spec.Kind != yaml.MappingNode {
+		problem(nodeOr(spec, root), "spec must be a mapping with a type")
+		return doc
+	}
+	doc.componentType = requiredString(spec, "type", problem)
+
+	items := mappingValue(spec, "metadata")
+	if items == nil {
+		return doc
+	}
+	if items.Kind != yaml.SequenceNode {
+		problem(items, "spec.metadata must be a list of name/value pairs")
+		return doc
+	}
+	for _, item := range items.Content {
+		if item.Kind != yaml.MappingNode {
+			problem(item, "spec.metadata items must be name/value pairs")
+			continue
+		}
+		name := requiredString(item, "name", problem)
+		value, secretRef := mappingValue(item, "value"), mappingValue(item, "secretKeyRef")
+		switch {
+		case value == nil && secretRef == nil:
+			problem(item, "metadata item %q has no value or secretKeyRef", name)
+		case value != nil && secretRef != nil:
+			problem(item, "metadata item %q has both a value and a secretKeyRef", name)
+		}
+	}
+	return doc
+}
+
+// mappingValue returns the value of key in the mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// requiredString returns the value of key in m, reporting a problem if it is missing or not a string.
+func requiredString(m *yaml.Node, key string, problem func(node *yaml.Node, format string, args ...interface{})) string {
+	v := mappingValue(m, key)
+	switch {
+	case v == nil || (v.Kind == yaml.ScalarNode && v.Value == ""):
+		problem(nodeOr(v, m), "missing %s", key)
+	case v.Kind != yaml.ScalarNode:
+		problem(v, "%s must be a string", key)
+	default:
+		return v.Value
+	}
+	return ""
+}
+
+func nodeOr(node, fallback *yaml.Node) *yaml.Node {
+	if node != nil {
+		return node
+	}
+	return fallback
+}
+
+// ComponentInfo describes a component defined in a resources directory.
+type ComponentInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	File string `json:"file"`
+	// Error describes the problems of the component, or of its file if it couldn't be parsed.
+	Error string `json:"error,omitempty"`
+}
+
+// ListComponents returns the components defined in the YAML files of dir. Files which can't be parsed are listed
+// as a component without a name, with the parsing error.
+func ListComponents(dir string) ([]ComponentInfo, error) {
+	files, err := resourceFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var components []ComponentInfo
+	for _, file := range files {
+		docs, fileProblems := loadResourceFile(file)
+		for _, doc := range docs {
+			if doc.kind != componentKind {
+				continue
+			}
+			components = append(components, ComponentInfo{
+				Name:  doc.name,
+				Type:  doc.componentType,
+				File:  file,
+				Error: joinProblems(doc.problems),
+			})
+		}
+		if len(fileProblems) > 0 {
+			components = append(components, ComponentInfo{File: file, Error: joinProblems(fileProblems)})
+		}
+	}
+	return components, nil
+}
+
+func joinProblems(problems []ComponentProblem) string {
+	messages := make([]string, 0, len(problems))
+	for _, p := range problems {
+		messages = append(messages, fmt.Sprintf("line %d: %s", p.Line, p.Message))
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeResourceFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestValidateComponents(t *testing.T) {
+	t.Run("valid resources", func(t *testing.T) {
+		dir := writeResourceFiles(t, map[string]string{
+			"statestore.yaml": `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  version: v1
+  metadata:
+  - name: redisHost
+    value: localhost:6379
+  - name: redisPassword
+    secretKeyRef:
+      name: redis
+      key: password
+`,
+			"resiliency.yml": `apiVersion: dapr.io/v1alpha1
+kind: Resiliency
+metadata:
+  name: statestore
+---
+`,
+			"README.md": "not a resource",
+		})
+		problems, err := ValidateComponents(dir)
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("all problems are reported with their line", func(t *testing.T) {
+		dir := writeResourceFiles(t, map[string]string{
+			"a.yaml": `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  metadata:
+  - name: redisHost
+  - value: localhost
+---
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  metadata: {}
+`,
+			"b.yaml": "kind: [\n",
+		})
+		problems, err := ValidateComponents(dir)
+		require.NoError(t, err)
+
+		a, b := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+		assert.Equal(t, []ComponentProblem{
+			{File: a, Line: 6, Message: "missing type"},
+			{File: a, Line: 7, Message: `metadata item "redisHost" has no value or secretKeyRef`},
+			{File: a, Line: 8, Message: "missing name"},
+			{File: a, Line: 10, Message: "missing apiVersion"},
+			{File: a, Line: 15, Message: "spec.metadata must be a list of name/value pairs"},
+			{File: a, Line: 10, Message: `duplicate Component name "statestore", also defined at ` + a + ":1"},
+			{File: b, Line: 1, Message: "did not find expected node content"},
+		}, problems)
+	})
+
+	t.Run("names are unique across directories", func(t *testing.T) {
+		component := "apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: pubsub\nspec:\n  type: pubsub.redis\n"
+		dir1 := writeResourceFiles(t, map[string]string{"pubsub.yaml": component})
+		dir2 := writeResourceFiles(t, map[string]string{"pubsub.yaml": component})
+		problems, err := ValidateComponents(dir1, dir2)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, filepath.Join(dir2, "pubsub.yaml"), problems[0].File)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := ValidateComponents(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func TestRunConfigValidatesComponents(t *testing.T) {
+	dir := writeResourceFiles(t, map[string]string{
+		"statestore.yaml": "apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: statestore\n",
+	})
+
+	config := &RunConfig{SharedRunConfig: SharedRunConfig{ResourcesPaths: []string{dir}}}
+	err := config.validateResourcesPaths()
+	var validationErr *ComponentsValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Problems, 1)
+	assert.Contains(t, err.Error(), "statestore.yaml:1: spec must be a mapping with a type")
+
+	config.SkipValidation = true
+	assert.NoError(t, config.validateResourcesPaths())
+}
+
+func TestListComponents(t *testing.T) {
+	dir := writeResourceFiles(t, map[string]string{
+		"a.yaml": `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+---
+apiVersion: dapr.io/v1alpha1
+kind: Configuration
+metadata:
+  name: config
+---
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: pubsub
+`,
+		"b.yaml": "kind: [\n",
+	})
+
+	components, err := ListComponents(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []ComponentInfo{
+		{Name: "statestore", Type: "state.redis", File: filepath.Join(dir, "a.yaml")},
+		{Name: "pubsub", File: filepath.Join(dir, "a.yaml"), Error: "line 13: spec must be a mapping with a type"},
+		{File: filepath.Join(dir, "b.yaml"), Error: "line 1: did not find expected node content"},
+	}, components)
+
+	_, err = ListComponents(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
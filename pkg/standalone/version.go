@@ -15,17 +15,102 @@ package standalone
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"strings"
-)
+	"time"
 
-// Values for these are injected by the build.
-var (
-	gitcommit, gitversion string
+	cli_ver "github.com/dapr/cli/pkg/version"
 )
 
 const naStr = "n/a\n"
 
+// runtimeVersionTimeout is how long the runtime binary has to print its version.
+const runtimeVersionTimeout = 5 * time.Second
+
+// Statuses of the runtime binary.
+const (
+	RuntimeInstalled     = "installed"
+	RuntimeNotInstalled  = "not installed"
+	RuntimeNotExecutable = "not executable"
+)
+
+// RuntimeInfo describes the installed runtime binary, as found by GetRuntimeInfo.
+type RuntimeInfo struct {
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+	Binary  string `json:"binary,omitempty"`
+	// PlacementImage is the image of the placement container recorded at init.
+	PlacementImage string `json:"placementImage,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// GetRuntimeInfo locates the runtime binary, in order from the install manifest of the dapr install dir, in its
+// bin directory and in PATH, and runs it to get its version. daprRuntimePath is based on the --runtime-path command
+// line flag, as for GetDaprRuntimePath.
+func GetRuntimeInfo(daprRuntimePath string) RuntimeInfo {
+	info := RuntimeInfo{Status: RuntimeNotInstalled}
+	var candidates []string
+	if daprDir, err := GetDaprRuntimePath(daprRuntimePath); err == nil {
+		manifest, err := ReadInstallManifest(daprDir)
+		if err != nil {
+			info.Error = err.Error()
+		}
+		if manifest != nil {
+			info.PlacementImage = manifest.PlacementImage
+			candidates = append(candidates, manifest.RuntimeBinary)
+		}
+		candidates = append(candidates, binaryFilePathWithDir(getDaprBinPath(daprDir), daprRuntimeFilePrefix))
+	}
+	if path, err := exec.LookPath(daprRuntimeFilePrefix); err == nil {
+		candidates = append(candidates, path)
+	}
+	for _, candidate := range candidates {
+		if fi, err := os.Stat(candidate); candidate != "" && err == nil && !fi.IsDir() {
+			info.Binary = candidate
+			break
+		}
+	}
+	if info.Binary == "" {
+		return info
+	}
+
+	version, err := runtimeBinaryVersion(info.Binary)
+	if err != nil {
+		info.Status = RuntimeNotExecutable
+		info.Error = err.Error()
+		return info
+	}
+	info.Status = RuntimeInstalled
+	info.Version = version
+	return info
+}
+
+// runtimeBinaryVersion runs the runtime binary at path to get its version.
+func runtimeBinaryVersion(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return "", errors.New("timed out printing its version")
+		case errors.As(err, &exitErr):
+			msg := fmt.Sprintf("failed printing its version with %s", exitErr)
+			if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+				msg += ": " + strings.SplitN(stderr, "\n", 2)[0]
+			}
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // GetRuntimeVersion returns the version for the local Dapr runtime.
 func GetRuntimeVersion(inputInstallPath string) (string, error) {
 	daprCMD, err := lookupBinaryFilePath(inputInstallPath, "daprd")
@@ -64,8 +149,8 @@ func GetBuildInfo(inputInstallPath string, version string) (string, error) {
 	strs := []string{
 		"CLI:",
 		"\tVersion: " + version,
-		"\tGit Commit: " + gitcommit,
-		"\tGit Version: " + gitversion,
+		"\tGit Commit: " + cli_ver.CLI.GitCommit,
+		"\tGit Version: " + cli_ver.CLI.GitVersion,
 		"Runtime:",
 	}
 
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRuntimeInfo(t *testing.T) {
+	if runtime.GOOS == daprWindowsOS {
+		t.Skip("the fake runtime binaries are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+
+	t.Run("not installed", func(t *testing.T) {
+		info := GetRuntimeInfo(t.TempDir())
+		assert.Equal(t, RuntimeInfo{Status: RuntimeNotInstalled}, info)
+	})
+
+	t.Run("installed, with the manifest", func(t *testing.T) {
+		runtimePath := t.TempDir()
+		daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+		require.NoError(t, os.MkdirAll(daprDir, 0o755))
+		binary := filepath.Join(t.TempDir(), "daprd")
+		require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho 1.11.0\n"), 0o755))
+		require.NoError(t, writeInstallManifest(daprDir, &InstallManifest{
+			RuntimeVersion: "1.11.0",
+			RuntimeBinary:  binary,
+			PlacementImage: "ghcr.io/dapr/placement:1.11.0",
+			InstalledAt:    time.Now(),
+		}))
+
+		info := GetRuntimeInfo(runtimePath)
+		assert.Equal(t, RuntimeInfo{Status: RuntimeInstalled, Version: "1.11.0", Binary: binary, PlacementImage: "ghcr.io/dapr/placement:1.11.0"}, info)
+	})
+
+	t.Run("installed in the bin directory", func(t *testing.T) {
+		runtimePath := t.TempDir()
+		daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+		binary := filepath.Join(getDaprBinPath(daprDir), "daprd")
+		require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o755))
+		require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho 1.10.0\n"), 0o755))
+
+		info := GetRuntimeInfo(runtimePath)
+		assert.Equal(t, RuntimeInfo{Status: RuntimeInstalled, Version: "1.10.0", Binary: binary}, info)
+	})
+
+	t.Run("not executable", func(t *testing.T) {
+		runtimePath := t.TempDir()
+		daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+		binary := filepath.Join(getDaprBinPath(daprDir), "daprd")
+		require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o755))
+		require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho 1.10.0\n"), 0o644))
+
+		info := GetRuntimeInfo(runtimePath)
+		assert.Equal(t, RuntimeNotExecutable, info.Status)
+		assert.Equal(t, binary, info.Binary)
+		assert.Contains(t, info.Error, "permission denied")
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		runtimePath := t.TempDir()
+		daprDir := filepath.Join(runtimePath, DefaultDaprDirName)
+		binary := filepath.Join(getDaprBinPath(daprDir), "daprd")
+		require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o755))
+		require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho broken >&2\nexit 2\n"), 0o755))
+
+		info := GetRuntimeInfo(runtimePath)
+		assert.Equal(t, RuntimeNotExecutable, info.Status)
+		assert.Equal(t, "failed printing its version with exit status 2: broken", info.Error)
+	})
+}
+
+func TestInstallManifest(t *testing.T) {
+	daprDir := t.TempDir()
+	m, err := ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.Nil(t, m, "there is no manifest before init")
+
+	written := &InstallManifest{RuntimeVersion: "1.11.0", RuntimeBinary: "/opt/dapr/bin/daprd", InstalledAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, writeInstallManifest(daprDir, written))
+	m, err = ReadInstallManifest(daprDir)
+	require.NoError(t, err)
+	assert.Equal(t, written, m)
+
+	require.NoError(t, os.WriteFile(GetInstallManifestPath(daprDir), []byte("{"), 0o600))
+	_, err = ReadInstallManifest(daprDir)
+	assert.ErrorContains(t, err, "error parsing install manifest")
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	path_filepath "path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchIgnores are the patterns of the files and directories which are not watched by default: dependencies,
+// build outputs, version control and the temporary files of editors.
+var DefaultWatchIgnores = []string{
+	".git", "node_modules", "bin", "obj", "target", "__pycache__", DefaultDaprDirName,
+	"*.swp", "*.swx", "*~", ".#*", "4913",
+}
+
+// DefaultWatchDebounce is how long the watcher waits for the changes to settle before reporting them.
+const DefaultWatchDebounce = 300 * time.Millisecond
+
+// WatchOptions are the options of NewWatcher.
+type WatchOptions struct {
+	// Include are the patterns of the files whose changes are reported, all of them if empty.
+	Include []string
+	// Ignore are the patterns of the files and directories which are not watched.
+	Ignore   []string
+	Debounce time.Duration
+}
+
+// Watcher reports the changes of the files in a directory tree. The patterns of the options are matched against the
+// base name and the path relative to the directory, with path_filepath.Match syntax.
+type Watcher struct {
+	dir     string
+	opts    WatchOptions
+	watcher *fsnotify.Watcher
+	changes chan string
+	errors  chan error
+	done    chan struct{}
+}
+
+// NewWatcher starts watching dir and its subdirectories.
+func NewWatcher(dir string, opts WatchOptions) (*Watcher, error) {
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Ignore...) {
+		if _, err := path_filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid watch pattern %q: %w", pattern, err)
+		}
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("error creating file watcher: %w", err)
+	}
+	w := &Watcher{
+		dir:     dir,
+		opts:    opts,
+		watcher: fsWatcher,
+		changes: make(chan string),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	if err = w.addTree(dir); err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Changes returns the channel of the changes: the path, relative to the directory, of the first file changed in a
+// burst of changes.
+func (w *Watcher) Changes() <-chan string {
+	return w.changes
+}
+
+// Errors returns the channel of the errors of the watcher, which keeps watching after them.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// addTree watches the directories of the tree of root which aren't ignored. The directories are watched rather than
+// the files, so that the files replaced by editors with a rename are still watched.
+func (w *Watcher) addTree(root string) error {
+	return path_filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directories removed while walking are skipped.
+			if path != root && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && w.ignored(path) {
+			return path_filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("error watching %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) loop() {
+	var timer *time.Timer
+	var fire <-chan time.Time
+	var first string
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			default:
+			}
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if w.ignored(event.Name) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// The files of a new directory are reported once they change.
+					w.addTree(event.Name)
+					continue
+				}
+			}
+			if !w.included(event.Name) {
+				continue
+			}
+			if timer == nil {
+				first = w.rel(event.Name)
+				timer = time.NewTimer(w.opts.Debounce)
+				fire = timer.C
+			} else {
+				timer.Reset(w.opts.Debounce)
+			}
+		case <-fire:
+			timer, fire = nil, nil
+			select {
+			case w.changes <- first:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) rel(path string) string {
+	if rel, err := path_filepath.Rel(w.dir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// ignored reports whether the path, or one of its parent directories in the tree, matches an ignore pattern.
+func (w *Watcher) ignored(path string) bool {
+	rel := w.rel(path)
+	parts := strings.Split(rel, string(path_filepath.Separator))
+	for i := range parts {
+		if matchAny(w.opts.Ignore, parts[i], path_filepath.Join(parts[:i+1]...)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) included(path string) bool {
+	if len(w.opts.Include) == 0 {
+		return true
+	}
+	rel := w.rel(path)
+	return matchAny(w.opts.Include, path_filepath.Base(rel), rel)
+}
+
+func matchAny(patterns []string, names ...string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := path_filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "lib"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "app.js"), []byte("v1"), 0o600))
+
+	w, err := NewWatcher(dir, WatchOptions{Include: []string{"*.js"}, Ignore: DefaultWatchIgnores, Debounce: 50 * time.Millisecond})
+	require.NoError(t, err)
+	defer w.Close()
+
+	expectChange := func(t *testing.T, expected string) {
+		t.Helper()
+		select {
+		case changed := <-w.Changes():
+			assert.Equal(t, expected, changed)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no change reported, expected %s", expected)
+		}
+	}
+	expectNoChange := func(t *testing.T) {
+		t.Helper()
+		select {
+		case changed := <-w.Changes():
+			t.Fatalf("unexpected change of %s", changed)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	t.Run("burst of changes", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "app.js"), []byte{byte(i)}, 0o600))
+		}
+		expectChange(t, filepath.Join("src", "app.js"))
+		expectNoChange(t)
+	})
+
+	t.Run("ignored and excluded files", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "lib", "index.js"), []byte("x"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "notes.txt"), []byte("x"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "app.js.swp"), []byte("x"), 0o600))
+		expectNoChange(t)
+	})
+
+	t.Run("atomic replace", func(t *testing.T) {
+		tmp := filepath.Join(dir, "src", "app.js.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte("v2"), 0o600))
+		require.NoError(t, os.Rename(tmp, filepath.Join(dir, "src", "app.js")))
+		expectChange(t, filepath.Join("src", "app.js"))
+	})
+
+	t.Run("new directory", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0o755))
+		time.Sleep(100 * time.Millisecond)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "util.js"), []byte("x"), 0o600))
+		expectChange(t, filepath.Join("lib", "util.js"))
+	})
+
+	_, err = NewWatcher(dir, WatchOptions{Include: []string{"["}})
+	assert.ErrorContains(t, err, "invalid watch pattern")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import "strings"
+
+const (
+	// windowsMaxPath is MAX_PATH, the length of the longest path the Windows APIs accept without the extended-length
+	// prefix. Directories are limited to 12 characters less, to leave room for an 8.3 file name.
+	windowsMaxPath    = 260
+	windowsMaxDirPath = windowsMaxPath - 12
+
+	extendedLengthPrefix    = `\\?\`
+	extendedLengthUNCPrefix = `\\?\UNC\`
+)
+
+// extendedLengthPath returns the absolute Windows path abs with the extended-length prefix if it is too long for the
+// Windows APIs without it, and abs itself otherwise. The prefix turns off the path normalization of Windows, so abs
+// must be clean and use backslashes.
+func extendedLengthPath(abs string) string {
+	switch {
+	case len(abs) < windowsMaxDirPath, strings.HasPrefix(abs, extendedLengthPrefix):
+		return abs
+	case strings.HasPrefix(abs, `\\`):
+		return extendedLengthUNCPrefix + abs[2:]
+	case len(abs) >= 3 && abs[1] == ':' && abs[2] == '\\':
+		return extendedLengthPrefix + abs
+	}
+	return abs
+}
+
+// appendPathListEntry appends dir to list, a Windows PATH, unless it's already one of its entries, and reports whether
+// it did. Windows paths are case-insensitive and PATH entries can be quoted or end with a backslash.
+func appendPathListEntry(list, dir string) (string, bool) {
+	normalize := func(entry string) string {
+		entry = strings.Trim(strings.TrimSpace(entry), `"`)
+		return strings.ToLower(strings.TrimRight(entry, `\/`))
+	}
+	target := normalize(dir)
+	for _, entry := range strings.Split(list, ";") {
+		if normalize(entry) == target {
+			return list, false
+		}
+	}
+	if list == "" {
+		return dir, true
+	}
+	return strings.TrimRight(list, ";") + ";" + dir, true
+}
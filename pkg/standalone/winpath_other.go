@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import "errors"
+
+// longPath returns p, the paths of the other platforms have no MAX_PATH limit.
+func longPath(p string) string {
+	return p
+}
+
+func addToUserPath(dir string) (bool, error) {
+	return false, errors.New("the user PATH is only edited on Windows")
+}
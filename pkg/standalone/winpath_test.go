@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// specialInstallDir returns a directory under the temporary directory of the test whose path has spaces, non-ASCII
+// characters and is longer than MAX_PATH.
+func specialInstallDir(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "Jürgen Müller", "アプリ", "my dapr")
+	for len(dir) <= windowsMaxPath {
+		dir = filepath.Join(dir, strings.Repeat("ü", 20)+" dir")
+	}
+	return dir
+}
+
+func TestExtendedLengthPath(t *testing.T) {
+	short := `C:\Users\Jürgen Müller\.dapr\bin`
+	assert.Equal(t, short, extendedLengthPath(short))
+
+	long := `C:\Users\Jürgen Müller\` + strings.Repeat(`dir\`, 70) + "daprd.exe"
+	assert.Equal(t, `\\?\`+long, extendedLengthPath(long))
+	assert.Equal(t, `\\?\`+long, extendedLengthPath(`\\?\`+long))
+
+	unc := `\\server\share\` + strings.Repeat(`dir\`, 70)
+	assert.Equal(t, `\\?\UNC\server\share\`+strings.Repeat(`dir\`, 70), extendedLengthPath(unc))
+}
+
+func TestAppendPathListEntry(t *testing.T) {
+	dir := `C:\Users\Jürgen Müller\.dapr\bin`
+
+	list, added := appendPathListEntry(`C:\Windows;C:\Program Files\Git\cmd;`, dir)
+	assert.True(t, added)
+	assert.Equal(t, `C:\Windows;C:\Program Files\Git\cmd;`+dir, list)
+
+	list, added = appendPathListEntry("", dir)
+	assert.True(t, added)
+	assert.Equal(t, dir, list)
+
+	// A directory which only contains the install dir isn't it.
+	_, added = appendPathListEntry(`C:\Users\Jürgen Müller\.dapr\bin2`, dir)
+	assert.True(t, added)
+
+	for _, existing := range []string{dir, strings.ToUpper(dir), `"` + dir + `"`, dir + `\`, " " + dir} {
+		list, added = appendPathListEntry(`C:\Windows;`+existing, dir)
+		assert.False(t, added, existing)
+		assert.Equal(t, `C:\Windows;`+existing, list)
+	}
+}
+
+func TestMoveFileToPathSpecialChars(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "daprd")
+	require.NoError(t, os.WriteFile(src, []byte("binary"), 0o755))
+	dir := specialInstallDir(t)
+	// The user PATH of the machine running the tests isn't edited when the directory is in the PATH already.
+	t.Setenv("PATH", dir)
+
+	dest, pathUpdated, err := moveFileToPath(src, dir)
+	require.NoError(t, err)
+	assert.False(t, pathUpdated)
+	assert.Equal(t, filepath.Join(dir, "daprd"), dest)
+	b, err := os.ReadFile(longPath(dest))
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(b))
+}
+
+func TestUntarSpecialChars(t *testing.T) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "daprd", Mode: 0o755, Size: 6}))
+	_, err := tw.Write([]byte("binary"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	dir := longPath(specialInstallDir(t))
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	extracted, err := untar(&buf, dir, "daprd")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "daprd"), extracted)
+	b, err := os.ReadFile(extracted)
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(b))
+}
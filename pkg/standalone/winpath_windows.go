@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	path_filepath "path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const userEnvironmentKey = `Environment`
+
+var procSendMessageTimeout = windows.NewLazySystemDLL("user32.dll").NewProc("SendMessageTimeoutW")
+
+// longPath returns p as an absolute path, with the extended-length prefix if it is longer than MAX_PATH, so that the
+// files under it can be created and opened whatever the long paths setting of Windows.
+func longPath(p string) string {
+	abs, err := path_filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	return extendedLengthPath(abs)
+}
+
+// addToUserPath adds dir to the PATH of the user in the registry, unless it's already in it, and reports whether it
+// did. The registry is edited directly rather than through a shell, which would mangle the paths with spaces or
+// non-ASCII characters, and keeps the type of the value so that the variables it refers to are still expanded.
+func addToUserPath(dir string) (bool, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, userEnvironmentKey, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return false, fmt.Errorf("error opening the user environment: %w", err)
+	}
+	defer key.Close()
+
+	current, valueType, err := key.GetStringValue("Path")
+	if err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return false, fmt.Errorf("error reading the user PATH: %w", err)
+	}
+	updated, added := appendPathListEntry(current, dir)
+	if !added {
+		return false, nil
+	}
+	if valueType == registry.EXPAND_SZ {
+		err = key.SetExpandStringValue("Path", updated)
+	} else {
+		err = key.SetStringValue("Path", updated)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error updating the user PATH: %w", err)
+	}
+	broadcastEnvironmentChange()
+	return true, nil
+}
+
+// broadcastEnvironmentChange tells the running programs, Explorer among them, that the user environment changed, so
+// that the shells started from then on get the new PATH.
+func broadcastEnvironmentChange() {
+	const (
+		hwndBroadcast   = 0xffff
+		wmSettingChange = 0x001a
+		smtoAbortIfHung = 0x0002
+	)
+	env, err := windows.UTF16PtrFromString(userEnvironmentKey)
+	if err != nil {
+		return
+	}
+	var result uintptr
+	// Best effort, the new PATH is picked up on the next sign-in otherwise.
+	_, _, _ = procSendMessageTimeout.Call(hwndBroadcast, wmSettingChange, 0, uintptr(unsafe.Pointer(env)), smtoAbortIfHung, 5000, uintptr(unsafe.Pointer(&result)))
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import "errors"
+
+var errWindowsServicesUnsupported = errors.New("windows services are only available on Windows")
+
+func installWindowsServices(cliExe string, services []ManifestService) error {
+	return errWindowsServicesUnsupported
+}
+
+func stopWindowsService(s ManifestService) (string, error) {
+	return "", errWindowsServicesUnsupported
+}
+
+func removeWindowsService(s ManifestService) error {
+	return errWindowsServicesUnsupported
+}
+
+func windowsServiceState(s ManifestService) (string, error) {
+	return "", errWindowsServicesUnsupported
+}
+
+// RunWindowsService runs command as the Windows service name, which is only available on Windows.
+func RunWindowsService(name string, command []string) error {
+	return errWindowsServicesUnsupported
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// windowsServiceStopTimeout is how long a Windows service has to stop.
+const windowsServiceStopTimeout = 30 * time.Second
+
+// connectServiceManager connects to the service control manager, which needs an elevated prompt to manage services.
+func connectServiceManager() (*mgr.Mgr, error) {
+	m, err := mgr.Connect()
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return nil, ErrServiceNeedsElevation
+	}
+	return m, err
+}
+
+// installWindowsServices registers the services with the service control manager as auto-start services hosted by
+// the CLI executable cliExe, and starts them. The services which are already registered are reconfigured.
+func installWindowsServices(cliExe string, services []ManifestService) error {
+	m, err := connectServiceManager()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	for _, s := range services {
+		config := mgr.Config{
+			DisplayName: "Dapr " + s.Name,
+			Description: fmt.Sprintf("Dapr %s service, installed by dapr init", s.Name),
+			StartType:   mgr.StartAutomatic,
+		}
+		args := windowsServiceHostArgs(s)
+		service, openErr := m.OpenService(s.serviceName())
+		if openErr == nil {
+			config.BinaryPathName = windows.EscapeArg(cliExe) + " " + joinWindowsArgs(args)
+			err = service.UpdateConfig(config)
+		} else {
+			service, err = m.CreateService(s.serviceName(), cliExe, config, args...)
+		}
+		if err != nil {
+			return fmt.Errorf("error registering the %s service: %w", s.Name, err)
+		}
+		// Restart the service if it fails, as systemd does with Restart=on-failure.
+		err = service.SetRecoveryActions([]mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 5 * time.Second}}, uint32((24 * time.Hour).Seconds()))
+		if err == nil {
+			err = service.Start()
+			if errors.Is(err, windows.ERROR_SERVICE_ALREADY_RUNNING) {
+				err = nil
+			}
+		}
+		service.Close()
+		if err != nil {
+			return fmt.Errorf("error starting the %s service: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func joinWindowsArgs(args []string) string {
+	escaped := make([]string, len(args))
+	for i, arg := range args {
+		escaped[i] = windows.EscapeArg(arg)
+	}
+	return strings.Join(escaped, " ")
+}
+
+// stopWindowsService stops the Windows service of s, and waits up to windowsServiceStopTimeout for it to stop.
+func stopWindowsService(s ManifestService) (string, error) {
+	m, err := connectServiceManager()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+	service, err := m.OpenService(s.serviceName())
+	if err != nil {
+		return ProcessNotRunning, nil
+	}
+	defer service.Close()
+	status, err := service.Control(svc.Stop)
+	if errors.Is(err, windows.ERROR_SERVICE_NOT_ACTIVE) {
+		return ProcessNotRunning, nil
+	} else if err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(windowsServiceStopTimeout)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("the %s service didn't stop within %s", s.Name, windowsServiceStopTimeout)
+		}
+		time.Sleep(stopPollInterval)
+		if status, err = service.Query(); err != nil {
+			return "", err
+		}
+	}
+	return ProcessStopped, nil
+}
+
+// removeWindowsService unregisters the Windows service of s, which is stopped.
+func removeWindowsService(s ManifestService) error {
+	m, err := connectServiceManager()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	service, err := m.OpenService(s.serviceName())
+	if err != nil {
+		return nil
+	}
+	defer service.Close()
+	return service.Delete()
+}
+
+// windowsServiceState returns the state of the Windows service of s.
+func windowsServiceState(s ManifestService) (string, error) {
+	// Querying the state of a service doesn't need an elevated prompt, unlike mgr.Connect.
+	manager, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+	if err != nil {
+		return "", err
+	}
+	defer windows.CloseServiceHandle(manager)
+	handle, err := windows.OpenService(manager, windows.StringToUTF16Ptr(s.serviceName()), windows.SERVICE_QUERY_STATUS)
+	if err != nil {
+		return RuntimeNotInstalled, nil
+	}
+	service := &mgr.Service{Name: s.serviceName(), Handle: handle}
+	defer service.Close()
+	status, err := service.Query()
+	if err != nil {
+		return "", err
+	}
+	switch status.State {
+	case svc.Running:
+		return ServiceRunning, nil
+	case svc.Stopped:
+		return ServiceStopped, nil
+	default:
+		return ServiceStarting, nil
+	}
+}
+
+// RunWindowsService runs command as the Windows service name, for the service control manager: the command is
+// started, stopped when the service is, and the service stops when it exits.
+func RunWindowsService(name string, command []string) error {
+	return svc.Run(name, &windowsServiceHost{command: command})
+}
+
+type windowsServiceHost struct {
+	command []string
+}
+
+func (h *windowsServiceHost) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	cmd := exec.Command(h.command[0], h.command[1:]...)
+	if err := cmd.Start(); err != nil {
+		return true, 1
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case err := <-exited:
+			// A failure makes the service control manager run the recovery actions.
+			if err != nil {
+				return true, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				changes <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				_ = cmd.Process.Kill()
+				<-exited
+				return false, 0
+			}
+		}
+	}
+}
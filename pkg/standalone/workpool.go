@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dapr/cli/utils"
+)
+
+// defaultPullConcurrency is the number of image pulls and downloads init runs at once, by default.
+const defaultPullConcurrency = 3
+
+// workPool runs the image pulls and the downloads of the steps of an init, at most a number of them at once so as
+// not to overwhelm the disk and the container runtime. The work started with the same key while it runs is only done
+// once, its progress being reported to all the steps waiting for it. A nil pool runs the work right away.
+type workPool struct {
+	slots chan struct{}
+
+	mu    sync.Mutex
+	tasks map[string]*poolTask
+}
+
+// poolTask is a work of a pool, along with the steps waiting for it.
+type poolTask struct {
+	done chan struct{}
+	err  error
+
+	mu        sync.Mutex
+	listeners []func(message string)
+}
+
+// newWorkPool returns a pool running at most concurrency works at once.
+func newWorkPool(concurrency int) *workPool {
+	return &workPool{slots: make(chan struct{}, concurrency), tasks: map[string]*poolTask{}}
+}
+
+// report reports the progress message of t to all the steps waiting for it.
+func (t *poolTask) report(message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, l := range t.listeners {
+		l(message)
+	}
+}
+
+// run runs fn as the work key, or waits for the one with the same key which is already running, and returns its
+// error. The progress reported by fn is passed to progress, which may be nil.
+func (p *workPool) run(ctx context.Context, key string, progress func(message string), fn func(report func(message string)) error) error {
+	if p == nil {
+		return fn(func(message string) {
+			if progress != nil {
+				progress(message)
+			}
+		})
+	}
+
+	p.mu.Lock()
+	t, running := p.tasks[key]
+	if !running {
+		t = &poolTask{done: make(chan struct{})}
+		p.tasks[key] = t
+	}
+	if progress != nil {
+		t.mu.Lock()
+		t.listeners = append(t.listeners, progress)
+		t.mu.Unlock()
+	}
+	p.mu.Unlock()
+
+	if running {
+		if progress != nil {
+			progress("waiting for " + key)
+		}
+		select {
+		case <-t.done:
+			return t.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// The work is forgotten once done, so that a step retried after a failure runs it again.
+	defer func() {
+		p.mu.Lock()
+		delete(p.tasks, key)
+		p.mu.Unlock()
+		close(t.done)
+	}()
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		t.err = ctx.Err()
+		return t.err
+	}
+	defer func() { <-p.slots }()
+	t.err = fn(t.report)
+	return t.err
+}
+
+// pullImage pulls image in the pool of the init running with info, unless it is already present and the init isn't
+// forced, which pulls it again so a tag is resolved to its latest digest. With a lockfile,
+// failing to pull the image fails the init, as it can't be satisfied.
+func pullImage(ctx context.Context, info initInfo, image string) error {
+	runtimeCmd := utils.GetContainerRuntimeCmd(info.containerRuntime)
+	err := info.pulls.run(ctx, "the pull of "+image, info.reportProgress, func(report func(string)) error {
+		if _, err := runCmd(runtimeCmd, "image", "inspect", image); err == nil && !info.force {
+			return nil
+		}
+		report("pulling " + image)
+		args := append([]string{"pull"}, platformArgs()...)
+		start := time.Now()
+		if _, err := runContainerCmd(ctx, runtimeCmd, append(args, image)...); err != nil {
+			return err
+		}
+		info.recordDuration(TimingPull, image, start, 0)
+		return nil
+	})
+	if err != nil && info.lock != nil {
+		return fmt.Errorf("the image %s of the lockfile can't be pulled: %w", image, err)
+	}
+	if err != nil {
+		return fmt.Errorf("error pulling the image %s: %w", image, err)
+	}
+	return nil
+}
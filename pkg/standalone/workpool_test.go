@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/cli/pkg/print"
+)
+
+func TestWorkPoolBoundsConcurrency(t *testing.T) {
+	pool := newWorkPool(2)
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			assert.NoError(t, pool.run(context.Background(), key, nil, func(func(string)) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			}))
+		}(key)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxRunning)
+}
+
+func TestWorkPoolDeduplicates(t *testing.T) {
+	pool := newWorkPool(2)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+	var mu sync.Mutex
+	var messages []string
+	progress := func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, message)
+	}
+	work := func(report func(string)) error {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+		report("pulling redis")
+		return errors.New("pull failed")
+	}
+
+	errs := make(chan error, 2)
+	go func() { errs <- pool.run(context.Background(), "the pull of redis", progress, work) }()
+	<-started
+	go func() {
+		errs <- pool.run(context.Background(), "the pull of redis", progress, func(func(string)) error {
+			t.Error("the work ran twice")
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(messages) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	assert.EqualError(t, <-errs, "pull failed")
+	assert.EqualError(t, <-errs, "pull failed")
+	assert.Equal(t, int32(1), calls)
+	assert.ElementsMatch(t, []string{"waiting for the pull of redis", "pulling redis", "pulling redis"}, messages)
+
+	// A failed work runs again for a retry.
+	assert.NoError(t, pool.run(context.Background(), "the pull of redis", nil, func(func(string)) error { return nil }))
+}
+
+func TestInitStepsPullInParallel(t *testing.T) {
+	const latency = 200 * time.Millisecond
+	t.Cleanup(runWithRunCmd())
+	runCmd = func(_ string, args ...string) (string, error) {
+		switch {
+		case args[0] == "image":
+			return "", errors.New("no such image")
+		case args[0] == "pull":
+			time.Sleep(latency)
+		}
+		return "", nil
+	}
+	steps := []initStep{{name: StepRedis, run: runRedis}, {name: StepZipkin, run: runZipkin}}
+	elapsed := func(concurrency int) time.Duration {
+		info := initInfo{imageRegistryURL: "registry.example.com", containerRuntime: "docker", ports: InstallPorts{}.withDefaults(), pulls: newWorkPool(concurrency)}
+		var mu sync.Mutex
+		var pulls []string
+		start := time.Now()
+		require.NoError(t, runInitSteps(context.Background(), steps, info, func(ev print.ProgressEvent) {
+			if strings.HasPrefix(ev.Message, "pulling ") {
+				mu.Lock()
+				pulls = append(pulls, ev.Message)
+				mu.Unlock()
+			}
+		}))
+		assert.Len(t, pulls, 2)
+		return time.Since(start)
+	}
+
+	assert.GreaterOrEqual(t, elapsed(1), 2*latency)
+	assert.Less(t, elapsed(2), 2*latency-latency/4)
+}
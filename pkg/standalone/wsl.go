@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/dapr/cli/utils"
+)
+
+const (
+	// dockerDesktopOS is the operating system docker info reports for the daemon of Docker Desktop.
+	dockerDesktopOS = "Docker Desktop"
+	// wslLoopbackHost is the address the ports published by Docker Desktop are reachable at from a WSL distro: they
+	// are forwarded over IPv4 only, so localhost can resolve to ::1 where nothing listens.
+	wslLoopbackHost = "127.0.0.1"
+)
+
+var (
+	wslOnce sync.Once
+	inWSL   bool
+
+	dockerDesktopOnce sync.Once
+	dockerDesktop     bool
+)
+
+// IsWSL reports whether the CLI runs in a WSL distro.
+func IsWSL() bool {
+	wslOnce.Do(func() {
+		procVersion, _ := os.ReadFile("/proc/version")
+		inWSL = detectWSL(os.Getenv, string(procVersion))
+	})
+	return inWSL
+}
+
+// detectWSL reports whether the environment read with getenv, with the kernel version procVersion, is a WSL distro.
+func detectWSL(getenv func(string) string, procVersion string) bool {
+	if getenv("WSL_DISTRO_NAME") != "" || getenv("WSL_INTEROP") != "" {
+		return true
+	}
+	procVersion = strings.ToLower(procVersion)
+	return strings.Contains(procVersion, "microsoft") || strings.Contains(procVersion, "wsl")
+}
+
+// wslDistroName returns the name of the WSL distro the CLI runs in, if known.
+func wslDistroName() string {
+	if name := os.Getenv("WSL_DISTRO_NAME"); name != "" {
+		return name
+	}
+	return "this WSL distro"
+}
+
+// usesDockerDesktop reports whether the docker daemon of runtimeCmd is the one of Docker Desktop, reached through its
+// WSL integration.
+func usesDockerDesktop(runtimeCmd string) bool {
+	if runtimeCmd != string(utils.DOCKER) || !IsWSL() {
+		return false
+	}
+	dockerDesktopOnce.Do(func() {
+		out, err := runCmd(runtimeCmd, "info", "--format", "{{.OperatingSystem}}")
+		dockerDesktop = err == nil && strings.Contains(out, dockerDesktopOS)
+	})
+	return dockerDesktop
+}
+
+// checkWSLDocker returns an error if docker can't be used in the WSL distro the CLI runs in, because the WSL
+// integration of Docker Desktop isn't enabled for it and docker isn't installed in it either. The other errors of
+// docker are left to the checks of the container runtime.
+func checkWSLDocker() error {
+	hint := fmt.Sprintf("enable the WSL integration of Docker Desktop for %s in Settings > Resources > WSL integration, or install docker in the distro", wslDistroName())
+	if _, err := exec.LookPath(string(utils.DOCKER)); err != nil {
+		return fmt.Errorf("docker is not available in %s: %s", wslDistroName(), hint)
+	}
+	out, err := runCmd(string(utils.DOCKER), "version", "--format", "{{.Server.Version}}")
+	if err != nil && (strings.Contains(out+err.Error(), "could not be found in this WSL") || strings.Contains(out+err.Error(), "WSL integration")) {
+		return fmt.Errorf("docker is not set up in %s: %s", wslDistroName(), hint)
+	}
+	return nil
+}
+
+// loopbackHost returns the host the ports published by the containers of runtimeCmd are reachable at.
+func loopbackHost(runtimeCmd string) string {
+	if usesDockerDesktop(runtimeCmd) {
+		return wslLoopbackHost
+	}
+	return daprDefaultHost
+}
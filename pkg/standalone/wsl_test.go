@@ -0,0 +1,38 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectWSL(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	assert.False(t, detectWSL(noEnv, "Linux version 6.1.0-13-amd64 (debian-kernel@lists.debian.org) (gcc-12 (Debian 12.2.0-14) 12.2.0)"))
+	assert.True(t, detectWSL(noEnv, "Linux version 5.15.90.1-microsoft-standard-WSL2 (oe-user@oe-host) (x86_64-msft-linux-gcc (GCC) 9.3.0)"))
+	assert.True(t, detectWSL(noEnv, "Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com) (gcc version 5.4.0 (GCC) )"))
+	assert.True(t, detectWSL(func(key string) string {
+		if key == "WSL_DISTRO_NAME" {
+			return "Ubuntu"
+		}
+		return ""
+	}, ""))
+}
+
+func TestLoopbackHost(t *testing.T) {
+	// Only the docker of Docker Desktop publishes its ports at the IPv4 loopback address.
+	assert.Equal(t, daprDefaultHost, loopbackHost("podman"))
+}
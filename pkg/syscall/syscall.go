@@ -18,6 +18,7 @@ package syscall
 
 import (
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 
@@ -40,3 +41,9 @@ func CreateProcessGroupID() {
 		print.WarningStatusEvent(os.Stdout, "Failed to create process group id: %s", err.Error())
 	}
 }
+
+// DetachProcess makes cmd start in a new session, so that it keeps running without the terminal of the current
+// process and doesn't get its signals.
+func DetachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -16,6 +16,7 @@ package syscall
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 
@@ -46,3 +47,12 @@ func CreateProcessGroupID() {
 	// No-op on Windows
 	print.WarningStatusEvent(os.Stdout, "Creating process group id is not implemented on Windows")
 }
+
+// DetachProcess makes cmd start without a console, in a new process group, so that it keeps running without the
+// console of the current process and doesn't get its Ctrl-C events.
+func DetachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+		HideWindow:    true,
+	}
+}
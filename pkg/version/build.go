@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Values for these are injected by the build.
+var (
+	gitCommit, gitVersion, buildDate string
+)
+
+// Build describes a build of the CLI.
+type Build struct {
+	Version    string `json:"version"`
+	GitCommit  string `json:"gitCommit,omitempty"`
+	GitVersion string `json:"gitVersion,omitempty"`
+	BuildDate  string `json:"buildDate,omitempty"`
+}
+
+// CLI is the build of the running CLI. Its version is set by SetCLIVersion, from the version injected in main.
+var CLI = Build{GitCommit: gitCommit, GitVersion: gitVersion, BuildDate: buildDate}
+
+// SetCLIVersion sets the version of the running CLI.
+func SetCLIVersion(v string) {
+	CLI.Version = v
+}
+
+// UserAgent returns the User-Agent of the requests of the CLI, e.g. dapr-cli/1.11.0 (linux/amd64).
+func (b Build) UserAgent() string {
+	v := b.Version
+	if v == "" {
+		v = "edge"
+	}
+	return fmt.Sprintf("dapr-cli/%s (%s/%s)", v, runtime.GOOS, runtime.GOARCH)
+}
@@ -19,12 +19,14 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-version"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/secret"
 	"github.com/dapr/cli/utils"
 )
 
@@ -35,6 +37,8 @@ const (
 	DaprGitHubRepo = "dapr"
 	// DashboardGitHubRepo is the repo name of dapr dashboard on GitHub.
 	DashboardGitHubRepo = "dashboard"
+	// CLIGitHubRepo is the repo name of the dapr CLI on GitHub.
+	CLIGitHubRepo = "cli"
 )
 
 type githubRepoReleaseItem struct {
@@ -59,12 +63,26 @@ func GetDashboardVersion() (string, error) {
 	return GetLatestReleaseGithub(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", DaprGitHubOrg, DashboardGitHubRepo))
 }
 
+// GetCLIVersion returns the latest release version of the dapr CLI.
+func GetCLIVersion() (string, error) {
+	return GetLatestReleaseGithub(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", DaprGitHubOrg, CLIGitHubRepo))
+}
+
+const daprHelmChartURL = "https://dapr.github.io/helm-charts/index.yaml"
+
+// daprReleasesURL is the GitHub API URL listing the releases of the runtime, on a single page.
+func daprReleasesURL() string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=100", DaprGitHubOrg, DaprGitHubRepo)
+}
+
+// GetDaprVersion returns the latest release version of the runtime, from GitHub or the helm chart index if GitHub
+// is unavailable. It is the newest version listed by ListDaprVersions.
 func GetDaprVersion() (string, error) {
-	version, err := GetLatestReleaseGithub(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", DaprGitHubOrg, DaprGitHubRepo))
+	version, err := GetLatestReleaseGithub(daprReleasesURL())
 	if err != nil {
 		print.WarningStatusEvent(os.Stdout, "Failed to get runtime version: '%s'. Trying secondary source", err)
 
-		version, err = GetLatestReleaseHelmChart("https://dapr.github.io/helm-charts/index.yaml")
+		version, err = GetLatestReleaseHelmChart(daprHelmChartURL)
 		if err != nil {
 			return "", err
 		}
@@ -73,92 +91,156 @@ func GetDaprVersion() (string, error) {
 	return version, nil
 }
 
+// ListDaprVersions returns the release versions of the runtime, newest first, from GitHub or the helm chart index if
+// GitHub is unavailable. The pre-releases are only included with preRelease.
+func ListDaprVersions(preRelease bool) ([]string, error) {
+	versions, err := ListReleasesGithub(daprReleasesURL(), preRelease)
+	if err != nil {
+		print.WarningStatusEvent(os.Stdout, "Failed to get runtime versions: '%s'. Trying secondary source", err)
+
+		versions, err = ListReleasesHelmChart(daprHelmChartURL, preRelease)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return versions, nil
+}
+
 func GetVersionFromURL(releaseURL string, parseVersion func(body []byte) (string, error)) (string, error) {
-	req, err := http.NewRequest(http.MethodGet, releaseURL, nil)
+	body, err := getReleasesFromURL(releaseURL)
 	if err != nil {
 		return "", err
 	}
 
-	githubToken := utils.GetEnv("GITHUB_TOKEN", "")
-	if githubToken != "" {
-		req.Header.Add("Authorization", "token "+githubToken)
-	}
+	return parseVersion(body)
+}
 
-	resp, err := http.DefaultClient.Do(req)
+func getReleasesFromURL(releaseURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, releaseURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	req.Header.Set("User-Agent", CLI.UserAgent())
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%s - %s", releaseURL, resp.Status)
+	githubToken := secret.New(utils.GetEnv("GITHUB_TOKEN", ""))
+	if !githubToken.IsEmpty() {
+		req.Header.Add("Authorization", "token "+githubToken.Reveal())
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	print.DebugStatusEvent(os.Stderr, "GET %s", releaseURL)
+	resp, err := utils.HTTPClient().Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer resp.Body.Close()
+	print.DebugStatusEvent(os.Stderr, "GET %s: %s", releaseURL, resp.Status)
 
-	return parseVersion(body)
-}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s - %s", releaseURL, resp.Status)
+	}
 
-// GetLatestReleaseGithub return the latest release version of dapr from GitHub API.
-func GetLatestReleaseGithub(githubURL string) (string, error) {
-	return GetVersionFromURL(githubURL, func(body []byte) (string, error) {
-		var githubRepoReleases []githubRepoReleaseItem
-		err := json.Unmarshal(body, &githubRepoReleases)
-		if err != nil {
-			return "", err
-		}
+	return io.ReadAll(resp.Body)
+}
 
-		if len(githubRepoReleases) == 0 {
-			return "", fmt.Errorf("no releases")
+// sortVersions parses the versions in tags, which may have a v prefix, and returns them newest first, without
+// duplicates. Pre-releases are only included with preRelease, and the tags which aren't versions are skipped.
+func sortVersions(tags []string, preRelease bool) []string {
+	seen := make(map[string]bool, len(tags))
+	parsed := make([]*version.Version, 0, len(tags))
+	for _, tag := range tags {
+		v, err := version.NewVersion(strings.TrimPrefix(strings.TrimSpace(tag), "v"))
+		if err != nil || (!preRelease && v.Prerelease() != "") || seen[v.String()] {
+			continue
 		}
+		seen[v.String()] = true
+		parsed = append(parsed, v)
+	}
+	sort.Sort(sort.Reverse(version.Collection(parsed)))
+	versions := make([]string, 0, len(parsed))
+	for _, v := range parsed {
+		versions = append(versions, v.String())
+	}
+	return versions
+}
 
-		defaultVersion, _ := version.NewVersion("0.0.0")
-		latestVersion := defaultVersion
-
-		for _, release := range githubRepoReleases {
-			if !strings.Contains(release.TagName, "-rc") {
-				cur, _ := version.NewVersion(strings.TrimPrefix(release.TagName, "v"))
-				if cur.GreaterThan(latestVersion) {
-					latestVersion = cur
-				}
-			}
+// ListReleasesGithub returns the release versions of a repo from the GitHub API, newest first. The drafts are
+// skipped, and the pre-releases are only included with preRelease.
+func ListReleasesGithub(githubURL string, preRelease bool) ([]string, error) {
+	body, err := getReleasesFromURL(githubURL)
+	if err != nil {
+		return nil, err
+	}
+	var githubRepoReleases []githubRepoReleaseItem
+	if err = json.Unmarshal(body, &githubRepoReleases); err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(githubRepoReleases))
+	for _, release := range githubRepoReleases {
+		if !release.Draft {
+			tags = append(tags, release.TagName)
 		}
+	}
+	versions := sortVersions(tags, preRelease)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no releases")
+	}
+	return versions, nil
+}
 
-		if latestVersion.Equal(defaultVersion) {
-			return "", fmt.Errorf("no releases")
-		}
+// GetLatestReleaseGithub return the latest release version of dapr from GitHub API.
+func GetLatestReleaseGithub(githubURL string) (string, error) {
+	versions, err := ListReleasesGithub(githubURL, false)
+	if err != nil {
+		return "", err
+	}
+	return versions[0], nil
+}
 
-		return latestVersion.String(), nil
-	})
+// ListReleasesHelmChart returns the release versions of dapr from the helm chart static index.yaml, newest first.
+// The pre-releases are only included with preRelease.
+func ListReleasesHelmChart(helmChartURL string, preRelease bool) ([]string, error) {
+	tags, err := getHelmChartVersions(helmChartURL)
+	if err != nil {
+		return nil, err
+	}
+	versions := sortVersions(tags, preRelease)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no releases")
+	}
+	return versions, nil
 }
 
 // GetLatestReleaseHelmChart return the latest release version of dapr from helm chart static index.yaml.
 func GetLatestReleaseHelmChart(helmChartURL string) (string, error) {
-	return GetVersionFromURL(helmChartURL, func(body []byte) (string, error) {
-		var helmChartReleases helmChartItems
-		err := yaml.Unmarshal(body, &helmChartReleases)
-		if err != nil {
-			return "", err
-		}
-		if len(helmChartReleases.Entries.Dapr) == 0 {
-			return "", fmt.Errorf("no releases")
-		}
-
-		for _, release := range helmChartReleases.Entries.Dapr {
-			if !strings.Contains(release.Version, "-rc") {
-				return release.Version, nil
-			}
-		}
-
+	tags, err := getHelmChartVersions(helmChartURL)
+	if err != nil {
+		return "", err
+	}
+	versions := sortVersions(tags, false)
+	if len(versions) == 0 {
 		// Did not find a non-rc version, so we fallback to an RC.
 		// This is helpful to allow us to validate installation of new charts (Dashboard).
-		for _, release := range helmChartReleases.Entries.Dapr {
-			return release.Version, nil
-		}
-
+		versions = sortVersions(tags, true)
+	}
+	if len(versions) == 0 {
 		return "", fmt.Errorf("no releases")
-	})
+	}
+	return versions[0], nil
+}
+
+func getHelmChartVersions(helmChartURL string) ([]string, error) {
+	body, err := getReleasesFromURL(helmChartURL)
+	if err != nil {
+		return nil, err
+	}
+	var helmChartReleases helmChartItems
+	if err = yaml.Unmarshal(body, &helmChartReleases); err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(helmChartReleases.Entries.Dapr))
+	for _, release := range helmChartReleases.Entries.Dapr {
+		tags = append(tags, release.Version)
+	}
+	return tags, nil
 }
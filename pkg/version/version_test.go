@@ -298,3 +298,11 @@ entries:
 
 	s.Shutdown(context.Background())
 }
+
+func TestSortVersions(t *testing.T) {
+	tags := []string{"v1.10.0", "v1.9.6", "v1.11.0-rc.2", "1.10.0", "v1.11.0-rc.1", "nightly", "v1.2.3"}
+
+	assert.Equal(t, []string{"1.10.0", "1.9.6", "1.2.3"}, sortVersions(tags, false))
+	assert.Equal(t, []string{"1.11.0-rc.2", "1.11.0-rc.1", "1.10.0", "1.9.6", "1.2.3"}, sortVersions(tags, true))
+	assert.Empty(t, sortVersions(nil, true))
+}
@@ -779,6 +779,28 @@ func installTest(details VersionDetails, opts TestOptions) func(t *testing.T) {
 	}
 }
 
+// RenderTest checks that the manifests rendered by init are the same on every render, and that kubectl accepts them.
+// It runs against an install, for the CRDs of the custom resources of the charts to be known to kubectl.
+func RenderTest(details VersionDetails) func(t *testing.T) {
+	return func(t *testing.T) {
+		daprPath := GetDaprPath()
+		args := []string{"init", "-k", "--render", "-n", DaprTestNamespace}
+		if !details.UseDaprLatestVersion {
+			args = append(args, "--runtime-version", details.RuntimeVersion)
+		}
+		output, err := spawn.Command(daprPath, args...)
+		require.NoError(t, err, "render failed: %s", output)
+		again, err := spawn.Command(daprPath, args...)
+		require.NoError(t, err, "render failed: %s", again)
+		assert.Equal(t, output, again, "renders must be the same")
+
+		manifests := filepath.Join(t.TempDir(), "runtime.yaml")
+		require.NoError(t, os.WriteFile(manifests, []byte(output), 0o600))
+		output, err = spawn.Command("kubectl", "apply", "--dry-run=client", "-f", manifests)
+		require.NoError(t, err, "kubectl rejects the rendered manifests: %s", output)
+	}
+}
+
 func uninstallTest(all bool) func(t *testing.T) {
 	return func(t *testing.T) {
 		output, err := EnsureUninstall(all)
@@ -87,6 +87,37 @@ func TestKubernetesNonHAModeMTLSDisabled(t *testing.T) {
 	}
 }
 
+func TestKubernetesRender(t *testing.T) {
+	// ensure clean env for test
+	ensureCleanEnv(t, false)
+
+	// setup tests
+	tests := []common.TestCase{}
+	tests = append(tests, common.GetTestsOnInstall(currentVersionDetails, common.TestOptions{
+		HAEnabled:   false,
+		MTLSEnabled: true,
+		CheckResourceExists: map[common.Resource]bool{
+			common.CustomResourceDefs:  true,
+			common.ClusterRoles:        true,
+			common.ClusterRoleBindings: true,
+		},
+	})...)
+	tests = append(tests, common.TestCase{Name: "render manifests " + currentVersionDetails.RuntimeVersion, Callable: common.RenderTest(currentVersionDetails)})
+
+	tests = append(tests, common.GetTestsOnUninstall(currentVersionDetails, common.TestOptions{
+		CheckResourceExists: map[common.Resource]bool{
+			common.CustomResourceDefs:  true,
+			common.ClusterRoles:        false,
+			common.ClusterRoleBindings: false,
+		},
+	})...)
+
+	// execute tests
+	for _, tc := range tests {
+		t.Run(tc.Name, tc.Callable)
+	}
+}
+
 func TestKubernetesHAModeMTLSDisabled(t *testing.T) {
 	// ensure clean env for test
 	ensureCleanEnv(t, false)
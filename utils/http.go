@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	httpClientLock sync.RWMutex
+	httpClient     = NewHTTPClient()
+)
+
+// NewHTTPClient returns the default HTTP client of the CLI. The requests have no overall timeout, as the downloads of
+// the archives can take a while on slow links, but connecting, the TLS handshake and waiting for the response headers
+// do. The proxy is the one of the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func NewHTTPClient() *http.Client {
+	return &http.Client{ //nolint:exhaustruct
+		Transport: &http.Transport{ //nolint:exhaustruct
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{ //nolint:exhaustruct
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   15 * time.Second,
+			ResponseHeaderTimeout: 15 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// HTTPClient returns the client of the network requests of the CLI: the release feeds, the downloads, the update
+// checks and the readiness probes.
+func HTTPClient() *http.Client {
+	httpClientLock.RLock()
+	defer httpClientLock.RUnlock()
+	return httpClient
+}
+
+// SetHTTPClient replaces the client returned by HTTPClient with c, or with a new default one if c is nil, and
+// returns the previous one. It is for the tests and the programs embedding the CLI, which need a transport with
+// e.g. proxy authentication, a custom dialer or pinned certificates.
+func SetHTTPClient(c *http.Client) *http.Client {
+	if c == nil {
+		c = NewHTTPClient()
+	}
+	httpClientLock.Lock()
+	defer httpClientLock.Unlock()
+	previous := httpClient
+	httpClient = c
+	return previous
+}
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/dapr/cli/pkg/print"
+	"github.com/dapr/cli/pkg/secret"
 
 	"github.com/docker/docker/client"
 	"github.com/gocarina/gocsv"
@@ -123,7 +124,14 @@ func TruncateString(str string, maxLength int) string {
 }
 
 func RunCmdAndWait(name string, args ...string) (string, error) {
-	cmd := exec.Command(name, args...)
+	return RunCmdAndWaitContext(context.Background(), name, args...)
+}
+
+// RunCmdAndWaitContext runs the command as RunCmdAndWait does, killing it once ctx is done, in which case the error
+// of ctx is returned.
+func RunCmdAndWaitContext(ctx context.Context, name string, args ...string) (string, error) {
+	print.DebugStatusEvent(os.Stderr, "running: %s %s", name, strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, name, args...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -150,10 +158,13 @@ func RunCmdAndWait(name string, args ...string) (string, error) {
 	}
 
 	err = cmd.Wait()
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
 	if err != nil {
-		// in case of error, capture the exact message.
+		// in case of error, capture the exact message, without the secrets it may echo.
 		if len(errB) > 0 {
-			return "", errors.New(string(errB))
+			return "", errors.New(secret.Redact(string(errB)))
 		}
 		return "", err
 	}
@@ -271,7 +282,7 @@ func PrintDetail(writer io.Writer, outputFormat string, list interface{}) error
 		return err
 	}
 
-	_, err = writer.Write(output)
+	_, err = writer.Write(secret.RedactBytes(output))
 	return err
 }
 
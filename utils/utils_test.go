@@ -16,12 +16,15 @@ package utils
 import (
 	"bytes"
 	"math"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/cli/pkg/secret"
 )
 
 func TestContainerRuntimeUtils(t *testing.T) {
@@ -512,3 +515,41 @@ func cleanupTempDir(t *testing.T, fileName string) {
 	err := os.RemoveAll(fileName)
 	assert.NoError(t, err)
 }
+
+func TestSecretsRedacted(t *testing.T) {
+	token := secret.New("ghp_utilsredacted0123")
+
+	var buf bytes.Buffer
+	report := struct {
+		Header string `json:"header"`
+		Token  secret.Secret
+	}{"Authorization: token " + token.Reveal(), token}
+	for _, format := range []string{"json", "yaml"} {
+		buf.Reset()
+		assert.NoError(t, PrintDetail(&buf, format, report))
+		assert.NotContains(t, buf.String(), token.Reveal(), format)
+		assert.Contains(t, buf.String(), "****(21)", format)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the failing command is a shell script")
+	}
+	_, err := RunCmdAndWait("sh", "-c", "echo invalid token "+token.Reveal()+" >&2; exit 1")
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid token ****(21)\n", err.Error())
+	}
+}
+
+func TestSetHTTPClient(t *testing.T) {
+	defaultClient := HTTPClient()
+	assert.Zero(t, defaultClient.Timeout, "the downloads have no overall timeout")
+	assert.NotSame(t, http.DefaultClient, defaultClient)
+
+	client := &http.Client{}
+	assert.Same(t, defaultClient, SetHTTPClient(client))
+	assert.Same(t, client, HTTPClient())
+	assert.Same(t, client, SetHTTPClient(nil))
+	assert.NotSame(t, client, HTTPClient())
+	assert.NotNil(t, HTTPClient().Transport)
+	SetHTTPClient(defaultClient)
+}